@@ -20,6 +20,7 @@ import (
 	"context"
 	"sort"
 	"strings"
+	"time"
 
 	volumesnapshot "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -73,6 +74,10 @@ const (
 	// BackupMethodBarmanObjectStore means using barman to backup the
 	// PostgreSQL cluster
 	BackupMethodBarmanObjectStore BackupMethod = "barmanObjectStore"
+
+	// BackupMethodVolumeBackup means using pg_basebackup to stream the
+	// backup to the volume dedicated to the backups of the cluster
+	BackupMethodVolumeBackup BackupMethod = "volumeBackup"
 )
 
 // BackupSpec defines the desired state of Backup
@@ -90,10 +95,10 @@ type BackupSpec struct {
 	// +kubebuilder:validation:Enum=primary;prefer-standby
 	Target BackupTarget `json:"target,omitempty"`
 
-	// The backup method to be used, possible options are `barmanObjectStore`
-	// and `volumeSnapshot`. Defaults to: `barmanObjectStore`.
+	// The backup method to be used, possible options are `barmanObjectStore`,
+	// `volumeSnapshot` and `volumeBackup`. Defaults to: `barmanObjectStore`.
 	// +optional
-	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;volumeBackup
 	// +kubebuilder:default:=barmanObjectStore
 	Method BackupMethod `json:"method,omitempty"`
 
@@ -156,6 +161,11 @@ type BackupStatus struct {
 	// +optional
 	Encryption string `json:"encryption,omitempty"`
 
+	// The maximum amount of data, in bytes per second, uploaded to the
+	// object store by the backup. There was no limit when it is not set
+	// +optional
+	MaxBandwidth *int64 `json:"maxBandwidth,omitempty"`
+
 	// The ID of the Barman backup
 	// +optional
 	BackupID string `json:"backupId,omitempty"`
@@ -370,6 +380,25 @@ func (list BackupList) GetPendingBackupNames() []string {
 	return pendingBackups
 }
 
+// GetLastSuccessfulBackupTime returns the time when the most recent
+// completed backup of the list ended, or nil if no backup of the list
+// has ever completed. Failed backups are not considered, so a failure
+// never makes this value go back in time
+func (list BackupList) GetLastSuccessfulBackupTime() *time.Time {
+	var result *time.Time
+	for _, backup := range list.Items {
+		if backup.Status.Phase != BackupPhaseCompleted || backup.Status.StoppedAt == nil {
+			continue
+		}
+
+		if result == nil || backup.Status.StoppedAt.After(*result) {
+			result = ptr.To(backup.Status.StoppedAt.Time)
+		}
+	}
+
+	return result
+}
+
 // CanExecuteBackup control if we can start a reconciliation loop for a certain backup.
 //
 // A reconciliation loop can start if:
@@ -229,6 +229,74 @@ var _ = Describe("BackupList structure", func() {
 		pendingBackups := backupList.GetPendingBackupNames()
 		Expect(pendingBackups).To(ConsistOf("backup-1", "backup-2"))
 	})
+
+	Context("last successful backup time", func() {
+		now := time.Now().Truncate(time.Second)
+		newBackup := func(name string, phase BackupPhase, stoppedAt time.Time) Backup {
+			return Backup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+				},
+				Status: BackupStatus{
+					Phase:     phase,
+					StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+				},
+			}
+		}
+
+		It("is nil when there are no backups", func() {
+			Expect(BackupList{}.GetLastSuccessfulBackupTime()).To(BeNil())
+		})
+
+		It("is nil when no backup has ever succeeded", func() {
+			backupList := BackupList{
+				Items: []Backup{
+					newBackup("backup-1", BackupPhaseFailed, now.Add(-2*time.Hour)),
+					newBackup("backup-2", BackupPhaseFailed, now.Add(-time.Hour)),
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "backup-3"},
+						Status:     BackupStatus{Phase: BackupPhaseRunning},
+					},
+				},
+			}
+			Expect(backupList.GetLastSuccessfulBackupTime()).To(BeNil())
+		})
+
+		It("selects the most recent completed backup", func() {
+			backupList := BackupList{
+				Items: []Backup{
+					newBackup("backup-2", BackupPhaseCompleted, now.Add(-time.Hour)),
+					newBackup("backup-1", BackupPhaseCompleted, now.Add(-3*time.Hour)),
+					newBackup("backup-3", BackupPhaseCompleted, now.Add(-2*time.Hour)),
+				},
+			}
+			Expect(backupList.GetLastSuccessfulBackupTime()).To(HaveValue(BeTemporally("==", now.Add(-time.Hour))))
+		})
+
+		It("doesn't regress when a newer backup fails", func() {
+			backupList := BackupList{
+				Items: []Backup{
+					newBackup("backup-1", BackupPhaseCompleted, now.Add(-3*time.Hour)),
+					newBackup("backup-2", BackupPhaseCompleted, now.Add(-2*time.Hour)),
+					newBackup("backup-3", BackupPhaseFailed, now.Add(-time.Hour)),
+				},
+			}
+			Expect(backupList.GetLastSuccessfulBackupTime()).To(HaveValue(BeTemporally("==", now.Add(-2*time.Hour))))
+		})
+
+		It("ignores completed backups without a stop time", func() {
+			backupList := BackupList{
+				Items: []Backup{
+					newBackup("backup-1", BackupPhaseCompleted, now.Add(-3*time.Hour)),
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "backup-2"},
+						Status:     BackupStatus{Phase: BackupPhaseCompleted},
+					},
+				},
+			}
+			Expect(backupList.GetLastSuccessfulBackupTime()).To(HaveValue(BeTemporally("==", now.Add(-3*time.Hour))))
+		})
+	})
 })
 
 var _ = Describe("backup_controller volumeSnapshot unit tests", func() {
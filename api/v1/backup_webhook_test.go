@@ -72,4 +72,16 @@ var _ = Describe("Backup webhook validate", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("complains if online is set on a volume backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method: BackupMethodVolumeBackup,
+				Online: ptr.To(false),
+			},
+		}
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.online"))
+	})
 })
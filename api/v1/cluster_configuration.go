@@ -17,6 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -28,7 +32,13 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	// We start with the number of healthy replicas (healthy pods minus one)
 	// and verify it is greater than 0 and between minSyncReplicas and maxSyncReplicas.
 	// Formula: 1 <= minSyncReplicas <= SyncReplicas <= maxSyncReplicas < readyReplicas
+	// Quarantined replicas are not counted, as they are about to be fenced
 	readyReplicas := len(cluster.Status.InstancesStatus[utils.PodHealthy]) - 1
+	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
+		if instance != cluster.Status.CurrentPrimary && cluster.IsInstanceQuarantined(instance) {
+			readyReplicas--
+		}
+	}
 
 	// Initially set it to the max sync replicas requested by user
 	syncReplicas = cluster.Spec.MaxSyncReplicas
@@ -63,11 +73,41 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
+// GetSynchronousReplicationDegradedCondition builds the condition telling if
+// the replicas that can be elected as synchronous standbys are less than
+// minSyncReplicas. It returns nil when synchronous replication is not
+// required, or when there's no primary instance yet
+func (cluster *Cluster) GetSynchronousReplicationDegradedCondition() *metav1.Condition {
+	if cluster.Spec.MinSyncReplicas == 0 || cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	electableSyncReplicas := cluster.getElectableSyncReplicas()
+	if len(electableSyncReplicas) >= cluster.Spec.MinSyncReplicas {
+		return &metav1.Condition{
+			Type:   string(ConditionSynchronousReplicationDegraded),
+			Status: metav1.ConditionFalse,
+			Reason: string(ConditionReasonSyncReplicasAvailable),
+			Message: fmt.Sprintf("%d replicas can be elected as synchronous standbys, minSyncReplicas is %d",
+				len(electableSyncReplicas), cluster.Spec.MinSyncReplicas),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:   string(ConditionSynchronousReplicationDegraded),
+		Status: metav1.ConditionTrue,
+		Reason: string(ConditionReasonNotEnoughSyncReplicas),
+		Message: fmt.Sprintf("Only %d replicas can be elected as synchronous standbys, "+
+			"while minSyncReplicas is %d: the synchronous replication requirements are not satisfied",
+			len(electableSyncReplicas), cluster.Spec.MinSyncReplicas),
+	}
+}
+
 // getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
 func (cluster *Cluster) getElectableSyncReplicas() []string {
 	var nonPrimaryInstances []string
 	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
-		if cluster.Status.CurrentPrimary != instance {
+		if cluster.Status.CurrentPrimary != instance && !cluster.IsInstanceQuarantined(instance) {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
 	}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -79,4 +81,90 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(BeEmpty())
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
+
+	It("should not elect the quarantined replicas", func() {
+		cluster := createFakeCluster("example")
+		cluster.Annotations = map[string]string{
+			utils.QuarantinedInstancesAnnotation: `["example-3"]`,
+		}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
+
+	It("should not count the quarantined replicas as ready", func() {
+		cluster := createFakeCluster("example")
+		cluster.Annotations = map[string]string{
+			utils.QuarantinedInstancesAnnotation: `["example-2","example-3"]`,
+		}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(BeZero())
+		Expect(names).To(BeEmpty())
+	})
+})
+
+var _ = Describe("synchronous replication degraded condition", func() {
+	It("is not built when synchronous replication is not required", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.MinSyncReplicas = 0
+		cluster.Spec.MaxSyncReplicas = 0
+		Expect(cluster.GetSynchronousReplicationDegradedCondition()).To(BeNil())
+	})
+
+	It("is not built before the primary is elected", func() {
+		cluster := createFakeCluster("example")
+		cluster.Status.CurrentPrimary = ""
+		Expect(cluster.GetSynchronousReplicationDegradedCondition()).To(BeNil())
+	})
+
+	It("toggles as the replicas disappear and reappear", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.MinSyncReplicas = 2
+
+		condition := cluster.GetSynchronousReplicationDegradedCondition()
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(string(ConditionSynchronousReplicationDegraded)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(ConditionReasonSyncReplicasAvailable)))
+
+		By("losing a replica", func() {
+			cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+				utils.PodHealthy: {"example-1", "example-2"},
+				utils.PodFailed:  {"example-3"},
+			}
+			condition = cluster.GetSynchronousReplicationDegradedCondition()
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(ConditionReasonNotEnoughSyncReplicas)))
+			Expect(condition.Message).To(ContainSubstring("Only 1 replicas"))
+		})
+
+		By("getting the replica back", func() {
+			cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+				utils.PodHealthy: {"example-1", "example-2", "example-3"},
+			}
+			condition = cluster.GetSynchronousReplicationDegradedCondition()
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+
+	It("doesn't count the replicas excluded by the election constraints", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint = SyncReplicaElectionConstraints{
+			Enabled:                true,
+			NodeLabelsAntiAffinity: []string{"az"},
+		}
+		cluster.Status.Topology = Topology{
+			SuccessfullyExtracted: true,
+			Instances: map[PodName]PodTopologyLabels{
+				"example-1": map[string]string{"az": "one"},
+				"example-2": map[string]string{"az": "one"},
+				"example-3": map[string]string{"az": "one"},
+			},
+		}
+
+		condition := cluster.GetSynchronousReplicationDegradedCondition()
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
 })
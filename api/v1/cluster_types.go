@@ -20,16 +20,20 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -60,6 +64,10 @@ const (
 	// the generated CA for the client certificates
 	ClientCaSecretSuffix = "-ca"
 
+	// ServerCAConfigMapSuffix is the suffix appended to the cluster name to
+	// get the name of the ConfigMap exporting the server CA certificate
+	ServerCAConfigMapSuffix = "-ca.crt"
+
 	// ServerSecretSuffix is the suffix appended to the secret containing
 	// the generated server secret for PostgreSQL
 	ServerSecretSuffix = "-server"
@@ -89,6 +97,14 @@ const (
 	// get the name of the PVC dedicated to WAL files.
 	WalArchiveVolumeSuffix = "-wal"
 
+	// LogVolumeSuffix is the suffix appended to the instance name to
+	// get the name of the PVC dedicated to the PostgreSQL logs.
+	LogVolumeSuffix = "-log"
+
+	// BackupVolumeSuffix is the suffix appended to the cluster name to
+	// get the name of the PVC dedicated to the backups.
+	BackupVolumeSuffix = "-backup"
+
 	// StreamingReplicationUser is the name of the user we'll use for
 	// streaming replication purposes
 	StreamingReplicationUser = "streaming_replica"
@@ -113,6 +129,10 @@ const (
 
 	// PGBouncerPoolerUserName is the name of the role to be used for
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
+
+	// MonitoringRoleName is the name of the least privileged role used
+	// by the metrics exporter when a dedicated monitoring role is requested
+	MonitoringRoleName = "cnpg_monitoring"
 )
 
 // SnapshotOwnerReference defines the reference type for the owner of the snapshot.
@@ -194,6 +214,13 @@ type OnlineConfiguration struct {
 	// possible. `false` by default.
 	// +optional
 	ImmediateCheckpoint *bool `json:"immediateCheckpoint,omitempty"`
+
+	// The number of seconds the instance keeps PostgreSQL in backup mode
+	// waiting for the snapshots to be taken, before aborting the backup.
+	// Default: 3600.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HoldTimeoutSeconds *int32 `json:"holdTimeoutSeconds,omitempty"`
 }
 
 // GetWaitForArchive tells whether to wait for archive or not
@@ -214,6 +241,16 @@ func (o OnlineConfiguration) GetImmediateCheckpoint() bool {
 	return *o.ImmediateCheckpoint
 }
 
+// GetHoldTimeoutSeconds gets the number of seconds the backup is kept in
+// progress waiting for the snapshots to be taken
+func (o OnlineConfiguration) GetHoldTimeoutSeconds() int32 {
+	if o.HoldTimeoutSeconds == nil {
+		return DefaultOnlineBackupHoldTimeoutSeconds
+	}
+
+	return *o.HoldTimeoutSeconds
+}
+
 // ClusterSpec defines the desired state of Cluster
 type ClusterSpec struct {
 	// Description of this PostgreSQL cluster
@@ -280,6 +317,11 @@ type ClusterSpec struct {
 	// +optional
 	PostgresConfiguration PostgresConfiguration `json:"postgresql,omitempty"`
 
+	// Overrides of the PostgreSQL parameters for single instances, merged
+	// by the instance manager on top of the ones of the cluster
+	// +optional
+	InstanceOverrides []InstanceOverride `json:"instanceOverrides,omitempty"`
+
 	// Replication slots management configuration
 	// +kubebuilder:default:={"highAvailability":{"enabled":true}}
 	// +optional
@@ -328,6 +370,11 @@ type ClusterSpec struct {
 	// +optional
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// Configuration of the storage for the PostgreSQL logs. When defined,
+	// the log directory of PostgreSQL is placed on a dedicated volume
+	// +optional
+	LogStorage *StorageConfiguration `json:"logStorage,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// successfully start up (default 3600).
 	// The startup probe failure threshold is derived from this value using the formula:
@@ -363,6 +410,38 @@ type ClusterSpec struct {
 	// +optional
 	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 
+	// The amount of time (in seconds) to wait for a crashed replica Pod,
+	// whose containers keep failing or whose node is not ready, to recover
+	// before the operator replaces it. The Pod is replaced right away when
+	// the data of the instance is known to be lost. Evicted Pods are always
+	// replaced right away. Replicas are not replaced when this is not set
+	// +kubebuilder:default:=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InstanceRecoveryDelay int32 `json:"instanceRecoveryDelay,omitempty"`
+
+	// The configuration of the startup, liveness and readiness probes
+	// of the PostgreSQL container, overriding the operator defaults
+	// +optional
+	Probes *ProbesConfiguration `json:"probes,omitempty"`
+
+	// Quarantine of the replicas that repeatedly fail to start: a
+	// quarantined replica is fenced, and it is not considered for
+	// synchronous replication nor for promotion, until it is removed from
+	// the `cnpg.io/quarantinedInstances` annotation
+	// +optional
+	Quarantine *QuarantineConfiguration `json:"quarantine,omitempty"`
+
+	// Configuration of the automatic switchover from a primary instance
+	// whose data volume is nearly full
+	// +optional
+	DiskPressureSwitchover *DiskPressureSwitchoverConfiguration `json:"diskPressureSwitchover,omitempty"`
+
+	// Configuration of the promotion of a replica when the primary
+	// instance fails
+	// +optional
+	Failover *FailoverConfiguration `json:"failover,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -406,6 +485,10 @@ type ClusterSpec struct {
 	// +optional
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Preferences about the placement of the primary instance
+	// +optional
+	Topology *TopologyConfiguration `json:"topology,omitempty"`
+
 	// The configuration to be used for backups
 	// +optional
 	Backup *BackupConfiguration `json:"backup,omitempty"`
@@ -414,6 +497,12 @@ type ClusterSpec struct {
 	// +optional
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// Define a maintenance window for the restarts of the instances needed
+	// to apply configuration changes. When set, the rolling updates are
+	// deferred until the window starts
+	// +optional
+	MaintenanceWindow *MaintenanceWindowConfiguration `json:"maintenanceWindow,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	// +optional
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
@@ -445,10 +534,32 @@ type ClusterSpec struct {
 	// +optional
 	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
 
+	// Additional containers to be run alongside PostgreSQL in the instance
+	// pods, such as monitoring sidecars. They are appended to the containers
+	// managed by the operator, and can mount the volumes holding the data of
+	// the instance (`pgdata`, `pg-wal`, `pg-log`, `scratch-data` and
+	// `backup`) in read-only mode
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Containers []corev1.Container `json:"containers,omitempty"`
+
+	// Additional init containers to be run in the instance pods after
+	// the ones managed by the operator
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
 	// The configuration that is used by the portions of PostgreSQL that are managed by the instance manager
 	// +optional
 	Managed *ManagedConfiguration `json:"managed,omitempty"`
 
+	// The SQL statements executed by the instance manager when PostgreSQL
+	// is started or stopped
+	// +optional
+	Lifecycle *LifecycleConfiguration `json:"lifecycle,omitempty"`
+
 	// The SeccompProfile applied to every Pod and Container.
 	// Defaults to: `RuntimeDefault`
 	// +optional
@@ -626,6 +737,160 @@ type ManagedRoles struct {
 	PasswordStatus map[string]PasswordState `json:"passwordStatus,omitempty"`
 }
 
+// ManagedPublications tracks the status of a cluster's managed publications
+type ManagedPublications struct {
+	// Reconciled lists the publications that are in line with the spec,
+	// grouped by database
+	// +optional
+	Reconciled map[string][]string `json:"reconciled,omitempty"`
+
+	// CannotReconcile lists publications that cannot be reconciled in PostgreSQL,
+	// with an explanation of the cause
+	// +optional
+	CannotReconcile map[string][]string `json:"cannotReconcile,omitempty"`
+
+	// WalLevelRestartRequired is true when the primary instance is not
+	// running with `wal_level` set to `logical` yet, and needs
+	// to be restarted before the publications can be reconciled
+	// +optional
+	WalLevelRestartRequired bool `json:"walLevelRestartRequired,omitempty"`
+}
+
+// ManagedTables tracks the status of the storage parameters of a
+// cluster's managed tables
+type ManagedTables struct {
+	// Reconciled lists the tables whose storage parameters are in line
+	// with the spec, grouped by database
+	// +optional
+	Reconciled map[string][]string `json:"reconciled,omitempty"`
+
+	// CannotReconcile lists the tables whose storage parameters cannot be
+	// reconciled in PostgreSQL, with an explanation of the cause
+	// +optional
+	CannotReconcile map[string][]string `json:"cannotReconcile,omitempty"`
+}
+
+// ManagedSubscriptions tracks the status of a cluster's managed subscriptions
+type ManagedSubscriptions struct {
+	// Reconciled lists the subscriptions that are in line with the spec,
+	// grouped by database
+	// +optional
+	Reconciled map[string][]string `json:"reconciled,omitempty"`
+
+	// CannotReconcile lists subscriptions that cannot be reconciled in PostgreSQL,
+	// with an explanation of the cause
+	// +optional
+	CannotReconcile map[string][]string `json:"cannotReconcile,omitempty"`
+}
+
+// SQLJobPhase is the phase of a SQL job
+type SQLJobPhase string
+
+const (
+	// SQLJobPhaseRunning means that the script is being executed
+	SQLJobPhaseRunning SQLJobPhase = "running"
+
+	// SQLJobPhaseCompleted means that the script has been executed
+	// successfully in every database
+	SQLJobPhaseCompleted SQLJobPhase = "completed"
+
+	// SQLJobPhaseFailed means that the script failed in at least
+	// one database
+	SQLJobPhaseFailed SQLJobPhase = "failed"
+
+	// SQLJobPhaseInterrupted means that the execution of the script was
+	// interrupted, i.e. by a restart of the instance manager, and its
+	// outcome is unknown. The script is not executed again
+	SQLJobPhaseInterrupted SQLJobPhase = "interrupted"
+)
+
+// SQLJobStatus reports the outcome of a SQL job
+type SQLJobStatus struct {
+	// Whether the script is running, has been executed successfully in
+	// every database, has failed, or has been interrupted
+	Phase SQLJobPhase `json:"phase"`
+
+	// The databases where the script has been executed successfully
+	// +optional
+	Succeeded []string `json:"succeeded,omitempty"`
+
+	// The databases where the script failed, with the error raised
+	// +optional
+	Failed map[string]string `json:"failed,omitempty"`
+
+	// The databases where the script has not been executed because
+	// it failed in a previous one and the error policy is `stop`
+	// +optional
+	Skipped []string `json:"skipped,omitempty"`
+
+	// When the execution of the job started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the job has been executed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// MaintenanceJobPhase is the phase of a maintenance job
+type MaintenanceJobPhase string
+
+const (
+	// MaintenanceJobPhasePending means that the job is waiting for its
+	// schedule, for the maintenance window or for another job to end
+	MaintenanceJobPhasePending MaintenanceJobPhase = "pending"
+
+	// MaintenanceJobPhaseRunning means that the job is being executed
+	MaintenanceJobPhaseRunning MaintenanceJobPhase = "running"
+
+	// MaintenanceJobPhaseCompleted means that the last execution of the
+	// job processed every target successfully
+	MaintenanceJobPhaseCompleted MaintenanceJobPhase = "completed"
+
+	// MaintenanceJobPhaseFailed means that the last execution of the
+	// job failed while processing a target
+	MaintenanceJobPhaseFailed MaintenanceJobPhase = "failed"
+
+	// MaintenanceJobPhaseCanceled means that the last execution of the
+	// job has been canceled because the job has been suspended
+	MaintenanceJobPhaseCanceled MaintenanceJobPhase = "canceled"
+)
+
+// MaintenanceJobStatus reports the progress of a maintenance job
+type MaintenanceJobStatus struct {
+	// The phase of the job
+	Phase MaintenanceJobPhase `json:"phase"`
+
+	// The table or index being processed, or the one an interrupted
+	// execution is resumed from
+	// +optional
+	CurrentTarget string `json:"currentTarget,omitempty"`
+
+	// The number of targets processed by the current or last execution
+	// +optional
+	ProcessedTargets int `json:"processedTargets,omitempty"`
+
+	// The number of targets of the job
+	// +optional
+	TotalTargets int `json:"totalTargets,omitempty"`
+
+	// When the current or last execution started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the last execution ended
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// When the next execution of a scheduled job is due
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// The reason of the last failure or interruption, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ClusterStatus defines the observed state of Cluster
 type ClusterStatus struct {
 	// The total number of PVC Groups detected in the cluster. It may differ from the number of existing instance pods.
@@ -648,6 +913,35 @@ type ClusterStatus struct {
 	// +optional
 	ManagedRolesStatus ManagedRoles `json:"managedRolesStatus,omitempty"`
 
+	// ManagedPublicationsStatus reports the state of the managed publications in the cluster
+	// +optional
+	ManagedPublicationsStatus ManagedPublications `json:"managedPublicationsStatus,omitempty"`
+
+	// ManagedSubscriptionsStatus reports the state of the managed subscriptions in the cluster
+	// +optional
+	ManagedSubscriptionsStatus ManagedSubscriptions `json:"managedSubscriptionsStatus,omitempty"`
+
+	// ManagedTablesStatus reports the state of the storage parameters
+	// of the managed tables in the cluster
+	// +optional
+	ManagedTablesStatus ManagedTables `json:"managedTablesStatus,omitempty"`
+
+	// ManagedRoleTimeoutsStatus contains the names of the roles whose
+	// timeouts have been set by the operator, to reset the ones removed
+	// from the configuration
+	// +optional
+	ManagedRoleTimeoutsStatus []string `json:"managedRoleTimeoutsStatus,omitempty"`
+
+	// ManagedSQLJobsStatus reports the outcome of the managed SQL
+	// jobs that have been executed, by job name
+	// +optional
+	ManagedSQLJobsStatus map[string]SQLJobStatus `json:"managedSQLJobsStatus,omitempty"`
+
+	// MaintenanceJobsStatus reports the progress of the managed
+	// maintenance jobs, by job name
+	// +optional
+	MaintenanceJobsStatus map[string]MaintenanceJobStatus `json:"maintenanceJobsStatus,omitempty"`
+
 	// The timeline of the Postgres cluster
 	// +optional
 	TimelineID int `json:"timelineID,omitempty"`
@@ -757,6 +1051,44 @@ type ClusterStatus struct {
 	// +optional
 	CurrentPrimaryFailingSinceTimestamp string `json:"currentPrimaryFailingSinceTimestamp,omitempty"`
 
+	// The last LSN reported by the current primary. This field is reported
+	// when spec.failover.maxDataLossBytes is populated
+	// +optional
+	LastPrimaryLSN string `json:"lastPrimaryLSN,omitempty"`
+
+	// The default isolation level of the transactions in use on the
+	// current primary, as reported by the `default_transaction_isolation`
+	// parameter
+	// +optional
+	DefaultTransactionIsolation string `json:"defaultTransactionIsolation,omitempty"`
+
+	// The PostgreSQL image used by the instances. While the cluster has the
+	// `cnpg.io/skipOperatorUpgrade` annotation, an operator upgrade doesn't
+	// change it, even if the default image of the operator changed
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The size in megabytes of the WAL segments of the cluster, as reported
+	// by the control file of the current primary
+	// +optional
+	WalSegmentSize int `json:"walSegmentSize,omitempty"`
+
+	// The default collation provider of the databases of the cluster, as
+	// reported by the `template1` database of the current primary
+	// +optional
+	LocaleProvider LocaleProvider `json:"localeProvider,omitempty"`
+
+	// The consecutive failed startups of the replicas that are not ready.
+	// This field is reported when spec.quarantine is populated
+	// +optional
+	StartupFailures map[string]StartupFailures `json:"startupFailures,omitempty"`
+
+	// The number of data checksum failures detected by each instance since
+	// its statistics were last reset. Only the instances with failures are
+	// reported
+	// +optional
+	ChecksumFailures map[string]int64 `json:"checksumFailures,omitempty"`
+
 	// The timestamp when the last request for a new primary has occurred
 	// +optional
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
@@ -784,6 +1116,59 @@ type ClusterStatus struct {
 	// AzurePVCUpdateEnabled shows if the PVC online upgrade is enabled for this cluster
 	// +optional
 	AzurePVCUpdateEnabled bool `json:"azurePVCUpdateEnabled,omitempty"`
+
+	// The progress of the regeneration of the planner statistics after
+	// the data has been imported or recovered
+	// +optional
+	PostImportAnalyze *PostImportAnalyzeStatus `json:"postImportAnalyze,omitempty"`
+}
+
+// PostImportAnalyzePhase is the phase of the regeneration of the planner
+// statistics after the data has been imported or recovered
+type PostImportAnalyzePhase string
+
+const (
+	// PostImportAnalyzePhaseRunning means that vacuumdb is running
+	PostImportAnalyzePhaseRunning PostImportAnalyzePhase = "Running"
+
+	// PostImportAnalyzePhaseCompleted means that the statistics have been regenerated
+	PostImportAnalyzePhaseCompleted PostImportAnalyzePhase = "Completed"
+
+	// PostImportAnalyzePhaseFailed means that vacuumdb failed
+	PostImportAnalyzePhaseFailed PostImportAnalyzePhase = "Failed"
+
+	// PostImportAnalyzePhaseCanceled means that the regeneration has
+	// been stopped before completing
+	PostImportAnalyzePhaseCanceled PostImportAnalyzePhase = "Canceled"
+)
+
+// PostImportAnalyzeStatus contains the progress of the regeneration of
+// the planner statistics after the data has been imported or recovered
+type PostImportAnalyzeStatus struct {
+	// The current phase
+	// +optional
+	Phase PostImportAnalyzePhase `json:"phase,omitempty"`
+
+	// The stage of `vacuumdb --analyze-in-stages` being executed, from 1
+	// (minimal statistics) to 3 (full statistics)
+	// +optional
+	Stage int `json:"stage,omitempty"`
+
+	// The database being analyzed
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// When the regeneration of the statistics started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the regeneration of the statistics ended
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// The reason of the failure, if any
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // InstanceReportedState describes the last reported state of an instance during a reconciliation loop
@@ -807,6 +1192,58 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionSubscriptionsReady represents whether the managed subscriptions
+	// have been reconciled
+	ConditionSubscriptionsReady ClusterConditionType = "SubscriptionsReady"
+	// ConditionStatsTempDirectoryInMemory represents whether the
+	// stats_temp_directory is placed on a memory backed volume
+	ConditionStatsTempDirectoryInMemory ClusterConditionType = "StatsTempDirectoryInMemory"
+	// ConditionReplicationConflicts represents whether the replicas are
+	// canceling queries because of conflicts with recovery
+	ConditionReplicationConflicts ClusterConditionType = "ReplicationConflicts"
+	// ConditionInstancesQuarantined represents whether some replicas
+	// have been quarantined after repeatedly failing to start
+	ConditionInstancesQuarantined ClusterConditionType = "InstancesQuarantined"
+	// ConditionRelaxedDurability represents whether the crash safety
+	// guarantees of PostgreSQL are disabled
+	ConditionRelaxedDurability ClusterConditionType = "RelaxedDurability"
+	// ConditionReplicationSlotsInvalidated represents whether some replication
+	// slots have been invalidated because of the cap to the WAL they retain
+	ConditionReplicationSlotsInvalidated ClusterConditionType = "ReplicationSlotsInvalidated"
+	// ConditionStorageCapacityExceeded represents whether a new instance
+	// cannot be created because its PVCs don't fit in the storage
+	// quotas or limits of the namespace
+	ConditionStorageCapacityExceeded ClusterConditionType = "StorageCapacityExceeded"
+	// ConditionBarmanEndpointCAInvalid represents whether the secret
+	// referenced as the CA bundle of the barman endpoint doesn't contain
+	// a valid PEM bundle
+	ConditionBarmanEndpointCAInvalid ClusterConditionType = "BarmanEndpointCAInvalid"
+	// ConditionSynchronousReplicationDegraded represents whether the
+	// replicas that can be elected as synchronous standbys are less than
+	// minSyncReplicas
+	ConditionSynchronousReplicationDegraded ClusterConditionType = "SynchronousReplicationDegraded"
+	// ConditionRestartPending represents whether some instances need to
+	// be restarted to apply the configuration changes, and the restart is
+	// waiting for the maintenance window
+	ConditionRestartPending ClusterConditionType = "RestartPending"
+	// ConditionDataChecksumFailures represents whether some instances
+	// detected data checksum failures, indicating a possible corruption
+	ConditionDataChecksumFailures ClusterConditionType = "DataChecksumFailures"
+	// ConditionHighReplicationSlotsUsage represents whether some instances
+	// are running out of the replication slots allowed by max_replication_slots
+	ConditionHighReplicationSlotsUsage ClusterConditionType = "HighReplicationSlotsUsage"
+	// ConditionImageUpdatePending represents whether the operator upgrade
+	// would change the image of the instances, but the cluster is cordoned
+	// with the cnpg.io/skipOperatorUpgrade annotation
+	ConditionImageUpdatePending ClusterConditionType = "ImageUpdatePending"
+	// ConditionSplitBrain represents whether more than one instance has been
+	// found accepting writes as a primary, and the instances other than the
+	// current primary have been fenced
+	ConditionSplitBrain ClusterConditionType = "SplitBrain"
+	// ConditionBootstrapSourceReachable represents whether the external
+	// cluster the instances are cloned from via streaming can be reached
+	// with the configured credentials
+	ConditionBootstrapSourceReachable ClusterConditionType = "BootstrapSourceReachable"
 )
 
 // A Condition that can be used to communicate the Backup progress
@@ -887,6 +1324,110 @@ const (
 
 	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
 	DetachedVolume ConditionReason = "DetachedVolume"
+
+	// ConditionReasonSubscriptionsReconciled means that the managed subscriptions
+	// have been applied to the database
+	ConditionReasonSubscriptionsReconciled ConditionReason = "SubscriptionsReconciled"
+
+	// ConditionReasonSubscriptionsUpstreamUnreachable means that the managed subscriptions
+	// could not be reconciled because a publisher could not be reached
+	ConditionReasonSubscriptionsUpstreamUnreachable ConditionReason = "UpstreamUnreachable"
+
+	// ConditionReasonStatsTempDirectoryInMemory means that the stats_temp_directory
+	// has been placed on a memory backed volume
+	ConditionReasonStatsTempDirectoryInMemory ConditionReason = "StatsTempDirectoryInMemory"
+
+	// ConditionReasonStatsTempDirectoryNotSupported means that the PostgreSQL version
+	// in use doesn't have a stats_temp_directory
+	ConditionReasonStatsTempDirectoryNotSupported ConditionReason = "StatsTempDirectoryNotSupported"
+
+	// ConditionReasonRelaxedDurability means that fsync, full_page_writes
+	// and synchronous_commit are disabled, and a crash can cause data loss
+	ConditionReasonRelaxedDurability ConditionReason = "DataLossRisk"
+
+	// ConditionReasonReplicationConflictsDetected means that at least a replica
+	// had a spike of queries canceled because of conflicts with recovery
+	ConditionReasonReplicationConflictsDetected ConditionReason = "ReplicationConflictsDetected"
+
+	// ConditionReasonNoReplicationConflicts means that no replica had a spike
+	// of queries canceled because of conflicts with recovery
+	ConditionReasonNoReplicationConflicts ConditionReason = "NoReplicationConflicts"
+
+	// ConditionReasonReplicationSlotsLost means that at least a replication slot
+	// has been invalidated, as it was retaining more WAL than allowed
+	ConditionReasonReplicationSlotsLost ConditionReason = "ReplicationSlotsLost"
+
+	// ConditionReasonNoReplicationSlotsLost means that no replication slot
+	// has been invalidated
+	ConditionReasonNoReplicationSlotsLost ConditionReason = "NoReplicationSlotsLost"
+
+	// ConditionReasonStorageQuotaExceeded means that the PVCs of a new instance
+	// would exceed the ResourceQuotas or the LimitRanges of the namespace
+	ConditionReasonStorageQuotaExceeded ConditionReason = "StorageQuotaExceeded"
+
+	// ConditionReasonInvalidCABundle means that the secret referenced as the
+	// CA bundle of the barman endpoint is missing, or doesn't contain a
+	// valid PEM bundle
+	ConditionReasonInvalidCABundle ConditionReason = "InvalidCABundle"
+
+	// ConditionReasonNotEnoughSyncReplicas means that the replicas that can be
+	// elected as synchronous standbys are less than minSyncReplicas
+	ConditionReasonNotEnoughSyncReplicas ConditionReason = "NotEnoughSyncReplicas"
+
+	// ConditionReasonSyncReplicasAvailable means that enough replicas can be
+	// elected as synchronous standbys to satisfy minSyncReplicas
+	ConditionReasonSyncReplicasAvailable ConditionReason = "SyncReplicasAvailable"
+
+	// ConditionReasonOutsideMaintenanceWindow means that a restart of the
+	// instances is required, but the maintenance window is not in progress
+	ConditionReasonOutsideMaintenanceWindow ConditionReason = "OutsideMaintenanceWindow"
+
+	// ConditionReasonChecksumFailuresDetected means that at least an instance
+	// detected data checksum failures, indicating a possible corruption
+	ConditionReasonChecksumFailuresDetected ConditionReason = "ChecksumFailuresDetected"
+
+	// ConditionReasonNoChecksumFailures means that no instance detected
+	// data checksum failures
+	ConditionReasonNoChecksumFailures ConditionReason = "NoChecksumFailures"
+
+	// ConditionReasonDataChecksumsDisabled means that the data checksums
+	// are not enabled, and the corruption of the data can't be detected
+	ConditionReasonDataChecksumsDisabled ConditionReason = "DataChecksumsDisabled"
+
+	// ConditionReasonReplicationSlotsUsageHigh means that at least an instance
+	// is using most of the replication slots allowed by max_replication_slots
+	ConditionReasonReplicationSlotsUsageHigh ConditionReason = "ReplicationSlotsUsageHigh"
+
+	// ConditionReasonReplicationSlotsUsageNormal means that every instance
+	// has enough replication slots available
+	ConditionReasonReplicationSlotsUsageNormal ConditionReason = "ReplicationSlotsUsageNormal"
+
+	// ConditionReasonOperatorUpgradeSkipped means that the instances are not
+	// updated to the images of the current operator, because the cluster
+	// is cordoned with the cnpg.io/skipOperatorUpgrade annotation
+	ConditionReasonOperatorUpgradeSkipped ConditionReason = "OperatorUpgradeSkipped"
+
+	// ConditionReasonMultiplePrimaries means that more than one instance is
+	// accepting writes as a primary, and the instances other than the current
+	// primary have been fenced
+	ConditionReasonMultiplePrimaries ConditionReason = "MultiplePrimaries"
+
+	// ConditionReasonSinglePrimary means that no more than one instance is
+	// accepting writes as a primary
+	ConditionReasonSinglePrimary ConditionReason = "SinglePrimary"
+
+	// ConditionReasonSourceReachable means that a test connection to the
+	// external cluster to be cloned has been successful
+	ConditionReasonSourceReachable ConditionReason = "SourceReachable"
+
+	// ConditionReasonSourceUnreachable means that the external cluster to
+	// be cloned can't be reached, or it is not a PostgreSQL server able to
+	// stream its data
+	ConditionReasonSourceUnreachable ConditionReason = "SourceUnreachable"
+
+	// ConditionReasonSourceAuthenticationFailed means that the external
+	// cluster to be cloned refused the configured credentials
+	ConditionReasonSourceAuthenticationFailed ConditionReason = "SourceAuthenticationFailed"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -922,11 +1463,22 @@ type ReplicaClusterConfiguration struct {
 	// object store or via streaming through pg_basebackup.
 	// Refer to the Replica clusters page of the documentation for more information.
 	Enabled bool `json:"enabled"`
+
+	// When true, the operator refuses to promote this replica cluster and
+	// to change its designated primary, including during failovers,
+	// switchovers and rolling updates. The flag must be removed before
+	// disabling the replica mode
+	// +optional
+	PromotionBlocked bool `json:"promotionBlocked,omitempty"`
 }
 
 // DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
 const DefaultReplicationSlotsUpdateInterval = 30
 
+// MinMaxSlotWALKeepSize is the minimum WAL retention that can be set for the
+// replication slots, to avoid invalidating them during the normal operations
+const MinMaxSlotWALKeepSize = "1Gi"
+
 // DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
 const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
 
@@ -944,6 +1496,14 @@ type ReplicationSlotsConfiguration struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	UpdateInterval int `json:"updateInterval,omitempty"`
+
+	// The maximum size of WAL files that replication slots are allowed to
+	// retain in the `pg_wal` directory, mapped to the `max_slot_wal_keep_size`
+	// parameter (e.g. `10Gi`). Slots retaining more WAL are invalidated by
+	// PostgreSQL, and the affected standbys need to be cloned again.
+	// Unlimited by default. Requires PostgreSQL 13 or above.
+	// +optional
+	MaxSlotWALKeepSize string `json:"maxSlotWALKeepSize,omitempty"`
 }
 
 // GetUpdateInterval returns the update interval, defaulting to DefaultReplicationSlotsUpdateInterval if empty
@@ -954,6 +1514,22 @@ func (r *ReplicationSlotsConfiguration) GetUpdateInterval() time.Duration {
 	return time.Duration(r.UpdateInterval) * time.Second
 }
 
+// GetMaxSlotWALKeepSize returns the value of the `max_slot_wal_keep_size`
+// parameter requested for the replication slots, in megabytes, or an
+// empty string if the WAL retention of the slots is not capped
+func (r *ReplicationSlotsConfiguration) GetMaxSlotWALKeepSize() string {
+	if r == nil || r.MaxSlotWALKeepSize == "" {
+		return ""
+	}
+
+	size, err := resource.ParseQuantity(r.MaxSlotWALKeepSize)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%dMB", size.Value()/(1024*1024))
+}
+
 // ReplicationSlotsHAConfiguration encapsulates the configuration
 // of the replication slots that are automatically managed by
 // the operator to control the streaming replication connections
@@ -1051,6 +1627,37 @@ type NodeMaintenanceWindow struct {
 	InProgress bool `json:"inProgress,omitempty"`
 }
 
+// MaintenanceWindowConfiguration defines the periods when the operator
+// is allowed to restart the instances to apply changes requiring it
+type MaintenanceWindowConfiguration struct {
+	// The schedule of the beginning of the maintenance windows, using the
+	// Cron expression format of the scheduled backups, which includes the
+	// seconds specifier (e.g. `0 0 2 * * 6` for every Saturday at 2:00).
+	// The schedule is evaluated in UTC
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The duration of every maintenance window, in seconds
+	// +kubebuilder:validation:Minimum=60
+	Duration int32 `json:"duration"`
+}
+
+// IsInProgress checks if a maintenance window is in progress at the
+// given time, returning when the current window started or, when no
+// window is in progress, when the next window will start
+func (window *MaintenanceWindowConfiguration) IsInProgress(now time.Time) (bool, time.Time, error) {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	// The first window starting after the beginning of a window lasting
+	// until now is either in progress or the next one
+	now = now.UTC()
+	start := schedule.Next(now.Add(-time.Duration(window.Duration) * time.Second))
+	return !start.After(now), start, nil
+}
+
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateStrategy string
@@ -1087,6 +1694,15 @@ const (
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
 	DefaultMaxSwitchoverDelay = 3600
 
+	// DefaultOnlineBackupHoldTimeoutSeconds is the default number of seconds
+	// an online backup with volume snapshots is kept in progress, waiting
+	// for the snapshots to be taken, before being aborted
+	DefaultOnlineBackupHoldTimeoutSeconds = 3600
+
+	// DefaultSQLJobTimeoutSeconds is the default number of seconds the
+	// script of a SQL job is allowed to run in each database
+	DefaultSQLJobTimeoutSeconds = 300
+
 	// DefaultStartupDelay is the default value for startupDelay, startupDelay will be used to calculate the
 	// FailureThreshold of startupProbe, the formula is `FailureThreshold = ceiling(startDelay / periodSeconds)`,
 	// the minimum value is 1
@@ -1104,6 +1720,11 @@ type PostgresConfiguration struct {
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// PostgreSQL User Name Maps (entries to be appended to the
+	// pg_ident.conf file, after the ones reserved to the operator)
+	// +optional
+	PgIdent []IdentMapEntry `json:"pgIdent,omitempty"`
+
 	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
 	// set up.
 	// +optional
@@ -1122,87 +1743,785 @@ type PostgresConfiguration struct {
 	// big enough to simulate an infinite timeout
 	// +optional
 	PgCtlTimeoutForPromotion int32 `json:"promotionTimeout,omitempty"`
-}
 
-// BootstrapConfiguration contains information about how to create the PostgreSQL
-// cluster. Only a single bootstrap method can be defined among the supported
-// ones. `initdb` will be used as the bootstrap method if left
-// unspecified. Refer to the Bootstrap page of the documentation for more
-// information.
-type BootstrapConfiguration struct {
-	// Bootstrap the cluster via initdb
+	// The number of seconds after which the instance manager terminates
+	// the client sessions that are idle in transaction. This is a safety
+	// net independent from the `idle_in_transaction_session_timeout`
+	// PostgreSQL parameter. Defaults to 0, which disables the feature
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default:=0
 	// +optional
-	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+	IdleInTransactionTimeout int32 `json:"idleInTransactionTimeout,omitempty"`
 
-	// Bootstrap the cluster from a backup
+	// When enabled, the `stats_temp_directory` is placed on a memory backed
+	// volume, sized after the memory of the pod, to reduce the IO.
+	// PostgreSQL 15 and later keep the statistics in shared memory, and this
+	// option has no effect on them
+	// +kubebuilder:default:=false
 	// +optional
-	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+	StatsTempDirectoryInMemory bool `json:"statsTempDirectoryInMemory,omitempty"`
 
-	// Bootstrap the cluster taking a physical backup of another compatible
-	// PostgreSQL instance
+	// When enabled, `effective_cache_size` is set to three quarters of the
+	// memory limit of the pod, or of the memory request when no limit is
+	// set, following its changes without restarting the instances. An
+	// `effective_cache_size` set in the parameters takes precedence
+	// +kubebuilder:default:=false
 	// +optional
-	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
-}
+	AutoEffectiveCacheSize bool `json:"autoEffectiveCacheSize,omitempty"`
 
-// LDAPScheme defines the possible schemes for LDAP
-type LDAPScheme string
+	// When enabled, after the cluster has been bootstrapped importing the
+	// data or recovering it from a backup, the instance manager of the
+	// primary regenerates the planner statistics in background running
+	// `vacuumdb --analyze-in-stages` on every database. The progress is
+	// reported in the status of the cluster
+	// +kubebuilder:default:=false
+	// +optional
+	AnalyzeAfterImport bool `json:"analyzeAfterImport,omitempty"`
 
-// These are the valid LDAP schemes
-const (
-	LDAPSchemeLDAP  LDAPScheme = "ldap"
-	LDAPSchemeLDAPS LDAPScheme = "ldaps"
-)
+	// The size in megabytes of the WAL segments, passed to the
+	// `--wal-segsize` option of initdb when the cluster is bootstrapped.
+	// It must be a power of two between 1 and 1024 and, as it is stored in
+	// the control file, it can't be changed afterwards. It takes precedence
+	// over `bootstrap.initdb.walSegmentSize` (default: empty, resulting in
+	// the PostgreSQL default: 16MB)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1024
+	// +optional
+	WalSegmentSize int `json:"walSegmentSize,omitempty"`
 
-// LDAPConfig contains the parameters needed for LDAP authentication
-type LDAPConfig struct {
-	// LDAP hostname or IP address
+	// How the replicas negotiate TLS when connecting to the primary:
+	// `postgres` sends an SSLRequest packet before starting the TLS
+	// handshake, while `direct` starts it immediately, saving a round trip.
+	// Direct TLS negotiation requires PostgreSQL 17 or later, and the
+	// `postgres` negotiation is used on older versions
+	// +kubebuilder:validation:Enum=postgres;direct
+	// +kubebuilder:default:=postgres
+	// +optional
+	SSLNegotiation SSLNegotiationMode `json:"sslNegotiation,omitempty"`
+
+	// The TCP keepalive settings of the connections to PostgreSQL,
+	// including the replication ones, rendered in the
+	// `tcp_keepalives_idle`, `tcp_keepalives_interval` and
+	// `tcp_keepalives_count` parameters. Changing them doesn't require
+	// a restart
+	// +optional
+	TCPKeepalives *TCPKeepalivesConfiguration `json:"tcpKeepalives,omitempty"`
+
+	// The settings of the connections of the replicas to the primary:
+	// the interval between the attempts to retrieve the WAL, rendered in
+	// the `wal_retrieve_retry_interval` parameter, and the timeout of each
+	// connection attempt, rendered in the `primary_conninfo`. Changing them
+	// doesn't require a restart on PostgreSQL 13 or later
+	// +optional
+	ReplicaConnection *ReplicaConnectionConfiguration `json:"replicaConnection,omitempty"`
+
+	// The durability preset of the instances: `strict` keeps the crash
+	// safety guarantees of PostgreSQL, while `relaxed` sets `fsync`,
+	// `full_page_writes` and `synchronous_commit` to `off`, trading the
+	// data safety for speed. The `relaxed` preset must only be used for
+	// ephemeral clusters, as a crash can corrupt the data, and it can't be
+	// used on clusters with backups configured
+	// +kubebuilder:validation:Enum=strict;relaxed
+	// +kubebuilder:default:=strict
+	// +optional
+	Durability DurabilityMode `json:"durability,omitempty"`
+
+	// The isolation level of the transactions not setting it explicitly,
+	// rendered in the `default_transaction_isolation` parameter. Changing
+	// it doesn't require a restart. Defaults to the PostgreSQL one, that
+	// is `read committed`
+	// +kubebuilder:validation:Enum="read uncommitted";"read committed";"repeatable read";serializable
+	// +optional
+	DefaultTransactionIsolation TransactionIsolationLevel `json:"defaultTransactionIsolation,omitempty"`
+
+	// When enabled, the replicas set `default_transaction_read_only` to
+	// `on`, so that the writes routed to them by mistake fail when the
+	// transaction starts. The instance manager clears the parameter as soon
+	// as a replica is promoted, and sets it back when a former primary is
+	// demoted
+	// +kubebuilder:default:=false
 	// +optional
-	Server string `json:"server,omitempty"`
-	// LDAP server port
+	ReadOnlyReplicas bool `json:"readOnlyReplicas,omitempty"`
+
+	// The statement and lock timeouts of the sessions, with the
+	// overrides for specific roles, e.g. the ones running migrations
 	// +optional
-	Port int `json:"port,omitempty"`
+	Timeouts *TimeoutsConfiguration `json:"timeouts,omitempty"`
 
-	// LDAP schema to be used, possible options are `ldap` and `ldaps`
-	// +kubebuilder:validation:Enum=ldap;ldaps
+	// The cluster-wide settings of the autovacuum, e.g. the number of
+	// workers running in parallel
 	// +optional
-	Scheme LDAPScheme `json:"scheme,omitempty"`
+	Autovacuum *AutovacuumConfiguration `json:"autovacuum,omitempty"`
 
-	// Bind as authentication configuration
+	// The time zone used to display and interpret the timestamps, rendered
+	// in the `timezone` parameter. It must be a name of the IANA time zone
+	// database, e.g. `Europe/Rome` or `UTC`. Changing it doesn't require a
+	// restart. Defaults to the PostgreSQL one, that is `GMT`
 	// +optional
-	BindAsAuth *LDAPBindAsAuth `json:"bindAsAuth,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
 
-	// Bind+Search authentication configuration
+	// The time zone used for the timestamps written in the server log,
+	// rendered in the `log_timezone` parameter. It must be a name of the
+	// IANA time zone database. Changing it doesn't require a restart.
+	// Defaults to the PostgreSQL one, that is `GMT`
 	// +optional
-	BindSearchAuth *LDAPBindSearchAuth `json:"bindSearchAuth,omitempty"`
+	LogTimezone string `json:"logTimezone,omitempty"`
 
-	// Set to 'true' to enable LDAP over TLS. 'false' is default
+	// The display format for the date and time values, and the rules to
+	// interpret the ambiguous dates, rendered in the `DateStyle` parameter,
+	// e.g. `ISO, DMY`. Changing it doesn't require a restart. Defaults to
+	// the PostgreSQL one, that is `ISO, MDY`
 	// +optional
-	TLS bool `json:"tls,omitempty"`
+	DateStyle string `json:"dateStyle,omitempty"`
 }
 
-// LDAPBindAsAuth provides the required fields to use the
-// bind authentication for LDAP
-type LDAPBindAsAuth struct {
-	// Prefix for the bind authentication option
-	// +optional
-	Prefix string `json:"prefix,omitempty"`
-	// Suffix for the bind authentication option
-	// +optional
-	Suffix string `json:"suffix,omitempty"`
+// IdentMapEntry is an entry of the pg_ident.conf file, mapping an external
+// system user name to a PostgreSQL user name
+type IdentMapEntry struct {
+	// The name of the map, to be referenced in the `map` option of
+	// the pg_hba.conf rules. The "local" map is reserved to the operator
+	// +kubebuilder:validation:MinLength=1
+	MapName string `json:"mapName"`
+
+	// The operating system user name, or a regular expression
+	// when starting with a slash
+	// +kubebuilder:validation:MinLength=1
+	SystemUsername string `json:"systemUsername"`
+
+	// The PostgreSQL user name the system user name is mapped to
+	// +kubebuilder:validation:MinLength=1
+	PGUsername string `json:"pgUsername"`
 }
 
-// LDAPBindSearchAuth provides the required fields to use
-// the bind+search LDAP authentication process
-type LDAPBindSearchAuth struct {
-	// Root DN to begin the user search
-	// +optional
-	BaseDN string `json:"baseDN,omitempty"`
-	// DN of the user to bind to the directory
+// AutovacuumConfiguration contains the cluster-wide settings of the
+// autovacuum. The settings of specific tables can be overridden in the
+// managed tables
+type AutovacuumConfiguration struct {
+	// The maximum number of autovacuum workers running at the same time,
+	// rendered in the `autovacuum_max_workers` parameter. Changing it
+	// requires a restart
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1024
 	// +optional
-	BindDN string `json:"bindDN,omitempty"`
-	// Secret with the password for the user to bind to the directory
+	MaxWorkers *int32 `json:"maxWorkers,omitempty"`
+
+	// The cost limit of the autovacuum workers, shared among the running
+	// ones, rendered in the `autovacuum_vacuum_cost_limit` parameter. `-1`
+	// uses the `vacuum_cost_limit` one. Changing it doesn't require a restart
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=10000
 	// +optional
-	BindPassword *corev1.SecretKeySelector `json:"bindPassword,omitempty"`
+	VacuumCostLimit *int32 `json:"vacuumCostLimit,omitempty"`
+
+	// The minimum delay between the runs of the autovacuum on a database,
+	// rendered in the `autovacuum_naptime` parameter, e.g. `30s` or `1min`.
+	// A value without unit is in seconds. Changing it doesn't require a restart
+	// +optional
+	Naptime string `json:"naptime,omitempty"`
+}
+
+// GetParameters returns the PostgreSQL parameters rendered from the
+// autovacuum settings which have been set
+func (config *AutovacuumConfiguration) GetParameters() map[string]string {
+	if config == nil {
+		return nil
+	}
+
+	result := make(map[string]string)
+	if config.MaxWorkers != nil {
+		result["autovacuum_max_workers"] = strconv.Itoa(int(*config.MaxWorkers))
+	}
+	if config.VacuumCostLimit != nil {
+		result["autovacuum_vacuum_cost_limit"] = strconv.Itoa(int(*config.VacuumCostLimit))
+	}
+	if config.Naptime != "" {
+		result["autovacuum_naptime"] = config.Naptime
+	}
+	return result
+}
+
+// TimeoutsConfiguration contains the cluster-wide statement and lock
+// timeouts, and their overrides for specific roles. The values use the
+// PostgreSQL format, such as `30s` or `5min`, `0` disabling the timeout,
+// and a value without unit being in milliseconds
+type TimeoutsConfiguration struct {
+	// The maximum duration of any statement, rendered in the
+	// `statement_timeout` parameter. Changing it doesn't require a restart
+	// +optional
+	StatementTimeout string `json:"statementTimeout,omitempty"`
+
+	// The maximum time spent waiting for a lock, rendered in the
+	// `lock_timeout` parameter. Changing it doesn't require a restart
+	// +optional
+	LockTimeout string `json:"lockTimeout,omitempty"`
+
+	// The timeouts of specific roles, applied by the primary
+	// with `ALTER ROLE ... SET`
+	// +optional
+	Roles []RoleTimeoutsConfiguration `json:"roles,omitempty"`
+}
+
+// RoleTimeoutsConfiguration contains the timeouts overridden for a role.
+// An empty value removes the override, making the role use the
+// cluster-wide timeout
+type RoleTimeoutsConfiguration struct {
+	// The name of the role
+	Name string `json:"name"`
+
+	// The maximum duration of any statement run by the role
+	// +optional
+	StatementTimeout string `json:"statementTimeout,omitempty"`
+
+	// The maximum time spent by the role waiting for a lock
+	// +optional
+	LockTimeout string `json:"lockTimeout,omitempty"`
+}
+
+// GetSettings returns the timeout parameters of the role, mapping
+// the ones to be removed to an empty value
+func (r RoleTimeoutsConfiguration) GetSettings() map[string]string {
+	return map[string]string{
+		"statement_timeout": r.StatementTimeout,
+		"lock_timeout":      r.LockTimeout,
+	}
+}
+
+// TransactionIsolationLevel is the isolation level of a transaction
+type TransactionIsolationLevel string
+
+const (
+	// TransactionIsolationReadUncommitted is the "read uncommitted" isolation
+	// level, behaving like "read committed" in PostgreSQL
+	TransactionIsolationReadUncommitted TransactionIsolationLevel = "read uncommitted"
+
+	// TransactionIsolationReadCommitted is the "read committed" isolation level,
+	// which is the default one of PostgreSQL
+	TransactionIsolationReadCommitted TransactionIsolationLevel = "read committed"
+
+	// TransactionIsolationRepeatableRead is the "repeatable read" isolation level
+	TransactionIsolationRepeatableRead TransactionIsolationLevel = "repeatable read"
+
+	// TransactionIsolationSerializable is the "serializable" isolation level
+	TransactionIsolationSerializable TransactionIsolationLevel = "serializable"
+)
+
+// IsValid checks if the isolation level is one of the ones
+// supported by PostgreSQL
+func (level TransactionIsolationLevel) IsValid() bool {
+	switch level {
+	case TransactionIsolationReadUncommitted, TransactionIsolationReadCommitted,
+		TransactionIsolationRepeatableRead, TransactionIsolationSerializable:
+		return true
+	}
+
+	return false
+}
+
+// DurabilityMode defines the crash safety guarantees of the instances
+type DurabilityMode string
+
+const (
+	// DurabilityModeStrict means that the PostgreSQL crash safety
+	// guarantees are kept
+	DurabilityModeStrict DurabilityMode = "strict"
+
+	// DurabilityModeRelaxed means that the PostgreSQL crash safety
+	// guarantees are disabled to run faster
+	DurabilityModeRelaxed DurabilityMode = "relaxed"
+)
+
+// InstanceOverride contains the PostgreSQL parameters overridden
+// for a single instance of the cluster
+type InstanceOverride struct {
+	// The name of the instance, that is the name of its Pod
+	Name string `json:"name"`
+
+	// PostgreSQL configuration options (postgresql.conf) overriding the
+	// ones of the cluster. The parameters that must have the same value on
+	// every instance, like `wal_level` and `max_connections`, can't be set
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// ReplicaConnectionConfiguration contains the settings controlling how
+// the replicas reconnect to the primary. Unset values are left to the
+// default of PostgreSQL
+type ReplicaConnectionConfiguration struct {
+	// The number of milliseconds to wait before retrying to retrieve
+	// the WAL after a failed attempt
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=300000
+	// +optional
+	RetryInterval *int32 `json:"retryInterval,omitempty"`
+
+	// The maximum number of seconds to wait while connecting to
+	// the primary
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	ConnectTimeout *int32 `json:"connectTimeout,omitempty"`
+
+	// The pattern of the `application_name` the replicas register with
+	// on the primary, which is what `pg_stat_replication` reports and
+	// `synchronous_standby_names` refers to. `{podName}` is replaced
+	// by the name of the Pod of the replica and `{serial}` by its serial
+	// number, and at least one of them is required to keep the names
+	// unique. Defaults to `{podName}`, and cannot be changed after the
+	// cluster is created
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.{}-]+$`
+	// +optional
+	ApplicationName string `json:"applicationName,omitempty"`
+}
+
+const (
+	// ReplicaApplicationNamePodName is the placeholder replaced by the name
+	// of the Pod in the application name pattern of the replicas
+	ReplicaApplicationNamePodName = "{podName}"
+
+	// ReplicaApplicationNameSerial is the placeholder replaced by the serial
+	// number of the instance in the application name pattern of the replicas
+	ReplicaApplicationNameSerial = "{serial}"
+)
+
+// GetApplicationNamePattern returns the pattern of the application name of
+// the replicas, defaulting to the name of their Pod
+func (r *ReplicaConnectionConfiguration) GetApplicationNamePattern() string {
+	if r == nil || r.ApplicationName == "" {
+		return ReplicaApplicationNamePodName
+	}
+	return r.ApplicationName
+}
+
+// GetApplicationName returns the application name the given instance
+// registers with when streaming from the primary
+func (r *ReplicaConnectionConfiguration) GetApplicationName(instanceName string) string {
+	serial := instanceName[strings.LastIndex(instanceName, "-")+1:]
+	return strings.NewReplacer(
+		ReplicaApplicationNamePodName, instanceName,
+		ReplicaApplicationNameSerial, serial,
+	).Replace(r.GetApplicationNamePattern())
+}
+
+// GetApplicationNameRegex returns a regular expression matching the
+// application names of the instances of the given cluster, capturing their
+// serial number. It's compatible with the regular expressions of PostgreSQL
+func (r *ReplicaConnectionConfiguration) GetApplicationNameRegex(clusterName string) string {
+	expression := strings.NewReplacer(
+		regexp.QuoteMeta(ReplicaApplicationNamePodName), regexp.QuoteMeta(clusterName)+"-([0-9]+)",
+		regexp.QuoteMeta(ReplicaApplicationNameSerial), "([0-9]+)",
+	).Replace(regexp.QuoteMeta(r.GetApplicationNamePattern()))
+	return "^" + expression + "$"
+}
+
+// GetInstanceName returns the name of the instance of the given cluster
+// registered on the primary with the given application name. It returns
+// false when the application name doesn't belong to any instance of the
+// cluster, i.e. for the clients that are not replicas
+func (r *ReplicaConnectionConfiguration) GetInstanceName(clusterName, applicationName string) (string, bool) {
+	matches := regexp.MustCompile(r.GetApplicationNameRegex(clusterName)).FindStringSubmatch(applicationName)
+	if len(matches) < 2 {
+		return "", false
+	}
+
+	// every placeholder refers to the same instance
+	serial := matches[1]
+	for _, match := range matches[2:] {
+		if match != serial {
+			return "", false
+		}
+	}
+
+	return fmt.Sprintf("%s-%s", clusterName, serial), true
+}
+
+// SSLNegotiationMode defines how TLS is negotiated when connecting
+// to a PostgreSQL instance
+type SSLNegotiationMode string
+
+const (
+	// SSLNegotiationModePostgres means that the client asks the server
+	// for TLS support before starting the handshake
+	SSLNegotiationModePostgres SSLNegotiationMode = "postgres"
+
+	// SSLNegotiationModeDirect means that the client starts the TLS
+	// handshake immediately after opening the connection
+	SSLNegotiationModeDirect SSLNegotiationMode = "direct"
+)
+
+// DirectSSLNegotiationMinVersion is the first PostgreSQL version
+// supporting direct TLS negotiation
+const DirectSSLNegotiationMinVersion = 170000
+
+// BootstrapConfiguration contains information about how to create the PostgreSQL
+// cluster. Only a single bootstrap method can be defined among the supported
+// ones. `initdb` will be used as the bootstrap method if left
+// unspecified. Refer to the Bootstrap page of the documentation for more
+// information.
+type BootstrapConfiguration struct {
+	// Bootstrap the cluster via initdb
+	// +optional
+	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+
+	// Bootstrap the cluster from a backup
+	// +optional
+	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+
+	// Bootstrap the cluster taking a physical backup of another compatible
+	// PostgreSQL instance
+	// +optional
+	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
+}
+
+// DiskPressureSwitchoverConfiguration configures the automatic switchover
+// from a primary instance whose data volume is nearly full to a replica
+// with more free space
+type DiskPressureSwitchoverConfiguration struct {
+	// When enabled, the operator switches over to the replica with the most
+	// free space when the usage of the data volume of the primary exceeds
+	// the high watermark
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The usage percentage of the data volume of the primary triggering
+	// the switchover
+	// +kubebuilder:default:=90
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	HighWatermark int32 `json:"highWatermark,omitempty"`
+
+	// The maximum usage percentage of the data volume of a replica to be
+	// elected as the new primary. It must be lower than the high watermark
+	// to avoid switching over back and forth between the instances
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	LowWatermark int32 `json:"lowWatermark,omitempty"`
+
+	// The minimum amount of time (in seconds) between the last request for
+	// a new primary and a switchover due to disk pressure
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+}
+
+// Default values of the disk pressure switchover configuration
+const (
+	// DefaultDiskPressureHighWatermark is the default high watermark
+	DefaultDiskPressureHighWatermark = 90
+
+	// DefaultDiskPressureLowWatermark is the default low watermark
+	DefaultDiskPressureLowWatermark = 80
+
+	// DefaultDiskPressureCooldownPeriod is the default cooldown period in seconds
+	DefaultDiskPressureCooldownPeriod = 600
+)
+
+// IsEnabled returns true when the disk pressure switchover is enabled
+func (configuration *DiskPressureSwitchoverConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.Enabled
+}
+
+// GetHighWatermark gets the high watermark, applying the default value
+func (configuration *DiskPressureSwitchoverConfiguration) GetHighWatermark() int32 {
+	if configuration == nil || configuration.HighWatermark == 0 {
+		return DefaultDiskPressureHighWatermark
+	}
+	return configuration.HighWatermark
+}
+
+// GetLowWatermark gets the low watermark, applying the default value
+func (configuration *DiskPressureSwitchoverConfiguration) GetLowWatermark() int32 {
+	if configuration == nil || configuration.LowWatermark == 0 {
+		return DefaultDiskPressureLowWatermark
+	}
+	return configuration.LowWatermark
+}
+
+// GetCooldownPeriod gets the cooldown period, applying the default value
+func (configuration *DiskPressureSwitchoverConfiguration) GetCooldownPeriod() time.Duration {
+	if configuration == nil || configuration.CooldownPeriod == nil {
+		return DefaultDiskPressureCooldownPeriod * time.Second
+	}
+	return time.Duration(*configuration.CooldownPeriod) * time.Second
+}
+
+// FailoverDataLossPolicy defines what the operator does when no replica
+// can be promoted within the maximum data loss allowed
+type FailoverDataLossPolicy string
+
+const (
+	// FailoverDataLossPolicyFailClosed means that the operator waits for a
+	// replica within the maximum data loss allowed before promoting it
+	FailoverDataLossPolicyFailClosed FailoverDataLossPolicy = "failClosed"
+
+	// FailoverDataLossPolicyFailOpen means that the operator promotes the
+	// most advanced replica anyway
+	FailoverDataLossPolicyFailOpen FailoverDataLossPolicy = "failOpen"
+)
+
+// FailoverConfiguration configures the promotion of a replica when the
+// primary instance fails
+type FailoverConfiguration struct {
+	// The maximum amount of WAL, in bytes, between the last LSN reported by
+	// the primary and the flush LSN of the replica to be promoted. When not
+	// set, the most advanced replica is promoted regardless of its lag
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDataLossBytes *int64 `json:"maxDataLossBytes,omitempty"`
+
+	// What to do when no replica is within `maxDataLossBytes`: `failClosed`
+	// blocks the failover until a replica catches up or the primary comes
+	// back, while `failOpen` promotes the most advanced replica anyway
+	// +kubebuilder:validation:Enum=failClosed;failOpen
+	// +kubebuilder:default:=failClosed
+	// +optional
+	DataLossPolicy FailoverDataLossPolicy `json:"dataLossPolicy,omitempty"`
+}
+
+// IsDataLossBounded returns true when the failover is limited
+// to the replicas within the maximum data loss allowed
+func (configuration *FailoverConfiguration) IsDataLossBounded() bool {
+	return configuration != nil && configuration.MaxDataLossBytes != nil
+}
+
+// GetDataLossPolicy gets the data loss policy, applying the default value
+func (configuration *FailoverConfiguration) GetDataLossPolicy() FailoverDataLossPolicy {
+	if configuration == nil || configuration.DataLossPolicy == "" {
+		return FailoverDataLossPolicyFailClosed
+	}
+	return configuration.DataLossPolicy
+}
+
+// TopologyConfiguration contains the preferences about the placement
+// of the primary instance
+type TopologyConfiguration struct {
+	// The zone, as reported by the `topology.kubernetes.io/zone` label of
+	// the nodes, where the primary should preferably run. During a
+	// switchover or a failover, a replica in this zone is chosen over
+	// the others only when they are equally advanced in the replication
+	// +optional
+	PreferredPrimaryZone string `json:"preferredPrimaryZone,omitempty"`
+}
+
+// GetPreferredPrimaryZone gets the zone where the primary should
+// preferably run, or an empty string when there is no preference
+func (configuration *TopologyConfiguration) GetPreferredPrimaryZone() string {
+	if configuration == nil {
+		return ""
+	}
+	return configuration.PreferredPrimaryZone
+}
+
+// QuarantineConfiguration configures the quarantine of the replicas
+// that repeatedly fail to start
+type QuarantineConfiguration struct {
+	// The number of consecutive failed startups, within the window,
+	// after which a replica is quarantined
+	// +kubebuilder:validation:Minimum=1
+	FailedStartups int32 `json:"failedStartups"`
+
+	// The length of the window, in seconds, starting from the first
+	// failed startup. Defaults to 600
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=600
+	// +optional
+	Window int32 `json:"window,omitempty"`
+}
+
+// DefaultQuarantineWindow is the default length of the window, in seconds,
+// in which the failed startups of a replica are counted
+const DefaultQuarantineWindow = 600
+
+// IsEnabled returns true when the replicas failing to start are quarantined
+func (configuration *QuarantineConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.FailedStartups > 0
+}
+
+// GetWindow gets the length of the window in which the failed
+// startups are counted, applying the default value
+func (configuration *QuarantineConfiguration) GetWindow() time.Duration {
+	if configuration == nil || configuration.Window == 0 {
+		return DefaultQuarantineWindow * time.Second
+	}
+	return time.Duration(configuration.Window) * time.Second
+}
+
+// ProbesConfiguration represents the configuration of the probes
+// of the PostgreSQL container
+type ProbesConfiguration struct {
+	// The startup probe configuration
+	// +optional
+	Startup *ProbeTimings `json:"startup,omitempty"`
+
+	// The liveness probe configuration
+	// +optional
+	Liveness *ProbeTimings `json:"liveness,omitempty"`
+
+	// The readiness probe configuration
+	// +optional
+	Readiness *ProbeTimings `json:"readiness,omitempty"`
+
+	// When enabled, the liveness probe runs a lightweight query on
+	// PostgreSQL with a tight timeout, detecting a server that is hung
+	// at the connection level and restarting it. Disabled by default,
+	// as a transient load could fail the query and cause restart loops
+	// +optional
+	LivenessQuery bool `json:"livenessQuery,omitempty"`
+}
+
+// ProbeTimings contains the timings of a probe. Every field that is
+// not set keeps the value chosen by the operator
+type ProbeTimings struct {
+	// Number of seconds after the container has started before the probe
+	// is initiated
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// How often (in seconds) to perform the probe
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// Number of seconds after which the probe times out
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// Minimum consecutive failures for the probe to be considered failed
+	// after having succeeded
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// ApplyTo overrides the timings of the passed probe with the ones
+// that have been set
+func (timings *ProbeTimings) ApplyTo(probe *corev1.Probe) {
+	if timings == nil || probe == nil {
+		return
+	}
+
+	if timings.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *timings.InitialDelaySeconds
+	}
+	if timings.PeriodSeconds != nil {
+		probe.PeriodSeconds = *timings.PeriodSeconds
+	}
+	if timings.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *timings.TimeoutSeconds
+	}
+	if timings.FailureThreshold != nil {
+		probe.FailureThreshold = *timings.FailureThreshold
+	}
+}
+
+// GetStartup gets the startup probe timings, if any
+func (configuration *ProbesConfiguration) GetStartup() *ProbeTimings {
+	if configuration == nil {
+		return nil
+	}
+	return configuration.Startup
+}
+
+// GetLiveness gets the liveness probe timings, if any
+func (configuration *ProbesConfiguration) GetLiveness() *ProbeTimings {
+	if configuration == nil {
+		return nil
+	}
+	return configuration.Liveness
+}
+
+// IsLivenessQueryEnabled checks whether the liveness probe should run
+// a query on PostgreSQL
+func (configuration *ProbesConfiguration) IsLivenessQueryEnabled() bool {
+	return configuration != nil && configuration.LivenessQuery
+}
+
+// GetReadiness gets the readiness probe timings, if any
+func (configuration *ProbesConfiguration) GetReadiness() *ProbeTimings {
+	if configuration == nil {
+		return nil
+	}
+	return configuration.Readiness
+}
+
+// StartupFailures tracks the consecutive failed startups of an instance
+type StartupFailures struct {
+	// The restart count of the PostgreSQL container when it was last observed
+	RestartCount int32 `json:"restartCount"`
+
+	// The number of consecutive failed startups in the current window
+	Count int32 `json:"count"`
+
+	// The timestamp of the first failed startup of the current window
+	// +optional
+	Since string `json:"since,omitempty"`
+}
+
+// LDAPScheme defines the possible schemes for LDAP
+type LDAPScheme string
+
+// These are the valid LDAP schemes
+const (
+	LDAPSchemeLDAP  LDAPScheme = "ldap"
+	LDAPSchemeLDAPS LDAPScheme = "ldaps"
+)
+
+// LDAPConfig contains the parameters needed for LDAP authentication
+type LDAPConfig struct {
+	// LDAP hostname or IP address
+	// +optional
+	Server string `json:"server,omitempty"`
+	// LDAP server port
+	// +optional
+	Port int `json:"port,omitempty"`
+
+	// LDAP schema to be used, possible options are `ldap` and `ldaps`
+	// +kubebuilder:validation:Enum=ldap;ldaps
+	// +optional
+	Scheme LDAPScheme `json:"scheme,omitempty"`
+
+	// Bind as authentication configuration
+	// +optional
+	BindAsAuth *LDAPBindAsAuth `json:"bindAsAuth,omitempty"`
+
+	// Bind+Search authentication configuration
+	// +optional
+	BindSearchAuth *LDAPBindSearchAuth `json:"bindSearchAuth,omitempty"`
+
+	// Set to 'true' to enable LDAP over TLS. 'false' is default
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+}
+
+// LDAPBindAsAuth provides the required fields to use the
+// bind authentication for LDAP
+type LDAPBindAsAuth struct {
+	// Prefix for the bind authentication option
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Suffix for the bind authentication option
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// LDAPBindSearchAuth provides the required fields to use
+// the bind+search LDAP authentication process
+type LDAPBindSearchAuth struct {
+	// Root DN to begin the user search
+	// +optional
+	BaseDN string `json:"baseDN,omitempty"`
+	// DN of the user to bind to the directory
+	// +optional
+	BindDN string `json:"bindDN,omitempty"`
+	// Secret with the password for the user to bind to the directory
+	// +optional
+	BindPassword *corev1.SecretKeySelector `json:"bindPassword,omitempty"`
 
 	// Attribute to match against the username
 	// +optional
@@ -1310,6 +2629,25 @@ type BootstrapInitDB struct {
 	// +optional
 	LocaleCType string `json:"localeCType,omitempty"`
 
+	// The value to be passed as option `--locale-provider` for initdb,
+	// choosing the default collation provider of the databases between
+	// `libc` and `icu` (default: empty, resulting in PostgreSQL default:
+	// `libc`). Requires PostgreSQL 15 or above
+	// +kubebuilder:validation:Enum=libc;icu
+	// +optional
+	LocaleProvider LocaleProvider `json:"localeProvider,omitempty"`
+
+	// The value to be passed as option `--icu-locale` for initdb, required
+	// when the `icu` locale provider is used
+	// +optional
+	ICULocale string `json:"icuLocale,omitempty"`
+
+	// The value to be passed as option `--icu-rules` for initdb, customizing
+	// the collation rules of the `icu` locale provider. Requires PostgreSQL
+	// 16 or above
+	// +optional
+	ICURules string `json:"icuRules,omitempty"`
+
 	// The value in megabytes (1 to 1024) to be passed to the `--wal-segsize`
 	// option for initdb (default: empty, resulting in PostgreSQL default: 16MB)
 	// +kubebuilder:validation:Minimum=1
@@ -1349,6 +2687,19 @@ type BootstrapInitDB struct {
 	PostInitApplicationSQLRefs *PostInitApplicationSQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
 }
 
+// LocaleProvider is the default collation provider of the databases
+type LocaleProvider string
+
+const (
+	// LocaleProviderLibc is the collation provider based on the C library
+	// of the operating system
+	LocaleProviderLibc LocaleProvider = "libc"
+
+	// LocaleProviderICU is the collation provider based on the external
+	// ICU library
+	LocaleProviderICU LocaleProvider = "icu"
+)
+
 // SnapshotType is a type of allowed import
 type SnapshotType string
 
@@ -1442,6 +2793,13 @@ type BootstrapRecovery struct {
 	// +optional
 	VolumeSnapshots *DataSource `json:"volumeSnapshots,omitempty"`
 
+	// The volume dedicated to the backups of another cluster, containing
+	// the base backup from which to initiate the recovery procedure and
+	// the WAL files to be replayed.
+	// Mutually exclusive with `backup`, `source` and `volumeSnapshots`.
+	// +optional
+	VolumeBackup *VolumeBackupSource `json:"volumeBackup,omitempty"`
+
 	// By default, the recovery process applies all the available
 	// WAL files in the archive (full recovery). However, you can also
 	// end the recovery as soon as a consistent state is reached or
@@ -1451,6 +2809,31 @@ type BootstrapRecovery struct {
 	// +optional
 	RecoveryTarget *RecoveryTarget `json:"recoveryTarget,omitempty"`
 
+	// The ID or the name of the backup to recover from, among the ones
+	// stored in the object store of the `source` external cluster or in
+	// the `volumeBackup`. The backup must be completed. Mutually exclusive
+	// with `skipLatest` and with the `backupID` of the recovery target
+	// +optional
+	BackupID string `json:"backupID,omitempty"`
+
+	// The number of the most recent completed backups, stored in the
+	// object store of the `source` external cluster or in the
+	// `volumeBackup`, to be skipped when choosing the backup to recover
+	// from. Useful to recover from an earlier backup when the latest ones
+	// are corrupted. Mutually exclusive with `backupID`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SkipLatest int `json:"skipLatest,omitempty"`
+
+	// The maximum bandwidth, in bytes per second, used to download each
+	// WAL file from the object store during the recovery, to avoid being
+	// rate limited when replaying a large amount of WAL files. The WAL
+	// files are still downloaded one at a time, and applied in order.
+	// Requires Barman >= 3.4
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WalRestoreMaxBandwidth *int64 `json:"walRestoreMaxBandwidth,omitempty"`
+
 	// Name of the database used by the application. Default: `app`.
 	// +optional
 	Database string `json:"database,omitempty"`
@@ -1478,6 +2861,14 @@ type DataSource struct {
 	WalStorage *corev1.TypedLocalObjectReference `json:"walStorage,omitempty"`
 }
 
+// VolumeBackupSource contains the reference to the volume dedicated to
+// the backups of a cluster, from which a new cluster can be recovered
+type VolumeBackupSource struct {
+	// The name of the PVC dedicated to the backups of the source
+	// cluster, which is named after it with the `-backup` suffix
+	ClaimName string `json:"claimName"`
+}
+
 // BackupSource contains the backup we need to restore from, plus some
 // information that could be needed to correctly restore it.
 type BackupSource struct {
@@ -1522,7 +2913,7 @@ type RecoveryTarget struct {
 	// +optional
 	BackupID string `json:"backupID,omitempty"`
 
-	// The target timeline ("latest" or a positive integer)
+	// The target timeline ("latest", "current" or a positive integer)
 	// +optional
 	TargetTLI string `json:"targetTLI,omitempty"`
 
@@ -1765,7 +3156,10 @@ type BarmanObjectStoreConfiguration struct {
 	DestinationPath string `json:"destinationPath"`
 
 	// The server name on S3, the cluster name is used if this
-	// parameter is omitted
+	// parameter is omitted. It is the folder, inside the destination
+	// path, containing the base backups and the WAL files, and allows
+	// multiple clusters to share the same destination path. It can only
+	// contain letters, digits, dots, underscores and dashes
 	// +optional
 	ServerName string `json:"serverName,omitempty"`
 
@@ -1782,15 +3176,72 @@ type BarmanObjectStoreConfiguration struct {
 	// +optional
 	Data *DataBackupConfiguration `json:"data,omitempty"`
 
-	// Tags is a list of key value pairs that will be passed to the
-	// Barman --tags option.
-	// +optional
-	Tags map[string]string `json:"tags,omitempty"`
+	// Tags is a list of key value pairs that will be passed to the
+	// Barman --tags option.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// HistoryTags is a list of key value pairs that will be passed to the
+	// Barman --history-tags option.
+	// +optional
+	HistoryTags map[string]string `json:"historyTags,omitempty"`
+
+	// The envelope encryption of the WAL files and of the base backups
+	// with a key managed by a KMS. When defined, it overrides the
+	// `encryption` option of the `wal` and `data` sections
+	// +optional
+	Encryption *BarmanEncryptionConfiguration `json:"encryption,omitempty"`
+
+	// The maximum amount of data, in bytes per second, uploaded to the
+	// object store by the base backups and by the WAL archiving, passed
+	// to the barman-cloud --max-bandwidth option. There is no limit
+	// when it is not defined
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBandwidth *int64 `json:"maxBandwidth,omitempty"`
+}
+
+// BarmanEncryptionMethod is the method used to encrypt the backups
+type BarmanEncryptionMethod string
+
+const (
+	// BarmanEncryptionMethodSSEKMS means to use the server-side encryption
+	// with a key managed by the KMS
+	BarmanEncryptionMethodSSEKMS = BarmanEncryptionMethod("sse-kms")
+)
+
+// KMSProvider is the KMS managing the keys used to encrypt the backups
+type KMSProvider string
+
+const (
+	// KMSProviderAWS means to use the AWS Key Management Service
+	KMSProviderAWS = KMSProvider("aws")
+)
+
+// BarmanEncryptionConfiguration configures the envelope encryption of the
+// backups with a key managed by a KMS
+type BarmanEncryptionConfiguration struct {
+	// The encryption method. Currently, only `sse-kms` is supported
+	// +kubebuilder:validation:Enum=sse-kms
+	Method BarmanEncryptionMethod `json:"method"`
 
-	// HistoryTags is a list of key value pairs that will be passed to the
-	// Barman --history-tags option.
+	// The ARN, the ID or the alias of the KMS key encrypting the data keys
+	// +kubebuilder:validation:MinLength=1
+	KeyID string `json:"keyID"`
+
+	// The KMS managing the key. Currently, only `aws` is supported
+	// +kubebuilder:validation:Enum=aws
+	// +kubebuilder:default:=aws
 	// +optional
-	HistoryTags map[string]string `json:"historyTags,omitempty"`
+	Provider KMSProvider `json:"provider,omitempty"`
+}
+
+// GetProvider gets the KMS managing the key, applying the default value
+func (configuration *BarmanEncryptionConfiguration) GetProvider() KMSProvider {
+	if configuration.Provider == "" {
+		return KMSProviderAWS
+	}
+	return configuration.Provider
 }
 
 // BackupConfiguration defines how the backup of the cluster are taken.
@@ -1806,11 +3257,32 @@ type BackupConfiguration struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// The configuration of the volume used to store the base backups,
+	// taken with `pg_basebackup`, and the archived WAL files, as an
+	// alternative to the object stores
+	// +optional
+	VolumeBackup *VolumeBackupConfiguration `json:"volumeBackup,omitempty"`
+
+	// The object stores receiving a copy of the WAL files besides the
+	// `barmanObjectStore` one, i.e. for cross-cloud redundancy. Only
+	// their connection, credentials and `wal` settings are used
+	// +optional
+	AdditionalWalObjectStores []BarmanObjectStoreConfiguration `json:"additionalWalObjectStores,omitempty"`
+
+	// The number of object stores, including the `barmanObjectStore`
+	// one, that must archive a WAL file before reporting success to
+	// PostgreSQL. The WAL files failing on the other object stores are
+	// queued and archived again later. Defaults to all the object stores
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WalArchiveQuorum *int `json:"walArchiveQuorum,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
 	// days, weeks, months.
-	// It's currently only applicable when using the BarmanObjectStore method.
+	// It's currently only applicable when using the BarmanObjectStore or the
+	// VolumeBackup methods.
 	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
@@ -1825,6 +3297,17 @@ type BackupConfiguration struct {
 	Target BackupTarget `json:"target,omitempty"`
 }
 
+// VolumeBackupConfiguration is the configuration of the volume
+// dedicated to the backups of the cluster. The volume is mounted by
+// every instance, so it needs to support the `ReadWriteMany` access
+// mode when the cluster has more than one instance
+type VolumeBackupConfiguration struct {
+	// The configuration of the storage of the backup volume. The
+	// `ReadWriteOnce` access mode is used unless the PVC template
+	// specifies a different one
+	Storage StorageConfiguration `json:"storage"`
+}
+
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
@@ -1942,112 +3425,725 @@ type AzureCredentials struct {
 	// +optional
 	StorageSasToken *SecretKeySelector `json:"storageSasToken,omitempty"`
 
-	// Use the Azure AD based authentication without providing explicitly the keys.
+	// Use the Azure AD based authentication without providing explicitly the keys.
+	// +optional
+	InheritFromAzureAD bool `json:"inheritFromAzureAD,omitempty"`
+}
+
+// GoogleCredentials is the type for the Google Cloud Storage credentials.
+// This needs to be specified even if we run inside a GKE environment.
+type GoogleCredentials struct {
+	// The secret containing the Google Cloud Storage JSON file with the credentials
+	// +optional
+	ApplicationCredentials *SecretKeySelector `json:"applicationCredentials,omitempty"`
+
+	// If set to true, will presume that it's running inside a GKE environment,
+	// default to false.
+	// +optional
+	GKEEnvironment bool `json:"gkeEnvironment,omitempty"`
+}
+
+// MonitoringConfiguration is the type containing all the monitoring
+// configuration for a certain cluster
+type MonitoringConfiguration struct {
+	// Whether the default queries should be injected.
+	// Set it to `true` if you don't want to inject default queries into the cluster.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	DisableDefaultQueries *bool `json:"disableDefaultQueries,omitempty"`
+
+	// The list of config maps containing the custom queries
+	// +optional
+	CustomQueriesConfigMap []ConfigMapKeySelector `json:"customQueriesConfigMap,omitempty"`
+
+	// The list of secrets containing the custom queries
+	// +optional
+	CustomQueriesSecret []SecretKeySelector `json:"customQueriesSecret,omitempty"`
+
+	// Enable or disable the `PodMonitor`
+	// +kubebuilder:default:=false
+	// +optional
+	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// Whether the size of the template databases should be reported
+	// by the `cnpg_pg_database_size_bytes` metric.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	IncludeTemplateDatabases bool `json:"includeTemplateDatabases,omitempty"`
+
+	// Whether the metrics exporter should connect to PostgreSQL using the
+	// `cnpg_monitoring` role, member of `pg_monitor`, instead of the
+	// superuser. The role is created by the operator on the primary.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	UseDedicatedRole bool `json:"useDedicatedRole,omitempty"`
+
+	// The list of the built-in collectors of the metrics exporter that
+	// should be disabled, to avoid running queries whose metrics are
+	// not needed. Unknown names are ignored
+	// +optional
+	DisableDefaultCollectors []string `json:"disableDefaultCollectors,omitempty"`
+
+	// The metrics of the top statements by total execution time, read
+	// from `pg_stat_statements`. Enabling them loads and creates the
+	// `pg_stat_statements` extension
+	// +optional
+	TopStatements *TopStatementsConfiguration `json:"topStatements,omitempty"`
+
+	// Whether the progress of the backups and of the restores from the
+	// object store should be exported as metrics, telling if they are
+	// running, since when and, for the restores, the completed fraction.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	EnableBackupProgressMetrics bool `json:"enableBackupProgressMetrics,omitempty"`
+
+	// Whether the instance manager may report the text of the queries of
+	// the active backends, which may contain sensitive data, when it is
+	// explicitly requested through the `/pg/activity` endpoint.
+	// Default: false.
+	// +kubebuilder:default:=false
+	// +optional
+	ExposeActivityQueries bool `json:"exposeActivityQueries,omitempty"`
+}
+
+// TopStatementsConfiguration contains the settings of the metrics
+// of the top statements by total execution time
+type TopStatementsConfiguration struct {
+	// Whether the metrics of the top statements should be exposed
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of statements to be reported, starting from the
+	// one with the highest total execution time
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default:=10
+	// +optional
+	Limit int32 `json:"limit,omitempty"`
+}
+
+// DefaultTopStatementsLimit is the default number of statements reported
+// by the metrics of the top statements
+const DefaultTopStatementsLimit = 10
+
+const (
+	// CollectorSynchronousStandbys is the built-in collector of the
+	// number of synchronous standbys observed on the primary
+	CollectorSynchronousStandbys = "synchronous_standbys"
+
+	// CollectorDatabaseConflicts is the built-in collector of the queries
+	// canceled because of conflicts with recovery
+	CollectorDatabaseConflicts = "database_conflicts"
+
+	// CollectorDatabaseSize is the built-in collector of the size of
+	// the databases and of its growth rate
+	CollectorDatabaseSize = "database_size"
+
+	// CollectorCacheHitRatio is the built-in collector of the buffer
+	// cache hit ratio of the databases
+	CollectorCacheHitRatio = "cache_hit_ratio"
+
+	// CollectorTempFiles is the built-in collector of the temporary
+	// files usage of the databases
+	CollectorTempFiles = "temp_files"
+
+	// CollectorDataChecksums is the built-in collector of the data
+	// checksum failures of the databases
+	CollectorDataChecksums = "data_checksums"
+
+	// CollectorWALGenerationRate is the built-in collector of the WAL
+	// generation rate of the primary
+	CollectorWALGenerationRate = "wal_generation_rate"
+
+	// CollectorWALArchiveStatus is the built-in collector of the WAL
+	// segments waiting to be archived
+	CollectorWALArchiveStatus = "wal_archive_status"
+
+	// CollectorWALDirectory is the built-in collector of the size of
+	// the WAL directory
+	CollectorWALDirectory = "wal_directory"
+
+	// CollectorReplicationSlots is the built-in collector of the WAL
+	// retained by the replication slots
+	CollectorReplicationSlots = "replication_slots"
+
+	// CollectorIdleInTransaction is the built-in collector of the
+	// sessions idle in transaction
+	CollectorIdleInTransaction = "idle_in_transaction"
+
+	// CollectorLongestRunningQuery is the built-in collector of the
+	// longest running query of each database
+	CollectorLongestRunningQuery = "longest_running_query"
+
+	// CollectorTableXidAge is the built-in collector of the transaction
+	// ID age of the oldest tables, reported by the primary
+	CollectorTableXidAge = "table_xid_age"
+
+	// CollectorPgStatWAL is the built-in collector of the WAL activity
+	// statistics, available from PostgreSQL 14
+	CollectorPgStatWAL = "pg_stat_wal"
+)
+
+// DefaultCollectors are the built-in collectors of the metrics exporter
+// that can be disabled
+var DefaultCollectors = []string{
+	CollectorSynchronousStandbys,
+	CollectorDatabaseConflicts,
+	CollectorDatabaseSize,
+	CollectorCacheHitRatio,
+	CollectorTempFiles,
+	CollectorDataChecksums,
+	CollectorWALGenerationRate,
+	CollectorWALArchiveStatus,
+	CollectorWALDirectory,
+	CollectorReplicationSlots,
+	CollectorIdleInTransaction,
+	CollectorLongestRunningQuery,
+	CollectorTableXidAge,
+	CollectorPgStatWAL,
+}
+
+// AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
+func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
+	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
+}
+
+// AreTemplateDatabasesIncluded checks whether the size of the template
+// databases should be reported
+func (m *MonitoringConfiguration) AreTemplateDatabasesIncluded() bool {
+	return m != nil && m.IncludeTemplateDatabases
+}
+
+// IsCollectorDisabled checks whether a built-in collector of the
+// metrics exporter has been disabled
+func (m *MonitoringConfiguration) IsCollectorDisabled(name string) bool {
+	return m != nil && slices.Contains(m.DisableDefaultCollectors, name)
+}
+
+// GetTopStatementsLimit gets the number of statements to be reported by
+// the metrics of the top statements, zero if they are disabled
+func (m *MonitoringConfiguration) GetTopStatementsLimit() int {
+	if m == nil || m.TopStatements == nil || !m.TopStatements.Enabled {
+		return 0
+	}
+
+	if m.TopStatements.Limit <= 0 {
+		return DefaultTopStatementsLimit
+	}
+
+	return int(m.TopStatements.Limit)
+}
+
+// IsBackupProgressMetricsEnabled checks whether the progress of the
+// backups and of the restores should be exported as metrics
+func (m *MonitoringConfiguration) IsBackupProgressMetricsEnabled() bool {
+	return m != nil && m.EnableBackupProgressMetrics
+}
+
+// AreActivityQueriesExposed checks whether the text of the queries of the
+// active backends may be reported by the instance manager
+func (m *MonitoringConfiguration) AreActivityQueriesExposed() bool {
+	return m != nil && m.ExposeActivityQueries
+}
+
+// IsDedicatedRoleEnabled checks whether the metrics exporter should use
+// the dedicated monitoring role
+func (m *MonitoringConfiguration) IsDedicatedRoleEnabled() bool {
+	return m != nil && m.UseDedicatedRole
+}
+
+// ExternalCluster represents the connection parameters to an
+// external cluster which is used in the other sections of the configuration
+type ExternalCluster struct {
+	// The server name, required
+	Name string `json:"name"`
+
+	// The list of connection parameters, such as dbname, host, username, etc
+	// +optional
+	ConnectionParameters map[string]string `json:"connectionParameters,omitempty"`
+
+	// The reference to an SSL certificate to be used to connect to this
+	// instance
+	// +optional
+	SSLCert *corev1.SecretKeySelector `json:"sslCert,omitempty"`
+
+	// The reference to an SSL private key to be used to connect to this
+	// instance
+	// +optional
+	SSLKey *corev1.SecretKeySelector `json:"sslKey,omitempty"`
+
+	// The reference to an SSL CA public key to be used to connect to this
+	// instance
+	// +optional
+	SSLRootCert *corev1.SecretKeySelector `json:"sslRootCert,omitempty"`
+
+	// The reference to the password to be used to connect to the server
+	// +optional
+	Password *corev1.SecretKeySelector `json:"password,omitempty"`
+
+	// The configuration for the barman-cloud tool suite
+	// +optional
+	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+}
+
+// GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
+// in the BarmanObjectStore
+func (in ExternalCluster) GetServerName() string {
+	if in.BarmanObjectStore != nil {
+		return in.BarmanObjectStore.GetServerName(in.Name)
+	}
+	return in.Name
+}
+
+// GetServerName returns the name of the server in the object store,
+// defaulting to the passed cluster name when not specified
+func (in *BarmanObjectStoreConfiguration) GetServerName(clusterName string) string {
+	if in.ServerName != "" {
+		return in.ServerName
+	}
+	return clusterName
+}
+
+// EnsureOption represents whether we should enforce the presence or absence of
+// a Role in a PostgreSQL instance
+type EnsureOption string
+
+// values taken by EnsureOption
+const (
+	EnsurePresent EnsureOption = "present"
+	EnsureAbsent  EnsureOption = "absent"
+)
+
+// LifecycleHookFailurePolicy defines what happens when the postStartSQL
+// statements cannot be executed
+// +kubebuilder:validation:Enum=block;ignore
+type LifecycleHookFailurePolicy string
+
+const (
+	// LifecycleHookFailurePolicyBlock means that the instance won't be
+	// reported as ready until the postStartSQL statements succeed
+	LifecycleHookFailurePolicyBlock LifecycleHookFailurePolicy = "block"
+
+	// LifecycleHookFailurePolicyIgnore means that failures are logged
+	// and the instance is reported as ready anyway
+	LifecycleHookFailurePolicyIgnore LifecycleHookFailurePolicy = "ignore"
+)
+
+// DefaultLifecycleHookTimeout is the default timeout, in seconds, for every
+// SQL statement executed at a lifecycle point
+const DefaultLifecycleHookTimeout = 30
+
+// LifecycleConfiguration contains the SQL statements that the instance
+// manager executes, as the superuser in the `postgres` database, at the
+// corresponding lifecycle points of PostgreSQL
+type LifecycleConfiguration struct {
+	// The list of SQL statements executed, in order, after PostgreSQL has
+	// been started and before the instance is reported as ready
+	// +optional
+	PostStartSQL []string `json:"postStartSQL,omitempty"`
+
+	// The list of SQL statements executed, in order, before PostgreSQL is
+	// shut down. Failures are logged and never prevent the shutdown
+	// +optional
+	PreStopSQL []string `json:"preStopSQL,omitempty"`
+
+	// The maximum amount of time (in seconds) allowed for every statement
+	// to complete. Default value is 30 seconds
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+
+	// What to do when the postStartSQL statements fail: `block` retries
+	// them without reporting the instance as ready until they succeed,
+	// while `ignore` (the default) logs the failure and continues
+	// +kubebuilder:default:=ignore
+	// +optional
+	FailurePolicy LifecycleHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// GetTimeout returns the timeout for every lifecycle statement
+func (lifecycle *LifecycleConfiguration) GetTimeout() time.Duration {
+	if lifecycle.Timeout > 0 {
+		return time.Duration(lifecycle.Timeout) * time.Second
+	}
+	return DefaultLifecycleHookTimeout * time.Second
+}
+
+// GetFailurePolicy returns the failure policy for the postStartSQL statements
+func (lifecycle *LifecycleConfiguration) GetFailurePolicy() LifecycleHookFailurePolicy {
+	if lifecycle.FailurePolicy != "" {
+		return lifecycle.FailurePolicy
+	}
+	return LifecycleHookFailurePolicyIgnore
+}
+
+// ManagedConfiguration represents the portions of PostgreSQL that are managed
+// by the instance manager
+type ManagedConfiguration struct {
+	// Database roles managed by the `Cluster`
+	// +optional
+	Roles []RoleConfiguration `json:"roles,omitempty"`
+
+	// Logical replication publications managed by the `Cluster`
+	// +optional
+	Publications []PublicationConfiguration `json:"publications,omitempty"`
+
+	// Logical replication subscriptions managed by the `Cluster`
+	// +optional
+	Subscriptions []SubscriptionConfiguration `json:"subscriptions,omitempty"`
+
+	// Existing tables whose storage parameters are managed by the `Cluster`.
+	// The tables that are not listed are not touched
+	// +optional
+	Tables []TableConfiguration `json:"tables,omitempty"`
+
+	// One-off SQL scripts executed by the primary instance against a set
+	// of databases. Each job is executed only once: to run a script again,
+	// add it with a different name
+	// +optional
+	SQLJobs []SQLJobConfiguration `json:"sqlJobs,omitempty"`
+
+	// Heavy maintenance operations, rebuilding indexes or rewriting
+	// tables, executed by the primary instance on demand or on a
+	// schedule, one at a time
+	// +optional
+	MaintenanceJobs []MaintenanceJobConfiguration `json:"maintenanceJobs,omitempty"`
+}
+
+// PublicationOperation is a DML operation that can be replicated by
+// a publication
+// +kubebuilder:validation:Enum=insert;update;delete;truncate
+type PublicationOperation string
+
+// values taken by PublicationOperation
+const (
+	PublicationOperationInsert   PublicationOperation = "insert"
+	PublicationOperationUpdate   PublicationOperation = "update"
+	PublicationOperationDelete   PublicationOperation = "delete"
+	PublicationOperationTruncate PublicationOperation = "truncate"
+)
+
+// PublicationConfiguration is the representation, in Kubernetes, of a
+// PostgreSQL logical replication publication, with the additional field
+// Ensure specifying whether to ensure the presence or absence of the
+// publication in the database
+//
+// Reference: https://www.postgresql.org/docs/current/sql-createpublication.html
+type PublicationConfiguration struct {
+	// Name of the publication
+	Name string `json:"name"`
+
+	// The name of the database where the publication will be created,
+	// defaults to the application database
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// Ensure the publication is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// Marks the publication as one that replicates changes for all tables
+	// in the database, including tables created in the future.
+	// Cannot be used together with `tables`
+	// +optional
+	AllTables bool `json:"allTables,omitempty"`
+
+	// The list of tables to add to the publication, optionally
+	// schema-qualified (i.e. `schema.table`). Cannot be used together
+	// with `allTables`
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+
+	// The DML operations that will be published to the subscribers.
+	// When empty (the default), all the operations are published
+	// +optional
+	Publish []PublicationOperation `json:"publish,omitempty"`
+}
+
+// GetDBName returns the name of the database where the publication lives,
+// or the given default
+func (publication *PublicationConfiguration) GetDBName(defaultDBName string) string {
+	if publication.DBName != "" {
+		return publication.DBName
+	}
+	return defaultDBName
+}
+
+// GetPublish returns the list of operations that are published,
+// defaulting to all of them
+func (publication *PublicationConfiguration) GetPublish() []PublicationOperation {
+	if len(publication.Publish) > 0 {
+		return publication.Publish
+	}
+	return []PublicationOperation{
+		PublicationOperationInsert,
+		PublicationOperationUpdate,
+		PublicationOperationDelete,
+		PublicationOperationTruncate,
+	}
+}
+
+// SubscriptionConfiguration is the representation, in Kubernetes, of a
+// PostgreSQL logical replication subscription, with the additional field
+// Ensure specifying whether to ensure the presence or absence of the
+// subscription in the database
+//
+// Reference: https://www.postgresql.org/docs/current/sql-createsubscription.html
+type SubscriptionConfiguration struct {
+	// Name of the subscription
+	Name string `json:"name"`
+
+	// The name of the database where the subscription will be created,
+	// defaults to the application database
 	// +optional
-	InheritFromAzureAD bool `json:"inheritFromAzureAD,omitempty"`
-}
+	DBName string `json:"dbname,omitempty"`
 
-// GoogleCredentials is the type for the Google Cloud Storage credentials.
-// This needs to be specified even if we run inside a GKE environment.
-type GoogleCredentials struct {
-	// The secret containing the Google Cloud Storage JSON file with the credentials
+	// Ensure the subscription is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
 	// +optional
-	ApplicationCredentials *SecretKeySelector `json:"applicationCredentials,omitempty"`
+	Ensure EnsureOption `json:"ensure,omitempty"`
 
-	// If set to true, will presume that it's running inside a GKE environment,
-	// default to false.
+	// The secret containing the connection string to the publisher,
+	// in the libpq keyword/value or URI format
+	ConnectionSecret SecretKeySelector `json:"connectionSecret"`
+
+	// The name of the publication on the publisher
+	PublicationName string `json:"publicationName"`
+
+	// Whether the data already existing in the published tables should
+	// be copied when the subscription is created, defaults to `true`
 	// +optional
-	GKEEnvironment bool `json:"gkeEnvironment,omitempty"`
+	CopyData *bool `json:"copyData,omitempty"`
 }
 
-// MonitoringConfiguration is the type containing all the monitoring
-// configuration for a certain cluster
-type MonitoringConfiguration struct {
-	// Whether the default queries should be injected.
-	// Set it to `true` if you don't want to inject default queries into the cluster.
-	// Default: false.
-	// +kubebuilder:default:=false
-	// +optional
-	DisableDefaultQueries *bool `json:"disableDefaultQueries,omitempty"`
+// GetDBName returns the name of the database where the subscription lives,
+// or the given default
+func (subscription *SubscriptionConfiguration) GetDBName(defaultDBName string) string {
+	if subscription.DBName != "" {
+		return subscription.DBName
+	}
+	return defaultDBName
+}
 
-	// The list of config maps containing the custom queries
-	// +optional
-	CustomQueriesConfigMap []ConfigMapKeySelector `json:"customQueriesConfigMap,omitempty"`
+// GetCopyData returns whether the existing data should be copied
+// when the subscription is created
+func (subscription *SubscriptionConfiguration) GetCopyData() bool {
+	if subscription.CopyData == nil {
+		return true
+	}
+	return *subscription.CopyData
+}
 
-	// The list of secrets containing the custom queries
+// TableConfiguration is the configuration of the storage parameters
+// of an existing table
+//
+// Reference: https://www.postgresql.org/docs/current/sql-createtable.html#SQL-CREATETABLE-STORAGE-PARAMETERS
+type TableConfiguration struct {
+	// Name of the table, optionally schema-qualified (i.e. `schema.table`)
+	Name string `json:"name"`
+
+	// The name of the database where the table lives,
+	// defaults to the application database
 	// +optional
-	CustomQueriesSecret []SecretKeySelector `json:"customQueriesSecret,omitempty"`
+	DBName string `json:"dbname,omitempty"`
 
-	// Enable or disable the `PodMonitor`
-	// +kubebuilder:default:=false
+	// The autovacuum storage parameters of the table, e.g.
+	// `autovacuum_vacuum_scale_factor`, including the ones of its TOAST
+	// table, prefixed by `toast.`. The autovacuum storage parameters of the
+	// table that are not listed are reset to their default value
 	// +optional
-	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+	AutovacuumSettings map[string]string `json:"autovacuumSettings,omitempty"`
 }
 
-// AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
-func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
-	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
-}
+// GetDBName returns the name of the database where the table lives,
+// or the given default
+func (table *TableConfiguration) GetDBName(defaultDBName string) string {
+	if table.DBName != "" {
+		return table.DBName
+	}
+	return defaultDBName
+}
+
+// autovacuumStorageParameters are the storage parameters of a table
+// that can be set in the autovacuum settings
+var autovacuumStorageParameters = stringset.From([]string{
+	"autovacuum_enabled",
+	"autovacuum_vacuum_threshold",
+	"autovacuum_vacuum_scale_factor",
+	"autovacuum_vacuum_insert_threshold",
+	"autovacuum_vacuum_insert_scale_factor",
+	"autovacuum_analyze_threshold",
+	"autovacuum_analyze_scale_factor",
+	"autovacuum_vacuum_cost_delay",
+	"autovacuum_vacuum_cost_limit",
+	"autovacuum_freeze_min_age",
+	"autovacuum_freeze_max_age",
+	"autovacuum_freeze_table_age",
+	"autovacuum_multixact_freeze_min_age",
+	"autovacuum_multixact_freeze_max_age",
+	"autovacuum_multixact_freeze_table_age",
+	"log_autovacuum_min_duration",
+	"toast.autovacuum_enabled",
+	"toast.autovacuum_vacuum_threshold",
+	"toast.autovacuum_vacuum_scale_factor",
+	"toast.autovacuum_vacuum_insert_threshold",
+	"toast.autovacuum_vacuum_insert_scale_factor",
+	"toast.autovacuum_vacuum_cost_delay",
+	"toast.autovacuum_vacuum_cost_limit",
+	"toast.autovacuum_freeze_min_age",
+	"toast.autovacuum_freeze_max_age",
+	"toast.autovacuum_freeze_table_age",
+	"toast.autovacuum_multixact_freeze_min_age",
+	"toast.autovacuum_multixact_freeze_max_age",
+	"toast.autovacuum_multixact_freeze_table_age",
+	"toast.log_autovacuum_min_duration",
+})
+
+// IsAutovacuumStorageParameter checks if the passed storage parameter
+// of a table can be set in the autovacuum settings
+func IsAutovacuumStorageParameter(name string) bool {
+	return autovacuumStorageParameters.Has(name)
+}
+
+// SQLJobErrorPolicy defines what happens when a SQL job fails
+// in one of its databases
+// +kubebuilder:validation:Enum=stop;continue
+type SQLJobErrorPolicy string
 
-// ExternalCluster represents the connection parameters to an
-// external cluster which is used in the other sections of the configuration
-type ExternalCluster struct {
-	// The server name, required
+const (
+	// SQLJobErrorPolicyStop means that the databases following the
+	// failed one are skipped
+	SQLJobErrorPolicyStop SQLJobErrorPolicy = "stop"
+
+	// SQLJobErrorPolicyContinue means that the script is executed
+	// in the remaining databases anyway
+	SQLJobErrorPolicyContinue SQLJobErrorPolicy = "continue"
+)
+
+// SQLJobConfiguration is a SQL script to be executed once, sequentially,
+// against a set of databases of the cluster
+type SQLJobConfiguration struct {
+	// Name of the job, unique in the cluster
 	Name string `json:"name"`
 
-	// The list of connection parameters, such as dbname, host, username, etc
+	// The databases where the script is executed, in the given order.
+	// When empty, the script is executed in every database accepting
+	// connections, except the templates
 	// +optional
-	ConnectionParameters map[string]string `json:"connectionParameters,omitempty"`
+	Databases []string `json:"databases,omitempty"`
 
-	// The reference to an SSL certificate to be used to connect to this
-	// instance
+	// The SQL script to be executed. Its string literals are redacted
+	// in the logs of the instance manager
 	// +optional
-	SSLCert *corev1.SecretKeySelector `json:"sslCert,omitempty"`
+	SQL string `json:"sql,omitempty"`
 
-	// The reference to an SSL private key to be used to connect to this
-	// instance
+	// The key of a secret containing the SQL script to be executed,
+	// useful when the script contains sensitive data. The content
+	// of the script is never logged
 	// +optional
-	SSLKey *corev1.SecretKeySelector `json:"sslKey,omitempty"`
+	SecretRef *SecretKeySelector `json:"secretRef,omitempty"`
 
-	// The reference to an SSL CA public key to be used to connect to this
-	// instance
+	// What to do when the script fails in a database: `stop` skips
+	// the following databases, while `continue` executes the script
+	// in the remaining ones anyway
+	// +kubebuilder:default:=stop
 	// +optional
-	SSLRootCert *corev1.SecretKeySelector `json:"sslRootCert,omitempty"`
+	OnError SQLJobErrorPolicy `json:"onError,omitempty"`
 
-	// The reference to the password to be used to connect to the server
+	// The number of seconds the script is allowed to run in each database
+	// before being canceled, failing the job in that database.
+	// Default: 300.
+	// +kubebuilder:validation:Minimum=1
 	// +optional
-	Password *corev1.SecretKeySelector `json:"password,omitempty"`
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
 
-	// The configuration for the barman-cloud tool suite
-	// +optional
-	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+// GetOnError gets the error policy of the job, applying the default value
+func (job *SQLJobConfiguration) GetOnError() SQLJobErrorPolicy {
+	if job.OnError == "" {
+		return SQLJobErrorPolicyStop
+	}
+	return job.OnError
 }
 
-// GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
-// in the BarmanObjectStore
-func (in ExternalCluster) GetServerName() string {
-	if in.BarmanObjectStore != nil && in.BarmanObjectStore.ServerName != "" {
-		return in.BarmanObjectStore.ServerName
+// GetTimeout gets how long the script of the job is allowed to run in
+// each database, applying the default value
+func (job *SQLJobConfiguration) GetTimeout() time.Duration {
+	if job.TimeoutSeconds == nil {
+		return DefaultSQLJobTimeoutSeconds * time.Second
 	}
-	return in.Name
+	return time.Duration(*job.TimeoutSeconds) * time.Second
 }
 
-// EnsureOption represents whether we should enforce the presence or absence of
-// a Role in a PostgreSQL instance
-type EnsureOption string
+// MaintenanceOperation is a heavy maintenance operation
+// +kubebuilder:validation:Enum=reindexConcurrently;vacuumFull
+type MaintenanceOperation string
 
-// values taken by EnsureOption
 const (
-	EnsurePresent EnsureOption = "present"
-	EnsureAbsent  EnsureOption = "absent"
+	// MaintenanceOperationReindexConcurrently rebuilds the indexes with
+	// `REINDEX CONCURRENTLY`, without blocking the writes
+	MaintenanceOperationReindexConcurrently MaintenanceOperation = "reindexConcurrently"
+
+	// MaintenanceOperationVacuumFull rewrites the tables with `VACUUM (FULL)`,
+	// holding an exclusive lock on each of them while it is processed
+	MaintenanceOperationVacuumFull MaintenanceOperation = "vacuumFull"
 )
 
-// ManagedConfiguration represents the portions of PostgreSQL that are managed
-// by the instance manager
-type ManagedConfiguration struct {
-	// Database roles managed by the `Cluster`
+// MaintenanceJobConfiguration is a heavy maintenance operation executed
+// by the primary instance on a set of tables or indexes of a database
+type MaintenanceJobConfiguration struct {
+	// Name of the job, unique in the cluster
+	Name string `json:"name"`
+
+	// The maintenance operation to be executed
+	Operation MaintenanceOperation `json:"operation"`
+
+	// The name of the database where the targets live,
+	// defaults to the application database
 	// +optional
-	Roles []RoleConfiguration `json:"roles,omitempty"`
+	DBName string `json:"dbname,omitempty"`
+
+	// The tables to be processed, optionally schema-qualified (i.e.
+	// `schema.table`). With `reindexConcurrently`, all the indexes of
+	// the tables are rebuilt
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+
+	// The indexes to be rebuilt, optionally schema-qualified (i.e.
+	// `schema.index`). Only allowed with `reindexConcurrently`
+	// +optional
+	Indexes []string `json:"indexes,omitempty"`
+
+	// The schedule of the executions of the job, using the Cron expression
+	// format of the scheduled backups, which includes the seconds specifier.
+	// The schedule is evaluated in UTC. When empty, the job is executed
+	// only once, as soon as possible
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// When set to `true`, the running execution of the job is canceled,
+	// and no other one is started until it is set to `false` again
+	// +kubebuilder:default:=false
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GetDBName returns the name of the database where the targets of the
+// job live, or the given default
+func (job *MaintenanceJobConfiguration) GetDBName(defaultDBName string) string {
+	if job.DBName != "" {
+		return job.DBName
+	}
+	return defaultDBName
 }
 
 // RoleConfiguration is the representation, in Kubernetes, of a PostgreSQL role
@@ -2262,6 +4358,72 @@ func (secretResourceVersion *SecretsResourceVersion) SetManagedRoleSecretVersion
 	}
 }
 
+// GetInstanceParameters gets the PostgreSQL parameters overridden
+// for the passed instance, or nil if there are none
+func (cluster *Cluster) GetInstanceParameters(instanceName string) map[string]string {
+	for _, override := range cluster.Spec.InstanceOverrides {
+		if override.Name == instanceName {
+			return override.Parameters
+		}
+	}
+
+	return nil
+}
+
+// GetExtensionsParameters gets the PostgreSQL parameters deciding which of
+// the extensions managed by the operator are used. The metrics of the top
+// statements require `pg_stat_statements`, which is enabled with its default
+// tracking level when the user didn't configure it
+func (cluster *Cluster) GetExtensionsParameters() map[string]string {
+	parameters := cluster.Spec.PostgresConfiguration.Parameters
+	if cluster.Spec.Monitoring.GetTopStatementsLimit() == 0 {
+		return parameters
+	}
+
+	for name := range parameters {
+		if strings.HasPrefix(name, "pg_stat_statements.") {
+			return parameters
+		}
+	}
+
+	result := make(map[string]string, len(parameters)+1)
+	for name, value := range parameters {
+		result[name] = value
+	}
+	result["pg_stat_statements.track"] = "top"
+
+	return result
+}
+
+// GetSSLNegotiation gets the SSL negotiation mode used by the replicas
+// to connect to the primary, falling back to the `postgres` one when the
+// PostgreSQL version of the cluster doesn't support direct TLS negotiation
+// or can't be detected
+func (cluster *Cluster) GetSSLNegotiation() SSLNegotiationMode {
+	if cluster.Spec.PostgresConfiguration.SSLNegotiation != SSLNegotiationModeDirect {
+		return SSLNegotiationModePostgres
+	}
+
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil || version < DirectSSLNegotiationMinVersion {
+		return SSLNegotiationModePostgres
+	}
+
+	return SSLNegotiationModeDirect
+}
+
+// ShouldAnalyzeAfterImport tells if the planner statistics need to be
+// regenerated in background because the data has been imported or
+// recovered
+func (cluster *Cluster) ShouldAnalyzeAfterImport() bool {
+	if !cluster.Spec.PostgresConfiguration.AnalyzeAfterImport || cluster.Spec.Bootstrap == nil {
+		return false
+	}
+
+	bootstrap := cluster.Spec.Bootstrap
+	return bootstrap.Recovery != nil || (bootstrap.InitDB != nil && bootstrap.InitDB.Import != nil)
+}
+
 // GetImageName get the name of the image that should be used
 // to create the pods
 func (cluster *Cluster) GetImageName() string {
@@ -2269,9 +4431,30 @@ func (cluster *Cluster) GetImageName() string {
 		return cluster.Spec.ImageName
 	}
 
+	// the instances keep their image while the operator upgrades are skipped
+	if utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) && cluster.Status.Image != "" {
+		return cluster.Status.Image
+	}
+
 	return configuration.Current.PostgresImageName
 }
 
+// GetWalSegmentSize gets the size in megabytes of the WAL segments to be
+// requested to initdb, preferring the one in the PostgreSQL configuration
+// to the one in the initdb bootstrap section. Zero means the PostgreSQL
+// default
+func (cluster *Cluster) GetWalSegmentSize() int {
+	if cluster.Spec.PostgresConfiguration.WalSegmentSize != 0 {
+		return cluster.Spec.PostgresConfiguration.WalSegmentSize
+	}
+
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		return cluster.Spec.Bootstrap.InitDB.WalSegmentSize
+	}
+
+	return 0
+}
+
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
 // image name.
 // Example:
@@ -2327,6 +4510,31 @@ func (cluster *Cluster) ContainsManagedRolesConfiguration() bool {
 	return cluster.Spec.Managed != nil && len(cluster.Spec.Managed.Roles) > 0
 }
 
+// ContainsManagedSubscriptionsConfiguration returns true iff there are managed subscriptions configured
+func (cluster *Cluster) ContainsManagedSubscriptionsConfiguration() bool {
+	return cluster.Spec.Managed != nil && len(cluster.Spec.Managed.Subscriptions) > 0
+}
+
+// ContainsManagedTablesConfiguration returns true iff there are managed tables configured
+func (cluster *Cluster) ContainsManagedTablesConfiguration() bool {
+	return cluster.Spec.Managed != nil && len(cluster.Spec.Managed.Tables) > 0
+}
+
+// ContainsMaintenanceJobsConfiguration returns true iff there are managed maintenance jobs configured
+func (cluster *Cluster) ContainsMaintenanceJobsConfiguration() bool {
+	return cluster.Spec.Managed != nil && len(cluster.Spec.Managed.MaintenanceJobs) > 0
+}
+
+// ContainsManagedSQLJobsConfiguration returns true iff there are managed SQL jobs configured
+func (cluster *Cluster) ContainsManagedSQLJobsConfiguration() bool {
+	return cluster.Spec.Managed != nil && len(cluster.Spec.Managed.SQLJobs) > 0
+}
+
+// ContainsManagedPublicationsConfiguration returns true iff there are managed publications configured
+func (cluster *Cluster) ContainsManagedPublicationsConfiguration() bool {
+	return cluster.Spec.Managed != nil && len(cluster.Spec.Managed.Publications) > 0
+}
+
 // UsesSecretInManagedRoles checks if the given secret name is used in a managed role
 func (cluster *Cluster) UsesSecretInManagedRoles(secretName string) bool {
 	if !cluster.ContainsManagedRolesConfiguration() {
@@ -2340,6 +4548,20 @@ func (cluster *Cluster) UsesSecretInManagedRoles(secretName string) bool {
 	return false
 }
 
+// UsesSecretInManagedSubscriptions checks if a secret is used for the
+// connection string of a managed subscription
+func (cluster *Cluster) UsesSecretInManagedSubscriptions(secretName string) bool {
+	if !cluster.ContainsManagedSubscriptionsConfiguration() {
+		return false
+	}
+	for _, subscription := range cluster.Spec.Managed.Subscriptions {
+		if subscription.ConnectionSecret.Name == secretName {
+			return true
+		}
+	}
+	return false
+}
+
 // GetApplicationSecretName get the name of the application secret for any bootstrap type
 func (cluster *Cluster) GetApplicationSecretName() string {
 	bootstrap := cluster.Spec.Bootstrap
@@ -2417,6 +4639,12 @@ func (cluster *Cluster) GetServerCASecretName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
 }
 
+// GetServerCAConfigMapName get the name of the ConfigMap exporting the
+// certificate of the server CA to the clients
+func (cluster *Cluster) GetServerCAConfigMapName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServerCAConfigMapSuffix)
+}
+
 // GetServerTLSSecretName get the name of the secret containing the
 // certificate that is used for the PostgreSQL servers
 func (cluster *Cluster) GetServerTLSSecretName() string {
@@ -2539,6 +4767,22 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// GetRestartDeferral checks if the restarts of the instances needed to
+// apply configuration changes are to be deferred at the given time,
+// returning when the next maintenance window will start
+func (cluster *Cluster) GetRestartDeferral(now time.Time) (bool, time.Time, error) {
+	if cluster.Spec.MaintenanceWindow == nil || utils.IsMaintenanceWindowBypassed(&cluster.ObjectMeta) {
+		return false, time.Time{}, nil
+	}
+
+	inProgress, start, err := cluster.Spec.MaintenanceWindow.IsInProgress(now)
+	if err != nil || inProgress {
+		return false, time.Time{}, err
+	}
+
+	return true, start, nil
+}
+
 // IsNodeMaintenanceWindowInProgress check if the upgrade mode is active or not
 func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
@@ -2563,8 +4807,23 @@ func (cluster *Cluster) IsReusePVCEnabled() bool {
 	return reusePVC
 }
 
-// IsInstanceFenced check if in a given instance should be fenced
+// IsInstanceQuarantined checks if a given instance has been quarantined
+func (cluster *Cluster) IsInstanceQuarantined(instance string) bool {
+	quarantinedInstances, err := utils.GetQuarantinedInstances(cluster.Annotations)
+	if err != nil {
+		return false
+	}
+
+	return quarantinedInstances.Has(instance)
+}
+
+// IsInstanceFenced check if in a given instance should be fenced.
+// Quarantined instances are fenced too
 func (cluster *Cluster) IsInstanceFenced(instance string) bool {
+	if cluster.IsInstanceQuarantined(instance) {
+		return true
+	}
+
 	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
 	if err != nil {
 		return false
@@ -2705,11 +4964,147 @@ func (cluster *Cluster) ShouldCreateProjectedVolume() bool {
 	return cluster.Spec.ProjectedVolumeTemplate != nil
 }
 
+// statsTempDirectoryMemoryRatio is the fraction of the pod memory
+// dedicated to the memory backed volume of the stats_temp_directory
+const statsTempDirectoryMemoryRatio = 16
+
+// statsTempDirectoryMaxVersion is the first PostgreSQL version
+// not having the stats_temp_directory parameter
+const statsTempDirectoryMaxVersion = 150000
+
+// ShouldCreateStatsTempVolume returns whether we should create the memory
+// backed volume hosting the stats_temp_directory
+func (cluster *Cluster) ShouldCreateStatsTempVolume() bool {
+	if !cluster.Spec.PostgresConfiguration.StatsTempDirectoryInMemory {
+		return false
+	}
+
+	version, err := cluster.GetPostgresqlVersion()
+	return err == nil && version < statsTempDirectoryMaxVersion
+}
+
+// GetStatsTempVolumeSizeLimit gets the size limit of the memory backed volume
+// hosting the stats_temp_directory, as a fraction of the memory limit of the
+// pod, or of the memory request when no limit is set. Nil if the memory of the
+// pod is not specified
+func (cluster *Cluster) GetStatsTempVolumeSizeLimit() *resource.Quantity {
+	memory := cluster.getPodMemory()
+	if memory.IsZero() {
+		return nil
+	}
+
+	return resource.NewQuantity(memory.Value()/statsTempDirectoryMemoryRatio, resource.BinarySI)
+}
+
+// effectiveCacheSizeMemoryPercentage is the percentage of the pod memory
+// used as effective_cache_size when it is computed automatically
+const effectiveCacheSizeMemoryPercentage = 75
+
+// GetAutoEffectiveCacheSize gets the value of effective_cache_size, in kB,
+// computed from the memory limit of the pod, or from the memory request when
+// no limit is set. Empty if the feature is not enabled or the memory of the
+// pod is not specified
+func (cluster *Cluster) GetAutoEffectiveCacheSize() string {
+	if !cluster.Spec.PostgresConfiguration.AutoEffectiveCacheSize {
+		return ""
+	}
+
+	memory := cluster.getPodMemory()
+	if memory.IsZero() {
+		return ""
+	}
+
+	sizeKB := memory.Value() / 1024 * effectiveCacheSizeMemoryPercentage / 100
+	return fmt.Sprintf("%dkB", max(sizeKB, 8))
+}
+
+// getPodMemory gets the memory limit of the pod, or the memory request
+// when no limit is set
+func (cluster *Cluster) getPodMemory() *resource.Quantity {
+	memory := cluster.Spec.Resources.Limits.Memory()
+	if memory.IsZero() {
+		memory = cluster.Spec.Resources.Requests.Memory()
+	}
+	return memory
+}
+
+// GetStatsTempDirectoryCondition gets the condition reporting whether the
+// stats_temp_directory is placed in memory. Nil if the feature is not enabled
+func (cluster *Cluster) GetStatsTempDirectoryCondition() *metav1.Condition {
+	if !cluster.Spec.PostgresConfiguration.StatsTempDirectoryInMemory {
+		return nil
+	}
+
+	if cluster.ShouldCreateStatsTempVolume() {
+		return &metav1.Condition{
+			Type:    string(ConditionStatsTempDirectoryInMemory),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonStatsTempDirectoryInMemory),
+			Message: "The stats_temp_directory is placed on a memory backed volume",
+		}
+	}
+
+	message := "PostgreSQL 15 and later keep the statistics in shared memory, " +
+		"statsTempDirectoryInMemory has no effect"
+	if _, err := cluster.GetPostgresqlVersion(); err != nil {
+		message = "Cannot detect the PostgreSQL version from the image name, " +
+			"statsTempDirectoryInMemory has no effect"
+	}
+
+	return &metav1.Condition{
+		Type:    string(ConditionStatsTempDirectoryInMemory),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(ConditionReasonStatsTempDirectoryNotSupported),
+		Message: message,
+	}
+}
+
+// IsDurabilityRelaxed checks if the crash safety guarantees of
+// PostgreSQL are disabled on the instances
+func (cluster *Cluster) IsDurabilityRelaxed() bool {
+	return cluster.Spec.PostgresConfiguration.Durability == DurabilityModeRelaxed
+}
+
+// GetRelaxedDurabilityCondition gets the condition warning about the data
+// loss risk of the relaxed durability. Nil if the durability is strict
+func (cluster *Cluster) GetRelaxedDurabilityCondition() *metav1.Condition {
+	if !cluster.IsDurabilityRelaxed() {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:   string(ConditionRelaxedDurability),
+		Status: metav1.ConditionTrue,
+		Reason: string(ConditionReasonRelaxedDurability),
+		Message: "WARNING: fsync, full_page_writes and synchronous_commit are off, " +
+			"a crash of an instance can lose or corrupt the data. Use the relaxed durability " +
+			"only on ephemeral clusters",
+	}
+}
+
 // ShouldCreateWalArchiveVolume returns whether we should create the wal archive volume
 func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// ShouldCreateBackupVolume returns whether we should create the volume
+// dedicated to the backups of the cluster
+func (cluster *Cluster) ShouldCreateBackupVolume() bool {
+	return cluster.Spec.Backup != nil && cluster.Spec.Backup.VolumeBackup != nil
+}
+
+// GetBackupVolumeName returns the name of the PVC dedicated to the
+// backups of the cluster
+func (cluster *Cluster) GetBackupVolumeName() string {
+	return cluster.Name + BackupVolumeSuffix
+}
+
+// ShouldCreateLogVolume returns whether we should create the volume
+// dedicated to the PostgreSQL logs
+func (cluster *Cluster) ShouldCreateLogVolume() bool {
+	return cluster.Spec.LogStorage != nil
+}
+
 // GetPostgresUID returns the UID that is being used for the "postgres"
 // user
 func (cluster Cluster) GetPostgresUID() int64 {
@@ -2745,6 +5140,25 @@ func (cluster Cluster) IsReplica() bool {
 	return cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled
 }
 
+// IsPromotionBlocked checks if the promotion of this replica cluster and
+// the changes of its designated primary are blocked
+func (cluster Cluster) IsPromotionBlocked() bool {
+	return cluster.IsReplica() && cluster.Spec.ReplicaCluster.PromotionBlocked
+}
+
+// GetReplicaApplicationName returns the application name the given
+// instance registers with when streaming from the primary
+func (cluster *Cluster) GetReplicaApplicationName(instanceName string) string {
+	return cluster.Spec.PostgresConfiguration.ReplicaConnection.GetApplicationName(instanceName)
+}
+
+// GetInstanceNameFromApplicationName returns the name of the instance
+// registered on the primary with the given application name, and false
+// if it's not an instance of this cluster
+func (cluster *Cluster) GetInstanceNameFromApplicationName(applicationName string) (string, bool) {
+	return cluster.Spec.PostgresConfiguration.ReplicaConnection.GetInstanceName(cluster.Name, applicationName)
+}
+
 var slotNameNegativeRegex = regexp.MustCompile("[^a-z0-9_]+")
 
 // GetSlotNameFromInstanceName returns the slot name, given the instance name.
@@ -2816,6 +5230,10 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 		return true
 	}
 
+	if cluster.UsesSecretInManagedSubscriptions(secret) {
+		return true
+	}
+
 	if cluster.Spec.Backup.IsBarmanEndpointCASet() && cluster.Spec.Backup.BarmanObjectStore.EndpointCA.Name == secret {
 		return true
 	}
@@ -2981,6 +5399,33 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// GetWalObjectStores returns the object stores where the WAL files are
+// archived, starting from the `barmanObjectStore` one
+func (backupConfiguration *BackupConfiguration) GetWalObjectStores() []*BarmanObjectStoreConfiguration {
+	if backupConfiguration == nil || backupConfiguration.BarmanObjectStore == nil {
+		return nil
+	}
+
+	result := make([]*BarmanObjectStoreConfiguration, 0, 1+len(backupConfiguration.AdditionalWalObjectStores))
+	result = append(result, backupConfiguration.BarmanObjectStore)
+	for idx := range backupConfiguration.AdditionalWalObjectStores {
+		result = append(result, &backupConfiguration.AdditionalWalObjectStores[idx])
+	}
+	return result
+}
+
+// GetWalArchiveQuorum returns the number of object stores that must
+// archive a WAL file before reporting success to PostgreSQL, defaulting
+// to all of them
+func (backupConfiguration *BackupConfiguration) GetWalArchiveQuorum() int {
+	objectStores := len(backupConfiguration.GetWalObjectStores())
+	if backupConfiguration == nil || backupConfiguration.WalArchiveQuorum == nil {
+		return objectStores
+	}
+
+	return min(*backupConfiguration.WalArchiveQuorum, objectStores)
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -2991,6 +5436,23 @@ func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
 		backupConfiguration.BarmanObjectStore.EndpointCA.Key != ""
 }
 
+// GetBackupID gets the ID or the name of the backup to recover from,
+// either set in the recovery section or in its recovery target.
+// An empty value means that the backup is chosen automatically
+func (recovery *BootstrapRecovery) GetBackupID() string {
+	if recovery == nil {
+		return ""
+	}
+	if recovery.BackupID != "" {
+		return recovery.BackupID
+	}
+	if recovery.RecoveryTarget != nil {
+		return recovery.RecoveryTarget.BackupID
+	}
+
+	return ""
+}
+
 // BuildPostgresOptions create the list of options that
 // should be added to the PostgreSQL configuration to
 // recover given a certain target
@@ -17,11 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -56,6 +59,10 @@ var _ = Describe("PostgreSQL cluster type", func() {
 		Expect(postgresql.GetServerCASecretName()).To(Equal("clustername-ca"))
 	})
 
+	It("correctly set the name of the ConfigMap exporting the CA of the cluster", func() {
+		Expect(postgresql.GetServerCAConfigMapName()).To(Equal("clustername-ca.crt"))
+	})
+
 	It("correctly set the name of the secret containing the certificate for PostgreSQL", func() {
 		Expect(postgresql.GetServerTLSSecretName()).To(Equal("clustername-server"))
 	})
@@ -785,6 +792,36 @@ var _ = Describe("Fencing annotation", func() {
 			Expect(cluster.IsInstanceFenced("one")).To(BeFalse())
 		})
 	})
+
+	When("an instance is quarantined", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.QuarantinedInstancesAnnotation: "[\"two\"]",
+				},
+			},
+		}
+
+		It("fences it", func() {
+			Expect(cluster.IsInstanceQuarantined("two")).To(BeTrue())
+			Expect(cluster.IsInstanceFenced("two")).To(BeTrue())
+			Expect(cluster.IsInstanceFenced("one")).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Quarantine configuration", func() {
+	It("is disabled by default", func() {
+		var configuration *QuarantineConfiguration
+		Expect(configuration.IsEnabled()).To(BeFalse())
+		Expect(configuration.GetWindow()).To(Equal(DefaultQuarantineWindow * time.Second))
+	})
+
+	It("uses the requested window", func() {
+		configuration := &QuarantineConfiguration{FailedStartups: 3, Window: 60}
+		Expect(configuration.IsEnabled()).To(BeTrue())
+		Expect(configuration.GetWindow()).To(Equal(time.Minute))
+	})
 })
 
 var _ = Describe("Barman credentials", func() {
@@ -799,6 +836,26 @@ var _ = Describe("Barman credentials", func() {
 	})
 })
 
+var _ = Describe("WAL retention cap of the replication slots", func() {
+	It("is not set by default", func() {
+		var replicationSlots *ReplicationSlotsConfiguration
+		Expect(replicationSlots.GetMaxSlotWALKeepSize()).To(BeEmpty())
+		Expect((&ReplicationSlotsConfiguration{}).GetMaxSlotWALKeepSize()).To(BeEmpty())
+	})
+
+	It("is converted to megabytes", func() {
+		Expect((&ReplicationSlotsConfiguration{MaxSlotWALKeepSize: "10Gi"}).GetMaxSlotWALKeepSize()).
+			To(Equal("10240MB"))
+		Expect((&ReplicationSlotsConfiguration{MaxSlotWALKeepSize: "1536Mi"}).GetMaxSlotWALKeepSize()).
+			To(Equal("1536MB"))
+	})
+
+	It("is ignored when not valid", func() {
+		Expect((&ReplicationSlotsConfiguration{MaxSlotWALKeepSize: "ten gigabytes"}).GetMaxSlotWALKeepSize()).
+			To(BeEmpty())
+	})
+})
+
 var _ = Describe("Replication slots names for instances", func() {
 	It("returns an empty name when no replication slots are configured", func() {
 		cluster := Cluster{}
@@ -971,3 +1028,553 @@ var _ = Describe("Ephemeral volume size limits", func() {
 		Expect(spec.GetTemporaryDataLimit().String()).To(Equal("20Mi"))
 	})
 })
+
+var _ = Describe("Recovery target PostgreSQL options", func() {
+	It("is empty when no target is specified", func() {
+		var target *RecoveryTarget
+		Expect(target.BuildPostgresOptions()).To(BeEmpty())
+	})
+
+	DescribeTable("renders the target timeline",
+		func(targetTLI string, expected string) {
+			target := &RecoveryTarget{TargetTLI: targetTLI}
+			Expect(target.BuildPostgresOptions()).To(Equal(expected + "recovery_target_inclusive = true\n"))
+		},
+		Entry("latest", "latest", "recovery_target_timeline = 'latest'\n"),
+		Entry("current", "current", "recovery_target_timeline = 'current'\n"),
+		Entry("a numeric timeline", "3", "recovery_target_timeline = '3'\n"),
+		Entry("nothing when not specified", "", ""),
+	)
+})
+
+var _ = Describe("stats_temp_directory in memory", func() {
+	newCluster := func(imageName string, enabled bool) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				ImageName: imageName,
+				PostgresConfiguration: PostgresConfiguration{
+					StatsTempDirectoryInMemory: enabled,
+				},
+			},
+		}
+	}
+
+	It("is used only when enabled on PostgreSQL versions having a stats_temp_directory", func() {
+		Expect(newCluster("postgres:14.10", true).ShouldCreateStatsTempVolume()).To(BeTrue())
+		Expect(newCluster("postgres:14.10", false).ShouldCreateStatsTempVolume()).To(BeFalse())
+		Expect(newCluster("postgres:15.5", true).ShouldCreateStatsTempVolume()).To(BeFalse())
+		Expect(newCluster("postgres:latest", true).ShouldCreateStatsTempVolume()).To(BeFalse())
+	})
+
+	It("is sized after the memory limit, or the memory request, of the pod", func() {
+		cluster := newCluster("postgres:14.10", true)
+		Expect(cluster.GetStatsTempVolumeSizeLimit()).To(BeNil())
+
+		cluster.Spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}
+		Expect(cluster.GetStatsTempVolumeSizeLimit().String()).To(Equal("32Mi"))
+
+		cluster.Spec.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}
+		Expect(cluster.GetStatsTempVolumeSizeLimit().String()).To(Equal("128Mi"))
+	})
+
+	It("has no condition when not enabled", func() {
+		Expect(newCluster("postgres:14.10", false).GetStatsTempDirectoryCondition()).To(BeNil())
+	})
+
+	It("reports the volume is used", func() {
+		condition := newCluster("postgres:14.10", true).GetStatsTempDirectoryCondition()
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(string(ConditionStatsTempDirectoryInMemory)))
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(ConditionReasonStatsTempDirectoryInMemory)))
+	})
+
+	It("reports the option has no effect on PostgreSQL keeping the statistics in shared memory", func() {
+		condition := newCluster("postgres:16.1", true).GetStatsTempDirectoryCondition()
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(v1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(ConditionReasonStatsTempDirectoryNotSupported)))
+		Expect(condition.Message).To(ContainSubstring("shared memory"))
+	})
+})
+
+var _ = Describe("Automatic effective_cache_size", func() {
+	newCluster := func(enabled bool) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AutoEffectiveCacheSize: enabled,
+				},
+			},
+		}
+	}
+
+	It("is not computed when not enabled", func() {
+		cluster := newCluster(false)
+		cluster.Spec.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}
+		Expect(cluster.GetAutoEffectiveCacheSize()).To(BeEmpty())
+	})
+
+	It("is not computed when the memory of the pod is not specified", func() {
+		Expect(newCluster(true).GetAutoEffectiveCacheSize()).To(BeEmpty())
+	})
+
+	It("is computed from the memory limit, or the memory request, of the pod", func() {
+		cluster := newCluster(true)
+		cluster.Spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		}
+		Expect(cluster.GetAutoEffectiveCacheSize()).To(Equal("3145728kB"))
+
+		cluster.Spec.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}
+		Expect(cluster.GetAutoEffectiveCacheSize()).To(Equal("6291456kB"))
+	})
+
+	It("is never lower than the minimum accepted by PostgreSQL", func() {
+		cluster := newCluster(true)
+		cluster.Spec.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Ki"),
+		}
+		Expect(cluster.GetAutoEffectiveCacheSize()).To(Equal("8kB"))
+	})
+})
+
+var _ = Describe("Analyze after import", func() {
+	It("is disabled by default", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{},
+				},
+			},
+		}
+		Expect(cluster.ShouldAnalyzeAfterImport()).To(BeFalse())
+	})
+
+	It("is required after a recovery or an import", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{AnalyzeAfterImport: true},
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{},
+				},
+			},
+		}
+		Expect(cluster.ShouldAnalyzeAfterImport()).To(BeTrue())
+
+		cluster.Spec.Bootstrap = &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{Import: &Import{}},
+		}
+		Expect(cluster.ShouldAnalyzeAfterImport()).To(BeTrue())
+	})
+
+	It("is not required for a new cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{AnalyzeAfterImport: true},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+				},
+			},
+		}
+		Expect(cluster.ShouldAnalyzeAfterImport()).To(BeFalse())
+
+		cluster.Spec.Bootstrap = nil
+		Expect(cluster.ShouldAnalyzeAfterImport()).To(BeFalse())
+	})
+})
+
+var _ = Describe("Failover configuration", func() {
+	It("doesn't bound the data loss by default", func() {
+		var configuration *FailoverConfiguration
+		Expect(configuration.IsDataLossBounded()).To(BeFalse())
+		Expect((&FailoverConfiguration{}).IsDataLossBounded()).To(BeFalse())
+		Expect((&FailoverConfiguration{MaxDataLossBytes: ptr.To(int64(0))}).IsDataLossBounded()).To(BeTrue())
+	})
+
+	It("is fail-closed by default", func() {
+		var configuration *FailoverConfiguration
+		Expect(configuration.GetDataLossPolicy()).To(Equal(FailoverDataLossPolicyFailClosed))
+		Expect((&FailoverConfiguration{
+			DataLossPolicy: FailoverDataLossPolicyFailOpen,
+		}).GetDataLossPolicy()).To(Equal(FailoverDataLossPolicyFailOpen))
+	})
+})
+
+var _ = Describe("SSL negotiation", func() {
+	newCluster := func(imageName string, mode SSLNegotiationMode) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				ImageName: imageName,
+				PostgresConfiguration: PostgresConfiguration{
+					SSLNegotiation: mode,
+				},
+			},
+		}
+	}
+
+	It("uses the postgres negotiation by default", func() {
+		Expect(newCluster("postgres:17.0", "").GetSSLNegotiation()).To(Equal(SSLNegotiationModePostgres))
+		Expect(newCluster("postgres:17.0", SSLNegotiationModePostgres).GetSSLNegotiation()).
+			To(Equal(SSLNegotiationModePostgres))
+	})
+
+	It("uses the direct negotiation from PostgreSQL 17", func() {
+		Expect(newCluster("postgres:17.0", SSLNegotiationModeDirect).GetSSLNegotiation()).
+			To(Equal(SSLNegotiationModeDirect))
+		Expect(newCluster("postgres:18", SSLNegotiationModeDirect).GetSSLNegotiation()).
+			To(Equal(SSLNegotiationModeDirect))
+	})
+
+	It("falls back to the postgres negotiation on older versions", func() {
+		Expect(newCluster("postgres:16.2", SSLNegotiationModeDirect).GetSSLNegotiation()).
+			To(Equal(SSLNegotiationModePostgres))
+	})
+
+	It("falls back to the postgres negotiation when the version can't be detected", func() {
+		Expect(newCluster("postgres:latest", SSLNegotiationModeDirect).GetSSLNegotiation()).
+			To(Equal(SSLNegotiationModePostgres))
+	})
+})
+
+var _ = Describe("Instance overrides", func() {
+	cluster := &Cluster{
+		Spec: ClusterSpec{
+			InstanceOverrides: []InstanceOverride{
+				{
+					Name:       "cluster-example-3",
+					Parameters: map[string]string{"work_mem": "256MB"},
+				},
+			},
+		},
+	}
+
+	It("gets the parameters overridden for an instance", func() {
+		Expect(cluster.GetInstanceParameters("cluster-example-3")).To(HaveKeyWithValue("work_mem", "256MB"))
+	})
+
+	It("gets no parameters for the other instances", func() {
+		Expect(cluster.GetInstanceParameters("cluster-example-1")).To(BeNil())
+	})
+})
+
+var _ = Describe("Relaxed durability", func() {
+	It("doesn't report any condition with the strict durability", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsDurabilityRelaxed()).To(BeFalse())
+		Expect(cluster.GetRelaxedDurabilityCondition()).To(BeNil())
+	})
+
+	It("warns about the data loss risk with the relaxed durability", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Durability: DurabilityModeRelaxed,
+				},
+			},
+		}
+		Expect(cluster.IsDurabilityRelaxed()).To(BeTrue())
+		condition := cluster.GetRelaxedDurabilityCondition()
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(string(ConditionRelaxedDurability)))
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("WARNING"))
+	})
+})
+
+var _ = Describe("Top statements metrics", func() {
+	It("are disabled by default", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+				},
+			},
+		}
+		Expect(cluster.Spec.Monitoring.GetTopStatementsLimit()).To(BeZero())
+		Expect(cluster.GetExtensionsParameters()).To(Equal(map[string]string{"work_mem": "8MB"}))
+	})
+
+	It("report the default number of statements when enabled", func() {
+		monitoring := &MonitoringConfiguration{TopStatements: &TopStatementsConfiguration{Enabled: true}}
+		Expect(monitoring.GetTopStatementsLimit()).To(Equal(DefaultTopStatementsLimit))
+
+		monitoring.TopStatements.Limit = 25
+		Expect(monitoring.GetTopStatementsLimit()).To(Equal(25))
+	})
+
+	It("enable pg_stat_statements without changing the cluster parameters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+				},
+				Monitoring: &MonitoringConfiguration{
+					TopStatements: &TopStatementsConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.GetExtensionsParameters()).To(Equal(map[string]string{
+			"work_mem":                 "8MB",
+			"pg_stat_statements.track": "top",
+		}))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+	})
+
+	It("keep the pg_stat_statements configuration of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"pg_stat_statements.max": "5000"},
+				},
+				Monitoring: &MonitoringConfiguration{
+					TopStatements: &TopStatementsConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.GetExtensionsParameters()).To(Equal(map[string]string{"pg_stat_statements.max": "5000"}))
+	})
+})
+
+var _ = Describe("Maintenance window", func() {
+	// Saturdays at 2:00, for two hours
+	window := &MaintenanceWindowConfiguration{
+		Schedule: "0 0 2 * * 6",
+		Duration: 7200,
+	}
+	windowStart := time.Date(2023, time.December, 2, 2, 0, 0, 0, time.UTC)
+
+	It("detects when the window is in progress", func() {
+		inProgress, start, err := window.IsInProgress(windowStart.Add(90 * time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress).To(BeTrue())
+		Expect(start).To(Equal(windowStart))
+
+		inProgress, start, err = window.IsInProgress(windowStart)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress).To(BeTrue())
+		Expect(start).To(Equal(windowStart))
+	})
+
+	It("returns the start of the next window when outside it", func() {
+		inProgress, start, err := window.IsInProgress(windowStart.Add(-time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress).To(BeFalse())
+		Expect(start).To(Equal(windowStart))
+
+		inProgress, start, err = window.IsInProgress(windowStart.Add(2 * time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress).To(BeFalse())
+		Expect(start).To(Equal(windowStart.AddDate(0, 0, 7)))
+	})
+
+	It("evaluates the schedule in UTC", func() {
+		location := time.FixedZone("UTC+5", 5*60*60)
+		inProgress, _, err := window.IsInProgress(windowStart.Add(time.Hour).In(location))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress).To(BeTrue())
+	})
+
+	It("complains about invalid schedules", func() {
+		_, _, err := (&MaintenanceWindowConfiguration{Schedule: "wrong", Duration: 60}).IsInProgress(windowStart)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("doesn't defer the restarts without a maintenance window", func() {
+		cluster := Cluster{}
+		deferred, _, err := cluster.GetRestartDeferral(windowStart.Add(-time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferred).To(BeFalse())
+	})
+
+	It("defers the restarts outside the maintenance window", func() {
+		cluster := Cluster{Spec: ClusterSpec{MaintenanceWindow: window}}
+		deferred, start, err := cluster.GetRestartDeferral(windowStart.Add(-time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferred).To(BeTrue())
+		Expect(start).To(Equal(windowStart))
+
+		deferred, _, err = cluster.GetRestartDeferral(windowStart.Add(time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferred).To(BeFalse())
+	})
+
+	It("doesn't defer the restarts when the maintenance window is bypassed", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{utils.BypassMaintenanceWindowAnnotationName: "enabled"},
+			},
+			Spec: ClusterSpec{MaintenanceWindow: window},
+		}
+		deferred, _, err := cluster.GetRestartDeferral(windowStart.Add(-time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferred).To(BeFalse())
+	})
+})
+
+var _ = Describe("Image of the instances", func() {
+	const pinnedImage = "ghcr.io/cloudnative-pg/postgresql:16.1"
+
+	cordonedCluster := func() *Cluster {
+		return &Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.SkipOperatorUpgradeAnnotationName: "enabled",
+				},
+			},
+			Status: ClusterStatus{Image: pinnedImage},
+		}
+	}
+
+	It("uses the default image of the operator", func() {
+		cluster := &Cluster{Status: ClusterStatus{Image: pinnedImage}}
+		Expect(cluster.GetImageName()).To(Equal(configuration.Current.PostgresImageName))
+	})
+
+	It("keeps the image in use while the operator upgrades are skipped", func() {
+		Expect(cordonedCluster().GetImageName()).To(Equal(pinnedImage))
+	})
+
+	It("uses the default image when the image in use is not known", func() {
+		cluster := cordonedCluster()
+		cluster.Status.Image = ""
+		Expect(cluster.GetImageName()).To(Equal(configuration.Current.PostgresImageName))
+	})
+
+	It("uses the image requested by the user", func() {
+		cluster := cordonedCluster()
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.2"
+		Expect(cluster.GetImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:16.2"))
+	})
+})
+
+var _ = Describe("WAL segment size", func() {
+	It("is zero when not specified", func() {
+		Expect((&Cluster{}).GetWalSegmentSize()).To(BeZero())
+	})
+
+	It("is read from the initdb bootstrap section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{WalSegmentSize: 32},
+				},
+			},
+		}
+		Expect(cluster.GetWalSegmentSize()).To(Equal(32))
+	})
+
+	It("prefers the PostgreSQL configuration", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{WalSegmentSize: 64},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{WalSegmentSize: 32},
+				},
+			},
+		}
+		Expect(cluster.GetWalSegmentSize()).To(Equal(64))
+	})
+})
+
+var _ = Describe("Autovacuum settings", func() {
+	It("renders no parameters without the autovacuum settings", func() {
+		var autovacuum *AutovacuumConfiguration
+		Expect(autovacuum.GetParameters()).To(BeEmpty())
+		Expect((&AutovacuumConfiguration{}).GetParameters()).To(BeEmpty())
+	})
+
+	It("renders the parameters of the settings which have been set", func() {
+		autovacuum := &AutovacuumConfiguration{
+			MaxWorkers:      ptr.To(int32(6)),
+			VacuumCostLimit: ptr.To(int32(-1)),
+		}
+		Expect(autovacuum.GetParameters()).To(Equal(map[string]string{
+			"autovacuum_max_workers":       "6",
+			"autovacuum_vacuum_cost_limit": "-1",
+		}))
+
+		autovacuum.Naptime = "30s"
+		Expect(autovacuum.GetParameters()).To(HaveKeyWithValue("autovacuum_naptime", "30s"))
+	})
+})
+
+var _ = Describe("Application names of the replicas", func() {
+	newCluster := func(applicationName string) *Cluster {
+		return &Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ReplicaConnection: &ReplicaConnectionConfiguration{ApplicationName: applicationName},
+				},
+			},
+		}
+	}
+
+	It("uses the name of the pod by default", func() {
+		cluster := &Cluster{ObjectMeta: v1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.GetReplicaApplicationName("cluster-example-2")).To(Equal("cluster-example-2"))
+		Expect(newCluster("").GetReplicaApplicationName("cluster-example-2")).To(Equal("cluster-example-2"))
+
+		name, ok := cluster.GetInstanceNameFromApplicationName("cluster-example-2")
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("cluster-example-2"))
+	})
+
+	It("replaces the placeholders of the pattern", func() {
+		Expect(newCluster("dc1-{podName}").GetReplicaApplicationName("cluster-example-12")).
+			To(Equal("dc1-cluster-example-12"))
+		Expect(newCluster("pg.{serial}").GetReplicaApplicationName("cluster-example-12")).
+			To(Equal("pg.12"))
+		Expect(newCluster("{podName}_{serial}").GetReplicaApplicationName("cluster-example-12")).
+			To(Equal("cluster-example-12_12"))
+	})
+
+	DescribeTable("maps the application names back to the instances",
+		func(pattern, applicationName, expected string) {
+			cluster := newCluster(pattern)
+			name, ok := cluster.GetInstanceNameFromApplicationName(applicationName)
+			Expect(ok).To(Equal(expected != ""))
+			Expect(name).To(Equal(expected))
+			if ok {
+				Expect(cluster.GetReplicaApplicationName(name)).To(Equal(applicationName))
+			}
+		},
+		Entry("with the default pattern", "", "cluster-example-3", "cluster-example-3"),
+		Entry("with the name of the pod", "dc1-{podName}", "dc1-cluster-example-3", "cluster-example-3"),
+		Entry("with the serial", "pg.{serial}", "pg.3", "cluster-example-3"),
+		Entry("with both placeholders", "{podName}_{serial}", "cluster-example-3_3", "cluster-example-3"),
+		Entry("with inconsistent placeholders", "{podName}_{serial}", "cluster-example-3_4", ""),
+		Entry("with a client of another cluster", "", "other-cluster-3", ""),
+		Entry("with a basebackup", "", "cluster-example-3-join", ""),
+		Entry("with a name not following the pattern", "pg.{serial}", "pgx3", ""),
+	)
+
+	It("builds a regular expression matching the application names", func() {
+		Expect(newCluster("").Spec.PostgresConfiguration.ReplicaConnection.
+			GetApplicationNameRegex("cluster-example")).
+			To(Equal(`^cluster-example-([0-9]+)$`))
+		Expect(newCluster("pg.{serial}").Spec.PostgresConfiguration.ReplicaConnection.
+			GetApplicationNameRegex("cluster-example")).
+			To(Equal(`^pg\.([0-9]+)$`))
+	})
+})
+
+var _ = Describe("online backup configuration", func() {
+	It("keeps the backups in progress for one hour by default", func() {
+		Expect(OnlineConfiguration{}.GetHoldTimeoutSeconds()).To(BeEquivalentTo(3600))
+	})
+
+	It("uses the configured hold timeout", func() {
+		configuration := OnlineConfiguration{HoldTimeoutSeconds: ptr.To(int32(7200))}
+		Expect(configuration.GetHoldTimeoutSeconds()).To(BeEquivalentTo(7200))
+	})
+})
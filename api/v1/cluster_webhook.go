@@ -19,10 +19,14 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -263,11 +267,12 @@ var _ webhook.Validator = &Cluster{}
 func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
 	allErrs := r.Validate()
+	allWarnings := r.getAdmissionWarnings()
 	if len(allErrs) == 0 {
-		return nil, nil
+		return allWarnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(
+	return allWarnings, apierrors.NewInvalid(
 		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Cluster"},
 		r.Name, allErrs)
 }
@@ -277,6 +282,7 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 	type validationFunc func() field.ErrorList
 	validations := []validationFunc{
 		r.validateInitDB,
+		r.validateLocaleProvider,
 		r.validateRecoveryApplicationDatabase,
 		r.validatePgBaseBackupApplicationDatabase,
 		r.validateImport,
@@ -287,26 +293,54 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateImagePullPolicy,
 		r.validateRecoveryTarget,
 		r.validatePrimaryUpdateStrategy,
+		r.validateMaintenanceWindow,
 		r.validateMinSyncReplicas,
 		r.validateMaxSyncReplicas,
+		r.validateDiskPressureSwitchover,
+		r.validateProbes,
 		r.validateStorageSize,
 		r.validateWalStorageSize,
+		r.validateLogStorageSize,
 		r.validateName,
 		r.validateBootstrapPgBaseBackupSource,
 		r.validateBootstrapRecoverySource,
 		r.validateBootstrapRecoveryDataSource,
+		r.validateBootstrapRecoveryVolumeBackup,
+		r.validateBootstrapRecoveryBackupSelection,
+		r.validateBootstrapRecoveryWalRestore,
 		r.validateExternalClusters,
 		r.validateTolerations,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
+		r.validateAdditionalWalObjectStores,
+		r.validateVolumeBackup,
 		r.validateConfiguration,
+		r.validateInstanceOverrides,
+		r.validateTCPKeepalives,
+		r.validateReplicaConnection,
+		r.validateDurability,
+		r.validateDefaultTransactionIsolation,
+		r.validateReadOnlyReplicas,
+		r.validateTimeouts,
+		r.validateDateTimeSettings,
+		r.validateAutovacuum,
 		r.validateLDAP,
+		r.validatePgIdent,
 		r.validateReplicationSlots,
+		r.validateMaxSlotWALKeepSize,
+		r.validateWalSegmentSize,
 		r.validateEnv,
+		r.validateContainers,
 		r.validateManagedRoles,
+		r.validateManagedPublications,
+		r.validateManagedSubscriptions,
+		r.validateManagedTables,
+		r.validateManagedSQLJobs,
+		r.validateMaintenanceJobs,
 		r.validateManagedExtensions,
 		r.validateResources,
+		r.validatePreferredPrimaryZone,
 	}
 
 	for _, validate := range validations {
@@ -328,16 +362,106 @@ func (r *Cluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error)
 		r.Validate(),
 		r.ValidateChanges(oldCluster)...,
 	)
+	allWarnings := append(r.getAdmissionWarnings(), r.getAutovacuumRestartWarnings(oldCluster)...)
 
 	if len(allErrs) == 0 {
-		return nil, nil
+		return allWarnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(
+	return allWarnings, apierrors.NewInvalid(
 		schema.GroupKind{Group: "cluster.cnpg.io", Kind: "Cluster"},
 		r.Name, allErrs)
 }
 
+// getAdmissionWarnings groups the checks of the settings that are accepted,
+// but that are likely to be mistakes, returning a warning for each of them
+func (r *Cluster) getAdmissionWarnings() admission.Warnings {
+	result := append(r.getDisabledCollectorsWarnings(), r.getReplicationSlotsWarnings()...)
+	return append(result, r.getDurabilityWarnings()...)
+}
+
+// getDurabilityWarnings warns that, with the relaxed durability,
+// full_page_writes is off and pg_rewind can't be used: after a failover
+// the former primary can't rejoin the cluster and must be recreated
+func (r *Cluster) getDurabilityWarnings() admission.Warnings {
+	if !r.IsDurabilityRelaxed() || r.Spec.Instances < 2 {
+		return nil
+	}
+
+	return admission.Warnings{fmt.Sprintf(
+		"%s: the relaxed durability disables full_page_writes, which is required by pg_rewind: "+
+			"after a failover, the former primary will not be able to rejoin the cluster "+
+			"and will need to be recreated",
+		field.NewPath("spec", "postgresql", "durability"))}
+}
+
+// getReplicationSlotsDemand estimates the number of replication slots an
+// instance needs: one for each of the other instances when the HA slots are
+// enabled, one for the subscribers of each managed publication, and the
+// temporary one used by pg_basebackup when a new instance joins the cluster
+func (r *Cluster) getReplicationSlotsDemand() (physical, logical int) {
+	if r.Spec.ReplicationSlots == nil || r.Spec.ReplicationSlots.HighAvailability.GetEnabled() {
+		physical = max(r.Spec.Instances-1, 0)
+	}
+	physical++
+
+	if r.Spec.Managed != nil {
+		for _, publication := range r.Spec.Managed.Publications {
+			if publication.Ensure != EnsureAbsent {
+				logical++
+			}
+		}
+	}
+
+	return physical, logical
+}
+
+// getReplicationSlotsWarnings warns when the replication slots the cluster
+// is expected to use could exceed the limit set by max_replication_slots
+func (r *Cluster) getReplicationSlotsWarnings() admission.Warnings {
+	limit, ok := r.Spec.PostgresConfiguration.Parameters["max_replication_slots"]
+	if !ok {
+		limit = postgres.CnpgConfigurationSettings.GlobalDefaultSettings["max_replication_slots"]
+	}
+	maxSlots, err := strconv.Atoi(limit)
+	if err != nil {
+		// an invalid value is rejected by PostgreSQL, not by this check
+		return nil
+	}
+
+	physical, logical := r.getReplicationSlotsDemand()
+	if physical+logical <= maxSlots {
+		return nil
+	}
+
+	return admission.Warnings{fmt.Sprintf(
+		"%s: %d replication slots could be needed (%d physical, %d logical), "+
+			"exceeding max_replication_slots (%d)",
+		field.NewPath("spec", "postgresql", "parameters", "max_replication_slots"),
+		physical+logical, physical, logical, maxSlots)}
+}
+
+// getDisabledCollectorsWarnings warns about the disabled collectors that
+// are not built into the metrics exporter, which are ignored
+func (r *Cluster) getDisabledCollectorsWarnings() admission.Warnings {
+	if r.Spec.Monitoring == nil {
+		return nil
+	}
+
+	var result admission.Warnings
+	for i, name := range r.Spec.Monitoring.DisableDefaultCollectors {
+		if !slices.Contains(DefaultCollectors, name) {
+			result = append(result, fmt.Sprintf(
+				"%s: unknown collector %q will be ignored, the known ones are: %s",
+				field.NewPath("spec", "monitoring", "disableDefaultCollectors").Index(i),
+				name,
+				strings.Join(DefaultCollectors, ", ")))
+		}
+	}
+
+	return result
+}
+
 // ValidateChanges groups the validation logic for cluster changes checking the differences between
 // the previous version and the new one of the cluster, returning a list of all encountered errors
 func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
@@ -350,9 +474,14 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validateConfigurationChange(old)...)
 	allErrs = append(allErrs, r.validateStorageChange(old)...)
 	allErrs = append(allErrs, r.validateWalStorageChange(old)...)
+	allErrs = append(allErrs, r.validateLogStorageChange(old)...)
+	allErrs = append(allErrs, r.validateBackupVolumeChange(old)...)
 	allErrs = append(allErrs, r.validateReplicaModeChange(old)...)
 	allErrs = append(allErrs, r.validateUnixPermissionIdentifierChange(old)...)
 	allErrs = append(allErrs, r.validateReplicationSlotsChange(old)...)
+	allErrs = append(allErrs, r.validateWalSegmentSizeChange(old)...)
+	allErrs = append(allErrs, r.validateLocaleProviderChange(old)...)
+	allErrs = append(allErrs, r.validateReplicaApplicationNameChange(old)...)
 	return allErrs
 }
 
@@ -391,6 +520,56 @@ func (r *Cluster) validateLDAP() field.ErrorList {
 	return result
 }
 
+// reservedIdentMapNames are the user name maps that are managed by the operator
+// or that would be interpreted as directives by PostgreSQL
+var reservedIdentMapNames = []string{"local", "include", "include_if_exists", "include_dir"}
+
+// validatePgIdent validates the user name maps to be added to pg_ident.conf
+func (r *Cluster) validatePgIdent() field.ErrorList {
+	var result field.ErrorList
+
+	for i, entry := range r.Spec.PostgresConfiguration.PgIdent {
+		entryPath := field.NewPath("spec", "postgresql", "pgIdent").Index(i)
+
+		result = append(result, validateIdentToken(entryPath.Child("mapName"), entry.MapName)...)
+		result = append(result, validateIdentToken(entryPath.Child("systemUsername"), entry.SystemUsername)...)
+		result = append(result, validateIdentToken(entryPath.Child("pgUsername"), entry.PGUsername)...)
+
+		if slices.Contains(reservedIdentMapNames, entry.MapName) {
+			result = append(result, field.Invalid(
+				entryPath.Child("mapName"),
+				entry.MapName,
+				"this map name is reserved"))
+		}
+
+		if strings.Contains(entry.PGUsername, `\1`) && !strings.HasPrefix(entry.SystemUsername, "/") {
+			result = append(result, field.Invalid(
+				entryPath.Child("pgUsername"),
+				entry.PGUsername,
+				"\\1 can only be used when the system user name is a regular expression"))
+		}
+	}
+
+	return result
+}
+
+// validateIdentToken checks that a value can be used as a field
+// of a pg_ident.conf entry
+func validateIdentToken(path *field.Path, value string) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(path, "cannot be empty")}
+	}
+
+	if strings.ContainsAny(value, " \t\r\n\",#") {
+		return field.ErrorList{field.Invalid(
+			path,
+			value,
+			"cannot contain whitespaces, commas, quotes or comment characters")}
+	}
+
+	return nil
+}
+
 // validateEnv validate the environment variables settings proposed by the user
 func (r *Cluster) validateEnv() field.ErrorList {
 	var result field.ErrorList
@@ -409,6 +588,53 @@ func (r *Cluster) validateEnv() field.ErrorList {
 	return result
 }
 
+// reservedContainerNames are the names of the containers created by the
+// operator in the instance pods
+var reservedContainerNames = []string{"postgres", "bootstrap-controller"}
+
+// readOnlyVolumeNames are the names of the volumes managed by the operator
+// that the user containers can only mount in read-only mode, as they hold
+// the data, the WAL files, the logs and the backups of the instance
+var readOnlyVolumeNames = []string{"pgdata", "pg-wal", "pg-log", "scratch-data", "backup"}
+
+// validateContainers validates the additional containers and init
+// containers proposed by the user
+func (r *Cluster) validateContainers() field.ErrorList {
+	var result field.ErrorList
+
+	names := make(map[string]bool)
+	validate := func(path *field.Path, containers []v1.Container) {
+		for i := range containers {
+			container := &containers[i]
+			containerPath := path.Index(i)
+			switch {
+			case slices.Contains(reservedContainerNames, container.Name):
+				result = append(result, field.Invalid(
+					containerPath.Child("name"),
+					container.Name,
+					"the usage of this container name is reserved for the operator"))
+			case names[container.Name]:
+				result = append(result, field.Duplicate(containerPath.Child("name"), container.Name))
+			}
+			names[container.Name] = true
+
+			for j, mount := range container.VolumeMounts {
+				if slices.Contains(readOnlyVolumeNames, mount.Name) && !mount.ReadOnly {
+					result = append(result, field.Invalid(
+						containerPath.Child("volumeMounts").Index(j).Child("readOnly"),
+						mount.ReadOnly,
+						fmt.Sprintf("the %s volume can only be mounted in read-only mode", mount.Name)))
+				}
+			}
+		}
+	}
+
+	validate(field.NewPath("spec", "initContainers"), r.Spec.InitContainers)
+	validate(field.NewPath("spec", "containers"), r.Spec.Containers)
+
+	return result
+}
+
 // isReservedEnvironmentVariable detects if a certain environment variable
 // is reserved for the usage of the operator
 func isReservedEnvironmentVariable(name string) bool {
@@ -487,6 +713,90 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 	return result
 }
 
+// validateLocaleProvider checks the collation provider options to be
+// passed to initdb
+func (r *Cluster) validateLocaleProvider() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
+	initDB := r.Spec.Bootstrap.InitDB
+	path := field.NewPath("spec", "bootstrap", "initdb")
+
+	var result field.ErrorList
+	if initDB.LocaleProvider == LocaleProviderICU {
+		if initDB.ICULocale == "" {
+			result = append(result, field.Required(
+				path.Child("icuLocale"),
+				"the ICU locale is required by the icu locale provider"))
+		}
+	} else {
+		if initDB.ICULocale != "" {
+			result = append(result, field.Invalid(
+				path.Child("icuLocale"),
+				initDB.ICULocale,
+				"the ICU locale can be set only with the icu locale provider"))
+		}
+		if initDB.ICURules != "" {
+			result = append(result, field.Invalid(
+				path.Child("icuRules"),
+				initDB.ICURules,
+				"the ICU rules can be set only with the icu locale provider"))
+		}
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+
+	if initDB.LocaleProvider != "" && psqlVersion < 150000 {
+		result = append(result, field.Invalid(
+			path.Child("localeProvider"),
+			initDB.LocaleProvider,
+			"the locale provider can be chosen only with PostgreSQL 15 or above"))
+	}
+
+	if initDB.ICURules != "" && psqlVersion < 160000 {
+		result = append(result, field.Invalid(
+			path.Child("icuRules"),
+			initDB.ICURules,
+			"the ICU rules can be set only with PostgreSQL 16 or above"))
+	}
+
+	return result
+}
+
+// validateLocaleProviderChange rejects the changes to the collation
+// provider options, which are applied by initdb when the cluster is created
+func (r *Cluster) validateLocaleProviderChange(old *Cluster) field.ErrorList {
+	var newInitDB, oldInitDB BootstrapInitDB
+	if r.Spec.Bootstrap != nil && r.Spec.Bootstrap.InitDB != nil {
+		newInitDB = *r.Spec.Bootstrap.InitDB
+	}
+	if old.Spec.Bootstrap != nil && old.Spec.Bootstrap.InitDB != nil {
+		oldInitDB = *old.Spec.Bootstrap.InitDB
+	}
+
+	path := field.NewPath("spec", "bootstrap", "initdb")
+	const message = "cannot be changed, as it is applied by initdb when the cluster is created"
+
+	var result field.ErrorList
+	if newInitDB.LocaleProvider != oldInitDB.LocaleProvider {
+		result = append(result, field.Invalid(path.Child("localeProvider"), newInitDB.LocaleProvider, message))
+	}
+	if newInitDB.ICULocale != oldInitDB.ICULocale {
+		result = append(result, field.Invalid(path.Child("icuLocale"), newInitDB.ICULocale, message))
+	}
+	if newInitDB.ICURules != oldInitDB.ICURules {
+		result = append(result, field.Invalid(path.Child("icuRules"), newInitDB.ICURules, message))
+	}
+
+	return result
+}
+
 func (r *Cluster) validateImport() field.ErrorList {
 	// If it's not configured, everything is ok
 	if r.Spec.Bootstrap == nil {
@@ -808,6 +1118,114 @@ func (r *Cluster) validateBootstrapRecoverySource() field.ErrorList {
 	return result
 }
 
+// validateBootstrapRecoveryBackupSelection is used to ensure that the
+// backup to recover from is chosen in a single way, among the ones
+// available in the object store of the source
+func (r *Cluster) validateBootstrapRecoveryBackupSelection() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil {
+		return nil
+	}
+
+	recovery := r.Spec.Bootstrap.Recovery
+	if recovery.BackupID == "" && recovery.SkipLatest == 0 {
+		return nil
+	}
+
+	recoveryPath := field.NewPath("spec", "bootstrap", "recovery")
+	var result field.ErrorList
+	if recovery.SkipLatest < 0 {
+		result = append(result, field.Invalid(
+			recoveryPath.Child("skipLatest"),
+			recovery.SkipLatest,
+			"the number of backups to skip can't be negative"))
+	}
+
+	if recovery.BackupID != "" && recovery.SkipLatest != 0 {
+		result = append(result, field.Invalid(
+			recoveryPath.Child("skipLatest"),
+			recovery.SkipLatest,
+			"cannot be specified together with backupID"))
+	}
+
+	if recovery.RecoveryTarget != nil && recovery.RecoveryTarget.BackupID != "" {
+		result = append(result, field.Invalid(
+			recoveryPath.Child("recoveryTarget", "backupID"),
+			recovery.RecoveryTarget.BackupID,
+			"cannot be specified together with backupID or skipLatest in the recovery section"))
+	}
+
+	if recovery.Source == "" && recovery.VolumeBackup == nil {
+		result = append(result, field.Required(
+			recoveryPath.Child("source"),
+			"the backup to recover from can be chosen only when recovering from the object store "+
+				"of an external cluster or from a backup volume"))
+	}
+
+	return result
+}
+
+// validateBootstrapRecoveryVolumeBackup is used to ensure that the
+// volume dedicated to the backups of another cluster is the only
+// source of the recovery
+func (r *Cluster) validateBootstrapRecoveryVolumeBackup() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil || r.Spec.Bootstrap.Recovery.VolumeBackup == nil {
+		return nil
+	}
+
+	recoveryPath := field.NewPath("spec", "bootstrap", "recovery")
+	recovery := r.Spec.Bootstrap.Recovery
+
+	var result field.ErrorList
+	if recovery.Backup != nil || recovery.Source != "" || recovery.VolumeSnapshots != nil {
+		result = append(result, field.Invalid(
+			recoveryPath.Child("volumeBackup"),
+			recovery.VolumeBackup,
+			"Recovery from a backup volume is not compatible with other types of recovery"))
+	}
+
+	claimName := recovery.VolumeBackup.ClaimName
+	switch {
+	case claimName == "":
+		result = append(result, field.Required(
+			recoveryPath.Child("volumeBackup", "claimName"),
+			"the name of the backup volume is required"))
+	case r.ShouldCreateBackupVolume() && claimName == r.GetBackupVolumeName():
+		result = append(result, field.Invalid(
+			recoveryPath.Child("volumeBackup", "claimName"),
+			claimName,
+			"cannot recover from the volume that will store the backups of this cluster"))
+	}
+
+	if recovery.WalRestoreMaxBandwidth != nil {
+		result = append(result, field.Invalid(
+			recoveryPath.Child("walRestoreMaxBandwidth"),
+			*recovery.WalRestoreMaxBandwidth,
+			"the WAL files are not downloaded when recovering from a backup volume"))
+	}
+
+	return result
+}
+
+// validateBootstrapRecoveryWalRestore validates the throttling of the
+// downloads of the WAL files during the recovery
+func (r *Cluster) validateBootstrapRecoveryWalRestore() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil ||
+		r.Spec.Bootstrap.Recovery.WalRestoreMaxBandwidth == nil {
+		return nil
+	}
+
+	if maxBandwidth := *r.Spec.Bootstrap.Recovery.WalRestoreMaxBandwidth; maxBandwidth <= 0 {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "walRestoreMaxBandwidth"),
+				maxBandwidth,
+				"must be a positive number of bytes per second"),
+		}
+	}
+
+	return nil
+}
+
 // validateBootstrapRecoveryDataSource is used to ensure that the data
 // source is correctly defined
 func (r *Cluster) validateBootstrapRecoveryDataSource() field.ErrorList {
@@ -895,6 +1313,15 @@ func (r *Cluster) validateImageName() field.ErrorList {
 		return result
 	}
 
+	if err := utils.ValidateImageName(r.Spec.ImageName); err != nil {
+		return append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "imageName"),
+				r.Spec.ImageName,
+				err.Error()))
+	}
+
 	tag := utils.GetImageTag(r.Spec.ImageName)
 	switch tag {
 	case "latest":
@@ -1307,14 +1734,14 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 		recoveryTarget.TargetXID != "" ||
 		recoveryTarget.TargetImmediate != nil
 	recoveryFromSnapshot := r.Spec.Bootstrap.Recovery.VolumeSnapshots != nil
-	if labelBasedPITR && !recoveryFromSnapshot && recoveryTarget.BackupID == "" {
+	if labelBasedPITR && !recoveryFromSnapshot && r.Spec.Bootstrap.Recovery.GetBackupID() == "" {
 		result = append(result, field.Required(
 			field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget"),
 			"BackupID is missing"))
 	}
 
 	switch recoveryTarget.TargetTLI {
-	case "", "latest":
+	case "", "latest", "current":
 		// Allowed non-numeric values
 	default:
 		// Everything else must be a valid positive integer
@@ -1322,7 +1749,7 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 			result = append(result, field.Invalid(
 				field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget", "targetTLI"),
 				recoveryTarget,
-				"recovery target timeline can be set to 'latest' or a positive integer"))
+				"recovery target timeline can be set to 'latest', 'current' or a positive integer"))
 		}
 	}
 
@@ -1387,6 +1814,24 @@ func (r *Cluster) validatePrimaryUpdateStrategy() field.ErrorList {
 	return nil
 }
 
+// validateMaintenanceWindow checks the schedule of the maintenance window
+func (r *Cluster) validateMaintenanceWindow() field.ErrorList {
+	if r.Spec.MaintenanceWindow == nil {
+		return nil
+	}
+
+	if _, err := cron.Parse(r.Spec.MaintenanceWindow.Schedule); err != nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "maintenanceWindow", "schedule"),
+				r.Spec.MaintenanceWindow.Schedule,
+				fmt.Sprintf("invalid schedule: %v", err)),
+		}
+	}
+
+	return nil
+}
+
 // Validate the maximum number of synchronous instances
 // that should be kept in sync with the primary server
 func (r *Cluster) validateMaxSyncReplicas() field.ErrorList {
@@ -1409,91 +1854,732 @@ func (r *Cluster) validateMaxSyncReplicas() field.ErrorList {
 	return result
 }
 
-// Validate the minimum number of synchronous instances
-func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
+// validateDiskPressureSwitchover validates the watermarks of the
+// disk pressure switchover
+func (r *Cluster) validateDiskPressureSwitchover() field.ErrorList {
 	var result field.ErrorList
 
-	if r.Spec.MinSyncReplicas < 0 {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "minSyncReplicas"),
-			r.Spec.MinSyncReplicas,
-			"minSyncReplicas must be a non negative integer"))
+	configuration := r.Spec.DiskPressureSwitchover
+	if configuration == nil {
+		return result
 	}
 
-	if r.Spec.MinSyncReplicas > r.Spec.MaxSyncReplicas {
+	if configuration.GetLowWatermark() >= configuration.GetHighWatermark() {
 		result = append(result, field.Invalid(
-			field.NewPath("spec", "minSyncReplicas"),
-			r.Spec.MinSyncReplicas,
-			"minSyncReplicas cannot be greater than maxSyncReplicas"))
+			field.NewPath("spec", "diskPressureSwitchover", "lowWatermark"),
+			configuration.GetLowWatermark(),
+			"lowWatermark must be lower than highWatermark"))
 	}
 
 	return result
 }
 
-func (r *Cluster) validateStorageSize() field.ErrorList {
-	return validateStorageConfigurationSize("Storage", r.Spec.StorageConfiguration)
+// These are the default timings of the liveness and readiness probes,
+// and they must be kept in sync with the ones used in the specs package
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeFailureThreshold = 3
+)
+
+// getFailureWindow gets the number of seconds the probe needs to fail
+// before the container is considered failed
+func (timings *ProbeTimings) getFailureWindow() int32 {
+	periodSeconds := int32(defaultProbePeriodSeconds)
+	failureThreshold := int32(defaultProbeFailureThreshold)
+	if timings != nil && timings.PeriodSeconds != nil {
+		periodSeconds = *timings.PeriodSeconds
+	}
+	if timings != nil && timings.FailureThreshold != nil {
+		failureThreshold = *timings.FailureThreshold
+	}
+
+	return periodSeconds * failureThreshold
 }
 
-func (r *Cluster) validateWalStorageSize() field.ErrorList {
+// validateProbes validates the probes configuration. The readiness
+// probe must not take longer than the liveness probe to detect a failure,
+// otherwise an instance would be restarted while it is still receiving
+// traffic
+func (r *Cluster) validateProbes() field.ErrorList {
 	var result field.ErrorList
 
-	if r.ShouldCreateWalArchiveVolume() {
-		result = append(result, validateStorageConfigurationSize("walStorage", *r.Spec.WalStorage)...)
+	if r.Spec.Probes == nil {
+		return result
+	}
+
+	readinessWindow := r.Spec.Probes.GetReadiness().getFailureWindow()
+	livenessWindow := r.Spec.Probes.GetLiveness().getFailureWindow()
+	if readinessWindow > livenessWindow {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "probes", "readiness"),
+			readinessWindow,
+			fmt.Sprintf(
+				"the readiness probe takes %d seconds to detect a failure, "+
+					"more than the %d seconds taken by the liveness probe: "+
+					"increase the liveness periodSeconds or failureThreshold",
+				readinessWindow, livenessWindow)))
 	}
 
 	return result
 }
 
-func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
+// validateInstanceOverrides validates the PostgreSQL parameters
+// overridden for single instances
+func (r *Cluster) validateInstanceOverrides() field.ErrorList {
 	var result field.ErrorList
 
-	if storageConfiguration.Size != "" {
-		if _, err := resource.ParseQuantity(storageConfiguration.Size); err != nil {
-			result = append(result, field.Invalid(
-				field.NewPath("spec", structPath, "size"),
-				storageConfiguration.Size,
-				"Size value isn't valid"))
+	names := make(map[string]bool, len(r.Spec.InstanceOverrides))
+	for idx, override := range r.Spec.InstanceOverrides {
+		path := field.NewPath("spec", "instanceOverrides").Index(idx)
+
+		if override.Name == "" {
+			result = append(result, field.Required(path.Child("name"), "the name of the instance is required"))
+		} else if names[override.Name] {
+			result = append(result, field.Duplicate(path.Child("name"), override.Name))
 		}
-	}
+		names[override.Name] = true
 
-	if storageConfiguration.Size == "" &&
-		(storageConfiguration.PersistentVolumeClaimTemplate == nil ||
-			storageConfiguration.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero()) {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", structPath, "size"),
-			storageConfiguration.Size,
-			"Size not configured. Please add it, or a storage request in the pvcTemplate."))
+		for key, value := range override.Parameters {
+			_, isFixed := postgres.FixedConfigurationParameters[key]
+			switch {
+			case isFixed:
+				result = append(result, field.Invalid(
+					path.Child("parameters", key),
+					value,
+					"Can't set fixed configuration parameter"))
+			case postgres.InstanceProtectedParameters[key]:
+				result = append(result, field.Invalid(
+					path.Child("parameters", key),
+					value,
+					"This parameter must have the same value on every instance, and can't be overridden"))
+			}
+		}
 	}
 
 	return result
 }
 
-// Validate a change in the storage
-func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
-	return validateStorageConfigurationChange(
-		"storage",
-		old.Spec.StorageConfiguration,
-		r.Spec.StorageConfiguration,
+// validateTCPKeepalives validates the TCP keepalive settings of the cluster
+func (r *Cluster) validateTCPKeepalives() field.ErrorList {
+	return validateTCPKeepalivesConfiguration(
+		field.NewPath("spec", "postgresql", "tcpKeepalives"),
+		r.Spec.PostgresConfiguration.TCPKeepalives,
+		r.Spec.PostgresConfiguration.Parameters,
+		[3]string{"tcp_keepalives_idle", "tcp_keepalives_interval", "tcp_keepalives_count"},
 	)
 }
 
-func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
-	if old.Spec.WalStorage == nil {
-		return nil
+// validateTCPKeepalivesConfiguration checks that the TCP keepalive settings
+// are in a sane range, and that they are not configured twice, here and in
+// the corresponding idle, interval and count parameters
+func validateTCPKeepalivesConfiguration(
+	path *field.Path,
+	keepalives *TCPKeepalivesConfiguration,
+	parameters map[string]string,
+	parameterNames [3]string,
+) field.ErrorList {
+	var result field.ErrorList
+	if keepalives == nil {
+		return result
 	}
 
-	if old.Spec.WalStorage != nil && r.Spec.WalStorage == nil {
-		return field.ErrorList{
-			field.Invalid(
-				field.NewPath("spec", "walStorage"),
-				r.Spec.WalStorage,
-				"walStorage cannot be disabled once the cluster is created"),
-		}
+	settings := []struct {
+		name      string
+		value     *int32
+		maximum   int32
+		parameter string
+	}{
+		{name: "idle", value: keepalives.Idle, maximum: 32767, parameter: parameterNames[0]},
+		{name: "interval", value: keepalives.Interval, maximum: 32767, parameter: parameterNames[1]},
+		{name: "count", value: keepalives.Count, maximum: 127, parameter: parameterNames[2]},
+	}
+	for _, setting := range settings {
+		if setting.value == nil {
+			continue
+		}
+
+		if *setting.value < 1 || *setting.value > setting.maximum {
+			result = append(result, field.Invalid(
+				path.Child(setting.name),
+				*setting.value,
+				fmt.Sprintf("must be between 1 and %d", setting.maximum)))
+		}
+
+		if _, ok := parameters[setting.parameter]; ok {
+			result = append(result, field.Invalid(
+				path.Child(setting.name),
+				*setting.value,
+				fmt.Sprintf("cannot be specified together with the %s parameter", setting.parameter)))
+		}
+	}
+
+	return result
+}
+
+// validateReplicaConnection checks that the settings of the connections of
+// the replicas to the primary are in a sane range, and that the retry interval
+// is not configured in the wal_retrieve_retry_interval parameter too
+func (r *Cluster) validateReplicaConnection() field.ErrorList {
+	var result field.ErrorList
+	replicaConnection := r.Spec.PostgresConfiguration.ReplicaConnection
+	if replicaConnection == nil {
+		return result
+	}
+
+	path := field.NewPath("spec", "postgresql", "replicaConnection")
+	if retryInterval := replicaConnection.RetryInterval; retryInterval != nil {
+		if *retryInterval < 100 || *retryInterval > 300000 {
+			result = append(result, field.Invalid(
+				path.Child("retryInterval"),
+				*retryInterval,
+				"must be between 100 and 300000 milliseconds"))
+		}
+
+		if _, ok := r.Spec.PostgresConfiguration.Parameters["wal_retrieve_retry_interval"]; ok {
+			result = append(result, field.Invalid(
+				path.Child("retryInterval"),
+				*retryInterval,
+				"cannot be specified together with the wal_retrieve_retry_interval parameter"))
+		}
+	}
+
+	if connectTimeout := replicaConnection.ConnectTimeout; connectTimeout != nil &&
+		(*connectTimeout < 2 || *connectTimeout > 300) {
+		result = append(result, field.Invalid(
+			path.Child("connectTimeout"),
+			*connectTimeout,
+			"must be between 2 and 300 seconds"))
+	}
+
+	if replicaConnection.ApplicationName != "" {
+		result = append(result, r.validateReplicaApplicationName(path.Child("applicationName"))...)
+	}
+
+	return result
+}
+
+const (
+	// maxApplicationNameLength is the length after which PostgreSQL
+	// truncates the application names (NAMEDATALEN - 1)
+	maxApplicationNameLength = 63
+
+	// maxSerialLength is the number of digits of the serial numbers of
+	// the instances accounted for when checking the length of the
+	// application names
+	maxSerialLength = 5
+)
+
+// validateReplicaApplicationNameChange checks that the application name
+// pattern of the replicas is not changed, as the replication slots, the
+// synchronous replication and the monitoring of the replicas rely on the
+// names they registered with
+func (r *Cluster) validateReplicaApplicationNameChange(old *Cluster) field.ErrorList {
+	pattern := r.Spec.PostgresConfiguration.ReplicaConnection.GetApplicationNamePattern()
+	if pattern == old.Spec.PostgresConfiguration.ReplicaConnection.GetApplicationNamePattern() {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "postgresql", "replicaConnection", "applicationName"),
+			pattern,
+			"the application name of the replicas cannot be changed"),
+	}
+}
+
+// validateReplicaApplicationName checks that the application name pattern
+// of the replicas gives a different name to every instance, and that
+// PostgreSQL doesn't need to truncate it
+func (r *Cluster) validateReplicaApplicationName(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+	pattern := r.Spec.PostgresConfiguration.ReplicaConnection.ApplicationName
+
+	if !strings.Contains(pattern, ReplicaApplicationNamePodName) &&
+		!strings.Contains(pattern, ReplicaApplicationNameSerial) {
+		result = append(result, field.Invalid(
+			path,
+			pattern,
+			fmt.Sprintf("must contain %s or %s, to identify the instances",
+				ReplicaApplicationNamePodName, ReplicaApplicationNameSerial)))
+	}
+
+	remainder := strings.NewReplacer(
+		ReplicaApplicationNamePodName, "",
+		ReplicaApplicationNameSerial, "",
+	).Replace(pattern)
+	if strings.ContainsAny(remainder, "{}") {
+		result = append(result, field.Invalid(
+			path,
+			pattern,
+			fmt.Sprintf("the only supported placeholders are %s and %s",
+				ReplicaApplicationNamePodName, ReplicaApplicationNameSerial)))
+	}
+
+	longestName := r.GetReplicaApplicationName(
+		fmt.Sprintf("%s-%s", r.Name, strings.Repeat("9", maxSerialLength)))
+	if len(longestName) > maxApplicationNameLength {
+		result = append(result, field.Invalid(
+			path,
+			pattern,
+			fmt.Sprintf("the application names of the instances must not be longer than %d characters",
+				maxApplicationNameLength)))
+	}
+
+	return result
+}
+
+// validateDurability checks that the relaxed durability is not used on
+// clusters with backups, and that the parameters it sets are not
+// configured by the user too
+func (r *Cluster) validateDurability() field.ErrorList {
+	var result field.ErrorList
+	if !r.IsDurabilityRelaxed() {
+		return result
+	}
+
+	path := field.NewPath("spec", "postgresql", "durability")
+	if r.Spec.Backup != nil && (r.Spec.Backup.BarmanObjectStore != nil || r.Spec.Backup.VolumeSnapshot != nil) {
+		result = append(result, field.Invalid(
+			path,
+			r.Spec.PostgresConfiguration.Durability,
+			"the relaxed durability can't be used on clusters with backups configured, "+
+				"as the backed up data could be corrupted"))
+	}
+
+	parameters := make([]string, 0, len(postgres.RelaxedDurabilitySettings))
+	for parameter := range postgres.RelaxedDurabilitySettings {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+	for _, parameter := range parameters {
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[parameter]; ok {
+			result = append(result, field.Invalid(
+				path,
+				r.Spec.PostgresConfiguration.Durability,
+				fmt.Sprintf("the relaxed durability cannot be specified together with the %s parameter", parameter)))
+		}
+	}
+
+	return result
+}
+
+// validateDefaultTransactionIsolation checks that the default isolation
+// level of the transactions is a valid one, and that it is not set by
+// the user in the parameters too
+func (r *Cluster) validateDefaultTransactionIsolation() field.ErrorList {
+	level := r.Spec.PostgresConfiguration.DefaultTransactionIsolation
+	if level == "" {
+		return nil
+	}
+
+	path := field.NewPath("spec", "postgresql", "defaultTransactionIsolation")
+	var result field.ErrorList
+	if !level.IsValid() {
+		result = append(result, field.NotSupported(
+			path,
+			level,
+			[]string{
+				string(TransactionIsolationReadUncommitted),
+				string(TransactionIsolationReadCommitted),
+				string(TransactionIsolationRepeatableRead),
+				string(TransactionIsolationSerializable),
+			}))
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["default_transaction_isolation"]; ok {
+		result = append(result, field.Invalid(
+			path,
+			level,
+			"cannot be specified together with the default_transaction_isolation parameter"))
+	}
+
+	return result
+}
+
+// validateReadOnlyReplicas prevents the enforcement of the read-only
+// replicas from clashing with the default_transaction_read_only parameter
+func (r *Cluster) validateReadOnlyReplicas() field.ErrorList {
+	if !r.Spec.PostgresConfiguration.ReadOnlyReplicas {
+		return nil
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["default_transaction_read_only"]; ok {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "postgresql", "readOnlyReplicas"),
+				r.Spec.PostgresConfiguration.ReadOnlyReplicas,
+				"cannot be enabled together with the default_transaction_read_only parameter"),
+		}
+	}
+
+	return nil
+}
+
+// validatePreferredPrimaryZone checks that the preferred primary zone
+// is a valid label value, as it is matched against the node labels
+func (r *Cluster) validatePreferredPrimaryZone() field.ErrorList {
+	zone := r.Spec.Topology.GetPreferredPrimaryZone()
+	if zone == "" {
+		return nil
+	}
+
+	if errs := validationutil.IsValidLabelValue(zone); len(errs) > 0 {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "topology", "preferredPrimaryZone"),
+				zone,
+				strings.Join(errs, ";")),
+		}
+	}
+
+	return nil
+}
+
+// timeoutRegex matches the PostgreSQL timeouts, that are integers
+// optionally followed by a time unit
+var timeoutRegex = regexp.MustCompile(`^[0-9]+(us|ms|s|min|h|d)?$`)
+
+// validateTimeouts checks the format of the statement and lock timeouts,
+// that the cluster-wide ones are not set in the parameters too, and that
+// every role is overridden only once
+func (r *Cluster) validateTimeouts() field.ErrorList {
+	timeouts := r.Spec.PostgresConfiguration.Timeouts
+	if timeouts == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "postgresql", "timeouts")
+	var result field.ErrorList
+	validateTimeout := func(path *field.Path, value string) {
+		if value != "" && !timeoutRegex.MatchString(value) {
+			result = append(result, field.Invalid(
+				path,
+				value,
+				"must be an integer optionally followed by one of the us, ms, s, min, h and d units"))
+		}
+	}
+
+	for _, timeout := range []struct {
+		name      string
+		parameter string
+		value     string
+	}{
+		{name: "statementTimeout", parameter: "statement_timeout", value: timeouts.StatementTimeout},
+		{name: "lockTimeout", parameter: "lock_timeout", value: timeouts.LockTimeout},
+	} {
+		validateTimeout(path.Child(timeout.name), timeout.value)
+
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[timeout.parameter]; ok && timeout.value != "" {
+			result = append(result, field.Invalid(
+				path.Child(timeout.name),
+				timeout.value,
+				fmt.Sprintf("cannot be specified together with the %s parameter", timeout.parameter)))
+		}
+	}
+
+	seenRoles := stringset.New()
+	for idx, role := range timeouts.Roles {
+		rolePath := path.Child("roles").Index(idx)
+		if role.Name == "" {
+			result = append(result, field.Required(rolePath.Child("name"), "the name of the role is required"))
+		} else if seenRoles.Has(role.Name) {
+			result = append(result, field.Duplicate(rolePath.Child("name"), role.Name))
+		}
+		seenRoles.Put(role.Name)
+
+		validateTimeout(rolePath.Child("statementTimeout"), role.StatementTimeout)
+		validateTimeout(rolePath.Child("lockTimeout"), role.LockTimeout)
+	}
+
+	return result
+}
+
+// dateStyleOutputFormats and dateStyleOrders are the components accepted
+// by PostgreSQL in the DateStyle parameter, compared case-insensitively
+var (
+	dateStyleOutputFormats = []string{"iso", "postgres", "sql", "german"}
+	dateStyleOrders        = []string{"dmy", "mdy", "ymd", "euro", "european", "us", "noneuro", "noneuropean"}
+)
+
+// validateDateTimeSettings checks that the time zones are known ones, that
+// the date style is made of an output format and an order of the fields,
+// and that none of them is set in the parameters too
+func (r *Cluster) validateDateTimeSettings() field.ErrorList {
+	path := field.NewPath("spec", "postgresql")
+	postgresConfig := r.Spec.PostgresConfiguration
+
+	var result field.ErrorList
+	for _, setting := range []struct {
+		name      string
+		parameter string
+		value     string
+		validate  func(string) string
+	}{
+		{name: "timezone", parameter: "timezone", value: postgresConfig.Timezone, validate: validateTimezone},
+		{name: "logTimezone", parameter: "log_timezone", value: postgresConfig.LogTimezone, validate: validateTimezone},
+		{name: "dateStyle", parameter: "datestyle", value: postgresConfig.DateStyle, validate: validateDateStyle},
+	} {
+		if setting.value == "" {
+			continue
+		}
+
+		if message := setting.validate(setting.value); message != "" {
+			result = append(result, field.Invalid(path.Child(setting.name), setting.value, message))
+		}
+
+		// the names of the parameters are case-insensitive
+		for parameter := range postgresConfig.Parameters {
+			if strings.EqualFold(parameter, setting.parameter) {
+				result = append(result, field.Invalid(
+					path.Child(setting.name),
+					setting.value,
+					fmt.Sprintf("cannot be specified together with the %s parameter", parameter)))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateTimezone returns why the passed name is not a time zone of the
+// IANA database, or an empty string if it is
+func validateTimezone(name string) string {
+	// "Local" is the time zone of the operator for the Go runtime
+	if name == "Local" {
+		return "must be a name of the IANA time zone database"
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Sprintf("must be a name of the IANA time zone database: %v", err)
+	}
+	return ""
+}
+
+// validateDateStyle returns why the passed value is not a valid DateStyle,
+// or an empty string if it is
+func validateDateStyle(value string) string {
+	var outputFormats, orders int
+	for _, component := range strings.Split(value, ",") {
+		component = strings.TrimSpace(component)
+		switch {
+		case slices.Contains(dateStyleOutputFormats, strings.ToLower(component)):
+			outputFormats++
+		case slices.Contains(dateStyleOrders, strings.ToLower(component)):
+			orders++
+		default:
+			return fmt.Sprintf("unknown component %q, the accepted ones are ISO, Postgres, SQL "+
+				"and German for the output format, and DMY, MDY and YMD for the order of the fields",
+				component)
+		}
+	}
+
+	if outputFormats > 1 || orders > 1 {
+		return "must contain at most one output format and one order of the fields"
+	}
+	return ""
+}
+
+// autovacuumParameters maps the fields of the autovacuum settings to the
+// parameters they are rendered in
+var autovacuumParameters = []struct {
+	field     string
+	parameter string
+}{
+	{field: "maxWorkers", parameter: "autovacuum_max_workers"},
+	{field: "vacuumCostLimit", parameter: "autovacuum_vacuum_cost_limit"},
+	{field: "naptime", parameter: "autovacuum_naptime"},
+}
+
+// naptimeRegex matches the PostgreSQL durations, that are integers
+// optionally followed by a time unit
+var naptimeRegex = regexp.MustCompile(`^([0-9]+)(ms|s|min|h|d)?$`)
+
+// naptimeUnits contains the number of milliseconds of every time unit,
+// a value without unit being in seconds
+var naptimeUnits = map[string]int64{
+	"ms":  1,
+	"":    1000,
+	"s":   1000,
+	"min": 60 * 1000,
+	"h":   60 * 60 * 1000,
+	"d":   24 * 60 * 60 * 1000,
+}
+
+// validateAutovacuum checks that the naptime is within the range accepted
+// by PostgreSQL, and that the autovacuum settings are not set in the
+// parameters too
+func (r *Cluster) validateAutovacuum() field.ErrorList {
+	autovacuum := r.Spec.PostgresConfiguration.Autovacuum
+	if autovacuum == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "postgresql", "autovacuum")
+	var result field.ErrorList
+	if autovacuum.Naptime != "" {
+		const minNaptime, maxNaptime = 1000, 2147483 * 1000
+		matches := naptimeRegex.FindStringSubmatch(autovacuum.Naptime)
+		var milliseconds int64
+		if matches != nil {
+			value, err := strconv.ParseInt(matches[1], 10, 64)
+			if err == nil && value <= maxNaptime {
+				milliseconds = value * naptimeUnits[matches[2]]
+			}
+		}
+		if milliseconds < minNaptime || milliseconds > maxNaptime {
+			result = append(result, field.Invalid(
+				path.Child("naptime"),
+				autovacuum.Naptime,
+				"must be an integer optionally followed by one of the ms, s, min, h and d units, "+
+					"between 1s and 2147483s"))
+		}
+	}
+
+	parameters := autovacuum.GetParameters()
+	for _, setting := range autovacuumParameters {
+		value, isSet := parameters[setting.parameter]
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[setting.parameter]; ok && isSet {
+			result = append(result, field.Invalid(
+				path.Child(setting.field),
+				value,
+				fmt.Sprintf("cannot be specified together with the %s parameter", setting.parameter)))
+		}
+	}
+
+	return result
+}
+
+// getAutovacuumRestartWarnings warns about the changes of the autovacuum
+// settings that are applied only after a restart of the instances
+func (r *Cluster) getAutovacuumRestartWarnings(old *Cluster) admission.Warnings {
+	parameters := r.Spec.PostgresConfiguration.Autovacuum.GetParameters()
+	oldParameters := old.Spec.PostgresConfiguration.Autovacuum.GetParameters()
+
+	var result admission.Warnings
+	for _, setting := range autovacuumParameters {
+		if !postgres.RestartRequiredParameters[setting.parameter] ||
+			parameters[setting.parameter] == oldParameters[setting.parameter] {
+			continue
+		}
+		result = append(result, fmt.Sprintf(
+			"%s: changing %s requires a restart of the instances, which will be performed "+
+				"according to the primary update strategy",
+			field.NewPath("spec", "postgresql", "autovacuum", setting.field), setting.parameter))
+	}
+	return result
+}
+
+// Validate the minimum number of synchronous instances
+func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.MinSyncReplicas < 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "minSyncReplicas"),
+			r.Spec.MinSyncReplicas,
+			"minSyncReplicas must be a non negative integer"))
+	}
+
+	if r.Spec.MinSyncReplicas > r.Spec.MaxSyncReplicas {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "minSyncReplicas"),
+			r.Spec.MinSyncReplicas,
+			"minSyncReplicas cannot be greater than maxSyncReplicas"))
+	}
+
+	return result
+}
+
+func (r *Cluster) validateStorageSize() field.ErrorList {
+	return validateStorageConfigurationSize("Storage", r.Spec.StorageConfiguration)
+}
+
+func (r *Cluster) validateWalStorageSize() field.ErrorList {
+	var result field.ErrorList
+
+	if r.ShouldCreateWalArchiveVolume() {
+		result = append(result, validateStorageConfigurationSize("walStorage", *r.Spec.WalStorage)...)
+	}
+
+	return result
+}
+
+func (r *Cluster) validateLogStorageSize() field.ErrorList {
+	var result field.ErrorList
+
+	if r.ShouldCreateLogVolume() {
+		result = append(result, validateStorageConfigurationSize("logStorage", *r.Spec.LogStorage)...)
+	}
+
+	return result
+}
+
+func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
+	var result field.ErrorList
+
+	if storageConfiguration.Size != "" {
+		if _, err := resource.ParseQuantity(storageConfiguration.Size); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", structPath, "size"),
+				storageConfiguration.Size,
+				"Size value isn't valid"))
+		}
+	}
+
+	if storageConfiguration.Size == "" &&
+		(storageConfiguration.PersistentVolumeClaimTemplate == nil ||
+			storageConfiguration.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero()) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", structPath, "size"),
+			storageConfiguration.Size,
+			"Size not configured. Please add it, or a storage request in the pvcTemplate."))
+	}
+
+	return result
+}
+
+// Validate a change in the storage
+func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
+	return validateStorageConfigurationChange(
+		"storage",
+		old.Spec.StorageConfiguration,
+		r.Spec.StorageConfiguration,
+	)
+}
+
+func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
+	if old.Spec.WalStorage == nil {
+		return nil
+	}
+
+	if old.Spec.WalStorage != nil && r.Spec.WalStorage == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "walStorage"),
+				r.Spec.WalStorage,
+				"walStorage cannot be disabled once the cluster is created"),
+		}
 	}
 
 	return validateStorageConfigurationChange("walStorage", *old.Spec.WalStorage, *r.Spec.WalStorage)
 }
 
+func (r *Cluster) validateLogStorageChange(old *Cluster) field.ErrorList {
+	if old.Spec.LogStorage == nil {
+		return nil
+	}
+
+	if r.Spec.LogStorage == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "logStorage"),
+				r.Spec.LogStorage,
+				"logStorage cannot be disabled once the cluster is created"),
+		}
+	}
+
+	return validateStorageConfigurationChange("logStorage", *old.Spec.LogStorage, *r.Spec.LogStorage)
+}
+
 // validateStorageConfigurationChange generates an error list by comparing two StorageConfiguration
 func validateStorageConfigurationChange(
 	structPath string,
@@ -1583,12 +2669,26 @@ func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path
 				"one of connectionParameters and barmanObjectStore is required"))
 	}
 
+	if externalCluster.BarmanObjectStore != nil {
+		result = append(result,
+			validateBarmanServerName(path.Child("barmanObjectStore"), externalCluster.BarmanObjectStore)...)
+	}
+
 	return result
 }
 
 // Check replica mode is enabled only at cluster creation time
 func (r *Cluster) validateReplicaModeChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
+
+	// the replica cluster can't be promoted while its promotion is blocked
+	if old.IsPromotionBlocked() && !r.IsReplica() {
+		result = append(result, field.Forbidden(
+			field.NewPath("spec", "replica", "enabled"),
+			"Can not promote the replica cluster while spec.replica.promotionBlocked is set, "+
+				"the flag must be removed first"))
+	}
+
 	// if we are not specifying any replica cluster configuration or disabling it, nothing to do
 	if r.Spec.ReplicaCluster == nil || !r.Spec.ReplicaCluster.Enabled {
 		return result
@@ -1645,6 +2745,13 @@ func (r *Cluster) validateReplicaMode() field.ErrorList {
 			r.Spec.ReplicaCluster,
 			"replica mode is compatible only with bootstrap using pg_basebackup or recovery"))
 	}
+	if r.Spec.ReplicaCluster.PromotionBlocked && !r.Spec.ReplicaCluster.Enabled {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "replica", "promotionBlocked"),
+			r.Spec.ReplicaCluster.PromotionBlocked,
+			"promotionBlocked requires the replica mode to be enabled"))
+	}
+
 	_, found := r.ExternalCluster(r.Spec.ReplicaCluster.Source)
 	if !found {
 		result = append(
@@ -1759,59 +2866,335 @@ func (r *Cluster) validateAntiAffinity() field.ErrorList {
 				PodAntiAffinityTypePreferred, PodAntiAffinityTypeRequired),
 		))
 	}
-	return allErrors
+	return allErrors
+}
+
+// validateBackupConfiguration validates the backup configuration
+func (r *Cluster) validateBackupConfiguration() field.ErrorList {
+	allErrors := field.ErrorList{}
+
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return nil
+	}
+
+	credentialsCount := 0
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure != nil {
+		credentialsCount++
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure.validateAzureCredentials(
+			field.NewPath("spec", "backupConfiguration", "azureCredentials"))
+	}
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS != nil {
+		credentialsCount++
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS.validateAwsCredentials(
+			field.NewPath("spec", "backupConfiguration", "s3Credentials"))
+	}
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google != nil {
+		credentialsCount++
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google.validateGCSCredentials(
+			field.NewPath("spec", "backupConfiguration", "googleCredentials"))
+	}
+	if credentialsCount == 0 {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backupConfiguration"),
+			r.Spec.Backup.BarmanObjectStore,
+			"missing credentials. "+
+				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
+		))
+	}
+	if credentialsCount > 1 {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backupConfiguration"),
+			r.Spec.Backup.BarmanObjectStore,
+			"too many credentials. "+
+				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
+		))
+	}
+
+	allErrors = append(allErrors, validateBarmanEncryption(
+		field.NewPath("spec", "backup", "barmanObjectStore"),
+		r.Spec.Backup.BarmanObjectStore)...)
+	allErrors = append(allErrors, validateBarmanMaxBandwidth(
+		field.NewPath("spec", "backup", "barmanObjectStore"),
+		r.Spec.Backup.BarmanObjectStore)...)
+	allErrors = append(allErrors, validateBarmanServerName(
+		field.NewPath("spec", "backup", "barmanObjectStore"),
+		r.Spec.Backup.BarmanObjectStore)...)
+
+	if r.Spec.Backup.RetentionPolicy != "" {
+		_, err := utils.ParsePolicy(r.Spec.Backup.RetentionPolicy)
+		if err != nil {
+			allErrors = append(allErrors, field.Invalid(
+				field.NewPath("spec", "retentionPolicy"),
+				r.Spec.Backup.RetentionPolicy,
+				"not a valid retention policy",
+			))
+		}
+	}
+
+	return allErrors
+}
+
+// validateVolumeBackup validates the configuration of the volume dedicated
+// to the backups
+func (r *Cluster) validateVolumeBackup() field.ErrorList {
+	if !r.ShouldCreateBackupVolume() {
+		return nil
+	}
+
+	path := field.NewPath("spec", "backup", "volumeBackup")
+	volumeBackup := r.Spec.Backup.VolumeBackup
+
+	var result field.ErrorList
+	if r.Spec.Backup.BarmanObjectStore != nil {
+		result = append(result, field.Invalid(
+			path,
+			volumeBackup,
+			"volumeBackup and barmanObjectStore cannot be used together"))
+	}
+
+	result = append(result, validateStorageConfigurationSize("backup.volumeBackup.storage", volumeBackup.Storage)...)
+
+	// Every instance mounts the backup volume, so it must be shared
+	if r.Spec.Instances > 1 {
+		var accessModes []v1.PersistentVolumeAccessMode
+		if template := volumeBackup.Storage.PersistentVolumeClaimTemplate; template != nil {
+			accessModes = template.AccessModes
+		}
+		if !isSharedAccessMode(accessModes) {
+			result = append(result, field.Invalid(
+				path.Child("storage", "pvcTemplate", "accessModes"),
+				accessModes,
+				"the backup volume is mounted by every instance and requires "+
+					"the ReadWriteMany access mode when there is more than one instance"))
+		}
+	}
+
+	return result
+}
+
+// isSharedAccessMode checks whether a volume with the passed access
+// modes can be mounted by more than one instance
+func isSharedAccessMode(accessModes []v1.PersistentVolumeAccessMode) bool {
+	for _, accessMode := range accessModes {
+		if accessMode == v1.ReadWriteMany {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateBackupVolumeChange validates a change in the volume dedicated
+// to the backups
+func (r *Cluster) validateBackupVolumeChange(old *Cluster) field.ErrorList {
+	if !old.ShouldCreateBackupVolume() || !r.ShouldCreateBackupVolume() {
+		return nil
+	}
+
+	return validateStorageConfigurationChange(
+		"backup.volumeBackup.storage",
+		old.Spec.Backup.VolumeBackup.Storage,
+		r.Spec.Backup.VolumeBackup.Storage,
+	)
+}
+
+// validateAdditionalWalObjectStores validates the object stores receiving
+// a copy of the WAL files, and the number of them that must succeed
+func (r *Cluster) validateAdditionalWalObjectStores() field.ErrorList {
+	if r.Spec.Backup == nil {
+		return nil
+	}
+
+	backupPath := field.NewPath("spec", "backup")
+	additionalPath := backupPath.Child("additionalWalObjectStores")
+	var result field.ErrorList
+
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		if len(r.Spec.Backup.AdditionalWalObjectStores) > 0 {
+			result = append(result, field.Invalid(
+				additionalPath,
+				len(r.Spec.Backup.AdditionalWalObjectStores),
+				"requires barmanObjectStore to be configured"))
+		}
+		if r.Spec.Backup.WalArchiveQuorum != nil {
+			result = append(result, field.Invalid(
+				backupPath.Child("walArchiveQuorum"),
+				*r.Spec.Backup.WalArchiveQuorum,
+				"requires barmanObjectStore to be configured"))
+		}
+		return result
+	}
+
+	// The Google credentials are written in a fixed location, and the
+	// CA bundle of the endpoint is only mounted for barmanObjectStore
+	googleCredentialsCount := 0
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google != nil {
+		googleCredentialsCount++
+	}
+	seenDestinations := stringset.New()
+	seenDestinations.Put(getWalDestinationID(r.Name, r.Spec.Backup.BarmanObjectStore))
+	for idx := range r.Spec.Backup.AdditionalWalObjectStores {
+		objectStore := &r.Spec.Backup.AdditionalWalObjectStores[idx]
+		path := additionalPath.Index(idx)
+
+		credentials := objectStore.BarmanCredentials
+		credentialsCount := 0
+		if credentials.Azure != nil {
+			credentialsCount++
+			result = append(result, credentials.Azure.validateAzureCredentials(path.Child("azureCredentials"))...)
+		}
+		if credentials.AWS != nil {
+			credentialsCount++
+			result = append(result, credentials.AWS.validateAwsCredentials(path.Child("s3Credentials"))...)
+		}
+		if credentials.Google != nil {
+			credentialsCount++
+			googleCredentialsCount++
+			result = append(result, credentials.Google.validateGCSCredentials(path.Child("googleCredentials"))...)
+		}
+		if credentialsCount != 1 {
+			result = append(result, field.Invalid(
+				path,
+				credentialsCount,
+				"one and only one of azureCredentials, s3Credentials and googleCredentials is required"))
+		}
+
+		if objectStore.EndpointCA != nil {
+			result = append(result, field.Forbidden(
+				path.Child("endpointCA"),
+				"the CA bundle of the endpoint is only supported in barmanObjectStore"))
+		}
+
+		destinationID := getWalDestinationID(r.Name, objectStore)
+		if seenDestinations.Has(destinationID) {
+			result = append(result, field.Duplicate(path.Child("destinationPath"), objectStore.DestinationPath))
+		}
+		seenDestinations.Put(destinationID)
+
+		result = append(result, validateBarmanEncryption(path, objectStore)...)
+		result = append(result, validateBarmanMaxBandwidth(path, objectStore)...)
+		result = append(result, validateBarmanServerName(path, objectStore)...)
+	}
+
+	if googleCredentialsCount > 1 {
+		result = append(result, field.Invalid(
+			additionalPath,
+			googleCredentialsCount,
+			"googleCredentials can be used by only one object store"))
+	}
+
+	if quorum := r.Spec.Backup.WalArchiveQuorum; quorum != nil {
+		if objectStores := 1 + len(r.Spec.Backup.AdditionalWalObjectStores); *quorum < 1 || *quorum > objectStores {
+			result = append(result, field.Invalid(
+				backupPath.Child("walArchiveQuorum"),
+				*quorum,
+				fmt.Sprintf("must be between 1 and the number of object stores (%d)", objectStores)))
+		}
+	}
+
+	return result
+}
+
+// getWalDestinationID returns an identifier of the location where an
+// object store archives the WAL files
+func getWalDestinationID(clusterName string, objectStore *BarmanObjectStoreConfiguration) string {
+	return strings.TrimSuffix(objectStore.DestinationPath, "/") + "/" + objectStore.GetServerName(clusterName)
+}
+
+// barmanServerNameRegex matches the server names that are safe to be
+// used as a folder in the object store
+var barmanServerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// validateBarmanServerName validates that the server name can be used as
+// a folder of the object store, without escaping the destination path
+func validateBarmanServerName(
+	path *field.Path,
+	configuration *BarmanObjectStoreConfiguration,
+) field.ErrorList {
+	if configuration.ServerName == "" || barmanServerNameRegex.MatchString(configuration.ServerName) {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			path.Child("serverName"),
+			configuration.ServerName,
+			"must start with a letter or a digit, and only contain letters, digits, dots, underscores and dashes"),
+	}
+}
+
+// validateBarmanMaxBandwidth validates the bandwidth limit of the uploads
+func validateBarmanMaxBandwidth(
+	path *field.Path,
+	configuration *BarmanObjectStoreConfiguration,
+) field.ErrorList {
+	if configuration.MaxBandwidth == nil || *configuration.MaxBandwidth > 0 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			path.Child("maxBandwidth"),
+			*configuration.MaxBandwidth,
+			"must be a positive number of bytes per second"),
+	}
 }
 
-// validateBackupConfiguration validates the backup configuration
-func (r *Cluster) validateBackupConfiguration() field.ErrorList {
-	allErrors := field.ErrorList{}
+// awsKMSKeyIDRegex matches the ARNs, the IDs and the aliases of the AWS KMS keys
+var awsKMSKeyIDRegex = regexp.MustCompile(
+	`^(arn:aws[a-z-]*:kms:[a-z0-9-]+:[0-9]{12}:(key|alias)/.+|alias/.+|(mrk-)?[0-9a-f-]+)$`)
 
-	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+// validateBarmanEncryption validates the envelope encryption of the backups
+func validateBarmanEncryption(
+	path *field.Path,
+	configuration *BarmanObjectStoreConfiguration,
+) field.ErrorList {
+	encryption := configuration.Encryption
+	if encryption == nil {
 		return nil
 	}
 
-	credentialsCount := 0
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure != nil {
-		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure.validateAzureCredentials(
-			field.NewPath("spec", "backupConfiguration", "azureCredentials"))
-	}
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS != nil {
-		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS.validateAwsCredentials(
-			field.NewPath("spec", "backupConfiguration", "s3Credentials"))
+	var allErrors field.ErrorList
+	encryptionPath := path.Child("encryption")
+
+	if encryption.Method != BarmanEncryptionMethodSSEKMS {
+		allErrors = append(allErrors, field.NotSupported(
+			encryptionPath.Child("method"),
+			encryption.Method,
+			[]string{string(BarmanEncryptionMethodSSEKMS)}))
 	}
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google != nil {
-		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google.validateGCSCredentials(
-			field.NewPath("spec", "backupConfiguration", "googleCredentials"))
+
+	if encryption.GetProvider() != KMSProviderAWS {
+		allErrors = append(allErrors, field.NotSupported(
+			encryptionPath.Child("provider"),
+			encryption.Provider,
+			[]string{string(KMSProviderAWS)}))
+	} else if !awsKMSKeyIDRegex.MatchString(encryption.KeyID) {
+		allErrors = append(allErrors, field.Invalid(
+			encryptionPath.Child("keyID"),
+			encryption.KeyID,
+			"must be the ARN, the ID or the alias of an AWS KMS key"))
 	}
-	if credentialsCount == 0 {
+
+	if configuration.AWS == nil {
 		allErrors = append(allErrors, field.Invalid(
-			field.NewPath("spec", "backupConfiguration"),
-			r.Spec.Backup.BarmanObjectStore,
-			"missing credentials. "+
-				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
-		))
+			encryptionPath,
+			encryption.Method,
+			"the sse-kms encryption requires an S3 object store, configured with s3Credentials"))
 	}
-	if credentialsCount > 1 {
+
+	if configuration.Wal != nil && configuration.Wal.Encryption == EncryptionTypeAES256 {
 		allErrors = append(allErrors, field.Invalid(
-			field.NewPath("spec", "backupConfiguration"),
-			r.Spec.Backup.BarmanObjectStore,
-			"too many credentials. "+
-				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
-		))
+			path.Child("wal", "encryption"),
+			configuration.Wal.Encryption,
+			"conflicts with the sse-kms encryption"))
 	}
 
-	if r.Spec.Backup.RetentionPolicy != "" {
-		_, err := utils.ParsePolicy(r.Spec.Backup.RetentionPolicy)
-		if err != nil {
-			allErrors = append(allErrors, field.Invalid(
-				field.NewPath("spec", "retentionPolicy"),
-				r.Spec.Backup.RetentionPolicy,
-				"not a valid retention policy",
-			))
-		}
+	if configuration.Data != nil && configuration.Data.Encryption == EncryptionTypeAES256 {
+		allErrors = append(allErrors, field.Invalid(
+			path.Child("data", "encryption"),
+			configuration.Data.Encryption,
+			"conflicts with the sse-kms encryption"))
 	}
 
 	return allErrors
@@ -1850,6 +3233,101 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	}
 }
 
+// validateMaxSlotWALKeepSize checks the cap to the WAL retained by the
+// replication slots
+func (r *Cluster) validateMaxSlotWALKeepSize() field.ErrorList {
+	if r.Spec.ReplicationSlots == nil || r.Spec.ReplicationSlots.MaxSlotWALKeepSize == "" {
+		return nil
+	}
+
+	path := field.NewPath("spec", "replicationSlots", "maxSlotWALKeepSize")
+	value := r.Spec.ReplicationSlots.MaxSlotWALKeepSize
+	size, err := resource.ParseQuantity(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, "Size value isn't valid")}
+	}
+
+	var result field.ErrorList
+	if size.Cmp(resource.MustParse(MinMaxSlotWALKeepSize)) < 0 {
+		result = append(result, field.Invalid(
+			path,
+			value,
+			fmt.Sprintf("the WAL retention of the replication slots can't be lower than %s", MinMaxSlotWALKeepSize)))
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["max_slot_wal_keep_size"]; ok {
+		result = append(result, field.Invalid(
+			path,
+			value,
+			"cannot be specified together with the max_slot_wal_keep_size parameter"))
+	}
+
+	if psqlVersion, err := r.GetPostgresqlVersion(); err == nil && psqlVersion < 130000 {
+		result = append(result, field.Invalid(
+			path,
+			value,
+			"the WAL retention of the replication slots can be capped only with PostgreSQL 13 or above"))
+	}
+
+	return result
+}
+
+// validateWalSegmentSize checks the WAL segment size requested in the
+// PostgreSQL configuration
+func (r *Cluster) validateWalSegmentSize() field.ErrorList {
+	walSegmentSize := r.Spec.PostgresConfiguration.WalSegmentSize
+	if walSegmentSize == 0 {
+		return nil
+	}
+
+	path := field.NewPath("spec", "postgresql", "walSegmentSize")
+	var result field.ErrorList
+	if !utils.IsPowerOfTwo(walSegmentSize) {
+		result = append(result, field.Invalid(
+			path,
+			walSegmentSize,
+			"WAL segment size must be a power of 2"))
+	}
+
+	if r.Spec.Bootstrap != nil && r.Spec.Bootstrap.InitDB != nil &&
+		r.Spec.Bootstrap.InitDB.WalSegmentSize != 0 &&
+		r.Spec.Bootstrap.InitDB.WalSegmentSize != walSegmentSize {
+		result = append(result, field.Invalid(
+			path,
+			walSegmentSize,
+			"cannot be different from the WAL segment size of the initdb bootstrap section"))
+	}
+
+	return result
+}
+
+// validateWalSegmentSizeChange rejects the changes to the WAL segment
+// size, that is chosen by initdb and can't be changed afterwards
+func (r *Cluster) validateWalSegmentSizeChange(old *Cluster) field.ErrorList {
+	walSegmentSize := r.GetWalSegmentSize()
+	if walSegmentSize == old.GetWalSegmentSize() {
+		return nil
+	}
+
+	// declaring the size the cluster is already using is not a change
+	if walSegmentSize != 0 && walSegmentSize == old.Status.WalSegmentSize {
+		return nil
+	}
+
+	path := field.NewPath("spec", "postgresql", "walSegmentSize")
+	if r.Spec.PostgresConfiguration.WalSegmentSize == 0 &&
+		old.Spec.PostgresConfiguration.WalSegmentSize == 0 {
+		path = field.NewPath("spec", "bootstrap", "initdb", "walSegmentSize")
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			path,
+			walSegmentSize,
+			"WAL segment size is chosen at bootstrap and cannot be changed"),
+	}
+}
+
 func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	newReplicationSlots := r.Spec.ReplicationSlots
 	oldReplicationSlots := old.Spec.ReplicationSlots
@@ -2045,6 +3523,278 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 	return result
 }
 
+// validateManagedPublications validate the publications settings proposed by the user
+func (r *Cluster) validateManagedPublications() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "managed", "publications")
+	managedPublications := make(map[string]interface{})
+	for _, publication := range r.Spec.Managed.Publications {
+		key := publication.GetDBName(r.GetApplicationDatabaseName()) + "/" + publication.Name
+		if _, found := managedPublications[key]; found {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					publication.Name,
+					"Publication name is duplicate of another in the same database"))
+		}
+		managedPublications[key] = nil
+
+		if publication.Ensure == EnsureAbsent {
+			continue
+		}
+
+		if publication.AllTables && len(publication.Tables) > 0 {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					publication.Name,
+					"This publication sets both allTables and a list of tables"))
+		}
+		if !publication.AllTables && len(publication.Tables) == 0 {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					publication.Name,
+					"This publication needs either allTables or a list of tables"))
+		}
+	}
+
+	return result
+}
+
+// validateManagedSubscriptions validate the subscriptions settings proposed by the user
+func (r *Cluster) validateManagedSubscriptions() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "managed", "subscriptions")
+	managedSubscriptions := make(map[string]interface{})
+	for _, subscription := range r.Spec.Managed.Subscriptions {
+		key := subscription.GetDBName(r.GetApplicationDatabaseName()) + "/" + subscription.Name
+		if _, found := managedSubscriptions[key]; found {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					subscription.Name,
+					"Subscription name is duplicate of another in the same database"))
+		}
+		managedSubscriptions[key] = nil
+
+		if subscription.Ensure == EnsureAbsent {
+			continue
+		}
+
+		if subscription.ConnectionSecret.Name == "" || subscription.ConnectionSecret.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					subscription.Name,
+					"This subscription needs a connection secret name and key"))
+		}
+		if subscription.PublicationName == "" {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					subscription.Name,
+					"This subscription needs the name of a publication"))
+		}
+	}
+
+	return result
+}
+
+// validateManagedTables validate the storage parameters of the tables proposed by the user
+func (r *Cluster) validateManagedTables() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "managed", "tables")
+	managedTables := make(map[string]interface{})
+	for idx, table := range r.Spec.Managed.Tables {
+		if table.Name == "" {
+			result = append(
+				result,
+				field.Required(path.Index(idx).Child("name"), "The name of the table is required"))
+			continue
+		}
+
+		tableName := table.Name
+		if !strings.Contains(tableName, ".") {
+			tableName = "public." + tableName
+		}
+		key := table.GetDBName(r.GetApplicationDatabaseName()) + "/" + tableName
+		if _, found := managedTables[key]; found {
+			result = append(
+				result,
+				field.Invalid(
+					path,
+					table.Name,
+					"Table name is duplicate of another in the same database"))
+		}
+		managedTables[key] = nil
+
+		for name := range table.AutovacuumSettings {
+			if !IsAutovacuumStorageParameter(name) {
+				result = append(
+					result,
+					field.Invalid(
+						path.Index(idx).Child("autovacuumSettings"),
+						name,
+						"Not an autovacuum storage parameter"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateManagedSQLJobs checks that the managed SQL jobs have a unique
+// name and exactly one source of the script
+func (r *Cluster) validateManagedSQLJobs() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "managed", "sqlJobs")
+	managedJobs := make(map[string]interface{})
+	for idx, job := range r.Spec.Managed.SQLJobs {
+		if job.Name == "" {
+			result = append(
+				result,
+				field.Required(path.Index(idx).Child("name"), "The name of the SQL job is required"))
+		} else {
+			if _, found := managedJobs[job.Name]; found {
+				result = append(
+					result,
+					field.Invalid(
+						path,
+						job.Name,
+						"SQL job name is duplicate of another"))
+			}
+			managedJobs[job.Name] = nil
+		}
+
+		if (job.SQL == "") == (job.SecretRef == nil) {
+			result = append(
+				result,
+				field.Invalid(
+					path.Index(idx),
+					job.Name,
+					"Exactly one of sql and secretRef must be specified"))
+		}
+	}
+
+	return result
+}
+
+// validateMaintenanceJobs checks that the maintenance jobs have a unique
+// name, a valid schedule and some targets suitable for their operation.
+// VACUUM FULL blocks the tables it rewrites, so it is only allowed within
+// a maintenance window
+func (r *Cluster) validateMaintenanceJobs() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "managed", "maintenanceJobs")
+	managedJobs := make(map[string]interface{})
+	for idx, job := range r.Spec.Managed.MaintenanceJobs {
+		jobPath := path.Index(idx)
+		if job.Name == "" {
+			result = append(
+				result,
+				field.Required(jobPath.Child("name"), "The name of the maintenance job is required"))
+		} else {
+			if _, found := managedJobs[job.Name]; found {
+				result = append(
+					result,
+					field.Invalid(
+						path,
+						job.Name,
+						"Maintenance job name is duplicate of another"))
+			}
+			managedJobs[job.Name] = nil
+		}
+
+		if len(job.Tables) == 0 && len(job.Indexes) == 0 {
+			result = append(
+				result,
+				field.Invalid(
+					jobPath,
+					job.Name,
+					"At least one table or index must be specified"))
+		}
+
+		for tableIdx, table := range job.Tables {
+			if table == "" {
+				result = append(
+					result,
+					field.Required(jobPath.Child("tables").Index(tableIdx), "The name of the table is required"))
+			}
+		}
+		for indexIdx, index := range job.Indexes {
+			if index == "" {
+				result = append(
+					result,
+					field.Required(jobPath.Child("indexes").Index(indexIdx), "The name of the index is required"))
+			}
+		}
+
+		if job.Operation == MaintenanceOperationVacuumFull {
+			if len(job.Indexes) > 0 {
+				result = append(
+					result,
+					field.Invalid(
+						jobPath.Child("indexes"),
+						job.Indexes,
+						"Indexes can only be specified with the reindexConcurrently operation"))
+			}
+			if r.Spec.MaintenanceWindow == nil {
+				result = append(
+					result,
+					field.Invalid(
+						jobPath.Child("operation"),
+						job.Operation,
+						"The vacuumFull operation requires spec.maintenanceWindow to be defined"))
+			}
+		}
+
+		if job.Schedule != "" {
+			if _, err := cron.Parse(job.Schedule); err != nil {
+				result = append(
+					result,
+					field.Invalid(
+						jobPath.Child("schedule"),
+						job.Schedule,
+						fmt.Sprintf("invalid schedule: %v", err)))
+			}
+		}
+	}
+
+	return result
+}
+
 // validateManagedExtensions validate the managed extensions parameters set by the user
 func (r *Cluster) validateManagedExtensions() field.ErrorList {
 	allErrors := field.ErrorList{}
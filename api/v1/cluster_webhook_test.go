@@ -707,6 +707,15 @@ var _ = Describe("Image name validation", func() {
 		}
 		Expect(cluster.validateImageName()).To(HaveLen(1))
 	})
+
+	It("complain when the image reference is malformed", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io//postgresql:16.1",
+			},
+		}
+		Expect(cluster.validateImageName()).To(HaveLen(1))
+	})
 })
 
 var _ = DescribeTable("parsePostgresQuantityValue",
@@ -1315,6 +1324,21 @@ var _ = Describe("recovery target", func() {
 			Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
 		})
 
+		It("allows 'current'", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetTLI: "current",
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+		})
+
 		It("allows a positive integer", func() {
 			cluster := Cluster{
 				Spec: ClusterSpec{
@@ -1374,6 +1398,52 @@ var _ = Describe("recovery target", func() {
 			}
 			Expect(cluster.validateRecoveryTarget()).To(HaveLen(1))
 		})
+
+		It("prevents values with a different case", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetTLI: "Current",
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("maintenance window", func() {
+	It("is optional", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateMaintenanceWindow()).To(BeEmpty())
+	})
+
+	It("allows valid schedules", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindow: &MaintenanceWindowConfiguration{
+					Schedule: "0 0 2 * * 6",
+					Duration: 3600,
+				},
+			},
+		}
+		Expect(cluster.validateMaintenanceWindow()).To(BeEmpty())
+	})
+
+	It("complains about invalid schedules", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindow: &MaintenanceWindowConfiguration{
+					Schedule: "every saturday",
+					Duration: 3600,
+				},
+			},
+		}
+		Expect(cluster.validateMaintenanceWindow()).To(HaveLen(1))
 	})
 })
 
@@ -1481,508 +1551,553 @@ var _ = Describe("Number of synchronous replicas", func() {
 	})
 })
 
-var _ = Describe("storage configuration validation", func() {
-	It("complains if the size is being reduced", func() {
-		clusterOld := Cluster{
-			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "1G",
-				},
-			},
-		}
-
-		clusterNew := Cluster{
-			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "512M",
-				},
-			},
-		}
-
-		Expect(clusterNew.validateStorageChange(&clusterOld)).ToNot(BeEmpty())
+var _ = Describe("Disk pressure switchover validation", func() {
+	It("accepts a missing configuration", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateDiskPressureSwitchover()).To(BeEmpty())
 	})
 
-	It("does not complain if nothing has been changed", func() {
-		one := "one"
-		clusterOld := Cluster{
+	It("accepts the default watermarks", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size:         "1G",
-					StorageClass: &one,
+				DiskPressureSwitchover: &DiskPressureSwitchoverConfiguration{
+					Enabled: true,
 				},
 			},
 		}
-
-		clusterNew := clusterOld.DeepCopy()
-
-		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
+		Expect(cluster.validateDiskPressureSwitchover()).To(BeEmpty())
 	})
 
-	It("works fine is the size is being enlarged", func() {
-		clusterOld := Cluster{
-			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "8G",
-				},
-			},
-		}
-
-		clusterNew := Cluster{
+	It("complains if the low watermark is not lower than the high one", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "10G",
+				DiskPressureSwitchover: &DiskPressureSwitchoverConfiguration{
+					Enabled:       true,
+					HighWatermark: 85,
+					LowWatermark:  85,
 				},
 			},
 		}
-
-		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
+		Expect(cluster.validateDiskPressureSwitchover()).To(HaveLen(1))
 	})
 })
 
-var _ = Describe("Cluster name validation", func() {
-	It("should be a valid DNS label", func() {
-		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test.one",
-			},
-		}
-		Expect(cluster.validateName()).ToNot(BeEmpty())
-	})
-
-	It("should not be too long", func() {
-		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi",
-			},
-		}
-		Expect(cluster.validateName()).ToNot(BeEmpty())
+var _ = Describe("probes validation", func() {
+	It("accepts a missing configuration", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateProbes()).To(BeEmpty())
 	})
 
-	It("should not raise errors when the name is ok", func() {
+	It("accepts a readiness probe detecting failures before the liveness one", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi",
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Liveness: &ProbeTimings{
+						PeriodSeconds:    ptr.To(int32(30)),
+						FailureThreshold: ptr.To(int32(10)),
+					},
+					Readiness: &ProbeTimings{
+						FailureThreshold: ptr.To(int32(6)),
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).To(BeEmpty())
+		Expect(cluster.validateProbes()).To(BeEmpty())
 	})
 
-	It("should return errors when the name is not DNS-1035 compliant", func() {
+	It("accepts the same failure window for the readiness and the liveness probes", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "4b96d026-a956-47eb-bae8-a99b840805c3",
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Startup: &ProbeTimings{
+						PeriodSeconds: ptr.To(int32(60)),
+					},
+					Readiness: &ProbeTimings{
+						PeriodSeconds:    ptr.To(int32(5)),
+						FailureThreshold: ptr.To(int32(6)),
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).NotTo(BeEmpty())
+		Expect(cluster.validateProbes()).To(BeEmpty())
 	})
 
-	It("should return errors when the name length is greater than 50", func() {
+	It("complains if the readiness probe is more tolerant than the liveness one", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: strings.Repeat("toomuchlong", 4) + "-" + "after4times",
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ProbeTimings{
+						PeriodSeconds:    ptr.To(int32(20)),
+						FailureThreshold: ptr.To(int32(5)),
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).NotTo(BeEmpty())
+		errors := cluster.validateProbes()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.probes.readiness"))
 	})
 
-	It("should return errors when having a name with dots", func() {
+	It("complains if the liveness probe gives up before the readiness one", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "wrong.name",
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Liveness: &ProbeTimings{
+						FailureThreshold: ptr.To(int32(1)),
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).NotTo(BeEmpty())
+		Expect(cluster.validateProbes()).To(HaveLen(1))
 	})
 })
 
-var _ = Describe("validation of the list of external clusters", func() {
-	It("is correct when it's empty", func() {
-		cluster := Cluster{}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
-	})
-
-	It("complains when the list of clusters contains duplicates", func() {
+var _ = Describe("instance overrides validation", func() {
+	It("accepts the overrides of the parameters that can differ between instances", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "one",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
-						},
-					},
+				InstanceOverrides: []InstanceOverride{
 					{
-						Name: "one",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
-						},
+						Name:       "cluster-example-3",
+						Parameters: map[string]string{"work_mem": "256MB"},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateExternalClusters()).ToNot(BeEmpty())
+		Expect(cluster.validateInstanceOverrides()).To(BeEmpty())
 	})
 
-	It("should not raise errors is the cluster name is unique", func() {
+	It("complains about the protected and the fixed parameters", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "one",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
-						},
-					},
+				InstanceOverrides: []InstanceOverride{
 					{
-						Name: "two",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
+						Name: "cluster-example-3",
+						Parameters: map[string]string{
+							"max_connections": "500",
+							"wal_level":       "replica",
+							"port":            "5433",
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+		Expect(cluster.validateInstanceOverrides()).To(HaveLen(3))
 	})
-})
 
-var _ = Describe("validation of an external cluster", func() {
-	It("ensure that one of connectionParameters and barmanObjectStore is set", func() {
+	It("complains about missing and duplicated instance names", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				ExternalClusters: []ExternalCluster{
-					{},
+				InstanceOverrides: []InstanceOverride{
+					{Name: "cluster-example-2"},
+					{Name: "cluster-example-2"},
+					{Name: ""},
 				},
 			},
 		}
-		Expect(cluster.validateExternalClusters()).To(Not(BeEmpty()))
-
-		cluster.Spec.ExternalClusters[0].ConnectionParameters = map[string]string{
-			"dbname": "postgres",
-		}
-		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
-
-		cluster.Spec.ExternalClusters[0].ConnectionParameters = nil
-		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+		errors := cluster.validateInstanceOverrides()
+		Expect(errors).To(HaveLen(2))
+		Expect(errors[0].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(errors[1].Type).To(Equal(field.ErrorTypeRequired))
 	})
 })
 
-var _ = Describe("bootstrap base backup validation", func() {
-	It("complains if you specify the database name but not the owner for pg_basebackup", func() {
+var _ = Describe("TCP keepalives validation", func() {
+	It("accepts a cluster without keepalive settings", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateTCPKeepalives()).To(BeEmpty())
+	})
+
+	It("accepts sane keepalive settings", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Database: "app",
+				PostgresConfiguration: PostgresConfiguration{
+					TCPKeepalives: &TCPKeepalivesConfiguration{
+						Idle:     ptr.To(int32(60)),
+						Interval: ptr.To(int32(10)),
+						Count:    ptr.To(int32(5)),
 					},
 				},
 			},
 		}
-
-		result := cluster.validatePgBaseBackupApplicationDatabase()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateTCPKeepalives()).To(BeEmpty())
 	})
 
-	It("complains if you specify the owner but not the database name for pg_basebackup", func() {
+	It("complains about out of range settings", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Owner: "app",
+				PostgresConfiguration: PostgresConfiguration{
+					TCPKeepalives: &TCPKeepalivesConfiguration{
+						Idle:     ptr.To(int32(40000)),
+						Interval: ptr.To(int32(-1)),
+						Count:    ptr.To(int32(0)),
 					},
 				},
 			},
 		}
-
-		result := cluster.validatePgBaseBackupApplicationDatabase()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateTCPKeepalives()).To(HaveLen(3))
 	})
 
-	It("doesn't complain if you specify both database name and owner user for pg_basebackup", func() {
+	It("complains when a setting is also specified as a parameter", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Database: "app",
-						Owner:    "app",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"tcp_keepalives_idle":  "30",
+						"tcp_keepalives_count": "3",
+					},
+					TCPKeepalives: &TCPKeepalivesConfiguration{
+						Idle:     ptr.To(int32(60)),
+						Interval: ptr.To(int32(10)),
 					},
 				},
 			},
 		}
-
-		result := cluster.validatePgBaseBackupApplicationDatabase()
-		Expect(result).To(BeEmpty())
+		errors := cluster.validateTCPKeepalives()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.tcpKeepalives.idle"))
 	})
+})
 
-	It("doesn't complain if we are not bootstrapping using pg_basebackup", func() {
-		recoveryCluster := &Cluster{
+var _ = Describe("replica connection validation", func() {
+	newCluster := func(replicaConnection *ReplicaConnectionConfiguration, parameters map[string]string) Cluster {
+		return Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters:        parameters,
+					ReplicaConnection: replicaConnection,
+				},
 			},
 		}
-		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
-		Expect(result).To(BeEmpty())
+	}
+
+	It("accepts a cluster without replica connection settings", func() {
+		cluster := newCluster(nil, nil)
+		Expect(cluster.validateReplicaConnection()).To(BeEmpty())
 	})
 
-	It("complain when the source cluster doesn't exist", func() {
-		recoveryCluster := &Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Source: "test",
-					},
-				},
-			},
+	It("accepts sane replica connection settings", func() {
+		cluster := newCluster(&ReplicaConnectionConfiguration{
+			RetryInterval:  ptr.To(int32(1000)),
+			ConnectTimeout: ptr.To(int32(10)),
+		}, nil)
+		Expect(cluster.validateReplicaConnection()).To(BeEmpty())
+	})
+
+	It("complains about out of range settings", func() {
+		cluster := newCluster(&ReplicaConnectionConfiguration{
+			RetryInterval:  ptr.To(int32(10)),
+			ConnectTimeout: ptr.To(int32(1)),
+		}, nil)
+		Expect(cluster.validateReplicaConnection()).To(HaveLen(2))
+
+		cluster = newCluster(&ReplicaConnectionConfiguration{
+			RetryInterval:  ptr.To(int32(600000)),
+			ConnectTimeout: ptr.To(int32(3600)),
+		}, nil)
+		Expect(cluster.validateReplicaConnection()).To(HaveLen(2))
+	})
+
+	It("complains when the retry interval is also specified as a parameter", func() {
+		cluster := newCluster(&ReplicaConnectionConfiguration{
+			RetryInterval: ptr.To(int32(1000)),
+		}, map[string]string{"wal_retrieve_retry_interval": "2s"})
+		errors := cluster.validateReplicaConnection()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.replicaConnection.retryInterval"))
+	})
+
+	It("accepts the application name patterns identifying the instances", func() {
+		for _, pattern := range []string{"{podName}", "dc1-{podName}", "pg_{serial}", "{podName}.{serial}"} {
+			cluster := newCluster(&ReplicaConnectionConfiguration{ApplicationName: pattern}, nil)
+			cluster.Name = "cluster-example"
+			Expect(cluster.validateReplicaConnection()).To(BeEmpty(), pattern)
 		}
-		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
-		Expect(result).ToNot(BeEmpty())
+	})
+
+	It("complains about the application name patterns not identifying the instances", func() {
+		cluster := newCluster(&ReplicaConnectionConfiguration{ApplicationName: "replica"}, nil)
+		cluster.Name = "cluster-example"
+		errors := cluster.validateReplicaConnection()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.replicaConnection.applicationName"))
+	})
+
+	It("complains about unknown placeholders", func() {
+		cluster := newCluster(&ReplicaConnectionConfiguration{ApplicationName: "{cluster}-{serial}"}, nil)
+		cluster.Name = "cluster-example"
+		errors := cluster.validateReplicaConnection()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Detail).To(ContainSubstring("placeholders"))
+	})
+
+	It("complains about the application names truncated by PostgreSQL", func() {
+		cluster := newCluster(&ReplicaConnectionConfiguration{
+			ApplicationName: "datacenter-west-{podName}",
+		}, nil)
+		cluster.Name = strings.Repeat("a", 41)
+		Expect(cluster.validateReplicaConnection()).To(BeEmpty())
+
+		cluster.Name = strings.Repeat("a", 42)
+		errors := cluster.validateReplicaConnection()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Detail).To(ContainSubstring("63 characters"))
+	})
+
+	It("rejects the changes of the application name pattern", func() {
+		oldCluster := newCluster(&ReplicaConnectionConfiguration{ApplicationName: "dc1-{serial}"}, nil)
+		cluster := newCluster(&ReplicaConnectionConfiguration{ApplicationName: "dc2-{serial}"}, nil)
+		errors := cluster.validateReplicaApplicationNameChange(&oldCluster)
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.replicaConnection.applicationName"))
+
+		cluster = newCluster(nil, nil)
+		Expect(cluster.validateReplicaApplicationNameChange(&oldCluster)).To(HaveLen(1))
+		Expect(cluster.ValidateChanges(&oldCluster)).ToNot(BeEmpty())
+	})
+
+	It("accepts setting the default application name pattern explicitly", func() {
+		oldCluster := newCluster(nil, nil)
+		cluster := newCluster(&ReplicaConnectionConfiguration{ApplicationName: "{podName}"}, nil)
+		Expect(cluster.validateReplicaApplicationNameChange(&oldCluster)).To(BeEmpty())
+		Expect(oldCluster.validateReplicaApplicationNameChange(&cluster)).To(BeEmpty())
 	})
 })
 
-var _ = Describe("bootstrap recovery validation", func() {
-	It("complains if you specify the database name but not the owner for recovery", func() {
+var _ = Describe("durability validation", func() {
+	It("accepts the strict durability with backups", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Database: "app",
-					},
+				PostgresConfiguration: PostgresConfiguration{
+					Durability: DurabilityModeStrict,
+				},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
 				},
 			},
 		}
-
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateDurability()).To(BeEmpty())
 	})
 
-	It("complains if you specify the owner but not the database name for recovery", func() {
+	It("accepts the relaxed durability without backups", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Owner: "app",
-					},
+				PostgresConfiguration: PostgresConfiguration{
+					Durability: DurabilityModeRelaxed,
 				},
+				Backup: &BackupConfiguration{},
 			},
 		}
-
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateDurability()).To(BeEmpty())
 	})
 
-	It("doesn't complain if you specify both database name and owner user for recovery", func() {
+	It("complains about the relaxed durability with backups", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Database: "app",
-						Owner:    "app",
-					},
+				PostgresConfiguration: PostgresConfiguration{
+					Durability: DurabilityModeRelaxed,
+				},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
 				},
 			},
 		}
+		Expect(cluster.validateDurability()).To(HaveLen(1))
 
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(result).To(BeEmpty())
+		cluster.Spec.Backup = &BackupConfiguration{
+			VolumeSnapshot: &VolumeSnapshotConfiguration{},
+		}
+		Expect(cluster.validateDurability()).To(HaveLen(1))
 	})
 
-	It("does not complain when bootstrap recovery source matches one of the names of external clusters", func() {
-		recoveryCluster := &Cluster{
+	It("complains about the parameters set by the relaxed durability", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source: "test",
-					},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+				PostgresConfiguration: PostgresConfiguration{
+					Durability: DurabilityModeRelaxed,
+					Parameters: map[string]string{
+						"fsync":              "on",
+						"synchronous_commit": "local",
+						"work_mem":           "8MB",
 					},
 				},
 			},
 		}
-		errorsList := recoveryCluster.validateBootstrapRecoverySource()
-		Expect(errorsList).To(BeEmpty())
+		errors := cluster.validateDurability()
+		Expect(errors).To(HaveLen(2))
+		Expect(errors[0].Detail).To(ContainSubstring("fsync"))
+		Expect(errors[1].Detail).To(ContainSubstring("synchronous_commit"))
 	})
 
-	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
-		recoveryCluster := &Cluster{
+	It("warns that pg_rewind can't be used with the relaxed durability", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source: "test",
-					},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "another-test",
-					},
+				Instances: 3,
+				PostgresConfiguration: PostgresConfiguration{
+					Durability: DurabilityModeRelaxed,
 				},
 			},
 		}
-		errorsList := recoveryCluster.validateBootstrapRecoverySource()
-		Expect(errorsList).ToNot(BeEmpty())
+		warnings := cluster.getDurabilityWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("pg_rewind"))
+		Expect(cluster.getAdmissionWarnings()).To(ContainElement(warnings[0]))
+
+		cluster.Spec.Instances = 1
+		Expect(cluster.getDurabilityWarnings()).To(BeEmpty())
+
+		cluster.Spec.Instances = 3
+		cluster.Spec.PostgresConfiguration.Durability = DurabilityModeStrict
+		Expect(cluster.getDurabilityWarnings()).To(BeEmpty())
 	})
 })
 
-var _ = Describe("toleration validation", func() {
-	It("doesn't complain if we provide a proper toleration", func() {
-		recoveryCluster := &Cluster{
+var _ = Describe("storage configuration validation", func() {
+	It("complains if the size is being reduced", func() {
+		clusterOld := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					Tolerations: []corev1.Toleration{
-						{
-							Key:      "test",
-							Operator: "Exists",
-							Effect:   "NoSchedule",
-						},
-					},
+				StorageConfiguration: StorageConfiguration{
+					Size: "1G",
 				},
 			},
 		}
-		result := recoveryCluster.validateTolerations()
-		Expect(result).To(BeEmpty())
-	})
 
-	It("complain when the toleration ", func() {
-		recoveryCluster := &Cluster{
+		clusterNew := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					Tolerations: []corev1.Toleration{
-						{
-							Key:      "",
-							Operator: "Equal",
-							Effect:   "NoSchedule",
-						},
-					},
+				StorageConfiguration: StorageConfiguration{
+					Size: "512M",
 				},
 			},
 		}
-		result := recoveryCluster.validateTolerations()
-		Expect(result).ToNot(BeEmpty())
-	})
-})
 
-var _ = Describe("validate anti-affinity", func() {
-	t := true
-	f := false
-	It("doesn't complain if we provide an empty affinity section", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{},
-			},
-		}
-		result := cluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+		Expect(clusterNew.validateStorageChange(&clusterOld)).ToNot(BeEmpty())
 	})
-	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
-		cluster := &Cluster{
+
+	It("does not complain if nothing has been changed", func() {
+		one := "one"
+		clusterOld := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "required",
+				StorageConfiguration: StorageConfiguration{
+					Size:         "1G",
+					StorageClass: &one,
 				},
 			},
 		}
-		result := cluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+
+		clusterNew := clusterOld.DeepCopy()
+
+		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
 	})
 
-	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity disabled", func() {
-		recoveryCluster := &Cluster{
+	It("works fine is the size is being enlarged", func() {
+		clusterOld := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &f,
-					PodAntiAffinityType:   "required",
+				StorageConfiguration: StorageConfiguration{
+					Size: "8G",
 				},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
-	})
 
-	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
-		recoveryCluster := &Cluster{
+		clusterNew := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "preferred",
+				StorageConfiguration: StorageConfiguration{
+					Size: "10G",
 				},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+
+		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
 	})
-	It("doesn't complain if we provide a proper PodAntiAffinity default with anti-affinity enabled", func() {
-		recoveryCluster := &Cluster{
-			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "",
-				},
+})
+
+var _ = Describe("Cluster name validation", func() {
+	It("should be a valid DNS label", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test.one",
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateName()).ToNot(BeEmpty())
 	})
 
-	It("complains if we provide a wrong PodAntiAffinity with anti-affinity disabled", func() {
-		recoveryCluster := &Cluster{
-			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &f,
-					PodAntiAffinityType:   "error",
-				},
+	It("should not be too long", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi",
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).NotTo(BeEmpty())
+		Expect(cluster.validateName()).ToNot(BeEmpty())
 	})
 
-	It("complains if we provide a wrong PodAntiAffinity with anti-affinity enabled", func() {
-		recoveryCluster := &Cluster{
-			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "error",
-				},
+	It("should not raise errors when the name is ok", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi",
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).NotTo(BeEmpty())
+		Expect(cluster.validateName()).To(BeEmpty())
 	})
-})
 
-var _ = Describe("validation of the list of external clusters", func() {
-	It("is correct when it's empty", func() {
-		cluster := Cluster{}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	It("should return errors when the name is not DNS-1035 compliant", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "4b96d026-a956-47eb-bae8-a99b840805c3",
+			},
+		}
+		Expect(cluster.validateName()).NotTo(BeEmpty())
 	})
 
-	It("complains when the list of servers contains duplicates", func() {
+	It("should return errors when the name length is greater than 50", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: strings.Repeat("toomuchlong", 4) + "-" + "after4times",
+			},
+		}
+		Expect(cluster.validateName()).NotTo(BeEmpty())
+	})
+
+	It("should return errors when having a name with dots", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "wrong.name",
+			},
+		}
+		Expect(cluster.validateName()).NotTo(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the list of external clusters", func() {
+	It("is correct when it's empty", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	It("complains when the list of clusters contains duplicates", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				ExternalClusters: []ExternalCluster{
 					{
-						Name:                 "one",
-						ConnectionParameters: map[string]string{},
+						Name: "one",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 					{
-						Name:                 "one",
-						ConnectionParameters: map[string]string{},
+						Name: "one",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 				},
 			},
@@ -1990,17 +2105,21 @@ var _ = Describe("validation of the list of external clusters", func() {
 		Expect(cluster.validateExternalClusters()).ToNot(BeEmpty())
 	})
 
-	It("should not raise errors is the server name is unique", func() {
+	It("should not raise errors is the cluster name is unique", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				ExternalClusters: []ExternalCluster{
 					{
-						Name:                 "one",
-						ConnectionParameters: map[string]string{},
+						Name: "one",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 					{
-						Name:                 "two",
-						ConnectionParameters: map[string]string{},
+						Name: "two",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 				},
 			},
@@ -2009,118 +2128,155 @@ var _ = Describe("validation of the list of external clusters", func() {
 	})
 })
 
-var _ = Describe("bootstrap base backup validation", func() {
-	It("complain when the source cluster doesn't exist", func() {
-		bootstrap := BootstrapConfiguration{}
-		bpb := BootstrapPgBaseBackup{Source: "test"}
-		bootstrap.PgBaseBackup = &bpb
-		recoveryCluster := &Cluster{
+var _ = Describe("validation of an external cluster", func() {
+	It("ensure that one of connectionParameters and barmanObjectStore is set", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Source: "test",
-					},
+				ExternalClusters: []ExternalCluster{
+					{},
 				},
 			},
 		}
-		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
-		Expect(result).ToNot(BeEmpty())
+		Expect(cluster.validateExternalClusters()).To(Not(BeEmpty()))
+
+		cluster.Spec.ExternalClusters[0].ConnectionParameters = map[string]string{
+			"dbname": "postgres",
+		}
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+
+		cluster.Spec.ExternalClusters[0].ConnectionParameters = nil
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
 	})
 })
 
-var _ = Describe("unix permissions identifiers change validation", func() {
-	It("complains if the PostgresGID is changed", func() {
-		oldCluster := &Cluster{
+var _ = Describe("bootstrap base backup validation", func() {
+	It("complains if you specify the database name but not the owner for pg_basebackup", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				PostgresGID: defaultPostgresGID,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Database: "app",
+					},
+				},
 			},
 		}
-		cluster := &Cluster{
+
+		result := cluster.validatePgBaseBackupApplicationDatabase()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("complains if you specify the owner but not the database name for pg_basebackup", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				PostgresGID: 53,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Owner: "app",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+
+		result := cluster.validatePgBaseBackupApplicationDatabase()
+		Expect(result).To(HaveLen(1))
 	})
 
-	It("complains if the PostgresUID is changed", func() {
-		oldCluster := &Cluster{
+	It("doesn't complain if you specify both database name and owner user for pg_basebackup", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				PostgresUID: defaultPostgresUID,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Database: "app",
+						Owner:    "app",
+					},
+				},
 			},
 		}
-		cluster := &Cluster{
+
+		result := cluster.validatePgBaseBackupApplicationDatabase()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("doesn't complain if we are not bootstrapping using pg_basebackup", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				PostgresGID: 74,
+				Bootstrap: &BootstrapConfiguration{},
 			},
 		}
-		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("should not complain if the values havn't been changed", func() {
-		oldCluster := &Cluster{
+	It("complain when the source cluster doesn't exist", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				PostgresUID: 74,
-				PostgresGID: 76,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Source: "test",
+					},
+				},
 			},
 		}
-		cluster := &Cluster{
+		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
+		Expect(result).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("bootstrap recovery validation", func() {
+	It("complains if you specify the database name but not the owner for recovery", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				PostgresUID: 74,
-				PostgresGID: 76,
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Database: "app",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).To(BeEmpty())
+
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(result).To(HaveLen(1))
 	})
-})
 
-var _ = Describe("replica mode validation", func() {
-	It("complains if the bootstrap method is not specified", func() {
-		cluster := &Cluster{
+	It("complains if you specify the owner but not the database name for recovery", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Owner: "app",
 					},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(result).To(HaveLen(1))
 	})
 
-	It("complains if the initdb bootstrap method is used", func() {
-		cluster := &Cluster{
+	It("doesn't complain if you specify both database name and owner user for recovery", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+					Recovery: &BootstrapRecovery{
+						Database: "app",
+						Owner:    "app",
 					},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("is valid when the pg_basebackup bootstrap option is used", func() {
-		cluster := &Cluster{
+	It("does not complain when bootstrap recovery source matches one of the names of external clusters", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
 				},
 				ExternalClusters: []ExternalCluster{
 					{
@@ -2129,812 +2285,2597 @@ var _ = Describe("replica mode validation", func() {
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
-		Expect(result).To(BeEmpty())
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).To(BeEmpty())
 	})
 
-	It("is valid when the restore bootstrap option is used", func() {
-		cluster := &Cluster{
+	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{},
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
 				},
 				ExternalClusters: []ExternalCluster{
 					{
-						Name: "test",
+						Name: "another-test",
 					},
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
-		Expect(result).To(BeEmpty())
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).ToNot(BeEmpty())
 	})
+})
 
-	It("complains when the external cluster doesn't exist", func() {
-		cluster := &Cluster{
+var _ = Describe("validation of the throttling of the WAL restore", func() {
+	newCluster := func(maxBandwidth *int64) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					Recovery: &BootstrapRecovery{Source: "origin", WalRestoreMaxBandwidth: maxBandwidth},
 				},
-				ExternalClusters: []ExternalCluster{},
 			},
 		}
+	}
 
-		cluster.Spec.Bootstrap.PgBaseBackup = nil
-		result := cluster.validateReplicaMode()
-		Expect(result).ToNot(BeEmpty())
+	It("accepts a recovery without throttling", func() {
+		Expect(newCluster(nil).validateBootstrapRecoveryWalRestore()).To(BeEmpty())
+		Expect((&Cluster{}).validateBootstrapRecoveryWalRestore()).To(BeEmpty())
 	})
 
-	It("complains when enabled on an existing cluster with no replica mode configured", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{},
-		}
+	It("accepts a positive bandwidth limit", func() {
+		Expect(newCluster(ptr.To(int64(1048576))).validateBootstrapRecoveryWalRestore()).To(BeEmpty())
+	})
+
+	It("rejects a non positive bandwidth limit", func() {
+		for _, maxBandwidth := range []int64{0, -1} {
+			result := newCluster(ptr.To(maxBandwidth)).validateBootstrapRecoveryWalRestore()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.walRestoreMaxBandwidth"))
+		}
+	})
+})
+
+var _ = Describe("validation of the recovery from a backup volume", func() {
+	newCluster := func(recovery *BootstrapRecovery) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: recovery,
+				},
+			},
+		}
+	}
+
+	It("accepts a recovery from a backup volume", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			VolumeBackup: &VolumeBackupSource{ClaimName: "origin-backup"},
+		})
+		Expect(cluster.validateBootstrapRecoveryVolumeBackup()).To(BeEmpty())
+		Expect((&Cluster{}).validateBootstrapRecoveryVolumeBackup()).To(BeEmpty())
+	})
+
+	It("complains when used together with other types of recovery", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			Source:       "origin",
+			VolumeBackup: &VolumeBackupSource{ClaimName: "origin-backup"},
+		})
+		result := cluster.validateBootstrapRecoveryVolumeBackup()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.volumeBackup"))
+	})
+
+	It("requires the name of the backup volume", func() {
+		cluster := newCluster(&BootstrapRecovery{VolumeBackup: &VolumeBackupSource{}})
+		result := cluster.validateBootstrapRecoveryVolumeBackup()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.volumeBackup.claimName"))
+	})
+
+	It("rejects the volume that will store the backups of the cluster", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			VolumeBackup: &VolumeBackupSource{ClaimName: "cluster-example-backup"},
+		})
+		cluster.Spec.Backup = &BackupConfiguration{
+			VolumeBackup: &VolumeBackupConfiguration{Storage: StorageConfiguration{Size: "10Gi"}},
+		}
+		result := cluster.validateBootstrapRecoveryVolumeBackup()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.volumeBackup.claimName"))
+	})
+
+	It("rejects the throttling of the WAL restore", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			VolumeBackup:           &VolumeBackupSource{ClaimName: "origin-backup"},
+			WalRestoreMaxBandwidth: ptr.To(int64(1048576)),
+		})
+		result := cluster.validateBootstrapRecoveryVolumeBackup()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.walRestoreMaxBandwidth"))
+	})
+})
+
+var _ = Describe("validation of the backup to recover from", func() {
+	newCluster := func(recovery *BootstrapRecovery) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: recovery,
+				},
+			},
+		}
+	}
+
+	It("accepts a backup ID", func() {
+		cluster := newCluster(&BootstrapRecovery{Source: "origin", BackupID: "20240101T120000"})
+		Expect(cluster.validateBootstrapRecoveryBackupSelection()).To(BeEmpty())
+	})
+
+	It("accepts skipping the latest backups", func() {
+		cluster := newCluster(&BootstrapRecovery{Source: "origin", SkipLatest: 1})
+		Expect(cluster.validateBootstrapRecoveryBackupSelection()).To(BeEmpty())
+	})
+
+	It("accepts a recovery choosing the backup automatically", func() {
+		Expect(newCluster(&BootstrapRecovery{Source: "origin"}).validateBootstrapRecoveryBackupSelection()).
+			To(BeEmpty())
+		Expect((&Cluster{}).validateBootstrapRecoveryBackupSelection()).To(BeEmpty())
+	})
+
+	It("rejects a backup ID together with skipLatest", func() {
+		cluster := newCluster(&BootstrapRecovery{Source: "origin", BackupID: "20240101T120000", SkipLatest: 1})
+		result := cluster.validateBootstrapRecoveryBackupSelection()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.skipLatest"))
+	})
+
+	It("rejects a negative number of backups to skip", func() {
+		cluster := newCluster(&BootstrapRecovery{Source: "origin", SkipLatest: -1})
+		Expect(cluster.validateBootstrapRecoveryBackupSelection()).To(HaveLen(1))
+	})
+
+	It("rejects a backup ID set in the recovery target too", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			Source:         "origin",
+			SkipLatest:     1,
+			RecoveryTarget: &RecoveryTarget{BackupID: "20240101T120000"},
+		})
+		result := cluster.validateBootstrapRecoveryBackupSelection()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.backupID"))
+	})
+
+	It("requires the recovery from an external cluster", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			Backup:   &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+			BackupID: "20240101T120000",
+		})
+		result := cluster.validateBootstrapRecoveryBackupSelection()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.source"))
+	})
+
+	It("accepts choosing the backup when recovering from a backup volume", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			VolumeBackup: &VolumeBackupSource{ClaimName: "origin-backup"},
+			BackupID:     "backup-20240101T120000",
+		})
+		Expect(cluster.validateBootstrapRecoveryBackupSelection()).To(BeEmpty())
+	})
+
+	It("accepts the backup ID of the recovery section to perform PITR with TargetName", func() {
+		cluster := newCluster(&BootstrapRecovery{
+			Source:         "origin",
+			BackupID:       "20240101T120000",
+			RecoveryTarget: &RecoveryTarget{TargetName: "restore_point_1"},
+		})
+		Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+	})
+
+	It("gets the ID of the backup to recover from", func() {
+		Expect((*BootstrapRecovery)(nil).GetBackupID()).To(BeEmpty())
+		Expect((&BootstrapRecovery{}).GetBackupID()).To(BeEmpty())
+		Expect((&BootstrapRecovery{BackupID: "a"}).GetBackupID()).To(Equal("a"))
+		Expect((&BootstrapRecovery{RecoveryTarget: &RecoveryTarget{BackupID: "b"}}).GetBackupID()).To(Equal("b"))
+	})
+})
+
+var _ = Describe("toleration validation", func() {
+	It("doesn't complain if we provide a proper toleration", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "test",
+							Operator: "Exists",
+							Effect:   "NoSchedule",
+						},
+					},
+				},
+			},
+		}
+		result := recoveryCluster.validateTolerations()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complain when the toleration ", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "",
+							Operator: "Equal",
+							Effect:   "NoSchedule",
+						},
+					},
+				},
+			},
+		}
+		result := recoveryCluster.validateTolerations()
+		Expect(result).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("validate anti-affinity", func() {
+	t := true
+	f := false
+	It("doesn't complain if we provide an empty affinity section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{},
+			},
+		}
+		result := cluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
+	})
+	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "required",
+				},
+			},
+		}
+		result := cluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity disabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &f,
+					PodAntiAffinityType:   "required",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "preferred",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
+	})
+	It("doesn't complain if we provide a proper PodAntiAffinity default with anti-affinity enabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains if we provide a wrong PodAntiAffinity with anti-affinity disabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &f,
+					PodAntiAffinityType:   "error",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).NotTo(BeEmpty())
+	})
+
+	It("complains if we provide a wrong PodAntiAffinity with anti-affinity enabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "error",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).NotTo(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the list of external clusters", func() {
+	It("is correct when it's empty", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	It("complains when the list of servers contains duplicates", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                 "one",
+						ConnectionParameters: map[string]string{},
+					},
+					{
+						Name:                 "one",
+						ConnectionParameters: map[string]string{},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).ToNot(BeEmpty())
+	})
+
+	It("should not raise errors is the server name is unique", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                 "one",
+						ConnectionParameters: map[string]string{},
+					},
+					{
+						Name:                 "two",
+						ConnectionParameters: map[string]string{},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("bootstrap base backup validation", func() {
+	It("complain when the source cluster doesn't exist", func() {
+		bootstrap := BootstrapConfiguration{}
+		bpb := BootstrapPgBaseBackup{Source: "test"}
+		bootstrap.PgBaseBackup = &bpb
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Source: "test",
+					},
+				},
+			},
+		}
+		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
+		Expect(result).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("unix permissions identifiers change validation", func() {
+	It("complains if the PostgresGID is changed", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresGID: defaultPostgresGID,
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresGID: 53,
+			},
+		}
+		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+	})
+
+	It("complains if the PostgresUID is changed", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresUID: defaultPostgresUID,
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresGID: 74,
+			},
+		}
+		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+	})
+
+	It("should not complain if the values havn't been changed", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresUID: 74,
+				PostgresGID: 76,
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresUID: 74,
+				PostgresGID: 76,
+			},
+		}
+		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replica mode validation", func() {
+	It("complains if the bootstrap method is not specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("complains if the initdb bootstrap method is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("is valid when the pg_basebackup bootstrap option is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("is valid when the restore bootstrap option is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains when the external cluster doesn't exist", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{},
+			},
+		}
+
+		cluster.Spec.Bootstrap.PgBaseBackup = nil
+		result := cluster.validateReplicaMode()
+		Expect(result).ToNot(BeEmpty())
+	})
+
+	It("complains when enabled on an existing cluster with no replica mode configured", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+	})
+
+	It("complains when enabled on an existing cluster with replica mode disabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: false,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+	})
+
+	Context("when the promotion is blocked", func() {
+		var oldCluster *Cluster
+
+		BeforeEach(func() {
+			oldCluster = &Cluster{
+				Spec: ClusterSpec{
+					ReplicaCluster: &ReplicaClusterConfiguration{
+						Enabled:          true,
+						Source:           "test",
+						PromotionBlocked: true,
+					},
+					Bootstrap: &BootstrapConfiguration{
+						PgBaseBackup: &BootstrapPgBaseBackup{},
+					},
+					ExternalClusters: []ExternalCluster{
+						{Name: "test"},
+					},
+				},
+			}
+		})
+
+		It("is valid on a replica cluster", func() {
+			Expect(oldCluster.validateReplicaMode()).To(BeEmpty())
+			Expect(oldCluster.IsPromotionBlocked()).To(BeTrue())
+		})
+
+		It("complains when the replica mode is disabled", func() {
+			cluster := oldCluster.DeepCopy()
+			cluster.Spec.ReplicaCluster.Enabled = false
+			Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+			Expect(cluster.IsPromotionBlocked()).To(BeFalse())
+		})
+
+		It("refuses to promote the replica cluster, even when removing the flag", func() {
+			cluster := oldCluster.DeepCopy()
+			cluster.Spec.ReplicaCluster.Enabled = false
+			cluster.Spec.ReplicaCluster.PromotionBlocked = false
+			Expect(cluster.validateReplicaModeChange(oldCluster)).To(HaveLen(1))
+
+			cluster.Spec.ReplicaCluster = nil
+			Expect(cluster.validateReplicaModeChange(oldCluster)).To(HaveLen(1))
+		})
+
+		It("promotes the replica cluster after the flag has been removed", func() {
+			unblockedCluster := oldCluster.DeepCopy()
+			unblockedCluster.Spec.ReplicaCluster.PromotionBlocked = false
+			Expect(unblockedCluster.validateReplicaModeChange(oldCluster)).To(BeEmpty())
+
+			cluster := unblockedCluster.DeepCopy()
+			cluster.Spec.ReplicaCluster.Enabled = false
+			Expect(cluster.validateReplicaModeChange(unblockedCluster)).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("Validation changes", func() {
+	It("doesn't complain if given old cluster is nil", func() {
+		newCluster := &Cluster{}
+		err := newCluster.ValidateChanges(nil)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("additional WAL object stores validation", func() {
+	s3Credentials := BarmanCredentials{
+		AWS: &S3Credentials{InheritFromIAMRole: true},
+	}
+	googleCredentials := BarmanCredentials{
+		Google: &GoogleCredentials{GKEEnvironment: true},
+	}
+
+	newCluster := func(additional ...BarmanObjectStoreConfiguration) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath:   "s3://primary-bucket/",
+						BarmanCredentials: s3Credentials,
+					},
+					AdditionalWalObjectStores: additional,
+				},
+			},
+		}
+	}
+
+	It("accepts clusters without additional object stores", func() {
+		Expect((&Cluster{}).validateAdditionalWalObjectStores()).To(BeEmpty())
+		Expect(newCluster().validateAdditionalWalObjectStores()).To(BeEmpty())
+	})
+
+	It("accepts an additional object store with a quorum", func() {
+		cluster := newCluster(BarmanObjectStoreConfiguration{
+			DestinationPath:   "gs://secondary-bucket/",
+			BarmanCredentials: googleCredentials,
+		})
+		cluster.Spec.Backup.WalArchiveQuorum = ptr.To(1)
+		Expect(cluster.validateAdditionalWalObjectStores()).To(BeEmpty())
+		Expect(cluster.Spec.Backup.GetWalObjectStores()).To(HaveLen(2))
+		Expect(cluster.Spec.Backup.GetWalArchiveQuorum()).To(Equal(1))
+
+		cluster.Spec.Backup.WalArchiveQuorum = nil
+		Expect(cluster.Spec.Backup.GetWalArchiveQuorum()).To(Equal(2))
+	})
+
+	It("requires barmanObjectStore", func() {
+		cluster := newCluster(BarmanObjectStoreConfiguration{
+			DestinationPath:   "gs://secondary-bucket/",
+			BarmanCredentials: googleCredentials,
+		})
+		cluster.Spec.Backup.BarmanObjectStore = nil
+		cluster.Spec.Backup.WalArchiveQuorum = ptr.To(1)
+		Expect(cluster.validateAdditionalWalObjectStores()).To(HaveLen(2))
+	})
+
+	It("requires one and only one kind of credentials", func() {
+		result := newCluster(
+			BarmanObjectStoreConfiguration{DestinationPath: "s3://secondary-bucket/"},
+			BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://third-bucket/",
+				BarmanCredentials: BarmanCredentials{
+					AWS:    &S3Credentials{InheritFromIAMRole: true},
+					Google: &GoogleCredentials{GKEEnvironment: true},
+				},
+			},
+		).validateAdditionalWalObjectStores()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalWalObjectStores[0]"))
+		Expect(result[1].Field).To(Equal("spec.backup.additionalWalObjectStores[1]"))
+	})
+
+	It("rejects the object stores archiving in the same location", func() {
+		result := newCluster(BarmanObjectStoreConfiguration{
+			DestinationPath:   "s3://primary-bucket",
+			ServerName:        "cluster-example",
+			BarmanCredentials: s3Credentials,
+		}).validateAdditionalWalObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalWalObjectStores[0].destinationPath"))
+
+		Expect(newCluster(BarmanObjectStoreConfiguration{
+			DestinationPath:   "s3://primary-bucket",
+			ServerName:        "cluster-example-copy",
+			BarmanCredentials: s3Credentials,
+		}).validateAdditionalWalObjectStores()).To(BeEmpty())
+	})
+
+	It("rejects the CA bundle of the endpoint and more Google credentials", func() {
+		cluster := newCluster(
+			BarmanObjectStoreConfiguration{
+				DestinationPath:   "gs://secondary-bucket/",
+				BarmanCredentials: googleCredentials,
+				EndpointCA:        &SecretKeySelector{Key: "ca.crt"},
+			},
+			BarmanObjectStoreConfiguration{
+				DestinationPath:   "gs://third-bucket/",
+				BarmanCredentials: googleCredentials,
+			},
+		)
+		result := cluster.validateAdditionalWalObjectStores()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalWalObjectStores[0].endpointCA"))
+		Expect(result[1].Field).To(Equal("spec.backup.additionalWalObjectStores"))
+	})
+
+	It("rejects a quorum greater than the number of object stores", func() {
+		cluster := newCluster(BarmanObjectStoreConfiguration{
+			DestinationPath:   "gs://secondary-bucket/",
+			BarmanCredentials: googleCredentials,
+		})
+		cluster.Spec.Backup.WalArchiveQuorum = ptr.To(3)
+		result := cluster.validateAdditionalWalObjectStores()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.walArchiveQuorum"))
+
+		cluster.Spec.Backup.WalArchiveQuorum = ptr.To(0)
+		Expect(cluster.validateAdditionalWalObjectStores()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Backup validation", func() {
+	It("complain if there's no credentials", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+	})
+
+	It("doesn't complain if given policy is not provided", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(BeNil())
+	})
+
+	It("doesn't complain if given policy is valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					RetentionPolicy: "90d",
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(BeNil())
+	})
+
+	It("complain if a given policy is not valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					RetentionPolicy:   "09",
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(2))
+	})
+
+	Context("envelope encryption", func() {
+		const keyARN = "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+		var cluster *Cluster
+
+		BeforeEach(func() {
+			cluster = &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+							DestinationPath: "s3://backups/",
+							Encryption: &BarmanEncryptionConfiguration{
+								Method: BarmanEncryptionMethodSSEKMS,
+								KeyID:  keyARN,
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("accepts a valid configuration", func() {
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("accepts the IDs and the aliases of the keys", func() {
+			for _, keyID := range []string{
+				"1234abcd-12ab-34cd-56ef-1234567890ab",
+				"mrk-1234abcd12ab34cd56ef1234567890ab",
+				"alias/backups",
+				"arn:aws:kms:eu-west-1:123456789012:alias/backups",
+			} {
+				cluster.Spec.Backup.BarmanObjectStore.Encryption.KeyID = keyID
+				Expect(cluster.validateBackupConfiguration()).To(BeEmpty(), keyID)
+			}
+		})
+
+		It("complains about an invalid key ID", func() {
+			cluster.Spec.Backup.BarmanObjectStore.Encryption.KeyID = "my key"
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+
+		It("complains about an unsupported method or provider", func() {
+			cluster.Spec.Backup.BarmanObjectStore.Encryption.Method = "client-side"
+			cluster.Spec.Backup.BarmanObjectStore.Encryption.Provider = "vault"
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(2))
+		})
+
+		It("requires an S3 object store", func() {
+			cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials = BarmanCredentials{
+				Azure: &AzureCredentials{InheritFromAzureAD: true},
+			}
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+
+		It("complains about conflicting encryption options", func() {
+			cluster.Spec.Backup.BarmanObjectStore.Wal = &WalBackupConfiguration{
+				Encryption: EncryptionTypeAES256,
+			}
+			cluster.Spec.Backup.BarmanObjectStore.Data = &DataBackupConfiguration{
+				Encryption: EncryptionTypeNoneAWSKMS,
+			}
+			errs := cluster.validateBackupConfiguration()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.wal.encryption"))
+		})
+	})
+
+	Context("bandwidth limit", func() {
+		var cluster *Cluster
+
+		BeforeEach(func() {
+			cluster = &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("accepts a positive bandwidth limit", func() {
+			cluster.Spec.Backup.BarmanObjectStore.MaxBandwidth = ptr.To(int64(10485760))
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains about a non positive bandwidth limit", func() {
+			for _, maxBandwidth := range []int64{0, -1} {
+				cluster.Spec.Backup.BarmanObjectStore.MaxBandwidth = ptr.To(maxBandwidth)
+				errs := cluster.validateBackupConfiguration()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.maxBandwidth"))
+			}
+		})
+	})
+
+	Context("server name", func() {
+		var cluster *Cluster
+
+		BeforeEach(func() {
+			cluster = &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("accepts the server names that are safe as a folder", func() {
+			for _, serverName := range []string{"", "cluster-example", "cluster_example.v2", "2024"} {
+				cluster.Spec.Backup.BarmanObjectStore.ServerName = serverName
+				Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+			}
+		})
+
+		It("complains about the server names escaping the destination path", func() {
+			for _, serverName := range []string{"..", "../other", "team/cluster", ".hidden", "cluster example", "a\\b"} {
+				cluster.Spec.Backup.BarmanObjectStore.ServerName = serverName
+				errs := cluster.validateBackupConfiguration()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.serverName"))
+			}
+		})
+
+		It("validates the server names of the external clusters", func() {
+			cluster.Spec.ExternalClusters = []ExternalCluster{
+				{
+					Name: "origin",
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket/path",
+						ServerName:      "../origin",
+					},
+				},
+			}
+			errs := cluster.validateExternalClusters()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.externalClusters[0].barmanObjectStore.serverName"))
+		})
+	})
+})
+
+var _ = Describe("Default monitoring queries", func() {
+	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
+		cluster := &Cluster{}
+		cluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret:    "test-secret",
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  DefaultMonitoringKey,
+			}))
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  DefaultMonitoringKey,
+			}))
+	})
+	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+		CustomQueriesConfigMap: []ConfigMapKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			},
+		},
+		CustomQueriesSecret: []SecretKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test3",
+			},
+		},
+	}}}
+	It("correctly set the default monitoring queries configmap when other metrics are already specified", func() {
+		modifiedCluster := testCluster.DeepCopy()
+		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+
+		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			}))
+
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesSecret))
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+	})
+	It("correctly set the default monitoring queries secret when other metrics are already specified", func() {
+		modifiedCluster := testCluster.DeepCopy()
+		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret: "test-secret",
+		})
+
+		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  "test3",
+			}))
+
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesSecret))
+	})
+})
+
+var _ = Describe("validation of imports", func() {
+	It("rejects unrecognized import type", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type: "fooBar",
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import with roles", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import without exactly one database", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo", "bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import with a wildcard on the database name", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"*foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts microservice import when well specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("rejects monolith import with no databases", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects monolith import with PostImport Application SQL", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:                     MonolithSnapshotType,
+							Databases:                []string{"foo"},
+							PostImportApplicationSQL: []string{"select * from bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects monolith import with wildcards alongside specific values", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"bar", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"baz", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result = cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts monolith import with proper values", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+						},
+					},
 				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("accepts monolith import with wildcards", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"*"},
+							Roles:     []string{"*"},
+						},
+					},
 				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the WAL retention cap of the replication slots", func() {
+	newCluster := func(imageName string, size string) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				ImageName: imageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					MaxSlotWALKeepSize: size,
+				},
+			},
+		}
+	}
+
+	It("accepts a valid size", func() {
+		Expect(newCluster(versions.DefaultImageName, "10Gi").validateMaxSlotWALKeepSize()).To(BeEmpty())
+		Expect(newCluster(versions.DefaultImageName, MinMaxSlotWALKeepSize).validateMaxSlotWALKeepSize()).To(BeEmpty())
+	})
+
+	It("accepts a cluster without the cap", func() {
+		Expect(newCluster(versions.DefaultImageName, "").validateMaxSlotWALKeepSize()).To(BeEmpty())
+		Expect((&Cluster{}).validateMaxSlotWALKeepSize()).To(BeEmpty())
+	})
+
+	It("rejects an invalid size", func() {
+		Expect(newCluster(versions.DefaultImageName, "ten gigabytes").validateMaxSlotWALKeepSize()).To(HaveLen(1))
+	})
+
+	It("rejects a size lower than the minimum", func() {
+		result := newCluster(versions.DefaultImageName, "512Mi").validateMaxSlotWALKeepSize()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicationSlots.maxSlotWALKeepSize"))
+	})
+
+	It("rejects the cap together with the max_slot_wal_keep_size parameter", func() {
+		cluster := newCluster(versions.DefaultImageName, "10Gi")
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"max_slot_wal_keep_size": "20GB",
+		}
+		Expect(cluster.validateMaxSlotWALKeepSize()).To(HaveLen(1))
+	})
+
+	It("rejects the cap on PostgreSQL 12 and older", func() {
+		Expect(newCluster("ghcr.io/cloudnative-pg/postgresql:12.16", "10Gi").validateMaxSlotWALKeepSize()).
+			To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the WAL segment size", func() {
+	newCluster := func(walSegmentSize, initDBWalSegmentSize int) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					WalSegmentSize: walSegmentSize,
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						WalSegmentSize: initDBWalSegmentSize,
+					},
+				},
+			},
+		}
+	}
+
+	It("accepts a power of two", func() {
+		Expect(newCluster(64, 0).validateWalSegmentSize()).To(BeEmpty())
+		Expect(newCluster(0, 0).validateWalSegmentSize()).To(BeEmpty())
+		Expect(newCluster(64, 64).validateWalSegmentSize()).To(BeEmpty())
+	})
+
+	It("rejects a value that is not a power of two", func() {
+		result := newCluster(48, 0).validateWalSegmentSize()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.walSegmentSize"))
+	})
+
+	It("rejects a value different from the one of the initdb section", func() {
+		Expect(newCluster(64, 32).validateWalSegmentSize()).To(HaveLen(1))
+	})
+
+	It("accepts the updates keeping the WAL segment size", func() {
+		Expect(newCluster(64, 0).validateWalSegmentSizeChange(newCluster(64, 0))).To(BeEmpty())
+		Expect(newCluster(64, 0).validateWalSegmentSizeChange(newCluster(0, 64))).To(BeEmpty())
+		Expect(newCluster(0, 0).validateWalSegmentSizeChange(newCluster(0, 0))).To(BeEmpty())
+	})
+
+	It("rejects the changes of the WAL segment size", func() {
+		result := newCluster(64, 0).validateWalSegmentSizeChange(newCluster(32, 0))
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.walSegmentSize"))
+
+		result = newCluster(0, 64).validateWalSegmentSizeChange(newCluster(0, 0))
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.walSegmentSize"))
+
+		Expect(newCluster(64, 0).validateWalSegmentSizeChange(newCluster(0, 0))).To(HaveLen(1))
+	})
+
+	It("accepts declaring the WAL segment size already in use", func() {
+		oldCluster := newCluster(0, 0)
+		oldCluster.Status.WalSegmentSize = 16
+		Expect(newCluster(16, 0).validateWalSegmentSizeChange(oldCluster)).To(BeEmpty())
+		Expect(newCluster(32, 0).validateWalSegmentSizeChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("rejects the changes through the whole update validation", func() {
+		Expect(newCluster(64, 0).ValidateChanges(newCluster(32, 0))).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the autovacuum settings", func() {
+	newCluster := func(autovacuum *AutovacuumConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Autovacuum: autovacuum,
+				},
+			},
+		}
+	}
+
+	It("accepts a cluster without the autovacuum settings", func() {
+		Expect(newCluster(nil).validateAutovacuum()).To(BeEmpty())
+	})
+
+	It("accepts the naptimes within the range of PostgreSQL", func() {
+		for _, naptime := range []string{"1", "30s", "1min", "1000ms", "2h", "1d", "2147483s"} {
+			Expect(newCluster(&AutovacuumConfiguration{Naptime: naptime}).validateAutovacuum()).
+				To(BeEmpty(), naptime)
+		}
+	})
+
+	It("rejects the invalid naptimes", func() {
+		for _, naptime := range []string{"0", "999ms", "1 min", "1m", "-1s", "25000d", "99999999999999999999"} {
+			result := newCluster(&AutovacuumConfiguration{Naptime: naptime}).validateAutovacuum()
+			Expect(result).To(HaveLen(1), naptime)
+			Expect(result[0].Field).To(Equal("spec.postgresql.autovacuum.naptime"))
+		}
+	})
+
+	It("rejects the settings together with the corresponding parameters", func() {
+		cluster := newCluster(&AutovacuumConfiguration{
+			MaxWorkers:      ptr.To(int32(6)),
+			VacuumCostLimit: ptr.To(int32(2000)),
+		})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"autovacuum_max_workers": "3",
+			"autovacuum_naptime":     "1min",
+		}
+		result := cluster.validateAutovacuum()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.autovacuum.maxWorkers"))
+	})
+
+	It("warns only about the changes requiring a restart", func() {
+		oldCluster := newCluster(&AutovacuumConfiguration{
+			MaxWorkers:      ptr.To(int32(3)),
+			VacuumCostLimit: ptr.To(int32(200)),
+		})
+
+		cluster := newCluster(&AutovacuumConfiguration{
+			MaxWorkers:      ptr.To(int32(3)),
+			VacuumCostLimit: ptr.To(int32(2000)),
+			Naptime:         "30s",
+		})
+		Expect(cluster.getAutovacuumRestartWarnings(oldCluster)).To(BeEmpty())
+
+		cluster.Spec.PostgresConfiguration.Autovacuum.MaxWorkers = ptr.To(int32(6))
+		warnings := cluster.getAutovacuumRestartWarnings(oldCluster)
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("spec.postgresql.autovacuum.maxWorkers"))
+		Expect(warnings[0]).To(ContainSubstring("autovacuum_max_workers requires a restart"))
+
+		Expect(newCluster(nil).getAutovacuumRestartWarnings(oldCluster)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the time zones and of the date style", func() {
+	newCluster := func(timezone, logTimezone, dateStyle string) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Timezone:    timezone,
+					LogTimezone: logTimezone,
+					DateStyle:   dateStyle,
+				},
+			},
+		}
+	}
+
+	It("accepts a cluster without the time zones and the date style", func() {
+		Expect(newCluster("", "", "").validateDateTimeSettings()).To(BeEmpty())
+	})
+
+	It("accepts the names of the IANA time zone database", func() {
+		Expect(newCluster("Europe/Rome", "UTC", "").validateDateTimeSettings()).To(BeEmpty())
+		Expect(newCluster("America/New_York", "Etc/GMT+3", "").validateDateTimeSettings()).To(BeEmpty())
+	})
+
+	It("rejects the unknown time zones", func() {
+		result := newCluster("Europe/Atlantis", "", "").validateDateTimeSettings()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.timezone"))
+
+		result = newCluster("", "Local", "").validateDateTimeSettings()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.logTimezone"))
+	})
+
+	It("accepts the date styles supported by PostgreSQL", func() {
+		for _, dateStyle := range []string{"ISO, MDY", "iso,dmy", "German", "YMD", "SQL, European"} {
+			Expect(newCluster("", "", dateStyle).validateDateTimeSettings()).To(BeEmpty(), dateStyle)
+		}
+	})
+
+	It("rejects the invalid date styles", func() {
+		for _, dateStyle := range []string{"ISO8601", "ISO, SQL", "DMY, MDY", "ISO,"} {
+			result := newCluster("", "", dateStyle).validateDateTimeSettings()
+			Expect(result).To(HaveLen(1), dateStyle)
+			Expect(result[0].Field).To(Equal("spec.postgresql.dateStyle"))
+		}
+	})
+
+	It("rejects the settings together with the corresponding parameters", func() {
+		cluster := newCluster("Europe/Rome", "UTC", "ISO, DMY")
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"timezone":     "UTC",
+			"log_timezone": "UTC",
+			"DateStyle":    "ISO, MDY",
+		}
+		Expect(cluster.validateDateTimeSettings()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("validation of the default isolation level of the transactions", func() {
+	newCluster := func(level TransactionIsolationLevel) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					DefaultTransactionIsolation: level,
+				},
+			},
+		}
+	}
+
+	It("accepts the isolation levels supported by PostgreSQL", func() {
+		Expect(newCluster(TransactionIsolationReadUncommitted).validateDefaultTransactionIsolation()).To(BeEmpty())
+		Expect(newCluster(TransactionIsolationReadCommitted).validateDefaultTransactionIsolation()).To(BeEmpty())
+		Expect(newCluster(TransactionIsolationRepeatableRead).validateDefaultTransactionIsolation()).To(BeEmpty())
+		Expect(newCluster(TransactionIsolationSerializable).validateDefaultTransactionIsolation()).To(BeEmpty())
+	})
+
+	It("accepts a cluster without the isolation level", func() {
+		Expect(newCluster("").validateDefaultTransactionIsolation()).To(BeEmpty())
+	})
+
+	It("rejects the invalid isolation levels", func() {
+		for _, level := range []TransactionIsolationLevel{"snapshot", "REPEATABLE READ", "repeatable_read"} {
+			result := newCluster(level).validateDefaultTransactionIsolation()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.postgresql.defaultTransactionIsolation"))
+		}
+	})
+
+	It("rejects the isolation level together with the default_transaction_isolation parameter", func() {
+		cluster := newCluster(TransactionIsolationRepeatableRead)
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"default_transaction_isolation": "serializable",
+		}
+		Expect(cluster.validateDefaultTransactionIsolation()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the read-only replicas", func() {
+	It("accepts the read-only replicas", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ReadOnlyReplicas: true,
+				},
+			},
+		}
+		Expect(cluster.validateReadOnlyReplicas()).To(BeEmpty())
+	})
+
+	It("accepts the default_transaction_read_only parameter without the read-only replicas", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"default_transaction_read_only": "on",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReadOnlyReplicas()).To(BeEmpty())
+	})
+
+	It("rejects the read-only replicas together with the default_transaction_read_only parameter", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ReadOnlyReplicas: true,
+					Parameters: map[string]string{
+						"default_transaction_read_only": "off",
+					},
+				},
+			},
+		}
+		result := cluster.validateReadOnlyReplicas()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.readOnlyReplicas"))
+	})
+})
+
+var _ = Describe("validation of the statement and lock timeouts", func() {
+	newCluster := func(timeouts *TimeoutsConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Timeouts: timeouts,
+				},
+			},
+		}
+	}
+
+	It("accepts a cluster without timeouts", func() {
+		Expect(newCluster(nil).validateTimeouts()).To(BeEmpty())
+		Expect(newCluster(&TimeoutsConfiguration{}).validateTimeouts()).To(BeEmpty())
+	})
+
+	It("accepts the timeouts in the PostgreSQL format", func() {
+		cluster := newCluster(&TimeoutsConfiguration{
+			StatementTimeout: "30s",
+			LockTimeout:      "500",
+			Roles: []RoleTimeoutsConfiguration{
+				{Name: "migrator", StatementTimeout: "0", LockTimeout: "1min"},
+				{Name: "reporting", StatementTimeout: "2h"},
+			},
+		})
+		Expect(cluster.validateTimeouts()).To(BeEmpty())
+	})
+
+	It("rejects the invalid timeouts", func() {
+		for _, value := range []string{"-1", "30 s", "1.5s", "30sec", "forever"} {
+			result := newCluster(&TimeoutsConfiguration{StatementTimeout: value}).validateTimeouts()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.postgresql.timeouts.statementTimeout"))
+		}
+
+		result := newCluster(&TimeoutsConfiguration{
+			Roles: []RoleTimeoutsConfiguration{{Name: "migrator", LockTimeout: "1 minute"}},
+		}).validateTimeouts()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.timeouts.roles[0].lockTimeout"))
+	})
+
+	It("rejects the timeouts together with the corresponding parameters", func() {
+		cluster := newCluster(&TimeoutsConfiguration{StatementTimeout: "30s"})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"statement_timeout": "1min",
+			"lock_timeout":      "5s",
+		}
+		result := cluster.validateTimeouts()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.timeouts.statementTimeout"))
+	})
+
+	It("rejects the roles without a name or overridden twice", func() {
+		result := newCluster(&TimeoutsConfiguration{
+			Roles: []RoleTimeoutsConfiguration{
+				{Name: "migrator", StatementTimeout: "0"},
+				{StatementTimeout: "0"},
+				{Name: "migrator", LockTimeout: "0"},
+			},
+		}).validateTimeouts()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.timeouts.roles[1].name"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.timeouts.roles[2].name"))
+	})
+})
+
+var _ = Describe("validation of replication slots configuration", func() {
+	It("prevents using replication slots on PostgreSQL 10 and older", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: ptr.To(true),
+					},
+					UpdateInterval: 0,
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+		cluster.Default()
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(HaveLen(1))
 	})
 
-	It("complains when enabled on an existing cluster with replica mode disabled", func() {
-		oldCluster := &Cluster{
+	It("can be enabled on the default PostgreSQL image", func() {
+		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: false,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: ptr.To(true),
+					},
+					UpdateInterval: 0,
 				},
 			},
 		}
+		cluster.Default()
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("set replicationSlots by default", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
-				},
+				ImageName: versions.DefaultImageName,
 			},
 		}
-		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
-	})
-})
+		cluster.Default()
+		Expect(cluster.Spec.ReplicationSlots).ToNot(BeNil())
+		Expect(cluster.Spec.ReplicationSlots.HighAvailability).ToNot(BeNil())
+		Expect(cluster.Spec.ReplicationSlots.HighAvailability.Enabled).To(HaveValue(BeTrue()))
 
-var _ = Describe("Validation changes", func() {
-	It("doesn't complain if given old cluster is nil", func() {
-		newCluster := &Cluster{}
-		err := newCluster.ValidateChanges(nil)
-		Expect(err).To(BeNil())
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(BeEmpty())
 	})
-})
 
-var _ = Describe("Backup validation", func() {
-	It("complain if there's no credentials", func() {
+	It("set replicationSlots.highAvailability by default", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					UpdateInterval: 30,
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(HaveLen(1))
+		cluster.Default()
+		Expect(cluster.Spec.ReplicationSlots.HighAvailability).ToNot(BeNil())
+		Expect(cluster.Spec.ReplicationSlots.HighAvailability.Enabled).To(HaveValue(BeTrue()))
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("doesn't complain if given policy is not provided", func() {
-		cluster := &Cluster{
+	It("allows enabling replication slots on the fly", func() {
+		oldCluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{},
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: ptr.To(false),
+					},
+				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(BeNil())
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{
+				Enabled:    ptr.To(true),
+				SlotPrefix: "_test_",
+			},
+		}
+
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
 	})
 
-	It("doesn't complain if given policy is valid", func() {
-		cluster := &Cluster{
+	It("prevents changing the slot prefix while replication slots are enabled", func() {
+		oldCluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					RetentionPolicy: "90d",
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    ptr.To(true),
+						SlotPrefix: "_test_",
+					},
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(BeNil())
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_toast_"
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
 	})
 
-	It("complain if a given policy is not valid", func() {
-		cluster := &Cluster{
+	It("prevents removing the replication slot section when replication slots are enabled", func() {
+		oldCluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
-					RetentionPolicy:   "09",
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    ptr.To(true),
+						SlotPrefix: "_test_",
+					},
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(HaveLen(2))
-	})
-})
+		oldCluster.Default()
 
-var _ = Describe("Default monitoring queries", func() {
-	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
-		cluster := &Cluster{}
-		cluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret:    "test-secret",
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  DefaultMonitoringKey,
-			}))
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  DefaultMonitoringKey,
-			}))
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots = nil
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
 	})
-	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
-		CustomQueriesConfigMap: []ConfigMapKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
-			},
-		},
-		CustomQueriesSecret: []SecretKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test3",
-			},
-		},
-	}}}
-	It("correctly set the default monitoring queries configmap when other metrics are already specified", func() {
-		modifiedCluster := testCluster.DeepCopy()
-		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
 
-		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
-			}))
+	It("allows disabling the replication slots", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    ptr.To(true),
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
 
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesSecret))
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.HighAvailability.Enabled = ptr.To(false)
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
 	})
-	It("correctly set the default monitoring queries secret when other metrics are already specified", func() {
-		modifiedCluster := testCluster.DeepCopy()
-		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret: "test-secret",
-		})
+})
 
-		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  "test3",
-			}))
+var _ = Describe("Environment variables validation", func() {
+	When("an environment variable is given", func() {
+		It("detects if it is valid", func() {
+			Expect(isReservedEnvironmentVariable("PGDATA")).To(BeTrue())
+		})
 
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesSecret))
+		It("detects if it is not valid", func() {
+			Expect(isReservedEnvironmentVariable("LC_ALL")).To(BeFalse())
+		})
 	})
-})
 
-var _ = Describe("validation of imports", func() {
-	It("rejects unrecognized import type", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type: "fooBar",
+	When("a ClusterSpec is given", func() {
+		It("detects if the environment variable list is correct", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Env: []corev1.EnvVar{
+						{
+							Name:  "TZ",
+							Value: "Europe/Rome",
 						},
 					},
 				},
-			},
-		}
+			}
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
-	})
+			Expect(cluster.validateEnv()).To(BeEmpty())
+		})
 
-	It("rejects microservice import with roles", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"bar"},
+		It("detects if the environment variable list contains a reserved variable", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Env: []corev1.EnvVar{
+						{
+							Name:  "TZ",
+							Value: "Europe/Rome",
+						},
+						{
+							Name:  "PGDATA",
+							Value: "/tmp",
 						},
 					},
 				},
-			},
-		}
+			}
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+			Expect(cluster.validateEnv()).To(HaveLen(1))
+		})
 	})
+})
 
-	It("rejects microservice import without exactly one database", func() {
-		cluster := &Cluster{
+var _ = Describe("validate the user name maps", func() {
+	clusterWithIdent := func(entries ...IdentMapEntry) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo", "bar"},
-						},
-					},
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: entries,
 				},
 			},
 		}
+	}
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+	It("accepts a cluster without user name maps", func() {
+		Expect(clusterWithIdent().validatePgIdent()).To(BeEmpty())
 	})
 
-	It("rejects microservice import with a wildcard on the database name", func() {
-		cluster := &Cluster{
+	It("accepts well formed entries", func() {
+		cluster := clusterWithIdent(
+			IdentMapEntry{MapName: "omicron", SystemUsername: "bryanh", PGUsername: "bryanh"},
+			IdentMapEntry{MapName: "omicron", SystemUsername: `/^(.*)@example\.com$`, PGUsername: `\1`},
+		)
+		Expect(cluster.validatePgIdent()).To(BeEmpty())
+	})
+
+	It("rejects empty fields", func() {
+		cluster := clusterWithIdent(IdentMapEntry{MapName: "omicron"})
+		Expect(cluster.validatePgIdent()).To(HaveLen(2))
+	})
+
+	It("rejects fields containing whitespaces, quotes, commas or comments", func() {
+		cluster := clusterWithIdent(
+			IdentMapEntry{MapName: "omicron", SystemUsername: "bryan h", PGUsername: "bryanh"},
+			IdentMapEntry{MapName: "omicron", SystemUsername: "bryanh", PGUsername: `"bryanh"`},
+			IdentMapEntry{MapName: "omi,cron", SystemUsername: "bryanh", PGUsername: "bryanh"},
+			IdentMapEntry{MapName: "omicron", SystemUsername: "bryanh", PGUsername: "bryanh#admin"},
+		)
+		Expect(cluster.validatePgIdent()).To(HaveLen(4))
+	})
+
+	It("rejects the reserved map names", func() {
+		cluster := clusterWithIdent(
+			IdentMapEntry{MapName: "local", SystemUsername: "bryanh", PGUsername: "postgres"},
+			IdentMapEntry{MapName: "include", SystemUsername: "bryanh", PGUsername: "postgres"},
+		)
+		Expect(cluster.validatePgIdent()).To(HaveLen(2))
+	})
+
+	It("rejects the substitution without a regular expression", func() {
+		cluster := clusterWithIdent(
+			IdentMapEntry{MapName: "omicron", SystemUsername: "bryanh", PGUsername: `\1`},
+		)
+		Expect(cluster.validatePgIdent()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("disabled default collectors", func() {
+	newCluster := func(collectors ...string) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"*foo"},
-						},
-					},
+				Monitoring: &MonitoringConfiguration{
+					DisableDefaultCollectors: collectors,
 				},
 			},
 		}
+	}
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+	It("doesn't warn about a cluster without monitoring settings", func() {
+		Expect((&Cluster{}).getDisabledCollectorsWarnings()).To(BeEmpty())
 	})
 
-	It("accepts microservice import when well specified", func() {
+	It("doesn't warn about the known collectors", func() {
+		cluster := newCluster(CollectorDatabaseSize, CollectorPgStatWAL)
+		Expect(cluster.getDisabledCollectorsWarnings()).To(BeEmpty())
+		Expect(cluster.Spec.Monitoring.IsCollectorDisabled(CollectorDatabaseSize)).To(BeTrue())
+		Expect(cluster.Spec.Monitoring.IsCollectorDisabled(CollectorCacheHitRatio)).To(BeFalse())
+	})
+
+	It("warns about the unknown collectors", func() {
+		cluster := newCluster(CollectorDatabaseSize, "bloat", "pg_stat_statements")
+		warnings := cluster.getDisabledCollectorsWarnings()
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0]).To(ContainSubstring(`spec.monitoring.disableDefaultCollectors[1]: unknown collector "bloat"`))
+		Expect(warnings[1]).To(ContainSubstring(`"pg_stat_statements"`))
+
+		admissionWarnings, _ := cluster.ValidateCreate()
+		Expect(admissionWarnings).To(Equal(warnings))
+	})
+})
+
+var _ = Describe("replication slots admission warnings", func() {
+	newCluster := func(instances int, parameters map[string]string, publications ...string) *Cluster {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo"},
-						},
-					},
+				Instances: instances,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: parameters,
 				},
+				Managed: &ManagedConfiguration{},
 			},
 		}
+		for _, name := range publications {
+			cluster.Spec.Managed.Publications = append(cluster.Spec.Managed.Publications,
+				PublicationConfiguration{Name: name, AllTables: true})
+		}
+		return cluster
+	}
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+	It("counts the HA slots, the slot of pg_basebackup and the managed publications", func() {
+		cluster := newCluster(3, nil, "pub1", "pub2")
+		cluster.Spec.Managed.Publications = append(cluster.Spec.Managed.Publications,
+			PublicationConfiguration{Name: "dropped", Ensure: EnsureAbsent})
+		physical, logical := cluster.getReplicationSlotsDemand()
+		Expect(physical).To(Equal(3))
+		Expect(logical).To(Equal(2))
 	})
 
-	It("rejects monolith import with no databases", func() {
-		cluster := &Cluster{
+	It("doesn't count the HA slots when they are disabled", func() {
+		cluster := newCluster(3, nil)
+		cluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{Enabled: ptr.To(false)},
+		}
+		physical, logical := cluster.getReplicationSlotsDemand()
+		Expect(physical).To(Equal(1))
+		Expect(logical).To(BeZero())
+	})
+
+	It("doesn't warn when the default limit is enough", func() {
+		Expect(newCluster(10, nil, "pub1").getReplicationSlotsWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when the demand matches the limit", func() {
+		cluster := newCluster(3, map[string]string{"max_replication_slots": "4"}, "pub1")
+		Expect(cluster.getReplicationSlotsWarnings()).To(BeEmpty())
+	})
+
+	It("warns when the demand exceeds the limit", func() {
+		cluster := newCluster(3, map[string]string{"max_replication_slots": "4"}, "pub1", "pub2")
+		warnings := cluster.getReplicationSlotsWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("spec.postgresql.parameters.max_replication_slots"))
+		Expect(warnings[0]).To(ContainSubstring("5 replication slots could be needed (3 physical, 2 logical)"))
+
+		admissionWarnings, _ := cluster.ValidateCreate()
+		Expect(admissionWarnings).To(Equal(warnings))
+	})
+
+	It("ignores an invalid limit", func() {
+		cluster := newCluster(3, map[string]string{"max_replication_slots": "many"})
+		Expect(cluster.getReplicationSlotsWarnings()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validate the user defined containers", func() {
+	It("accepts the sidecars mounting the data volume in read-only mode", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{},
+				Containers: []corev1.Container{
+					{
+						Name: "metrics-proxy",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "pgdata", MountPath: "/var/lib/postgresql/data", ReadOnly: true},
+							{Name: "proxy-cache", MountPath: "/cache"},
 						},
 					},
 				},
+				InitContainers: []corev1.Container{{Name: "setup"}},
 			},
 		}
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateContainers()).To(BeEmpty())
 	})
 
-	It("rejects monolith import with PostImport Application SQL", func() {
-		cluster := &Cluster{
+	It("rejects the containers using a name reserved for the operator", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:                     MonolithSnapshotType,
-							Databases:                []string{"foo"},
-							PostImportApplicationSQL: []string{"select * from bar"},
-						},
-					},
-				},
+				Containers:     []corev1.Container{{Name: "postgres"}},
+				InitContainers: []corev1.Container{{Name: "bootstrap-controller"}},
 			},
 		}
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		result := cluster.validateContainers()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.initContainers[0].name"))
+		Expect(result[1].Field).To(Equal("spec.containers[0].name"))
 	})
 
-	It("rejects monolith import with wildcards alongside specific values", func() {
-		cluster := &Cluster{
+	It("rejects the duplicated container names", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"bar", "*"},
-						},
-					},
-				},
+				Containers:     []corev1.Container{{Name: "sidecar"}},
+				InitContainers: []corev1.Container{{Name: "sidecar"}},
 			},
 		}
 
-		result := cluster.validateImport()
+		result := cluster.validateContainers()
 		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(result[0].Field).To(Equal("spec.containers[0].name"))
+	})
 
-		cluster = &Cluster{
+	It("rejects the writable mounts of the data and WAL volumes", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"baz", "*"},
+				Containers: []corev1.Container{
+					{
+						Name: "metrics-proxy",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "pgdata", MountPath: "/var/lib/postgresql/data"},
+							{Name: "pg-wal", MountPath: "/var/lib/postgresql/wal"},
 						},
 					},
 				},
 			},
 		}
 
-		result = cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		result := cluster.validateContainers()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.containers[0].volumeMounts[0].readOnly"))
+		Expect(result[1].Field).To(Equal("spec.containers[0].volumeMounts[1].readOnly"))
 	})
 
-	It("accepts monolith import with proper values", func() {
-		cluster := &Cluster{
+	It("rejects the writable mounts of the log, scratch and backup volumes", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
+				InitContainers: []corev1.Container{
+					{
+						Name: "log-shipper",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "pg-log", MountPath: "/logs"},
+							{Name: "scratch-data", MountPath: "/run"},
+							{Name: "backup", MountPath: "/backup"},
+							{Name: "shipper-config", MountPath: "/config"},
 						},
 					},
 				},
 			},
 		}
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+		result := cluster.validateContainers()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.initContainers[0].volumeMounts[0].readOnly"))
+		Expect(result[1].Field).To(Equal("spec.initContainers[0].volumeMounts[1].readOnly"))
+		Expect(result[2].Field).To(Equal("spec.initContainers[0].volumeMounts[2].readOnly"))
 	})
+})
 
-	It("accepts monolith import with wildcards", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"*"},
-							Roles:     []string{"*"},
+var _ = Describe("Storage configuration validation", func() {
+	When("a ClusterSpec is given", func() {
+		It("produces one error if storage is not set at all", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{},
+				},
+			}
+			Expect(cluster.validateStorageSize()).To(HaveLen(1))
+		})
+
+		It("succeeds if storage size is set", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{
+						Size: "1G",
+					},
+				},
+			}
+			Expect(cluster.validateStorageSize()).To(BeEmpty())
+		})
+
+		It("succeeds if storage is not set but a pvc template specifies storage", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{
+						PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{"storage": resource.MustParse("1Gi")},
+							},
 						},
 					},
 				},
-			},
-		}
+			}
+			Expect(cluster.validateStorageSize()).To(BeEmpty())
+		})
+	})
+
+	When("a log storage is given", func() {
+		It("produces one error if its size is not set", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					LogStorage: &StorageConfiguration{},
+				},
+			}
+			errs := cluster.validateLogStorageSize()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.logStorage.size"))
+		})
+
+		It("succeeds if its size is set", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					LogStorage: &StorageConfiguration{Size: "1Gi"},
+				},
+			}
+			Expect(cluster.validateLogStorageSize()).To(BeEmpty())
+		})
+
+		It("allows adding it to an existing cluster", func() {
+			oldCluster := Cluster{}
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					LogStorage: &StorageConfiguration{Size: "1Gi"},
+				},
+			}
+			Expect(cluster.validateLogStorageChange(&oldCluster)).To(BeEmpty())
+		})
+
+		It("doesn't allow it to be removed or shrunk", func() {
+			oldCluster := Cluster{
+				Spec: ClusterSpec{
+					LogStorage: &StorageConfiguration{Size: "2Gi"},
+				},
+			}
+			Expect((&Cluster{}).validateLogStorageChange(&oldCluster)).To(HaveLen(1))
+
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					LogStorage: &StorageConfiguration{Size: "1Gi"},
+				},
+			}
+			Expect(cluster.validateLogStorageChange(&oldCluster)).To(HaveLen(1))
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+			cluster.Spec.LogStorage.Size = "4Gi"
+			Expect(cluster.validateLogStorageChange(&oldCluster)).To(BeEmpty())
+		})
 	})
 })
 
-var _ = Describe("validation of replication slots configuration", func() {
-	It("prevents using replication slots on PostgreSQL 10 and older", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: ptr.To(true),
-					},
-					UpdateInterval: 0,
-				},
-			},
+var _ = Describe("Role management validation", func() {
+	It("should succeed if there is no management stanza", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{},
 		}
-		cluster.Default()
-
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
 	})
 
-	It("can be enabled on the default PostgreSQL image", func() {
-		cluster := &Cluster{
+	It("should succeed if the role defined is not reserved", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: ptr.To(true),
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name: "non-conflicting",
+						},
 					},
-					UpdateInterval: 0,
 				},
 			},
 		}
-		cluster.Default()
-
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(BeEmpty())
-	})
-
-	It("set replicationSlots by default", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-			},
-		}
-		cluster.Default()
-		Expect(cluster.Spec.ReplicationSlots).ToNot(BeNil())
-		Expect(cluster.Spec.ReplicationSlots.HighAvailability).ToNot(BeNil())
-		Expect(cluster.Spec.ReplicationSlots.HighAvailability.Enabled).To(HaveValue(BeTrue()))
-
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
 	})
 
-	It("set replicationSlots.highAvailability by default", func() {
-		cluster := &Cluster{
+	It("should produce an error on invalid connection limit", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					UpdateInterval: 30,
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:            "non-conflicting",
+							ConnectionLimit: -3,
+						},
+					},
 				},
 			},
 		}
-		cluster.Default()
-		Expect(cluster.Spec.ReplicationSlots.HighAvailability).ToNot(BeNil())
-		Expect(cluster.Spec.ReplicationSlots.HighAvailability.Enabled).To(HaveValue(BeTrue()))
-
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
 
-	It("allows enabling replication slots on the fly", func() {
-		oldCluster := &Cluster{
+	It("should produce an error if the role is reserved", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: ptr.To(false),
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name: "postgres",
+						},
 					},
 				},
 			},
 		}
-		oldCluster.Default()
-
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
-			HighAvailability: &ReplicationSlotsHAConfiguration{
-				Enabled:    ptr.To(true),
-				SlotPrefix: "_test_",
-			},
-		}
-
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
 
-	It("prevents changing the slot prefix while replication slots are enabled", func() {
-		oldCluster := &Cluster{
+	It("should produce two errors if the role is reserved and the connection limit is invalid", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    ptr.To(true),
-						SlotPrefix: "_test_",
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:            "postgres",
+							ConnectionLimit: -3,
+						},
 					},
 				},
 			},
 		}
-		oldCluster.Default()
-
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_toast_"
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+		Expect(cluster.validateManagedRoles()).To(HaveLen(2))
 	})
 
-	It("prevents removing the replication slot section when replication slots are enabled", func() {
-		oldCluster := &Cluster{
+	It("should produce an error if we define two roles with the same name", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    ptr.To(true),
-						SlotPrefix: "_test_",
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:            "my_test",
+							ConnectionLimit: -1,
+						},
+						{
+							Name:            "my_test",
+							Superuser:       true,
+							BypassRLS:       true,
+							ConnectionLimit: -1,
+						},
 					},
 				},
 			},
 		}
-		oldCluster.Default()
-
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots = nil
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
-
-	It("allows disabling the replication slots", func() {
-		oldCluster := &Cluster{
+	It("should produce an error if we have a password secret AND DisablePassword in a role", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    ptr.To(true),
-						SlotPrefix: "_test_",
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:            "my_test",
+							Superuser:       true,
+							BypassRLS:       true,
+							DisablePassword: true,
+							PasswordSecret: &LocalObjectReference{
+								Name: "myPassword",
+							},
+							ConnectionLimit: -1,
+						},
 					},
 				},
 			},
 		}
-		oldCluster.Default()
-
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots.HighAvailability.Enabled = ptr.To(false)
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
 })
 
-var _ = Describe("Environment variables validation", func() {
-	When("an environment variable is given", func() {
-		It("detects if it is valid", func() {
-			Expect(isReservedEnvironmentVariable("PGDATA")).To(BeTrue())
-		})
-
-		It("detects if it is not valid", func() {
-			Expect(isReservedEnvironmentVariable("LC_ALL")).To(BeFalse())
-		})
+var _ = Describe("Publication management validation", func() {
+	It("should succeed if there is no management stanza", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{},
+		}
+		Expect(cluster.validateManagedPublications()).To(BeEmpty())
 	})
 
-	When("a ClusterSpec is given", func() {
-		It("detects if the environment variable list is correct", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					Env: []corev1.EnvVar{
+	It("should succeed with valid publications", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Publications: []PublicationConfiguration{
 						{
-							Name:  "TZ",
-							Value: "Europe/Rome",
+							Name:      "all_tables",
+							AllTables: true,
+						},
+						{
+							Name:   "some_tables",
+							Tables: []string{"public.orders", "customers"},
+						},
+						{
+							Name:   "some_tables",
+							DBName: "another_db",
+							Tables: []string{"public.orders"},
 						},
 					},
 				},
-			}
-
-			Expect(cluster.validateEnv()).To(BeEmpty())
-		})
+			},
+		}
+		Expect(cluster.validateManagedPublications()).To(BeEmpty())
+	})
 
-		It("detects if the environment variable list contains a reserved variable", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					Env: []corev1.EnvVar{
+	It("should produce an error if we define two publications with the same name in the same database", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+					},
+				},
+				Managed: &ManagedConfiguration{
+					Publications: []PublicationConfiguration{
 						{
-							Name:  "TZ",
-							Value: "Europe/Rome",
+							Name:      "my_pub",
+							AllTables: true,
 						},
 						{
-							Name:  "PGDATA",
-							Value: "/tmp",
+							Name:      "my_pub",
+							DBName:    "app",
+							AllTables: true,
 						},
 					},
 				},
-			}
-
-			Expect(cluster.validateEnv()).To(HaveLen(1))
-		})
+			},
+		}
+		Expect(cluster.validateManagedPublications()).To(HaveLen(1))
 	})
-})
 
-var _ = Describe("Storage configuration validation", func() {
-	When("a ClusterSpec is given", func() {
-		It("produces one error if storage is not set at all", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					StorageConfiguration: StorageConfiguration{},
+	It("should produce an error if both allTables and tables are set", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Publications: []PublicationConfiguration{
+						{
+							Name:      "my_pub",
+							AllTables: true,
+							Tables:    []string{"orders"},
+						},
+					},
 				},
-			}
-			Expect(cluster.validateStorageSize()).To(HaveLen(1))
-		})
+			},
+		}
+		Expect(cluster.validateManagedPublications()).To(HaveLen(1))
+	})
 
-		It("succeeds if storage size is set", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					StorageConfiguration: StorageConfiguration{
-						Size: "1G",
+	It("should produce an error if neither allTables nor tables are set", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Publications: []PublicationConfiguration{
+						{
+							Name: "my_pub",
+						},
 					},
 				},
-			}
-			Expect(cluster.validateStorageSize()).To(BeEmpty())
-		})
+			},
+		}
+		Expect(cluster.validateManagedPublications()).To(HaveLen(1))
+	})
 
-		It("succeeds if storage is not set but a pvc template specifies storage", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					StorageConfiguration: StorageConfiguration{
-						PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{"storage": resource.MustParse("1Gi")},
-							},
+	It("should not validate the content of publications that need to be dropped", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Publications: []PublicationConfiguration{
+						{
+							Name:   "my_pub",
+							Ensure: EnsureAbsent,
 						},
 					},
 				},
-			}
-			Expect(cluster.validateStorageSize()).To(BeEmpty())
-		})
+			},
+		}
+		Expect(cluster.validateManagedPublications()).To(BeEmpty())
 	})
 })
 
-var _ = Describe("Role management validation", func() {
+var _ = Describe("Subscription management validation", func() {
+	connectionSecret := SecretKeySelector{
+		LocalObjectReference: LocalObjectReference{Name: "publisher-conninfo"},
+		Key:                  "conninfo",
+	}
+
 	It("should succeed if there is no management stanza", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{},
 		}
-		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+		Expect(cluster.validateManagedSubscriptions()).To(BeEmpty())
 	})
 
-	It("should succeed if the role defined is not reserved", func() {
+	It("should succeed with valid subscriptions", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				Managed: &ManagedConfiguration{
-					Roles: []RoleConfiguration{
+					Subscriptions: []SubscriptionConfiguration{
 						{
-							Name: "non-conflicting",
+							Name:             "my_sub",
+							ConnectionSecret: connectionSecret,
+							PublicationName:  "my_pub",
+						},
+						{
+							Name:             "my_sub",
+							DBName:           "another_db",
+							ConnectionSecret: connectionSecret,
+							PublicationName:  "my_pub",
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+		Expect(cluster.validateManagedSubscriptions()).To(BeEmpty())
 	})
 
-	It("should produce an error on invalid connection limit", func() {
+	It("should produce an error if we define two subscriptions with the same name in the same database", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+					},
+				},
 				Managed: &ManagedConfiguration{
-					Roles: []RoleConfiguration{
+					Subscriptions: []SubscriptionConfiguration{
 						{
-							Name:            "non-conflicting",
-							ConnectionLimit: -3,
+							Name:             "my_sub",
+							ConnectionSecret: connectionSecret,
+							PublicationName:  "my_pub",
+						},
+						{
+							Name:             "my_sub",
+							DBName:           "app",
+							ConnectionSecret: connectionSecret,
+							PublicationName:  "another_pub",
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+		Expect(cluster.validateManagedSubscriptions()).To(HaveLen(1))
 	})
 
-	It("should produce an error if the role is reserved", func() {
+	It("should produce an error if the connection secret or the publication are missing", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				Managed: &ManagedConfiguration{
-					Roles: []RoleConfiguration{
+					Subscriptions: []SubscriptionConfiguration{
 						{
-							Name: "postgres",
+							Name: "my_sub",
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+		Expect(cluster.validateManagedSubscriptions()).To(HaveLen(2))
 	})
 
-	It("should produce two errors if the role is reserved and the connection limit is invalid", func() {
+	It("should not validate the content of subscriptions that need to be dropped", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				Managed: &ManagedConfiguration{
-					Roles: []RoleConfiguration{
+					Subscriptions: []SubscriptionConfiguration{
 						{
-							Name:            "postgres",
-							ConnectionLimit: -3,
+							Name:   "my_sub",
+							Ensure: EnsureAbsent,
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateManagedRoles()).To(HaveLen(2))
+		Expect(cluster.validateManagedSubscriptions()).To(BeEmpty())
 	})
+})
 
-	It("should produce an error if we define two roles with the same name", func() {
+var _ = Describe("Table management validation", func() {
+	It("should succeed if there is no management stanza", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{},
+		}
+		Expect(cluster.validateManagedTables()).To(BeEmpty())
+	})
+
+	It("should succeed with valid autovacuum settings", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				Managed: &ManagedConfiguration{
-					Roles: []RoleConfiguration{
+					Tables: []TableConfiguration{
 						{
-							Name:            "my_test",
-							ConnectionLimit: -1,
+							Name: "orders",
+							AutovacuumSettings: map[string]string{
+								"autovacuum_vacuum_scale_factor":       "0.01",
+								"toast.autovacuum_vacuum_scale_factor": "0.05",
+							},
 						},
 						{
-							Name:            "my_test",
-							Superuser:       true,
-							BypassRLS:       true,
-							ConnectionLimit: -1,
+							Name:   "orders",
+							DBName: "another_db",
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+		Expect(cluster.validateManagedTables()).To(BeEmpty())
 	})
-	It("should produce an error if we have a password secret AND DisablePassword in a role", func() {
+
+	It("should produce an error if the same table is listed twice in the same database", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+					},
+				},
 				Managed: &ManagedConfiguration{
-					Roles: []RoleConfiguration{
+					Tables: []TableConfiguration{
 						{
-							Name:            "my_test",
-							Superuser:       true,
-							BypassRLS:       true,
-							DisablePassword: true,
-							PasswordSecret: &LocalObjectReference{
-								Name: "myPassword",
+							Name: "orders",
+						},
+						{
+							Name:   "public.orders",
+							DBName: "app",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedTables()).To(HaveLen(1))
+	})
+
+	It("should produce an error if the table has no name", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Tables: []TableConfiguration{
+						{
+							AutovacuumSettings: map[string]string{
+								"autovacuum_enabled": "off",
 							},
-							ConnectionLimit: -1,
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+		Expect(cluster.validateManagedTables()).To(HaveLen(1))
+	})
+
+	It("should produce an error for storage parameters not related to autovacuum", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Tables: []TableConfiguration{
+						{
+							Name: "orders",
+							AutovacuumSettings: map[string]string{
+								"fillfactor":                         "70",
+								"toast.autovacuum_analyze_threshold": "50",
+								"autovacuum_analyze_threshold":       "50",
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedTables()).To(HaveLen(2))
 	})
 })
 
@@ -3344,3 +5285,363 @@ var _ = Describe("validateResources", func() {
 		Expect(errors).To(BeEmpty())
 	})
 })
+
+var _ = Describe("validation of the preferred primary zone", func() {
+	It("accepts a cluster without a preferred primary zone", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validatePreferredPrimaryZone()).To(BeEmpty())
+	})
+
+	It("accepts a valid zone", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Topology: &TopologyConfiguration{
+					PreferredPrimaryZone: "eu-west-1a",
+				},
+			},
+		}
+		Expect(cluster.validatePreferredPrimaryZone()).To(BeEmpty())
+	})
+
+	It("rejects a zone which is not a valid label value", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Topology: &TopologyConfiguration{
+					PreferredPrimaryZone: "eu west 1a",
+				},
+			},
+		}
+		result := cluster.validatePreferredPrimaryZone()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.topology.preferredPrimaryZone"))
+	})
+})
+
+var _ = Describe("SQL job management validation", func() {
+	newCluster := func(jobs ...SQLJobConfiguration) Cluster {
+		return Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					SQLJobs: jobs,
+				},
+			},
+		}
+	}
+
+	It("should succeed if there is no management stanza", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateManagedSQLJobs()).To(BeEmpty())
+	})
+
+	It("should succeed with a script or a secret", func() {
+		cluster := newCluster(
+			SQLJobConfiguration{Name: "inline", SQL: "ANALYZE"},
+			SQLJobConfiguration{
+				Name: "secret",
+				SecretRef: &SecretKeySelector{
+					LocalObjectReference: LocalObjectReference{Name: "migration"},
+					Key:                  "script.sql",
+				},
+			},
+		)
+		Expect(cluster.validateManagedSQLJobs()).To(BeEmpty())
+	})
+
+	It("should fail with duplicate names", func() {
+		cluster := newCluster(
+			SQLJobConfiguration{Name: "migration", SQL: "ANALYZE"},
+			SQLJobConfiguration{Name: "migration", SQL: "VACUUM"},
+		)
+		Expect(cluster.validateManagedSQLJobs()).To(HaveLen(1))
+	})
+
+	It("should fail without a name", func() {
+		cluster := newCluster(SQLJobConfiguration{SQL: "ANALYZE"})
+		Expect(cluster.validateManagedSQLJobs()).To(HaveLen(1))
+	})
+
+	It("should fail without a script", func() {
+		cluster := newCluster(SQLJobConfiguration{Name: "migration"})
+		Expect(cluster.validateManagedSQLJobs()).To(HaveLen(1))
+	})
+
+	It("should fail with both a script and a secret", func() {
+		cluster := newCluster(SQLJobConfiguration{
+			Name: "migration",
+			SQL:  "ANALYZE",
+			SecretRef: &SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: "migration"},
+				Key:                  "script.sql",
+			},
+		})
+		Expect(cluster.validateManagedSQLJobs()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("maintenance jobs validation", func() {
+	newCluster := func(jobs ...MaintenanceJobConfiguration) Cluster {
+		return Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindow: &MaintenanceWindowConfiguration{
+					Schedule: "0 0 2 * * 6",
+					Duration: 3600,
+				},
+				Managed: &ManagedConfiguration{
+					MaintenanceJobs: jobs,
+				},
+			},
+		}
+	}
+
+	It("should succeed if there is no management stanza", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateMaintenanceJobs()).To(BeEmpty())
+	})
+
+	It("should succeed with valid jobs", func() {
+		cluster := newCluster(
+			MaintenanceJobConfiguration{
+				Name:      "reindex",
+				Operation: MaintenanceOperationReindexConcurrently,
+				Tables:    []string{"orders"},
+				Indexes:   []string{"sales.customers_pkey"},
+				Schedule:  "0 0 3 * * 0",
+			},
+			MaintenanceJobConfiguration{
+				Name:      "rewrite",
+				Operation: MaintenanceOperationVacuumFull,
+				Tables:    []string{"public.events"},
+			},
+		)
+		Expect(cluster.validateMaintenanceJobs()).To(BeEmpty())
+	})
+
+	It("should fail with duplicate or missing names", func() {
+		cluster := newCluster(
+			MaintenanceJobConfiguration{
+				Name:      "reindex",
+				Operation: MaintenanceOperationReindexConcurrently,
+				Tables:    []string{"orders"},
+			},
+			MaintenanceJobConfiguration{
+				Name:      "reindex",
+				Operation: MaintenanceOperationReindexConcurrently,
+				Tables:    []string{"customers"},
+			},
+			MaintenanceJobConfiguration{
+				Operation: MaintenanceOperationReindexConcurrently,
+				Tables:    []string{"customers"},
+			},
+		)
+		Expect(cluster.validateMaintenanceJobs()).To(HaveLen(2))
+	})
+
+	It("should fail without targets or with empty ones", func() {
+		cluster := newCluster(
+			MaintenanceJobConfiguration{
+				Name:      "nothing",
+				Operation: MaintenanceOperationReindexConcurrently,
+			},
+			MaintenanceJobConfiguration{
+				Name:      "empty",
+				Operation: MaintenanceOperationReindexConcurrently,
+				Tables:    []string{""},
+				Indexes:   []string{""},
+			},
+		)
+		result := cluster.validateMaintenanceJobs()
+		Expect(result).To(HaveLen(3))
+		Expect(result[1].Field).To(Equal("spec.managed.maintenanceJobs[1].tables[0]"))
+		Expect(result[2].Field).To(Equal("spec.managed.maintenanceJobs[1].indexes[0]"))
+	})
+
+	It("should fail when VACUUM FULL is requested on indexes", func() {
+		cluster := newCluster(MaintenanceJobConfiguration{
+			Name:      "rewrite",
+			Operation: MaintenanceOperationVacuumFull,
+			Indexes:   []string{"orders_pkey"},
+		})
+		result := cluster.validateMaintenanceJobs()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.managed.maintenanceJobs[0].indexes"))
+	})
+
+	It("should fail when VACUUM FULL can run outside of a maintenance window", func() {
+		cluster := newCluster(MaintenanceJobConfiguration{
+			Name:      "rewrite",
+			Operation: MaintenanceOperationVacuumFull,
+			Tables:    []string{"events"},
+		})
+		cluster.Spec.MaintenanceWindow = nil
+		result := cluster.validateMaintenanceJobs()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.managed.maintenanceJobs[0].operation"))
+	})
+
+	It("should fail with an invalid schedule", func() {
+		cluster := newCluster(MaintenanceJobConfiguration{
+			Name:      "reindex",
+			Operation: MaintenanceOperationReindexConcurrently,
+			Tables:    []string{"orders"},
+			Schedule:  "every sunday",
+		})
+		result := cluster.validateMaintenanceJobs()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.managed.maintenanceJobs[0].schedule"))
+	})
+})
+
+var _ = Describe("volume backup validation", func() {
+	newCluster := func(instances int, accessModes ...corev1.PersistentVolumeAccessMode) *Cluster {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: instances,
+				Backup: &BackupConfiguration{
+					VolumeBackup: &VolumeBackupConfiguration{
+						Storage: StorageConfiguration{Size: "10Gi"},
+					},
+				},
+			},
+		}
+		if len(accessModes) > 0 {
+			cluster.Spec.Backup.VolumeBackup.Storage.PersistentVolumeClaimTemplate = &corev1.PersistentVolumeClaimSpec{
+				AccessModes: accessModes,
+			}
+		}
+		return cluster
+	}
+
+	It("accepts a cluster without a backup volume", func() {
+		Expect((&Cluster{}).validateVolumeBackup()).To(BeEmpty())
+	})
+
+	It("accepts a backup volume with the default access modes when there is a single instance", func() {
+		Expect(newCluster(1).validateVolumeBackup()).To(BeEmpty())
+	})
+
+	It("requires a shared volume when there is more than one instance", func() {
+		Expect(newCluster(3).validateVolumeBackup()).To(HaveLen(1))
+
+		cluster := newCluster(3)
+		cluster.Spec.Backup.Target = BackupTargetPrimary
+		Expect(cluster.validateVolumeBackup()).To(HaveLen(1))
+		Expect(newCluster(3, corev1.ReadWriteOnce).validateVolumeBackup()).To(HaveLen(1))
+		Expect(newCluster(3, corev1.ReadWriteOnce, corev1.ReadWriteMany).validateVolumeBackup()).To(BeEmpty())
+		Expect(newCluster(1, corev1.ReadWriteOnce).validateVolumeBackup()).To(BeEmpty())
+	})
+
+	It("complains when used together with barmanObjectStore", func() {
+		cluster := newCluster(1)
+		cluster.Spec.Backup.BarmanObjectStore = &BarmanObjectStoreConfiguration{}
+		Expect(cluster.validateVolumeBackup()).To(HaveLen(1))
+	})
+
+	It("complains when the size is not valid", func() {
+		cluster := newCluster(1)
+		cluster.Spec.Backup.VolumeBackup.Storage.Size = "10 apples"
+		Expect(cluster.validateVolumeBackup()).To(HaveLen(1))
+	})
+
+	It("rejects shrinking the backup volume", func() {
+		oldCluster := newCluster(1)
+		cluster := newCluster(1)
+		cluster.Spec.Backup.VolumeBackup.Storage.Size = "5Gi"
+		Expect(cluster.validateBackupVolumeChange(oldCluster)).To(HaveLen(1))
+
+		cluster.Spec.Backup.VolumeBackup.Storage.Size = "20Gi"
+		Expect(cluster.validateBackupVolumeChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("locale provider validation", func() {
+	newCluster := func(imageName string, initDB BootstrapInitDB) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				ImageName: imageName,
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &initDB,
+				},
+			},
+		}
+	}
+
+	It("accepts a cluster without initdb options", func() {
+		Expect((&Cluster{}).validateLocaleProvider()).To(BeEmpty())
+		Expect(newCluster("postgres:14", BootstrapInitDB{}).validateLocaleProvider()).To(BeEmpty())
+	})
+
+	It("accepts the icu locale provider with its options", func() {
+		cluster := newCluster("postgres:16", BootstrapInitDB{
+			LocaleProvider: LocaleProviderICU,
+			ICULocale:      "en-US",
+			ICURules:       "&a < g",
+		})
+		Expect(cluster.validateLocaleProvider()).To(BeEmpty())
+	})
+
+	It("requires the ICU locale with the icu locale provider", func() {
+		cluster := newCluster("postgres:15", BootstrapInitDB{LocaleProvider: LocaleProviderICU})
+		result := cluster.validateLocaleProvider()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.icuLocale"))
+	})
+
+	It("rejects the ICU options without the icu locale provider", func() {
+		cluster := newCluster("postgres:16", BootstrapInitDB{
+			LocaleProvider: LocaleProviderLibc,
+			ICULocale:      "en-US",
+			ICURules:       "&a < g",
+		})
+		Expect(cluster.validateLocaleProvider()).To(HaveLen(2))
+	})
+
+	It("rejects the options that are not supported by the PostgreSQL version", func() {
+		cluster := newCluster("postgres:14", BootstrapInitDB{
+			LocaleProvider: LocaleProviderICU,
+			ICULocale:      "en-US",
+		})
+		result := cluster.validateLocaleProvider()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.localeProvider"))
+
+		cluster = newCluster("postgres:15", BootstrapInitDB{
+			LocaleProvider: LocaleProviderICU,
+			ICULocale:      "en-US",
+			ICURules:       "&a < g",
+		})
+		result = cluster.validateLocaleProvider()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.icuRules"))
+	})
+
+	It("accepts the updates keeping the locale provider options", func() {
+		initDB := BootstrapInitDB{
+			LocaleProvider: LocaleProviderICU,
+			ICULocale:      "en-US",
+		}
+		cluster := newCluster("postgres:16", initDB)
+		cluster.Spec.Bootstrap.InitDB.PostInitSQL = []string{"SELECT 1"}
+		Expect(cluster.validateLocaleProviderChange(newCluster("postgres:16", initDB))).To(BeEmpty())
+		Expect((&Cluster{}).validateLocaleProviderChange(&Cluster{})).To(BeEmpty())
+	})
+
+	It("rejects the changes to the locale provider options", func() {
+		oldCluster := newCluster("postgres:16", BootstrapInitDB{
+			LocaleProvider: LocaleProviderICU,
+			ICULocale:      "en-US",
+		})
+
+		result := newCluster("postgres:16", BootstrapInitDB{
+			LocaleProvider: LocaleProviderICU,
+			ICULocale:      "de-DE",
+			ICURules:       "&a < g",
+		}).validateLocaleProviderChange(oldCluster)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.icuLocale"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.initdb.icuRules"))
+
+		result = (&Cluster{}).validateLocaleProviderChange(oldCluster)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.localeProvider"))
+	})
+})
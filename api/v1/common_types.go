@@ -38,3 +38,29 @@ type Metadata struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
+
+// TCPKeepalivesConfiguration contains the TCP keepalive settings of
+// the connections. Unset values are left to the default of the
+// operating system
+type TCPKeepalivesConfiguration struct {
+	// The number of seconds of inactivity after which a keepalive
+	// message is sent
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32767
+	// +optional
+	Idle *int32 `json:"idle,omitempty"`
+
+	// The number of seconds after which a keepalive message that
+	// has not been acknowledged is retransmitted
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32767
+	// +optional
+	Interval *int32 `json:"interval,omitempty"`
+
+	// The number of keepalive messages that can be lost before the
+	// connection is considered dead
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=127
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +40,27 @@ const (
 
 	// DefaultPgBouncerPoolerAuthQuery is the default auth_query for PgBouncer
 	DefaultPgBouncerPoolerAuthQuery = "SELECT usename, passwd FROM user_search($1)"
+
+	// DefaultPgBouncerDefaultPoolSize is the default value of the
+	// default_pool_size PgBouncer parameter
+	DefaultPgBouncerDefaultPoolSize = 20
+
+	// DefaultPgBouncerReservePoolSize is the default value of the
+	// reserve_pool_size PgBouncer parameter
+	DefaultPgBouncerReservePoolSize = 0
+
+	// DefaultPgBouncerReservePoolTimeout is the default value of the
+	// reserve_pool_timeout PgBouncer parameter
+	DefaultPgBouncerReservePoolTimeout = "5"
+
+	// DefaultPgBouncerMaxPreparedStatements is the default value of the
+	// max_prepared_statements PgBouncer parameter
+	DefaultPgBouncerMaxPreparedStatements = 0
+
+	// MinPgBouncerVersionForPreparedStatements is the first PgBouncer
+	// version supporting the prepared statements in transaction and
+	// statement pooling
+	MinPgBouncerVersionForPreparedStatements = "1.21.0"
 )
 
 // PgBouncerPoolMode is the mode of PgBouncer
@@ -50,6 +75,31 @@ const (
 	PgBouncerPoolModeTransaction = PgBouncerPoolMode("transaction")
 )
 
+// PgBouncerTLSMode is the TLS mode of the connections of PgBouncer
+type PgBouncerTLSMode string
+
+const (
+	// PgBouncerTLSModeDisable the "disable" mode, where TLS is not used
+	PgBouncerTLSModeDisable = PgBouncerTLSMode("disable")
+
+	// PgBouncerTLSModeAllow the "allow" mode
+	PgBouncerTLSModeAllow = PgBouncerTLSMode("allow")
+
+	// PgBouncerTLSModePrefer the "prefer" mode, where TLS is used when available
+	PgBouncerTLSModePrefer = PgBouncerTLSMode("prefer")
+
+	// PgBouncerTLSModeRequire the "require" mode, where TLS is mandatory
+	PgBouncerTLSModeRequire = PgBouncerTLSMode("require")
+
+	// PgBouncerTLSModeVerifyCA the "verify-ca" mode, where the certificate
+	// of the other side must be signed by a trusted CA
+	PgBouncerTLSModeVerifyCA = PgBouncerTLSMode("verify-ca")
+
+	// PgBouncerTLSModeVerifyFull the "verify-full" mode, where the
+	// certificate of the other side must also match its host name
+	PgBouncerTLSModeVerifyFull = PgBouncerTLSMode("verify-full")
+)
+
 // PoolerSpec defines the desired state of Pooler
 type PoolerSpec struct {
 	// This is the cluster reference on which the Pooler will work.
@@ -70,6 +120,26 @@ type PoolerSpec struct {
 	// +optional
 	Template *PodTemplateSpec `json:"template,omitempty"`
 
+	// Name of the container image to be used for PgBouncer, supporting both
+	// tags (`<image>:<tag>`) and digests (`<image>:<tag>@sha256:<digestValue>`).
+	// An image set in the pod template takes precedence over this one.
+	// If not defined, the default PgBouncer image of the operator is used
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// The list of pull secrets to be used to pull the PgBouncer image,
+	// independently of the ones used by the referenced cluster
+	// +optional
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// The environment variables to be set in the PgBouncer container. They
+	// take precedence over the ones of the pod template, while the ones
+	// managed by the operator take precedence over them
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
 	// The PgBouncer configuration
 	PgBouncer *PgBouncerSpec `json:"pgbouncer"`
 
@@ -92,6 +162,30 @@ type PoolerMonitoringConfiguration struct {
 	// +kubebuilder:default:=false
 	// +optional
 	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// The interval at which Prometheus scrapes the metrics of the pooler
+	// pods, expressed in the Prometheus duration format (e.g. `30s`).
+	// Defaults to the global scrape interval of Prometheus.
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	// +optional
+	PodMonitorInterval string `json:"podMonitorInterval,omitempty"`
+
+	// The TLS configuration of the metrics endpoint.
+	// Changing this option will force a rollout of the pooler pods.
+	// +optional
+	TLSConfig *PoolerMonitoringTLSConfiguration `json:"tls,omitempty"`
+}
+
+// PoolerMonitoringTLSConfiguration is the type containing the TLS
+// configuration of the metrics endpoint of a Pooler
+type PoolerMonitoringTLSConfiguration struct {
+	// Whether the metrics endpoint is served over HTTPS, using the
+	// server certificate of the cluster. The generated `PodMonitor`
+	// verifies it against the server CA of the cluster, using the
+	// read-write service name as the server name.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 // PodTemplateSpec is a structure allowing the user to set
@@ -138,6 +232,35 @@ type PgBouncerSpec struct {
 	// +optional
 	AuthQuery string `json:"authQuery,omitempty"`
 
+	// The number of additional server connections allowed to a pool when
+	// a client has been waiting for more than `reservePoolTimeout` seconds.
+	// It cannot exceed the `default_pool_size`. Default: 0 (disabled).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReservePoolSize *int32 `json:"reservePoolSize,omitempty"`
+
+	// The number of seconds a client has to wait before the reserve
+	// pool is used. Default: 5.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReservePoolTimeout *int32 `json:"reservePoolTimeout,omitempty"`
+
+	// The maximum number of protocol-level prepared statements tracked by
+	// PgBouncer for each connection, allowing the applications to use them
+	// in transaction and statement pooling. Requires PgBouncer 1.21 or
+	// later. Default: 0 (disabled).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPreparedStatements *int32 `json:"maxPreparedStatements,omitempty"`
+
+	// The TCP keepalive settings of PgBouncer, rendered in the
+	// `tcp_keepidle`, `tcp_keepintvl` and `tcp_keepcnt` parameters
+	// together with `tcp_keepalive = 1`. PgBouncer applies them to both
+	// the client and the server connections. Changing them doesn't
+	// require a restart
+	// +optional
+	TCPKeepalives *TCPKeepalivesConfiguration `json:"tcpKeepalives,omitempty"`
+
 	// Additional parameters to be passed to PgBouncer - please check
 	// the CNPG documentation for a list of options you can configure
 	// +optional
@@ -148,6 +271,17 @@ type PgBouncerSpec struct {
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// The access to the PgBouncer admin console, where commands like
+	// `PAUSE` and `KILL` can be issued
+	// +optional
+	Admin *PgBouncerAdminConfiguration `json:"admin,omitempty"`
+
+	// The TLS configuration of PgBouncer, both for the connections
+	// coming from the client applications and for the ones toward
+	// PostgreSQL
+	// +optional
+	TLS *PgBouncerTLSConfiguration `json:"tls,omitempty"`
+
 	// When set to `true`, PgBouncer will disconnect from the PostgreSQL
 	// server, first waiting for all queries to complete, and pause all new
 	// client connections until this value is set to `false` (default). Internally,
@@ -155,6 +289,96 @@ type PgBouncerSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// The list of databases paused in PgBouncer, for example before a
+	// logical dump or a maintenance operation. The server connections to
+	// these databases are closed after waiting for the queries to complete,
+	// and the new queries are held until the database is removed from the
+	// list. Internally, the operator calls PgBouncer's `PAUSE <db>` and
+	// `RESUME <db>` commands
+	// +optional
+	PausedDatabases []string `json:"pausedDatabases,omitempty"`
+
+	// When set to `true`, the operator pauses PgBouncer while the primary
+	// of the cluster is changing, because of a switchover or a failover, and
+	// resumes it as soon as the new primary is ready to accept connections.
+	// The client connections are held instead of failing during the
+	// promotion. Only applies to poolers of type `rw`
+	// +kubebuilder:default:=false
+	// +optional
+	PauseDuringSwitchover *bool `json:"pauseDuringSwitchover,omitempty"`
+
+	// The maximum number of seconds PgBouncer waits, when its pod is
+	// terminated, for the in-flight transactions to complete before
+	// closing the client connections. Internally, the operator issues
+	// PgBouncer's `PAUSE` and `SHUTDOWN` commands. When not set,
+	// PgBouncer waits for the queries without any time limit, until the
+	// termination grace period of the pod expires
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DrainTimeout *int32 `json:"drainTimeout,omitempty"`
+}
+
+// PgBouncerAdminConfiguration contains the settings of the access to
+// the PgBouncer admin console
+type PgBouncerAdminConfiguration struct {
+	// The additional users allowed to connect to the admin console,
+	// rendered in the `admin_users` parameter. The `pgbouncer` user,
+	// used by the operator to pause and resume the pooler, is always
+	// an admin user
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// When set to `true`, the connections to the admin console are
+	// only accepted from the unix socket of PgBouncer, and the ones
+	// coming from the network are rejected
+	// +kubebuilder:default:=false
+	// +optional
+	LocalOnly bool `json:"localOnly,omitempty"`
+}
+
+// PgBouncerTLSConfiguration contains the TLS settings of PgBouncer
+type PgBouncerTLSConfiguration struct {
+	// The secret containing the certificate presented by PgBouncer to the
+	// client applications, in the `tls.crt` key, and its private key, in
+	// the `tls.key` key. Defaults to the server certificate of the cluster
+	// +optional
+	ClientTLSSecret *LocalObjectReference `json:"clientTLSSecret,omitempty"`
+
+	// The secret containing the CA certificate, in the `ca.crt` key, used
+	// to validate the certificates of the client applications. Defaults to
+	// the client CA of the cluster
+	// +optional
+	ClientCASecret *LocalObjectReference `json:"clientCASecret,omitempty"`
+
+	// The TLS mode of the connections coming from the client applications,
+	// rendered in the `client_tls_sslmode` parameter. Default: `prefer`
+	// +kubebuilder:validation:Enum=disable;allow;prefer;require;verify-ca;verify-full
+	// +optional
+	ClientSSLMode PgBouncerTLSMode `json:"clientSSLMode,omitempty"`
+
+	// The TLS mode of the connections toward PostgreSQL, rendered in the
+	// `server_tls_sslmode` parameter. Default: `verify-ca`
+	// +kubebuilder:validation:Enum=require;verify-ca;verify-full
+	// +optional
+	ServerSSLMode PgBouncerTLSMode `json:"serverSSLMode,omitempty"`
+}
+
+// GetClientSSLMode returns the TLS mode of the connections coming
+// from the client applications
+func (in PgBouncerSpec) GetClientSSLMode() PgBouncerTLSMode {
+	if in.TLS == nil || in.TLS.ClientSSLMode == "" {
+		return PgBouncerTLSModePrefer
+	}
+	return in.TLS.ClientSSLMode
+}
+
+// GetServerSSLMode returns the TLS mode of the connections toward PostgreSQL
+func (in PgBouncerSpec) GetServerSSLMode() PgBouncerTLSMode {
+	if in.TLS == nil || in.TLS.ServerSSLMode == "" {
+		return PgBouncerTLSModeVerifyCA
+	}
+	return in.TLS.ServerSSLMode
 }
 
 // IsPaused returns whether all database should be paused or not
@@ -162,6 +386,71 @@ func (in PgBouncerSpec) IsPaused() bool {
 	return in.Paused != nil && *in.Paused
 }
 
+// IsPausedDuringSwitchover returns whether PgBouncer should be paused
+// while the primary of the cluster is changing
+func (in PgBouncerSpec) IsPausedDuringSwitchover() bool {
+	return in.PauseDuringSwitchover != nil && *in.PauseDuringSwitchover
+}
+
+// GetDrainTimeout returns the time PgBouncer waits for the in-flight
+// transactions when its pod is terminated, zero when there's no limit
+func (in PgBouncerSpec) GetDrainTimeout() time.Duration {
+	if in.DrainTimeout == nil {
+		return 0
+	}
+	return time.Duration(*in.DrainTimeout) * time.Second
+}
+
+// GetPoolSettings returns the sizing of the pools used by PgBouncer,
+// considering the dedicated options, the parameters and the defaults
+func (in PgBouncerSpec) GetPoolSettings() (PgBouncerPoolSettings, error) {
+	result := PgBouncerPoolSettings{
+		DefaultPoolSize:       DefaultPgBouncerDefaultPoolSize,
+		ReservePoolSize:       DefaultPgBouncerReservePoolSize,
+		ReservePoolTimeout:    DefaultPgBouncerReservePoolTimeout,
+		MaxPreparedStatements: DefaultPgBouncerMaxPreparedStatements,
+	}
+
+	if value, ok := in.Parameters["default_pool_size"]; ok {
+		size, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return result, fmt.Errorf("invalid default_pool_size %q: %w", value, err)
+		}
+		result.DefaultPoolSize = int32(size)
+	}
+
+	switch value, ok := in.Parameters["reserve_pool_size"]; {
+	case in.ReservePoolSize != nil:
+		result.ReservePoolSize = *in.ReservePoolSize
+	case ok:
+		size, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return result, fmt.Errorf("invalid reserve_pool_size %q: %w", value, err)
+		}
+		result.ReservePoolSize = int32(size)
+	}
+
+	switch value, ok := in.Parameters["reserve_pool_timeout"]; {
+	case in.ReservePoolTimeout != nil:
+		result.ReservePoolTimeout = strconv.Itoa(int(*in.ReservePoolTimeout))
+	case ok:
+		result.ReservePoolTimeout = value
+	}
+
+	switch value, ok := in.Parameters["max_prepared_statements"]; {
+	case in.MaxPreparedStatements != nil:
+		result.MaxPreparedStatements = *in.MaxPreparedStatements
+	case ok:
+		size, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return result, fmt.Errorf("invalid max_prepared_statements %q: %w", value, err)
+		}
+		result.MaxPreparedStatements = int32(size)
+	}
+
+	return result, nil
+}
+
 // PoolerStatus defines the observed state of Pooler
 type PoolerStatus struct {
 	// The resource version of the config object
@@ -170,6 +459,32 @@ type PoolerStatus struct {
 	// The number of pods trying to be scheduled
 	// +optional
 	Instances int32 `json:"instances,omitempty"`
+	// The sizing of the pools currently configured in PgBouncer
+	// +optional
+	PoolSettings *PgBouncerPoolSettings `json:"poolSettings,omitempty"`
+	// Whether PgBouncer is paused by the operator because the primary
+	// of the cluster is changing
+	// +optional
+	PausedDuringSwitchover bool `json:"pausedDuringSwitchover,omitempty"`
+}
+
+// PgBouncerPoolSettings contains the effective sizing of the PgBouncer pools
+// and of the prepared statements they track
+type PgBouncerPoolSettings struct {
+	// The number of server connections allowed for each user/database pair
+	DefaultPoolSize int32 `json:"defaultPoolSize"`
+
+	// The number of additional server connections allowed to a pool
+	ReservePoolSize int32 `json:"reservePoolSize"`
+
+	// The number of seconds a client has to wait before the reserve
+	// pool is used, as written in the PgBouncer configuration
+	ReservePoolTimeout string `json:"reservePoolTimeout"`
+
+	// The maximum number of prepared statements tracked for each connection,
+	// zero when their support is disabled
+	// +optional
+	MaxPreparedStatements int32 `json:"maxPreparedStatements,omitempty"`
 }
 
 // PoolerSecrets contains the versions of all the secrets used
@@ -197,6 +512,16 @@ type PgBouncerSecrets struct {
 	// The auth query secret version
 	// +optional
 	AuthQuery SecretVersion `json:"authQuery,omitempty"`
+
+	// The version of the user-provided secret containing the certificate
+	// presented to the client applications
+	// +optional
+	ClientTLS SecretVersion `json:"clientTLS,omitempty"`
+
+	// The version of the user-provided secret containing the CA used
+	// to validate the certificates of the client applications
+	// +optional
+	ClientCA SecretVersion `json:"clientCA,omitempty"`
 }
 
 // SecretVersion contains a secret name and its ResourceVersion
@@ -246,6 +571,28 @@ func init() {
 	SchemeBuilder.Register(&Pooler{}, &PoolerList{})
 }
 
+// GetClientTLSSecretName returns the name of the user-provided secret
+// containing the certificate presented to the client applications, or
+// an empty string when the server certificate of the cluster is used
+func (in *Pooler) GetClientTLSSecretName() string {
+	if in.Spec.PgBouncer != nil && in.Spec.PgBouncer.TLS != nil && in.Spec.PgBouncer.TLS.ClientTLSSecret != nil {
+		return in.Spec.PgBouncer.TLS.ClientTLSSecret.Name
+	}
+
+	return ""
+}
+
+// GetClientCASecretName returns the name of the user-provided secret
+// containing the CA used to validate the certificates of the client
+// applications, or an empty string when the client CA of the cluster is used
+func (in *Pooler) GetClientCASecretName() string {
+	if in.Spec.PgBouncer != nil && in.Spec.PgBouncer.TLS != nil && in.Spec.PgBouncer.TLS.ClientCASecret != nil {
+		return in.Spec.PgBouncer.TLS.ClientCASecret.Name
+	}
+
+	return ""
+}
+
 // GetAuthQuerySecretName returns the specified AuthQuerySecret name for PgBouncer
 // if provided or the default name otherwise.
 func (in *Pooler) GetAuthQuerySecretName() string {
@@ -265,3 +612,11 @@ func (in *Pooler) GetAuthQuery() string {
 
 	return DefaultPgBouncerPoolerAuthQuery
 }
+
+// IsMetricsTLSEnabled checks if the metrics endpoint should be served
+// over TLS
+func (in *Pooler) IsMetricsTLSEnabled() bool {
+	return in.Spec.Monitoring != nil &&
+		in.Spec.Monitoring.TLSConfig != nil &&
+		in.Spec.Monitoring.TLSConfig.Enabled
+}
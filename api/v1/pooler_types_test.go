@@ -17,6 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	"k8s.io/utils/ptr"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -34,4 +38,107 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+
+	It("pgbouncer has no drain timeout by default", func() {
+		Expect(PgBouncerSpec{}.GetDrainTimeout()).To(BeZero())
+
+		pgbouncer := PgBouncerSpec{DrainTimeout: ptr.To(int32(45))}
+		Expect(pgbouncer.GetDrainTimeout()).To(Equal(45 * time.Second))
+	})
+
+	It("uses the default TLS modes of the pooler", func() {
+		Expect(PgBouncerSpec{}.GetClientSSLMode()).To(Equal(PgBouncerTLSModePrefer))
+		Expect(PgBouncerSpec{}.GetServerSSLMode()).To(Equal(PgBouncerTLSModeVerifyCA))
+
+		pgbouncer := PgBouncerSpec{TLS: &PgBouncerTLSConfiguration{
+			ClientSSLMode: PgBouncerTLSModeRequire,
+			ServerSSLMode: PgBouncerTLSModeVerifyFull,
+		}}
+		Expect(pgbouncer.GetClientSSLMode()).To(Equal(PgBouncerTLSModeRequire))
+		Expect(pgbouncer.GetServerSSLMode()).To(Equal(PgBouncerTLSModeVerifyFull))
+	})
+
+	It("returns the names of the user-provided TLS secrets", func() {
+		pooler := Pooler{Spec: PoolerSpec{PgBouncer: &PgBouncerSpec{}}}
+		Expect(pooler.GetClientTLSSecretName()).To(BeEmpty())
+		Expect(pooler.GetClientCASecretName()).To(BeEmpty())
+
+		pooler.Spec.PgBouncer.TLS = &PgBouncerTLSConfiguration{
+			ClientTLSSecret: &LocalObjectReference{Name: "pooler-tls"},
+			ClientCASecret:  &LocalObjectReference{Name: "pooler-client-ca"},
+		}
+		Expect(pooler.GetClientTLSSecretName()).To(Equal("pooler-tls"))
+		Expect(pooler.GetClientCASecretName()).To(Equal("pooler-client-ca"))
+	})
+
+	It("uses the PgBouncer defaults for the pools sizing", func() {
+		settings, err := PgBouncerSpec{}.GetPoolSettings()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(PgBouncerPoolSettings{
+			DefaultPoolSize:    DefaultPgBouncerDefaultPoolSize,
+			ReservePoolSize:    DefaultPgBouncerReservePoolSize,
+			ReservePoolTimeout: DefaultPgBouncerReservePoolTimeout,
+		}))
+	})
+
+	It("considers both the parameters and the dedicated options for the pools sizing", func() {
+		pgbouncer := PgBouncerSpec{
+			ReservePoolSize: ptr.To(int32(5)),
+			Parameters: map[string]string{
+				"default_pool_size":    "30",
+				"reserve_pool_timeout": "2.5",
+			},
+		}
+		settings, err := pgbouncer.GetPoolSettings()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(PgBouncerPoolSettings{
+			DefaultPoolSize:    30,
+			ReservePoolSize:    5,
+			ReservePoolTimeout: "2.5",
+		}))
+
+		pgbouncer.ReservePoolTimeout = ptr.To(int32(3))
+		settings, err = pgbouncer.GetPoolSettings()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.ReservePoolTimeout).To(Equal("3"))
+	})
+
+	It("complains when the pool sizes are not integers", func() {
+		_, err := PgBouncerSpec{Parameters: map[string]string{"default_pool_size": "big"}}.GetPoolSettings()
+		Expect(err).To(HaveOccurred())
+
+		_, err = PgBouncerSpec{Parameters: map[string]string{"reserve_pool_size": "1.5"}}.GetPoolSettings()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("considers both the parameter and the dedicated option for the prepared statements", func() {
+		pgbouncer := PgBouncerSpec{
+			Parameters: map[string]string{"max_prepared_statements": "100"},
+		}
+		settings, err := pgbouncer.GetPoolSettings()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.MaxPreparedStatements).To(BeEquivalentTo(100))
+
+		pgbouncer = PgBouncerSpec{MaxPreparedStatements: ptr.To(int32(200))}
+		settings, err = pgbouncer.GetPoolSettings()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.MaxPreparedStatements).To(BeEquivalentTo(200))
+
+		_, err = PgBouncerSpec{Parameters: map[string]string{"max_prepared_statements": "many"}}.GetPoolSettings()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("serves the metrics over TLS only when enabled", func() {
+		pooler := Pooler{}
+		Expect(pooler.IsMetricsTLSEnabled()).To(BeFalse())
+
+		pooler.Spec.Monitoring = &PoolerMonitoringConfiguration{}
+		Expect(pooler.IsMetricsTLSEnabled()).To(BeFalse())
+
+		pooler.Spec.Monitoring.TLSConfig = &PoolerMonitoringTLSConfiguration{}
+		Expect(pooler.IsMetricsTLSEnabled()).To(BeFalse())
+
+		pooler.Spec.Monitoring.TLSConfig.Enabled = true
+		Expect(pooler.IsMetricsTLSEnabled()).To(BeTrue())
+	})
 })
@@ -17,9 +17,15 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -27,6 +33,7 @@ import (
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 var (
@@ -94,11 +101,12 @@ func (r *Pooler) ValidateCreate() (admission.Warnings, error) {
 	poolerLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
 
 	allErrs = r.Validate()
+	allWarnings := r.getAdmissionWarnings()
 	if len(allErrs) == 0 {
-		return nil, nil
+		return allWarnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(
+	return allWarnings, apierrors.NewInvalid(
 		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Pooler"},
 		r.Name, allErrs)
 }
@@ -109,11 +117,12 @@ func (r *Pooler) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
 	poolerLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
 
 	allErrs = r.Validate()
+	allWarnings := r.getAdmissionWarnings()
 	if len(allErrs) == 0 {
-		return nil, nil
+		return allWarnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(
+	return allWarnings, apierrors.NewInvalid(
 		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Pooler"},
 		r.Name, allErrs)
 }
@@ -147,6 +156,87 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 	}
 
 	result = append(result, r.validatePgbouncerGenericParameters()...)
+	result = append(result, r.validateReservePool()...)
+	result = append(result, r.validateMaxPreparedStatements()...)
+	result = append(result, r.validateTCPKeepalives()...)
+	result = append(result, r.validateAdmin()...)
+	result = append(result, r.validateDrainTimeout()...)
+	result = append(result, r.validatePausedDatabases()...)
+
+	return result
+}
+
+// validatePausedDatabases checks that the paused databases are valid
+// PgBouncer database names, and are not repeated
+func (r *Pooler) validatePausedDatabases() field.ErrorList {
+	if r.Spec.PgBouncer == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "pgbouncer", "pausedDatabases")
+	seen := stringset.New()
+	for idx, name := range r.Spec.PgBouncer.PausedDatabases {
+		switch {
+		case name == "":
+			result = append(result, field.Invalid(path.Index(idx), name, "cannot be empty"))
+		case len(name) > 63:
+			result = append(result, field.Invalid(path.Index(idx), name, "cannot be longer than 63 characters"))
+		case name == "pgbouncer":
+			result = append(result, field.Invalid(path.Index(idx), name,
+				"the pgbouncer administrative database cannot be paused"))
+		case seen.Has(name):
+			result = append(result, field.Duplicate(path.Index(idx), name))
+		}
+		seen.Put(name)
+	}
+
+	return result
+}
+
+// validateDrainTimeout checks that the pod is not killed before the end
+// of the drain of the connections
+func (r *Pooler) validateDrainTimeout() field.ErrorList {
+	if r.Spec.PgBouncer == nil || r.Spec.PgBouncer.DrainTimeout == nil ||
+		r.Spec.Template == nil || r.Spec.Template.Spec.TerminationGracePeriodSeconds == nil {
+		return nil
+	}
+
+	drainTimeout := *r.Spec.PgBouncer.DrainTimeout
+	if *r.Spec.Template.Spec.TerminationGracePeriodSeconds < int64(drainTimeout) {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "drainTimeout"),
+				drainTimeout,
+				"cannot be greater than the terminationGracePeriodSeconds of the pod template"),
+		}
+	}
+
+	return nil
+}
+
+// validateAdmin checks the users allowed to connect to the admin console,
+// whose names are rendered in a comma separated list
+func (r *Pooler) validateAdmin() field.ErrorList {
+	if r.Spec.PgBouncer == nil || r.Spec.PgBouncer.Admin == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "pgbouncer", "admin", "users")
+	users := make(map[string]bool, len(r.Spec.PgBouncer.Admin.Users))
+	for i, user := range r.Spec.PgBouncer.Admin.Users {
+		switch {
+		case user == "":
+			result = append(result, field.Invalid(path.Index(i), user, "cannot be empty"))
+		case strings.ContainsAny(user, ", \t\n\"'"):
+			result = append(result, field.Invalid(path.Index(i), user,
+				"cannot contain commas, quotes or whitespaces"))
+		case users[user]:
+			result = append(result, field.Duplicate(path.Index(i), user))
+		}
+		users[user] = true
+	}
 
 	return result
 }
@@ -173,9 +263,208 @@ func (r *Pooler) validateCluster() field.ErrorList {
 func (r *Pooler) Validate() (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validatePgBouncer()...)
 	allErrs = append(allErrs, r.validateCluster()...)
+	allErrs = append(allErrs, r.validateImageName()...)
+	allErrs = append(allErrs, r.validateEnv()...)
 	return allErrs
 }
 
+// validateReservePool checks that the reserve pool is not configured
+// twice and that it is not bigger than the default pool
+func (r *Pooler) validateReservePool() field.ErrorList {
+	var result field.ErrorList
+	if r.Spec.PgBouncer == nil {
+		return result
+	}
+
+	pgbouncerPath := field.NewPath("spec", "pgbouncer")
+	if _, ok := r.Spec.PgBouncer.Parameters["reserve_pool_size"]; ok && r.Spec.PgBouncer.ReservePoolSize != nil {
+		result = append(result,
+			field.Invalid(
+				pgbouncerPath.Child("reservePoolSize"),
+				*r.Spec.PgBouncer.ReservePoolSize,
+				"cannot be specified together with the reserve_pool_size parameter"))
+	}
+	if _, ok := r.Spec.PgBouncer.Parameters["reserve_pool_timeout"]; ok && r.Spec.PgBouncer.ReservePoolTimeout != nil {
+		result = append(result,
+			field.Invalid(
+				pgbouncerPath.Child("reservePoolTimeout"),
+				*r.Spec.PgBouncer.ReservePoolTimeout,
+				"cannot be specified together with the reserve_pool_timeout parameter"))
+	}
+
+	settings, err := r.Spec.PgBouncer.GetPoolSettings()
+	if err != nil {
+		return append(result,
+			field.Invalid(
+				pgbouncerPath.Child("parameters"),
+				r.Spec.PgBouncer.Parameters,
+				err.Error()))
+	}
+
+	if settings.ReservePoolSize > settings.DefaultPoolSize {
+		result = append(result,
+			field.Invalid(
+				pgbouncerPath.Child("reservePoolSize"),
+				settings.ReservePoolSize,
+				fmt.Sprintf("the reserve pool cannot be bigger than the default pool (%d)",
+					settings.DefaultPoolSize)))
+	}
+
+	return result
+}
+
+// validateMaxPreparedStatements checks that the prepared statements are not
+// configured twice
+func (r *Pooler) validateMaxPreparedStatements() field.ErrorList {
+	if r.Spec.PgBouncer == nil || r.Spec.PgBouncer.MaxPreparedStatements == nil {
+		return nil
+	}
+
+	if _, ok := r.Spec.PgBouncer.Parameters["max_prepared_statements"]; ok {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "maxPreparedStatements"),
+				*r.Spec.PgBouncer.MaxPreparedStatements,
+				"cannot be specified together with the max_prepared_statements parameter"),
+		}
+	}
+
+	return nil
+}
+
+// getAdmissionWarnings returns the warnings about the configuration of
+// the pooler that are not errors
+func (r *Pooler) getAdmissionWarnings() admission.Warnings {
+	return append(r.getMaxPreparedStatementsWarnings(), r.getEnvWarnings()...)
+}
+
+// poolerOperatorEnvironmentVariables are the environment variables of the
+// PgBouncer container managed by the operator
+var poolerOperatorEnvironmentVariables = []string{"NAMESPACE", "POOLER_NAME"}
+
+// validateEnv checks that the environment variables of the PgBouncer
+// container have valid and unique names
+func (r *Pooler) validateEnv() field.ErrorList {
+	var result field.ErrorList
+
+	path := field.NewPath("spec", "env")
+	names := make(map[string]bool, len(r.Spec.Env))
+	for i, env := range r.Spec.Env {
+		namePath := path.Index(i).Child("name")
+		for _, msg := range validation.IsEnvVarName(env.Name) {
+			result = append(result, field.Invalid(namePath, env.Name, msg))
+		}
+		if names[env.Name] {
+			result = append(result, field.Duplicate(namePath, env.Name))
+		}
+		names[env.Name] = true
+	}
+
+	return result
+}
+
+// getEnvWarnings warns about the environment variables that are ignored
+// because they are managed by the operator
+func (r *Pooler) getEnvWarnings() admission.Warnings {
+	var result admission.Warnings
+	for _, env := range r.Spec.Env {
+		if slices.Contains(poolerOperatorEnvironmentVariables, env.Name) {
+			result = append(result, fmt.Sprintf(
+				"The %s environment variable is managed by the operator, and the value in spec.env is ignored",
+				env.Name))
+		}
+	}
+
+	return result
+}
+
+// getMaxPreparedStatementsWarnings warns when the prepared statements are
+// enabled with a PgBouncer image too old to support them. Images whose
+// version can't be detected from the tag are not reported
+func (r *Pooler) getMaxPreparedStatementsWarnings() admission.Warnings {
+	if r.Spec.PgBouncer == nil {
+		return nil
+	}
+
+	settings, err := r.Spec.PgBouncer.GetPoolSettings()
+	if err != nil || settings.MaxPreparedStatements == 0 {
+		return nil
+	}
+
+	imageName := r.getCustomImageName()
+	if imageName == "" {
+		return nil
+	}
+
+	version, err := semver.ParseTolerant(strings.SplitN(utils.GetImageTag(imageName), "-", 2)[0])
+	if err != nil || version.GE(semver.MustParse(MinPgBouncerVersionForPreparedStatements)) {
+		return nil
+	}
+
+	return admission.Warnings{
+		fmt.Sprintf(
+			"max_prepared_statements requires PgBouncer %s or later, while the image %q provides "+
+				"PgBouncer %s: the prepared statements won't be supported",
+			MinPgBouncerVersionForPreparedStatements, imageName, version),
+	}
+}
+
+// getCustomImageName gets the PgBouncer image chosen by the user, if any,
+// preferring the one of the pod template
+func (r *Pooler) getCustomImageName() string {
+	if r.Spec.Template != nil {
+		for _, container := range r.Spec.Template.Spec.Containers {
+			if container.Name == "pgbouncer" && container.Image != "" {
+				return container.Image
+			}
+		}
+	}
+
+	return r.Spec.ImageName
+}
+
+// validateTCPKeepalives checks the TCP keepalive settings of PgBouncer,
+// which cannot be used when the keepalives are disabled via parameters
+func (r *Pooler) validateTCPKeepalives() field.ErrorList {
+	if r.Spec.PgBouncer == nil || r.Spec.PgBouncer.TCPKeepalives == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "pgbouncer", "tcpKeepalives")
+	result := validateTCPKeepalivesConfiguration(
+		path,
+		r.Spec.PgBouncer.TCPKeepalives,
+		r.Spec.PgBouncer.Parameters,
+		[3]string{"tcp_keepidle", "tcp_keepintvl", "tcp_keepcnt"},
+	)
+	if _, ok := r.Spec.PgBouncer.Parameters["tcp_keepalive"]; ok {
+		result = append(result,
+			field.Invalid(
+				path,
+				"",
+				"cannot be specified together with the tcp_keepalive parameter"))
+	}
+
+	return result
+}
+
+// validateImageName checks that the PgBouncer image, when specified,
+// is a well-formed image reference
+func (r *Pooler) validateImageName() field.ErrorList {
+	var result field.ErrorList
+	if r.Spec.ImageName == "" {
+		return result
+	}
+
+	if err := utils.ValidateImageName(r.Spec.ImageName); err != nil {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "imageName"),
+				r.Spec.ImageName, err.Error()))
+	}
+	return result
+}
+
 // validatePgbouncerGenericParameters validates pgbouncer parameters
 func (r *Pooler) validatePgbouncerGenericParameters() field.ErrorList {
 	var result field.ErrorList
@@ -17,7 +17,12 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -128,4 +133,322 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("does not complain when the image name is not specified", func() {
+		pooler := Pooler{}
+		Expect(pooler.validateImageName()).To(BeEmpty())
+	})
+
+	It("does not complain when given a valid image name", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				ImageName: "mirror.example.com:5000/pgbouncer/pgbouncer:1.21.0",
+			},
+		}
+		Expect(pooler.validateImageName()).To(BeEmpty())
+	})
+
+	It("does complain when given a malformed image name", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				ImageName: "mirror.example.com/PgBouncer:1.21.0:latest",
+			},
+		}
+		Expect(pooler.validateImageName()).To(HaveLen(1))
+	})
+
+	Describe("reserve pool validation", func() {
+		It("allows a reserve pool smaller than the default pool", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						ReservePoolSize:    ptr.To(int32(5)),
+						ReservePoolTimeout: ptr.To(int32(3)),
+					},
+				},
+			}
+			Expect(pooler.validateReservePool()).To(BeEmpty())
+		})
+
+		It("complains when the reserve pool is bigger than the default one", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						ReservePoolSize: ptr.To(int32(15)),
+						Parameters:      map[string]string{"default_pool_size": "10"},
+					},
+				},
+			}
+			Expect(pooler.validateReservePool()).To(HaveLen(1))
+
+			pooler.Spec.PgBouncer.ReservePoolSize = nil
+			pooler.Spec.PgBouncer.Parameters["reserve_pool_size"] = "15"
+			Expect(pooler.validateReservePool()).To(HaveLen(1))
+		})
+
+		It("complains when the reserve pool is configured twice", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						ReservePoolSize:    ptr.To(int32(5)),
+						ReservePoolTimeout: ptr.To(int32(3)),
+						Parameters: map[string]string{
+							"reserve_pool_size":    "5",
+							"reserve_pool_timeout": "3",
+						},
+					},
+				},
+			}
+			Expect(pooler.validateReservePool()).To(HaveLen(2))
+		})
+
+		It("complains when the default pool size is not an integer", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						ReservePoolSize: ptr.To(int32(5)),
+						Parameters:      map[string]string{"default_pool_size": "twenty"},
+					},
+				},
+			}
+			Expect(pooler.validateReservePool()).To(HaveLen(1))
+		})
+	})
+
+	Describe("prepared statements validation", func() {
+		newPooler := func(imageName string) *Pooler {
+			return &Pooler{
+				Spec: PoolerSpec{
+					ImageName: imageName,
+					PgBouncer: &PgBouncerSpec{
+						MaxPreparedStatements: ptr.To(int32(100)),
+					},
+				},
+			}
+		}
+
+		It("complains when the prepared statements are configured twice", func() {
+			pooler := newPooler("")
+			Expect(pooler.validateMaxPreparedStatements()).To(BeEmpty())
+
+			pooler.Spec.PgBouncer.Parameters = map[string]string{"max_prepared_statements": "100"}
+			Expect(pooler.validateMaxPreparedStatements()).To(HaveLen(1))
+		})
+
+		It("doesn't warn with the default image or a recent one", func() {
+			Expect(newPooler("").getAdmissionWarnings()).To(BeEmpty())
+			Expect(newPooler("ghcr.io/cloudnative-pg/pgbouncer:1.21.0").getAdmissionWarnings()).To(BeEmpty())
+			Expect(newPooler("ghcr.io/cloudnative-pg/pgbouncer:1.22.1-3").getAdmissionWarnings()).To(BeEmpty())
+		})
+
+		It("doesn't warn when the version can't be detected", func() {
+			Expect(newPooler("ghcr.io/cloudnative-pg/pgbouncer:latest").getAdmissionWarnings()).To(BeEmpty())
+			Expect(newPooler("ghcr.io/cloudnative-pg/pgbouncer").getAdmissionWarnings()).To(BeEmpty())
+		})
+
+		It("warns when the image is too old to support them", func() {
+			Expect(newPooler("ghcr.io/cloudnative-pg/pgbouncer:1.20.1").getAdmissionWarnings()).To(HaveLen(1))
+			Expect(newPooler("ghcr.io/cloudnative-pg/pgbouncer:1.19.0-2").getAdmissionWarnings()).To(HaveLen(1))
+
+			pooler := newPooler("")
+			pooler.Spec.PgBouncer.MaxPreparedStatements = nil
+			pooler.Spec.PgBouncer.Parameters = map[string]string{"max_prepared_statements": "50"}
+			pooler.Spec.Template = &PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "pgbouncer", Image: "ghcr.io/cloudnative-pg/pgbouncer:1.18.0"},
+					},
+				},
+			}
+			Expect(pooler.getAdmissionWarnings()).To(HaveLen(1))
+		})
+
+		It("doesn't warn when the prepared statements are disabled", func() {
+			pooler := newPooler("ghcr.io/cloudnative-pg/pgbouncer:1.20.1")
+			pooler.Spec.PgBouncer.MaxPreparedStatements = ptr.To(int32(0))
+			Expect(pooler.getAdmissionWarnings()).To(BeEmpty())
+		})
+	})
+
+	Describe("environment variables validation", func() {
+		newPooler := func(env ...corev1.EnvVar) *Pooler {
+			return &Pooler{
+				Spec: PoolerSpec{
+					Env:       env,
+					PgBouncer: &PgBouncerSpec{},
+				},
+			}
+		}
+
+		It("allows valid names", func() {
+			pooler := newPooler(
+				corev1.EnvVar{Name: "AGENT_ENDPOINT", Value: "http://agent:4317"},
+				corev1.EnvVar{Name: "agent.level", Value: "debug"},
+			)
+			Expect(pooler.validateEnv()).To(BeEmpty())
+			Expect(pooler.getAdmissionWarnings()).To(BeEmpty())
+		})
+
+		It("complains about invalid names", func() {
+			result := newPooler(
+				corev1.EnvVar{Name: "1AGENT"},
+				corev1.EnvVar{Name: "AGENT=LEVEL"},
+				corev1.EnvVar{Name: ""},
+			).validateEnv()
+			Expect(result).To(HaveLen(3))
+			Expect(result[0].Field).To(Equal("spec.env[0].name"))
+		})
+
+		It("complains about duplicated names", func() {
+			result := newPooler(
+				corev1.EnvVar{Name: "AGENT_LEVEL", Value: "debug"},
+				corev1.EnvVar{Name: "AGENT_LEVEL", Value: "info"},
+			).validateEnv()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.env[1].name"))
+		})
+
+		It("warns about the variables managed by the operator", func() {
+			pooler := newPooler(corev1.EnvVar{Name: "POOLER_NAME", Value: "mine"})
+			Expect(pooler.validateEnv()).To(BeEmpty())
+			Expect(pooler.getAdmissionWarnings()).To(HaveLen(1))
+		})
+	})
+
+	Describe("TCP keepalives validation", func() {
+		It("allows sane keepalive settings", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						TCPKeepalives: &TCPKeepalivesConfiguration{
+							Idle:     ptr.To(int32(60)),
+							Interval: ptr.To(int32(10)),
+							Count:    ptr.To(int32(5)),
+						},
+					},
+				},
+			}
+			Expect(pooler.validateTCPKeepalives()).To(BeEmpty())
+		})
+
+		It("complains about out of range settings", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						TCPKeepalives: &TCPKeepalivesConfiguration{
+							Idle:  ptr.To(int32(0)),
+							Count: ptr.To(int32(128)),
+						},
+					},
+				},
+			}
+			Expect(pooler.validateTCPKeepalives()).To(HaveLen(2))
+		})
+
+		It("complains when the keepalives are configured twice", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						TCPKeepalives: &TCPKeepalivesConfiguration{
+							Idle: ptr.To(int32(60)),
+						},
+						Parameters: map[string]string{
+							"tcp_keepidle":  "30",
+							"tcp_keepalive": "0",
+						},
+					},
+				},
+			}
+			Expect(pooler.validateTCPKeepalives()).To(HaveLen(2))
+		})
+	})
+
+	Describe("admin console validation", func() {
+		It("allows additional admin users", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						Admin: &PgBouncerAdminConfiguration{
+							Users:     []string{"dba", "pgbouncer"},
+							LocalOnly: true,
+						},
+					},
+				},
+			}
+			Expect(pooler.validateAdmin()).To(BeEmpty())
+		})
+
+		It("complains about invalid and duplicated users", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						Admin: &PgBouncerAdminConfiguration{
+							Users: []string{"dba", "", "dba,app", "my user", "dba"},
+						},
+					},
+				},
+			}
+			errs := pooler.validateAdmin()
+			Expect(errs).To(HaveLen(4))
+			Expect(errs[0].Field).To(Equal("spec.pgbouncer.admin.users[1]"))
+			Expect(errs[3].Type).To(Equal(field.ErrorTypeDuplicate))
+		})
+	})
+
+	Describe("drain timeout validation", func() {
+		poolerWithGracePeriod := func(drainTimeout int32, gracePeriod *int64) Pooler {
+			return Pooler{
+				Spec: PoolerSpec{
+					Template: &PodTemplateSpec{
+						Spec: corev1.PodSpec{TerminationGracePeriodSeconds: gracePeriod},
+					},
+					PgBouncer: &PgBouncerSpec{DrainTimeout: ptr.To(drainTimeout)},
+				},
+			}
+		}
+
+		It("allows a drain timeout when the pod template has no grace period", func() {
+			pooler := poolerWithGracePeriod(60, nil)
+			Expect(pooler.validateDrainTimeout()).To(BeEmpty())
+		})
+
+		It("allows a drain timeout fitting in the grace period of the pod", func() {
+			pooler := poolerWithGracePeriod(60, ptr.To(int64(60)))
+			Expect(pooler.validateDrainTimeout()).To(BeEmpty())
+		})
+
+		It("complains when the pod would be killed during the drain", func() {
+			pooler := poolerWithGracePeriod(60, ptr.To(int64(30)))
+			errs := pooler.validateDrainTimeout()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.pgbouncer.drainTimeout"))
+		})
+	})
+
+	Describe("paused databases validation", func() {
+		It("allows pausing databases", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{PausedDatabases: []string{"app", "reporting"}},
+				},
+			}
+			Expect(pooler.validatePausedDatabases()).To(BeEmpty())
+		})
+
+		It("complains about invalid and duplicated databases", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					PgBouncer: &PgBouncerSpec{
+						PausedDatabases: []string{"app", "", "pgbouncer", strings.Repeat("a", 64), "app"},
+					},
+				},
+			}
+			errs := pooler.validatePausedDatabases()
+			Expect(errs).To(HaveLen(4))
+			Expect(errs[0].Field).To(Equal("spec.pgbouncer.pausedDatabases[1]"))
+			Expect(errs[1].Field).To(Equal("spec.pgbouncer.pausedDatabases[2]"))
+			Expect(errs[2].Field).To(Equal("spec.pgbouncer.pausedDatabases[3]"))
+			Expect(errs[3].Type).To(Equal(field.ErrorTypeDuplicate))
+		})
+	})
 })
@@ -102,7 +102,8 @@ func (r *ScheduledBackup) validate() field.ErrorList {
 		))
 	}
 
-	if r.Spec.Method == BackupMethodBarmanObjectStore && r.Spec.Online != nil {
+	if (r.Spec.Method == BackupMethodBarmanObjectStore || r.Spec.Method == BackupMethodVolumeBackup) &&
+		r.Spec.Online != nil {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "online"),
 			r.Spec.Online,
@@ -110,7 +111,8 @@ func (r *ScheduledBackup) validate() field.ErrorList {
 		))
 	}
 
-	if r.Spec.Method == BackupMethodBarmanObjectStore && r.Spec.OnlineConfiguration != nil {
+	if (r.Spec.Method == BackupMethodBarmanObjectStore || r.Spec.Method == BackupMethodVolumeBackup) &&
+		r.Spec.OnlineConfiguration != nil {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "onlineConfiguration"),
 			r.Spec.OnlineConfiguration,
@@ -77,6 +77,31 @@ func (in *AffinityConfiguration) DeepCopy() *AffinityConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutovacuumConfiguration) DeepCopyInto(out *AutovacuumConfiguration) {
+	*out = *in
+	if in.MaxWorkers != nil {
+		in, out := &in.MaxWorkers, &out.MaxWorkers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VacuumCostLimit != nil {
+		in, out := &in.VacuumCostLimit, &out.VacuumCostLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutovacuumConfiguration.
+func (in *AutovacuumConfiguration) DeepCopy() *AutovacuumConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AutovacuumConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureCredentials) DeepCopyInto(out *AzureCredentials) {
 	*out = *in
@@ -152,6 +177,23 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeBackup != nil {
+		in, out := &in.VolumeBackup, &out.VolumeBackup
+		*out = new(VolumeBackupConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalWalObjectStores != nil {
+		in, out := &in.AdditionalWalObjectStores, &out.AdditionalWalObjectStores
+		*out = make([]BarmanObjectStoreConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WalArchiveQuorum != nil {
+		in, out := &in.WalArchiveQuorum, &out.WalArchiveQuorum
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -287,6 +329,11 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(SecretKeySelector)
 		**out = **in
 	}
+	if in.MaxBandwidth != nil {
+		in, out := &in.MaxBandwidth, &out.MaxBandwidth
+		*out = new(int64)
+		**out = **in
+	}
 	if in.StartedAt != nil {
 		in, out := &in.StartedAt, &out.StartedAt
 		*out = (*in).DeepCopy()
@@ -358,6 +405,21 @@ func (in *BarmanCredentials) DeepCopy() *BarmanCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BarmanEncryptionConfiguration) DeepCopyInto(out *BarmanEncryptionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarmanEncryptionConfiguration.
+func (in *BarmanEncryptionConfiguration) DeepCopy() *BarmanEncryptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BarmanEncryptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreConfiguration) {
 	*out = *in
@@ -391,6 +453,16 @@ func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreCon
 			(*out)[key] = val
 		}
 	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BarmanEncryptionConfiguration)
+		**out = **in
+	}
+	if in.MaxBandwidth != nil {
+		in, out := &in.MaxBandwidth, &out.MaxBandwidth
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarmanObjectStoreConfiguration.
@@ -521,11 +593,21 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(DataSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeBackup != nil {
+		in, out := &in.VolumeBackup, &out.VolumeBackup
+		*out = new(VolumeBackupSource)
+		**out = **in
+	}
 	if in.RecoveryTarget != nil {
 		in, out := &in.RecoveryTarget, &out.RecoveryTarget
 		*out = new(RecoveryTarget)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WalRestoreMaxBandwidth != nil {
+		in, out := &in.WalRestoreMaxBandwidth, &out.WalRestoreMaxBandwidth
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Secret != nil {
 		in, out := &in.Secret, &out.Secret
 		*out = new(LocalObjectReference)
@@ -654,6 +736,13 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
+	if in.InstanceOverrides != nil {
+		in, out := &in.InstanceOverrides, &out.InstanceOverrides
+		*out = make([]InstanceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
@@ -700,6 +789,31 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LogStorage != nil {
+		in, out := &in.LogStorage, &out.LogStorage
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quarantine != nil {
+		in, out := &in.Quarantine, &out.Quarantine
+		*out = new(QuarantineConfiguration)
+		**out = **in
+	}
+	if in.DiskPressureSwitchover != nil {
+		in, out := &in.DiskPressureSwitchover, &out.DiskPressureSwitchover
+		*out = new(DiskPressureSwitchoverConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
@@ -714,6 +828,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EphemeralVolumesSizeLimitConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(TopologyConfiguration)
+		**out = **in
+	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
@@ -724,6 +843,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowConfiguration)
+		**out = **in
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
@@ -755,11 +879,30 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Managed != nil {
 		in, out := &in.Managed, &out.Managed
 		*out = new(ManagedConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(LifecycleConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SeccompProfile != nil {
 		in, out := &in.SeccompProfile, &out.SeccompProfile
 		*out = new(corev1.SeccompProfile)
@@ -804,6 +947,28 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	in.ManagedRolesStatus.DeepCopyInto(&out.ManagedRolesStatus)
+	in.ManagedPublicationsStatus.DeepCopyInto(&out.ManagedPublicationsStatus)
+	in.ManagedSubscriptionsStatus.DeepCopyInto(&out.ManagedSubscriptionsStatus)
+	in.ManagedTablesStatus.DeepCopyInto(&out.ManagedTablesStatus)
+	if in.ManagedRoleTimeoutsStatus != nil {
+		in, out := &in.ManagedRoleTimeoutsStatus, &out.ManagedRoleTimeoutsStatus
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedSQLJobsStatus != nil {
+		in, out := &in.ManagedSQLJobsStatus, &out.ManagedSQLJobsStatus
+		*out = make(map[string]SQLJobStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MaintenanceJobsStatus != nil {
+		in, out := &in.MaintenanceJobsStatus, &out.MaintenanceJobsStatus
+		*out = make(map[string]MaintenanceJobStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -833,6 +998,20 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	in.SecretsResourceVersion.DeepCopyInto(&out.SecretsResourceVersion)
 	in.ConfigMapResourceVersion.DeepCopyInto(&out.ConfigMapResourceVersion)
 	in.Certificates.DeepCopyInto(&out.Certificates)
+	if in.StartupFailures != nil {
+		in, out := &in.StartupFailures, &out.StartupFailures
+		*out = make(map[string]StartupFailures, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ChecksumFailures != nil {
+		in, out := &in.ChecksumFailures, &out.ChecksumFailures
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
@@ -850,6 +1029,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostImportAnalyze != nil {
+		in, out := &in.PostImportAnalyze, &out.PostImportAnalyze
+		*out = new(PostImportAnalyzeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -941,6 +1125,26 @@ func (in *DataSource) DeepCopy() *DataSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskPressureSwitchoverConfiguration) DeepCopyInto(out *DiskPressureSwitchoverConfiguration) {
+	*out = *in
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskPressureSwitchoverConfiguration.
+func (in *DiskPressureSwitchoverConfiguration) DeepCopy() *DiskPressureSwitchoverConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskPressureSwitchoverConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1042,6 +1246,26 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverConfiguration) DeepCopyInto(out *FailoverConfiguration) {
+	*out = *in
+	if in.MaxDataLossBytes != nil {
+		in, out := &in.MaxDataLossBytes, &out.MaxDataLossBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverConfiguration.
+func (in *FailoverConfiguration) DeepCopy() *FailoverConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
 	*out = *in
@@ -1062,6 +1286,21 @@ func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentMapEntry) DeepCopyInto(out *IdentMapEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentMapEntry.
+func (in *IdentMapEntry) DeepCopy() *IdentMapEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentMapEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Import) DeepCopyInto(out *Import) {
 	*out = *in
@@ -1123,6 +1362,28 @@ func (in *InstanceID) DeepCopy() *InstanceID {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceOverride) DeepCopyInto(out *InstanceOverride) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceOverride.
+func (in *InstanceOverride) DeepCopy() *InstanceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
 	*out = *in
@@ -1198,6 +1459,31 @@ func (in *LDAPConfig) DeepCopy() *LDAPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleConfiguration) DeepCopyInto(out *LifecycleConfiguration) {
+	*out = *in
+	if in.PostStartSQL != nil {
+		in, out := &in.PostStartSQL, &out.PostStartSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreStopSQL != nil {
+		in, out := &in.PreStopSQL, &out.PreStopSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleConfiguration.
+func (in *LifecycleConfiguration) DeepCopy() *LifecycleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
 	*out = *in
@@ -1213,6 +1499,73 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJobConfiguration) DeepCopyInto(out *MaintenanceJobConfiguration) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJobConfiguration.
+func (in *MaintenanceJobConfiguration) DeepCopy() *MaintenanceJobConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJobConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceJobStatus) DeepCopyInto(out *MaintenanceJobStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceJobStatus.
+func (in *MaintenanceJobStatus) DeepCopy() *MaintenanceJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowConfiguration) DeepCopyInto(out *MaintenanceWindowConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowConfiguration.
+func (in *MaintenanceWindowConfiguration) DeepCopy() *MaintenanceWindowConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 	*out = *in
@@ -1223,6 +1576,41 @@ func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Publications != nil {
+		in, out := &in.Publications, &out.Publications
+		*out = make([]PublicationConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]SubscriptionConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]TableConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SQLJobs != nil {
+		in, out := &in.SQLJobs, &out.SQLJobs
+		*out = make([]SQLJobConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaintenanceJobs != nil {
+		in, out := &in.MaintenanceJobs, &out.MaintenanceJobs
+		*out = make([]MaintenanceJobConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
@@ -1236,11 +1624,11 @@ func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
+func (in *ManagedPublications) DeepCopyInto(out *ManagedPublications) {
 	*out = *in
-	if in.ByStatus != nil {
-		in, out := &in.ByStatus, &out.ByStatus
-		*out = make(map[RoleStatus][]string, len(*in))
+	if in.Reconciled != nil {
+		in, out := &in.Reconciled, &out.Reconciled
+		*out = make(map[string][]string, len(*in))
 		for key, val := range *in {
 			var outVal []string
 			if val == nil {
@@ -1270,31 +1658,172 @@ func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
 			(*out)[key] = outVal
 		}
 	}
-	if in.PasswordStatus != nil {
-		in, out := &in.PasswordStatus, &out.PasswordStatus
-		*out = make(map[string]PasswordState, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
-func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedPublications.
+func (in *ManagedPublications) DeepCopy() *ManagedPublications {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedRoles)
+	out := new(ManagedPublications)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Metadata) DeepCopyInto(out *Metadata) {
+func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
+	if in.ByStatus != nil {
+		in, out := &in.ByStatus, &out.ByStatus
+		*out = make(map[RoleStatus][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PasswordStatus != nil {
+		in, out := &in.PasswordStatus, &out.PasswordStatus
+		*out = make(map[string]PasswordState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
+func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRoles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedSubscriptions) DeepCopyInto(out *ManagedSubscriptions) {
+	*out = *in
+	if in.Reconciled != nil {
+		in, out := &in.Reconciled, &out.Reconciled
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedSubscriptions.
+func (in *ManagedSubscriptions) DeepCopy() *ManagedSubscriptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedSubscriptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedTables) DeepCopyInto(out *ManagedTables) {
+	*out = *in
+	if in.Reconciled != nil {
+		in, out := &in.Reconciled, &out.Reconciled
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedTables.
+func (in *ManagedTables) DeepCopy() *ManagedTables {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedTables)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metadata) DeepCopyInto(out *Metadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -1336,6 +1865,16 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 		*out = make([]SecretKeySelector, len(*in))
 		copy(*out, *in)
 	}
+	if in.DisableDefaultCollectors != nil {
+		in, out := &in.DisableDefaultCollectors, &out.DisableDefaultCollectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopStatements != nil {
+		in, out := &in.TopStatements, &out.TopStatements
+		*out = new(TopStatementsConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -1381,6 +1920,11 @@ func (in *OnlineConfiguration) DeepCopyInto(out *OnlineConfiguration) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HoldTimeoutSeconds != nil {
+		in, out := &in.HoldTimeoutSeconds, &out.HoldTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineConfiguration.
@@ -1408,6 +1952,26 @@ func (in *PasswordState) DeepCopy() *PasswordState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerAdminConfiguration) DeepCopyInto(out *PgBouncerAdminConfiguration) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerAdminConfiguration.
+func (in *PgBouncerAdminConfiguration) DeepCopy() *PgBouncerAdminConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerAdminConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -1428,10 +1992,27 @@ func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerPoolSettings) DeepCopyInto(out *PgBouncerPoolSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerPoolSettings.
+func (in *PgBouncerPoolSettings) DeepCopy() *PgBouncerPoolSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerPoolSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
 	*out = *in
 	out.AuthQuery = in.AuthQuery
+	out.ClientTLS = in.ClientTLS
+	out.ClientCA = in.ClientCA
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
@@ -1452,6 +2033,26 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.ReservePoolSize != nil {
+		in, out := &in.ReservePoolSize, &out.ReservePoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReservePoolTimeout != nil {
+		in, out := &in.ReservePoolTimeout, &out.ReservePoolTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPreparedStatements != nil {
+		in, out := &in.MaxPreparedStatements, &out.MaxPreparedStatements
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TCPKeepalives != nil {
+		in, out := &in.TCPKeepalives, &out.TCPKeepalives
+		*out = new(TCPKeepalivesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make(map[string]string, len(*in))
@@ -1464,11 +2065,36 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Admin != nil {
+		in, out := &in.Admin, &out.Admin
+		*out = new(PgBouncerAdminConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PgBouncerTLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Paused != nil {
 		in, out := &in.Paused, &out.Paused
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PausedDatabases != nil {
+		in, out := &in.PausedDatabases, &out.PausedDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PauseDuringSwitchover != nil {
+		in, out := &in.PauseDuringSwitchover, &out.PauseDuringSwitchover
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
@@ -1481,6 +2107,31 @@ func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerTLSConfiguration) DeepCopyInto(out *PgBouncerTLSConfiguration) {
+	*out = *in
+	if in.ClientTLSSecret != nil {
+		in, out := &in.ClientTLSSecret, &out.ClientTLSSecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.ClientCASecret != nil {
+		in, out := &in.ClientCASecret, &out.ClientCASecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerTLSConfiguration.
+func (in *PgBouncerTLSConfiguration) DeepCopy() *PgBouncerTLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerTLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
 	*out = *in
@@ -1597,6 +2248,11 @@ func (in *PoolerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
 	*out = *in
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(PoolerMonitoringTLSConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
@@ -1609,6 +2265,21 @@ func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfigurati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerMonitoringTLSConfiguration) DeepCopyInto(out *PoolerMonitoringTLSConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringTLSConfiguration.
+func (in *PoolerMonitoringTLSConfiguration) DeepCopy() *PoolerMonitoringTLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerMonitoringTLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
 	*out = *in
@@ -1641,6 +2312,18 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PgBouncer != nil {
 		in, out := &in.PgBouncer, &out.PgBouncer
 		*out = new(PgBouncerSpec)
@@ -1651,102 +2334,260 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(appsv1.DeploymentStrategy)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Monitoring != nil {
-		in, out := &in.Monitoring, &out.Monitoring
-		*out = new(PoolerMonitoringConfiguration)
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(PoolerMonitoringConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
+func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(PoolerSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PoolSettings != nil {
+		in, out := &in.PoolSettings, &out.PoolSettings
+		*out = new(PgBouncerPoolSettings)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
+func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostImportAnalyzeStatus) DeepCopyInto(out *PostImportAnalyzeStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostImportAnalyzeStatus.
+func (in *PostImportAnalyzeStatus) DeepCopy() *PostImportAnalyzeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostImportAnalyzeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
+func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(PostInitApplicationSQLRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PgHBA != nil {
+		in, out := &in.PgHBA, &out.PgHBA
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]IdentMapEntry, len(*in))
+		copy(*out, *in)
+	}
+	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
+	if in.AdditionalLibraries != nil {
+		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCPKeepalives != nil {
+		in, out := &in.TCPKeepalives, &out.TCPKeepalives
+		*out = new(TCPKeepalivesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaConnection != nil {
+		in, out := &in.ReplicaConnection, &out.ReplicaConnection
+		*out = new(ReplicaConnectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(TimeoutsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autovacuum != nil {
+		in, out := &in.Autovacuum, &out.Autovacuum
+		*out = new(AutovacuumConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
+func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTimings) DeepCopyInto(out *ProbeTimings) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
-func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTimings.
+func (in *ProbeTimings) DeepCopy() *ProbeTimings {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerSpec)
+	out := new(ProbeTimings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+func (in *ProbesConfiguration) DeepCopyInto(out *ProbesConfiguration) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = new(PoolerSecrets)
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeTimings)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
-func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfiguration.
+func (in *ProbesConfiguration) DeepCopy() *ProbesConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerStatus)
+	out := new(ProbesConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+func (in *PublicationConfiguration) DeepCopyInto(out *PublicationConfiguration) {
 	*out = *in
-	if in.SecretRefs != nil {
-		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]SecretKeySelector, len(*in))
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ConfigMapRefs != nil {
-		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]ConfigMapKeySelector, len(*in))
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = make([]PublicationOperation, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
-func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationConfiguration.
+func (in *PublicationConfiguration) DeepCopy() *PublicationConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PostInitApplicationSQLRefs)
+	out := new(PublicationConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+func (in *QuarantineConfiguration) DeepCopyInto(out *QuarantineConfiguration) {
 	*out = *in
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.PgHBA != nil {
-		in, out := &in.PgHBA, &out.PgHBA
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
-	if in.AdditionalLibraries != nil {
-		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.LDAP != nil {
-		in, out := &in.LDAP, &out.LDAP
-		*out = new(LDAPConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
-func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarantineConfiguration.
+func (in *QuarantineConfiguration) DeepCopy() *QuarantineConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresConfiguration)
+	out := new(QuarantineConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1791,6 +2632,31 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaConnectionConfiguration) DeepCopyInto(out *ReplicaConnectionConfiguration) {
+	*out = *in
+	if in.RetryInterval != nil {
+		in, out := &in.RetryInterval, &out.RetryInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConnectTimeout != nil {
+		in, out := &in.ConnectTimeout, &out.ConnectTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaConnectionConfiguration.
+func (in *ReplicaConnectionConfiguration) DeepCopy() *ReplicaConnectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaConnectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
@@ -1865,6 +2731,21 @@ func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTimeoutsConfiguration) DeepCopyInto(out *RoleTimeoutsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTimeoutsConfiguration.
+func (in *RoleTimeoutsConfiguration) DeepCopy() *RoleTimeoutsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTimeoutsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
@@ -1916,6 +2797,76 @@ func (in *S3Credentials) DeepCopy() *S3Credentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLJobConfiguration) DeepCopyInto(out *SQLJobConfiguration) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLJobConfiguration.
+func (in *SQLJobConfiguration) DeepCopy() *SQLJobConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLJobConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLJobStatus) DeepCopyInto(out *SQLJobStatus) {
+	*out = *in
+	if in.Succeeded != nil {
+		in, out := &in.Succeeded, &out.Succeeded
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLJobStatus.
+func (in *SQLJobStatus) DeepCopy() *SQLJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	*out = *in
@@ -2114,6 +3065,21 @@ func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupFailures) DeepCopyInto(out *StartupFailures) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupFailures.
+func (in *StartupFailures) DeepCopy() *StartupFailures {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupFailures)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -2144,6 +3110,27 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionConfiguration) DeepCopyInto(out *SubscriptionConfiguration) {
+	*out = *in
+	out.ConnectionSecret = in.ConnectionSecret
+	if in.CopyData != nil {
+		in, out := &in.CopyData, &out.CopyData
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionConfiguration.
+func (in *SubscriptionConfiguration) DeepCopy() *SubscriptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
@@ -2164,6 +3151,93 @@ func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstra
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPKeepalivesConfiguration) DeepCopyInto(out *TCPKeepalivesConfiguration) {
+	*out = *in
+	if in.Idle != nil {
+		in, out := &in.Idle, &out.Idle
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPKeepalivesConfiguration.
+func (in *TCPKeepalivesConfiguration) DeepCopy() *TCPKeepalivesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPKeepalivesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableConfiguration) DeepCopyInto(out *TableConfiguration) {
+	*out = *in
+	if in.AutovacuumSettings != nil {
+		in, out := &in.AutovacuumSettings, &out.AutovacuumSettings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableConfiguration.
+func (in *TableConfiguration) DeepCopy() *TableConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TableConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutsConfiguration) DeepCopyInto(out *TimeoutsConfiguration) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleTimeoutsConfiguration, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutsConfiguration.
+func (in *TimeoutsConfiguration) DeepCopy() *TimeoutsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopStatementsConfiguration) DeepCopyInto(out *TopStatementsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopStatementsConfiguration.
+func (in *TopStatementsConfiguration) DeepCopy() *TopStatementsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TopStatementsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -2197,6 +3271,52 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyConfiguration) DeepCopyInto(out *TopologyConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyConfiguration.
+func (in *TopologyConfiguration) DeepCopy() *TopologyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeBackupConfiguration) DeepCopyInto(out *VolumeBackupConfiguration) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeBackupConfiguration.
+func (in *VolumeBackupConfiguration) DeepCopy() *VolumeBackupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeBackupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeBackupSource) DeepCopyInto(out *VolumeBackupSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeBackupSource.
+func (in *VolumeBackupSource) DeepCopy() *VolumeBackupSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeBackupSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
 	*out = *in
@@ -26,8 +26,10 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/activity"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/backup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/certificate"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/configdiff"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fio"
@@ -42,6 +44,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/snapshot"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/statements"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -74,7 +77,9 @@ func main() {
 	logFlags.AddFlags(rootCmd.PersistentFlags())
 	configFlags.AddFlags(rootCmd.PersistentFlags())
 
+	rootCmd.AddCommand(activity.NewCmd())
 	rootCmd.AddCommand(certificate.NewCmd())
+	rootCmd.AddCommand(configdiff.NewCmd())
 	rootCmd.AddCommand(destroy.NewCmd())
 	rootCmd.AddCommand(fence.NewCmd())
 	rootCmd.AddCommand(fio.NewCmd())
@@ -86,6 +91,7 @@ func main() {
 	rootCmd.AddCommand(reload.NewCmd())
 	rootCmd.AddCommand(report.NewCmd())
 	rootCmd.AddCommand(restart.NewCmd())
+	rootCmd.AddCommand(statements.NewCmd())
 	rootCmd.AddCommand(status.NewCmd())
 	rootCmd.AddCommand(versions.NewCmd())
 	rootCmd.AddCommand(backup.NewCmd())
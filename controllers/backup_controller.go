@@ -43,6 +43,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	backupmetrics "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/metrics"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/volumesnapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
@@ -104,6 +106,9 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	switch backup.Status.Phase {
 	case apiv1.BackupPhaseFailed, apiv1.BackupPhaseCompleted:
+		if err := r.updateLastSuccessfulBackupMetric(ctx, &backup); err != nil {
+			contextLogger.Error(err, "while updating the last successful backup metric")
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -152,7 +157,8 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
-	if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore {
+	if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore ||
+		backup.Spec.Method == apiv1.BackupMethodVolumeBackup {
 		if isRunning {
 			return ctrl.Result{}, nil
 		}
@@ -163,7 +169,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	origBackup := backup.DeepCopy()
 	switch backup.Spec.Method {
-	case apiv1.BackupMethodBarmanObjectStore:
+	case apiv1.BackupMethodBarmanObjectStore, apiv1.BackupMethodVolumeBackup:
 		// If no good running backups are found we elect a pod for the backup
 		pod, err := r.getBackupTargetPod(ctx, &cluster, &backup)
 		if apierrs.IsNotFound(err) {
@@ -194,13 +200,18 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			"cluster", cluster.Name,
 			"pod", pod.Name)
 
-		if cluster.Spec.Backup.BarmanObjectStore == nil {
+		if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore && cluster.Spec.Backup.BarmanObjectStore == nil {
 			tryFlagBackupAsFailed(ctx, r.Client, &backup,
 				errors.New("no barmanObjectStore section defined on the target cluster"))
 			return ctrl.Result{}, nil
 		}
+		if backup.Spec.Method == apiv1.BackupMethodVolumeBackup && !cluster.ShouldCreateBackupVolume() {
+			tryFlagBackupAsFailed(ctx, r.Client, &backup,
+				errors.New("no volumeBackup section defined on the target cluster"))
+			return ctrl.Result{}, nil
+		}
 		// This backup has been started
-		if err := startBarmanBackup(ctx, r.Client, &backup, pod, &cluster); err != nil {
+		if err := startInstanceBackup(ctx, r.Client, &backup, pod, &cluster); err != nil {
 			r.Recorder.Eventf(&backup, "Warning", "Error", "Backup exit with error %v", err)
 			tryFlagBackupAsFailed(ctx, r.Client, &backup, fmt.Errorf("encountered an error while taking the backup: %w", err))
 			return ctrl.Result{}, nil
@@ -460,42 +471,66 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 		backupTarget = backup.Spec.Target
 	}
 	postgresqlStatusList := r.instanceStatusClient.GetStatusFromInstances(ctx, pods)
+	if pod := electBackupTargetInstance(ctx, postgresqlStatusList, backupTarget); pod != nil {
+		return pod, nil
+	}
+
+	contextLogger.Debug("No ready instances found as target for backup, defaulting to primary")
+
+	var pod corev1.Pod
+	err = r.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.TargetPrimary,
+	}, &pod)
+
+	return &pod, err
+}
+
+// electBackupTargetInstance chooses the instance that should run the backup
+// according to the target policy, between the ones that are ready and whose
+// status has been correctly retrieved. The list is sorted with the primary
+// first and then the most up-to-date replicas. Returns nil when no instance
+// is suitable
+func electBackupTargetInstance(
+	ctx context.Context,
+	postgresqlStatusList postgresSpec.PostgresqlStatusList,
+	backupTarget apiv1.BackupTarget,
+) *corev1.Pod {
+	contextLogger := log.FromContext(ctx)
+
 	for _, item := range postgresqlStatusList.Items {
 		if !item.IsPodReady {
 			contextLogger.Debug("Instance not ready, discarded as target for backup",
 				"pod", item.Pod.Name)
 			continue
 		}
+		if item.Error != nil {
+			contextLogger.Debug("Instance status not available, discarded as target for backup",
+				"pod", item.Pod.Name, "error", item.Error.Error())
+			continue
+		}
 		switch backupTarget {
 		case apiv1.BackupTargetPrimary:
 			if item.IsPrimary {
 				contextLogger.Debug("Primary Instance is elected as backup target",
 					"instance", item.Pod.Name)
-				return item.Pod, nil
+				return item.Pod
 			}
 		case apiv1.BackupTargetStandby, "":
 			if !item.IsPrimary {
 				contextLogger.Debug("Standby Instance is elected as backup target",
 					"instance", item.Pod.Name)
-				return item.Pod, nil
+				return item.Pod
 			}
 		}
 	}
 
-	contextLogger.Debug("No ready instances found as target for backup, defaulting to primary")
-
-	var pod corev1.Pod
-	err = r.Get(ctx, client.ObjectKey{
-		Namespace: cluster.Namespace,
-		Name:      cluster.Status.TargetPrimary,
-	}, &pod)
-
-	return &pod, err
+	return nil
 }
 
-// startBarmanBackup request a backup in a Pod and marks the backup started
-// or failed if needed
-func startBarmanBackup(
+// startInstanceBackup request a backup, taken by the instance manager,
+// in a Pod and marks the backup started or failed if needed
+func startInstanceBackup(
 	ctx context.Context,
 	client client.Client,
 	backup *apiv1.Backup,
@@ -504,7 +539,7 @@ func startBarmanBackup(
 ) error {
 	// This backup has been started
 	status := backup.GetStatus()
-	status.SetAsStarted(pod, apiv1.BackupMethodBarmanObjectStore)
+	status.SetAsStarted(pod, backup.Spec.Method)
 
 	if err := postgres.PatchBackupStatusAndRetry(ctx, client, backup); err != nil {
 		return err
@@ -545,6 +580,39 @@ func startBarmanBackup(
 	return nil
 }
 
+// updateLastSuccessfulBackupMetric refreshes the last successful backup
+// metric of the cluster of a backup taking into account all its completed
+// backups
+func (r *BackupReconciler) updateLastSuccessfulBackupMetric(ctx context.Context, backup *apiv1.Backup) error {
+	clusterKey := client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.Cluster.Name}
+
+	// Backups are not owned by their cluster: don't report clusters
+	// that have been deleted
+	var cluster apiv1.Cluster
+	if err := r.Get(ctx, clusterKey, &cluster); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	var clusterBackups apiv1.BackupList
+	if err := r.List(
+		ctx,
+		&clusterBackups,
+		client.InNamespace(backup.Namespace),
+		client.MatchingFields{clusterName: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	if stoppedAt := clusterBackups.GetLastSuccessfulBackupTime(); stoppedAt != nil {
+		backupmetrics.BackupCollector.SetLastSuccessfulBackup(clusterKey, *stoppedAt)
+	}
+
+	return nil
+}
+
 // SetupWithManager sets up this controller given a controller manager
 func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(
@@ -18,11 +18,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -247,3 +249,78 @@ var _ = Describe("backup_controller volumeSnapshot unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("backup target election", func() {
+	newStatus := func(name string, isPrimary, isReady bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:  isPrimary,
+			IsPodReady: isReady,
+		}
+	}
+
+	electedName := func(ctx context.Context, list postgres.PostgresqlStatusList, target apiv1.BackupTarget) string {
+		pod := electBackupTargetInstance(ctx, list, target)
+		if pod == nil {
+			return ""
+		}
+		return pod.Name
+	}
+
+	It("elects the most up-to-date ready standby when preferring the standby", func(ctx context.Context) {
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			newStatus("cluster-example-2", false, true),
+			newStatus("cluster-example-3", false, true),
+		}}
+		Expect(electedName(ctx, list, apiv1.BackupTargetStandby)).To(Equal("cluster-example-2"))
+		Expect(electedName(ctx, list, "")).To(Equal("cluster-example-2"))
+	})
+
+	It("discards the standbys that are not ready", func(ctx context.Context) {
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			newStatus("cluster-example-2", false, false),
+			newStatus("cluster-example-3", false, true),
+		}}
+		Expect(electedName(ctx, list, apiv1.BackupTargetStandby)).To(Equal("cluster-example-3"))
+	})
+
+	It("discards the standbys whose status can't be retrieved", func(ctx context.Context) {
+		unreachable := newStatus("cluster-example-2", false, true)
+		unreachable.Error = errors.New("connection refused")
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			unreachable,
+			newStatus("cluster-example-3", false, true),
+		}}
+		Expect(electedName(ctx, list, apiv1.BackupTargetStandby)).To(Equal("cluster-example-3"))
+	})
+
+	It("elects no instance when no standby is healthy, falling back to the primary", func(ctx context.Context) {
+		unreachable := newStatus("cluster-example-3", false, true)
+		unreachable.Error = errors.New("connection refused")
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			newStatus("cluster-example-2", false, false),
+			unreachable,
+		}}
+		Expect(electedName(ctx, list, apiv1.BackupTargetStandby)).To(BeEmpty())
+	})
+
+	It("elects the primary when requested, even with healthy standbys", func(ctx context.Context) {
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, true),
+			newStatus("cluster-example-2", false, true),
+		}}
+		Expect(electedName(ctx, list, apiv1.BackupTargetPrimary)).To(Equal("cluster-example-1"))
+	})
+
+	It("elects no instance when the primary is not ready", func(ctx context.Context) {
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", true, false),
+			newStatus("cluster-example-2", false, true),
+		}}
+		Expect(electedName(ctx, list, apiv1.BackupTargetPrimary)).To(BeEmpty())
+	})
+})
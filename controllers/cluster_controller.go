@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	goruntime "runtime"
+	"sync"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -45,9 +46,14 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	backupmetrics "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/metrics"
+	clustermetrics "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/cluster/metrics"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/failover"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 	instanceReconciler "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/quarantine"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/reinitialize"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
@@ -68,11 +74,19 @@ var apiGVString = apiv1.GroupVersion.String()
 type ClusterReconciler struct {
 	client.Client
 
+	// APIReader reads the objects the operator doesn't
+	// watch directly from the API server
+	APIReader client.Reader
+
 	DiscoveryClient discovery.DiscoveryInterface
 	Scheme          *runtime.Scheme
 	Recorder        record.EventRecorder
 
 	*instance.StatusClient
+
+	// The time the last LSN of the primary of every cluster was
+	// updated in its status, indexed by the name of the cluster
+	primaryLSNUpdates sync.Map
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
@@ -81,6 +95,7 @@ func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.Discov
 		StatusClient:    instance.NewStatusClient(),
 		DiscoveryClient: discoveryClient,
 		Client:          mgr.GetClient(),
+		APIReader:       mgr.GetAPIReader(),
 		Scheme:          mgr.GetScheme(),
 		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg"),
 	}
@@ -105,12 +120,14 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=configmaps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;create;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
@@ -132,6 +149,8 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		backupmetrics.BackupCollector.Forget(req.NamespacedName)
+		clustermetrics.ReconcileCollector.Forget(req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -145,6 +164,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
+	clustermetrics.ReconcileCollector.RecordReconcile(req.NamespacedName, err)
 	if errors.Is(err, ErrNextLoop) {
 		return result, nil
 	}
@@ -222,6 +242,11 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 	// Get the replication status
 	instancesStatus := r.StatusClient.GetStatusFromInstances(ctx, resources.instances)
+	if zone := cluster.Spec.Topology.GetPreferredPrimaryZone(); zone != "" {
+		// Equally advanced replicas are elected preferring the ones
+		// running in the preferred zone
+		instancesStatus.SortWithPreferredZone(resources.nodes, zone)
+	}
 
 	// we update all the cluster status fields that require the instances status
 	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
@@ -233,6 +258,16 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the instances status on the cluster: %w", err)
 	}
 
+	// two instances accepting writes must never be left running together
+	if splitBrain, err := r.reconcileSplitBrain(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the split-brain detection: %w", err)
+	} else if splitBrain {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	if err := instanceReconciler.ReconcileMetadata(ctx, r.Client, cluster, resources.instances); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -381,6 +416,14 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, ErrPromotionBlocked) {
+			contextLogger.Info("Not changing the designated primary, the promotion is blocked")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		if errors.Is(err, failover.ErrDataLossThresholdExceeded) {
+			contextLogger.Info("Waiting for a replica within the maximum data loss allowed to elect a new primary")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"error", err)
@@ -477,6 +520,37 @@ func (r *ClusterReconciler) reconcileResources(
 		return *result, err
 	}
 
+	// Delete Pods which crashed and didn't recover in time
+	result, err = r.deleteUnrecoveredInstances(ctx, cluster, resources)
+	if err != nil {
+		contextLogger.Error(err, "While deleting crashed pods")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if result != nil {
+		return *result, err
+	}
+
+	// Quarantine the replicas that keep failing to start, so that
+	// they stop crash looping until the user looks at them
+	if result, err := quarantine.Reconcile(ctx, r.Client, r.Recorder, cluster); result != nil || err != nil {
+		if err != nil {
+			contextLogger.Error(err, "While quarantining instances")
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		return *result, nil
+	}
+
+	// Rebuild the replicas the user asked to reinitialize, before waiting
+	// for them to be active as their data might be corrupted
+	result, err = reinitialize.Reconcile(ctx, r.Client, r.Recorder, cluster, instancesStatus)
+	if err != nil {
+		contextLogger.Error(err, "While reinitializing instances")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if result != nil {
+		return *result, nil
+	}
+
 	// TODO: move into a central waiting phase
 	// If we are joining a node, we should wait for the process to finish
 	if resources.countRunningJobs() > 0 {
@@ -572,6 +646,14 @@ func (r *ClusterReconciler) deleteEvictedOrUnscheduledInstances(ctx context.Cont
 			!cluster.IsReusePVCEnabled()) {
 			continue
 		}
+
+		// Quarantined instances are not recreated until the quarantine is lifted
+		if cluster.IsInstanceQuarantined(instance.Name) {
+			contextLogger.Info("Not replacing the evicted pod of a quarantined instance",
+				"pod", instance.Name)
+			continue
+		}
+
 		contextLogger.Warning("Deleting evicted/unscheduled pod",
 			"pod", instance.Name,
 			"podStatus", instance.Status)
@@ -615,6 +697,154 @@ func (r *ClusterReconciler) deleteEvictedOrUnscheduledInstances(ctx context.Cont
 	return nil, nil
 }
 
+// deleteUnrecoveredInstances will delete the Pods that crashed and didn't
+// recover within the instance recovery delay, so that they are recreated
+func (r *ClusterReconciler) deleteUnrecoveredInstances(ctx context.Context, cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	if cluster.Spec.InstanceRecoveryDelay <= 0 {
+		return nil, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	deletedPods := false
+	var recoveryWait time.Duration
+
+	for idx := range resources.instances.Items {
+		instance := &resources.instances.Items[idx]
+
+		// The failure of the primary is managed by the failover procedure,
+		// while quarantined instances are not recreated until the quarantine
+		// is lifted
+		if instance.Name == cluster.Status.CurrentPrimary ||
+			cluster.IsInstanceQuarantined(instance.Name) ||
+			!isInstanceCrashed(instance, resources.nodes) {
+			continue
+		}
+
+		timeLeft := getInstanceRecoveryTimeLeft(cluster, instance, resources.nodes, resources.pvcs.Items, time.Now())
+		if timeLeft > 0 {
+			contextLogger.Info("Waiting for the crashed pod to recover before replacing it",
+				"pod", instance.Name,
+				"instanceRecoveryDelay", cluster.Spec.InstanceRecoveryDelay,
+				"timeLeft", timeLeft)
+			if recoveryWait == 0 || timeLeft < recoveryWait {
+				recoveryWait = timeLeft
+			}
+			continue
+		}
+
+		// The kubelet of a node which is not ready cannot confirm the
+		// termination of the Pod, which would be stuck forever
+		var deleteOptions []client.DeleteOption
+		if !isNodeReady(resources.nodes, instance.Spec.NodeName) {
+			deleteOptions = append(deleteOptions, client.GracePeriodSeconds(0))
+		}
+
+		contextLogger.Warning("Deleting crashed pod which didn't recover",
+			"pod", instance.Name,
+			"podStatus", instance.Status)
+		if err := r.Delete(ctx, instance, deleteOptions...); err != nil {
+			if apierrs.IsConflict(err) || apierrs.IsNotFound(err) {
+				contextLogger.Debug("Error while deleting crashed instance", "error", err)
+				return &ctrl.Result{Requeue: true}, nil
+			}
+			return nil, err
+		}
+		deletedPods = true
+
+		r.Recorder.Eventf(cluster, "Normal", "DeletePod",
+			"Deleted crashed Pod %v which didn't recover",
+			instance.Name)
+	}
+
+	if deletedPods {
+		// Let's wait for the informer cache to notice the deleted Pods
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+	if recoveryWait > 0 {
+		// Some crashed Pods may still recover, let's check them again
+		// when their recovery delay expires
+		return &ctrl.Result{RequeueAfter: recoveryWait}, nil
+	}
+	return nil, nil
+}
+
+// isInstanceCrashed checks whether an instance Pod, which is still present,
+// stopped working because its containers keep crashing or because its
+// node is not ready or unreachable. Evicted Pods will never come back and
+// are replaced right away
+func isInstanceCrashed(instance *corev1.Pod, nodes map[string]corev1.Node) bool {
+	if utils.IsPodEvicted(instance) || instance.Spec.NodeName == "" {
+		return false
+	}
+
+	if !isNodeReady(nodes, instance.Spec.NodeName) {
+		return true
+	}
+
+	return utils.IsPodActive(*instance) && !utils.IsPodAlive(*instance)
+}
+
+// isNodeReady checks whether the node with the passed name exists and
+// is ready
+func isNodeReady(nodes map[string]corev1.Node, nodeName string) bool {
+	node, ok := nodes[nodeName]
+	if !ok {
+		return false
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getInstanceRecoveryTimeLeft returns how long the operator should still
+// wait for a crashed instance to recover before replacing it. A zero
+// value means that the instance can be replaced right away, which is
+// always the case when its data is known to be lost
+func getInstanceRecoveryTimeLeft(
+	cluster *apiv1.Cluster,
+	instance *corev1.Pod,
+	nodes map[string]corev1.Node,
+	pvcs []corev1.PersistentVolumeClaim,
+	now time.Time,
+) time.Duration {
+	if cluster.Spec.InstanceRecoveryDelay <= 0 {
+		return 0
+	}
+
+	if persistentvolumeclaim.IsInstanceDataLost(instance, pvcs) {
+		return 0
+	}
+
+	// The instance stopped working when it was last marked as not ready,
+	// or when its node stopped being ready
+	failedSince := instance.CreationTimestamp.Time
+	for _, condition := range instance.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue {
+			failedSince = condition.LastTransitionTime.Time
+		}
+	}
+	if node, ok := nodes[instance.Spec.NodeName]; ok {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue &&
+				condition.LastTransitionTime.After(failedSince) {
+				failedSince = condition.LastTransitionTime.Time
+			}
+		}
+	}
+
+	delay := time.Duration(cluster.Spec.InstanceRecoveryDelay) * time.Second
+	if timeLeft := failedSince.Add(delay).Sub(now); timeLeft > 0 {
+		return timeLeft
+	}
+	return 0
+}
+
 // checkPodsArchitecture checks whether the architecture of the instances is consistent with the runtime one
 func (r *ClusterReconciler) checkPodsArchitecture(
 	ctx context.Context,
@@ -766,20 +996,28 @@ func (r *ClusterReconciler) handleRollingUpdate(
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
-	// If we need to roll out a restart of any instance, this is the right moment
-	done, err := r.rolloutRequiredInstances(ctx, cluster, &instancesStatus)
+	// The restarts are deferred when the maintenance window is not in progress
+	rolloutDeferral, err := r.deferRolloutToMaintenanceWindow(ctx, cluster, instancesStatus, time.Now())
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if done {
-		// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
-		return ctrl.Result{}, ErrNextLoop
-	}
 
-	if instancesStatus.ArePodsWaitingForDecreasedSettings() {
-		// requeue and wait for the pods to be ready to be restarted,
-		// which will be handled by rolloutDueToCondition
-		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+	if rolloutDeferral == 0 {
+		// If we need to roll out a restart of any instance, this is the right moment
+		done, err := r.rolloutRequiredInstances(ctx, cluster, &instancesStatus)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if done {
+			// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
+			return ctrl.Result{}, ErrNextLoop
+		}
+
+		if instancesStatus.ArePodsWaitingForDecreasedSettings() {
+			// requeue and wait for the pods to be ready to be restarted,
+			// which will be handled by rolloutDueToCondition
+			return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+		}
 	}
 
 	// Stop acting here if there are Pods that are waiting for
@@ -789,8 +1027,10 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
-	// Execute online update, if enabled and if not already executing
-	if cluster.Status.OnlineUpdateEnabled && cluster.Status.Phase != apiv1.PhaseOnlineUpgrading {
+	// Execute online update, if enabled and if not already executing.
+	// The clusters skipping the operator upgrades keep their instance manager
+	if cluster.Status.OnlineUpdateEnabled && cluster.Status.Phase != apiv1.PhaseOnlineUpgrading &&
+		!utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) {
 		if err := r.upgradeInstanceManager(ctx, cluster, &instancesStatus); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -800,7 +1040,8 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		}
 	}
 
-	return ctrl.Result{}, nil
+	// Wake up when the maintenance window starts to roll out the pending restarts
+	return ctrl.Result{RequeueAfter: rolloutDeferral}, nil
 }
 
 // SetupWithManager creates a ClusterReconciler
@@ -22,6 +22,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -268,3 +270,121 @@ var _ = Describe("Updating target primary", func() {
 		})
 	})
 })
+
+var _ = Describe("Instance recovery delay", func() {
+	now := time.Now()
+
+	var (
+		cluster  *apiv1.Cluster
+		instance *corev1.Pod
+		nodes    map[string]corev1.Node
+		pvcs     []corev1.PersistentVolumeClaim
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				InstanceRecoveryDelay: 60,
+			},
+		}
+		instance = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "cluster-example-1",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node-1",
+				Volumes: []corev1.Volume{
+					{
+						Name: "pgdata",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: "cluster-example-1",
+							},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:               corev1.PodReady,
+						Status:             corev1.ConditionFalse,
+						LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Second)),
+					},
+				},
+			},
+		}
+		nodes = map[string]corev1.Node{
+			"node-1": {
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:               corev1.NodeReady,
+							Status:             corev1.ConditionUnknown,
+							LastTransitionTime: metav1.NewTime(now.Add(-20 * time.Second)),
+						},
+					},
+				},
+			},
+		}
+		pvcs = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+		}
+	})
+
+	It("considers crashed the pods running on a node which is not ready", func() {
+		Expect(isInstanceCrashed(instance, nodes)).To(BeTrue())
+		Expect(isInstanceCrashed(instance, nil)).To(BeTrue())
+	})
+
+	It("considers crashed the pods which are crash looping", func() {
+		nodes["node-1"].Status.Conditions[0].Status = corev1.ConditionTrue
+		Expect(isInstanceCrashed(instance, nodes)).To(BeFalse())
+
+		instance.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: "postgres",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+			},
+		}
+		Expect(isInstanceCrashed(instance, nodes)).To(BeTrue())
+	})
+
+	It("doesn't wait for evicted pods, as they never recover", func() {
+		instance.Status.Phase = corev1.PodFailed
+		instance.Status.Reason = utils.PodReasonEvicted
+		Expect(isInstanceCrashed(instance, nodes)).To(BeFalse())
+	})
+
+	It("waits for a crashed instance to recover", func() {
+		Expect(getInstanceRecoveryTimeLeft(cluster, instance, nodes, pvcs, now)).To(Equal(50 * time.Second))
+	})
+
+	It("starts waiting when the node stopped being ready", func() {
+		instance.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-30 * time.Second))
+		Expect(getInstanceRecoveryTimeLeft(cluster, instance, nodes, pvcs, now)).To(Equal(40 * time.Second))
+	})
+
+	It("replaces the instance once the delay has expired", func() {
+		Expect(getInstanceRecoveryTimeLeft(cluster, instance, nodes, pvcs, now.Add(time.Minute))).To(BeZero())
+	})
+
+	It("replaces the instance right away when its data is lost", func() {
+		pvcs[0].Status.Phase = corev1.ClaimLost
+		Expect(getInstanceRecoveryTimeLeft(cluster, instance, nodes, pvcs, now)).To(BeZero())
+		Expect(getInstanceRecoveryTimeLeft(cluster, instance, nodes, nil, now)).To(BeZero())
+	})
+
+	It("replaces the instance right away when no delay is configured", func() {
+		cluster.Spec.InstanceRecoveryDelay = 0
+		Expect(getInstanceRecoveryTimeLeft(cluster, instance, nodes, pvcs, now)).To(BeZero())
+	})
+})
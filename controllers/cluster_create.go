@@ -31,6 +31,7 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
@@ -72,6 +73,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = persistentvolumeclaim.ReconcileBackupVolume(ctx, r.Client, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.createOrPatchServiceAccount(ctx, cluster)
 	if err != nil {
 		return err
@@ -1134,6 +1140,10 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		return ctrl.Result{}, err
 	}
 
+	if res, err := r.ensureStorageCapacity(ctx, cluster, nodeSerial); err != nil || !res.IsZero() {
+		return res, err
+	}
+
 	job := specs.JoinReplicaInstance(*cluster, nodeSerial)
 
 	// If we can bootstrap this replica from a pre-existing source, we do it
@@ -1197,6 +1207,51 @@ func (r *ClusterReconciler) joinReplicaInstance(
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
 }
 
+// ensureStorageCapacity checks that the PVCs of a new instance fit in the
+// storage quotas and limits of the namespace, reporting it in the
+// StorageCapacityExceeded condition instead of leaving a pending PVC behind
+func (r *ClusterReconciler) ensureStorageCapacity(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	message, err := persistentvolumeclaim.CheckStorageCapacity(ctx, r.APIReader, cluster, nodeSerial)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if message == "" {
+		if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionStorageCapacityExceeded)) == nil {
+			return ctrl.Result{}, nil
+		}
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionStorageCapacityExceeded))
+		return ctrl.Result{}, r.Status().Update(ctx, cluster)
+	}
+
+	contextLogger.Warning("Cannot create a new instance, not enough storage capacity",
+		"nodeSerial", nodeSerial, "reason", message)
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionStorageCapacityExceeded),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonStorageQuotaExceeded),
+		Message: message,
+	}
+	if current := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type); current == nil ||
+		current.Status != condition.Status || current.Message != condition.Message {
+		r.Recorder.Eventf(cluster, "Warning", "StorageCapacityExceeded",
+			"Cannot create instance %v-%v: %s", cluster.Name, nodeSerial, message)
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: time.Minute}, ErrNextLoop
+}
+
 // ensureInstancesAreCreated recreates any missing instance
 func (r *ClusterReconciler) ensureInstancesAreCreated(
 	ctx context.Context,
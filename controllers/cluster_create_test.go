@@ -23,11 +23,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	k8client "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -500,6 +503,23 @@ var _ = Describe("CreateOrPatchPodMonitor", func() {
 		Expect(podMonitor.Namespace).To(Equal(manager.podMonitor.Namespace))
 	})
 
+	It("should not create the PodMonitor when the PodMonitor CRD is not installed", func() {
+		fakeDiscoveryClient = &fakediscovery.FakeDiscovery{Fake: &testing.Fake{}}
+		err := createOrPatchPodMonitor(ctx, fakeCli, fakeDiscoveryClient, manager)
+		Expect(err).ToNot(HaveOccurred())
+
+		podMonitor := &v1.PodMonitor{}
+		err = fakeCli.Get(
+			ctx,
+			types.NamespacedName{
+				Name:      manager.podMonitor.Name,
+				Namespace: manager.podMonitor.Namespace,
+			},
+			podMonitor,
+		)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
 	It("should not return an error when PodMonitor is disabled", func() {
 		manager.isEnabled = false
 		err := createOrPatchPodMonitor(ctx, fakeCli, fakeDiscoveryClient, manager)
@@ -562,3 +582,82 @@ var _ = Describe("CreateOrPatchPodMonitor", func() {
 		Expect(podMonitor.Annotations).To(Equal(updatedAnnotations))
 	})
 })
+
+var _ = Describe("storage capacity check", func() {
+	var (
+		cluster    *apiv1.Cluster
+		quota      *corev1.ResourceQuota
+		recorder   *record.FakeRecorder
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+				},
+			},
+		}
+		quota = &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "default"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("25Gi")},
+				Used: corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("20Gi")},
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, quota).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		reconciler = &ClusterReconciler{
+			Client:    fakeClient,
+			APIReader: fakeClient,
+			Recorder:  recorder,
+		}
+		Expect(reconciler.Get(context.Background(), k8client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	})
+
+	It("sets the condition when the quota would be exceeded", func(ctx context.Context) {
+		res, err := reconciler.ensureStorageCapacity(ctx, cluster, 3)
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(res.RequeueAfter).ToNot(BeZero())
+		Expect(recorder.Events).To(Receive(ContainSubstring("StorageCapacityExceeded")))
+
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionStorageCapacityExceeded))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonStorageQuotaExceeded)))
+		Expect(condition.Message).To(ContainSubstring("storage ResourceQuota"))
+
+		By("not raising the event again while nothing changes", func() {
+			_, err := reconciler.ensureStorageCapacity(ctx, &updatedCluster, 3)
+			Expect(err).To(MatchError(ErrNextLoop))
+			Expect(recorder.Events).ToNot(Receive())
+		})
+	})
+
+	It("removes the condition when the PVCs fit in the quota", func(ctx context.Context) {
+		_, err := reconciler.ensureStorageCapacity(ctx, cluster, 3)
+		Expect(err).To(MatchError(ErrNextLoop))
+
+		quota.Status.Hard[corev1.ResourceRequestsStorage] = resource.MustParse("50Gi")
+		Expect(reconciler.Update(ctx, quota)).To(Succeed())
+
+		res, err := reconciler.ensureStorageCapacity(ctx, cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.IsZero()).To(BeTrue())
+
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionStorageCapacityExceeded))).To(BeNil())
+	})
+})
@@ -23,6 +23,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -43,6 +44,11 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 		return fmt.Errorf("generating server CA certificate: %w", err)
 	}
 
+	// This is the copy of the CA certificate available to the clients
+	if err = r.reconcileServerCAConfigMap(ctx, cluster, serverCaSecret); err != nil {
+		return fmt.Errorf("exporting server CA certificate: %w", err)
+	}
+
 	// This is the certificate for the server
 	serverCertificateName := client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetServerTLSSecretName()}
 	opts := x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
@@ -168,6 +174,57 @@ func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *a
 	return &secret, nil
 }
 
+// reconcileServerCAConfigMap exports the certificate of the server CA in a
+// ConfigMap, so that clients can verify the identity of PostgreSQL without
+// being granted access to the CA secret. The ConfigMap is kept in sync
+// with the secret, following the CA renewals.
+func (r *ClusterReconciler) reconcileServerCAConfigMap(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caSecret *v1.Secret,
+) error {
+	caCertificate, err := certs.GetCACertificate(caSecret)
+	if err != nil {
+		return err
+	}
+
+	var configMap v1.ConfigMap
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetServerCAConfigMapName()},
+		&configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetServerCAConfigMapName(),
+				Namespace: cluster.GetNamespace(),
+				Labels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+				},
+			},
+			Data: map[string]string{
+				certs.CACertKey: string(caCertificate),
+			},
+		}
+		cluster.SetInheritedDataAndOwnership(&configMap.ObjectMeta)
+		return r.Create(ctx, &configMap)
+	}
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data[certs.CACertKey] == string(caCertificate) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating the exported server CA certificate",
+		"configmap", configMap.Name, "secret", caSecret.Name)
+	patchedConfigMap := configMap.DeepCopy()
+	if patchedConfigMap.Data == nil {
+		patchedConfigMap.Data = make(map[string]string, 1)
+	}
+	patchedConfigMap.Data[certs.CACertKey] = string(caCertificate)
+	return r.Patch(ctx, patchedConfigMap, client.MergeFrom(&configMap))
+}
+
 func (r *ClusterReconciler) verifyCAValidity(secret v1.Secret, cluster *apiv1.Cluster) error {
 	// Verify validity of the CA and expiration (only ca.crt)
 	publicKey, ok := secret.Data[certs.CACertKey]
@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("server CA certificate export", func() {
+	var (
+		cluster    *apiv1.Cluster
+		caPair     *certs.KeyPair
+		caSecret   *corev1.Secret
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		var err error
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		caPair, err = certs.CreateRootCA(cluster.Name, cluster.Namespace)
+		Expect(err).ToNot(HaveOccurred())
+		caSecret = caPair.GenerateCASecret(cluster.Namespace, cluster.GetServerCASecretName())
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster, caSecret).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	getConfigMap := func(ctx context.Context) *corev1.ConfigMap {
+		var configMap corev1.ConfigMap
+		Expect(reconciler.Get(ctx, k8client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      cluster.GetServerCAConfigMapName(),
+		}, &configMap)).To(Succeed())
+		return &configMap
+	}
+
+	It("creates the ConfigMap containing the CA certificate", func(ctx context.Context) {
+		Expect(reconciler.reconcileServerCAConfigMap(ctx, cluster, caSecret)).To(Succeed())
+
+		configMap := getConfigMap(ctx)
+		Expect(configMap.Data).To(HaveKeyWithValue(certs.CACertKey, string(caPair.Certificate)))
+		Expect(configMap.Data).ToNot(HaveKey(certs.CAPrivateKeyKey))
+		Expect(configMap.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, cluster.Name))
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+		Expect(configMap.OwnerReferences[0].Name).To(Equal(cluster.Name))
+	})
+
+	It("updates the ConfigMap when the CA is renewed", func(ctx context.Context) {
+		Expect(reconciler.reconcileServerCAConfigMap(ctx, cluster, caSecret)).To(Succeed())
+		oldCertificate := getConfigMap(ctx).Data[certs.CACertKey]
+
+		privateKey, err := caPair.ParseECPrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(caPair.RenewCertificate(privateKey, nil)).To(Succeed())
+		caSecret.Data[certs.CACertKey] = caPair.Certificate
+		Expect(reconciler.Update(ctx, caSecret)).To(Succeed())
+
+		Expect(reconciler.reconcileServerCAConfigMap(ctx, cluster, caSecret)).To(Succeed())
+		newCertificate := getConfigMap(ctx).Data[certs.CACertKey]
+		Expect(newCertificate).ToNot(Equal(oldCertificate))
+		Expect(newCertificate).To(Equal(string(caPair.Certificate)))
+	})
+
+	It("restores the certificate when the ConfigMap has been altered", func(ctx context.Context) {
+		Expect(reconciler.reconcileServerCAConfigMap(ctx, cluster, caSecret)).To(Succeed())
+
+		configMap := getConfigMap(ctx)
+		configMap.Data = nil
+		Expect(reconciler.Update(ctx, configMap)).To(Succeed())
+
+		Expect(reconciler.reconcileServerCAConfigMap(ctx, cluster, caSecret)).To(Succeed())
+		Expect(getConfigMap(ctx).Data).To(HaveKeyWithValue(certs.CACertKey, string(caPair.Certificate)))
+	})
+
+	It("exports a user-provided CA without its private key", func(ctx context.Context) {
+		delete(caSecret.Data, certs.CAPrivateKeyKey)
+
+		Expect(reconciler.reconcileServerCAConfigMap(ctx, cluster, caSecret)).To(Succeed())
+		Expect(getConfigMap(ctx).Data).To(HaveKeyWithValue(certs.CACertKey, string(caPair.Certificate)))
+	})
+})
@@ -0,0 +1,134 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileSplitBrain fences the instances accepting writes as a primary,
+// other than the current primary of the cluster, as soon as more than one
+// instance reports doing it, and raises the SplitBrain condition.
+// It returns true when a split-brain has been detected
+func (r *ClusterReconciler) reconcileSplitBrain(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	primaries := statuses.WritablePrimaries()
+	if len(primaries) <= 1 {
+		return false, r.resolveSplitBrainCondition(ctx, cluster)
+	}
+	sort.Strings(primaries)
+
+	condition := getSplitBrainCondition(primaries)
+	contextLogger.Warning("Split-brain detected, fencing the instances that are not the current primary",
+		"primaries", primaries, "currentPrimary", cluster.Status.CurrentPrimary)
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonMultiplePrimaries), condition.Message)
+	}
+
+	// PostgreSQL is stopped on the other instances accepting writes, so
+	// that only the current primary keeps doing it until the user
+	// removes the fencing
+	origCluster := cluster.DeepCopy()
+	for _, instanceName := range primaries {
+		if instanceName == cluster.Status.CurrentPrimary {
+			continue
+		}
+		err := utils.AddFencedInstance(instanceName, &cluster.ObjectMeta)
+		if err != nil && !errors.Is(err, utils.ErrorServerAlreadyFenced) {
+			return true, err
+		}
+	}
+	if !reflect.DeepEqual(origCluster.Annotations, cluster.Annotations) {
+		if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return true, fmt.Errorf("while fencing the instances: %w", err)
+		}
+	}
+
+	origCluster = cluster.DeepCopy()
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	if !reflect.DeepEqual(origCluster.Status, cluster.Status) {
+		if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
+// resolveSplitBrainCondition sets the SplitBrain condition to false when
+// the user removed the fencing set after a split-brain. The condition is
+// kept while any instance is fenced, as the fenced instances can't report
+// being a primary
+func (r *ClusterReconciler) resolveSplitBrainCondition(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionSplitBrain)) {
+		return nil
+	}
+
+	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil {
+		return err
+	}
+	if fencedInstances.Len() > 0 {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&cluster.Status.Conditions, getSplitBrainCondition(nil))
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getSplitBrainCondition builds the SplitBrain condition given the
+// instances accepting writes as a primary
+func getSplitBrainCondition(primaries []string) metav1.Condition {
+	if len(primaries) <= 1 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionSplitBrain),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonSinglePrimary),
+			Message: "No more than one instance is accepting writes",
+		}
+	}
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionSplitBrain),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonMultiplePrimaries),
+		Message: fmt.Sprintf("Split-brain detected, more than one instance is accepting writes "+
+			"as a primary: %s. The instances other than the current primary have been fenced "+
+			"and need manual intervention",
+			strings.Join(primaries, ", ")),
+	}
+}
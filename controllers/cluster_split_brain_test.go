@@ -0,0 +1,174 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Split-brain detection", func() {
+	var (
+		cluster    *apiv1.Cluster
+		reconciler *ClusterReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	newInstanceStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+			IsPodReady: true,
+			IsPrimary:  isPrimary,
+		}
+	}
+
+	fetchCluster := func(ctx context.Context) *apiv1.Cluster {
+		var result apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &result)).To(Succeed())
+		return &result
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				WithStatusSubresource(&apiv1.Cluster{}).
+				Build(),
+			Recorder: recorder,
+		}
+		Expect(reconciler.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	})
+
+	It("does nothing when there is only one primary", func(ctx context.Context) {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstanceStatus("cluster-example-1", true),
+				newInstanceStatus("cluster-example-2", false),
+			},
+		}
+		splitBrain, err := reconciler.reconcileSplitBrain(ctx, cluster, statuses)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(splitBrain).To(BeFalse())
+
+		stored := fetchCluster(ctx)
+		Expect(stored.Annotations).ToNot(HaveKey(utils.FencedInstanceAnnotation))
+		Expect(meta.FindStatusCondition(stored.Status.Conditions, string(apiv1.ConditionSplitBrain))).To(BeNil())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("fences the instances other than the current primary and raises the condition", func(ctx context.Context) {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstanceStatus("cluster-example-2", true),
+				newInstanceStatus("cluster-example-1", true),
+				newInstanceStatus("cluster-example-3", false),
+			},
+		}
+		splitBrain, err := reconciler.reconcileSplitBrain(ctx, cluster, statuses)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(splitBrain).To(BeTrue())
+
+		stored := fetchCluster(ctx)
+		fencedInstances, err := utils.GetFencedInstances(stored.Annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fencedInstances.ToList()).To(ConsistOf("cluster-example-2"))
+
+		condition := meta.FindStatusCondition(stored.Status.Conditions, string(apiv1.ConditionSplitBrain))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonMultiplePrimaries)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-1, cluster-example-2"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("MultiplePrimaries")))
+
+		By("not raising the event again while the split-brain persists", func() {
+			splitBrain, err := reconciler.reconcileSplitBrain(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(splitBrain).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	It("fences every instance accepting writes when none is the current primary", func(ctx context.Context) {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstanceStatus("cluster-example-2", true),
+				newInstanceStatus("cluster-example-3", true),
+			},
+		}
+		splitBrain, err := reconciler.reconcileSplitBrain(ctx, cluster, statuses)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(splitBrain).To(BeTrue())
+
+		fencedInstances, err := utils.GetFencedInstances(fetchCluster(ctx).Annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fencedInstances.ToList()).To(ConsistOf("cluster-example-2", "cluster-example-3"))
+	})
+
+	It("keeps the condition until the fencing is removed", func(ctx context.Context) {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstanceStatus("cluster-example-1", true),
+				newInstanceStatus("cluster-example-2", true),
+			},
+		}
+		_, err := reconciler.reconcileSplitBrain(ctx, cluster, statuses)
+		Expect(err).ToNot(HaveOccurred())
+
+		// the fenced instances can't report being a primary
+		splitBrain, err := reconciler.reconcileSplitBrain(ctx, cluster, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(splitBrain).To(BeFalse())
+		Expect(meta.IsStatusConditionTrue(fetchCluster(ctx).Status.Conditions,
+			string(apiv1.ConditionSplitBrain))).To(BeTrue())
+
+		origCluster := cluster.DeepCopy()
+		Expect(utils.RemoveFencedInstance("cluster-example-2", &cluster.ObjectMeta)).To(Succeed())
+		Expect(reconciler.Patch(ctx, cluster, client.MergeFrom(origCluster))).To(Succeed())
+
+		statuses.Items[1].IsPrimary = false
+		splitBrain, err = reconciler.reconcileSplitBrain(ctx, cluster, statuses)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(splitBrain).To(BeFalse())
+		condition := meta.FindStatusCondition(fetchCluster(ctx).Status.Conditions, string(apiv1.ConditionSplitBrain))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSinglePrimary)))
+	})
+})
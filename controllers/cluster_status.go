@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,16 +33,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/quarantine"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
+// primaryLSNUpdateInterval is the minimum time between two updates of the
+// last LSN of the primary in the status of a cluster
+const primaryLSNUpdateInterval = 30 * time.Second
+
 // managedResources contains the resources that are created a cluster
 // and need to be managed by the controller
 type managedResources struct {
@@ -247,6 +256,29 @@ func (r *ClusterReconciler) updateResourceStatus(
 		cluster,
 		resources.instances.Items,
 	)
+	quarantine.EnrichStatus(
+		ctx,
+		cluster,
+		resources.instances.Items,
+		time.Now(),
+	)
+
+	if condition := cluster.GetStatsTempDirectoryCondition(); condition != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionStatsTempDirectoryInMemory))
+	}
+
+	if condition := cluster.GetRelaxedDurabilityCondition(); condition != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionRelaxedDurability))
+	}
+
+	// The storage capacity is only checked while creating new instances
+	if len(resources.instances.Items) >= cluster.Spec.Instances {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionStorageCapacityExceeded))
+	}
 
 	// Count jobs
 	newJobs := int32(len(resources.jobs.Items))
@@ -330,6 +362,10 @@ func (r *ClusterReconciler) updateResourceStatus(
 		return err
 	}
 
+	if err := r.refreshBarmanEndpointCACondition(ctx, cluster); err != nil {
+		return err
+	}
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
@@ -647,6 +683,58 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 	return nil
 }
 
+// refreshBarmanEndpointCACondition checks that the secret referenced as
+// the CA bundle of the barman endpoint contains a valid PEM bundle, and
+// sets the corresponding condition
+func (r *ClusterReconciler) refreshBarmanEndpointCACondition(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.Spec.Backup.IsBarmanEndpointCASet() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionBarmanEndpointCAInvalid))
+		return nil
+	}
+
+	endpointCA := cluster.Spec.Backup.BarmanObjectStore.EndpointCA
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: endpointCA.Name}, &secret)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	var validationErr error
+	if err != nil {
+		validationErr = fmt.Errorf("secret %s not found", endpointCA.Name)
+	} else {
+		validationErr = validateBarmanEndpointCASecret(&secret, endpointCA.Key)
+	}
+
+	if validationErr == nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionBarmanEndpointCAInvalid))
+		return nil
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionBarmanEndpointCAInvalid),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonInvalidCABundle),
+		Message: validationErr.Error(),
+	})
+	return nil
+}
+
+// validateBarmanEndpointCASecret checks that the passed key of the secret
+// contains a PEM bundle
+func validateBarmanEndpointCASecret(secret *corev1.Secret, key string) error {
+	data, ok := secret.Data[key]
+	if !ok {
+		return fmt.Errorf("missing key %s in secret %s", key, secret.Name)
+	}
+
+	if err := certs.ValidateCABundle(data); err != nil {
+		return fmt.Errorf("secret %s, key %s: %w", secret.Name, key, err)
+	}
+
+	return nil
+}
+
 // getSecretResourceVersion retrieves the resource version of a secret
 func (r *ClusterReconciler) getSecretResourceVersion(
 	ctx context.Context,
@@ -690,6 +778,10 @@ func (r *ClusterReconciler) setPrimaryInstance(
 	cluster *apiv1.Cluster,
 	podName string,
 ) error {
+	if cluster.IsPromotionBlocked() && cluster.Status.CurrentPrimary != "" && podName != cluster.Status.CurrentPrimary {
+		return ErrPromotionBlocked
+	}
+
 	cluster.Status.TargetPrimary = podName
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
 	return r.Status().Update(ctx, cluster)
@@ -744,7 +836,7 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	cluster *apiv1.Cluster,
 	statuses postgres.PostgresqlStatusList,
 ) error {
-	existingClusterStatus := cluster.Status
+	existingClusterStatus := cluster.Status.DeepCopy()
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
 	// we extract the instances reported state
@@ -762,14 +854,354 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		if item.IsPrimary && item.TimeLineID != 0 {
 			cluster.Status.TimelineID = item.TimeLineID
 		}
+
+		// we keep track of the last LSN of the primary, to estimate the
+		// data loss of a failover
+		if item.IsPrimary && item.Pod.Name == cluster.Status.CurrentPrimary &&
+			cluster.Spec.Failover.IsDataLossBounded() && r.shouldUpdatePrimaryLSN(cluster, item.CurrentLsn) {
+			cluster.Status.LastPrimaryLSN = string(item.CurrentLsn)
+		}
+
+		// we report the default isolation level of the transactions
+		// actually in use, reflecting the reloaded configuration
+		if item.IsPrimary && item.Pod.Name == cluster.Status.CurrentPrimary &&
+			item.DefaultTransactionIsolation != "" {
+			cluster.Status.DefaultTransactionIsolation = item.DefaultTransactionIsolation
+		}
+
+		// the WAL segment size is fixed at bootstrap, and reported in
+		// megabytes like in the spec
+		if item.IsPrimary && item.Pod.Name == cluster.Status.CurrentPrimary &&
+			item.WalSegmentSize != 0 {
+			cluster.Status.WalSegmentSize = int(item.WalSegmentSize / (1024 * 1024))
+		}
+
+		// the locale provider is chosen by initdb and doesn't change
+		if item.IsPrimary && item.Pod.Name == cluster.Status.CurrentPrimary &&
+			item.LocaleProvider != "" {
+			cluster.Status.LocaleProvider = apiv1.LocaleProvider(item.LocaleProvider)
+		}
 	}
 
-	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
-		return r.Status().Update(ctx, cluster)
+	if !cluster.Spec.Failover.IsDataLossBounded() {
+		cluster.Status.LastPrimaryLSN = ""
+	}
+
+	updateImageStatus(cluster, statuses)
+	if condition := getImageUpdatePendingCondition(cluster, statuses); condition != nil {
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+			log.FromContext(ctx).Info("Operator upgrade skipped", "message", condition.Message)
+			r.Recorder.Event(cluster, "Normal", string(apiv1.ConditionReasonOperatorUpgradeSkipped), condition.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionImageUpdatePending))
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, getReplicationConflictsCondition(statuses))
+
+	// the condition is kept as is when no instance reported the data checksums
+	if failures, enabled, reported := getChecksumFailures(statuses, cluster.Status.ChecksumFailures); reported {
+		if increased := getIncreasedChecksumFailures(cluster.Status.ChecksumFailures, failures); len(increased) > 0 {
+			message := fmt.Sprintf("New data checksum failures detected, indicating a possible data corruption, on: %s",
+				strings.Join(increased, ", "))
+			log.FromContext(ctx).Warning("Data checksum failures detected", "instances", increased)
+			r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonChecksumFailuresDetected), message)
+		}
+		cluster.Status.ChecksumFailures = failures
+		meta.SetStatusCondition(&cluster.Status.Conditions, getDataChecksumFailuresCondition(failures, enabled))
+	}
+
+	// the condition is kept as is when the primary didn't report its replication slots
+	if condition, ok := getReplicationSlotsInvalidatedCondition(statuses); ok {
+		if condition.Status == metav1.ConditionTrue &&
+			!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+			log.FromContext(ctx).Warning("Replication slots invalidated because of max_slot_wal_keep_size",
+				"message", condition.Message)
+			r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonReplicationSlotsLost), condition.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	}
+
+	// the condition is kept as is when no instance reported its replication slots usage
+	if condition, ok := getHighReplicationSlotsUsageCondition(statuses); ok {
+		if condition.Status == metav1.ConditionTrue &&
+			!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+			log.FromContext(ctx).Warning("High usage of the replication slots", "message", condition.Message)
+			r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonReplicationSlotsUsageHigh), condition.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	}
+
+	if condition := cluster.GetSynchronousReplicationDegradedCondition(); condition != nil {
+		if condition.Status == metav1.ConditionTrue &&
+			!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+			log.FromContext(ctx).Warning("Synchronous replication degraded", "message", condition.Message)
+			r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonNotEnoughSyncReplicas), condition.Message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionSynchronousReplicationDegraded))
+	}
+
+	if reflect.DeepEqual(*existingClusterStatus, cluster.Status) {
+		return nil
+	}
+
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return err
+	}
+
+	if cluster.Status.LastPrimaryLSN != existingClusterStatus.LastPrimaryLSN {
+		r.primaryLSNUpdates.Store(client.ObjectKeyFromObject(cluster), time.Now())
 	}
 	return nil
 }
 
+// shouldUpdatePrimaryLSN checks whether the passed LSN of the primary is to
+// be stored in the status of the cluster. The LSN changes at almost every
+// reconciliation of a primary taking writes, and every update of the status
+// triggers a new reconciliation, so it is updated at most once every
+// primaryLSNUpdateInterval
+func (r *ClusterReconciler) shouldUpdatePrimaryLSN(cluster *apiv1.Cluster, lsn postgres.LSN) bool {
+	switch {
+	case lsn == "" || string(lsn) == cluster.Status.LastPrimaryLSN:
+		return false
+	case cluster.Status.LastPrimaryLSN == "":
+		return true
+	}
+
+	lastUpdate, ok := r.primaryLSNUpdates.Load(client.ObjectKeyFromObject(cluster))
+	return !ok || time.Since(lastUpdate.(time.Time)) >= primaryLSNUpdateInterval
+}
+
+// getReplicationConflictsCondition builds the condition telling if any replica
+// had a spike of queries canceled because of conflicts with recovery
+func getReplicationConflictsCondition(statuses postgres.PostgresqlStatusList) metav1.Condition {
+	instances := statuses.InstancesWithReplicationConflicts()
+	if len(instances) == 0 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionReplicationConflicts),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonNoReplicationConflicts),
+			Message: "No spike of queries canceled because of conflicts with recovery",
+		}
+	}
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionReplicationConflicts),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonReplicationConflictsDetected),
+		Message: fmt.Sprintf(
+			"Queries canceled because of conflicts with recovery in the last five minutes on: %s",
+			strings.Join(instances, ", ")),
+	}
+}
+
+// getChecksumFailures gets the data checksum failures detected by each
+// instance, keeping the previous value for the instances that didn't
+// report them, and whether the data checksums are enabled. The third value
+// is false when no instance reported the status of the data checksums
+func getChecksumFailures(
+	statuses postgres.PostgresqlStatusList,
+	previous map[string]int64,
+) (map[string]int64, bool, bool) {
+	failures := make(map[string]int64)
+	enabled, reported := true, false
+	for _, item := range statuses.Items {
+		if item.Error != nil || item.DataChecksumsEnabled == nil {
+			if value, ok := previous[item.Pod.Name]; ok {
+				failures[item.Pod.Name] = value
+			}
+			continue
+		}
+
+		reported = true
+		enabled = enabled && *item.DataChecksumsEnabled
+		if item.ChecksumFailures > 0 {
+			failures[item.Pod.Name] = item.ChecksumFailures
+		}
+	}
+
+	if len(failures) == 0 {
+		failures = nil
+	}
+
+	return failures, enabled, reported
+}
+
+// getIncreasedChecksumFailures returns the sorted names of the instances
+// whose data checksum failures increased
+func getIncreasedChecksumFailures(previous, current map[string]int64) []string {
+	var result []string
+	for name, value := range current {
+		if value > previous[name] {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// getDataChecksumFailuresCondition builds the condition telling if any
+// instance detected data checksum failures
+func getDataChecksumFailuresCondition(failures map[string]int64, enabled bool) metav1.Condition {
+	if !enabled {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionDataChecksumFailures),
+			Status:  metav1.ConditionUnknown,
+			Reason:  string(apiv1.ConditionReasonDataChecksumsDisabled),
+			Message: "Data checksums are disabled, the corruption of the data pages can't be detected",
+		}
+	}
+
+	if len(failures) == 0 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionDataChecksumFailures),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonNoChecksumFailures),
+			Message: "No data checksum failures detected",
+		}
+	}
+
+	instances := make([]string, 0, len(failures))
+	for name, value := range failures {
+		instances = append(instances, fmt.Sprintf("%s (%d)", name, value))
+	}
+	sort.Strings(instances)
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionDataChecksumFailures),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonChecksumFailuresDetected),
+		Message: fmt.Sprintf(
+			"Data checksum failures detected, indicating a possible data corruption, on: %s",
+			strings.Join(instances, ", ")),
+	}
+}
+
+// getReplicationSlotsInvalidatedCondition builds the condition telling if any
+// replication slot of the primary instance has been invalidated. The second
+// value is false when the primary didn't report its replication slots
+func getReplicationSlotsInvalidatedCondition(statuses postgres.PostgresqlStatusList) (metav1.Condition, bool) {
+	slots, reported := statuses.InvalidatedReplicationSlots()
+	if !reported {
+		return metav1.Condition{}, false
+	}
+
+	if len(slots) == 0 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionReplicationSlotsInvalidated),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonNoReplicationSlotsLost),
+			Message: "No replication slot has been invalidated",
+		}, true
+	}
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionReplicationSlotsInvalidated),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonReplicationSlotsLost),
+		Message: fmt.Sprintf(
+			"Replication slots invalidated for retaining more WAL than max_slot_wal_keep_size: %s",
+			strings.Join(slots, ", ")),
+	}, true
+}
+
+// updateImageStatus records the PostgreSQL image used by the instances. While
+// the operator upgrades are skipped the recorded image is kept, and when it
+// is still unknown it is taken from the instances
+func updateImageStatus(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	if !utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) || cluster.Spec.ImageName != "" {
+		cluster.Status.Image = cluster.GetImageName()
+		return
+	}
+
+	if cluster.Status.Image != "" {
+		return
+	}
+
+	for _, item := range statuses.Items {
+		if image, err := specs.GetPostgresImageName(*item.Pod); err == nil {
+			cluster.Status.Image = image
+			return
+		}
+	}
+}
+
+// getImageUpdatePendingCondition builds the condition telling which updates
+// of the instances are held because the operator upgrades are skipped,
+// or nil if there is none
+func getImageUpdatePendingCondition(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) *metav1.Condition {
+	if !utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) {
+		return nil
+	}
+
+	var pending []string
+	if cluster.Spec.ImageName == "" && cluster.Status.Image != "" &&
+		cluster.Status.Image != configuration.Current.PostgresImageName {
+		pending = append(pending, fmt.Sprintf("the image %s -> %s",
+			cluster.Status.Image, configuration.Current.PostgresImageName))
+	}
+
+	var outdatedInstances []string
+	for _, item := range statuses.Items {
+		if item.ExecutableHash != "" && cluster.Status.OperatorHash != "" &&
+			item.ExecutableHash != cluster.Status.OperatorHash {
+			outdatedInstances = append(outdatedInstances, item.Pod.Name)
+		}
+	}
+	if len(outdatedInstances) > 0 {
+		sort.Strings(outdatedInstances)
+		pending = append(pending, fmt.Sprintf("the instance manager of %s",
+			strings.Join(outdatedInstances, ", ")))
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionImageUpdatePending),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonOperatorUpgradeSkipped),
+		Message: fmt.Sprintf("Operator upgrade skipped because of the %s annotation, pending update of %s",
+			utils.SkipOperatorUpgradeAnnotationName, strings.Join(pending, " and ")),
+	}
+}
+
+// getHighReplicationSlotsUsageCondition builds the condition telling if any
+// instance is running out of replication slots. The second value is false
+// when no instance reported its replication slots usage
+func getHighReplicationSlotsUsageCondition(statuses postgres.PostgresqlStatusList) (metav1.Condition, bool) {
+	instances, reported := statuses.InstancesWithHighReplicationSlotsUsage()
+	if !reported {
+		return metav1.Condition{}, false
+	}
+
+	if len(instances) == 0 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionHighReplicationSlotsUsage),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonReplicationSlotsUsageNormal),
+			Message: "Every instance has enough replication slots available",
+		}, true
+	}
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionHighReplicationSlotsUsage),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonReplicationSlotsUsageHigh),
+		Message: fmt.Sprintf(
+			"At least %d%% of the replication slots allowed by max_replication_slots are used on: %s",
+			postgres.ReplicationSlotsUsageThreshold, strings.Join(instances, ", ")),
+	}, true
+}
+
 // getPodsTopology returns a map with all the information about the pods topology
 func getPodsTopology(
 	ctx context.Context,
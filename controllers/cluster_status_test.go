@@ -18,15 +18,24 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -166,3 +175,339 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("replication slots invalidated condition", func() {
+	primaryStatus := func(slots ...postgres.PgReplicationSlot) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary:            true,
+					ReplicationSlotsInfo: slots,
+				},
+			},
+		}
+	}
+
+	It("is not built when the primary didn't report its replication slots", func() {
+		_, ok := getReplicationSlotsInvalidatedCondition(postgres.PostgresqlStatusList{})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("is false when no replication slot has been invalidated", func() {
+		condition, ok := getReplicationSlotsInvalidatedCondition(primaryStatus(
+			postgres.PgReplicationSlot{SlotName: "_cnpg_cluster_example_2", WalStatus: "reserved"},
+		))
+		Expect(ok).To(BeTrue())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonNoReplicationSlotsLost)))
+	})
+
+	It("lists the invalidated replication slots", func() {
+		condition, ok := getReplicationSlotsInvalidatedCondition(primaryStatus(
+			postgres.PgReplicationSlot{SlotName: "_cnpg_cluster_example_2", WalStatus: "reserved"},
+			postgres.PgReplicationSlot{SlotName: "_cnpg_cluster_example_3", WalStatus: "lost"},
+		))
+		Expect(ok).To(BeTrue())
+		Expect(condition.Type).To(Equal(string(v1.ConditionReplicationSlotsInvalidated)))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReplicationSlotsLost)))
+		Expect(condition.Message).To(ContainSubstring("_cnpg_cluster_example_3"))
+		Expect(condition.Message).ToNot(ContainSubstring("_cnpg_cluster_example_2"))
+	})
+})
+
+var _ = Describe("replication slots usage condition", func() {
+	instanceStatus := func(name string, used, maxSlots int) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			ReplicationSlotsUsed: used,
+			MaxReplicationSlots:  maxSlots,
+		}
+	}
+
+	It("is not reported when no instance reported its replication slots usage", func() {
+		_, ok := getHighReplicationSlotsUsageCondition(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{instanceStatus("cluster-example-1", 0, 0)},
+		})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("is false when every instance has enough replication slots", func() {
+		condition, ok := getHighReplicationSlotsUsageCondition(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-1", 2, 10),
+				instanceStatus("cluster-example-2", 7, 10),
+			},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReplicationSlotsUsageNormal)))
+	})
+
+	It("lists the instances running out of replication slots", func() {
+		condition, ok := getHighReplicationSlotsUsageCondition(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-1", 9, 10),
+				instanceStatus("cluster-example-2", 2, 10),
+			},
+		})
+		Expect(ok).To(BeTrue())
+		Expect(condition.Type).To(Equal(string(v1.ConditionHighReplicationSlotsUsage)))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReplicationSlotsUsageHigh)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-1"))
+		Expect(condition.Message).ToNot(ContainSubstring("cluster-example-2"))
+	})
+})
+
+var _ = Describe("clusters skipping the operator upgrades", func() {
+	const (
+		oldImage = "postgres:16.1"
+		newImage = "postgres:16.2"
+	)
+
+	var cluster *v1.Cluster
+
+	instanceStatus := func(name, image, hash string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: specs.PostgresContainerName, Image: image}},
+				},
+			},
+			ExecutableHash: hash,
+		}
+	}
+
+	BeforeEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+		configuration.Current.PostgresImageName = newImage
+		cluster = &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.SkipOperatorUpgradeAnnotationName: "enabled",
+				},
+			},
+			Status: v1.ClusterStatus{OperatorHash: "new_hash"},
+		}
+		DeferCleanup(func() {
+			configuration.Current = configuration.NewConfiguration()
+		})
+	})
+
+	It("records the default image when the operator upgrades are not skipped", func() {
+		cluster.Annotations = nil
+		cluster.Status.Image = oldImage
+		updateImageStatus(cluster, postgres.PostgresqlStatusList{})
+		Expect(cluster.Status.Image).To(Equal(newImage))
+		Expect(getImageUpdatePendingCondition(cluster, postgres.PostgresqlStatusList{})).To(BeNil())
+	})
+
+	It("keeps the recorded image", func() {
+		cluster.Status.Image = oldImage
+		updateImageStatus(cluster, postgres.PostgresqlStatusList{})
+		Expect(cluster.Status.Image).To(Equal(oldImage))
+	})
+
+	It("takes the image from the instances when it's not recorded", func() {
+		updateImageStatus(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{instanceStatus("cluster-example-1", oldImage, "new_hash")},
+		})
+		Expect(cluster.Status.Image).To(Equal(oldImage))
+	})
+
+	It("records the image requested by the user", func() {
+		cluster.Spec.ImageName = "postgres:16.3"
+		cluster.Status.Image = oldImage
+		updateImageStatus(cluster, postgres.PostgresqlStatusList{})
+		Expect(cluster.Status.Image).To(Equal("postgres:16.3"))
+		Expect(getImageUpdatePendingCondition(cluster, postgres.PostgresqlStatusList{})).To(BeNil())
+	})
+
+	It("reports the pending updates of the image and of the instance managers", func() {
+		cluster.Status.Image = oldImage
+		condition := getImageUpdatePendingCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-2", oldImage, "old_hash"),
+				instanceStatus("cluster-example-1", oldImage, "new_hash"),
+			},
+		})
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(string(v1.ConditionImageUpdatePending)))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonOperatorUpgradeSkipped)))
+		Expect(condition.Message).To(ContainSubstring("the image postgres:16.1 -> postgres:16.2"))
+		Expect(condition.Message).To(ContainSubstring("the instance manager of cluster-example-2"))
+		Expect(condition.Message).ToNot(ContainSubstring("cluster-example-1"))
+	})
+
+	It("doesn't report anything when the instances are up to date", func() {
+		cluster.Status.Image = newImage
+		Expect(getImageUpdatePendingCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{instanceStatus("cluster-example-1", newImage, "new_hash")},
+		})).To(BeNil())
+	})
+})
+
+var _ = Describe("data checksum failures", func() {
+	instanceStatus := func(name string, enabled bool, failures int64) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			DataChecksumsEnabled: ptr.To(enabled),
+			ChecksumFailures:     failures,
+		}
+	}
+
+	It("is not reported when no instance reported the data checksums", func() {
+		_, _, reported := getChecksumFailures(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}},
+			},
+		}, nil)
+		Expect(reported).To(BeFalse())
+	})
+
+	It("is false when the instances didn't detect checksum failures", func() {
+		failures, enabled, reported := getChecksumFailures(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-1", true, 0),
+				instanceStatus("cluster-example-2", true, 0),
+			},
+		}, nil)
+		Expect(reported).To(BeTrue())
+		Expect(enabled).To(BeTrue())
+		Expect(failures).To(BeNil())
+
+		condition := getDataChecksumFailuresCondition(failures, enabled)
+		Expect(condition.Type).To(Equal(string(v1.ConditionDataChecksumFailures)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonNoChecksumFailures)))
+	})
+
+	It("lists the instances that detected checksum failures", func() {
+		failures, enabled, _ := getChecksumFailures(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-1", true, 0),
+				instanceStatus("cluster-example-2", true, 3),
+			},
+		}, nil)
+		Expect(failures).To(Equal(map[string]int64{"cluster-example-2": 3}))
+
+		condition := getDataChecksumFailuresCondition(failures, enabled)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonChecksumFailuresDetected)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2 (3)"))
+		Expect(condition.Message).ToNot(ContainSubstring("cluster-example-1"))
+	})
+
+	It("is unknown when the data checksums are disabled", func() {
+		failures, enabled, reported := getChecksumFailures(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-1", false, 0),
+				instanceStatus("cluster-example-2", false, 0),
+			},
+		}, nil)
+		Expect(reported).To(BeTrue())
+		Expect(enabled).To(BeFalse())
+
+		condition := getDataChecksumFailuresCondition(failures, enabled)
+		Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonDataChecksumsDisabled)))
+	})
+
+	It("keeps the failures of the instances that didn't report them", func() {
+		unreachable := instanceStatus("cluster-example-2", true, 0)
+		unreachable.Error = errors.New("connection refused")
+		failures, _, _ := getChecksumFailures(postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instanceStatus("cluster-example-1", true, 1),
+				unreachable,
+			},
+		}, map[string]int64{"cluster-example-2": 3, "cluster-example-3": 5})
+		Expect(failures).To(Equal(map[string]int64{"cluster-example-1": 1, "cluster-example-2": 3}))
+	})
+
+	It("detects the instances whose checksum failures increased", func() {
+		Expect(getIncreasedChecksumFailures(nil, nil)).To(BeEmpty())
+		Expect(getIncreasedChecksumFailures(
+			map[string]int64{"cluster-example-1": 2, "cluster-example-2": 3},
+			map[string]int64{"cluster-example-1": 2, "cluster-example-2": 3},
+		)).To(BeEmpty())
+		Expect(getIncreasedChecksumFailures(
+			map[string]int64{"cluster-example-1": 2},
+			map[string]int64{"cluster-example-1": 4, "cluster-example-3": 1},
+		)).To(Equal([]string{"cluster-example-1", "cluster-example-3"}))
+
+		// the counters restart from zero when the statistics are reset
+		Expect(getIncreasedChecksumFailures(
+			map[string]int64{"cluster-example-1": 2},
+			map[string]int64{"cluster-example-1": 1},
+		)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("last LSN of the primary", func() {
+	var (
+		cluster    *v1.Cluster
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status:     v1.ClusterStatus{LastPrimaryLSN: "0/3000000"},
+		}
+		reconciler = &ClusterReconciler{}
+	})
+
+	It("records the first LSN reported by the primary", func() {
+		cluster.Status.LastPrimaryLSN = ""
+		Expect(reconciler.shouldUpdatePrimaryLSN(cluster, "0/3000000")).To(BeTrue())
+		Expect(reconciler.shouldUpdatePrimaryLSN(cluster, "")).To(BeFalse())
+	})
+
+	It("doesn't update an unchanged LSN", func() {
+		Expect(reconciler.shouldUpdatePrimaryLSN(cluster, "0/3000000")).To(BeFalse())
+	})
+
+	It("updates the LSN at most once every interval", func() {
+		key := client.ObjectKeyFromObject(cluster)
+		Expect(reconciler.shouldUpdatePrimaryLSN(cluster, "0/3001000")).To(BeTrue())
+
+		reconciler.primaryLSNUpdates.Store(key, time.Now())
+		Expect(reconciler.shouldUpdatePrimaryLSN(cluster, "0/3001000")).To(BeFalse())
+
+		reconciler.primaryLSNUpdates.Store(key, time.Now().Add(-primaryLSNUpdateInterval))
+		Expect(reconciler.shouldUpdatePrimaryLSN(cluster, "0/3001000")).To(BeTrue())
+	})
+})
+
+var _ = Describe("barman endpoint CA validation", func() {
+	newSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "endpoint-ca", Namespace: "default"},
+			Data:       data,
+		}
+	}
+
+	It("accepts a secret containing a PEM bundle", func() {
+		ca, err := certs.CreateRootCA("endpoint", "default")
+		Expect(err).ToNot(HaveOccurred())
+
+		secret := newSecret(map[string][]byte{"ca.crt": ca.Certificate})
+		Expect(validateBarmanEndpointCASecret(secret, "ca.crt")).To(Succeed())
+	})
+
+	It("complains if the key is missing", func() {
+		secret := newSecret(map[string][]byte{"other.crt": []byte("data")})
+		err := validateBarmanEndpointCASecret(secret, "ca.crt")
+		Expect(err).To(MatchError(ContainSubstring("missing key ca.crt")))
+	})
+
+	It("complains if the key doesn't contain a PEM bundle", func() {
+		secret := newSecret(map[string][]byte{"ca.crt": []byte("not a certificate")})
+		Expect(validateBarmanEndpointCASecret(secret, "ca.crt")).ToNot(Succeed())
+	})
+})
@@ -25,13 +25,18 @@ import (
 	"net/http"
 	neturl "net/url"
 	"reflect"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
@@ -108,6 +113,58 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		podRollout.canBeInPlace, podRollout.reason)
 }
 
+// deferRolloutToMaintenanceWindow checks if the rollout of the instances
+// needing a restart is to be deferred until the maintenance window starts,
+// keeping the RestartPending condition updated. It returns the time left
+// before the maintenance window starts, or zero if the rollout can proceed
+func (r *ClusterReconciler) deferRolloutToMaintenanceWindow(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	now time.Time,
+) (time.Duration, error) {
+	deferred, windowStart, err := cluster.GetRestartDeferral(now)
+	if err != nil {
+		return 0, fmt.Errorf("while evaluating the maintenance window: %w", err)
+	}
+
+	var pendingInstances []string
+	if deferred {
+		for _, status := range instancesStatus.Items {
+			if isPodNeedingRollout(ctx, status, cluster).required {
+				pendingInstances = append(pendingInstances, status.Pod.Name)
+			}
+		}
+	}
+
+	if len(pendingInstances) == 0 {
+		if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionRestartPending)) == nil {
+			return 0, nil
+		}
+		existingCluster := cluster.DeepCopy()
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionRestartPending))
+		return 0, r.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster))
+	}
+
+	condition := &metav1.Condition{
+		Type:   string(apiv1.ConditionRestartPending),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonOutsideMaintenanceWindow),
+		Message: fmt.Sprintf("The restart of %s is deferred to the maintenance window starting at %s",
+			strings.Join(pendingInstances, ", "), windowStart.Format(time.RFC3339)),
+	}
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		log.FromContext(ctx).Info("Deferring the rollout to the maintenance window",
+			"instances", pendingInstances, "windowStart", windowStart)
+		r.Recorder.Event(cluster, "Normal", "RestartDeferred", condition.Message)
+	}
+	if err := conditions.Patch(ctx, r.Client, cluster, condition); err != nil {
+		return 0, err
+	}
+
+	return windowStart.Sub(now), nil
+}
+
 func (r *ClusterReconciler) updatePrimaryPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -131,7 +188,9 @@ func (r *ClusterReconciler) updatePrimaryPod(
 		return true, nil
 	}
 
-	if cluster.GetPrimaryUpdateMethod() == apiv1.PrimaryUpdateMethodRestart {
+	// When the promotion is blocked, the designated primary is restarted
+	// without a switchover
+	if cluster.GetPrimaryUpdateMethod() == apiv1.PrimaryUpdateMethodRestart || cluster.IsPromotionBlocked() {
 		if inPlacePossible {
 			// In-place restart is possible
 			if err := r.updateRestartAnnotation(ctx, cluster, primaryPod); err != nil {
@@ -265,6 +324,7 @@ func isPodNeedingRollout(
 	checkers := map[string]rolloutChecker{
 		"instance is missing executable hash":  checkHasExecutableHash,
 		"pod has missing PVCs":                 checkHasMissingPVCs,
+		"pod backup volume is outdated":        checkBackupVolumeIsOutdated,
 		"pod has PVC requiring resizing":       checkHasResizingPVC,
 		"pod projected volume is outdated":     checkProjectedVolumeIsOutdated,
 		"pod image is outdated":                checkPodImageIsOutdated,
@@ -416,10 +476,21 @@ func getProjectedVolumeConfigurationFromPod(pod corev1.Pod) *corev1.ProjectedVol
 	return nil
 }
 
+// isImageUpdateHeld checks if the PostgreSQL image of the instances is kept
+// as it is, because the cluster is skipping the operator upgrades and the
+// image is not explicitly set in the spec
+func isImageUpdateHeld(cluster *apiv1.Cluster) bool {
+	return utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) && cluster.Spec.ImageName == ""
+}
+
 func checkPodImageIsOutdated(
 	status postgres.PostgresqlStatus,
 	cluster *apiv1.Cluster,
 ) (rollout, error) {
+	if isImageUpdateHeld(cluster) {
+		return rollout{}, nil
+	}
+
 	targetImageName := cluster.GetImageName()
 
 	pgCurrentImageName, err := specs.GetPostgresImageName(*status.Pod)
@@ -451,9 +522,10 @@ func checkPodImageIsOutdated(
 
 func checkPodInitContainerIsOutdated(
 	status postgres.PostgresqlStatus,
-	_ *apiv1.Cluster,
+	cluster *apiv1.Cluster,
 ) (rollout, error) {
-	if configuration.Current.EnableInstanceManagerInplaceUpdates {
+	if configuration.Current.EnableInstanceManagerInplaceUpdates ||
+		utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) {
 		return rollout{}, nil
 	}
 
@@ -484,6 +556,27 @@ func checkHasMissingPVCs(
 	return rollout{}, nil
 }
 
+// checkBackupVolumeIsOutdated checks whether the Pod mounts the volume
+// dedicated to the backups, which is added to or removed from every
+// instance together with the backup configuration
+func checkBackupVolumeIsOutdated(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	isMounted := persistentvolumeclaim.IsUsedByPodSpec(status.Pod.Spec, cluster.GetBackupVolumeName())
+	shouldMount := cluster.ShouldCreateBackupVolume()
+	if isMounted == shouldMount {
+		return rollout{}, nil
+	}
+
+	reason := "unmounting the backup volume from the instance Pod"
+	if shouldMount {
+		reason = "mounting the backup volume in the instance Pod"
+	}
+
+	return rollout{required: true, reason: reason}, nil
+}
+
 func checkClusterHasNewerRestartAnnotation(
 	status postgres.PostgresqlStatus,
 	cluster *apiv1.Cluster,
@@ -589,13 +682,15 @@ func checkPodSpecIsOutdated(
 	targetPodSpec := specs.CreateClusterPodSpec(status.Pod.Name, *cluster, envConfig, gracePeriod)
 
 	// the bootstrap init-container could change image after an operator upgrade.
-	// If in-place upgrades of the instance manager are enabled, we don't need rollout.
+	// If in-place upgrades of the instance manager are enabled, or the cluster
+	// is skipping the operator upgrades, we don't need rollout.
 	opCurrentImageName, err := specs.GetBootstrapControllerImageName(*status.Pod)
 	if err != nil {
 		return rollout{}, err
 	}
 	if opCurrentImageName != configuration.Current.OperatorImageName &&
-		!configuration.Current.EnableInstanceManagerInplaceUpdates {
+		!configuration.Current.EnableInstanceManagerInplaceUpdates &&
+		!utils.IsOperatorUpgradeSkipped(&cluster.ObjectMeta) {
 		return rollout{
 			required: true,
 			reason: fmt.Sprintf("the instance is using an old init container image: %s -> %s",
@@ -607,6 +702,12 @@ func checkPodSpecIsOutdated(
 	storedPodSpec.InitContainers = nil
 	targetPodSpec.InitContainers = nil
 
+	// the image change brought by an operator upgrade is held back,
+	// until the cluster stops skipping the operator upgrades
+	if isImageUpdateHeld(cluster) {
+		keepPostgresImage(storedPodSpec, &targetPodSpec)
+	}
+
 	match, diff := specs.ComparePodSpecs(storedPodSpec, targetPodSpec)
 	if !match {
 		return rollout{
@@ -618,6 +719,23 @@ func checkPodSpecIsOutdated(
 	return rollout{}, nil
 }
 
+// keepPostgresImage sets the image of the PostgreSQL container of the target
+// PodSpec to the one of the stored PodSpec
+func keepPostgresImage(storedPodSpec corev1.PodSpec, targetPodSpec *corev1.PodSpec) {
+	for _, storedContainer := range storedPodSpec.Containers {
+		if storedContainer.Name != specs.PostgresContainerName {
+			continue
+		}
+
+		for idx := range targetPodSpec.Containers {
+			if targetPodSpec.Containers[idx].Name == specs.PostgresContainerName {
+				targetPodSpec.Containers[idx].Image = storedContainer.Image
+			}
+		}
+		return
+	}
+}
+
 // upgradePod deletes a Pod to let the operator recreate it using an
 // updated definition
 func (r *ClusterReconciler) upgradePod(
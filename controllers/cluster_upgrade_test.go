@@ -17,14 +17,21 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"encoding/json"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -410,6 +417,186 @@ var _ = Describe("Pod upgrade", Ordered, func() {
 	})
 })
 
+var _ = Describe("Pod upgrade of the clusters skipping the operator upgrades", Ordered, func() {
+	const (
+		oldImage         = "postgres:13.10"
+		newImage         = "postgres:13.11"
+		newOperatorImage = "ghcr.io/cloudnative-pg/cloudnative-pg:next"
+	)
+
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+		configuration.Current.PostgresImageName = oldImage
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				Annotations: map[string]string{
+					utils.SkipOperatorUpgradeAnnotationName: "enabled",
+				},
+			},
+			Status: apiv1.ClusterStatus{Image: oldImage},
+		}
+	})
+
+	AfterAll(func() {
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	It("keeps the image of the instances when the default image changes", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+
+		// let's simulate an operator upgrade changing the default image
+		configuration.Current.PostgresImageName = newImage
+		rollout := isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeFalse())
+		Expect(specs.PodWithExistingStorage(cluster, 2).Spec.Containers[0].Image).To(Equal(oldImage))
+
+		// removing the annotation the instances are updated
+		delete(cluster.Annotations, utils.SkipOperatorUpgradeAnnotationName)
+		rollout = isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeTrue())
+		Expect(rollout.reason).To(Equal("the instance is using an old image: postgres:13.10 -> postgres:13.11"))
+	})
+
+	It("keeps the image of the instances before recording it in the status", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+
+		cluster.Status.Image = ""
+		configuration.Current.PostgresImageName = newImage
+		configuration.Current.OperatorImageName = newOperatorImage
+		configuration.Current.EnableInstanceManagerInplaceUpdates = false
+		rollout := isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeFalse())
+
+		// an explicit change of the image is still applied
+		cluster.Spec.ImageName = newImage
+		rollout = isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeTrue())
+		Expect(rollout.reason).To(Equal("the instance is using an old image: postgres:13.10 -> postgres:13.11"))
+	})
+
+	It("keeps the instance manager without in-place upgrades", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+
+		configuration.Current.OperatorImageName = newOperatorImage
+		configuration.Current.EnableInstanceManagerInplaceUpdates = false
+		rollout := isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeFalse())
+
+		delete(pod.Annotations, utils.PodSpecAnnotationName)
+		rollout = isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeFalse())
+	})
+})
+
+var _ = Describe("Rollout of the backup volume mount", func() {
+	newCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName:           "postgres:13.11",
+				Instances:           2,
+				PrimaryUpdateMethod: apiv1.PrimaryUpdateMethodSwitchover,
+				Backup: &apiv1.BackupConfiguration{
+					Target: apiv1.BackupTargetPrimary,
+					VolumeBackup: &apiv1.VolumeBackupConfiguration{
+						Storage: apiv1.StorageConfiguration{Size: "10Gi"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Instances:      2,
+				CurrentPrimary: "test-1",
+				TargetPrimary:  "test-1",
+			},
+		}
+	}
+
+	newStatus := func(cluster *apiv1.Cluster, serial int, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:            specs.PodWithExistingStorage(*cluster, serial),
+			IsPodReady:     true,
+			IsPrimary:      isPrimary,
+			ExecutableHash: "test_hash",
+		}
+	}
+
+	It("mounts the backup volume in every instance when it's configured", func(ctx SpecContext) {
+		cluster := newCluster()
+		primary := newStatus(cluster, 1, true)
+		replica := newStatus(cluster, 2, false)
+		Expect(isPodNeedingRollout(ctx, primary, cluster).required).To(BeFalse())
+		Expect(isPodNeedingRollout(ctx, replica, cluster).required).To(BeFalse())
+
+		cluster.Status.CurrentPrimary = "test-2"
+		cluster.Status.TargetPrimary = "test-2"
+		Expect(isPodNeedingRollout(ctx, primary, cluster).required).To(BeFalse())
+		Expect(isPodNeedingRollout(ctx, replica, cluster).required).To(BeFalse())
+
+		cluster.Spec.Backup.VolumeBackup = nil
+		podRollout := isPodNeedingRollout(ctx, primary, cluster)
+		Expect(podRollout.required).To(BeTrue())
+		Expect(podRollout.reason).To(Equal("unmounting the backup volume from the instance Pod"))
+	})
+
+	It("follows the primary update strategy when mounting the backup volume", func(ctx SpecContext) {
+		cluster := newCluster()
+		cluster.Spec.PrimaryUpdateStrategy = apiv1.PrimaryUpdateStrategySupervised
+		cluster.Spec.Backup.VolumeBackup = nil
+		primary := newStatus(cluster, 1, true)
+		replica := newStatus(cluster, 2, false)
+		cluster.Spec.Backup = newCluster().Spec.Backup
+
+		podRollout := isPodNeedingRollout(ctx, primary, cluster)
+		Expect(podRollout.required).To(BeTrue())
+		Expect(podRollout.reason).To(Equal("mounting the backup volume in the instance Pod"))
+
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster, primary.Pod).
+				WithStatusSubresource(&apiv1.Cluster{}).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		// The replica has already been updated, and the primary
+		// waits for the user to trigger the switchover
+		replica.Pod = specs.PodWithExistingStorage(*cluster, 2)
+		podList := &postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{primary, replica}}
+		done, err := reconciler.rolloutRequiredInstances(ctx, cluster, podList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.Phase).To(Equal(apiv1.PhaseWaitingForUser))
+
+		var pod corev1.Pod
+		Expect(reconciler.Get(ctx, k8client.ObjectKeyFromObject(primary.Pod), &pod)).To(Succeed())
+	})
+})
+
 var _ = Describe("Test pod rollout due to topology", func() {
 	var cluster *apiv1.Cluster
 	var pod *corev1.Pod
@@ -614,3 +801,99 @@ var _ = Describe("hasValidPodSpec", func() {
 		})
 	})
 })
+
+var _ = Describe("Rollout deferral to the maintenance window", func() {
+	var (
+		cluster    *apiv1.Cluster
+		status     postgres.PostgresqlStatusList
+		recorder   *record.FakeRecorder
+		reconciler *ClusterReconciler
+	)
+
+	// Saturdays at 2:00, for two hours
+	windowStart := time.Date(2023, time.December, 2, 2, 0, 0, 0, time.UTC)
+	outsideWindow := windowStart.Add(-time.Hour)
+	insideWindow := windowStart.Add(time.Hour)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-example",
+				Namespace:   "default",
+				Annotations: map[string]string{utils.ClusterRestartAnnotationName: "now"},
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:13.11",
+				MaintenanceWindow: &apiv1.MaintenanceWindowConfiguration{
+					Schedule: "0 0 2 * * 6",
+					Duration: 7200,
+				},
+			},
+		}
+		status = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:            specs.PodWithExistingStorage(*cluster, 1),
+					IsPodReady:     true,
+					ExecutableHash: "test_hash",
+				},
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				WithStatusSubresource(&apiv1.Cluster{}).
+				Build(),
+			Recorder: recorder,
+		}
+		Expect(reconciler.Get(context.Background(), k8client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	})
+
+	getRestartPendingCondition := func(ctx context.Context) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, k8client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions, string(apiv1.ConditionRestartPending))
+	}
+
+	It("defers the rollout outside the maintenance window", func(ctx context.Context) {
+		deferral, err := reconciler.deferRolloutToMaintenanceWindow(ctx, cluster, status, outsideWindow)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferral).To(Equal(time.Hour))
+		Expect(recorder.Events).To(Receive(ContainSubstring("RestartDeferred")))
+
+		condition := getRestartPendingCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonOutsideMaintenanceWindow)))
+		Expect(condition.Message).To(ContainSubstring(status.Items[0].Pod.Name))
+	})
+
+	It("rolls out the restarts inside the maintenance window", func(ctx context.Context) {
+		_, err := reconciler.deferRolloutToMaintenanceWindow(ctx, cluster, status, outsideWindow)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getRestartPendingCondition(ctx)).ToNot(BeNil())
+
+		deferral, err := reconciler.deferRolloutToMaintenanceWindow(ctx, cluster, status, insideWindow)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferral).To(BeZero())
+		Expect(getRestartPendingCondition(ctx)).To(BeNil())
+	})
+
+	It("doesn't defer anything when no restart is needed", func(ctx context.Context) {
+		cluster.Annotations = nil
+		deferral, err := reconciler.deferRolloutToMaintenanceWindow(ctx, cluster, status, outsideWindow)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferral).To(BeZero())
+		Expect(getRestartPendingCondition(ctx)).To(BeNil())
+	})
+
+	It("rolls out the restarts when the maintenance window is bypassed", func(ctx context.Context) {
+		cluster.Annotations[utils.BypassMaintenanceWindowAnnotationName] = "enabled"
+		deferral, err := reconciler.deferRolloutToMaintenanceWindow(ctx, cluster, status, outsideWindow)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deferral).To(BeZero())
+		Expect(getRestartPendingCondition(ctx)).To(BeNil())
+	})
+})
@@ -105,6 +105,16 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	if name := pooler.GetClientTLSSecretName(); name != "" && resources.ClientTLSSecret == nil {
+		contextLogger.Info("ClientTLSSecret not found, waiting 30 seconds", "secret", name)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if name := pooler.GetClientCASecretName(); name != "" && resources.ClientCASecret == nil {
+		contextLogger.Info("ClientCASecret not found, waiting 30 seconds", "secret", name)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	// Update the status of the Pooler resource given what we read
 	// from the controlled resources
 	if err := r.updatePoolerStatus(ctx, &pooler, resources); err != nil {
@@ -117,7 +127,17 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	// Take the required actions to align the spec with the collected status
-	return ctrl.Result{}, r.updateOwnedObjects(ctx, &pooler, resources)
+	if err := r.updateOwnedObjects(ctx, &pooler, resources); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The readiness of the new primary is not watched, so we need
+	// to check it again to resume the pooler as soon as possible
+	if pooler.Status.PausedDuringSwitchover {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	return ctrl.Result{}, nil
 }
 
 // SetupWithManager setup this controller inside the controller manager
@@ -134,6 +154,11 @@ func (r *PoolerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPooler()),
 			builder.WithPredicates(secretsPoolerPredicate),
 		).
+		Watches(
+			&apiv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterToPoolers()),
+			builder.WithPredicates(clustersPoolerPredicate),
+		).
 		Complete(r)
 }
 
@@ -187,10 +212,45 @@ func (r *PoolerReconciler) mapSecretToPooler() handler.MapFunc {
 	}
 }
 
+// mapClusterToPoolers returns a function mapping cluster events to the
+// poolers attached to the cluster
+func (r *PoolerReconciler) mapClusterToPoolers() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		cluster, ok := obj.(*apiv1.Cluster)
+		if !ok {
+			return nil
+		}
+
+		var poolers apiv1.PoolerList
+		if err := r.List(ctx, &poolers,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingFields{poolerClusterKey: cluster.Name},
+		); err != nil {
+			log.FromContext(ctx).Error(err, "while getting pooler list for cluster",
+				"namespace", cluster.Namespace, "cluster", cluster.Name)
+			return nil
+		}
+
+		result := make([]reconcile.Request, len(poolers.Items))
+		for idx, pooler := range poolers.Items {
+			result[idx] = reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace},
+			}
+		}
+
+		return result
+	}
+}
+
 // getPoolersUsingSecret get a list of poolers which are using the passed secret
 func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (requests []types.NamespacedName) {
 	for _, pooler := range poolers.Items {
-		if pooler.Spec.PgBouncer != nil && pooler.GetAuthQuerySecretName() == secret.Name {
+		if pooler.Spec.PgBouncer == nil {
+			continue
+		}
+		if pooler.GetAuthQuerySecretName() == secret.Name ||
+			pooler.GetClientTLSSecretName() == secret.Name ||
+			pooler.GetClientCASecretName() == secret.Name {
 			requests = append(requests,
 				types.NamespacedName{
 					Name:      pooler.Name,
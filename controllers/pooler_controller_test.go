@@ -79,6 +79,33 @@ var _ = Describe("pooler_controller unit tests", func() {
 		})
 	})
 
+	It("should make sure that getPoolersUsingSecret finds the TLS secrets", func() {
+		uses := func(name string, tls *v1.PgBouncerTLSConfiguration) v1.Pooler {
+			return v1.Pooler{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: v1.PoolerSpec{
+					Cluster:   v1.LocalObjectReference{Name: "cluster"},
+					PgBouncer: &v1.PgBouncerSpec{TLS: tls},
+				},
+			}
+		}
+		poolerList := v1.PoolerList{Items: []v1.Pooler{
+			uses("pooler-tls", &v1.PgBouncerTLSConfiguration{
+				ClientTLSSecret: &v1.LocalObjectReference{Name: "pooler-secret"},
+			}),
+			uses("pooler-ca", &v1.PgBouncerTLSConfiguration{
+				ClientCASecret: &v1.LocalObjectReference{Name: "pooler-secret"},
+			}),
+			uses("pooler-default", nil),
+		}}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pooler-secret", Namespace: "default"}}
+
+		Expect(getPoolersUsingSecret(poolerList, secret)).To(Equal([]types.NamespacedName{
+			{Name: "pooler-tls", Namespace: "default"},
+			{Name: "pooler-ca", Namespace: "default"},
+		}))
+	})
+
 	It("should make sure that mapSecretToPooler produces the correct requests", func() {
 		var expectedRequests []reconcile.Request
 		var nonExpectedRequests []reconcile.Request
@@ -21,6 +21,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // secretsPoolerPredicate contains the set of predicate functions of the pooler secrets
@@ -39,6 +41,23 @@ var (
 			return isUsefulPoolerSecret(e.ObjectNew)
 		},
 	}
+
+	// clustersPoolerPredicate filters the cluster events, keeping only
+	// the ones signalling a change of the primary
+	clustersPoolerPredicate = predicate.Funcs{
+		CreateFunc: func(_ event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(_ event.GenericEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isPrimaryChangeEvent(e.ObjectOld, e.ObjectNew)
+		},
+	}
 )
 
 func isOwnedByPoolerOrSatisfiesPredicate(
@@ -55,3 +74,18 @@ func isUsefulPoolerSecret(object client.Object) bool {
 		return ok && hasReloadLabelSet(object)
 	})
 }
+
+func isPrimaryChangeEvent(oldObject, newObject client.Object) bool {
+	oldCluster, ok := oldObject.(*apiv1.Cluster)
+	if !ok {
+		return false
+	}
+	newCluster, ok := newObject.(*apiv1.Cluster)
+	if !ok {
+		return false
+	}
+
+	return oldCluster.Status.Phase != newCluster.Status.Phase ||
+		oldCluster.Status.CurrentPrimary != newCluster.Status.CurrentPrimary ||
+		oldCluster.Status.TargetPrimary != newCluster.Status.TargetPrimary
+}
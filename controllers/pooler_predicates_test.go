@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -73,4 +74,36 @@ var _ = Describe("pooler_predicates unit tests", func() {
 			return false
 		})
 	})
+
+	It("makes sure isPrimaryChangeEvent only accepts changes of the primary", func() {
+		oldCluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "cluster-1",
+				TargetPrimary:  "cluster-1",
+			},
+		}
+
+		By("ignoring the changes unrelated to the primary", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.ReadyInstances = 3
+			Expect(isPrimaryChangeEvent(oldCluster, newCluster)).To(BeFalse())
+		})
+
+		By("accepting a new target primary", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.TargetPrimary = "cluster-2"
+			Expect(isPrimaryChangeEvent(oldCluster, newCluster)).To(BeTrue())
+		})
+
+		By("accepting a new phase", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.Phase = apiv1.PhaseSwitchover
+			Expect(isPrimaryChangeEvent(oldCluster, newCluster)).To(BeTrue())
+		})
+
+		By("ignoring the objects that are not clusters", func() {
+			Expect(isPrimaryChangeEvent(&corev1.Secret{}, &corev1.Secret{})).To(BeFalse())
+		})
+	})
 })
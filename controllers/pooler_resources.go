@@ -35,6 +35,12 @@ type poolerManagedResources struct {
 	// the auth_query connection
 	AuthUserSecret *corev1.Secret
 
+	// These are the user-provided secrets containing the certificate
+	// presented to the client applications and the CA used to validate
+	// their certificates
+	ClientTLSSecret *corev1.Secret
+	ClientCASecret  *corev1.Secret
+
 	// This is the pgbouncer deployment
 	Deployment *appsv1.Deployment
 
@@ -44,6 +50,10 @@ type poolerManagedResources struct {
 	// The referenced Cluster
 	Cluster *apiv1.Cluster
 
+	// The current primary of the referenced Cluster, only fetched when
+	// the pooler is paused during a switchover
+	PrimaryPod *corev1.Pod
+
 	// The RBAC resources needed for the pooler instance manager
 	// to watch over the relative Pooler resource
 	ServiceAccount *corev1.ServiceAccount
@@ -65,6 +75,23 @@ func (r *PoolerReconciler) getManagedResources(ctx context.Context,
 		return nil, err
 	}
 
+	// Get the user-provided TLS secrets if any
+	if name := pooler.GetClientTLSSecretName(); name != "" {
+		result.ClientTLSSecret, err = getSecretOrNil(
+			ctx, r.Client, client.ObjectKey{Name: name, Namespace: pooler.Namespace})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if name := pooler.GetClientCASecretName(); name != "" {
+		result.ClientCASecret, err = getSecretOrNil(
+			ctx, r.Client, client.ObjectKey{Name: name, Namespace: pooler.Namespace})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get the pooler deployment
 	result.Deployment, err = getDeploymentOrNil(
 		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
@@ -86,6 +113,17 @@ func (r *PoolerReconciler) getManagedResources(ctx context.Context,
 		return nil, err
 	}
 
+	// Get the current primary, needed to pause the pooler while the
+	// primary is drained and to resume it once the new primary is ready
+	if result.Cluster != nil && result.Cluster.Status.CurrentPrimary != "" &&
+		pooler.Spec.PgBouncer != nil && (pooler.Spec.Type == "" || pooler.Spec.Type == apiv1.PoolerTypeRW) {
+		result.PrimaryPod, err = getPodOrNil(
+			ctx, r.Client, client.ObjectKey{Name: result.Cluster.Status.CurrentPrimary, Namespace: pooler.Namespace})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	result.ServiceAccount, err = getServiceAccountOrNil(
 		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
 	if err != nil {
@@ -217,3 +255,18 @@ func getClusterOrNil(ctx context.Context, r client.Client, objectKey client.Obje
 
 	return &cluster, nil
 }
+
+// getPodOrNil gets a pod with a certain name, returning nil when it doesn't exist
+func getPodOrNil(ctx context.Context, r client.Client, objectKey client.ObjectKey) (*corev1.Pod, error) {
+	var pod corev1.Pod
+	err := r.Get(ctx, objectKey, &pod)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &pod, nil
+}
@@ -20,7 +20,11 @@ import (
 	"context"
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // updatePoolerStatus sets the status of the pooler and writes it inside kubernetes
@@ -46,6 +50,22 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		}
 	}
 
+	updatedStatus.Secrets.PgBouncerSecrets.ClientTLS = apiv1.SecretVersion{}
+	if resources.ClientTLSSecret != nil {
+		updatedStatus.Secrets.PgBouncerSecrets.ClientTLS = apiv1.SecretVersion{
+			Name:    resources.ClientTLSSecret.Name,
+			Version: resources.ClientTLSSecret.ResourceVersion,
+		}
+	}
+
+	updatedStatus.Secrets.PgBouncerSecrets.ClientCA = apiv1.SecretVersion{}
+	if resources.ClientCASecret != nil {
+		updatedStatus.Secrets.PgBouncerSecrets.ClientCA = apiv1.SecretVersion{
+			Name:    resources.ClientCASecret.Name,
+			Version: resources.ClientCASecret.ResourceVersion,
+		}
+	}
+
 	if cluster := resources.Cluster; cluster != nil {
 		updatedStatus.Secrets.ServerTLS = apiv1.SecretVersion{
 			Name:    cluster.GetServerTLSSecretName(),
@@ -65,6 +85,23 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		updatedStatus.Instances = resources.Deployment.Status.Replicas
 	}
 
+	if pooler.Spec.PgBouncer != nil {
+		if settings, err := pooler.Spec.PgBouncer.GetPoolSettings(); err == nil {
+			updatedStatus.PoolSettings = &settings
+		}
+	}
+
+	updatedStatus.PausedDuringSwitchover = isPausedDuringSwitchover(pooler, resources)
+	if updatedStatus.PausedDuringSwitchover != pooler.Status.PausedDuringSwitchover {
+		if updatedStatus.PausedDuringSwitchover {
+			r.Recorder.Event(pooler, "Normal", "PausedDuringSwitchover",
+				"The primary of the cluster is changing, pausing the pooler")
+		} else {
+			r.Recorder.Event(pooler, "Normal", "ResumedAfterSwitchover",
+				"The new primary of the cluster is ready, resuming the pooler")
+		}
+	}
+
 	// then update the status if anything changed
 	if !reflect.DeepEqual(pooler.Status, updatedStatus) {
 		pooler.Status = *updatedStatus
@@ -73,3 +110,58 @@ func (r *PoolerReconciler) updatePoolerStatus(
 
 	return nil
 }
+
+// isPausedDuringSwitchover checks whether the pooler should be held paused
+// because the primary of the cluster is changing. When requested in the
+// spec, the pooler is paused as soon as a switchover or a failover begins,
+// and is resumed only when the new primary is ready to accept connections.
+// Otherwise, it is paused only while switching over from a primary whose
+// Pod is being drained, until the new primary is promoted
+func isPausedDuringSwitchover(pooler *apiv1.Pooler, resources *poolerManagedResources) bool {
+	if pooler.Spec.PgBouncer == nil {
+		return false
+	}
+
+	if pooler.Spec.Type != "" && pooler.Spec.Type != apiv1.PoolerTypeRW {
+		return false
+	}
+
+	cluster := resources.Cluster
+	if cluster == nil {
+		return false
+	}
+
+	if !pooler.Spec.PgBouncer.IsPausedDuringSwitchover() {
+		return isPrimaryChanging(cluster) && isPrimaryDrained(cluster, resources.PrimaryPod)
+	}
+
+	if isPrimaryChanging(cluster) {
+		return true
+	}
+
+	return pooler.Status.PausedDuringSwitchover && !isPrimaryReady(cluster, resources.PrimaryPod)
+}
+
+// isPrimaryChanging checks whether the cluster is promoting a new primary
+func isPrimaryChanging(cluster *apiv1.Cluster) bool {
+	return cluster.Status.Phase == apiv1.PhaseSwitchover ||
+		cluster.Status.Phase == apiv1.PhaseFailOver ||
+		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary
+}
+
+// isPrimaryDrained checks whether the passed pod is the current primary of
+// the cluster and is being disrupted, e.g. because its node is drained
+func isPrimaryDrained(cluster *apiv1.Cluster, pod *corev1.Pod) bool {
+	return pod != nil && pod.Name == cluster.Status.CurrentPrimary && utils.IsPodDisrupted(pod)
+}
+
+// isPrimaryReady checks whether the passed pod is the current primary of
+// the cluster and is ready to accept connections
+func isPrimaryReady(cluster *apiv1.Cluster, pod *corev1.Pod) bool {
+	if pod == nil || pod.Name != cluster.Status.CurrentPrimary {
+		return false
+	}
+
+	role, _ := utils.GetInstanceRole(pod.Labels)
+	return role == specs.ClusterRoleLabelPrimary && utils.IsPodReady(*pod)
+}
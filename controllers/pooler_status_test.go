@@ -22,9 +22,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -72,6 +75,34 @@ var _ = Describe("pooler_status unit tests", func() {
 		assertAuthUserStatus(pooler, authUserSecret)
 	})
 
+	It("should correctly set the status for the user-provided TLS secrets", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pooler := newFakePooler(cluster)
+		clientTLSSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-tls", Namespace: namespace, ResourceVersion: "2"},
+		}
+		clientCASecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-client-ca", Namespace: namespace, ResourceVersion: "3"},
+		}
+		res := &poolerManagedResources{
+			Cluster:         cluster,
+			ClientTLSSecret: clientTLSSecret,
+			ClientCASecret:  clientCASecret,
+		}
+
+		err := poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pooler.Status.Secrets.PgBouncerSecrets.ClientTLS).To(Equal(v1.SecretVersion{
+			Name: "pooler-tls", Version: "2",
+		}))
+		Expect(pooler.Status.Secrets.PgBouncerSecrets.ClientCA).To(Equal(v1.SecretVersion{
+			Name: "pooler-client-ca", Version: "3",
+		}))
+		assertClusterInheritedStatus(pooler, cluster)
+	})
+
 	It("should correctly set the deployment status", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -139,3 +170,106 @@ var _ = Describe("pooler_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("pausing the pooler during a switchover", func() {
+	var (
+		cluster *v1.Cluster
+		pooler  *v1.Pooler
+		primary *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			Status: v1.ClusterStatus{
+				Phase:          v1.PhaseHealthy,
+				CurrentPrimary: "cluster-1",
+				TargetPrimary:  "cluster-1",
+			},
+		}
+		pooler = &v1.Pooler{
+			Spec: v1.PoolerSpec{
+				Cluster: v1.LocalObjectReference{Name: cluster.Name},
+				Type:    v1.PoolerTypeRW,
+				PgBouncer: &v1.PgBouncerSpec{
+					PauseDuringSwitchover: ptr.To(true),
+				},
+			},
+		}
+		primary = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-1",
+				Labels: map[string]string{utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelPrimary},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	})
+
+	It("doesn't pause the pooler while the primary is stable", func() {
+		res := &poolerManagedResources{Cluster: cluster, PrimaryPod: primary}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeFalse())
+	})
+
+	It("pauses the pooler when a switchover begins", func() {
+		cluster.Status.Phase = v1.PhaseSwitchover
+		cluster.Status.TargetPrimary = "cluster-2"
+		res := &poolerManagedResources{Cluster: cluster, PrimaryPod: primary}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeTrue())
+	})
+
+	It("pauses the pooler when a failover is detected", func() {
+		cluster.Status.Phase = v1.PhaseFailOver
+		res := &poolerManagedResources{Cluster: cluster}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeTrue())
+	})
+
+	It("keeps the pooler paused until the new primary is ready", func() {
+		pooler.Status.PausedDuringSwitchover = true
+		cluster.Status.CurrentPrimary = "cluster-2"
+		cluster.Status.TargetPrimary = "cluster-2"
+		Expect(isPausedDuringSwitchover(pooler, &poolerManagedResources{Cluster: cluster})).To(BeTrue())
+
+		newPrimary := primary.DeepCopy()
+		newPrimary.Name = "cluster-2"
+		newPrimary.Status.Conditions = nil
+		res := &poolerManagedResources{Cluster: cluster, PrimaryPod: newPrimary}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeTrue())
+
+		newPrimary.Status = primary.Status
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeFalse())
+	})
+
+	It("resumes the pooler when the feature is disabled", func() {
+		pooler.Status.PausedDuringSwitchover = true
+		pooler.Spec.PgBouncer.PauseDuringSwitchover = nil
+		cluster.Status.Phase = v1.PhaseSwitchover
+		res := &poolerManagedResources{Cluster: cluster}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeFalse())
+	})
+
+	It("pauses the pooler while switching over from a drained primary", func() {
+		pooler.Spec.PgBouncer.PauseDuringSwitchover = nil
+		cluster.Status.Phase = v1.PhaseSwitchover
+		cluster.Status.TargetPrimary = "cluster-2"
+		res := &poolerManagedResources{Cluster: cluster, PrimaryPod: primary}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeFalse())
+
+		primary.Status.Conditions = append(primary.Status.Conditions,
+			corev1.PodCondition{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue})
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeTrue())
+
+		// the new primary has been promoted
+		pooler.Status.PausedDuringSwitchover = true
+		cluster.Status.CurrentPrimary = "cluster-2"
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeFalse())
+	})
+
+	It("doesn't pause the read-only poolers", func() {
+		pooler.Spec.Type = v1.PoolerTypeRO
+		cluster.Status.Phase = v1.PhaseSwitchover
+		res := &poolerManagedResources{Cluster: cluster}
+		Expect(isPausedDuringSwitchover(pooler, res)).To(BeFalse())
+	})
+})
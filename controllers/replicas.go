@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,6 +29,9 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/diskpressure"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/failover"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/quarantine"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -40,6 +44,10 @@ var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 // elapsed yet
 var ErrWaitingOnFailOverDelay = fmt.Errorf("current primary isn't healthy, waiting for the delay before triggering a failover") //nolint: lll
 
+// ErrPromotionBlocked is raised when the designated primary of a replica
+// cluster can't be changed because its promotion is blocked
+var ErrPromotionBlocked = fmt.Errorf("the promotion of the replica cluster is blocked")
+
 // updateTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPods(
@@ -57,10 +65,11 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 	}
 
 	// First step: check if the current primary is running in an unschedulable node
-	// and issue a switchover if that's the case
+	// and issue a switchover if that's the case, unless the promotion is blocked
 	if primary := status.Items[0]; (primary.IsPrimary || (cluster.IsReplica() && primary.IsPodReady)) &&
 		primary.Pod.Name == cluster.Status.CurrentPrimary &&
-		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary &&
+		!cluster.IsPromotionBlocked() {
 		isPrimaryOnUnschedulableNode, err := r.isNodeUnschedulable(ctx, primary.Node)
 		if err != nil {
 			contextLogger.Error(err, "while checking if current primary is on an unschedulable node")
@@ -72,6 +81,16 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 		}
 	}
 
+	// Then, check if the current primary is running out of disk space
+	// and issue a switchover to a replica with more free space, excluding
+	// the quarantined replicas which are not eligible for promotion
+	if !cluster.IsReplica() {
+		candidates := quarantine.ExcludeQuarantinedReplicas(cluster, status)
+		if switchover := diskpressure.GetSwitchover(cluster, candidates, time.Now()); switchover != nil {
+			return r.switchoverForDiskPressure(ctx, cluster, candidates, switchover)
+		}
+	}
+
 	// Second step: check if the first element of the sorted list is the primary
 	if cluster.IsReplica() {
 		return r.updateTargetPrimaryFromPodsReplicaCluster(ctx, cluster, status, resources)
@@ -90,6 +109,12 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 ) (string, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// Quarantined replicas are not eligible for promotion
+	status = quarantine.ExcludeQuarantinedReplicas(cluster, status)
+	if len(status.Items) == 0 {
+		return "", fmt.Errorf("unable to evaluate failover logic, every instance is quarantined")
+	}
+
 	// When replica mode is not active, the first instance in the list is the primary one.
 	// This means we can just look at the first element of the list to check if the primary
 	// is available or not.
@@ -141,6 +166,16 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", ErrWalReceiversRunning
 	}
 
+	// When the data loss is bounded, the replica to be promoted must be
+	// within the maximum amount of WAL allowed from the last LSN of the primary
+	if cluster.Spec.Failover.IsDataLossBounded() {
+		promotion, err := r.getFailoverPromotion(ctx, cluster, status)
+		if err != nil {
+			return "", err
+		}
+		mostAdvancedInstance = *promotion.Target
+	}
+
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
@@ -173,6 +208,78 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	return mostAdvancedInstance.Pod.Name, r.setPrimaryInstance(ctx, cluster, mostAdvancedInstance.Pod.Name)
 }
 
+// getFailoverPromotion chooses the replica to be promoted within the maximum
+// data loss allowed, returning ErrDataLossThresholdExceeded when the failover
+// must wait
+func (r *ClusterReconciler) getFailoverPromotion(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) (*failover.Promotion, error) {
+	contextLogger := log.FromContext(ctx)
+
+	promotion, err := failover.GetPromotion(cluster, status)
+	switch {
+	case errors.Is(err, failover.ErrDataLossThresholdExceeded):
+		contextLogger.Info("No replica is within the maximum data loss allowed, waiting before failing over",
+			"candidate", promotion.Target.Pod.Name,
+			"dataLossBytes", promotion.DataLossBytes,
+			"maxDataLossBytes", *cluster.Spec.Failover.MaxDataLossBytes,
+			"lastPrimaryLSN", cluster.Status.LastPrimaryLSN)
+		r.Recorder.Eventf(cluster, "Warning", "FailoverBlocked",
+			"Not promoting %v, %d bytes behind the last known LSN of the primary (maximum allowed: %d)",
+			promotion.Target.Pod.Name, promotion.DataLossBytes, *cluster.Spec.Failover.MaxDataLossBytes)
+		return nil, err
+
+	case err != nil:
+		return nil, err
+
+	case promotion.ThresholdExceeded:
+		contextLogger.Info("Promoting a replica beyond the maximum data loss allowed",
+			"newPrimary", promotion.Target.Pod.Name,
+			"dataLossBytes", promotion.DataLossBytes,
+			"maxDataLossBytes", *cluster.Spec.Failover.MaxDataLossBytes)
+		r.Recorder.Eventf(cluster, "Warning", "FailoverDataLoss",
+			"Promoting %v, %d bytes behind the last known LSN of the primary (maximum allowed: %d)",
+			promotion.Target.Pod.Name, promotion.DataLossBytes, *cluster.Spec.Failover.MaxDataLossBytes)
+
+	case promotion.DataLossBytes == failover.UnknownDataLoss:
+		contextLogger.Info("The last LSN of the primary is not known, promoting the most advanced replica",
+			"newPrimary", promotion.Target.Pod.Name)
+	}
+
+	return promotion, nil
+}
+
+// switchoverForDiskPressure promotes the replica selected because the data
+// volume of the current primary is nearly full
+func (r *ClusterReconciler) switchoverForDiskPressure(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	switchover *diskpressure.Switchover,
+) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	contextLogger.Info("Current primary is running out of disk space, triggering a switchover",
+		"currentPrimary", switchover.Primary.Pod.Name,
+		"currentPrimaryDiskUsage", switchover.PrimaryUsage,
+		"targetPrimary", switchover.Target.Pod.Name,
+		"targetPrimaryDiskUsage", switchover.TargetUsage)
+	status.LogStatus(ctx)
+	r.Recorder.Eventf(cluster, "Warning", "SwitchingOverForDiskPressure",
+		"Data volume of the current primary %v is %.1f%% full, switching over to %v (%.1f%% full)",
+		switchover.Primary.Pod.Name, switchover.PrimaryUsage,
+		switchover.Target.Pod.Name, switchover.TargetUsage)
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v, because the data volume of the primary instance "+
+			"is %.1f%% full", switchover.Target.Pod.Name, switchover.PrimaryUsage)); err != nil {
+		return "", err
+	}
+
+	return switchover.Target.Pod.Name, r.setPrimaryInstance(ctx, cluster, switchover.Target.Pod.Name)
+}
+
 // isNodeUnschedulable checks whether a node is set to unschedulable
 func (r *ClusterReconciler) isNodeUnschedulable(ctx context.Context, nodeName string) (bool, error) {
 	var node corev1.Node
@@ -270,6 +377,24 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 ) (string, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// When the promotion is blocked, the designated primary can't be changed,
+	// not even by the user
+	if cluster.IsPromotionBlocked() && cluster.Status.CurrentPrimary != "" &&
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		contextLogger.Warning("Refusing to change the designated primary, the promotion is blocked",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", cluster.Status.TargetPrimary)
+		r.Recorder.Eventf(cluster, "Warning", "PromotionBlocked",
+			"Refusing to switch over from %v to %v, the promotion of the replica cluster is blocked",
+			cluster.Status.CurrentPrimary, cluster.Status.TargetPrimary)
+		cluster.Status.TargetPrimary = cluster.Status.CurrentPrimary
+		cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return "", err
+		}
+		return "", ErrPromotionBlocked
+	}
+
 	// When replica mode is active, the designated primary may not be the first element
 	// in this list, since from the PostgreSQL point-of-view it's not the real primary.
 
@@ -280,6 +405,15 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 		}
 	}
 
+	if cluster.IsPromotionBlocked() {
+		contextLogger.Warning("Current target primary isn't healthy, not failing over as the promotion is blocked",
+			"targetPrimary", cluster.Status.TargetPrimary)
+		r.Recorder.Eventf(cluster, "Warning", "PromotionBlocked",
+			"Current target primary %v isn't healthy, not failing over as the promotion "+
+				"of the replica cluster is blocked", cluster.Status.TargetPrimary)
+		return "", ErrPromotionBlocked
+	}
+
 	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
 		return "", err
 	}
@@ -17,10 +17,16 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -151,3 +157,87 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Replica cluster with blocked promotion", func() {
+	var (
+		cluster    *apiv1.Cluster
+		reconciler *ClusterReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	newInstanceStatus := func(name string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+			IsPodReady: true,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled:          true,
+					Source:           "cluster-origin",
+					PromotionBlocked: true,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				WithStatusSubresource(&apiv1.Cluster{}).
+				Build(),
+			Recorder: recorder,
+		}
+		Expect(reconciler.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	})
+
+	It("refuses to set a new designated primary", func(ctx context.Context) {
+		err := reconciler.setPrimaryInstance(ctx, cluster, "cluster-example-2")
+		Expect(err).To(MatchError(ErrPromotionBlocked))
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("doesn't fail over when the designated primary is not available", func(ctx context.Context) {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newInstanceStatus("cluster-example-2")},
+		}
+		newPrimary, err := reconciler.updateTargetPrimaryFromPodsReplicaCluster(
+			ctx, cluster, status, &managedResources{})
+		Expect(err).To(MatchError(ErrPromotionBlocked))
+		Expect(newPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("PromotionBlocked")))
+	})
+
+	It("reverts the switchovers requested by the user", func(ctx context.Context) {
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstanceStatus("cluster-example-1"),
+				newInstanceStatus("cluster-example-2"),
+			},
+		}
+		_, err := reconciler.updateTargetPrimaryFromPodsReplicaCluster(
+			ctx, cluster, status, &managedResources{})
+		Expect(err).To(MatchError(ErrPromotionBlocked))
+		Expect(recorder.Events).To(Receive(ContainSubstring("PromotionBlocked")))
+
+		var updatedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("allows the changes of the designated primary when the promotion is not blocked", func(ctx context.Context) {
+		cluster.Spec.ReplicaCluster.PromotionBlocked = false
+		Expect(reconciler.setPrimaryInstance(ctx, cluster, "cluster-example-2")).To(Succeed())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+	})
+})
@@ -93,6 +93,7 @@ var _ = BeforeSuite(func() {
 
 	clusterReconciler = &ClusterReconciler{
 		Client:          k8sClient,
+		APIReader:       k8sClient,
 		Scheme:          scheme,
 		Recorder:        record.NewFakeRecorder(120),
 		DiscoveryClient: discoveryClient,
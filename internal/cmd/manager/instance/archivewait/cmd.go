@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archivewait implements the "instance archive-wait" subcommand of the operator
+package archivewait
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+var (
+	// ErrArchiveTimeout is returned when the WAL file has not been
+	// archived before the timeout expired
+	ErrArchiveTimeout = errors.New("timeout while waiting for the WAL file to be archived")
+
+	// ErrNotPrimary is returned when the command is not executed
+	// on the primary instance
+	ErrNotPrimary = errors.New("archive-wait can only be executed on the primary instance")
+)
+
+// walFileNameLength is the length of the name of a WAL segment, i.e.
+// 000000010000000000000001
+const walFileNameLength = 24
+
+// NewCmd creates the "instance archive-wait" subcommand
+func NewCmd() *cobra.Command {
+	var timeout time.Duration
+	var pollInterval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "archive-wait",
+		Short: "Switch to a new WAL file and wait for the previous one to be archived",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			db, err := postgres.NewInstance().GetSuperUserDB()
+			if err != nil {
+				return fmt.Errorf("while connecting to the instance: %w", err)
+			}
+
+			// Returning an error makes the command exit with a nonzero code
+			return archiveWait(ctx, db, pollInterval)
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute,
+		"The maximum amount of time to wait for the WAL file to be archived")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", time.Second,
+		"How often the archiver status is checked")
+
+	return cmd
+}
+
+// archiveWait switches to a new WAL file and waits for the completed
+// one to be archived, until the context expires
+func archiveWait(ctx context.Context, db *sql.DB, pollInterval time.Duration) error {
+	walFile, err := switchWAL(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Waiting for the WAL file to be archived", "walFile", walFile)
+	if err := waitForArchive(ctx, db, walFile, pollInterval); err != nil {
+		log.Error(err, "WAL file not archived", "walFile", walFile)
+		return err
+	}
+
+	log.Info("WAL file archived", "walFile", walFile)
+	return nil
+}
+
+// switchWAL forces PostgreSQL to switch to a new WAL file, returning
+// the name of the one that has just been completed
+func switchWAL(ctx context.Context, db *sql.DB) (string, error) {
+	var inRecovery bool
+	if err := db.QueryRowContext(ctx, "SELECT pg_catalog.pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return "", fmt.Errorf("while checking if the instance is a primary: %w", err)
+	}
+	if inRecovery {
+		return "", ErrNotPrimary
+	}
+
+	var walFile string
+	row := db.QueryRowContext(ctx, "SELECT pg_catalog.pg_walfile_name(pg_catalog.pg_switch_wal())")
+	if err := row.Scan(&walFile); err != nil {
+		return "", fmt.Errorf("while switching to a new WAL file: %w", err)
+	}
+
+	return walFile, nil
+}
+
+// waitForArchive polls pg_stat_archiver until the passed WAL file has
+// been archived or the context expires. Archiving failures are only
+// logged, as PostgreSQL keeps retrying the archive command
+func waitForArchive(ctx context.Context, db *sql.DB, walFile string, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		archived, err := isArchived(ctx, db, walFile)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Warning("Error while checking the archiver status", "err", err.Error())
+		case archived:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrArchiveTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// isArchived checks whether the passed WAL file, or a more recent one,
+// has already been archived
+func isArchived(ctx context.Context, db *sql.DB, walFile string) (bool, error) {
+	var lastArchivedWAL, lastFailedWAL string
+	row := db.QueryRowContext(ctx,
+		"SELECT COALESCE(last_archived_wal, ''), COALESCE(last_failed_wal, '') "+
+			"FROM pg_catalog.pg_stat_archiver")
+	if err := row.Scan(&lastArchivedWAL, &lastFailedWAL); err != nil {
+		return false, err
+	}
+
+	if isSegmentAtLeast(lastArchivedWAL, walFile) {
+		return true, nil
+	}
+
+	if lastFailedWAL == walFile {
+		log.Info("The archiver failed archiving the WAL file, waiting for it to retry", "walFile", walFile)
+	}
+
+	return false, nil
+}
+
+// isSegmentAtLeast returns true when the passed name is the one of a WAL
+// segment which is the same or more recent than the target one. History
+// files and partial WAL files are not considered
+func isSegmentAtLeast(name, target string) bool {
+	if len(name) != walFileNameLength {
+		return false
+	}
+
+	return name >= target
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archivewait
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("archive-wait", func() {
+	const (
+		inRecoveryQuery = "SELECT pg_catalog.pg_is_in_recovery()"
+		switchQuery     = "SELECT pg_catalog.pg_walfile_name(pg_catalog.pg_switch_wal())"
+		archiverQuery   = "SELECT COALESCE(last_archived_wal, ''), COALESCE(last_failed_wal, '') " +
+			"FROM pg_catalog.pg_stat_archiver"
+		walFile = "000000010000000000000005"
+	)
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	archiverRows := func(lastArchived, lastFailed string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"last_archived_wal", "last_failed_wal"}).
+			AddRow(lastArchived, lastFailed)
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("refuses to run on a replica", func(ctx SpecContext) {
+		mock.ExpectQuery(inRecoveryQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+
+		err := archiveWait(ctx, db, time.Millisecond)
+		Expect(err).To(MatchError(ErrNotPrimary))
+	})
+
+	It("polls until the WAL file is archived", func(ctx SpecContext) {
+		mock.ExpectQuery(inRecoveryQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+		mock.ExpectQuery(switchQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_walfile_name"}).AddRow(walFile))
+		mock.ExpectQuery(archiverQuery).WillReturnRows(archiverRows("000000010000000000000004", ""))
+		mock.ExpectQuery(archiverQuery).WillReturnRows(archiverRows("000000010000000000000004", walFile))
+		mock.ExpectQuery(archiverQuery).WillReturnRows(archiverRows(walFile, walFile))
+
+		Expect(archiveWait(ctx, db, time.Millisecond)).To(Succeed())
+	})
+
+	It("considers the WAL file archived when a more recent one has been archived", func(ctx SpecContext) {
+		mock.ExpectQuery(archiverQuery).WillReturnRows(archiverRows("000000010000000000000006", ""))
+
+		Expect(waitForArchive(ctx, db, walFile, time.Millisecond)).To(Succeed())
+	})
+
+	It("ignores the history files", func(ctx SpecContext) {
+		mock.ExpectQuery(archiverQuery).WillReturnRows(archiverRows("00000002.history", ""))
+
+		archived, err := isArchived(ctx, db, walFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(archived).To(BeFalse())
+	})
+
+	It("returns a timeout error when the WAL file is not archived in time", func(ctx SpecContext) {
+		mock.ExpectQuery(inRecoveryQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+		mock.ExpectQuery(switchQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_walfile_name"}).AddRow(walFile))
+		mock.ExpectQuery(archiverQuery).WillReturnRows(archiverRows("000000010000000000000004", ""))
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		// the error is returned by the command, making it exit with a nonzero code
+		err := archiveWait(timeoutCtx, db, time.Hour)
+		Expect(err).To(MatchError(ErrArchiveTimeout))
+	})
+})
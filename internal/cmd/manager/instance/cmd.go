@@ -22,9 +22,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/archivewait"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/initdb"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/join"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/pgbasebackup"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/prestop"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restoresnapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
@@ -48,6 +50,8 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(pgbasebackup.NewCmd())
 	cmd.AddCommand(restore.NewCmd())
 	cmd.AddCommand(restoresnapshot.NewCmd())
+	cmd.AddCommand(archivewait.NewCmd())
+	cmd.AddCommand(prestop.NewCmd())
 
 	return cmd
 }
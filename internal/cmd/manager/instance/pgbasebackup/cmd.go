@@ -19,15 +19,19 @@ package pgbasebackup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -38,10 +42,15 @@ import (
 // CloneInfo is the structure containing all the information needed
 // to clone an existing server
 type CloneInfo struct {
-	info   *postgres.InitInfo
-	client ctrl.Client
+	info      *postgres.InitInfo
+	client    ctrl.Client
+	connector external.Connector
 }
 
+// sourceCheckInterval is the time between the test connections to the
+// source server, while it can't be reached
+const sourceCheckInterval = 10 * time.Second
+
 // NewCmd creates the "pgbasebackup" subcommand
 func NewCmd() *cobra.Command {
 	var clusterName string
@@ -70,7 +79,8 @@ func NewCmd() *cobra.Command {
 					PgData:      pgData,
 					PgWal:       pgWal,
 				},
-				client: client,
+				client:    client,
+				connector: external.NewConnector(),
 			}
 
 			ctx := context.Background()
@@ -136,6 +146,11 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 			return err
 		}
 	}
+
+	if err := env.waitForSourceServer(ctx, &cluster, server.Name, connectionString, sourceCheckInterval); err != nil {
+		return err
+	}
+
 	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal)
 	if err != nil {
 		return err
@@ -150,6 +165,76 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 	return env.configureInstanceAsNewPrimary(ctx, &cluster)
 }
 
+// waitForSourceServer opens test connections to the source server until
+// it can be reached with the configured credentials, reporting the outcome
+// in the conditions of the cluster. This avoids failing the bootstrap,
+// and crash-looping the Pod, while the source server is not reachable
+func (env *CloneInfo) waitForSourceServer(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	serverName string,
+	connectionString string,
+	interval time.Duration,
+) error {
+	for {
+		identity, checkErr := external.CheckServer(ctx, env.connector, connectionString)
+		condition := getSourceReachableCondition(serverName, identity, checkErr)
+		if err := conditions.Patch(ctx, env.client, cluster, condition); err != nil {
+			log.Error(err, "while updating the condition of the source server")
+		}
+
+		if checkErr == nil {
+			log.Info("The source server is reachable",
+				"server", serverName, "systemID", identity.SystemID, "timeline", identity.Timeline)
+			return nil
+		}
+
+		log.Warning("Cannot connect to the source server, retrying",
+			"server", serverName, "error", checkErr.Error(), "interval", interval.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// getSourceReachableCondition builds the condition reporting the outcome
+// of the test connection to the source server
+func getSourceReachableCondition(
+	serverName string,
+	identity *external.SystemIdentity,
+	err error,
+) *metav1.Condition {
+	switch {
+	case err == nil:
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionBootstrapSourceReachable),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonSourceReachable),
+			Message: fmt.Sprintf("Connected to the external cluster %s, system identifier %s, timeline %s",
+				serverName, identity.SystemID, identity.Timeline),
+		}
+
+	case errors.Is(err, external.ErrAuthenticationFailed):
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionBootstrapSourceReachable),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonSourceAuthenticationFailed),
+			Message: fmt.Sprintf("The external cluster %s refused the credentials, "+
+				"check its user and password secret: %s", serverName, err.Error()),
+		}
+
+	default:
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionBootstrapSourceReachable),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonSourceUnreachable),
+			Message: fmt.Sprintf("Cannot connect to the external cluster %s: %s", serverName, err.Error()),
+		}
+	}
+}
+
 // configureInstanceAsNewPrimary sets up this instance as a new primary server, using
 // the configuration created by the user and setting up the global objects as needed
 func (env *CloneInfo) configureInstanceAsNewPrimary(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -0,0 +1,128 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbasebackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeConnector is a Connector returning the configured outcomes one
+// after the other, the last one being repeated
+type fakeConnector struct {
+	errors []error
+	calls  int
+}
+
+func (f *fakeConnector) IdentifySystem(_ context.Context, _ string) (*external.SystemIdentity, error) {
+	err := f.errors[min(f.calls, len(f.errors)-1)]
+	f.calls++
+	if err != nil {
+		return nil, err
+	}
+	return &external.SystemIdentity{SystemID: "7300000000000000000", Timeline: "1", XLogPos: "0/3000060"}, nil
+}
+
+var _ = Describe("test connection to the source server", func() {
+	var (
+		cluster   *apiv1.Cluster
+		cli       ctrl.Client
+		connector *fakeConnector
+		env       *CloneInfo
+	)
+
+	authFailure := fmt.Errorf("failed to connect: %w", &pgconn.PgError{
+		Severity: "FATAL",
+		Code:     "28P01",
+		Message:  `password authentication failed for user "streaming_replica"`,
+	})
+
+	getCondition := func(ctx context.Context) *metav1.Condition {
+		var stored apiv1.Cluster
+		Expect(cli.Get(ctx, ctrl.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		return meta.FindStatusCondition(stored.Status.Conditions, string(apiv1.ConditionBootstrapSourceReachable))
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"}}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		connector = &fakeConnector{}
+		env = &CloneInfo{info: &postgres.InitInfo{}, client: cli, connector: connector}
+	})
+
+	It("reports a reachable source server", func(ctx context.Context) {
+		connector.errors = []error{nil}
+		Expect(env.waitForSourceServer(ctx, cluster, "cluster-origin", "host=origin", time.Millisecond)).
+			To(Succeed())
+		Expect(connector.calls).To(Equal(1))
+
+		condition := getCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSourceReachable)))
+		Expect(condition.Message).To(ContainSubstring("7300000000000000000"))
+	})
+
+	It("retries after an authentication failure instead of failing", func(ctx context.Context) {
+		connector.errors = []error{authFailure, authFailure, nil}
+		Expect(env.waitForSourceServer(ctx, cluster, "cluster-origin", "host=origin", time.Millisecond)).
+			To(Succeed())
+		Expect(connector.calls).To(Equal(3))
+		Expect(getCondition(ctx).Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("surfaces the authentication failure in the condition", func(ctx context.Context) {
+		connector.errors = []error{authFailure}
+		waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		err := env.waitForSourceServer(waitCtx, cluster, "cluster-origin", "host=origin", time.Millisecond)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		condition := getCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSourceAuthenticationFailed)))
+		Expect(condition.Message).To(ContainSubstring("cluster-origin"))
+	})
+
+	It("distinguishes the unreachable source servers", func() {
+		condition := getSourceReachableCondition("cluster-origin", nil,
+			fmt.Errorf("dial tcp: lookup cluster-origin-rw: no such host"))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSourceUnreachable)))
+		Expect(condition.Message).To(ContainSubstring("no such host"))
+	})
+})
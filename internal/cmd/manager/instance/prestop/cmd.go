@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prestop implement the "instance prestop" subcommand of the operator,
+// invoked by the preStop hook of the Pod
+package prestop
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewCmd create the "instance prestop" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prestop",
+		Short: "Coordinate the shutdown of the instance before the Pod is stopped",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return preStopSubCommand()
+		},
+	}
+
+	return cmd
+}
+
+func preStopSubCommand() error {
+	preStopURL := url.Local(url.PathPgPreStop, url.LocalPort)
+	// The request blocks until the switchover of a drained primary is complete,
+	// the kubelet is in charge of terminating it at the end of the grace period
+	resp, err := http.Post(preStopURL, "", nil) // nolint:gosec
+	if err != nil {
+		log.Error(err, "Error while requesting the shutdown coordination")
+		return err
+	}
+
+	defer func() {
+		err = resp.Body.Close()
+		if err != nil {
+			log.Error(err, "Can't close the connection",
+				"preStopURL", preStopURL,
+				"statusCode", resp.StatusCode,
+			)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error(err, "Error while reading the shutdown coordination response body",
+			"preStopURL", preStopURL,
+			"statusCode", resp.StatusCode,
+		)
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Info(
+			"Error while coordinating the shutdown",
+			"preStopURL", preStopURL,
+			"statusCode", resp.StatusCode,
+			"body", string(body),
+		)
+		return fmt.Errorf("invalid status code: %v", resp.StatusCode)
+	}
+
+	return nil
+}
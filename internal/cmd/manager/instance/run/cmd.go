@@ -37,7 +37,10 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/analyze"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/maintenance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/sessions"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
@@ -128,12 +131,14 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 			},
 		},
 		// We don't need a cache for secrets and configmap, as all reloads
-		// should be driven by changes in the Cluster we are watching
+		// should be driven by changes in the Cluster we are watching.
+		// Poolers are only read by the primary, to resume them after a drain
 		Client: client.Options{
 			Cache: &client.CacheOptions{
 				DisableFor: []client.Object{
 					&corev1.Secret{},
 					&corev1.ConfigMap{},
+					&apiv1.Pooler{},
 				},
 			},
 		},
@@ -173,7 +178,7 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 	exitedConditions = append(exitedConditions, postgresLogPipe.GetExitedCondition())
 
 	// raw logs handler
-	rawPipe := logpipe.NewRawLineLogPipe(filepath.Join(pg.LogPath, pg.LogFileName),
+	rawPipe := logpipe.NewRawLineLogPipe(filepath.Join(logpipe.GetLogDirectory(), pg.LogFileName),
 		logpipe.LoggingCollectorRecordName)
 	if err := mgr.Add(rawPipe); err != nil {
 		return err
@@ -221,6 +226,21 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	if err = mgr.Add(sessions.NewIdleInTransactionTerminator(instance)); err != nil {
+		setupLog.Error(err, "unable to create idle in transaction sessions terminator")
+		return err
+	}
+
+	if err = mgr.Add(analyze.NewPostImportAnalyzer(instance, reconciler.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create post import analyzer")
+		return err
+	}
+
+	if err = mgr.Add(maintenance.NewJobRunner(instance, reconciler.GetClient(), mgr.GetAPIReader())); err != nil {
+		setupLog.Error(err, "unable to create maintenance job runner")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
@@ -0,0 +1,123 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// postStartRetryInterval is the time between two executions of the
+// postStartSQL statements, when they fail and the failure policy is `block`
+const postStartRetryInterval = 5 * time.Second
+
+// getLifecycleConfiguration returns the lifecycle configuration of the
+// cluster stored in the local cache, if any
+func getLifecycleConfiguration() *apiv1.LifecycleConfiguration {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return nil
+	}
+	return cluster.Spec.Lifecycle
+}
+
+// runSQLStatements executes the passed statements, in order, stopping
+// at the first one that fails
+func runSQLStatements(
+	ctx context.Context,
+	db *sql.DB,
+	statements []string,
+	timeout time.Duration,
+) error {
+	for idx, statement := range statements {
+		if err := runSQLStatement(ctx, db, statement, timeout); err != nil {
+			return fmt.Errorf("while executing statement %d (%q): %w", idx+1, statement, err)
+		}
+	}
+	return nil
+}
+
+func runSQLStatement(ctx context.Context, db *sql.DB, statement string, timeout time.Duration) error {
+	statementCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := db.ExecContext(statementCtx, statement)
+	return err
+}
+
+// runPostStartSQL executes the postStartSQL statements. When the failure
+// policy is `block`, the statements are executed again until they succeed
+// or the context is cancelled, and an error is returned only in the latter
+// case. Otherwise, failures are just logged.
+func runPostStartSQL(
+	ctx context.Context,
+	db *sql.DB,
+	lifecycle *apiv1.LifecycleConfiguration,
+	retryInterval time.Duration,
+) error {
+	if lifecycle == nil || len(lifecycle.PostStartSQL) == 0 {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithName("lifecycle")
+	for {
+		contextLogger.Info("Executing the postStartSQL statements",
+			"count", len(lifecycle.PostStartSQL))
+		err := runSQLStatements(ctx, db, lifecycle.PostStartSQL, lifecycle.GetTimeout())
+		if err == nil {
+			return nil
+		}
+
+		if lifecycle.GetFailurePolicy() != apiv1.LifecycleHookFailurePolicyBlock {
+			contextLogger.Error(err, "postStartSQL failed, continuing as requested by the failure policy")
+			return nil
+		}
+
+		contextLogger.Error(err, "postStartSQL failed, the instance won't be ready until it succeeds",
+			"retryInterval", retryInterval)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("postStartSQL has not been executed: %w", ctx.Err())
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// runPreStopSQL executes the preStopSQL statements. Failures are logged and
+// never prevent the instance from being shut down
+func runPreStopSQL(ctx context.Context, db *sql.DB, lifecycle *apiv1.LifecycleConfiguration) {
+	if lifecycle == nil || len(lifecycle.PreStopSQL) == 0 {
+		return
+	}
+
+	// the statements must be executed even if we are shutting down
+	// because our context has been cancelled
+	ctx = context.WithoutCancel(ctx)
+
+	contextLogger := log.FromContext(ctx).WithName("lifecycle")
+	contextLogger.Info("Executing the preStopSQL statements",
+		"count", len(lifecycle.PreStopSQL))
+	if err := runSQLStatements(ctx, db, lifecycle.PreStopSQL, lifecycle.GetTimeout()); err != nil {
+		contextLogger.Error(err, "preStopSQL failed, proceeding with the shutdown")
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lifecycle SQL statements", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("does nothing when there is no lifecycle configuration", func() {
+		Expect(runPostStartSQL(context.TODO(), db, nil, time.Millisecond)).To(Succeed())
+		runPreStopSQL(context.TODO(), db, nil)
+	})
+
+	It("executes the postStartSQL statements in order", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PostStartSQL: []string{
+				"SELECT pg_reload_conf()",
+				"SELECT pg_prewarm('orders')",
+			},
+		}
+		mock.ExpectExec("SELECT pg_reload_conf()").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT pg_prewarm('orders')").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(runPostStartSQL(context.TODO(), db, lifecycle, time.Millisecond)).To(Succeed())
+	})
+
+	It("logs and continues when postStartSQL fails with the ignore policy", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PostStartSQL: []string{
+				"SELECT pg_reload_conf()",
+				"SELECT pg_prewarm('orders')",
+			},
+			FailurePolicy: apiv1.LifecycleHookFailurePolicyIgnore,
+		}
+		mock.ExpectExec("SELECT pg_reload_conf()").WillReturnError(errors.New("boom"))
+
+		Expect(runPostStartSQL(context.TODO(), db, lifecycle, time.Millisecond)).To(Succeed())
+	})
+
+	It("retries postStartSQL until it succeeds with the block policy", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PostStartSQL: []string{
+				"SELECT pg_reload_conf()",
+				"SELECT pg_prewarm('orders')",
+			},
+			FailurePolicy: apiv1.LifecycleHookFailurePolicyBlock,
+		}
+		mock.ExpectExec("SELECT pg_reload_conf()").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT pg_prewarm('orders')").WillReturnError(errors.New("boom"))
+		mock.ExpectExec("SELECT pg_reload_conf()").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT pg_prewarm('orders')").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(runPostStartSQL(context.TODO(), db, lifecycle, time.Millisecond)).To(Succeed())
+	})
+
+	It("returns an error with the block policy when the context is cancelled", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PostStartSQL:  []string{"SELECT pg_reload_conf()"},
+			FailurePolicy: apiv1.LifecycleHookFailurePolicyBlock,
+		}
+		mock.ExpectExec("SELECT pg_reload_conf()").WillReturnError(errors.New("boom"))
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		Expect(runPostStartSQL(ctx, db, lifecycle, time.Hour)).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("executes the preStopSQL statements in order even when the context is cancelled", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PreStopSQL: []string{
+				"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = 'app'",
+				"CHECKPOINT",
+			},
+		}
+		mock.ExpectExec("SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = 'app'").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CHECKPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		runPreStopSQL(ctx, db, lifecycle)
+	})
+
+	It("stops executing the preStopSQL statements at the first failure", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PreStopSQL: []string{"CHECKPOINT", "SELECT 1"},
+		}
+		mock.ExpectExec("CHECKPOINT").WillReturnError(errors.New("boom"))
+
+		runPreStopSQL(context.TODO(), db, lifecycle)
+	})
+
+	It("applies the timeout to every statement", func() {
+		lifecycle := &apiv1.LifecycleConfiguration{
+			PostStartSQL: []string{"SELECT pg_sleep(10)"},
+			Timeout:      1,
+		}
+		Expect(lifecycle.GetTimeout()).To(Equal(time.Second))
+		Expect((&apiv1.LifecycleConfiguration{}).GetTimeout()).
+			To(Equal(apiv1.DefaultLifecycleHookTimeout * time.Second))
+
+		mock.ExpectExec("SELECT pg_sleep(10)").WillDelayFor(time.Minute).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		err := runSQLStatements(context.TODO(), db, lifecycle.PostStartSQL, 10*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+	})
+})
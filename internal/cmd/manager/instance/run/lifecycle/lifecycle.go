@@ -111,6 +111,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					return nil
 				}
 				contextLogger.Info("Context has been cancelled, shutting down and exiting")
+				i.runPreStopSQL(ctx)
 				if err := i.instance.TryShuttingDownSmartFast(ctx); err != nil {
 					contextLogger.Error(err, "error shutting down instance, proceeding")
 				}
@@ -125,6 +126,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					"signal", sig,
 					"smartShutdownTimeout", i.instance.SmartStopDelay,
 				)
+				i.runPreStopSQL(ctx)
 				if err := i.instance.TryShuttingDownSmartFast(ctx); err != nil {
 					contextLogger.Error(err, "error while shutting down instance, proceeding")
 				}
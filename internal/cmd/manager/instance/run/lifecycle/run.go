@@ -21,6 +21,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/jackc/pgx/v5"
 
@@ -74,9 +75,23 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 			return
 		}
 
-		// from now on the instance can be considered ready
-		i.instance.SetCanCheckReadiness(true)
+		// the instance can be considered ready as soon as the postStartSQL
+		// statements have been executed, while we keep waiting for the postmaster
+		postStartCtx, cancelPostStart := context.WithCancel(ctx)
+		var postStartWg sync.WaitGroup
 		defer i.instance.SetCanCheckReadiness(false)
+		defer postStartWg.Wait()
+		defer cancelPostStart()
+
+		postStartWg.Add(1)
+		go func() {
+			defer postStartWg.Done()
+			if err := i.runPostStartSQL(postStartCtx); err != nil {
+				contextLogger.Error(err, "The instance will not be reported as ready")
+				return
+			}
+			i.instance.SetCanCheckReadiness(true)
+		}()
 
 		errChan <- streamingCmd.Wait()
 	}()
@@ -84,6 +99,35 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 	return errChan
 }
 
+// runPostStartSQL executes the postStartSQL statements of the cluster
+func (i *PostgresLifecycle) runPostStartSQL(ctx context.Context) error {
+	lifecycle := getLifecycleConfiguration()
+	if lifecycle == nil || len(lifecycle.PostStartSQL) == 0 {
+		return nil
+	}
+
+	db, err := i.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while getting a connection to the instance: %w", err)
+	}
+	return runPostStartSQL(ctx, db, lifecycle, postStartRetryInterval)
+}
+
+// runPreStopSQL executes the preStopSQL statements of the cluster
+func (i *PostgresLifecycle) runPreStopSQL(ctx context.Context) {
+	lifecycle := getLifecycleConfiguration()
+	if lifecycle == nil || len(lifecycle.PreStopSQL) == 0 {
+		return
+	}
+
+	db, err := i.instance.GetSuperUserDB()
+	if err != nil {
+		log.FromContext(ctx).Error(err, "while getting a connection to the instance, skipping preStopSQL")
+		return
+	}
+	runPreStopSQL(ctx, db, lifecycle)
+}
+
 // ConfigureInstancePermissions creates the expected users and databases in a new
 // PostgreSQL instance
 func configureInstancePermissions(ctx context.Context, instance *postgres.Instance) error {
@@ -26,6 +26,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/types"
@@ -42,6 +43,8 @@ import (
 func NewCmd() *cobra.Command {
 	var (
 		poolerNamespacedName types.NamespacedName
+		metricsTLS           bool
+		drainTimeout         time.Duration
 
 		errorMissingPoolerNamespacedName = fmt.Errorf("missing pooler name or namespace")
 	)
@@ -64,7 +67,7 @@ func NewCmd() *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := runSubCommand(cmd.Context(), poolerNamespacedName); err != nil {
+			if err := runSubCommand(cmd.Context(), poolerNamespacedName, metricsTLS, drainTimeout); err != nil {
 				log.Error(err, "Error while running manager")
 				return err
 			}
@@ -84,18 +87,34 @@ func NewCmd() *cobra.Command {
 		os.Getenv(poolerNamespaceEnvVar),
 		"The namespace of the cluster and of the Pod in k8s. "+
 			"Defaults to the value of the NAMESPACE environment variable")
+	cmd.Flags().BoolVar(
+		&metricsTLS,
+		"metrics-tls",
+		false,
+		"Serve the metrics over HTTPS, using the server certificate of the cluster")
+	cmd.Flags().DurationVar(
+		&drainTimeout,
+		"drain-timeout",
+		0,
+		"The maximum time to wait for the in-flight transactions when shutting down. "+
+			"When not set, PgBouncer is stopped with a safe shutdown")
 
 	return cmd
 }
 
-func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedName) error {
+func runSubCommand(
+	ctx context.Context,
+	poolerNamespacedName types.NamespacedName,
+	metricsTLS bool,
+	drainTimeout time.Duration,
+) error {
 	var err error
 
 	log.Info("Starting CloudNativePG PgBouncer Instance Manager",
 		"version", versions.Version,
 		"build", versions.Info)
 
-	if err = startWebServer(); err != nil {
+	if err = startWebServer(metricsTLS); err != nil {
 		return fmt.Errorf("while starting the web server: %w", err)
 	}
 
@@ -126,9 +145,15 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 	}
 
 	startReconciler(ctx, reconciler)
-	registerSignalHandler(reconciler, pgBouncerCmd)
+	registerSignalHandler(ctx, reconciler, pgBouncerCmd, drainTimeout)
 
-	if err = streamingCmd.Wait(); err != nil {
+	err = streamingCmd.Wait()
+
+	// The output of pgbouncer has been completely read, and the last
+	// record, often the reason why pgbouncer exited, is still pending
+	_ = stderrWriter.Close()
+
+	if err != nil {
 		var exitError *exec.ExitError
 		if !errors.As(err, &exitError) {
 			log.Error(err, "Error waiting on pgbouncer process")
@@ -143,7 +168,12 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 
 // registerSignalHandler handles signals from k8s, notifying postgres as
 // needed
-func registerSignalHandler(reconciler *controller.PgBouncerReconciler, command *exec.Cmd) {
+func registerSignalHandler(
+	ctx context.Context,
+	reconciler *controller.PgBouncerReconciler,
+	command *exec.Cmd,
+	drainTimeout time.Duration,
+) {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
@@ -161,6 +191,15 @@ func registerSignalHandler(reconciler *controller.PgBouncerReconciler, command *
 
 		reconciler.Stop()
 
+		if drainTimeout > 0 {
+			log.Info("Draining pgbouncer connections", "timeout", drainTimeout)
+			err := reconciler.Drain(ctx, drainTimeout)
+			if err == nil {
+				return
+			}
+			log.Error(err, "Error while draining pgbouncer connections")
+		}
+
 		if command != nil {
 			log.Info("Shutting down pgbouncer instance")
 			err := command.Process.Signal(syscall.SIGINT)
@@ -173,13 +212,13 @@ func registerSignalHandler(reconciler *controller.PgBouncerReconciler, command *
 
 // startWebServer start the web server for handling probes given
 // a certain PostgreSQL instance
-func startWebServer() error {
+func startWebServer(metricsTLS bool) error {
 	if err := metricsserver.Setup(); err != nil {
 		return err
 	}
 
 	go func() {
-		err := metricsserver.ListenAndServe()
+		err := metricsserver.ListenAndServe(metricsTLS)
 		if err != nil {
 			log.Error(err, "Error while starting the metrics server")
 		}
@@ -21,43 +21,125 @@ import (
 	"bytes"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
 
-var pgBouncerLogRegex = regexp.
-	MustCompile(`(?s)(?P<Timestamp>^.*) \[(?P<Pid>[0-9]+)\] (?P<Level>[A-Z]+) (?P<Msg>.+)$`)
+// continuationTimeout is how long we wait for the continuation lines of
+// a multi-line record before writing it
+const continuationTimeout = 100 * time.Millisecond
 
+var (
+	pgBouncerLogRegex = regexp.MustCompile(
+		`(?s)(?P<Timestamp>^.*) \[(?P<Pid>[0-9]+)\] (?P<Level>[A-Z]+) (?P<Msg>.+)$`)
+
+	// pgBouncerConnectionRegex matches the messages about a client
+	// or a server connection, such as:
+	// "C-0x22824f0: app/app@10.244.0.251:41996 login attempt: db=app user=app"
+	pgBouncerConnectionRegex = regexp.MustCompile(
+		`(?s)^(?P<Kind>[CS])-(?P<ID>0x[0-9a-f]+): ` +
+			`(?P<Database>[^/]*)/(?P<User>[^@]*)@(?P<Address>\S+) (?P<Msg>.+)$`)
+)
+
+// pgBouncerEvents maps the prefix of the log messages to the
+// corresponding event, and is evaluated in order
+var pgBouncerEvents = []struct {
+	prefix string
+	event  string
+}{
+	{prefix: "stats:", event: "stats"},
+	{prefix: "login attempt", event: "login_attempt"},
+	{prefix: "login failed", event: "login_failed"},
+	{prefix: "closing because", event: "closing"},
+	{prefix: "new connection to server", event: "server_connection"},
+	{prefix: "SSL established", event: "tls_established"},
+	{prefix: "registered new auto-database", event: "auto_database"},
+	{prefix: "pooler error", event: "pooler_error"},
+	{prefix: "listening on", event: "listening"},
+	{prefix: "process up", event: "startup"},
+	{prefix: "kernel file descriptor limit", event: "fd_limit"},
+	{prefix: "RELOAD command issued", event: "reload"},
+	{prefix: "PAUSE command issued", event: "pause"},
+	{prefix: "RESUME command issued", event: "resume"},
+	{prefix: "got SIGINT", event: "shutdown"},
+	{prefix: "got SIGTERM", event: "shutdown"},
+}
+
+// pgBouncerLogWriter parses the log lines written by pgbouncer and
+// writes them as structured records
 type pgBouncerLogWriter struct {
 	Logger log.Logger
+
+	mu sync.Mutex
+	// pending is the record being written, waiting for its
+	// continuation lines
+	pending string
+	timer   *time.Timer
 }
 
 func (p *pgBouncerLogWriter) Write(in []byte) (n int, err error) {
 	// pgbouncer can write multi-line logs, and each continuation line starts
-	// with "\t". This code will parse that and work on each log line separately
-
-	currentLogLine := ""
+	// with "\t". Since we receive the output one line at a time, we keep the
+	// current record until a new one starts or the continuation timeout
+	// expires, so that multi-line records are written together.
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
 	sc := bufio.NewScanner(bytes.NewReader(in))
 	for sc.Scan() {
 		logLine := sc.Text()
-		if strings.HasPrefix(logLine, "\t") {
-			currentLogLine += logLine
+		if strings.HasPrefix(logLine, "\t") && p.pending != "" {
+			p.pending += "\n" + strings.TrimPrefix(logLine, "\t")
 			continue
 		}
 
-		if currentLogLine != "" {
-			p.writePgbouncerLogLine(currentLogLine)
+		p.flushPending()
+		p.pending = logLine
+	}
+
+	if p.pending != "" {
+		if p.timer == nil {
+			p.timer = time.AfterFunc(continuationTimeout, p.flush)
+		} else {
+			p.timer.Reset(continuationTimeout)
 		}
+	}
+
+	return len(in), nil
+}
+
+// Close writes the pending record, if any, without waiting for its
+// continuation lines, which will never come once pgbouncer exited
+func (p *pgBouncerLogWriter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-		currentLogLine = logLine
+	if p.timer != nil {
+		p.timer.Stop()
 	}
+	p.flushPending()
+	return nil
+}
+
+// flush writes the pending record, if any
+func (p *pgBouncerLogWriter) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flushPending()
+}
 
-	if currentLogLine != "" {
-		p.writePgbouncerLogLine(currentLogLine)
+// flushPending writes the pending record, if any. The caller must hold
+// the lock
+func (p *pgBouncerLogWriter) flushPending() {
+	if p.pending == "" {
+		return
 	}
 
-	return len(in), nil
+	p.writePgbouncerLogLine(p.pending)
+	p.pending = ""
 }
 
 func (p *pgBouncerLogWriter) writePgbouncerLogLine(line string) {
@@ -68,19 +150,69 @@ func (p *pgBouncerLogWriter) writePgbouncerLogLine(line string) {
 	case len(matches) != 5:
 		p.Logger.WithValues("matched", false, "matches", matches).Info(line)
 	default:
-		p.Logger.Info("record", "record",
-			pgBouncerLogRecord{
-				Timestamp: matches[1],
-				Pid:       matches[2],
-				Level:     matches[3],
-				Msg:       matches[4],
-			})
+		p.Logger.Info("record", "record", newPgBouncerLogRecord(matches[1], matches[2], matches[3], matches[4]))
 	}
 }
 
+// newPgBouncerLogRecord creates a log record, detecting the event and
+// the connection the message refers to
+func newPgBouncerLogRecord(timestamp, pid, level, msg string) pgBouncerLogRecord {
+	record := pgBouncerLogRecord{
+		Timestamp: timestamp,
+		Pid:       pid,
+		Level:     level,
+		Msg:       msg,
+	}
+
+	eventMsg := msg
+	if matches := pgBouncerConnectionRegex.FindStringSubmatch(msg); matches != nil {
+		record.Connection = &pgBouncerLogConnection{
+			Kind:     "client",
+			ID:       matches[2],
+			Database: normalizeConnectionField(matches[3], "(nodb)"),
+			User:     normalizeConnectionField(matches[4], "(nouser)"),
+			Address:  matches[5],
+		}
+		if matches[1] == "S" {
+			record.Connection.Kind = "server"
+		}
+		eventMsg = matches[6]
+	}
+
+	for _, event := range pgBouncerEvents {
+		if strings.HasPrefix(eventMsg, event.prefix) {
+			record.Event = event.event
+			break
+		}
+	}
+
+	return record
+}
+
+// normalizeConnectionField removes the placeholder pgbouncer uses
+// when the database or the user are not yet known
+func normalizeConnectionField(value, placeholder string) string {
+	if value == placeholder {
+		return ""
+	}
+	return value
+}
+
 type pgBouncerLogRecord struct {
-	Timestamp string `json:"timestamp"`
-	Pid       string `json:"pid"`
-	Level     string `json:"level"`
-	Msg       string `json:"msg"`
+	Timestamp  string                  `json:"timestamp"`
+	Pid        string                  `json:"pid"`
+	Level      string                  `json:"level"`
+	Event      string                  `json:"event,omitempty"`
+	Connection *pgBouncerLogConnection `json:"connection,omitempty"`
+	Msg        string                  `json:"msg"`
+}
+
+// pgBouncerLogConnection is the client or server connection a log
+// record refers to
+type pgBouncerLogConnection struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	Database string `json:"database,omitempty"`
+	User     string `json:"user,omitempty"`
+	Address  string `json:"address"`
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package run
 
 import (
+	"bufio"
 	"io"
 	"os"
 
@@ -40,6 +41,7 @@ var _ = Describe("pgbouncer log parsing", func() {
 		}
 		_, err = io.Copy(&writer, f)
 		Expect(err).ToNot(HaveOccurred())
+		writer.flush()
 
 		// Check if we received the correct records
 		Expect(spy.Records).To(HaveLen(27))
@@ -58,4 +60,104 @@ var _ = Describe("pgbouncer log parsing", func() {
 			Expect(pgbouncerLog.(pgBouncerLogRecord).Msg).ToNot(BeEmpty())
 		}
 	})
+
+	It("groups the continuation lines written one at a time", func() {
+		f, err := os.Open("testdata/pgbouncer-multiline.log")
+		defer func() {
+			_ = f.Close()
+		}()
+		Expect(err).ToNot(HaveOccurred())
+
+		spy := logtest.NewSpy()
+		writer := pgBouncerLogWriter{
+			Logger: spy,
+		}
+
+		// This is how the output of pgbouncer is streamed to the writer
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			_, err = writer.Write(sc.Bytes())
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(sc.Err()).ToNot(HaveOccurred())
+
+		// The last record is written when the continuation timeout expires
+		countRecords := func() int {
+			writer.mu.Lock()
+			defer writer.mu.Unlock()
+			return len(spy.Records)
+		}
+		Expect(countRecords()).To(Equal(3))
+		Eventually(countRecords).WithTimeout(continuationTimeout * 10).Should(Equal(4))
+
+		stats := spy.Records[2].Attributes["record"].(pgBouncerLogRecord)
+		Expect(stats.Event).To(Equal("stats"))
+		Expect(stats.Connection).To(BeNil())
+		Expect(stats.Msg).To(Equal("stats: 12 xacts/s, 30 queries/s, in 2048 B/s, out 8192 B/s, " +
+			"xact 1200 us, query 450 us, wait 12 us\n" +
+			"database app: 12 xacts/s, 30 queries/s\n" +
+			"database postgres: 0 xacts/s, 0 queries/s"))
+	})
+
+	It("writes the pending record when closed", func() {
+		spy := logtest.NewSpy()
+		writer := pgBouncerLogWriter{
+			Logger: spy,
+		}
+
+		_, err := writer.Write([]byte("2023-06-12 10:15:02.113 UTC [1] FATAL cannot load config file"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spy.Records).To(BeEmpty())
+
+		Expect(writer.Close()).To(Succeed())
+		Expect(spy.Records).To(HaveLen(1))
+		record := spy.Records[0].Attributes["record"].(pgBouncerLogRecord)
+		Expect(record.Level).To(Equal("FATAL"))
+		Expect(record.Msg).To(Equal("cannot load config file"))
+
+		// The continuation timer must not write the record again
+		Consistently(func() int {
+			writer.mu.Lock()
+			defer writer.mu.Unlock()
+			return len(spy.Records)
+		}).WithTimeout(continuationTimeout * 3).Should(Equal(1))
+	})
+
+	It("detects the event and the client connection", func() {
+		record := newPgBouncerLogRecord("2023-06-12 10:15:02.113 UTC", "1", "LOG",
+			"C-0x55d0a2c3e1f0: app/app@10.244.0.21:50542 closing because: client close request (age=0s)")
+		Expect(record.Event).To(Equal("closing"))
+		Expect(record.Connection).To(Equal(&pgBouncerLogConnection{
+			Kind:     "client",
+			ID:       "0x55d0a2c3e1f0",
+			Database: "app",
+			User:     "app",
+			Address:  "10.244.0.21:50542",
+		}))
+	})
+
+	It("removes the placeholders of unknown databases and users", func() {
+		record := newPgBouncerLogRecord("2023-06-12 10:15:02.114 UTC", "1", "WARNING",
+			"C-0x55d0a2c3e3a0: (nodb)/(nouser)@10.244.0.22:50550 pooler error: no such database: missing")
+		Expect(record.Level).To(Equal("WARNING"))
+		Expect(record.Event).To(Equal("pooler_error"))
+		Expect(record.Connection.Database).To(BeEmpty())
+		Expect(record.Connection.User).To(BeEmpty())
+	})
+
+	It("detects the server connections", func() {
+		record := newPgBouncerLogRecord("2023-06-12 10:16:05.420 UTC", "1", "LOG",
+			"S-0x55d0a2c4b980: app/cnpg_pooler_pgbouncer@10.96.16.93:5432 new connection to server "+
+				"(from 10.244.0.12:38130)")
+		Expect(record.Event).To(Equal("server_connection"))
+		Expect(record.Connection.Kind).To(Equal("server"))
+		Expect(record.Connection.User).To(Equal("cnpg_pooler_pgbouncer"))
+		Expect(record.Connection.Address).To(Equal("10.96.16.93:5432"))
+	})
+
+	It("leaves the event empty for unknown messages", func() {
+		record := newPgBouncerLogRecord("2023-06-12 10:16:05.420 UTC", "1", "LOG", "something new happened")
+		Expect(record.Event).To(BeEmpty())
+		Expect(record.Connection).To(BeNil())
+	})
 })
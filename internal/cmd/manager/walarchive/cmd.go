@@ -40,7 +40,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/volumebackup"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -48,6 +50,11 @@ const (
 	// SpoolDirectory is the directory where we spool the WAL files that
 	// were pre-archived in parallel
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-archive-spool"
+
+	// PendingDirectory is the directory where we queue the WAL files that
+	// failed on some object store, while reaching the archive quorum, to
+	// archive them again later
+	PendingDirectory = specs.PgWalArchivePendingPath
 )
 
 // errSwitchoverInProgress is raised when there is a switchover in progress
@@ -138,7 +145,8 @@ func run(
 	contextLog := log.FromContext(ctx)
 	walName := args[0]
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	if cluster.Spec.Backup == nil ||
+		(cluster.Spec.Backup.BarmanObjectStore == nil && !cluster.ShouldCreateBackupVolume()) {
 		// Backup not configured, skipping WAL
 		contextLog.Info("Backup not configured, skip WAL archiving",
 			"walName", walName,
@@ -169,6 +177,10 @@ func run(
 		return errSwitchoverInProgress
 	}
 
+	if cluster.ShouldCreateBackupVolume() {
+		return archiveToVolume(ctx, pgData, walName)
+	}
+
 	maxParallel := 1
 	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
 		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
@@ -182,7 +194,7 @@ func run(
 
 	// Create the archiver
 	var walArchiver *archiver.WALArchiver
-	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, pgData); err != nil {
+	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, PendingDirectory, pgData); err != nil {
 		return fmt.Errorf("while creating the archiver: %w", err)
 	}
 
@@ -210,14 +222,19 @@ func run(
 		}
 	}
 
-	options, err := barmanCloudWalArchiveOptions(cluster, cluster.Name)
+	destinations, err := getDestinations(ctx, cluster, env)
 	if err != nil {
 		return err
 	}
 
 	// Step 5: archive the WAL files in parallel
 	uploadStartTime := time.Now()
-	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
+	walStatus := walArchiver.ArchiveList(ctx, walFilesList, destinations, cluster.Spec.Backup.GetWalArchiveQuorum())
+	if len(destinations) > 1 {
+		// Step 6: archive again the WAL files queued for the object
+		// stores which failed to archive them
+		walArchiver.ArchivePending(ctx, destinations, maxParallel)
+	}
 	if len(walStatus) > 1 {
 		contextLog.Info("Completed archive command (parallel)",
 			"walsCount", len(walStatus),
@@ -234,6 +251,28 @@ func run(
 	return walStatus[0].Err
 }
 
+// archiveToVolume archives a WAL file in the volume dedicated to the backups
+func archiveToVolume(ctx context.Context, pgData, walName string) error {
+	walPath := walName
+	if !filepath.IsAbs(walPath) {
+		walPath = filepath.Join(pgData, walName)
+	}
+
+	if err := volumebackup.CheckVolumeMounted(postgres.BackupVolumeDirectory); err != nil {
+		return fmt.Errorf("while archiving %s in the backup volume: %w", walName, err)
+	}
+
+	startTime := time.Now()
+	if err := volumebackup.ArchiveWAL(postgres.BackupVolumeDirectory, walPath); err != nil {
+		return fmt.Errorf("while archiving %s in the backup volume: %w", walName, err)
+	}
+
+	log.FromContext(ctx).Info("Archived WAL file in the backup volume",
+		"walName", walName,
+		"totalTime", time.Since(startTime))
+	return nil
+}
+
 // gatherWALFilesToArchive reads from the archived status the list of WAL files
 // that can be archived in parallel way.
 // `requestedWALFile` is the name of the file whose archiving was requested by
@@ -307,15 +346,49 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 	return walList
 }
 
-func barmanCloudWalArchiveOptions(
+// getDestinations returns the object stores where the WAL files are
+// archived, starting from the `barmanObjectStore` one, whose environment
+// is passed
+func getDestinations(
+	ctx context.Context,
 	cluster *apiv1.Cluster,
+	env []string,
+) ([]archiver.Destination, error) {
+	objectStores := cluster.Spec.Backup.GetWalObjectStores()
+	destinations := make([]archiver.Destination, 0, len(objectStores))
+	for idx, configuration := range objectStores {
+		destinationEnv := env
+		if idx > 0 {
+			var err error
+			if destinationEnv, err = cacheClient.GetEnv(cache.AdditionalWALArchiveKey(idx - 1)); err != nil {
+				return nil, fmt.Errorf("failed to get envs of the additional WAL object store %d: %w", idx-1, err)
+			}
+		}
+
+		options, err := barmanCloudWalArchiveOptions(ctx, configuration, cluster.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		destinations = append(destinations, archiver.Destination{
+			Name:    archiver.DestinationName(configuration, cluster.Name),
+			Env:     destinationEnv,
+			Options: options,
+		})
+	}
+
+	return destinations, nil
+}
+
+func barmanCloudWalArchiveOptions(
+	ctx context.Context,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
 	capabilities, err := barmanCapabilities.CurrentCapabilities()
 	if err != nil {
 		return nil, err
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
 
 	var options []string
 	if configuration.Wal != nil {
@@ -327,12 +400,15 @@ func barmanCloudWalArchiveOptions(
 				options,
 				fmt.Sprintf("--%v", configuration.Wal.Compression))
 		}
-		if len(configuration.Wal.Encryption) != 0 {
-			options = append(
-				options,
-				"-e",
-				string(configuration.Wal.Encryption))
-		}
+	}
+
+	var encryption apiv1.EncryptionType
+	if configuration.Wal != nil {
+		encryption = configuration.Wal.Encryption
+	}
+	options, err = barman.AppendEncryptionOptions(ctx, options, configuration, encryption)
+	if err != nil {
+		return nil, err
 	}
 	if len(configuration.EndpointURL) > 0 {
 		options = append(
@@ -357,19 +433,20 @@ func barmanCloudWalArchiveOptions(
 		options = append(options, historyTags...)
 	}
 
-	options, err = barman.AppendCloudProviderOptionsFromConfiguration(options, configuration)
+	options, err = barman.AppendMaxBandwidthOptions(options, configuration)
 	if err != nil {
 		return nil, err
 	}
 
-	serverName := clusterName
-	if len(configuration.ServerName) != 0 {
-		serverName = configuration.ServerName
+	options, err = barman.AppendCloudProviderOptionsFromConfiguration(options, configuration)
+	if err != nil {
+		return nil, err
 	}
+
 	options = append(
 		options,
 		configuration.DestinationPath,
-		serverName)
+		configuration.GetServerName(clusterName))
 	return options, nil
 }
 
@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman-cloud-wal-archive options", func() {
+	configuration := func(serverName string) *apiv1.BarmanObjectStoreConfiguration {
+		return &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://bucket/path",
+			ServerName:      serverName,
+			BarmanCredentials: apiv1.BarmanCredentials{
+				AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+			},
+		}
+	}
+
+	It("archives in the folder of the cluster by default", func(ctx context.Context) {
+		options, err := barmanCloudWalArchiveOptions(ctx, configuration(""), "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options[len(options)-2:]).To(Equal([]string{"s3://bucket/path", "cluster-example"}))
+	})
+
+	It("archives in the folder of the configured server name", func(ctx context.Context) {
+		options, err := barmanCloudWalArchiveOptions(ctx, configuration("cluster-example-v2"), "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options[len(options)-2:]).To(Equal([]string{"s3://bucket/path", "cluster-example-v2"}))
+	})
+})
@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// maxQueryLength is the maximum length of the query text printed in
+// the table, the full text is available in the JSON and YAML output
+const maxQueryLength = 60
+
+// redactedQuery is how we print a query whose text has been redacted
+const redactedQuery = "<redacted>"
+
+// Activity prints the active backends of an instance of the cluster
+func Activity(
+	ctx context.Context,
+	clusterName, instanceName string,
+	includeQueries bool,
+	format plugin.OutputFormat,
+) error {
+	if instanceName == "" {
+		var cluster apiv1.Cluster
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+			&cluster,
+		); err != nil {
+			return err
+		}
+
+		if cluster.Status.CurrentPrimary == "" {
+			return fmt.Errorf("the cluster has no current primary")
+		}
+		instanceName = cluster.Status.CurrentPrimary
+	}
+
+	report, err := getActivityReport(ctx, instanceName, includeQueries)
+	if err != nil {
+		return fmt.Errorf("while getting the activity of instance %s: %w", instanceName, err)
+	}
+
+	if format != plugin.OutputFormatText {
+		return plugin.Print(report, format, os.Stdout)
+	}
+
+	printActivityReport(os.Stdout, report)
+	return nil
+}
+
+// getActivityReport gets the active backends from the instance manager,
+// proxying the request through the Kubernetes API server
+func getActivityReport(
+	ctx context.Context,
+	instanceName string,
+	includeQueries bool,
+) (*postgres.ActivityReport, error) {
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	data, err := clientInterface.CoreV1().Pods(plugin.Namespace).ProxyGet(
+		"http",
+		instanceName,
+		strconv.Itoa(url.StatusPort),
+		url.PathPgActivity,
+		map[string]string{"includeQueries": strconv.FormatBool(includeQueries)},
+	).DoRaw(timeoutCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseActivityReport(data)
+}
+
+// parseActivityReport parses the response of the instance manager
+func parseActivityReport(data []byte) (*postgres.ActivityReport, error) {
+	var report postgres.ActivityReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("while parsing the activity: %w", err)
+	}
+
+	return &report, nil
+}
+
+// printActivityReport prints the active backends as a table
+func printActivityReport(writer io.Writer, report *postgres.ActivityReport) {
+	if len(report.Backends) == 0 {
+		_, _ = fmt.Fprintln(writer, "No active backends")
+		return
+	}
+
+	table := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 4, ' ', 0))
+	table.AddHeader("PID", "User", "Database", "State", "Wait event", "Duration", "Query")
+	for _, backend := range report.Backends {
+		table.AddLine(
+			backend.PID,
+			backend.User,
+			backend.Database,
+			backend.State,
+			formatWaitEvent(backend),
+			formatDuration(backend.DurationSeconds),
+			formatQuery(backend),
+		)
+	}
+	table.Print()
+
+	if report.Truncated {
+		_, _ = fmt.Fprintf(writer, "\nOnly the %d longest running backends are shown\n", len(report.Backends))
+	}
+}
+
+// formatWaitEvent prints the wait event together with its type
+func formatWaitEvent(backend postgres.BackendActivity) string {
+	if backend.WaitEvent == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", backend.WaitEventType, backend.WaitEvent)
+}
+
+// formatDuration prints the duration of the query, rounded to the millisecond
+func formatDuration(seconds float64) string {
+	return (time.Duration(seconds * float64(time.Second))).Round(time.Millisecond).String()
+}
+
+// formatQuery prints the query text on a single line, truncating it
+func formatQuery(backend postgres.BackendActivity) string {
+	if backend.QueryRedacted {
+		return redactedQuery
+	}
+
+	query := []rune(strings.Join(strings.Fields(backend.Query), " "))
+	if len(query) > maxQueryLength {
+		return string(query[:maxQueryLength-3]) + "..."
+	}
+	return string(query)
+}
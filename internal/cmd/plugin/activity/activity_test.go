@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"bytes"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("activity report", func() {
+	It("parses the response of the instance manager", func() {
+		report, err := parseActivityReport([]byte(`{
+			"backends": [
+				{"pid": 42, "user": "app", "database": "app", "state": "active",
+				 "waitEventType": "Lock", "waitEvent": "relation",
+				 "query": "SELECT 1", "durationSeconds": 1.5},
+				{"pid": 43, "user": "app", "database": "app", "state": "idle in transaction",
+				 "queryRedacted": true, "durationSeconds": 0.25}
+			],
+			"truncated": true
+		}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Truncated).To(BeTrue())
+		Expect(report.Backends).To(HaveLen(2))
+		Expect(report.Backends[0].PID).To(Equal(42))
+		Expect(report.Backends[1].QueryRedacted).To(BeTrue())
+	})
+
+	It("fails on invalid responses", func() {
+		_, err := parseActivityReport([]byte("not found"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("prints the redacted queries and the truncation notice", func() {
+		var buffer bytes.Buffer
+		printActivityReport(&buffer, &postgres.ActivityReport{
+			Backends: []postgres.BackendActivity{
+				{
+					PID:             42,
+					User:            "app",
+					Database:        "app",
+					State:           "active",
+					WaitEventType:   "Lock",
+					WaitEvent:       "relation",
+					QueryRedacted:   true,
+					DurationSeconds: 1.5,
+				},
+			},
+			Truncated: true,
+		})
+		Expect(buffer.String()).To(ContainSubstring("<redacted>"))
+		Expect(buffer.String()).To(ContainSubstring("Lock:relation"))
+		Expect(buffer.String()).To(ContainSubstring("1.5s"))
+		Expect(buffer.String()).To(ContainSubstring("Only the 1 longest running backends are shown"))
+	})
+
+	It("prints a message when no backend is active", func() {
+		var buffer bytes.Buffer
+		printActivityReport(&buffer, &postgres.ActivityReport{})
+		Expect(buffer.String()).To(Equal("No active backends\n"))
+	})
+
+	It("prints long queries on a single line", func() {
+		query := formatQuery(postgres.BackendActivity{
+			Query: "SELECT *\n  FROM accounts\n  WHERE id IN (1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17)",
+		})
+		Expect(query).To(HaveLen(maxQueryLength))
+		Expect(query).To(HavePrefix("SELECT * FROM accounts WHERE id IN"))
+		Expect(query).To(HaveSuffix("..."))
+	})
+})
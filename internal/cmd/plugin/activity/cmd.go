@@ -0,0 +1,66 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "activity" command
+func NewCmd() *cobra.Command {
+	var (
+		includeQueries bool
+		output         string
+	)
+
+	activityCmd := &cobra.Command{
+		Use:   "activity [cluster] [instance]",
+		Short: "Show the active backends of a PostgreSQL instance",
+		Long: "Show the backends of a PostgreSQL instance that are not idle, as reported by pg_stat_activity. " +
+			"The current primary is used when the instance is not specified.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			var instanceName string
+			if len(args) > 1 {
+				instanceName = args[1]
+			}
+			return Activity(ctx, args[0], instanceName, includeQueries, plugin.OutputFormat(output))
+		},
+	}
+
+	activityCmd.Flags().BoolVar(
+		&includeQueries,
+		"include-queries",
+		false,
+		"Include the text of the queries, which may contain sensitive data, "+
+			"if exposed by the cluster",
+	)
+	activityCmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		"text",
+		"Output format. One of text|json|yaml",
+	)
+
+	return activityCmd
+}
@@ -90,6 +90,7 @@ func NewCmd() *cobra.Command {
 				"",
 				string(apiv1.BackupMethodBarmanObjectStore),
 				string(apiv1.BackupMethodVolumeSnapshot),
+				string(apiv1.BackupMethodVolumeBackup),
 			}
 			if !slices.Contains(allowedBackupMethods, backupMethod) {
 				return fmt.Errorf("backup-method: %s is not supported by the backup command", backupMethod)
@@ -157,7 +158,7 @@ func NewCmd() *cobra.Command {
 		"m",
 		"",
 		"If present, will override the backup method defined in backup resource, "+
-			"valid values are volumeSnapshot and barmanObjectStore.",
+			"valid values are volumeSnapshot, barmanObjectStore and volumeBackup.",
 	)
 
 	const optionalAcceptedValues = "Optional. Accepted values: true|false|\"\"."
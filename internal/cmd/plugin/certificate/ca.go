@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+)
+
+// GetCA writes the PEM encoded certificate of the CA used to sign
+// the PostgreSQL server certificate of a cluster, to be used by
+// external clients as `sslrootcert`
+func GetCA(ctx context.Context, clusterName string, w io.Writer) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster)
+	if err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", clusterName, err)
+	}
+
+	var secret corev1.Secret
+	err = plugin.Client.Get(ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.GetServerCASecretName()},
+		&secret)
+	if err != nil {
+		return fmt.Errorf("while getting the server CA secret %s: %w", cluster.GetServerCASecretName(), err)
+	}
+
+	caCertificate, err := certs.GetCACertificate(&secret)
+	if err != nil {
+		return fmt.Errorf("while reading the server CA secret %s: %w", secret.Name, err)
+	}
+
+	_, err = w.Write(caCertificate)
+	return err
+}
@@ -0,0 +1,136 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("get-ca", func() {
+	const namespace = "default"
+
+	var (
+		cluster  *apiv1.Cluster
+		caPair   *certs.KeyPair
+		caSecret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		var err error
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace},
+		}
+		caPair, err = certs.CreateRootCA(cluster.Name, namespace)
+		Expect(err).ToNot(HaveOccurred())
+		caSecret = caPair.GenerateCASecret(namespace, cluster.GetServerCASecretName())
+
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, caSecret).
+			Build()
+	})
+
+	It("prints the CA certificate of the cluster", func(ctx SpecContext) {
+		var output bytes.Buffer
+		Expect(GetCA(ctx, cluster.Name, &output)).To(Succeed())
+		Expect(output.Bytes()).To(Equal(caPair.Certificate))
+		Expect(certs.ValidateCABundle(output.Bytes())).To(Succeed())
+	})
+
+	It("prints the renewed CA certificate after a rotation", func(ctx SpecContext) {
+		privateKey, err := caPair.ParseECPrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(caPair.RenewCertificate(privateKey, nil)).To(Succeed())
+		caSecret.Data[certs.CACertKey] = caPair.Certificate
+		Expect(plugin.Client.Update(ctx, caSecret)).To(Succeed())
+
+		var output bytes.Buffer
+		Expect(GetCA(ctx, cluster.Name, &output)).To(Succeed())
+		Expect(output.Bytes()).To(Equal(caPair.Certificate))
+	})
+
+	It("uses the CA secret specified in the cluster", func(ctx SpecContext) {
+		customCA, err := certs.CreateRootCA("custom", namespace)
+		Expect(err).ToNot(HaveOccurred())
+		customSecret := customCA.GenerateCASecret(namespace, "custom-ca")
+		delete(customSecret.Data, certs.CAPrivateKeyKey)
+		Expect(plugin.Client.Create(ctx, customSecret)).To(Succeed())
+
+		cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{ServerCASecret: customSecret.Name}
+		Expect(plugin.Client.Update(ctx, cluster)).To(Succeed())
+
+		var output bytes.Buffer
+		Expect(GetCA(ctx, cluster.Name, &output)).To(Succeed())
+		Expect(output.Bytes()).To(Equal(customCA.Certificate))
+	})
+
+	It("fails when the cluster doesn't exist", func(ctx SpecContext) {
+		var output bytes.Buffer
+		Expect(GetCA(ctx, "missing", &output)).ToNot(Succeed())
+		Expect(output.Len()).To(BeZero())
+	})
+
+	It("fails when the CA secret doesn't exist", func(ctx SpecContext) {
+		Expect(plugin.Client.Delete(ctx, caSecret)).To(Succeed())
+
+		var output bytes.Buffer
+		err := GetCA(ctx, cluster.Name, &output)
+		Expect(err).To(MatchError(ContainSubstring(cluster.GetServerCASecretName())))
+	})
+
+	It("is available as a subcommand of certificate", func(ctx SpecContext) {
+		var output bytes.Buffer
+		cmd := NewCmd()
+		cmd.SetArgs([]string{"get-ca", cluster.Name})
+		cmd.SetOut(&output)
+		Expect(cmd.ExecuteContext(ctx)).To(Succeed())
+		Expect(output.Bytes()).To(Equal(caPair.Certificate))
+		Expect(cmd.Aliases).To(ContainElement("cert"))
+	})
+})
+
+var _ = Describe("get-ca with a missing certificate", func() {
+	It("rejects a secret without the CA certificate", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: cluster.GetServerCASecretName(), Namespace: "default"},
+		}
+		plugin.Namespace = "default"
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, secret).
+			Build()
+
+		var output bytes.Buffer
+		Expect(GetCA(ctx, cluster.Name, &output)).To(MatchError(ContainSubstring(certs.CACertKey)))
+	})
+})
@@ -27,8 +27,9 @@ import (
 // NewCmd creates the new "certificate" subcommand
 func NewCmd() *cobra.Command {
 	certificateCmd := &cobra.Command{
-		Use:   "certificate [secretName]",
-		Short: `Create a client certificate to connect to PostgreSQL using TLS and Certificate authentication`,
+		Use:     "certificate [secretName]",
+		Aliases: []string{"cert"},
+		Short:   `Create a client certificate to connect to PostgreSQL using TLS and Certificate authentication`,
 		Long: `This command creates a new Kubernetes secret containing the crypto-material.
 This is needed to configure TLS with Certificate authentication access for an application to
 connect to the PostgreSQL cluster.`,
@@ -64,5 +65,22 @@ connect to the PostgreSQL cluster.`,
 	certificateCmd.Flags().Bool(
 		"dry-run", false, "If specified, the secret is not created")
 
+	certificateCmd.AddCommand(newGetCACmd())
+
 	return certificateCmd
 }
+
+// newGetCACmd creates the "certificate get-ca" subcommand
+func newGetCACmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-ca [cluster]",
+		Short: "Print the CA certificate used to sign the PostgreSQL server certificate",
+		Long: `This command prints the PEM encoded certificate of the CA of the cluster.
+Clients connecting from outside Kubernetes can use it as "sslrootcert" to verify
+the identity of the PostgreSQL server.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetCA(cmd.Context(), args[0], cmd.OutOrStdout())
+		},
+	}
+}
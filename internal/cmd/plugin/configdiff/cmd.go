@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configdiff
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "config-diff" command
+func NewCmd() *cobra.Command {
+	var dbname string
+
+	configDiffCmd := &cobra.Command{
+		Use:   "config-diff [clusterA] [clusterB]",
+		Short: "Compare the configuration of two clusters",
+		Long: "Compare the effective PostgreSQL settings, the extensions and the roles of the " +
+			"primary instances of two clusters, printing only the meaningful differences.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			return ConfigDiff(ctx, args[0], args[1], dbname)
+		},
+	}
+
+	configDiffCmd.Flags().StringVarP(
+		&dbname,
+		"dbname",
+		"d",
+		"",
+		"The database where the extensions are compared. Defaults to the application database of each cluster",
+	)
+
+	return configDiffCmd
+}
@@ -0,0 +1,240 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configdiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/logrusorgru/aurora/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// snapshotQuery extracts, in JSON format, the effective settings of the
+// instance, the extensions installed in the current database and the roles
+const snapshotQuery = `SELECT pg_catalog.json_build_object(
+  'settings', (SELECT coalesce(pg_catalog.json_object_agg(name, setting), '{}'::json)
+    FROM pg_catalog.pg_settings),
+  'extensions', (SELECT coalesce(pg_catalog.json_object_agg(extname, extversion), '{}'::json)
+    FROM pg_catalog.pg_extension),
+  'roles', (SELECT coalesce(pg_catalog.json_object_agg(rolname, concat_ws(' ',
+      CASE WHEN rolsuper THEN 'SUPERUSER' END,
+      CASE WHEN rolinherit THEN 'INHERIT' END,
+      CASE WHEN rolcreaterole THEN 'CREATEROLE' END,
+      CASE WHEN rolcreatedb THEN 'CREATEDB' END,
+      CASE WHEN rolcanlogin THEN 'LOGIN' END,
+      CASE WHEN rolreplication THEN 'REPLICATION' END,
+      CASE WHEN rolbypassrls THEN 'BYPASSRLS' END)), '{}'::json)
+    FROM pg_catalog.pg_roles WHERE rolname NOT LIKE 'pg\_%'))`
+
+// absentValue is how we print a value that is missing in one of the clusters
+const absentValue = "<absent>"
+
+// ignoredSettings are the PostgreSQL settings whose value depends on
+// the cluster or on the instance, and would always be reported as different
+var ignoredSettings = map[string]bool{
+	"application_name":          true,
+	"cluster_name":              true,
+	"cnpg.config_sha256":        true,
+	"primary_conninfo":          true,
+	"primary_slot_name":         true,
+	"restore_command":           true,
+	"synchronous_standby_names": true,
+}
+
+// configSnapshot is the configuration of a PostgreSQL instance
+type configSnapshot struct {
+	// Settings maps the name of each setting to its value
+	Settings map[string]string `json:"settings"`
+
+	// Extensions maps the name of each extension to its version
+	Extensions map[string]string `json:"extensions"`
+
+	// Roles maps the name of each role to its attributes
+	Roles map[string]string `json:"roles"`
+}
+
+// difference is an element whose value is not the same in the two clusters
+type difference struct {
+	category string
+	name     string
+	left     string
+	right    string
+}
+
+// ConfigDiff prints the differences between the configuration of two clusters
+func ConfigDiff(ctx context.Context, clusterA, clusterB, dbname string) error {
+	left, err := getConfigSnapshot(ctx, clusterA, dbname)
+	if err != nil {
+		return fmt.Errorf("while getting the configuration of cluster %s: %w", clusterA, err)
+	}
+
+	right, err := getConfigSnapshot(ctx, clusterB, dbname)
+	if err != nil {
+		return fmt.Errorf("while getting the configuration of cluster %s: %w", clusterB, err)
+	}
+
+	differences := computeDiff(left, right)
+	if len(differences) == 0 {
+		fmt.Println(aurora.Green("No differences found"))
+		return nil
+	}
+
+	table := tabby.New()
+	table.AddHeader("Category", "Name", clusterA, clusterB)
+	for _, d := range differences {
+		table.AddLine(d.category, d.name, d.left, d.right)
+	}
+	table.Print()
+
+	return nil
+}
+
+// getConfigSnapshot gets the configuration of the current primary of a cluster
+func getConfigSnapshot(ctx context.Context, clusterName, dbname string) (*configSnapshot, error) {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return nil, err
+	}
+
+	if cluster.Status.CurrentPrimary == "" {
+		return nil, fmt.Errorf("the cluster has no current primary")
+	}
+
+	var pod corev1.Pod
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&pod,
+	); err != nil {
+		return nil, err
+	}
+
+	if dbname == "" {
+		dbname = cluster.GetApplicationDatabaseName()
+	}
+
+	timeout := time.Second * 10
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		"psql", "-AtX", "-d", dbname, "-c", snapshotQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseConfigSnapshot(stdout)
+}
+
+// parseConfigSnapshot parses the output of the snapshot query
+func parseConfigSnapshot(data string) (*configSnapshot, error) {
+	var snapshot configSnapshot
+	if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &snapshot); err != nil {
+		return nil, fmt.Errorf("while parsing the configuration: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// computeDiff gets the meaningful differences between two configurations,
+// sorted by category and name
+func computeDiff(left, right *configSnapshot) []difference {
+	var result []difference
+
+	settingsLeft := withoutIgnoredSettings(left.Settings)
+	settingsRight := withoutIgnoredSettings(right.Settings)
+	result = append(result, diffMaps("setting", settingsLeft, settingsRight)...)
+	result = append(result, diffMaps("extension", left.Extensions, right.Extensions)...)
+	result = append(result, diffMaps("role", left.Roles, right.Roles)...)
+
+	return result
+}
+
+// withoutIgnoredSettings removes the instance specific settings
+func withoutIgnoredSettings(settings map[string]string) map[string]string {
+	result := make(map[string]string, len(settings))
+	for name, value := range settings {
+		if ignoredSettings[name] {
+			continue
+		}
+		result[name] = value
+	}
+
+	return result
+}
+
+// diffMaps compares two maps, returning the keys having different values
+func diffMaps(category string, left, right map[string]string) []difference {
+	names := make(map[string]bool, len(left)+len(right))
+	for name := range left {
+		names[name] = true
+	}
+	for name := range right {
+		names[name] = true
+	}
+
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	var result []difference
+	for _, name := range sortedNames {
+		leftValue, leftFound := left[name]
+		rightValue, rightFound := right[name]
+		if leftFound && rightFound && leftValue == rightValue {
+			continue
+		}
+
+		if !leftFound {
+			leftValue = absentValue
+		}
+		if !rightFound {
+			rightValue = absentValue
+		}
+		result = append(result, difference{
+			category: category,
+			name:     name,
+			left:     leftValue,
+			right:    rightValue,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configdiff
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("configuration diff", func() {
+	It("finds no differences between identical configurations", func() {
+		snapshot := &configSnapshot{
+			Settings:   map[string]string{"max_connections": "100"},
+			Extensions: map[string]string{"plpgsql": "1.0"},
+			Roles:      map[string]string{"app": "INHERIT LOGIN"},
+		}
+		Expect(computeDiff(snapshot, snapshot)).To(BeEmpty())
+	})
+
+	It("ignores the cluster and instance specific settings", func() {
+		left := &configSnapshot{
+			Settings: map[string]string{
+				"cluster_name":              "cluster-a",
+				"primary_conninfo":          "host=cluster-a-rw",
+				"synchronous_standby_names": "ANY 1 (\"cluster-a-2\")",
+				"cnpg.config_sha256":        "abc",
+			},
+		}
+		right := &configSnapshot{
+			Settings: map[string]string{
+				"cluster_name":              "cluster-b",
+				"primary_conninfo":          "host=cluster-b-rw",
+				"synchronous_standby_names": "ANY 1 (\"cluster-b-2\")",
+				"cnpg.config_sha256":        "def",
+			},
+		}
+		Expect(computeDiff(left, right)).To(BeEmpty())
+	})
+
+	It("reports the changed, missing and added elements sorted by category and name", func() {
+		left := &configSnapshot{
+			Settings: map[string]string{
+				"work_mem":        "4096",
+				"max_connections": "100",
+				"shared_buffers":  "16384",
+			},
+			Extensions: map[string]string{
+				"plpgsql":   "1.0",
+				"pgaudit":   "1.7",
+				"pg_repack": "1.4.8",
+			},
+			Roles: map[string]string{
+				"app": "INHERIT LOGIN",
+			},
+		}
+		right := &configSnapshot{
+			Settings: map[string]string{
+				"work_mem":        "8192",
+				"max_connections": "200",
+				"shared_buffers":  "16384",
+			},
+			Extensions: map[string]string{
+				"plpgsql": "1.0",
+				"pgaudit": "1.7",
+				"postgis": "3.4.0",
+			},
+			Roles: map[string]string{
+				"app":      "INHERIT LOGIN",
+				"reporter": "INHERIT LOGIN",
+			},
+		}
+
+		Expect(computeDiff(left, right)).To(Equal([]difference{
+			{category: "setting", name: "max_connections", left: "100", right: "200"},
+			{category: "setting", name: "work_mem", left: "4096", right: "8192"},
+			{category: "extension", name: "pg_repack", left: "1.4.8", right: absentValue},
+			{category: "extension", name: "postgis", left: absentValue, right: "3.4.0"},
+			{category: "role", name: "reporter", left: absentValue, right: "INHERIT LOGIN"},
+		}))
+	})
+
+	It("parses the output of the snapshot query", func() {
+		snapshot, err := parseConfigSnapshot(
+			`{"settings" : {"max_connections" : "100"}, "extensions" : {"plpgsql" : "1.0"}, ` +
+				`"roles" : {"app" : "INHERIT LOGIN"}}` + "\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot.Settings).To(HaveKeyWithValue("max_connections", "100"))
+		Expect(snapshot.Extensions).To(HaveKeyWithValue("plpgsql", "1.0"))
+		Expect(snapshot.Roles).To(HaveKeyWithValue("app", "INHERIT LOGIN"))
+	})
+
+	It("fails when the output of the snapshot query is not valid", func() {
+		_, err := parseConfigSnapshot("ERROR: permission denied")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -44,11 +44,20 @@ func getHibernatedPVCGroup(ctx context.Context, clusterName string) ([]corev1.Pe
 	); err != nil {
 		return nil, err
 	}
-	if len(pvcList.Items) == 0 {
+
+	// The backup volume is not part of the hibernated resources
+	pvcs := make([]corev1.PersistentVolumeClaim, 0, len(pvcList.Items))
+	for _, pvc := range pvcList.Items {
+		if utils.PVCRole(pvc.Labels[utils.PvcRoleLabelName]) == utils.PVCRolePgBackup {
+			continue
+		}
+		pvcs = append(pvcs, pvc)
+	}
+	if len(pvcs) == 0 {
 		return nil, errNoHibernatedPVCsFound
 	}
 
-	return pvcList.Items, nil
+	return pvcs, nil
 }
 
 // getClusterFromPVCAnnotation reads the original cluster resource from the chosen PVC
@@ -39,6 +39,13 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
 	}
 
+	// The designated primary of a replica cluster can't be changed
+	// while the promotion is blocked
+	if cluster.IsPromotionBlocked() {
+		return fmt.Errorf("the promotion of the replica cluster %s is blocked, "+
+			"remove spec.replica.promotionBlocked first", clusterName)
+	}
+
 	// If server name is equal to target primary, there is no need to promote
 	// that instance
 	if cluster.Status.TargetPrimary == serverName {
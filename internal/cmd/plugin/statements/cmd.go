@@ -0,0 +1,43 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statements
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "statements" command
+func NewCmd() *cobra.Command {
+	statementsCmd := &cobra.Command{
+		Use:   "statements [reset]",
+		Short: "Manage the statistics of the statements gathered by pg_stat_statements",
+	}
+
+	statementsCmd.AddCommand(&cobra.Command{
+		Use:   "reset [cluster]",
+		Short: "Reset the statistics of the statements",
+		Long: "Discard the statistics gathered by pg_stat_statements on the current primary, " +
+			"for example between the runs of a benchmark. The pg_stat_statements extension " +
+			"must be installed in the postgres database.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Reset(cmd.Context(), args[0])
+		},
+	})
+
+	return statementsCmd
+}
@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// Reset discards the statistics of the statements on the current
+// primary of the cluster
+func Reset(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("the cluster has no current primary")
+	}
+
+	if err := resetStatStatements(ctx, cluster.Status.CurrentPrimary); err != nil {
+		return fmt.Errorf("while resetting the statistics of the statements on instance %s: %w",
+			cluster.Status.CurrentPrimary, err)
+	}
+
+	fmt.Printf("Statistics of the statements reset on instance %s\n", cluster.Status.CurrentPrimary)
+	return nil
+}
+
+// resetStatStatements asks the instance manager to reset the statistics,
+// proxying the request through the Kubernetes API server
+func resetStatStatements(ctx context.Context, instanceName string) error {
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	data, err := clientInterface.CoreV1().RESTClient().
+		Post().
+		Namespace(plugin.Namespace).
+		Resource("pods").
+		SubResource("proxy").
+		Name(fmt.Sprintf("%s:%d", instanceName, url.StatusPort)).
+		Suffix(url.PathPgStatStatementsReset).
+		DoRaw(timeoutCtx)
+
+	return parseResetResponse(data, err)
+}
+
+// parseResetResponse extracts the error reported by the instance manager,
+// if any, to return it in place of the generic error of the proxy
+func parseResetResponse(data []byte, requestErr error) error {
+	var response webserver.Response[struct{}]
+	if err := json.Unmarshal(data, &response); err != nil {
+		return requestErr
+	}
+
+	if response.Error != nil {
+		return errors.New(response.Error.Message)
+	}
+
+	return requestErr
+}
@@ -0,0 +1,43 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statements
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("statements reset response", func() {
+	It("succeeds when the instance manager reset the statistics", func() {
+		Expect(parseResetResponse([]byte(`{"data":{}}`), nil)).To(Succeed())
+	})
+
+	It("reports the error of the instance manager", func() {
+		err := parseResetResponse(
+			[]byte(`{"error":{"code":"EXTENSION_NOT_INSTALLED",`+
+				`"message":"the pg_stat_statements extension is not installed in the postgres database"}}`),
+			errors.New("the server rejected our request for an unknown reason"))
+		Expect(err).To(MatchError("the pg_stat_statements extension is not installed in the postgres database"))
+	})
+
+	It("reports the error of the request when the response is not valid", func() {
+		requestErr := errors.New("the server could not find the requested resource")
+		Expect(parseResetResponse([]byte("404 page not found"), requestErr)).To(MatchError(requestErr))
+	})
+})
@@ -195,6 +195,9 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 	if cluster.IsReplica() {
 		summary.AddLine("Designated primary:", primaryInstance)
 		summary.AddLine("Source cluster: ", cluster.Spec.ReplicaCluster.Source)
+		if cluster.IsPromotionBlocked() {
+			summary.AddLine("Promotion:", aurora.Red("Blocked"))
+		}
 	} else {
 		summary.AddLine("Primary instance:", primaryInstance)
 	}
@@ -442,7 +445,8 @@ func (fullStatus *PostgresqlStatus) addReplicationSlotsColumns(
 		}
 		return "inactive"
 	}
-	slot := fullStatus.getPrintableReplicationSlotInfo(applicationName)
+	instanceName, _ := fullStatus.Cluster.GetInstanceNameFromApplicationName(applicationName)
+	slot := fullStatus.getPrintableReplicationSlotInfo(instanceName)
 	switch {
 	case slot != nil && verbose:
 		*columns = append(*columns,
@@ -695,9 +699,10 @@ func getReplicaRole(instance postgres.PostgresqlStatus, fullStatus *PostgresqlSt
 		return "Unknown"
 	}
 
+	applicationName := fullStatus.Cluster.GetReplicaApplicationName(instance.Pod.Name)
 	for _, state := range primaryInstanceStatus.ReplicationInfo {
 		// todo: handle others states other than 'streaming'
-		if !(state.ApplicationName == instance.Pod.Name && state.State == "streaming") {
+		if !(state.ApplicationName == applicationName && state.State == "streaming") {
 			continue
 		}
 		switch state.SyncState {
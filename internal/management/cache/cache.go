@@ -19,6 +19,9 @@ limitations under the License.
 package cache
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -35,6 +38,32 @@ const (
 
 var cache sync.Map
 
+// AdditionalWALArchiveKey is the key to be used to access the cached envs
+// for wal-archive, for the additional object store with the given index
+func AdditionalWALArchiveKey(index int) string {
+	return fmt.Sprintf("%s-%d", WALArchiveKey, index)
+}
+
+// IsEnvKey checks whether the key is used to access cached envs
+func IsEnvKey(c string) bool {
+	return c == WALRestoreKey || c == WALArchiveKey || strings.HasPrefix(c, WALArchiveKey+"-")
+}
+
+// PruneAdditionalWALArchiveEnvs deletes the cached envs for wal-archive of
+// the additional object stores whose index is not lower than the given count
+func PruneAdditionalWALArchiveEnvs(count int) {
+	cache.Range(func(key, _ any) bool {
+		c, ok := key.(string)
+		if !ok || !strings.HasPrefix(c, WALArchiveKey+"-") {
+			return true
+		}
+		if index, err := strconv.Atoi(strings.TrimPrefix(c, WALArchiveKey+"-")); err != nil || index >= count {
+			cache.Delete(c)
+		}
+		return true
+	})
+}
+
 // Store write an object into the local cache
 func Store(c string, v interface{}) {
 	cache.Store(c, v)
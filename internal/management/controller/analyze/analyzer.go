@@ -0,0 +1,228 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkInterval is how often the instance manager checks if the planner
+// statistics need to be regenerated
+const checkInterval = 30 * time.Second
+
+// A PostImportAnalyzer is a Kubernetes manager.Runnable that regenerates
+// the planner statistics in background, once the primary is up, after
+// the data of the cluster has been imported or recovered
+type PostImportAnalyzer struct {
+	instance   *postgres.Instance
+	client     client.Client
+	runCommand commandRunner
+
+	mu sync.Mutex
+	// cancel stops the running vacuumdb, nil when it is not running
+	cancel context.CancelFunc
+}
+
+// NewPostImportAnalyzer creates a new PostImportAnalyzer
+func NewPostImportAnalyzer(instance *postgres.Instance, client client.Client) *PostImportAnalyzer {
+	return &PostImportAnalyzer{
+		instance:   instance,
+		client:     client,
+		runCommand: runCommand,
+	}
+}
+
+// Start starts running the PostImportAnalyzer
+func (a *PostImportAnalyzer) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("post_import_analyzer")
+	ticker := time.NewTicker(checkInterval)
+	defer func() {
+		ticker.Stop()
+		a.stop()
+		contextLog.Info("Terminated post import analyzer loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := a.reconcile(ctx); err != nil {
+			contextLog.Warning("regenerating the planner statistics", "err", err)
+		}
+	}
+}
+
+func (a *PostImportAnalyzer) reconcile(ctx context.Context) error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	isPrimary, err := a.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if !isPrimary || a.instance.IsFenced() || !cluster.ShouldAnalyzeAfterImport() {
+		// the user may have disabled the feature while vacuumdb was running
+		a.stop()
+		return nil
+	}
+
+	if a.isRunning() || !needsAnalyze(cluster.Status.PostImportAnalyze) {
+		return nil
+	}
+
+	if err := a.instance.IsServerHealthy(); err != nil {
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	a.mu.Lock()
+	a.cancel = cancel
+	a.mu.Unlock()
+
+	go func() {
+		defer func() {
+			a.mu.Lock()
+			a.cancel = nil
+			a.mu.Unlock()
+			cancel()
+		}()
+		a.run(ctx, runCtx)
+	}()
+
+	return nil
+}
+
+// needsAnalyze tells if the statistics regeneration still need to be executed.
+// A regeneration that was running is restarted, as the instance manager
+// has been restarted in the meantime
+func needsAnalyze(status *apiv1.PostImportAnalyzeStatus) bool {
+	if status == nil {
+		return true
+	}
+
+	return status.Phase == apiv1.PostImportAnalyzePhaseRunning ||
+		status.Phase == apiv1.PostImportAnalyzePhaseCanceled
+}
+
+// isRunning tells if vacuumdb is running
+func (a *PostImportAnalyzer) isRunning() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cancel != nil
+}
+
+// stop cancels the running vacuumdb, if any
+func (a *PostImportAnalyzer) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// run executes vacuumdb until it ends or runCtx is canceled, reporting the
+// progress in the status of the cluster. The status is updated using ctx,
+// to be able to record the cancellation
+func (a *PostImportAnalyzer) run(ctx, runCtx context.Context) {
+	contextLog := log.FromContext(ctx).WithName("post_import_analyzer")
+	contextLog.Info("Regenerating the planner statistics")
+
+	startedAt := metav1.Now()
+	a.updateStatus(ctx, &apiv1.PostImportAnalyzeStatus{
+		Phase:     apiv1.PostImportAnalyzePhaseRunning,
+		StartedAt: &startedAt,
+	})
+
+	args := buildVacuumdbArgs(postgres.GetSocketDir(), postgres.GetServerPort())
+	err := a.runCommand(runCtx, vacuumdbCommand, args, func(line string) {
+		contextLog.Info(line, execlog.PipeKey, execlog.StdOut)
+		if current, ok := parseProgress(line); ok {
+			a.updateStatus(ctx, &apiv1.PostImportAnalyzeStatus{
+				Phase:     apiv1.PostImportAnalyzePhaseRunning,
+				Stage:     current.stage,
+				Database:  current.database,
+				StartedAt: &startedAt,
+			})
+		}
+	})
+
+	completedAt := metav1.Now()
+	status := &apiv1.PostImportAnalyzeStatus{
+		Phase:       apiv1.PostImportAnalyzePhaseCompleted,
+		StartedAt:   &startedAt,
+		CompletedAt: &completedAt,
+	}
+	switch {
+	case ctx.Err() != nil:
+		// the instance manager is shutting down, the regeneration
+		// will be restarted by the next one
+		contextLog.Info("Interrupted the regeneration of the planner statistics")
+		return
+	case runCtx.Err() != nil:
+		contextLog.Info("Canceled the regeneration of the planner statistics")
+		status.Phase = apiv1.PostImportAnalyzePhaseCanceled
+	case err != nil:
+		contextLog.Error(err, "while regenerating the planner statistics")
+		status.Phase = apiv1.PostImportAnalyzePhaseFailed
+		status.Message = err.Error()
+	default:
+		contextLog.Info("Regenerated the planner statistics")
+	}
+
+	a.updateStatus(ctx, status)
+}
+
+// updateStatus sets the progress of the statistics regeneration in the
+// status of the cluster
+func (a *PostImportAnalyzer) updateStatus(ctx context.Context, status *apiv1.PostImportAnalyzeStatus) {
+	var cluster apiv1.Cluster
+	if err := a.client.Get(ctx, types.NamespacedName{
+		Name:      a.instance.ClusterName,
+		Namespace: a.instance.Namespace,
+	}, &cluster); err != nil {
+		log.FromContext(ctx).Warning("while getting the cluster to report the analyze progress", "err", err)
+		return
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.PostImportAnalyze = status
+	if err := a.client.Status().Patch(ctx, updatedCluster, client.MergeFrom(&cluster)); err != nil {
+		log.FromContext(ctx).Warning("while reporting the analyze progress", "err", err)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"context"
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("post import analyzer", func() {
+	var (
+		cluster  *apiv1.Cluster
+		analyzer *PostImportAnalyzer
+		cl       client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+		cl = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+
+		instance := postgres.NewInstance()
+		instance.ClusterName = cluster.Name
+		instance.Namespace = cluster.Namespace
+		analyzer = NewPostImportAnalyzer(instance, cl)
+	})
+
+	getStatus := func(ctx context.Context) *apiv1.PostImportAnalyzeStatus {
+		var updated apiv1.Cluster
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		return updated.Status.PostImportAnalyze
+	}
+
+	It("reports the progress and the completion of vacuumdb", func(ctx SpecContext) {
+		var reported []int
+		analyzer.runCommand = func(_ context.Context, name string, args []string, onLine func(string)) error {
+			Expect(name).To(Equal("vacuumdb"))
+			Expect(args).To(ContainElement("--analyze-in-stages"))
+
+			Expect(getStatus(ctx).Phase).To(Equal(apiv1.PostImportAnalyzePhaseRunning))
+			for _, line := range []string{
+				`vacuumdb: processing database "app": Generating minimal optimizer statistics (1 target)`,
+				`vacuumdb: processing database "app": Generating medium optimizer statistics (10 targets)`,
+				`vacuumdb: processing database "app": Generating default (full) optimizer statistics`,
+			} {
+				onLine(line)
+				status := getStatus(ctx)
+				Expect(status.Database).To(Equal("app"))
+				reported = append(reported, status.Stage)
+			}
+			return nil
+		}
+
+		analyzer.run(ctx, ctx)
+		Expect(reported).To(Equal([]int{1, 2, 3}))
+
+		status := getStatus(ctx)
+		Expect(status.Phase).To(Equal(apiv1.PostImportAnalyzePhaseCompleted))
+		Expect(status.StartedAt).ToNot(BeNil())
+		Expect(status.CompletedAt).ToNot(BeNil())
+		Expect(status.Message).To(BeEmpty())
+	})
+
+	It("reports the failure of vacuumdb", func(ctx SpecContext) {
+		analyzer.runCommand = func(context.Context, string, []string, func(string)) error {
+			return errors.New("vacuumdb failed: exit status 1: connection refused")
+		}
+
+		analyzer.run(ctx, ctx)
+
+		status := getStatus(ctx)
+		Expect(status.Phase).To(Equal(apiv1.PostImportAnalyzePhaseFailed))
+		Expect(status.Message).To(ContainSubstring("connection refused"))
+		Expect(needsAnalyze(status)).To(BeFalse())
+	})
+
+	It("stops vacuumdb when canceled", func(ctx SpecContext) {
+		runCtx, cancel := context.WithCancel(ctx)
+		analyzer.runCommand = func(commandCtx context.Context, _ string, _ []string, _ func(string)) error {
+			cancel()
+			<-commandCtx.Done()
+			return commandCtx.Err()
+		}
+
+		analyzer.run(ctx, runCtx)
+
+		status := getStatus(ctx)
+		Expect(status.Phase).To(Equal(apiv1.PostImportAnalyzePhaseCanceled))
+		Expect(needsAnalyze(status)).To(BeTrue())
+	})
+
+	It("runs until the statistics have been regenerated", func() {
+		Expect(needsAnalyze(nil)).To(BeTrue())
+		Expect(needsAnalyze(&apiv1.PostImportAnalyzeStatus{Phase: apiv1.PostImportAnalyzePhaseRunning})).
+			To(BeTrue())
+		Expect(needsAnalyze(&apiv1.PostImportAnalyzeStatus{Phase: apiv1.PostImportAnalyzePhaseCompleted})).
+			To(BeFalse())
+	})
+})
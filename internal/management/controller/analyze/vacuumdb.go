@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// vacuumdbCommand is the name of the executable used to regenerate
+// the planner statistics
+const vacuumdbCommand = "vacuumdb"
+
+// stageRegex matches the lines printed by `vacuumdb --analyze-in-stages`
+// when a new stage begins on a database
+var stageRegex = regexp.MustCompile(
+	`processing database "(.*)": Generating (minimal|medium|default \(full\)) optimizer statistics`)
+
+// stages maps the description of every stage of `vacuumdb --analyze-in-stages`
+// to its number
+var stages = map[string]int{
+	"minimal":        1,
+	"medium":         2,
+	"default (full)": 3,
+}
+
+// progress is the position of vacuumdb in the statistics regeneration
+type progress struct {
+	stage    int
+	database string
+}
+
+// parseProgress extracts the progress from a line printed by vacuumdb,
+// returning false if the line isn't about the beginning of a stage
+func parseProgress(line string) (progress, bool) {
+	matches := stageRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return progress{}, false
+	}
+
+	return progress{
+		stage:    stages[matches[2]],
+		database: matches[1],
+	}, true
+}
+
+// buildVacuumdbArgs gets the arguments to regenerate the statistics of
+// every database of the instance listening on the passed socket
+func buildVacuumdbArgs(socketDir string, port int) []string {
+	return []string{
+		"--all",
+		"--analyze-in-stages",
+		"--host", socketDir,
+		"--port", strconv.Itoa(port),
+		"--username", "postgres",
+	}
+}
+
+// commandRunner executes a command, calling onLine for every line
+// written on its standard output, until it ends or the context is
+// canceled
+type commandRunner func(ctx context.Context, name string, args []string, onLine func(string)) error
+
+// runCommand is the commandRunner executing the commands for real
+func runCommand(ctx context.Context, name string, args []string, onLine func(string)) error {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		onLine(scanner.Text())
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("vacuumdb invocation", func() {
+	It("analyzes every database in stages through the local socket", func() {
+		Expect(buildVacuumdbArgs("/controller/run", 5432)).To(Equal([]string{
+			"--all",
+			"--analyze-in-stages",
+			"--host", "/controller/run",
+			"--port", "5432",
+			"--username", "postgres",
+		}))
+	})
+
+	DescribeTable("parsing the progress",
+		func(line string, expected progress, found bool) {
+			current, ok := parseProgress(line)
+			Expect(ok).To(Equal(found))
+			Expect(current).To(Equal(expected))
+		},
+		Entry("minimal statistics",
+			`vacuumdb: processing database "app": Generating minimal optimizer statistics (1 target)`,
+			progress{stage: 1, database: "app"}, true),
+		Entry("medium statistics",
+			`vacuumdb: processing database "postgres": Generating medium optimizer statistics (10 targets)`,
+			progress{stage: 2, database: "postgres"}, true),
+		Entry("full statistics",
+			`vacuumdb: processing database "my db": Generating default (full) optimizer statistics`,
+			progress{stage: 3, database: "my db"}, true),
+		Entry("other lines",
+			`vacuumdb: vacuuming database "app"`,
+			progress{}, false),
+	)
+
+	It("streams the standard output of the command", func(ctx SpecContext) {
+		var lines []string
+		err := runCommand(ctx, "sh", []string{"-c", "echo first; echo second"}, func(line string) {
+			lines = append(lines, line)
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lines).To(Equal([]string{"first", "second"}))
+	})
+
+	It("reports the standard error of a failed command", func(ctx SpecContext) {
+		err := runCommand(ctx, "sh", []string{"-c", "echo broken >&2; exit 1"}, func(string) {})
+		Expect(err).To(MatchError(ContainSubstring("broken")))
+	})
+
+	It("kills the command when the context is canceled", func(ctx SpecContext) {
+		commandCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := runCommand(commandCtx, "sleep", []string{"10"}, func(string) {})
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
@@ -83,6 +83,7 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 ) (shouldRetry bool) {
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 		cache.Delete(cache.WALArchiveKey)
+		cache.PruneAdditionalWALArchiveEnvs(0)
 		return false
 	}
 
@@ -104,5 +105,29 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	}
 
 	cache.Store(cache.WALArchiveKey, envArchive)
+
+	// Populate the cache with the additional object stores
+	// receiving the WAL files
+	cache.PruneAdditionalWALArchiveEnvs(len(cluster.Spec.Backup.AdditionalWalObjectStores))
+	for idx := range cluster.Spec.Backup.AdditionalWalObjectStores {
+		envArchive, err := barmanCredentials.EnvSetBackupCloudCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			&cluster.Spec.Backup.AdditionalWalObjectStores[idx],
+			os.Environ())
+		if apierrors.IsForbidden(err) {
+			log.Info("additional WAL object store credentials don't yet have access permissions. "+
+				"Will retry reconciliation loop", "index", idx)
+			return true
+		}
+		if err != nil {
+			log.Error(err, "while getting additional WAL object store credentials", "index", idx)
+			continue
+		}
+
+		cache.Store(cache.AdditionalWALArchiveKey(idx), envArchive)
+	}
+
 	return false
 }
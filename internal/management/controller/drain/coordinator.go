@@ -0,0 +1,225 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// pollInterval is how often the progress of the switchover is checked
+const pollInterval = 2 * time.Second
+
+// standbysQuery lists the standbys streaming from the primary, from the
+// most advanced one
+const standbysQuery = "SELECT application_name FROM pg_catalog.pg_stat_replication " +
+	"WHERE state = 'streaming' " +
+	"ORDER BY flush_lsn DESC NULLS LAST, replay_lsn DESC NULLS LAST"
+
+// ErrSwitchoverTimeout is returned when the switchover has not been
+// completed before the termination grace period of the Pod nears
+var ErrSwitchoverTimeout = errors.New("timed out waiting for the switchover to complete")
+
+// A Coordinator drives the shutdown of an instance whose Pod is being
+// disrupted, switching over to the most advanced replica when the instance
+// is the primary
+type Coordinator struct {
+	instance       *postgres.Instance
+	client         client.Client
+	getSuperUserDB func() (*sql.DB, error)
+	pollInterval   time.Duration
+}
+
+// NewCoordinator creates a new Coordinator
+func NewCoordinator(instance *postgres.Instance, client client.Client) *Coordinator {
+	return &Coordinator{
+		instance:       instance,
+		client:         client,
+		getSuperUserDB: instance.GetSuperUserDB,
+		pollInterval:   pollInterval,
+	}
+}
+
+// Run is invoked by the preStop hook of the Pod. When the Pod is being
+// disrupted while running the primary, Run requests a switchover to the
+// most advanced replica, and waits for it to complete, or for the
+// termination grace period to near. The operator pauses the poolers
+// fronting the primary while the switchover is in progress
+func (c *Coordinator) Run(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx).WithName("drain")
+
+	var pod corev1.Pod
+	if err := c.client.Get(ctx, types.NamespacedName{
+		Namespace: c.instance.Namespace,
+		Name:      c.instance.PodName,
+	}, &pod); err != nil {
+		return fmt.Errorf("while getting the instance pod: %w", err)
+	}
+
+	if !utils.IsPodDisrupted(&pod) {
+		contextLogger.Info("The pod is not being disrupted, no switchover is needed")
+		return nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := c.client.Get(ctx, types.NamespacedName{
+		Namespace: c.instance.Namespace,
+		Name:      c.instance.ClusterName,
+	}, &cluster); err != nil {
+		return fmt.Errorf("while getting the cluster: %w", err)
+	}
+
+	if !cluster.DeletionTimestamp.IsZero() ||
+		cluster.Status.CurrentPrimary != c.instance.PodName ||
+		cluster.IsPromotionBlocked() {
+		contextLogger.Info("The instance is not a primary that can be switched over, proceeding with the shutdown")
+		return nil
+	}
+
+	waitCtx, cancel := context.WithDeadline(ctx, getDeadline(&pod, &cluster))
+	defer cancel()
+
+	if cluster.Status.TargetPrimary == c.instance.PodName {
+		targetPrimary, err := c.getTargetPrimary(waitCtx, &cluster)
+		if err != nil {
+			return err
+		}
+		if targetPrimary == "" {
+			contextLogger.Info("No replica can be promoted, proceeding with the shutdown")
+			return nil
+		}
+
+		contextLogger.Info("Requesting a switchover before shutting down", "targetPrimary", targetPrimary)
+		if err := c.requestSwitchover(waitCtx, &cluster, targetPrimary); err != nil {
+			return err
+		}
+	}
+
+	return c.waitForSwitchover(waitCtx)
+}
+
+// getDeadline gets the time by which the switchover needs to be completed,
+// leaving to PostgreSQL the smart shutdown timeout to shut down, but never
+// more than half the termination grace period of the Pod
+func getDeadline(pod *corev1.Pod, cluster *apiv1.Cluster) time.Time {
+	gracePeriod := time.Duration(cluster.GetMaxStopDelay()) * time.Second
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = time.Duration(*pod.Spec.TerminationGracePeriodSeconds) * time.Second
+	}
+
+	terminationTime := time.Now().Add(gracePeriod)
+	if pod.DeletionTimestamp != nil {
+		terminationTime = pod.DeletionTimestamp.Time
+	}
+
+	reserved := min(time.Duration(cluster.GetSmartShutdownTimeout())*time.Second, gracePeriod/2)
+	return terminationTime.Add(-reserved)
+}
+
+// getTargetPrimary gets the most advanced replica that is streaming from
+// this instance and can be promoted, or an empty string if there is none
+func (c *Coordinator) getTargetPrimary(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
+	db, err := c.getSuperUserDB()
+	if err != nil {
+		return "", err
+	}
+
+	rows, err := db.QueryContext(ctx, standbysQuery)
+	if err != nil {
+		return "", fmt.Errorf("while listing the standbys: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	healthyInstances := cluster.Status.InstancesStatus[utils.PodHealthy]
+	for rows.Next() {
+		var applicationName string
+		if err := rows.Scan(&applicationName); err != nil {
+			return "", err
+		}
+		name, ok := cluster.GetInstanceNameFromApplicationName(applicationName)
+		if !ok || name == c.instance.PodName || cluster.IsInstanceQuarantined(name) {
+			continue
+		}
+		for _, healthyInstance := range healthyInstances {
+			if name == healthyInstance {
+				return name, nil
+			}
+		}
+	}
+
+	return "", rows.Err()
+}
+
+// requestSwitchover sets the target primary of the cluster, the switchover
+// is then carried out by the instance managers like a manual one
+func (c *Coordinator) requestSwitchover(ctx context.Context, cluster *apiv1.Cluster, targetPrimary string) error {
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.TargetPrimary = targetPrimary
+	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	cluster.Status.Phase = apiv1.PhaseSwitchover
+	cluster.Status.PhaseReason = fmt.Sprintf("Switching over to %v, %v is being drained",
+		targetPrimary, c.instance.PodName)
+
+	// the optimistic lock prevents overriding a concurrent failover
+	return c.client.Status().Patch(ctx, cluster,
+		client.MergeFromWithOptions(oldCluster, client.MergeFromWithOptimisticLock{}))
+}
+
+// waitForSwitchover waits for another instance to become the primary
+func (c *Coordinator) waitForSwitchover(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		var cluster apiv1.Cluster
+		err := c.client.Get(ctx, types.NamespacedName{
+			Namespace: c.instance.Namespace,
+			Name:      c.instance.ClusterName,
+		}, &cluster)
+		switch {
+		case ctx.Err() != nil:
+			return ErrSwitchoverTimeout
+		case err != nil:
+			contextLogger.Warning("while getting the cluster, retrying", "err", err)
+		case cluster.Status.CurrentPrimary != c.instance.PodName &&
+			cluster.Status.CurrentPrimary == cluster.Status.TargetPrimary:
+			contextLogger.Info("Switchover completed", "currentPrimary", cluster.Status.CurrentPrimary)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrSwitchoverTimeout
+		case <-ticker.C:
+		}
+	}
+}
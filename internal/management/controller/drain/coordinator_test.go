@@ -0,0 +1,251 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("drain coordinator", func() {
+	var (
+		cluster     *apiv1.Cluster
+		pod         *corev1.Pod
+		cl          client.Client
+		dbMock      sqlmock.Sqlmock
+		coordinator *Coordinator
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstancesStatus: map[utils.PodStatus][]string{
+					utils.PodHealthy: {"cluster-example-1", "cluster-example-2"},
+					utils.PodFailed:  {"cluster-example-3"},
+				},
+			},
+		}
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1",
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				TerminationGracePeriodSeconds: ptr.To(int64(1800)),
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.DisruptionTarget,
+						Status: corev1.ConditionTrue,
+						Reason: "EvictionByEvictionAPI",
+					},
+				},
+			},
+		}
+
+		var db *sql.DB
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		instance := postgres.NewInstance()
+		instance.PodName = pod.Name
+		instance.ClusterName = cluster.Name
+		instance.Namespace = cluster.Namespace
+
+		coordinator = &Coordinator{
+			instance: instance,
+			getSuperUserDB: func() (*sql.DB, error) {
+				return db, nil
+			},
+			pollInterval: 10 * time.Millisecond,
+		}
+	})
+
+	buildClient := func() {
+		cl = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, pod).
+			WithStatusSubresource(cluster).
+			Build()
+		coordinator.client = cl
+	}
+
+	getCluster := func(ctx context.Context) *apiv1.Cluster {
+		var updated apiv1.Cluster
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		return &updated
+	}
+
+	It("switches over to the most advanced healthy replica when the primary is drained", func(ctx SpecContext) {
+		buildClient()
+		dbMock.ExpectQuery(standbysQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"application_name"}).
+				AddRow("cluster-example-3").
+				AddRow("cluster-example-2"))
+
+		done := make(chan error)
+		go func() {
+			defer GinkgoRecover()
+			done <- coordinator.Run(ctx)
+		}()
+
+		Eventually(func(g Gomega) {
+			updated := getCluster(ctx)
+			g.Expect(updated.Status.TargetPrimary).To(Equal("cluster-example-2"))
+			g.Expect(updated.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+		}).Should(Succeed())
+		Consistently(done, 50*time.Millisecond).ShouldNot(Receive())
+
+		// the new primary has been promoted
+		updated := getCluster(ctx)
+		oldCluster := updated.DeepCopy()
+		updated.Status.CurrentPrimary = "cluster-example-2"
+		Expect(cl.Status().Patch(ctx, updated, client.MergeFrom(oldCluster))).To(Succeed())
+
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("stops waiting when the termination grace period nears", func(ctx SpecContext) {
+		pod.Spec.TerminationGracePeriodSeconds = ptr.To(int64(1))
+		buildClient()
+		dbMock.ExpectQuery(standbysQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"application_name"}).AddRow("cluster-example-2"))
+
+		Expect(coordinator.Run(ctx)).To(MatchError(ErrSwitchoverTimeout))
+		Expect(getCluster(ctx).Status.TargetPrimary).To(Equal("cluster-example-2"))
+	})
+
+	It("finds the replicas registered with a custom application name", func(ctx SpecContext) {
+		cluster.Spec.PostgresConfiguration.ReplicaConnection = &apiv1.ReplicaConnectionConfiguration{
+			ApplicationName: "dc1-{serial}",
+		}
+		pod.Spec.TerminationGracePeriodSeconds = ptr.To(int64(1))
+		buildClient()
+		dbMock.ExpectQuery(standbysQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"application_name"}).
+				AddRow("pg_basebackup").
+				AddRow("dc1-2"))
+
+		Expect(coordinator.Run(ctx)).To(MatchError(ErrSwitchoverTimeout))
+		Expect(getCluster(ctx).Status.TargetPrimary).To(Equal("cluster-example-2"))
+	})
+
+	It("doesn't switch over when no replica can be promoted", func(ctx SpecContext) {
+		buildClient()
+		dbMock.ExpectQuery(standbysQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"application_name"}).AddRow("cluster-example-3"))
+
+		Expect(coordinator.Run(ctx)).To(Succeed())
+		Expect(getCluster(ctx).Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("doesn't switch over when the pod is not being disrupted", func(ctx SpecContext) {
+		pod.Status.Conditions = nil
+		buildClient()
+
+		Expect(coordinator.Run(ctx)).To(Succeed())
+		Expect(getCluster(ctx).Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("doesn't switch over when the pod is running a replica", func(ctx SpecContext) {
+		pod.Name = "cluster-example-2"
+		coordinator.instance.PodName = pod.Name
+		buildClient()
+
+		Expect(coordinator.Run(ctx)).To(Succeed())
+		Expect(getCluster(ctx).Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("waits for a switchover that is already in progress", func(ctx SpecContext) {
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		buildClient()
+
+		done := make(chan error)
+		go func() {
+			defer GinkgoRecover()
+			done <- coordinator.Run(ctx)
+		}()
+		Consistently(done, 50*time.Millisecond).ShouldNot(Receive())
+
+		updated := getCluster(ctx)
+		oldCluster := updated.DeepCopy()
+		updated.Status.CurrentPrimary = "cluster-example-2"
+		Expect(cl.Status().Patch(ctx, updated, client.MergeFrom(oldCluster))).To(Succeed())
+
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
+
+var _ = Describe("getDeadline", func() {
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			SmartShutdownTimeout: 180,
+		},
+	}
+
+	It("leaves the smart shutdown timeout to PostgreSQL", func() {
+		deletionTime := time.Now().Add(time.Hour)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				DeletionTimestamp: &metav1.Time{Time: deletionTime},
+			},
+			Spec: corev1.PodSpec{
+				TerminationGracePeriodSeconds: ptr.To(int64(3600)),
+			},
+		}
+		Expect(getDeadline(pod, cluster)).To(BeTemporally("==", deletionTime.Add(-180*time.Second)))
+	})
+
+	It("reserves at most half of the termination grace period", func() {
+		deletionTime := time.Now().Add(time.Minute)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				DeletionTimestamp: &metav1.Time{Time: deletionTime},
+			},
+			Spec: corev1.PodSpec{
+				TerminationGracePeriodSeconds: ptr.To(int64(60)),
+			},
+		}
+		Expect(getDeadline(pod, cluster)).To(BeTemporally("==", deletionTime.Add(-30*time.Second)))
+	})
+})
@@ -38,9 +38,15 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/monitoring"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/publications"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/infrastructure"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/reconciler"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/sqljobs"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/subscriptions"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/tables"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/timeouts"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
@@ -53,6 +59,7 @@ import (
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -215,11 +222,33 @@ func (r *InstanceReconciler) Reconcile(
 		return result, err
 	}
 
+	// The managed SQL jobs waiting for their scripts don't prevent the
+	// rest of the instance from being reconciled
+	var sqlJobsResult reconcile.Result
 	if r.instance.PodName == cluster.Status.CurrentPrimary {
 		result, err := roles.Reconcile(ctx, r.instance, cluster, r.client)
 		if err != nil || !result.IsZero() {
 			return result, err
 		}
+
+		result, err = publications.Reconcile(ctx, r.instance, cluster, r.client)
+		if err != nil || !result.IsZero() {
+			return result, err
+		}
+
+		if err := timeouts.Reconcile(ctx, r.instance, cluster, r.client); err != nil {
+			return reconcile.Result{}, fmt.Errorf("cannot reconcile the timeouts of the roles: %w", err)
+		}
+
+		result, err = tables.Reconcile(ctx, r.instance, cluster, r.client)
+		if err != nil || !result.IsZero() {
+			return result, err
+		}
+
+		sqlJobsResult, err = sqljobs.Reconcile(ctx, r.instance, cluster, r.client)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	if err = r.refreshCredentialsFromSecret(ctx, cluster); err != nil {
@@ -230,6 +259,19 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if err := monitoring.Reconcile(ctx, r.instance, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the monitoring role: %w", err)
+	}
+
+	// Subscriptions are reconciled last, as an unreachable publisher
+	// makes us retry the reconciliation loop
+	if r.instance.PodName == cluster.Status.CurrentPrimary {
+		result, err := subscriptions.Reconcile(ctx, r.instance, cluster, r.client)
+		if err != nil || !result.IsZero() {
+			return result, err
+		}
+	}
+
 	// EXTREMELY IMPORTANT
 	//
 	// The reconciliation loop may not have applied all the changes needed. In this case
@@ -249,7 +291,7 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	return reconcile.Result{}, nil
+	return sqlJobsResult, nil
 }
 
 func (r *InstanceReconciler) configureSlotReplicator(cluster *apiv1.Cluster) {
@@ -290,6 +332,12 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
+	reloadIdent, err := r.instance.RefreshPGIdent(cluster)
+	if err != nil {
+		return false, err
+	}
+	reloadNeeded = reloadNeeded || reloadIdent
+
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(cluster, false)
@@ -493,7 +541,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 
 	extensionStatusChanged := false
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := extension.IsUsed(cluster.GetExtensionsParameters())
 		if lastStatus, ok := r.extensionStatus[extension.Name]; !ok || lastStatus != extensionIsUsed {
 			extensionStatusChanged = true
 			break
@@ -502,14 +550,14 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 
 	databases, errors := r.getAllAccessibleDatabases(ctx, db)
 	for _, databaseName := range databases {
-		db, err := r.instance.ConnectionPool().Connection(databaseName)
+		db, err := r.instance.ConnectionPool().Connection(ctx, databaseName)
 		if err != nil {
 			errors = append(errors,
 				fmt.Errorf("could not connect to database %s: %w", databaseName, err))
 			continue
 		}
 		if extensionStatusChanged {
-			if err = r.reconcileExtensions(ctx, db, cluster.Spec.PostgresConfiguration.Parameters); err != nil {
+			if err = r.reconcileExtensions(ctx, db, cluster.GetExtensionsParameters()); err != nil {
 				errors = append(errors,
 					fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
 			}
@@ -524,7 +572,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 	}
 
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := extension.IsUsed(cluster.GetExtensionsParameters())
 		r.extensionStatus[extension.Name] = extensionIsUsed
 	}
 
@@ -880,6 +928,8 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
 	r.instance.SmartStopDelay = cluster.GetSmartShutdownTimeout()
+	r.instance.SetLivenessQuery(cluster.Spec.Probes.IsLivenessQueryEnabled(), specs.GetLivenessProbeTimeout(*cluster))
+	r.instance.SetActivityQueriesExposed(cluster.Spec.Monitoring.AreActivityQueriesExposed())
 }
 
 func (r *InstanceReconciler) reconcileCheckWalArchiveFile(cluster *apiv1.Cluster) error {
@@ -1101,6 +1151,20 @@ func (r *InstanceReconciler) handlePromotion(ctx context.Context, cluster *apiv1
 	if err != nil {
 		return fmt.Errorf("error promoting instance: %w", err)
 	}
+
+	// The replicas may have been enforcing the read-only transactions,
+	// and the new primary must accept the writes straight away
+	changed, err := r.instance.RefreshConfigurationFilesFromCluster(cluster, false)
+	if err != nil {
+		return fmt.Errorf("while refreshing the configuration of the promoted instance: %w", err)
+	}
+	if changed {
+		contextLogger.Info("reloading the promoted instance")
+		if err := r.instance.Reload(ctx); err != nil {
+			return fmt.Errorf("while reloading the promoted instance: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -1175,10 +1239,19 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 			return err
 		}
 	} else {
-		err = postgresutils.DisableSuperuserPassword(db)
+		hasPassword, err := postgresutils.IsSuperuserPasswordSet(db)
 		if err != nil {
 			return err
 		}
+		if hasPassword {
+			// The password may have been set manually: we never allow
+			// the superuser to log in while its access is disabled
+			log.FromContext(ctx).Warning(
+				"The superuser has a password while the superuser access is disabled, removing it")
+			if err := postgresutils.DisableSuperuserPassword(db); err != nil {
+				return err
+			}
+		}
 	}
 
 	if cluster.ShouldCreateApplicationDatabase() {
@@ -252,7 +252,14 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(ctx context.Context
 		}
 
 		// Now I can demote myself
-		return r.instance.Demote(ctx, cluster)
+		if err := r.instance.Demote(ctx, cluster); err != nil {
+			return err
+		}
+
+		// Enforce the read-only transactions, if requested, before
+		// starting as a replica
+		_, err = r.instance.RefreshConfigurationFilesFromCluster(cluster, false)
+		return err
 	}
 }
 
@@ -0,0 +1,516 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkInterval is how often the instance manager checks if a maintenance
+// job has to be started or canceled
+const checkInterval = 30 * time.Second
+
+const (
+	// windowEndedMessage is reported when a job is interrupted because
+	// the maintenance window ended
+	windowEndedMessage = "Interrupted by the end of the maintenance window, " +
+		"it will be resumed in the next one"
+
+	// suspendedMessage is reported when a job is canceled because it
+	// has been suspended or removed
+	suspendedMessage = "Canceled because the job has been suspended"
+
+	// demotedMessage is reported when a job is interrupted because the
+	// instance is not the primary anymore
+	demotedMessage = "Interrupted because the instance is not the primary anymore, " +
+		"it will be resumed by the new primary"
+
+	// interruptedMessage is reported when the execution of a job has
+	// ended without recording its outcome, i.e. when the instance
+	// manager has been restarted
+	interruptedMessage = "Interrupted before completing, it will be resumed"
+)
+
+// statementExecutor executes a statement in a database, until it
+// completes or ctx is canceled
+type statementExecutor func(ctx context.Context, dbname string, statement string) error
+
+// runningJob is the maintenance job being executed
+type runningJob struct {
+	name   string
+	cancel context.CancelFunc
+
+	// phase and message are recorded in the status of the job when its
+	// execution is canceled
+	phase   apiv1.MaintenanceJobPhase
+	message string
+}
+
+// A JobRunner is a Kubernetes manager.Runnable that executes the managed
+// maintenance jobs on the primary, one at a time, within the maintenance
+// window of the cluster
+type JobRunner struct {
+	instance  *postgres.Instance
+	client    client.Client
+	apiReader client.Reader
+	execute   statementExecutor
+
+	mu sync.Mutex
+	// running is the job being executed, nil when no job is running
+	running *runningJob
+	wg      sync.WaitGroup
+}
+
+// NewJobRunner creates a new JobRunner. The status of the jobs is read
+// with apiReader, that is meant to read from the API server, as the jobs
+// that have just ended are not in the cache yet
+func NewJobRunner(instance *postgres.Instance, client client.Client, apiReader client.Reader) *JobRunner {
+	runner := &JobRunner{
+		instance:  instance,
+		client:    client,
+		apiReader: apiReader,
+	}
+	runner.execute = runner.executeStatement
+	return runner
+}
+
+// Start starts running the JobRunner
+func (r *JobRunner) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("maintenance_jobs")
+	ticker := time.NewTicker(checkInterval)
+	defer func() {
+		ticker.Stop()
+		r.cancelRunningJob("", "")
+		r.wg.Wait()
+		contextLog.Info("Terminated maintenance jobs loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := r.check(ctx); err != nil {
+			contextLog.Warning("checking the maintenance jobs", "err", err)
+		}
+	}
+}
+
+// check reconciles the maintenance jobs when the instance is a healthy
+// primary, interrupting the running one otherwise
+func (r *JobRunner) check(ctx context.Context) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if !isPrimary || r.instance.IsFenced() {
+		r.cancelRunningJob(apiv1.MaintenanceJobPhasePending, demotedMessage)
+		return nil
+	}
+
+	if err := r.instance.IsServerHealthy(); err != nil {
+		return nil
+	}
+
+	// the status is read from the API server, as the jobs that have
+	// just ended are not in the cached cluster yet
+	var cluster apiv1.Cluster
+	if err := r.apiReader.Get(ctx, types.NamespacedName{
+		Name:      r.instance.ClusterName,
+		Namespace: r.instance.Namespace,
+	}, &cluster); err != nil {
+		return err
+	}
+
+	return r.reconcile(ctx, &cluster, time.Now())
+}
+
+// reconcile cancels the running job if it has been suspended or the
+// maintenance window ended, and otherwise starts the first job that is
+// due, if any
+func (r *JobRunner) reconcile(ctx context.Context, cluster *apiv1.Cluster, now time.Time) error {
+	inWindow, err := isInMaintenanceWindow(cluster, now)
+	if err != nil {
+		return fmt.Errorf("while evaluating the maintenance window: %w", err)
+	}
+
+	jobs := getJobs(cluster)
+	if name := r.getRunningJob(); name != "" {
+		job := findJob(jobs, name)
+		switch {
+		case job == nil || job.Suspend:
+			r.cancelRunningJob(apiv1.MaintenanceJobPhaseCanceled, suspendedMessage)
+		case !inWindow:
+			r.cancelRunningJob(apiv1.MaintenanceJobPhasePending, windowEndedMessage)
+		}
+
+		// the heavy jobs are never executed concurrently: the other
+		// ones will be checked after the running one ends
+		return nil
+	}
+
+	status, err := getUpdatedStatus(cluster, jobs, now)
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(status, cluster.Status.MaintenanceJobsStatus) {
+		if err := r.patchStatus(ctx, cluster, status); err != nil {
+			return err
+		}
+	}
+
+	if !inWindow {
+		return nil
+	}
+
+	for _, job := range jobs {
+		if isDue(job, status[job.Name], now) {
+			r.startJob(ctx, cluster.GetApplicationDatabaseName(), job, status[job.Name])
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// isInMaintenanceWindow checks if the jobs can be executed at the given
+// time, that is when the cluster has no maintenance window, or during it
+func isInMaintenanceWindow(cluster *apiv1.Cluster, now time.Time) (bool, error) {
+	if cluster.Spec.MaintenanceWindow == nil {
+		return true, nil
+	}
+
+	inProgress, _, err := cluster.Spec.MaintenanceWindow.IsInProgress(now)
+	return inProgress, err
+}
+
+// getJobs returns the managed maintenance jobs of the cluster
+func getJobs(cluster *apiv1.Cluster) []apiv1.MaintenanceJobConfiguration {
+	if !cluster.ContainsMaintenanceJobsConfiguration() {
+		return nil
+	}
+	return cluster.Spec.Managed.MaintenanceJobs
+}
+
+// findJob gets the job with the given name, nil if it doesn't exist
+func findJob(jobs []apiv1.MaintenanceJobConfiguration, name string) *apiv1.MaintenanceJobConfiguration {
+	for i := range jobs {
+		if jobs[i].Name == name {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// getUpdatedStatus returns the status of the jobs that are still in the
+// spec, scheduling the next execution of the new scheduled jobs and
+// marking as pending the executions that ended without recording their
+// outcome. No job is running when this is called
+func getUpdatedStatus(
+	cluster *apiv1.Cluster,
+	jobs []apiv1.MaintenanceJobConfiguration,
+	now time.Time,
+) (map[string]apiv1.MaintenanceJobStatus, error) {
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	status := make(map[string]apiv1.MaintenanceJobStatus, len(jobs))
+	for _, job := range jobs {
+		jobStatus, found := cluster.Status.MaintenanceJobsStatus[job.Name]
+		if !found {
+			jobStatus = apiv1.MaintenanceJobStatus{Phase: apiv1.MaintenanceJobPhasePending}
+		}
+
+		// the execution is resumed from the target being processed
+		if jobStatus.Phase == apiv1.MaintenanceJobPhaseRunning {
+			jobStatus.Phase = apiv1.MaintenanceJobPhasePending
+			jobStatus.Message = interruptedMessage
+		}
+
+		switch {
+		case job.Schedule == "":
+			jobStatus.NextScheduleTime = nil
+		case jobStatus.NextScheduleTime == nil:
+			next, err := getNextScheduleTime(job.Schedule, now)
+			if err != nil {
+				return nil, fmt.Errorf("while scheduling the maintenance job %s: %w", job.Name, err)
+			}
+			jobStatus.NextScheduleTime = next
+		}
+
+		status[job.Name] = jobStatus
+	}
+
+	return status, nil
+}
+
+// getNextScheduleTime gets the first time after the given one matching
+// the schedule
+func getNextScheduleTime(schedule string, after time.Time) (*metav1.Time, error) {
+	parsedSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	next := metav1.NewTime(parsedSchedule.Next(after.UTC()))
+	return &next, nil
+}
+
+// isDue checks if a job is to be executed at the given time: the jobs
+// without a schedule are executed until they complete or fail, while
+// the scheduled ones whenever their schedule is reached
+func isDue(job apiv1.MaintenanceJobConfiguration, status apiv1.MaintenanceJobStatus, now time.Time) bool {
+	if job.Suspend {
+		return false
+	}
+
+	if job.Schedule == "" {
+		return status.Phase == apiv1.MaintenanceJobPhasePending ||
+			status.Phase == apiv1.MaintenanceJobPhaseCanceled
+	}
+
+	return status.NextScheduleTime != nil && !status.NextScheduleTime.After(now)
+}
+
+// getResumedTarget gets the index of the target an interrupted execution is
+// resumed from, that is the one being processed when it was interrupted. The
+// execution starts from the first target when it was not interrupted, or the
+// target is not in the job anymore
+func getResumedTarget(targets []target, status apiv1.MaintenanceJobStatus) int {
+	if status.Phase != apiv1.MaintenanceJobPhasePending || status.CurrentTarget == "" {
+		return 0
+	}
+
+	for i := range targets {
+		if targets[i].name == status.CurrentTarget {
+			return i
+		}
+	}
+	return 0
+}
+
+// getRunningJob gets the name of the running job, empty if no job is running
+func (r *JobRunner) getRunningJob() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running == nil {
+		return ""
+	}
+	return r.running.name
+}
+
+// cancelRunningJob cancels the running job, if any, recording the
+// given phase and message in its status
+func (r *JobRunner) cancelRunningJob(phase apiv1.MaintenanceJobPhase, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running == nil {
+		return
+	}
+
+	r.running.phase = phase
+	r.running.message = message
+	r.running.cancel()
+}
+
+// startJob executes the job in background
+func (r *JobRunner) startJob(
+	ctx context.Context,
+	defaultDBName string,
+	job apiv1.MaintenanceJobConfiguration,
+	status apiv1.MaintenanceJobStatus,
+) {
+	runCtx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	r.running = &runningJob{name: job.Name, cancel: cancel}
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			r.running = nil
+			r.mu.Unlock()
+			cancel()
+			r.wg.Done()
+		}()
+		r.run(ctx, runCtx, job.GetDBName(defaultDBName), job, status)
+	}()
+}
+
+// run processes the targets of the job until it ends or runCtx is canceled,
+// reporting the progress in the status of the cluster. The status is
+// updated using ctx, to be able to record the cancellation
+func (r *JobRunner) run(
+	ctx, runCtx context.Context,
+	dbname string,
+	job apiv1.MaintenanceJobConfiguration,
+	status apiv1.MaintenanceJobStatus,
+) {
+	contextLog := log.FromContext(ctx).WithName("maintenance_jobs").WithValues("job", job.Name)
+
+	targets := buildTargets(job)
+	first := getResumedTarget(targets, status)
+	if first > 0 && status.StartedAt != nil {
+		contextLog.Info("Resuming the maintenance job", "operation", job.Operation, "database", dbname,
+			"target", targets[first].name)
+	} else {
+		contextLog.Info("Starting the maintenance job", "operation", job.Operation, "database", dbname)
+		startedAt := metav1.Now()
+		status.StartedAt = &startedAt
+	}
+	status.Phase = apiv1.MaintenanceJobPhaseRunning
+	status.TotalTargets = len(targets)
+	status.CompletedAt = nil
+	status.Message = ""
+
+	var err error
+	for i := first; i < len(targets); i++ {
+		status.CurrentTarget = targets[i].name
+		status.ProcessedTargets = i
+		r.updateStatus(ctx, job.Name, status)
+
+		contextLog.Info("Executing the maintenance statement", "statement", targets[i].statement)
+		if err = r.execute(runCtx, dbname, targets[i].statement); err != nil {
+			break
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		// the instance manager is shutting down, the job will be
+		// resumed by the next one
+		contextLog.Info("Interrupted the maintenance job")
+		return
+	case runCtx.Err() != nil:
+		r.mu.Lock()
+		status.Phase, status.Message = r.running.phase, r.running.message
+		r.mu.Unlock()
+		contextLog.Info("Canceled the maintenance job", "phase", status.Phase, "reason", status.Message)
+	case err != nil:
+		contextLog.Error(err, "while executing the maintenance job")
+		status.Phase = apiv1.MaintenanceJobPhaseFailed
+		status.Message = err.Error()
+	default:
+		contextLog.Info("Completed the maintenance job")
+		status.Phase = apiv1.MaintenanceJobPhaseCompleted
+		status.ProcessedTargets = len(targets)
+	}
+
+	// an interrupted execution is resumed from the current target, while
+	// a new one is scheduled for the jobs that ended
+	if status.Phase != apiv1.MaintenanceJobPhasePending {
+		status.CurrentTarget = ""
+		completedAt := metav1.Now()
+		status.CompletedAt = &completedAt
+		if job.Schedule != "" {
+			next, err := getNextScheduleTime(job.Schedule, completedAt.Time)
+			if err != nil {
+				contextLog.Error(err, "while scheduling the next execution of the maintenance job")
+			}
+			status.NextScheduleTime = next
+		}
+	}
+
+	r.updateStatus(ctx, job.Name, status)
+}
+
+// executeStatement executes a maintenance statement in a database. The
+// statement timeout of the cluster is meant for the applications, and is
+// disabled, while the lock timeout still applies
+func (r *JobRunner) executeStatement(ctx context.Context, dbname string, statement string) error {
+	db, err := r.instance.ConnectionPool().Connection(ctx, dbname)
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.FromContext(ctx).Debug("while closing the maintenance connection", "err", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, "SET statement_timeout TO 0"); err != nil {
+		return err
+	}
+	defer func() {
+		// the connection goes back to the pool
+		_, _ = conn.ExecContext(context.Background(), "RESET statement_timeout")
+	}()
+
+	_, err = conn.ExecContext(ctx, statement)
+	return err
+}
+
+// updateStatus sets the status of a job in the cluster
+func (r *JobRunner) updateStatus(ctx context.Context, name string, status apiv1.MaintenanceJobStatus) {
+	var cluster apiv1.Cluster
+	if err := r.apiReader.Get(ctx, types.NamespacedName{
+		Name:      r.instance.ClusterName,
+		Namespace: r.instance.Namespace,
+	}, &cluster); err != nil {
+		log.FromContext(ctx).Warning("while getting the cluster to report the maintenance job progress",
+			"job", name, "err", err)
+		return
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	if updatedCluster.Status.MaintenanceJobsStatus == nil {
+		updatedCluster.Status.MaintenanceJobsStatus = make(map[string]apiv1.MaintenanceJobStatus)
+	}
+	updatedCluster.Status.MaintenanceJobsStatus[name] = status
+	if err := r.client.Status().Patch(ctx, updatedCluster, client.MergeFrom(&cluster)); err != nil {
+		log.FromContext(ctx).Warning("while reporting the maintenance job progress", "job", name, "err", err)
+	}
+}
+
+// patchStatus stores the status of the jobs in the cluster, keeping the
+// passed cluster aligned with the stored one
+func (r *JobRunner) patchStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status map[string]apiv1.MaintenanceJobStatus,
+) error {
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.MaintenanceJobsStatus = status
+	if err := r.client.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster)); err != nil {
+		return fmt.Errorf("while updating the status of the maintenance jobs: %w", err)
+	}
+	cluster.Status.MaintenanceJobsStatus = updatedCluster.Status.MaintenanceJobsStatus
+	return nil
+}
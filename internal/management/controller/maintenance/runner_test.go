@@ -0,0 +1,363 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("maintenance job runner", func() {
+	var (
+		cluster  *apiv1.Cluster
+		runner   *JobRunner
+		cl       client.Client
+		mu       sync.Mutex
+		executed []string
+		dbnames  []string
+	)
+
+	// a Saturday within the maintenance window starting at 2:00
+	inWindow := time.Date(2024, time.June, 1, 2, 30, 0, 0, time.UTC)
+	afterWindow := inWindow.Add(2 * time.Hour)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app"},
+				},
+				MaintenanceWindow: &apiv1.MaintenanceWindowConfiguration{
+					Schedule: "0 0 2 * * 6",
+					Duration: 3600,
+				},
+				Managed: &apiv1.ManagedConfiguration{
+					MaintenanceJobs: []apiv1.MaintenanceJobConfiguration{
+						{
+							Name:      "reindex",
+							Operation: apiv1.MaintenanceOperationReindexConcurrently,
+							Tables:    []string{"orders"},
+							Indexes:   []string{"sales.orders_pkey"},
+						},
+						{
+							Name:      "vacuum",
+							Operation: apiv1.MaintenanceOperationVacuumFull,
+							Tables:    []string{"events"},
+						},
+					},
+				},
+			},
+		}
+		cl = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+
+		instance := postgres.NewInstance()
+		instance.ClusterName = cluster.Name
+		instance.Namespace = cluster.Namespace
+		runner = NewJobRunner(instance, cl, cl)
+
+		executed = nil
+		dbnames = nil
+		runner.execute = func(_ context.Context, dbname string, statement string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			dbnames = append(dbnames, dbname)
+			executed = append(executed, statement)
+			return nil
+		}
+	})
+
+	getCluster := func(ctx context.Context) *apiv1.Cluster {
+		var updated apiv1.Cluster
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		return &updated
+	}
+
+	getStatus := func(ctx context.Context, name string) apiv1.MaintenanceJobStatus {
+		return getCluster(ctx).Status.MaintenanceJobsStatus[name]
+	}
+
+	reconcile := func(ctx context.Context, now time.Time) {
+		Expect(runner.reconcile(ctx, getCluster(ctx), now)).To(Succeed())
+	}
+
+	// blockExecution makes the statements wait for the cancellation of
+	// the job, returning a channel notified when a statement is started
+	blockExecution := func() chan string {
+		started := make(chan string, 10)
+		runner.execute = func(ctx context.Context, _ string, statement string) error {
+			started <- statement
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return started
+	}
+
+	Context("scheduling", func() {
+		It("executes the on-demand jobs one at a time, until they complete", func(ctx SpecContext) {
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+			Expect(executed).To(Equal([]string{
+				`REINDEX TABLE CONCURRENTLY "public"."orders"`,
+				`REINDEX INDEX CONCURRENTLY "sales"."orders_pkey"`,
+			}))
+			Expect(dbnames).To(ConsistOf("app", "app"))
+
+			status := getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhaseCompleted))
+			Expect(status.ProcessedTargets).To(Equal(2))
+			Expect(status.TotalTargets).To(Equal(2))
+			Expect(status.CurrentTarget).To(BeEmpty())
+			Expect(status.StartedAt).ToNot(BeNil())
+			Expect(status.CompletedAt).ToNot(BeNil())
+			Expect(getStatus(ctx, "vacuum").Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+			Expect(executed).To(HaveLen(3))
+			Expect(executed[2]).To(Equal(`VACUUM (FULL) "public"."events"`))
+			Expect(getStatus(ctx, "vacuum").Phase).To(Equal(apiv1.MaintenanceJobPhaseCompleted))
+
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+			Expect(executed).To(HaveLen(3))
+		})
+
+		It("doesn't start a job while another one is running", func(ctx SpecContext) {
+			started := blockExecution()
+			reconcile(ctx, inWindow)
+			Eventually(started).Should(Receive())
+
+			reconcile(ctx, inWindow)
+			Consistently(started, 100*time.Millisecond).ShouldNot(Receive())
+			Expect(getStatus(ctx, "reindex").Phase).To(Equal(apiv1.MaintenanceJobPhaseRunning))
+			Expect(getStatus(ctx, "reindex").CurrentTarget).To(Equal("orders"))
+			Expect(getStatus(ctx, "vacuum").Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+
+			runner.cancelRunningJob(apiv1.MaintenanceJobPhasePending, demotedMessage)
+			runner.wg.Wait()
+		})
+
+		It("executes the scheduled jobs when their schedule is reached", func(ctx SpecContext) {
+			stored := getCluster(ctx)
+			stored.Spec.Managed.MaintenanceJobs = stored.Spec.Managed.MaintenanceJobs[:1]
+			stored.Spec.Managed.MaintenanceJobs[0].Schedule = "0 45 2 * * *"
+			Expect(cl.Update(ctx, stored)).To(Succeed())
+
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+			Expect(executed).To(BeEmpty())
+			status := getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+			Expect(status.NextScheduleTime.Time).To(BeTemporally("==", inWindow.Add(15*time.Minute)))
+
+			reconcile(ctx, inWindow.Add(20*time.Minute))
+			runner.wg.Wait()
+			Expect(executed).To(HaveLen(2))
+			status = getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhaseCompleted))
+			Expect(status.NextScheduleTime.After(inWindow.Add(20 * time.Minute))).To(BeTrue())
+		})
+
+		It("doesn't execute the suspended jobs", func(ctx SpecContext) {
+			stored := getCluster(ctx)
+			stored.Spec.Managed.MaintenanceJobs[0].Suspend = true
+			stored.Spec.Managed.MaintenanceJobs = stored.Spec.Managed.MaintenanceJobs[:1]
+			Expect(cl.Update(ctx, stored)).To(Succeed())
+
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+			Expect(executed).To(BeEmpty())
+			Expect(getStatus(ctx, "reindex").Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+		})
+
+		It("reports the failure of a job", func(ctx SpecContext) {
+			runner.execute = func(context.Context, string, string) error {
+				return errors.New(`relation "public.orders" does not exist`)
+			}
+
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+
+			status := getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhaseFailed))
+			Expect(status.Message).To(ContainSubstring("does not exist"))
+			Expect(status.ProcessedTargets).To(Equal(0))
+			Expect(isDue(cluster.Spec.Managed.MaintenanceJobs[0], status, inWindow)).To(BeFalse())
+		})
+
+		It("resumes the jobs whose execution has been interrupted", func(ctx SpecContext) {
+			stored := getCluster(ctx)
+			stored.Status.MaintenanceJobsStatus = map[string]apiv1.MaintenanceJobStatus{
+				"reindex": {Phase: apiv1.MaintenanceJobPhaseRunning, CurrentTarget: "orders"},
+			}
+			Expect(cl.Status().Update(ctx, stored)).To(Succeed())
+
+			Expect(runner.reconcile(ctx, getCluster(ctx), afterWindow)).To(Succeed())
+			status := getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+			Expect(status.Message).To(Equal(interruptedMessage))
+			Expect(status.CurrentTarget).To(Equal("orders"))
+		})
+
+		It("resumes an interrupted execution from the target being processed", func(ctx SpecContext) {
+			startedAt := metav1.NewTime(inWindow.Add(-time.Hour))
+			stored := getCluster(ctx)
+			stored.Spec.Managed.MaintenanceJobs = stored.Spec.Managed.MaintenanceJobs[:1]
+			Expect(cl.Update(ctx, stored)).To(Succeed())
+			stored.Status.MaintenanceJobsStatus = map[string]apiv1.MaintenanceJobStatus{
+				"reindex": {
+					Phase:            apiv1.MaintenanceJobPhaseRunning,
+					CurrentTarget:    "sales.orders_pkey",
+					ProcessedTargets: 1,
+					TotalTargets:     2,
+					StartedAt:        &startedAt,
+				},
+			}
+			Expect(cl.Status().Update(ctx, stored)).To(Succeed())
+
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+			Expect(executed).To(Equal([]string{`REINDEX INDEX CONCURRENTLY "sales"."orders_pkey"`}))
+
+			status := getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhaseCompleted))
+			Expect(status.ProcessedTargets).To(Equal(2))
+			Expect(status.CurrentTarget).To(BeEmpty())
+			Expect(status.StartedAt.Time).To(BeTemporally("==", startedAt.Time))
+		})
+
+		It("restarts from the first target when the interrupted one has been removed", func() {
+			targets := buildTargets(cluster.Spec.Managed.MaintenanceJobs[0])
+			status := apiv1.MaintenanceJobStatus{Phase: apiv1.MaintenanceJobPhasePending, CurrentTarget: "sales.orders_pkey"}
+			Expect(getResumedTarget(targets, status)).To(Equal(1))
+
+			status.CurrentTarget = "removed_idx"
+			Expect(getResumedTarget(targets, status)).To(Equal(0))
+
+			status = apiv1.MaintenanceJobStatus{Phase: apiv1.MaintenanceJobPhaseCanceled, CurrentTarget: "sales.orders_pkey"}
+			Expect(getResumedTarget(targets, status)).To(Equal(0))
+		})
+	})
+
+	Context("maintenance window", func() {
+		It("doesn't start the jobs outside the maintenance window", func(ctx SpecContext) {
+			reconcile(ctx, afterWindow)
+			runner.wg.Wait()
+			Expect(executed).To(BeEmpty())
+			Expect(getStatus(ctx, "reindex").Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+		})
+
+		It("starts the jobs at any time without a maintenance window", func(ctx SpecContext) {
+			stored := getCluster(ctx)
+			stored.Spec.MaintenanceWindow = nil
+			stored.Spec.Managed.MaintenanceJobs = stored.Spec.Managed.MaintenanceJobs[:1]
+			Expect(cl.Update(ctx, stored)).To(Succeed())
+
+			reconcile(ctx, afterWindow)
+			runner.wg.Wait()
+			Expect(executed).To(HaveLen(2))
+		})
+
+		It("interrupts the running job when the window ends, resuming it in the next one",
+			func(ctx SpecContext) {
+				started := blockExecution()
+				reconcile(ctx, inWindow)
+				Eventually(started).Should(Receive())
+
+				reconcile(ctx, afterWindow)
+				runner.wg.Wait()
+				status := getStatus(ctx, "reindex")
+				Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhasePending))
+				Expect(status.Message).To(Equal(windowEndedMessage))
+				Expect(status.CompletedAt).To(BeNil())
+				Expect(status.CurrentTarget).To(Equal("orders"))
+
+				reconcile(ctx, inWindow.Add(7*24*time.Hour))
+				Eventually(started).Should(Receive(Equal(`REINDEX TABLE CONCURRENTLY "public"."orders"`)))
+				Expect(getStatus(ctx, "reindex").Phase).To(Equal(apiv1.MaintenanceJobPhaseRunning))
+
+				runner.cancelRunningJob(apiv1.MaintenanceJobPhasePending, demotedMessage)
+				runner.wg.Wait()
+			})
+	})
+
+	Context("cancellation", func() {
+		It("cancels the running job when it is suspended", func(ctx SpecContext) {
+			started := blockExecution()
+			reconcile(ctx, inWindow)
+			Eventually(started).Should(Receive())
+
+			stored := getCluster(ctx)
+			stored.Spec.Managed.MaintenanceJobs[0].Suspend = true
+			Expect(runner.reconcile(ctx, stored, inWindow)).To(Succeed())
+			runner.wg.Wait()
+
+			status := getStatus(ctx, "reindex")
+			Expect(status.Phase).To(Equal(apiv1.MaintenanceJobPhaseCanceled))
+			Expect(status.Message).To(Equal(suspendedMessage))
+			Expect(status.CompletedAt).ToNot(BeNil())
+		})
+
+		It("cancels the running job when it is removed", func(ctx SpecContext) {
+			started := blockExecution()
+			reconcile(ctx, inWindow)
+			Eventually(started).Should(Receive())
+
+			stored := getCluster(ctx)
+			stored.Spec.Managed.MaintenanceJobs = stored.Spec.Managed.MaintenanceJobs[1:]
+			Expect(cl.Update(ctx, stored)).To(Succeed())
+			reconcile(ctx, inWindow)
+			runner.wg.Wait()
+
+			// the status of the removed job is dropped at the next check
+			reconcile(ctx, inWindow)
+			Eventually(started).Should(Receive(Equal(`VACUUM (FULL) "public"."events"`)))
+			runner.cancelRunningJob(apiv1.MaintenanceJobPhasePending, demotedMessage)
+			runner.wg.Wait()
+			Expect(getCluster(ctx).Status.MaintenanceJobsStatus).ToNot(HaveKey("reindex"))
+		})
+
+		It("executes again the canceled on-demand jobs once resumed", func() {
+			job := apiv1.MaintenanceJobConfiguration{Name: "reindex"}
+			canceled := apiv1.MaintenanceJobStatus{Phase: apiv1.MaintenanceJobPhaseCanceled}
+			Expect(isDue(job, canceled, inWindow)).To(BeTrue())
+
+			job.Suspend = true
+			Expect(isDue(job, canceled, inWindow)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// defaultSchema is the schema of the targets that are not schema-qualified
+const defaultSchema = "public"
+
+// target is a table or an index processed by a maintenance job
+type target struct {
+	// name is the name of the target, as written in the job
+	name string
+
+	// statement is the statement processing the target
+	statement string
+}
+
+// buildTargets gets the statements executed by a job, one for each
+// of its targets, processing the tables before the indexes
+func buildTargets(job apiv1.MaintenanceJobConfiguration) []target {
+	tableTemplate, indexTemplate := "VACUUM (FULL) %s", ""
+	if job.Operation == apiv1.MaintenanceOperationReindexConcurrently {
+		tableTemplate, indexTemplate = "REINDEX TABLE CONCURRENTLY %s", "REINDEX INDEX CONCURRENTLY %s"
+	}
+
+	result := make([]target, 0, len(job.Tables)+len(job.Indexes))
+	for _, table := range job.Tables {
+		result = append(result, target{name: table, statement: fmt.Sprintf(tableTemplate, sanitizeName(table))})
+	}
+	if indexTemplate != "" {
+		for _, index := range job.Indexes {
+			result = append(result, target{name: index, statement: fmt.Sprintf(indexTemplate, sanitizeName(index))})
+		}
+	}
+
+	return result
+}
+
+// sanitizeName quotes every part of the name of a table or an index,
+// adding the default schema unless it is already schema-qualified
+func sanitizeName(name string) string {
+	if !strings.Contains(name, ".") {
+		name = defaultSchema + "." + name
+	}
+	return pgx.Identifier(strings.SplitN(name, ".", 2)).Sanitize()
+}
@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("maintenance statements", func() {
+	It("rebuilds the indexes of the tables and the listed indexes concurrently", func() {
+		Expect(buildTargets(apiv1.MaintenanceJobConfiguration{
+			Operation: apiv1.MaintenanceOperationReindexConcurrently,
+			Tables:    []string{"orders", "sales.Customers"},
+			Indexes:   []string{"orders_pkey"},
+		})).To(Equal([]target{
+			{name: "orders", statement: `REINDEX TABLE CONCURRENTLY "public"."orders"`},
+			{name: "sales.Customers", statement: `REINDEX TABLE CONCURRENTLY "sales"."Customers"`},
+			{name: "orders_pkey", statement: `REINDEX INDEX CONCURRENTLY "public"."orders_pkey"`},
+		}))
+	})
+
+	It("rewrites the tables", func() {
+		Expect(buildTargets(apiv1.MaintenanceJobConfiguration{
+			Operation: apiv1.MaintenanceOperationVacuumFull,
+			Tables:    []string{`weird"name`},
+		})).To(Equal([]target{
+			{name: `weird"name`, statement: `VACUUM (FULL) "public"."weird""name"`},
+		}))
+	})
+})
@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// monitoringGroupRoleName is the predefined role granting the
+// privileges needed by the metrics exporter
+const monitoringGroupRoleName = "pg_monitor"
+
+// Reconcile creates the dedicated monitoring role on the primary instance
+// when requested, and tells the metrics exporter whether it can use it.
+// Until the role can be used, the exporter keeps using the superuser, so that
+// clusters enabling the feature never lose their metrics
+func Reconcile(ctx context.Context, instance *postgres.Instance, cluster *apiv1.Cluster) error {
+	if !cluster.Spec.Monitoring.IsDedicatedRoleEnabled() {
+		instance.SetMonitoringRoleAvailable(false)
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while getting the superuser connection: %w", err)
+	}
+
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if isPrimary {
+		if err := ensureMonitoringRole(ctx, superUserDB); err != nil {
+			return fmt.Errorf("while creating the monitoring role: %w", err)
+		}
+	}
+
+	// Replicas receive the role through the WAL stream, so it
+	// may not be there yet
+	exists, err := monitoringRoleExists(ctx, superUserDB)
+	if err != nil {
+		return fmt.Errorf("while checking the monitoring role: %w", err)
+	}
+
+	available := false
+	if exists {
+		monitoringDB, err := instance.MonitoringRoleConnectionPool().Connection(ctx, "postgres")
+		if err == nil {
+			err = monitoringDB.PingContext(ctx)
+		}
+		if err != nil {
+			// This happens when PostgreSQL has not reloaded the user
+			// name map yet, i.e. right after an online upgrade
+			contextLogger.Debug("Cannot connect with the monitoring role, using the superuser",
+				"role", apiv1.MonitoringRoleName, "err", err)
+		}
+		available = err == nil
+	}
+
+	if available != instance.IsMonitoringRoleAvailable() {
+		contextLogger.Info("Changing the role used by the metrics exporter",
+			"role", apiv1.MonitoringRoleName, "dedicatedRoleAvailable", available)
+	}
+	instance.SetMonitoringRoleAvailable(available)
+	return nil
+}
+
+// monitoringRoleExists checks whether the dedicated monitoring role exists
+func monitoringRoleExists(ctx context.Context, db *sql.DB) (bool, error) {
+	var exists bool
+	row := db.QueryRowContext(ctx,
+		"SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = $1",
+		apiv1.MonitoringRoleName)
+	if err := row.Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// ensureMonitoringRole creates the dedicated monitoring role, if missing,
+// and makes it a member of pg_monitor
+func ensureMonitoringRole(ctx context.Context, db *sql.DB) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	var existsRole, isMember bool
+	row := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) > 0, COALESCE(bool_or(pg_catalog.pg_has_role(oid, $2, 'MEMBER')), false) "+
+			"FROM pg_catalog.pg_roles WHERE rolname = $1",
+		apiv1.MonitoringRoleName, monitoringGroupRoleName)
+	if err = row.Scan(&existsRole, &isMember); err != nil {
+		return err
+	}
+
+	roleIdentifier := pgx.Identifier{apiv1.MonitoringRoleName}.Sanitize()
+	if !existsRole {
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf("CREATE ROLE %s WITH LOGIN", roleIdentifier)); err != nil {
+			return err
+		}
+	}
+	if !isMember {
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf("GRANT %s TO %s",
+			pgx.Identifier{monitoringGroupRoleName}.Sanitize(), roleIdentifier)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("monitoring role reconciler", func() {
+	const (
+		roleStatusQuery = "SELECT COUNT(*) > 0, COALESCE(bool_or(pg_catalog.pg_has_role(oid, $2, 'MEMBER')), false) " +
+			"FROM pg_catalog.pg_roles WHERE rolname = $1"
+		roleExistsQuery = "SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = $1"
+	)
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates the role and grants pg_monitor when missing", func(ctx context.Context) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(roleStatusQuery).
+			WithArgs(apiv1.MonitoringRoleName, "pg_monitor").
+			WillReturnRows(sqlmock.NewRows([]string{"exists", "member"}).AddRow(false, false))
+		mock.ExpectExec(`CREATE ROLE "cnpg_monitoring" WITH LOGIN`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`GRANT "pg_monitor" TO "cnpg_monitoring"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(ensureMonitoringRole(ctx, db)).To(Succeed())
+	})
+
+	It("grants pg_monitor to an existing role missing it", func(ctx context.Context) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(roleStatusQuery).
+			WithArgs(apiv1.MonitoringRoleName, "pg_monitor").
+			WillReturnRows(sqlmock.NewRows([]string{"exists", "member"}).AddRow(true, false))
+		mock.ExpectExec(`GRANT "pg_monitor" TO "cnpg_monitoring"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(ensureMonitoringRole(ctx, db)).To(Succeed())
+	})
+
+	It("does nothing when the role is already configured", func(ctx context.Context) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(roleStatusQuery).
+			WithArgs(apiv1.MonitoringRoleName, "pg_monitor").
+			WillReturnRows(sqlmock.NewRows([]string{"exists", "member"}).AddRow(true, true))
+		mock.ExpectCommit()
+
+		Expect(ensureMonitoringRole(ctx, db)).To(Succeed())
+	})
+
+	It("rolls back when the role cannot be created", func(ctx context.Context) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(roleStatusQuery).
+			WithArgs(apiv1.MonitoringRoleName, "pg_monitor").
+			WillReturnRows(sqlmock.NewRows([]string{"exists", "member"}).AddRow(false, false))
+		mock.ExpectExec(`CREATE ROLE "cnpg_monitoring" WITH LOGIN`).
+			WillReturnError(errors.New("permission denied"))
+		mock.ExpectRollback()
+
+		Expect(ensureMonitoringRole(ctx, db)).To(MatchError("permission denied"))
+	})
+
+	It("checks whether the role exists", func(ctx context.Context) {
+		mock.ExpectQuery(roleExistsQuery).
+			WithArgs(apiv1.MonitoringRoleName).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		exists, err := monitoringRoleExists(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("stops using the dedicated role when the feature is disabled", func(ctx context.Context) {
+		instance := postgres.NewInstance()
+		instance.SetMonitoringRoleAvailable(true)
+
+		Expect(Reconcile(ctx, instance, &apiv1.Cluster{})).To(Succeed())
+		Expect(instance.IsMonitoringRoleAvailable()).To(BeFalse())
+	})
+})
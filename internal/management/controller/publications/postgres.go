@@ -0,0 +1,244 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	"golang.org/x/exp/slices"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// defaultSchema is the schema used for the tables in a publication
+// which are not schema-qualified
+const defaultSchema = "public"
+
+// DatabasePublication is the representation of a publication
+// as it is stored in the PostgreSQL catalog
+type DatabasePublication struct {
+	Name      string
+	AllTables bool
+	// Tables is the sorted list of the schema-qualified tables
+	// in the publication
+	Tables  []string
+	Publish []apiv1.PublicationOperation
+}
+
+// listPublications returns the publications defined in the database
+// the passed connection is pointing to
+func listPublications(ctx context.Context, db *sql.DB) (map[string]DatabasePublication, error) {
+	logger := log.FromContext(ctx).WithName("publications_reconciler")
+	wrapErr := func(err error) error { return fmt.Errorf("while listing publications: %w", err) }
+
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT p.pubname, p.puballtables, p.pubinsert, p.pubupdate, p.pubdelete, p.pubtruncate,
+			coalesce(
+				array_agg(pt.schemaname || '.' || pt.tablename) FILTER (WHERE pt.tablename IS NOT NULL),
+				'{}') AS tables
+		FROM pg_catalog.pg_publication p
+		LEFT JOIN pg_catalog.pg_publication_tables pt ON p.pubname = pt.pubname AND NOT p.puballtables
+		GROUP BY p.pubname, p.puballtables, p.pubinsert, p.pubupdate, p.pubdelete, p.pubtruncate`)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Info("Ignorable error while querying pg_catalog.pg_publication", "err", err)
+		}
+	}()
+
+	publications := make(map[string]DatabasePublication)
+	for rows.Next() {
+		var publication DatabasePublication
+		var pubInsert, pubUpdate, pubDelete, pubTruncate bool
+		var tables pq.StringArray
+		if err := rows.Scan(
+			&publication.Name,
+			&publication.AllTables,
+			&pubInsert,
+			&pubUpdate,
+			&pubDelete,
+			&pubTruncate,
+			&tables,
+		); err != nil {
+			return nil, wrapErr(err)
+		}
+
+		for operation, enabled := range map[apiv1.PublicationOperation]bool{
+			apiv1.PublicationOperationInsert:   pubInsert,
+			apiv1.PublicationOperationUpdate:   pubUpdate,
+			apiv1.PublicationOperationDelete:   pubDelete,
+			apiv1.PublicationOperationTruncate: pubTruncate,
+		} {
+			if enabled {
+				publication.Publish = append(publication.Publish, operation)
+			}
+		}
+		publication.Publish = sortedOperations(publication.Publish)
+		publication.Tables = sortedTables(tables)
+		publications[publication.Name] = publication
+	}
+
+	if rows.Err() != nil {
+		return nil, wrapErr(rows.Err())
+	}
+
+	return publications, nil
+}
+
+// createPublication creates the publication in the database
+func createPublication(ctx context.Context, db *sql.DB, publication apiv1.PublicationConfiguration) error {
+	contextLog := log.FromContext(ctx).WithName("publications_reconciler")
+
+	var query strings.Builder
+	query.WriteString(fmt.Sprintf("CREATE PUBLICATION %s ", pgx.Identifier{publication.Name}.Sanitize()))
+	if publication.AllTables {
+		query.WriteString("FOR ALL TABLES ")
+	} else {
+		query.WriteString(fmt.Sprintf("FOR TABLE %s ", sanitizeTables(publication.Tables)))
+	}
+	query.WriteString(fmt.Sprintf("WITH (publish = %s)", publishOption(publication.GetPublish())))
+
+	contextLog.Info("Creating publication", "publication", publication.Name, "query", query.String())
+	if _, err := db.ExecContext(ctx, query.String()); err != nil {
+		return fmt.Errorf("while creating publication %s: %w", publication.Name, err)
+	}
+	return nil
+}
+
+// updatePublication aligns the list of tables and the published operations
+// of an existing publication to the spec. Changing a publication
+// from/to `FOR ALL TABLES` is not supported by PostgreSQL, and needs
+// the publication to be dropped and recreated
+func updatePublication(ctx context.Context, db *sql.DB, publication apiv1.PublicationConfiguration) error {
+	contextLog := log.FromContext(ctx).WithName("publications_reconciler")
+	wrapErr := func(err error) error {
+		return fmt.Errorf("while updating publication %s: %w", publication.Name, err)
+	}
+	name := pgx.Identifier{publication.Name}.Sanitize()
+
+	var queries []string
+	if !publication.AllTables {
+		queries = append(queries, fmt.Sprintf("ALTER PUBLICATION %s SET TABLE %s",
+			name, sanitizeTables(publication.Tables)))
+	}
+	queries = append(queries, fmt.Sprintf("ALTER PUBLICATION %s SET (publish = %s)",
+		name, publishOption(publication.GetPublish())))
+
+	for _, query := range queries {
+		contextLog.Info("Updating publication", "publication", publication.Name, "query", query)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return wrapErr(err)
+		}
+	}
+	return nil
+}
+
+// dropPublication drops the publication from the database
+func dropPublication(ctx context.Context, db *sql.DB, name string) error {
+	contextLog := log.FromContext(ctx).WithName("publications_reconciler")
+	query := fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pgx.Identifier{name}.Sanitize())
+
+	contextLog.Info("Dropping publication", "publication", name, "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping publication %s: %w", name, err)
+	}
+	return nil
+}
+
+// getWalLevel returns the value of `wal_level` the instance is running with
+func getWalLevel(ctx context.Context, db *sql.DB) (string, error) {
+	var walLevel string
+	row := db.QueryRowContext(ctx, "SELECT pg_catalog.current_setting('wal_level')")
+	if err := row.Scan(&walLevel); err != nil {
+		return "", fmt.Errorf("while reading wal_level: %w", err)
+	}
+	return walLevel, nil
+}
+
+// isInSync checks if the publication in the database matches the spec
+func isInSync(publication apiv1.PublicationConfiguration, inDB DatabasePublication) bool {
+	if publication.AllTables != inDB.AllTables {
+		return false
+	}
+	if !publication.AllTables && !slices.Equal(sortedTables(publication.Tables), inDB.Tables) {
+		return false
+	}
+
+	publish := make([]string, 0, len(inDB.Publish))
+	for _, operation := range inDB.Publish {
+		publish = append(publish, string(operation))
+	}
+	wantedPublish := make([]string, 0, len(inDB.Publish))
+	for _, operation := range sortedOperations(publication.GetPublish()) {
+		wantedPublish = append(wantedPublish, string(operation))
+	}
+	return slices.Equal(wantedPublish, publish)
+}
+
+// qualifyTableName adds the default schema to a table name, unless
+// it is already schema-qualified
+func qualifyTableName(table string) string {
+	if strings.Contains(table, ".") {
+		return table
+	}
+	return defaultSchema + "." + table
+}
+
+// sanitizeTables returns the comma separated list of the passed tables,
+// quoting every part of the table names
+func sanitizeTables(tables []string) string {
+	sanitized := make([]string, len(tables))
+	for i, table := range tables {
+		sanitized[i] = pgx.Identifier(strings.SplitN(qualifyTableName(table), ".", 2)).Sanitize()
+	}
+	return strings.Join(sanitized, ", ")
+}
+
+// publishOption returns the value of the `publish` option
+// for the given operations
+func publishOption(operations []apiv1.PublicationOperation) string {
+	values := make([]string, len(operations))
+	for i, operation := range operations {
+		values[i] = string(operation)
+	}
+	return pq.QuoteLiteral(strings.Join(values, ", "))
+}
+
+func sortedTables(tables []string) []string {
+	result := make([]string, len(tables))
+	for i, table := range tables {
+		result[i] = qualifyTableName(table)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedOperations(operations []apiv1.PublicationOperation) []apiv1.PublicationOperation {
+	result := slices.Clone(operations)
+	slices.Sort(result)
+	return result
+}
@@ -0,0 +1,95 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("publication comparison", func() {
+	allOperations := []apiv1.PublicationOperation{
+		apiv1.PublicationOperationDelete,
+		apiv1.PublicationOperationInsert,
+		apiv1.PublicationOperationTruncate,
+		apiv1.PublicationOperationUpdate,
+	}
+
+	It("considers unqualified tables as part of the public schema", func() {
+		Expect(isInSync(
+			apiv1.PublicationConfiguration{
+				Name:   "pub",
+				Tables: []string{"orders", "sales.customers"},
+			},
+			DatabasePublication{
+				Name:    "pub",
+				Tables:  []string{"public.orders", "sales.customers"},
+				Publish: allOperations,
+			},
+		)).To(BeTrue())
+	})
+
+	It("detects a change in the list of tables", func() {
+		Expect(isInSync(
+			apiv1.PublicationConfiguration{
+				Name:   "pub",
+				Tables: []string{"orders"},
+			},
+			DatabasePublication{
+				Name:    "pub",
+				Tables:  []string{"public.orders", "public.customers"},
+				Publish: allOperations,
+			},
+		)).To(BeFalse())
+	})
+
+	It("detects a change in the published operations", func() {
+		Expect(isInSync(
+			apiv1.PublicationConfiguration{
+				Name:      "pub",
+				AllTables: true,
+				Publish:   []apiv1.PublicationOperation{apiv1.PublicationOperationInsert},
+			},
+			DatabasePublication{
+				Name:      "pub",
+				AllTables: true,
+				Publish:   allOperations,
+			},
+		)).To(BeFalse())
+	})
+
+	It("ignores the tables of a publication for all tables", func() {
+		Expect(isInSync(
+			apiv1.PublicationConfiguration{
+				Name:      "pub",
+				AllTables: true,
+			},
+			DatabasePublication{
+				Name:      "pub",
+				AllTables: true,
+				Publish:   allOperations,
+			},
+		)).To(BeTrue())
+	})
+
+	It("quotes the table names", func() {
+		Expect(sanitizeTables([]string{"orders", `my"schema.Customers`})).
+			To(Equal(`"public"."orders", "my""schema"."Customers"`))
+	})
+})
@@ -0,0 +1,183 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// requiredWalLevel is the `wal_level` needed by logical replication
+const requiredWalLevel = "logical"
+
+// Reconcile applies the managed publications to the databases of the
+// primary instance, and updates their status into the cluster Status
+func Reconcile(
+	ctx context.Context,
+	instance *postgres.Instance,
+	cluster *apiv1.Cluster,
+	c client.Client,
+) (reconcile.Result, error) {
+	if !cluster.ContainsManagedPublicationsConfiguration() {
+		return reconcile.Result{}, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Reconciling managed publications")
+
+	status, err := synchronizePublications(ctx, instance.ConnectionPool(), cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if reflect.DeepEqual(status, cluster.Status.ManagedPublicationsStatus) {
+		return reconcile.Result{}, nil
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.ManagedPublicationsStatus = status
+	return reconcile.Result{}, c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster))
+}
+
+// synchronizePublications aligns the publications in the databases to the spec,
+// returning their status.
+//
+// NOTE: synchronizePublications will not error out if a single publication
+// cannot be applied by PostgreSQL, so that a wrong publication cannot prevent
+// the other ones from being applied
+func synchronizePublications(
+	ctx context.Context,
+	pooler pool.Pooler,
+	cluster *apiv1.Cluster,
+) (apiv1.ManagedPublications, error) {
+	contextLogger := log.FromContext(ctx).WithName("publications_reconciler")
+
+	var status apiv1.ManagedPublications
+
+	superUserDB, err := pooler.Connection(ctx, "postgres")
+	if err != nil {
+		return status, fmt.Errorf("while connecting to the postgres database: %w", err)
+	}
+	walLevel, err := getWalLevel(ctx, superUserDB)
+	if err != nil {
+		return status, err
+	}
+	if walLevel != requiredWalLevel {
+		// wal_level is a mandatory setting, so the configuration file already
+		// contains the right value: we just need the instance to be restarted
+		contextLogger.Info("Skipping publications reconciliation, the instance needs to be "+
+			"restarted to apply the required wal_level",
+			"currentWalLevel", walLevel, "requiredWalLevel", requiredWalLevel)
+		status.WalLevelRestartRequired = true
+		return status, nil
+	}
+
+	publicationsByDB := make(map[string][]apiv1.PublicationConfiguration)
+	var dbNames []string
+	for _, publication := range cluster.Spec.Managed.Publications {
+		dbName := publication.GetDBName(cluster.GetApplicationDatabaseName())
+		if _, found := publicationsByDB[dbName]; !found {
+			dbNames = append(dbNames, dbName)
+		}
+		publicationsByDB[dbName] = append(publicationsByDB[dbName], publication)
+	}
+
+	for _, dbName := range dbNames {
+		db, err := pooler.Connection(ctx, dbName)
+		if err != nil {
+			return status, fmt.Errorf("while connecting to database %s: %w", dbName, err)
+		}
+
+		publicationsInDB, err := listPublications(ctx, db)
+		if err != nil {
+			return status, fmt.Errorf("in database %s: %w", dbName, err)
+		}
+
+		for _, publication := range publicationsByDB[dbName] {
+			err := reconcilePublication(ctx, db, publication, publicationsInDB)
+			var pgErr *pgconn.PgError
+			switch {
+			case err == nil:
+				if publication.Ensure != apiv1.EnsureAbsent {
+					if status.Reconciled == nil {
+						status.Reconciled = make(map[string][]string)
+					}
+					status.Reconciled[dbName] = append(status.Reconciled[dbName], publication.Name)
+				}
+			case errors.As(err, &pgErr):
+				// this is an expectable error, i.e. a missing table,
+				// that needs to be fixed in the spec
+				if status.CannotReconcile == nil {
+					status.CannotReconcile = make(map[string][]string)
+				}
+				status.CannotReconcile[publication.Name] = append(
+					status.CannotReconcile[publication.Name],
+					fmt.Sprintf("in database %s: %s", dbName, pgErr.Message))
+			default:
+				return status, err
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// reconcilePublication applies the needed changes to a single publication
+func reconcilePublication(
+	ctx context.Context,
+	db *sql.DB,
+	publication apiv1.PublicationConfiguration,
+	publicationsInDB map[string]DatabasePublication,
+) error {
+	inDB, found := publicationsInDB[publication.Name]
+
+	switch {
+	case publication.Ensure == apiv1.EnsureAbsent:
+		if !found {
+			return nil
+		}
+		return dropPublication(ctx, db, publication.Name)
+
+	case !found:
+		return createPublication(ctx, db, publication)
+
+	case isInSync(publication, inDB):
+		return nil
+
+	case publication.AllTables != inDB.AllTables:
+		// PostgreSQL cannot change a publication from/to FOR ALL TABLES
+		if err := dropPublication(ctx, db, publication.Name); err != nil {
+			return err
+		}
+		return createPublication(ctx, db, publication)
+
+	default:
+		return updatePublication(ctx, db, publication)
+	}
+}
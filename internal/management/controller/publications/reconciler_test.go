@@ -0,0 +1,198 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publications
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool/pooltest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("publications reconciler", func() {
+	const (
+		walLevelQuery        = "SELECT pg_catalog.current_setting('wal_level')"
+		listPublicationQuery = "FROM pg_catalog.pg_publication p"
+	)
+
+	var (
+		superUserDB   *sql.DB
+		superUserMock sqlmock.Sqlmock
+		appDB         *sql.DB
+		appMock       sqlmock.Sqlmock
+		pooler        pooltest.FakePooler
+		cluster       *apiv1.Cluster
+	)
+
+	publicationColumns := []string{
+		"pubname", "puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate", "tables",
+	}
+
+	BeforeEach(func() {
+		var err error
+		superUserDB, superUserMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		appDB, appMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		pooler = pooltest.FakePooler{Databases: map[string]*sql.DB{
+			"postgres": superUserDB,
+			"app":      appDB,
+		}}
+
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app"},
+				},
+				Managed: &apiv1.ManagedConfiguration{
+					Publications: []apiv1.PublicationConfiguration{
+						{
+							Name:   "orders_pub",
+							Tables: []string{"orders", "sales.customers"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(superUserMock.ExpectationsWereMet()).To(Succeed())
+		Expect(appMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectWalLevel := func(walLevel string) {
+		superUserMock.ExpectQuery(regexp.QuoteMeta(walLevelQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow(walLevel))
+	}
+
+	It("flags the required restart when wal_level is not logical", func() {
+		expectWalLevel("replica")
+
+		status, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.WalLevelRestartRequired).To(BeTrue())
+		Expect(status.Reconciled).To(BeEmpty())
+	})
+
+	It("creates a missing publication", func() {
+		expectWalLevel("logical")
+		appMock.ExpectQuery(listPublicationQuery).
+			WillReturnRows(sqlmock.NewRows(publicationColumns))
+		appMock.ExpectExec(regexp.QuoteMeta(
+			`CREATE PUBLICATION "orders_pub" FOR TABLE "public"."orders", "sales"."customers" ` +
+				`WITH (publish = 'insert, update, delete, truncate')`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.WalLevelRestartRequired).To(BeFalse())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders_pub"}))
+		Expect(status.CannotReconcile).To(BeEmpty())
+	})
+
+	It("does nothing when the publication is already in sync", func() {
+		expectWalLevel("logical")
+		appMock.ExpectQuery(listPublicationQuery).
+			WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(
+				"orders_pub", false, true, true, true, true,
+				pq.StringArray{"sales.customers", "public.orders"}))
+
+		status, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders_pub"}))
+	})
+
+	It("alters a publication whose tables and operations changed", func() {
+		cluster.Spec.Managed.Publications[0].Publish = []apiv1.PublicationOperation{
+			apiv1.PublicationOperationInsert,
+		}
+
+		expectWalLevel("logical")
+		appMock.ExpectQuery(listPublicationQuery).
+			WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(
+				"orders_pub", false, true, true, true, true,
+				pq.StringArray{"public.orders"}))
+		appMock.ExpectExec(regexp.QuoteMeta(
+			`ALTER PUBLICATION "orders_pub" SET TABLE "public"."orders", "sales"."customers"`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		appMock.ExpectExec(regexp.QuoteMeta(
+			`ALTER PUBLICATION "orders_pub" SET (publish = 'insert')`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders_pub"}))
+	})
+
+	It("recreates a publication switching to all tables", func() {
+		cluster.Spec.Managed.Publications[0].Tables = nil
+		cluster.Spec.Managed.Publications[0].AllTables = true
+
+		expectWalLevel("logical")
+		appMock.ExpectQuery(listPublicationQuery).
+			WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(
+				"orders_pub", false, true, true, true, true,
+				pq.StringArray{"public.orders"}))
+		appMock.ExpectExec(regexp.QuoteMeta(`DROP PUBLICATION IF EXISTS "orders_pub"`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		appMock.ExpectExec(regexp.QuoteMeta(
+			`CREATE PUBLICATION "orders_pub" FOR ALL TABLES WITH (publish = 'insert, update, delete, truncate')`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("drops a publication marked as absent", func() {
+		cluster.Spec.Managed.Publications[0].Ensure = apiv1.EnsureAbsent
+
+		expectWalLevel("logical")
+		appMock.ExpectQuery(listPublicationQuery).
+			WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(
+				"orders_pub", true, true, true, true, true, pq.StringArray{}))
+		appMock.ExpectExec(regexp.QuoteMeta(`DROP PUBLICATION IF EXISTS "orders_pub"`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(BeEmpty())
+	})
+
+	It("reports the publications that PostgreSQL cannot apply", func() {
+		expectWalLevel("logical")
+		appMock.ExpectQuery(listPublicationQuery).
+			WillReturnRows(sqlmock.NewRows(publicationColumns))
+		appMock.ExpectExec("CREATE PUBLICATION").
+			WillReturnError(&pgconn.PgError{Code: "42P01", Message: `relation "public.orders" does not exist`})
+
+		status, err := synchronizePublications(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(BeEmpty())
+		Expect(status.CannotReconcile).To(HaveKeyWithValue("orders_pub",
+			[]string{`in database app: relation "public.orders" does not exist`}))
+	})
+})
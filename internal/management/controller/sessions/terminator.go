@@ -0,0 +1,174 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkInterval is how often the instance manager looks for
+// sessions idle in transaction
+const checkInterval = 10 * time.Second
+
+// terminateIdleInTransactionQuery terminates the client sessions that have been
+// idle in transaction for more than the given number of seconds
+const terminateIdleInTransactionQuery = `SELECT pid,
+  coalesce(datname, '') AS datname,
+  coalesce(usename, '') AS usename,
+  pg_catalog.pg_terminate_backend(pid) AS terminated
+FROM pg_catalog.pg_stat_activity
+WHERE backend_type = 'client backend'
+  AND state IN ('idle in transaction', 'idle in transaction (aborted)')
+  AND pid <> pg_catalog.pg_backend_pid()
+  AND state_change < pg_catalog.now() - $1 * interval '1 second'`
+
+// terminatedSession describes a session that has been terminated
+type terminatedSession struct {
+	pid      int
+	database string
+	user     string
+}
+
+// An IdleInTransactionTerminator is a Kubernetes manager.Runnable that
+// terminates the client sessions that are idle in transaction for longer
+// than `.spec.postgresql.idleInTransactionTimeout`
+type IdleInTransactionTerminator struct {
+	instance *postgres.Instance
+}
+
+// NewIdleInTransactionTerminator creates a new IdleInTransactionTerminator
+func NewIdleInTransactionTerminator(instance *postgres.Instance) *IdleInTransactionTerminator {
+	return &IdleInTransactionTerminator{
+		instance: instance,
+	}
+}
+
+// Start starts running the IdleInTransactionTerminator
+func (t *IdleInTransactionTerminator) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("idle_in_transaction_terminator")
+	ticker := time.NewTicker(checkInterval)
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated idle in transaction sessions terminator loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := t.reconcile(ctx); err != nil {
+			contextLog.Warning("terminating idle in transaction sessions", "err", err)
+		}
+	}
+}
+
+func (t *IdleInTransactionTerminator) reconcile(ctx context.Context) error {
+	if t.instance.IsFenced() || t.instance.MightBeUnavailable() {
+		return nil
+	}
+
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	timeout := getIdleInTransactionTimeout(cluster)
+	if timeout == 0 {
+		return nil
+	}
+
+	db, err := t.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := terminateIdleInTransactionSessions(ctx, db, timeout)
+	if err != nil {
+		return err
+	}
+
+	contextLog := log.FromContext(ctx)
+	for _, session := range sessions {
+		contextLog.Info("Terminated session idle in transaction",
+			"pid", session.pid,
+			"database", session.database,
+			"user", session.user,
+			"timeout", timeout)
+	}
+
+	return nil
+}
+
+// getIdleInTransactionTimeout gets the configured timeout, 0 if the
+// termination of idle in transaction sessions is disabled
+func getIdleInTransactionTimeout(cluster *apiv1.Cluster) time.Duration {
+	if cluster == nil || cluster.Spec.PostgresConfiguration.IdleInTransactionTimeout <= 0 {
+		return 0
+	}
+
+	return time.Duration(cluster.Spec.PostgresConfiguration.IdleInTransactionTimeout) * time.Second
+}
+
+// terminateIdleInTransactionSessions terminates the client sessions that have
+// been idle in transaction for longer than timeout, returning the ones
+// that have been terminated
+func terminateIdleInTransactionSessions(
+	ctx context.Context,
+	db *sql.DB,
+	timeout time.Duration,
+) ([]terminatedSession, error) {
+	rows, err := db.QueryContext(ctx, terminateIdleInTransactionQuery, int(timeout.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("while terminating idle in transaction sessions: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.FromContext(ctx).Error(err, "while closing rows for terminateIdleInTransactionSessions")
+		}
+	}()
+
+	var result []terminatedSession
+	for rows.Next() {
+		var session terminatedSession
+		var terminated bool
+		if err := rows.Scan(&session.pid, &session.database, &session.user, &terminated); err != nil {
+			return nil, err
+		}
+
+		// the session may have ended by itself in the meantime
+		if terminated {
+			result = append(result, session)
+		}
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sessions
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("idle in transaction timeout", func() {
+	It("is disabled when there is no cluster", func() {
+		Expect(getIdleInTransactionTimeout(nil)).To(BeZero())
+	})
+
+	It("is disabled by default", func() {
+		Expect(getIdleInTransactionTimeout(&apiv1.Cluster{})).To(BeZero())
+	})
+
+	It("uses the configured number of seconds", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					IdleInTransactionTimeout: 300,
+				},
+			},
+		}
+		Expect(getIdleInTransactionTimeout(cluster)).To(Equal(5 * time.Minute))
+	})
+})
+
+var _ = Describe("idle in transaction sessions termination", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("terminates the sessions idle in transaction for longer than the timeout", func(ctx SpecContext) {
+		mock.ExpectQuery(terminateIdleInTransactionQuery).
+			WithArgs(300).
+			WillReturnRows(sqlmock.NewRows([]string{"pid", "datname", "usename", "terminated"}).
+				AddRow(42, "app", "app", true).
+				AddRow(43, "app", "app", false))
+
+		sessions, err := terminateIdleInTransactionSessions(ctx, db, 5*time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sessions).To(ConsistOf(terminatedSession{pid: 42, database: "app", user: "app"}))
+	})
+
+	It("doesn't report anything when there are no such sessions", func(ctx SpecContext) {
+		mock.ExpectQuery(terminateIdleInTransactionQuery).
+			WithArgs(60).
+			WillReturnRows(sqlmock.NewRows([]string{"pid", "datname", "usename", "terminated"}))
+
+		sessions, err := terminateIdleInTransactionSessions(ctx, db, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sessions).To(BeEmpty())
+	})
+
+	It("reports the errors", func(ctx SpecContext) {
+		expectedErr := errors.New("connection refused")
+		mock.ExpectQuery(terminateIdleInTransactionQuery).
+			WithArgs(60).
+			WillReturnError(expectedErr)
+
+		_, err := terminateIdleInTransactionSessions(ctx, db, time.Minute)
+		Expect(err).To(MatchError(expectedErr))
+	})
+})
@@ -45,7 +45,7 @@ func (sm PostgresManager) List(
 	ctx context.Context,
 	config *v1.ReplicationSlotsConfiguration,
 ) (ReplicationSlotList, error) {
-	db, err := sm.pool.Connection("postgres")
+	db, err := sm.pool.Connection(ctx, "postgres")
 	if err != nil {
 		return ReplicationSlotList{}, err
 	}
@@ -93,7 +93,7 @@ func (sm PostgresManager) Update(ctx context.Context, slot ReplicationSlot) erro
 	if slot.RestartLSN == "" {
 		return nil
 	}
-	db, err := sm.pool.Connection("postgres")
+	db, err := sm.pool.Connection(ctx, "postgres")
 	if err != nil {
 		return err
 	}
@@ -107,7 +107,7 @@ func (sm PostgresManager) Create(ctx context.Context, slot ReplicationSlot) erro
 	contextLog := log.FromContext(ctx).WithName("createSlot")
 	contextLog.Trace("Invoked", "slot", slot)
 
-	db, err := sm.pool.Connection("postgres")
+	db, err := sm.pool.Connection(ctx, "postgres")
 	if err != nil {
 		return err
 	}
@@ -125,7 +125,7 @@ func (sm PostgresManager) Delete(ctx context.Context, slot ReplicationSlot) erro
 		return nil
 	}
 
-	db, err := sm.pool.Connection("postgres")
+	db, err := sm.pool.Connection(ctx, "postgres")
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -35,7 +36,7 @@ type mockPooler struct {
 	db *sql.DB
 }
 
-func (mp *mockPooler) Connection(_ string) (*sql.DB, error) {
+func (mp *mockPooler) Connection(_ context.Context, _ string) (*sql.DB, error) {
 	if mp.db == nil {
 		return nil, errors.New("connection error")
 	}
@@ -0,0 +1,254 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqljobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// databasesQuery lists the databases where a job is executed when
+// they are not specified
+const databasesQuery = `SELECT datname FROM pg_catalog.pg_database
+WHERE datallowconn AND NOT datistemplate
+ORDER BY datname`
+
+// redactedLiteral replaces the content of the string literals
+const redactedLiteral = "***"
+
+// executeJob executes the script of a job in its databases, one after the
+// other, following the error policy when it fails in one of them
+func executeJob(
+	ctx context.Context,
+	pooler pool.Pooler,
+	job apiv1.SQLJobConfiguration,
+	script string,
+) (apiv1.SQLJobStatus, error) {
+	contextLogger := log.FromContext(ctx).WithValues("job", job.Name)
+
+	databases := job.Databases
+	if len(databases) == 0 {
+		var err error
+		if databases, err = getDatabases(ctx, pooler); err != nil {
+			return apiv1.SQLJobStatus{}, err
+		}
+	}
+
+	status := apiv1.SQLJobStatus{Phase: apiv1.SQLJobPhaseCompleted}
+	for idx, dbName := range databases {
+		if job.SecretRef != nil {
+			contextLogger.Info("Executing the SQL job", "database", dbName)
+		} else {
+			contextLogger.Info("Executing the SQL job", "database", dbName, "sql", redactSQL(script))
+		}
+
+		if err := executeScript(ctx, pooler, dbName, script, job.GetTimeout()); err != nil {
+			contextLogger.Warning("The SQL job failed", "database", dbName, "err", err)
+			status.Phase = apiv1.SQLJobPhaseFailed
+			if status.Failed == nil {
+				status.Failed = make(map[string]string)
+			}
+			status.Failed[dbName] = err.Error()
+
+			if job.GetOnError() == apiv1.SQLJobErrorPolicyStop {
+				status.Skipped = append(status.Skipped, databases[idx+1:]...)
+				break
+			}
+			continue
+		}
+
+		status.Succeeded = append(status.Succeeded, dbName)
+	}
+
+	completedAt := metav1.Now()
+	status.CompletedAt = &completedAt
+	return status, nil
+}
+
+// executeScript executes a script in a database, canceling it when it
+// doesn't complete within the timeout. The errors raised by PostgreSQL
+// are reported without their details, as they may contain the sensitive
+// data of the script
+func executeScript(
+	ctx context.Context,
+	pooler pool.Pooler,
+	dbName string,
+	script string,
+	timeout time.Duration,
+) error {
+	db, err := pooler.Connection(ctx, dbName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", dbName, err)
+	}
+
+	scriptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err = db.ExecContext(scriptCtx, script)
+	if err != nil && errors.Is(scriptCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("the script did not complete within %s and has been canceled", timeout)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return errors.New(pgErr.Message)
+	}
+	return err
+}
+
+// getDatabases returns the databases accepting connections, except
+// the templates
+func getDatabases(ctx context.Context, pooler pool.Pooler) ([]string, error) {
+	db, err := pooler.Connection(ctx, "postgres")
+	if err != nil {
+		return nil, fmt.Errorf("while connecting to database postgres: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, databasesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("while listing the databases: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var dbName string
+		if err := rows.Scan(&dbName); err != nil {
+			return nil, fmt.Errorf("while listing the databases: %w", err)
+		}
+		databases = append(databases, dbName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while listing the databases: %w", err)
+	}
+
+	return databases, nil
+}
+
+// redactSQL replaces the content of the string literals of a script,
+// where passwords and other secrets are found, with a placeholder. The
+// quoted literals, the ones with C-style escapes (i.e. `E'...'`) and
+// the dollar-quoted ones (i.e. `$$...$$` or `$tag$...$tag$`) are
+// recognized. An unterminated literal extends to the end of the script
+func redactSQL(script string) string {
+	var result strings.Builder
+	for idx := 0; idx < len(script); {
+		switch {
+		case script[idx] == '"':
+			// quoted identifiers are kept, but their content is
+			// not mistaken for the start of a literal
+			end := findQuoteEnd(script, idx+1, '"', false)
+			result.WriteString(script[idx:end])
+			idx = end
+
+		case script[idx] == '\'':
+			end := findQuoteEnd(script, idx+1, '\'', hasEscapePrefix(script, idx))
+			result.WriteString("'" + redactedLiteral + "'")
+			idx = end
+
+		case script[idx] == '$' && (idx == 0 || !isIdentifierChar(script[idx-1])):
+			tag, ok := getDollarQuoteTag(script[idx:])
+			if !ok {
+				result.WriteByte(script[idx])
+				idx++
+				continue
+			}
+			end := len(script)
+			if pos := strings.Index(script[idx+len(tag):], tag); pos >= 0 {
+				end = idx + len(tag) + pos + len(tag)
+			}
+			result.WriteString(tag + redactedLiteral + tag)
+			idx = end
+
+		default:
+			result.WriteByte(script[idx])
+			idx++
+		}
+	}
+
+	return result.String()
+}
+
+// findQuoteEnd returns the position following the quote closing the
+// literal starting at the passed position, or the length of the script
+// if the literal is unterminated. Doubled quotes are part of the literal,
+// and so are the quotes escaped by a backslash when escapes are allowed
+func findQuoteEnd(script string, start int, quote byte, escapes bool) int {
+	for idx := start; idx < len(script); idx++ {
+		switch {
+		case escapes && script[idx] == '\\':
+			idx++
+		case script[idx] != quote:
+		case idx+1 < len(script) && script[idx+1] == quote:
+			idx++
+		default:
+			return idx + 1
+		}
+	}
+
+	return len(script)
+}
+
+// hasEscapePrefix checks whether the literal starting at the passed
+// position is a string constant with C-style escapes, like E'\n'
+func hasEscapePrefix(script string, quotePosition int) bool {
+	if quotePosition == 0 {
+		return false
+	}
+	if prefix := script[quotePosition-1]; prefix != 'e' && prefix != 'E' {
+		return false
+	}
+
+	return quotePosition == 1 || !isIdentifierChar(script[quotePosition-2])
+}
+
+// getDollarQuoteTag gets the tag starting a dollar-quoted literal,
+// including the dollar signs, i.e. `$$` or `$body$`
+func getDollarQuoteTag(script string) (string, bool) {
+	for idx := 1; idx < len(script); idx++ {
+		switch {
+		case script[idx] == '$':
+			return script[:idx+1], true
+		case !isIdentifierChar(script[idx]):
+			return "", false
+		case idx == 1 && script[idx] >= '0' && script[idx] <= '9':
+			// a positional parameter, like $1
+			return "", false
+		}
+	}
+
+	return "", false
+}
+
+// isIdentifierChar checks whether a byte can be part of an unquoted
+// identifier. The bytes of the multibyte characters are accepted
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
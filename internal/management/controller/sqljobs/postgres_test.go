@@ -0,0 +1,227 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqljobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool/pooltest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL jobs execution", func() {
+	const script = "ALTER TABLE orders ADD COLUMN notes text"
+
+	var (
+		mock   sqlmock.Sqlmock
+		pooler pooltest.FakePooler
+	)
+
+	BeforeEach(func() {
+		pooler = pooltest.FakePooler{Databases: make(map[string]*sql.DB)}
+
+		// every database shares the same mock, so that
+		// the execution order can be checked
+		var (
+			db  *sql.DB
+			err error
+		)
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		for _, dbName := range []string{"postgres", "app", "sales", "stock"} {
+			pooler.Databases[dbName] = db
+		}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("executes the script in every database, in the given order", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name:      "add-notes",
+			Databases: []string{"sales", "app"},
+			SQL:       script,
+		}
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Phase).To(Equal(apiv1.SQLJobPhaseCompleted))
+		Expect(status.Succeeded).To(Equal([]string{"sales", "app"}))
+		Expect(status.Failed).To(BeEmpty())
+		Expect(status.Skipped).To(BeEmpty())
+		Expect(status.CompletedAt).ToNot(BeNil())
+	})
+
+	It("executes the script in every database accepting connections when none is specified", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name: "add-notes",
+			SQL:  script,
+		}
+		mock.ExpectQuery(databasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("postgres"))
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Phase).To(Equal(apiv1.SQLJobPhaseCompleted))
+		Expect(status.Succeeded).To(Equal([]string{"app", "postgres"}))
+	})
+
+	It("skips the following databases when the script fails and the policy is stop", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name:      "add-notes",
+			Databases: []string{"app", "sales", "stock"},
+			SQL:       script,
+		}
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(script).WillReturnError(&pgconn.PgError{
+			Message: `relation "orders" does not exist`,
+		})
+
+		status, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Phase).To(Equal(apiv1.SQLJobPhaseFailed))
+		Expect(status.Succeeded).To(Equal([]string{"app"}))
+		Expect(status.Failed).To(Equal(map[string]string{"sales": `relation "orders" does not exist`}))
+		Expect(status.Skipped).To(Equal([]string{"stock"}))
+	})
+
+	It("executes the script in the remaining databases when the policy is continue", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name:      "add-notes",
+			Databases: []string{"app", "sales", "stock"},
+			SQL:       script,
+			OnError:   apiv1.SQLJobErrorPolicyContinue,
+		}
+		mock.ExpectExec(script).WillReturnError(errors.New("connection refused"))
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Phase).To(Equal(apiv1.SQLJobPhaseFailed))
+		Expect(status.Succeeded).To(Equal([]string{"sales", "stock"}))
+		Expect(status.Failed).To(Equal(map[string]string{"app": "connection refused"}))
+		Expect(status.Skipped).To(BeEmpty())
+	})
+
+	It("reports the databases that can't be reached", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name:      "add-notes",
+			Databases: []string{"missing", "app"},
+			SQL:       script,
+			OnError:   apiv1.SQLJobErrorPolicyContinue,
+		}
+		mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Phase).To(Equal(apiv1.SQLJobPhaseFailed))
+		Expect(status.Succeeded).To(Equal([]string{"app"}))
+		Expect(status.Failed).To(HaveKey("missing"))
+	})
+
+	It("cancels the script when it doesn't complete within the timeout", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name:           "add-notes",
+			Databases:      []string{"app", "sales"},
+			SQL:            script,
+			TimeoutSeconds: ptr.To(int32(1)),
+		}
+		mock.ExpectExec(script).WillDelayFor(2 * time.Second).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Phase).To(Equal(apiv1.SQLJobPhaseFailed))
+		Expect(status.Failed).To(Equal(map[string]string{
+			"app": "the script did not complete within 1s and has been canceled",
+		}))
+		Expect(status.Skipped).To(Equal([]string{"sales"}))
+	})
+
+	It("fails when the databases can't be listed", func() {
+		job := apiv1.SQLJobConfiguration{
+			Name: "add-notes",
+			SQL:  script,
+		}
+		mock.ExpectQuery(databasesQuery).WillReturnError(errors.New("connection refused"))
+
+		_, err := executeJob(context.TODO(), pooler, job, script)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("redactSQL", func() {
+	It("redacts the string literals", func() {
+		Expect(redactSQL("ALTER ROLE app PASSWORD 'secret'; SELECT 1")).
+			To(Equal("ALTER ROLE app PASSWORD '***'; SELECT 1"))
+	})
+
+	It("redacts the string literals containing escaped quotes", func() {
+		Expect(redactSQL(`SELECT 'it''s', E'it\'s', 'done'`)).
+			To(Equal("SELECT '***', E'***', '***'"))
+	})
+
+	It("redacts the unterminated string literals", func() {
+		Expect(redactSQL("CREATE USER app PASSWORD 'secret")).
+			To(Equal("CREATE USER app PASSWORD '***'"))
+	})
+
+	It("redacts the literals with C-style escapes", func() {
+		Expect(redactSQL(`ALTER ROLE app PASSWORD E'se\'cret\\'; SELECT 1`)).
+			To(Equal("ALTER ROLE app PASSWORD E'***'; SELECT 1"))
+	})
+
+	It("doesn't treat the backslashes of the standard literals as escapes", func() {
+		Expect(redactSQL(`SELECT 'C:\', 'secret'`)).
+			To(Equal("SELECT '***', '***'"))
+	})
+
+	It("redacts the dollar-quoted literals", func() {
+		Expect(redactSQL("ALTER ROLE app PASSWORD $$se'cret$$; DO $body$ BEGIN PERFORM 'x'; END $body$")).
+			To(Equal("ALTER ROLE app PASSWORD $$***$$; DO $body$***$body$"))
+	})
+
+	It("redacts the unterminated dollar-quoted literals", func() {
+		Expect(redactSQL("CREATE USER app PASSWORD $tag$secret")).
+			To(Equal("CREATE USER app PASSWORD $tag$***$tag$"))
+	})
+
+	It("leaves the positional parameters and the identifiers unchanged", func() {
+		Expect(redactSQL(`PREPARE q AS SELECT $1, a$b$ FROM "it's" WHERE note = 'secret'`)).
+			To(Equal(`PREPARE q AS SELECT $1, a$b$ FROM "it's" WHERE note = '***'`))
+	})
+
+	It("leaves the scripts without string literals unchanged", func() {
+		Expect(redactSQL(`CREATE INDEX ON "orders" (id)`)).
+			To(Equal(`CREATE INDEX ON "orders" (id)`))
+	})
+})
@@ -0,0 +1,209 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqljobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// secretRetryInterval is how long we wait before retrying a job
+// whose script can't be read from its secret yet
+const secretRetryInterval = 10 * time.Second
+
+// errMissingScript is raised when the script of a job can't be
+// found in the referenced secret
+var errMissingScript = errors.New("missing SQL script")
+
+// Reconcile executes the managed SQL jobs that have not been executed yet.
+// A job is marked as running in the cluster Status before being executed,
+// and its outcome is recorded after it. A job found running has been
+// interrupted, and is marked as such without being executed again, so that
+// a job is executed at most once
+func Reconcile(
+	ctx context.Context,
+	instance *postgres.Instance,
+	cluster *apiv1.Cluster,
+	c client.Client,
+) (reconcile.Result, error) {
+	if !cluster.ContainsManagedSQLJobsConfiguration() && len(cluster.Status.ManagedSQLJobsStatus) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Reconciling managed SQL jobs")
+
+	var result reconcile.Result
+	status := getRetainedStatus(cluster)
+	if markInterruptedJobs(status) {
+		contextLogger.Warning("Some SQL jobs have been interrupted and will not be executed again")
+		if err := patchStatus(ctx, c, cluster, status); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	for _, job := range getJobs(cluster) {
+		if _, executed := status[job.Name]; executed {
+			continue
+		}
+
+		script, err := getScript(ctx, c, cluster.Namespace, job)
+		if err != nil {
+			// the secret may be not readable yet, as the role of the
+			// instance has not been updated, so we retry later
+			contextLogger.Warning("Cannot read the script of the SQL job, retrying later",
+				"job", job.Name, "err", err)
+			result.RequeueAfter = secretRetryInterval
+			continue
+		}
+
+		startedAt := metav1.Now()
+		status[job.Name] = apiv1.SQLJobStatus{Phase: apiv1.SQLJobPhaseRunning, StartedAt: &startedAt}
+		if err := patchStatus(ctx, c, cluster, status); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		jobStatus, err := executeJob(ctx, instance.ConnectionPool(), job, script)
+		if err != nil {
+			// the script has not been executed in any database
+			delete(status, job.Name)
+			if patchErr := patchStatus(ctx, c, cluster, status); patchErr != nil {
+				contextLogger.Error(patchErr, "while removing the running marker of the SQL job", "job", job.Name)
+			}
+			return reconcile.Result{}, err
+		}
+		jobStatus.StartedAt = &startedAt
+		status[job.Name] = jobStatus
+
+		if err := patchStatus(ctx, c, cluster, status); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if len(status) != len(cluster.Status.ManagedSQLJobsStatus) {
+		// some jobs have been removed from the spec
+		if err := patchStatus(ctx, c, cluster, status); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// getJobs returns the managed SQL jobs of the cluster
+func getJobs(cluster *apiv1.Cluster) []apiv1.SQLJobConfiguration {
+	if !cluster.ContainsManagedSQLJobsConfiguration() {
+		return nil
+	}
+	return cluster.Spec.Managed.SQLJobs
+}
+
+// getRetainedStatus returns a copy of the status of the jobs that are
+// still in the spec. A job that is removed and added again is executed again
+func getRetainedStatus(cluster *apiv1.Cluster) map[string]apiv1.SQLJobStatus {
+	status := make(map[string]apiv1.SQLJobStatus)
+	for _, job := range getJobs(cluster) {
+		if jobStatus, ok := cluster.Status.ManagedSQLJobsStatus[job.Name]; ok {
+			status[job.Name] = jobStatus
+		}
+	}
+	return status
+}
+
+// markInterruptedJobs marks as interrupted the jobs that were running when
+// the previous execution of the instance manager stopped, or whose outcome
+// couldn't be stored. It returns true if any job has been marked
+func markInterruptedJobs(status map[string]apiv1.SQLJobStatus) bool {
+	marked := false
+	for name, jobStatus := range status {
+		if jobStatus.Phase != apiv1.SQLJobPhaseRunning {
+			continue
+		}
+
+		completedAt := metav1.Now()
+		jobStatus.Phase = apiv1.SQLJobPhaseInterrupted
+		jobStatus.CompletedAt = &completedAt
+		status[name] = jobStatus
+		marked = true
+	}
+	return marked
+}
+
+// patchStatus stores the status of the jobs in the cluster, keeping the
+// passed cluster aligned with the stored one
+func patchStatus(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	status map[string]apiv1.SQLJobStatus,
+) error {
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.ManagedSQLJobsStatus = make(map[string]apiv1.SQLJobStatus, len(status))
+	for name, jobStatus := range status {
+		updatedCluster.Status.ManagedSQLJobsStatus[name] = *jobStatus.DeepCopy()
+	}
+	if len(status) == 0 {
+		updatedCluster.Status.ManagedSQLJobsStatus = nil
+	}
+
+	if err := c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster)); err != nil {
+		return fmt.Errorf("while updating the status of the SQL jobs: %w", err)
+	}
+	cluster.Status.ManagedSQLJobsStatus = updatedCluster.Status.ManagedSQLJobsStatus
+	return nil
+}
+
+// getScript returns the SQL script of a job, reading it from the
+// referenced secret when needed
+func getScript(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	job apiv1.SQLJobConfiguration,
+) (string, error) {
+	if job.SecretRef == nil {
+		return job.SQL, nil
+	}
+
+	var secret corev1.Secret
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: job.SecretRef.Name}, &secret)
+	if apierrs.IsNotFound(err) {
+		return "", fmt.Errorf("%w: secret %s not found", errMissingScript, job.SecretRef.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("while reading secret %s: %w", job.SecretRef.Name, err)
+	}
+
+	value, ok := secret.Data[job.SecretRef.Key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %s not found in secret %s",
+			errMissingScript, job.SecretRef.Key, job.SecretRef.Name)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqljobs
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL jobs reconciler", func() {
+	It("does nothing without SQL jobs", func() {
+		cluster := &apiv1.Cluster{}
+		mockClient := fake.NewClientBuilder().Build()
+
+		result, err := Reconcile(context.TODO(), &postgres.Instance{}, cluster, mockClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeEquivalentTo(reconcile.Result{}))
+	})
+
+	It("doesn't execute the jobs again, and forgets the ones removed from the spec", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					SQLJobs: []apiv1.SQLJobConfiguration{
+						{Name: "executed", SQL: "ANALYZE"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				ManagedSQLJobsStatus: map[string]apiv1.SQLJobStatus{
+					"executed": {Phase: apiv1.SQLJobPhaseCompleted, Succeeded: []string{"app"}},
+					"removed":  {Phase: apiv1.SQLJobPhaseFailed},
+				},
+			},
+		}
+		mockClient := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster.DeepCopy()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+
+		result, err := Reconcile(context.TODO(), &postgres.Instance{}, cluster, mockClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeEquivalentTo(reconcile.Result{}))
+
+		var stored apiv1.Cluster
+		Expect(mockClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		Expect(stored.Status.ManagedSQLJobsStatus).To(HaveLen(1))
+		Expect(stored.Status.ManagedSQLJobsStatus).To(HaveKey("executed"))
+	})
+
+	It("marks the running jobs as interrupted without executing them again", func() {
+		startedAt := metav1.Now()
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					SQLJobs: []apiv1.SQLJobConfiguration{
+						{Name: "migration", SQL: "ALTER TABLE orders ADD COLUMN notes text"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				ManagedSQLJobsStatus: map[string]apiv1.SQLJobStatus{
+					"migration": {Phase: apiv1.SQLJobPhaseRunning, StartedAt: &startedAt},
+				},
+			},
+		}
+		mockClient := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster.DeepCopy()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+
+		result, err := Reconcile(context.TODO(), &postgres.Instance{}, cluster, mockClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeEquivalentTo(reconcile.Result{}))
+
+		var stored apiv1.Cluster
+		Expect(mockClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		Expect(stored.Status.ManagedSQLJobsStatus).To(HaveKey("migration"))
+		jobStatus := stored.Status.ManagedSQLJobsStatus["migration"]
+		Expect(jobStatus.Phase).To(Equal(apiv1.SQLJobPhaseInterrupted))
+		Expect(jobStatus.StartedAt).ToNot(BeNil())
+		Expect(jobStatus.CompletedAt).ToNot(BeNil())
+	})
+
+	It("retries the jobs whose secret can't be read", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					SQLJobs: []apiv1.SQLJobConfiguration{
+						{
+							Name: "migration",
+							SecretRef: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "migration"},
+								Key:                  "script.sql",
+							},
+						},
+					},
+				},
+			},
+		}
+		mockClient := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster.DeepCopy()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+
+		result, err := Reconcile(context.TODO(), &postgres.Instance{}, cluster, mockClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(secretRetryInterval))
+		Expect(cluster.Status.ManagedSQLJobsStatus).To(BeEmpty())
+	})
+})
+
+var _ = Describe("getScript", func() {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "default"},
+		Data: map[string][]byte{
+			"script.sql": []byte("ALTER ROLE app PASSWORD 'secret'"),
+		},
+	}
+
+	newJob := func(key string) apiv1.SQLJobConfiguration {
+		return apiv1.SQLJobConfiguration{
+			Name: "migration",
+			SecretRef: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: "migration"},
+				Key:                  key,
+			},
+		}
+	}
+
+	It("returns the inline script", func() {
+		script, err := getScript(context.TODO(), fake.NewClientBuilder().Build(), "default",
+			apiv1.SQLJobConfiguration{Name: "inline", SQL: "ANALYZE"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(script).To(Equal("ANALYZE"))
+	})
+
+	It("reads the script from the secret", func() {
+		mockClient := fake.NewClientBuilder().WithObjects(secret).Build()
+		script, err := getScript(context.TODO(), mockClient, "default", newJob("script.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(script).To(Equal("ALTER ROLE app PASSWORD 'secret'"))
+	})
+
+	It("fails when the key is not in the secret", func() {
+		mockClient := fake.NewClientBuilder().WithObjects(secret).Build()
+		_, err := getScript(context.TODO(), mockClient, "default", newJob("other.sql"))
+		Expect(err).To(MatchError(errMissingScript))
+	})
+
+	It("fails when the secret doesn't exist", func() {
+		_, err := getScript(context.TODO(), fake.NewClientBuilder().Build(), "default", newJob("script.sql"))
+		Expect(err).To(MatchError(errMissingScript))
+	})
+})
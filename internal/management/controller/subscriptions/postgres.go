@@ -0,0 +1,209 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"golang.org/x/exp/slices"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// connectionExceptionClass is the SQLSTATE class raised by PostgreSQL
+// when the publisher cannot be reached
+const connectionExceptionClass = "08"
+
+// redactedPassword replaces the passwords in the connection strings
+// that are written in the logs
+const redactedPassword = "********"
+
+var (
+	// keywordPasswordRegex matches the password in a keyword/value connection
+	// string, as well as in the query parameters of a connection URI
+	keywordPasswordRegex = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|[^\s&]*)`)
+
+	// uriPasswordRegex matches the password in the userinfo of a connection URI
+	uriPasswordRegex = regexp.MustCompile(`^(postgres(?:ql)?://[^:@/]*):[^@/]*@`)
+)
+
+// DatabaseSubscription is the representation of a subscription
+// as it is stored in the PostgreSQL catalog
+type DatabaseSubscription struct {
+	Name             string
+	ConnectionString string
+	Publications     []string
+}
+
+// listSubscriptions returns the subscriptions defined in the database
+// the passed connection is pointing to
+func listSubscriptions(ctx context.Context, db *sql.DB) (map[string]DatabaseSubscription, error) {
+	logger := log.FromContext(ctx).WithName("subscriptions_reconciler")
+	wrapErr := func(err error) error { return fmt.Errorf("while listing subscriptions: %w", err) }
+
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT s.subname, s.subconninfo, s.subpublications
+		FROM pg_catalog.pg_subscription s
+		JOIN pg_catalog.pg_database d ON s.subdbid = d.oid
+		WHERE d.datname = pg_catalog.current_database()`)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Info("Ignorable error while querying pg_catalog.pg_subscription", "err", err)
+		}
+	}()
+
+	subscriptions := make(map[string]DatabaseSubscription)
+	for rows.Next() {
+		var subscription DatabaseSubscription
+		var publications pq.StringArray
+		if err := rows.Scan(
+			&subscription.Name,
+			&subscription.ConnectionString,
+			&publications,
+		); err != nil {
+			return nil, wrapErr(err)
+		}
+		subscription.Publications = publications
+		subscriptions[subscription.Name] = subscription
+	}
+
+	if rows.Err() != nil {
+		return nil, wrapErr(rows.Err())
+	}
+
+	return subscriptions, nil
+}
+
+// createSubscription creates the subscription in the database. This
+// requires the publisher to be reachable
+func createSubscription(
+	ctx context.Context,
+	db *sql.DB,
+	subscription apiv1.SubscriptionConfiguration,
+	connectionString string,
+) error {
+	contextLog := log.FromContext(ctx).WithName("subscriptions_reconciler")
+
+	query := fmt.Sprintf("CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s WITH (copy_data = %t)",
+		pgx.Identifier{subscription.Name}.Sanitize(),
+		pq.QuoteLiteral(connectionString),
+		pgx.Identifier{subscription.PublicationName}.Sanitize(),
+		subscription.GetCopyData())
+
+	contextLog.Info("Creating subscription",
+		"subscription", subscription.Name,
+		"publication", subscription.PublicationName,
+		"connection", redactConnectionString(connectionString))
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while creating subscription %s: %w", subscription.Name, err)
+	}
+	return nil
+}
+
+// updateSubscription aligns the connection string and the publication
+// of an existing subscription to the spec
+func updateSubscription(
+	ctx context.Context,
+	db *sql.DB,
+	subscription apiv1.SubscriptionConfiguration,
+	connectionString string,
+	inDB DatabaseSubscription,
+) error {
+	contextLog := log.FromContext(ctx).WithName("subscriptions_reconciler")
+	wrapErr := func(err error) error {
+		return fmt.Errorf("while updating subscription %s: %w", subscription.Name, err)
+	}
+	name := pgx.Identifier{subscription.Name}.Sanitize()
+
+	if connectionString != inDB.ConnectionString {
+		contextLog.Info("Updating subscription connection",
+			"subscription", subscription.Name,
+			"connection", redactConnectionString(connectionString))
+		query := fmt.Sprintf("ALTER SUBSCRIPTION %s CONNECTION %s", name, pq.QuoteLiteral(connectionString))
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return wrapErr(err)
+		}
+	}
+
+	if !slices.Equal(inDB.Publications, []string{subscription.PublicationName}) {
+		query := fmt.Sprintf("ALTER SUBSCRIPTION %s SET PUBLICATION %s",
+			name, pgx.Identifier{subscription.PublicationName}.Sanitize())
+		contextLog.Info("Updating subscription publication", "subscription", subscription.Name, "query", query)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return wrapErr(err)
+		}
+	}
+	return nil
+}
+
+// dropSubscription drops the subscription from the database
+func dropSubscription(ctx context.Context, db *sql.DB, name string) error {
+	contextLog := log.FromContext(ctx).WithName("subscriptions_reconciler")
+	query := fmt.Sprintf("DROP SUBSCRIPTION IF EXISTS %s", pgx.Identifier{name}.Sanitize())
+
+	contextLog.Info("Dropping subscription", "subscription", name, "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping subscription %s: %w", name, err)
+	}
+	return nil
+}
+
+// isInSync checks if the subscription in the database matches the spec
+func isInSync(
+	subscription apiv1.SubscriptionConfiguration,
+	connectionString string,
+	inDB DatabaseSubscription,
+) bool {
+	return connectionString == inDB.ConnectionString &&
+		slices.Equal(inDB.Publications, []string{subscription.PublicationName})
+}
+
+// isUpstreamUnreachable checks if the error has been raised because
+// PostgreSQL could not connect to the publisher
+func isUpstreamUnreachable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, connectionExceptionClass)
+}
+
+// redactConnectionString hides the password contained in a connection
+// string, either in the keyword/value or in the URI format
+func redactConnectionString(connectionString string) string {
+	redacted := uriPasswordRegex.ReplaceAllString(connectionString, "${1}:"+redactedPassword+"@")
+	return keywordPasswordRegex.ReplaceAllString(redacted, "${1}"+redactedPassword)
+}
+
+// redactMessage hides the password of the connection string
+// whenever it appears in the passed message
+func redactMessage(message, connectionString string) string {
+	if connectionString == "" {
+		return message
+	}
+	return strings.ReplaceAll(message, connectionString, redactConnectionString(connectionString))
+}
@@ -0,0 +1,262 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// unreachableUpstreamRetryInterval is the time after which the reconciliation
+// is retried when a publisher cannot be reached
+const unreachableUpstreamRetryInterval = 30 * time.Second
+
+// errMissingConnectionString is raised when the secret containing
+// the connection string of a subscription, or its key, cannot be found
+var errMissingConnectionString = errors.New("cannot find the connection string")
+
+// Reconcile applies the managed subscriptions to the databases of the
+// primary instance, and updates their status into the cluster Status
+func Reconcile(
+	ctx context.Context,
+	instance *postgres.Instance,
+	cluster *apiv1.Cluster,
+	c client.Client,
+) (reconcile.Result, error) {
+	if !cluster.ContainsManagedSubscriptionsConfiguration() {
+		return reconcile.Result{}, nil
+	}
+
+	return reconcileSubscriptions(ctx, c, instance.ConnectionPool(), cluster)
+}
+
+// reconcileSubscriptions synchronizes the subscriptions, updates the
+// cluster status and requests a new reconciliation loop when a publisher
+// could not be reached
+func reconcileSubscriptions(
+	ctx context.Context,
+	c client.Client,
+	pooler pool.Pooler,
+	cluster *apiv1.Cluster,
+) (reconcile.Result, error) {
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Reconciling managed subscriptions")
+
+	status, unreachable, err := synchronizeSubscriptions(ctx, c, pooler, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !reflect.DeepEqual(status, cluster.Status.ManagedSubscriptionsStatus) {
+		updatedCluster := cluster.DeepCopy()
+		updatedCluster.Status.ManagedSubscriptionsStatus = status
+		if err := c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster)); err != nil {
+			return reconcile.Result{}, err
+		}
+		cluster = updatedCluster
+	}
+
+	if err := conditions.Patch(ctx, c, cluster, buildSubscriptionsCondition(unreachable)); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if len(unreachable) > 0 {
+		contextLogger.Info("Cannot reach the publisher of some subscriptions, will retry",
+			"subscriptions", unreachable, "retryAfter", unreachableUpstreamRetryInterval)
+		return reconcile.Result{RequeueAfter: unreachableUpstreamRetryInterval}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// buildSubscriptionsCondition builds the condition reporting whether
+// the subscriptions have been reconciled
+func buildSubscriptionsCondition(unreachable []string) *metav1.Condition {
+	if len(unreachable) > 0 {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionSubscriptionsReady),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonSubscriptionsUpstreamUnreachable),
+			Message: fmt.Sprintf("Cannot reach the publisher of subscriptions: %s",
+				strings.Join(unreachable, ", ")),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionSubscriptionsReady),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonSubscriptionsReconciled),
+		Message: "Managed subscriptions have been reconciled",
+	}
+}
+
+// synchronizeSubscriptions aligns the subscriptions in the databases to the spec,
+// returning their status and the sorted list of the subscriptions whose
+// publisher could not be reached.
+//
+// NOTE: synchronizeSubscriptions will not error out if a single subscription
+// cannot be applied by PostgreSQL, so that a wrong subscription or an
+// unreachable publisher cannot prevent the other ones from being applied
+func synchronizeSubscriptions(
+	ctx context.Context,
+	c client.Client,
+	pooler pool.Pooler,
+	cluster *apiv1.Cluster,
+) (apiv1.ManagedSubscriptions, []string, error) {
+	var status apiv1.ManagedSubscriptions
+	var unreachable []string
+
+	addCannotReconcile := func(name, message string) {
+		if status.CannotReconcile == nil {
+			status.CannotReconcile = make(map[string][]string)
+		}
+		status.CannotReconcile[name] = append(status.CannotReconcile[name], message)
+	}
+
+	subscriptionsByDB := make(map[string][]apiv1.SubscriptionConfiguration)
+	var dbNames []string
+	for _, subscription := range cluster.Spec.Managed.Subscriptions {
+		dbName := subscription.GetDBName(cluster.GetApplicationDatabaseName())
+		if _, found := subscriptionsByDB[dbName]; !found {
+			dbNames = append(dbNames, dbName)
+		}
+		subscriptionsByDB[dbName] = append(subscriptionsByDB[dbName], subscription)
+	}
+
+	for _, dbName := range dbNames {
+		db, err := pooler.Connection(ctx, dbName)
+		if err != nil {
+			return status, nil, fmt.Errorf("while connecting to database %s: %w", dbName, err)
+		}
+
+		subscriptionsInDB, err := listSubscriptions(ctx, db)
+		if err != nil {
+			return status, nil, fmt.Errorf("in database %s: %w", dbName, err)
+		}
+
+		for _, subscription := range subscriptionsByDB[dbName] {
+			var connectionString string
+			if subscription.Ensure != apiv1.EnsureAbsent {
+				connectionString, err = getConnectionString(ctx, c, cluster.Namespace, subscription.ConnectionSecret)
+				if errors.Is(err, errMissingConnectionString) {
+					addCannotReconcile(subscription.Name, fmt.Sprintf("in database %s: %s", dbName, err))
+					continue
+				}
+				if err != nil {
+					return status, nil, err
+				}
+			}
+
+			err := reconcileSubscription(ctx, db, subscription, connectionString, subscriptionsInDB)
+			var pgErr *pgconn.PgError
+			switch {
+			case err == nil:
+				if subscription.Ensure != apiv1.EnsureAbsent {
+					if status.Reconciled == nil {
+						status.Reconciled = make(map[string][]string)
+					}
+					status.Reconciled[dbName] = append(status.Reconciled[dbName], subscription.Name)
+				}
+			case errors.As(err, &pgErr):
+				// this is an expectable error, i.e. a missing publication
+				// or a publisher that is temporarily unreachable
+				if isUpstreamUnreachable(err) {
+					unreachable = append(unreachable, subscription.Name)
+				}
+				addCannotReconcile(subscription.Name,
+					fmt.Sprintf("in database %s: %s", dbName, redactMessage(pgErr.Message, connectionString)))
+			default:
+				return status, nil, err
+			}
+		}
+	}
+
+	sort.Strings(unreachable)
+	return status, unreachable, nil
+}
+
+// reconcileSubscription applies the needed changes to a single subscription
+func reconcileSubscription(
+	ctx context.Context,
+	db *sql.DB,
+	subscription apiv1.SubscriptionConfiguration,
+	connectionString string,
+	subscriptionsInDB map[string]DatabaseSubscription,
+) error {
+	inDB, found := subscriptionsInDB[subscription.Name]
+
+	switch {
+	case subscription.Ensure == apiv1.EnsureAbsent:
+		if !found {
+			return nil
+		}
+		return dropSubscription(ctx, db, subscription.Name)
+
+	case !found:
+		return createSubscription(ctx, db, subscription, connectionString)
+
+	case isInSync(subscription, connectionString, inDB):
+		return nil
+
+	default:
+		return updateSubscription(ctx, db, subscription, connectionString, inDB)
+	}
+}
+
+// getConnectionString reads the connection string of a subscription
+// from the referenced secret
+func getConnectionString(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	selector apiv1.SecretKeySelector,
+) (string, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, &secret)
+	if apierrs.IsNotFound(err) {
+		return "", fmt.Errorf("%w: secret %s not found", errMissingConnectionString, selector.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("while reading secret %s: %w", selector.Name, err)
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %s not found in secret %s",
+			errMissingConnectionString, selector.Key, selector.Name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
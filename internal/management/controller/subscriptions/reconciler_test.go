@@ -0,0 +1,239 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool/pooltest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("subscriptions reconciler", func() {
+	const (
+		listSubscriptionQuery = "FROM pg_catalog.pg_subscription s"
+		connectionString      = "host=publisher dbname=app user=app password=s3cr3t"
+	)
+
+	var (
+		appDB   *sql.DB
+		appMock sqlmock.Sqlmock
+		pooler  pooltest.FakePooler
+		cluster *apiv1.Cluster
+		cl      client.Client
+	)
+
+	subscriptionColumns := []string{"subname", "subconninfo", "subpublications"}
+
+	BeforeEach(func() {
+		var err error
+		appDB, appMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		pooler = pooltest.FakePooler{Databases: map[string]*sql.DB{
+			"app": appDB,
+		}}
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app"},
+				},
+				Managed: &apiv1.ManagedConfiguration{
+					Subscriptions: []apiv1.SubscriptionConfiguration{
+						{
+							Name: "orders_sub",
+							ConnectionSecret: apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "publisher-conninfo"},
+								Key:                  "conninfo",
+							},
+							PublicationName: "orders_pub",
+						},
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "publisher-conninfo",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"conninfo": []byte(connectionString),
+			},
+		}
+
+		cl = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, secret).
+			WithStatusSubresource(cluster).
+			Build()
+	})
+
+	AfterEach(func() {
+		Expect(appMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates a missing subscription", func() {
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+		appMock.ExpectExec(regexp.QuoteMeta(
+			`CREATE SUBSCRIPTION "orders_sub" CONNECTION 'host=publisher dbname=app user=app password=s3cr3t' ` +
+				`PUBLICATION "orders_pub" WITH (copy_data = true)`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		result, err := reconcileSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+
+		var updatedCluster apiv1.Cluster
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.ManagedSubscriptionsStatus.Reconciled).
+			To(HaveKeyWithValue("app", []string{"orders_sub"}))
+		Expect(updatedCluster.Status.ManagedSubscriptionsStatus.CannotReconcile).To(BeEmpty())
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionSubscriptionsReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("does not copy the existing data when requested", func() {
+		copyData := false
+		cluster.Spec.Managed.Subscriptions[0].CopyData = &copyData
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+		appMock.ExpectExec(regexp.QuoteMeta(`WITH (copy_data = false)`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status, unreachable, err := synchronizeSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unreachable).To(BeEmpty())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders_sub"}))
+	})
+
+	It("does nothing when the subscription is already in sync", func() {
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(
+				"orders_sub", connectionString, pq.StringArray{"orders_pub"}))
+
+		status, unreachable, err := synchronizeSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unreachable).To(BeEmpty())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders_sub"}))
+	})
+
+	It("alters a subscription whose connection and publication changed", func() {
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(
+				"orders_sub", "host=old-publisher", pq.StringArray{"old_pub"}))
+		appMock.ExpectExec(regexp.QuoteMeta(
+			`ALTER SUBSCRIPTION "orders_sub" CONNECTION 'host=publisher dbname=app user=app password=s3cr3t'`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		appMock.ExpectExec(regexp.QuoteMeta(`ALTER SUBSCRIPTION "orders_sub" SET PUBLICATION "orders_pub"`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status, _, err := synchronizeSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders_sub"}))
+	})
+
+	It("drops a subscription marked as absent", func() {
+		cluster.Spec.Managed.Subscriptions[0].Ensure = apiv1.EnsureAbsent
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(
+				"orders_sub", connectionString, pq.StringArray{"orders_pub"}))
+		appMock.ExpectExec(regexp.QuoteMeta(`DROP SUBSCRIPTION IF EXISTS "orders_sub"`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status, _, err := synchronizeSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(BeEmpty())
+	})
+
+	It("reports the subscriptions whose connection secret is missing", func() {
+		cluster.Spec.Managed.Subscriptions[0].ConnectionSecret.Name = "missing"
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+
+		status, unreachable, err := synchronizeSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unreachable).To(BeEmpty())
+		Expect(status.CannotReconcile).To(HaveKey("orders_sub"))
+	})
+
+	It("surfaces an unreachable publisher and retries", func() {
+		appMock.ExpectQuery(listSubscriptionQuery).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+		appMock.ExpectExec("CREATE SUBSCRIPTION").
+			WillReturnError(&pgconn.PgError{
+				Code: "08006",
+				Message: "could not connect to the publisher: " +
+					"invalid connection option in \"" + connectionString + "\"",
+			})
+
+		result, err := reconcileSubscriptions(context.TODO(), cl, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(unreachableUpstreamRetryInterval))
+
+		var updatedCluster apiv1.Cluster
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionSubscriptionsReady))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSubscriptionsUpstreamUnreachable)))
+		Expect(condition.Message).To(ContainSubstring("orders_sub"))
+
+		cannotReconcile := updatedCluster.Status.ManagedSubscriptionsStatus.CannotReconcile
+		Expect(cannotReconcile).To(HaveKey("orders_sub"))
+		Expect(cannotReconcile["orders_sub"][0]).ToNot(ContainSubstring("s3cr3t"))
+
+		By("reconciling again once the publisher is back", func() {
+			appMock.ExpectQuery(listSubscriptionQuery).
+				WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+			appMock.ExpectExec("CREATE SUBSCRIPTION").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			result, err := reconcileSubscriptions(context.TODO(), cl, pooler, &updatedCluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.IsZero()).To(BeTrue())
+
+			Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.ManagedSubscriptionsStatus.CannotReconcile).To(BeEmpty())
+			condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+				string(apiv1.ConditionSubscriptionsReady))
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+})
@@ -0,0 +1,142 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// defaultSchema is the schema of the tables which are not schema-qualified
+const defaultSchema = "public"
+
+// toastPrefix is the prefix of the storage parameters of the TOAST table
+const toastPrefix = "toast."
+
+// storageParametersQuery gets the storage parameters of a table and of its TOAST table
+const storageParametersQuery = `SELECT coalesce(c.reloptions, '{}'), coalesce(t.reloptions, '{}')
+FROM pg_catalog.pg_class c
+LEFT JOIN pg_catalog.pg_class t ON t.oid = c.reltoastrelid
+WHERE c.oid = pg_catalog.to_regclass($1) AND c.relkind = 'r'`
+
+// errTableNotFound is returned when a managed table doesn't exist in the database
+var errTableNotFound = errors.New("table does not exist")
+
+// errInvalidStorageParameter is returned when the autovacuum settings of a
+// managed table contain a storage parameter not related to autovacuum
+var errInvalidStorageParameter = errors.New("not an autovacuum storage parameter")
+
+// reconcileTable aligns the autovacuum storage parameters of a table to the spec
+func reconcileTable(ctx context.Context, db *sql.DB, table apiv1.TableConfiguration) error {
+	contextLog := log.FromContext(ctx).WithName("tables_reconciler")
+	name := sanitizeTableName(table.Name)
+
+	for parameter := range table.AutovacuumSettings {
+		if !apiv1.IsAutovacuumStorageParameter(parameter) {
+			return fmt.Errorf("%w: %s", errInvalidStorageParameter, parameter)
+		}
+	}
+
+	current, err := getAutovacuumSettings(ctx, db, name)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range getAutovacuumStatements(name, current, table.AutovacuumSettings) {
+		contextLog.Info("Updating the storage parameters of a table", "table", table.Name, "query", query)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("while updating the storage parameters of table %s: %w", table.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getAutovacuumSettings returns the autovacuum storage parameters of a
+// table, including the ones of its TOAST table prefixed by `toast.`
+func getAutovacuumSettings(ctx context.Context, db *sql.DB, sanitizedName string) (map[string]string, error) {
+	var options, toastOptions pq.StringArray
+	row := db.QueryRowContext(ctx, storageParametersQuery, sanitizedName)
+	if err := row.Scan(&options, &toastOptions); errors.Is(err, sql.ErrNoRows) {
+		return nil, errTableNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("while reading the storage parameters of table %s: %w", sanitizedName, err)
+	}
+
+	settings := make(map[string]string)
+	addSettings := func(options []string, prefix string) {
+		for _, option := range options {
+			name, value, _ := strings.Cut(option, "=")
+			if apiv1.IsAutovacuumStorageParameter(prefix + name) {
+				settings[prefix+name] = value
+			}
+		}
+	}
+	addSettings(options, "")
+	addSettings(toastOptions, toastPrefix)
+
+	return settings, nil
+}
+
+// getAutovacuumStatements returns the statements needed to align the
+// current autovacuum storage parameters of a table to the desired ones.
+// The current parameters that are not desired anymore are reset
+func getAutovacuumStatements(sanitizedName string, current, desired map[string]string) []string {
+	var toSet, toReset []string
+	for name, value := range desired {
+		if currentValue, found := current[name]; !found || currentValue != value {
+			toSet = append(toSet, fmt.Sprintf("%s = %s", name, pq.QuoteLiteral(value)))
+		}
+	}
+	for name := range current {
+		if _, found := desired[name]; !found {
+			toReset = append(toReset, name)
+		}
+	}
+	sort.Strings(toSet)
+	sort.Strings(toReset)
+
+	var statements []string
+	if len(toSet) > 0 {
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s SET (%s)", sanitizedName, strings.Join(toSet, ", ")))
+	}
+	if len(toReset) > 0 {
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s RESET (%s)", sanitizedName, strings.Join(toReset, ", ")))
+	}
+	return statements
+}
+
+// sanitizeTableName quotes every part of the table name, adding the
+// default schema unless it is already schema-qualified
+func sanitizeTableName(table string) string {
+	if !strings.Contains(table, ".") {
+		table = defaultSchema + "." + table
+	}
+	return pgx.Identifier(strings.SplitN(table, ".", 2)).Sanitize()
+}
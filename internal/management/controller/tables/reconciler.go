@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tables
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// Reconcile applies the storage parameters of the managed tables to the
+// databases of the primary instance, and updates their status into the
+// cluster Status
+func Reconcile(
+	ctx context.Context,
+	instance *postgres.Instance,
+	cluster *apiv1.Cluster,
+	c client.Client,
+) (reconcile.Result, error) {
+	if !cluster.ContainsManagedTablesConfiguration() &&
+		reflect.DeepEqual(cluster.Status.ManagedTablesStatus, apiv1.ManagedTables{}) {
+		return reconcile.Result{}, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Reconciling managed tables")
+
+	status, err := synchronizeTables(ctx, instance.ConnectionPool(), cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if reflect.DeepEqual(status, cluster.Status.ManagedTablesStatus) {
+		return reconcile.Result{}, nil
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.ManagedTablesStatus = status
+	return reconcile.Result{}, c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster))
+}
+
+// synchronizeTables aligns the storage parameters of the managed tables
+// to the spec, returning their status.
+//
+// NOTE: synchronizeTables will not error out if the storage parameters of a
+// single table cannot be applied by PostgreSQL, so that a wrong setting cannot
+// prevent the other tables from being reconciled
+func synchronizeTables(
+	ctx context.Context,
+	pooler pool.Pooler,
+	cluster *apiv1.Cluster,
+) (apiv1.ManagedTables, error) {
+	var status apiv1.ManagedTables
+	if !cluster.ContainsManagedTablesConfiguration() {
+		return status, nil
+	}
+
+	tablesByDB := make(map[string][]apiv1.TableConfiguration)
+	var dbNames []string
+	for _, table := range cluster.Spec.Managed.Tables {
+		dbName := table.GetDBName(cluster.GetApplicationDatabaseName())
+		if _, found := tablesByDB[dbName]; !found {
+			dbNames = append(dbNames, dbName)
+		}
+		tablesByDB[dbName] = append(tablesByDB[dbName], table)
+	}
+
+	for _, dbName := range dbNames {
+		db, err := pooler.Connection(ctx, dbName)
+		if err != nil {
+			return status, fmt.Errorf("while connecting to database %s: %w", dbName, err)
+		}
+
+		for _, table := range tablesByDB[dbName] {
+			err := reconcileTable(ctx, db, table)
+			var pgErr *pgconn.PgError
+			switch {
+			case err == nil:
+				if status.Reconciled == nil {
+					status.Reconciled = make(map[string][]string)
+				}
+				status.Reconciled[dbName] = append(status.Reconciled[dbName], table.Name)
+			case errors.As(err, &pgErr):
+				// this is an expectable error, i.e. an invalid value,
+				// that needs to be fixed in the spec
+				addCannotReconcile(&status, table.Name, fmt.Sprintf("in database %s: %s", dbName, pgErr.Message))
+			case errors.Is(err, errTableNotFound), errors.Is(err, errInvalidStorageParameter):
+				addCannotReconcile(&status, table.Name, fmt.Sprintf("in database %s: %s", dbName, err.Error()))
+			default:
+				return status, err
+			}
+		}
+	}
+
+	return status, nil
+}
+
+func addCannotReconcile(status *apiv1.ManagedTables, tableName string, cause string) {
+	if status.CannotReconcile == nil {
+		status.CannotReconcile = make(map[string][]string)
+	}
+	status.CannotReconcile[tableName] = append(status.CannotReconcile[tableName], cause)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tables
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool/pooltest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tables reconciler", func() {
+	var (
+		appDB   *sql.DB
+		appMock sqlmock.Sqlmock
+		pooler  pooltest.FakePooler
+		cluster *apiv1.Cluster
+	)
+
+	storageParametersColumns := []string{"reloptions", "toast_reloptions"}
+
+	BeforeEach(func() {
+		var err error
+		appDB, appMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		pooler = pooltest.FakePooler{Databases: map[string]*sql.DB{
+			"app": appDB,
+		}}
+
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app"},
+				},
+				Managed: &apiv1.ManagedConfiguration{
+					Tables: []apiv1.TableConfiguration{
+						{
+							Name: "orders",
+							AutovacuumSettings: map[string]string{
+								"autovacuum_vacuum_scale_factor":       "0.01",
+								"toast.autovacuum_vacuum_scale_factor": "0.05",
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(appMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectStorageParameters := func(options, toastOptions string) {
+		appMock.ExpectQuery(storageParametersQuery).
+			WithArgs(`"public"."orders"`).
+			WillReturnRows(sqlmock.NewRows(storageParametersColumns).AddRow(options, toastOptions))
+	}
+
+	It("sets the autovacuum storage parameters of a table", func() {
+		expectStorageParameters("{fillfactor=70}", "{}")
+		appMock.ExpectExec(`ALTER TABLE "public"."orders" SET (` +
+			`autovacuum_vacuum_scale_factor = '0.01', toast.autovacuum_vacuum_scale_factor = '0.05')`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders"}))
+		Expect(status.CannotReconcile).To(BeEmpty())
+	})
+
+	It("does nothing when the storage parameters are already in sync", func() {
+		expectStorageParameters(
+			"{fillfactor=70,autovacuum_vacuum_scale_factor=0.01}",
+			"{autovacuum_vacuum_scale_factor=0.05}")
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders"}))
+	})
+
+	It("changes the storage parameters that differ from the spec", func() {
+		expectStorageParameters(
+			"{autovacuum_vacuum_scale_factor=0.2}",
+			"{autovacuum_vacuum_scale_factor=0.05}")
+		appMock.ExpectExec(`ALTER TABLE "public"."orders" SET (autovacuum_vacuum_scale_factor = '0.01')`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders"}))
+	})
+
+	It("resets the autovacuum storage parameters removed from the spec", func() {
+		cluster.Spec.Managed.Tables[0].AutovacuumSettings = nil
+		expectStorageParameters(
+			"{fillfactor=70,autovacuum_vacuum_scale_factor=0.01,autovacuum_enabled=off}",
+			"{autovacuum_vacuum_scale_factor=0.05}")
+		appMock.ExpectExec(`ALTER TABLE "public"."orders" RESET (` +
+			`autovacuum_enabled, autovacuum_vacuum_scale_factor, toast.autovacuum_vacuum_scale_factor)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(HaveKeyWithValue("app", []string{"orders"}))
+	})
+
+	It("reports the tables that don't exist", func() {
+		appMock.ExpectQuery(storageParametersQuery).
+			WithArgs(`"public"."orders"`).
+			WillReturnRows(sqlmock.NewRows(storageParametersColumns))
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Reconciled).To(BeEmpty())
+		Expect(status.CannotReconcile).To(HaveKeyWithValue("orders",
+			[]string{"in database app: table does not exist"}))
+	})
+
+	It("reports the settings refused by PostgreSQL", func() {
+		expectStorageParameters("{}", "{}")
+		appMock.ExpectExec(`ALTER TABLE "public"."orders" SET (` +
+			`autovacuum_vacuum_scale_factor = '0.01', toast.autovacuum_vacuum_scale_factor = '0.05')`).
+			WillReturnError(&pgconn.PgError{Message: "invalid value for floating point option"})
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.CannotReconcile).To(HaveKeyWithValue("orders",
+			[]string{"in database app: invalid value for floating point option"}))
+	})
+
+	It("doesn't touch the tables that are not listed", func() {
+		cluster.Spec.Managed.Tables = nil
+
+		status, err := synchronizeTables(context.TODO(), pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(apiv1.ManagedTables{}))
+	})
+})
+
+var _ = Describe("sanitizeTableName", func() {
+	It("adds the default schema", func() {
+		Expect(sanitizeTableName("orders")).To(Equal(`"public"."orders"`))
+	})
+
+	It("keeps the schema of qualified names", func() {
+		Expect(sanitizeTableName("sales.Orders")).To(Equal(`"sales"."Orders"`))
+	})
+})
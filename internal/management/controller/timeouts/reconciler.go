@@ -0,0 +1,211 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeouts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// roleSettingsQuery gets the parameters set for every role,
+// regardless of the database
+const roleSettingsQuery = `SELECT r.rolname, COALESCE(s.setconfig, '{}')
+FROM pg_catalog.pg_roles r
+LEFT JOIN pg_catalog.pg_db_role_setting s ON s.setrole = r.oid AND s.setdatabase = 0`
+
+// Reconcile applies the timeouts overridden for specific roles on the
+// primary instance, resetting the ones of the roles removed from the
+// configuration. The roles not existing yet are skipped, as they may
+// be created later, i.e. by the managed roles reconciler
+func Reconcile(
+	ctx context.Context,
+	instance *postgres.Instance,
+	cluster *apiv1.Cluster,
+	c client.Client,
+) error {
+	if cluster.IsReplica() {
+		return nil
+	}
+
+	var roles []apiv1.RoleTimeoutsConfiguration
+	if timeouts := cluster.Spec.PostgresConfiguration.Timeouts; timeouts != nil {
+		roles = timeouts.Roles
+	}
+	if len(roles) == 0 && len(cluster.Status.ManagedRoleTimeoutsStatus) == 0 {
+		return nil
+	}
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while getting the superuser connection: %w", err)
+	}
+
+	if err := synchronizeRoleTimeouts(
+		ctx,
+		db,
+		withRemovedRoles(roles, cluster.Status.ManagedRoleTimeoutsStatus),
+	); err != nil {
+		return err
+	}
+
+	status := getRoleNames(roles)
+	if reflect.DeepEqual(status, cluster.Status.ManagedRoleTimeoutsStatus) {
+		return nil
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	updatedCluster.Status.ManagedRoleTimeoutsStatus = status
+	return c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster))
+}
+
+// withRemovedRoles adds to the requested timeouts the ones of the roles
+// whose timeouts have been set previously and are not requested anymore,
+// with empty values to have them reset
+func withRemovedRoles(
+	roles []apiv1.RoleTimeoutsConfiguration,
+	previousRoleNames []string,
+) []apiv1.RoleTimeoutsConfiguration {
+	result := make([]apiv1.RoleTimeoutsConfiguration, len(roles), len(roles)+len(previousRoleNames))
+	copy(result, roles)
+
+	requested := stringset.From(getRoleNames(roles))
+	for _, name := range previousRoleNames {
+		if !requested.Has(name) {
+			result = append(result, apiv1.RoleTimeoutsConfiguration{Name: name})
+		}
+	}
+
+	return result
+}
+
+// getRoleNames returns the sorted names of the roles whose
+// timeouts are overridden, or nil if there are none
+func getRoleNames(roles []apiv1.RoleTimeoutsConfiguration) []string {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(roles))
+	for idx := range roles {
+		names[idx] = roles[idx].Name
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// synchronizeRoleTimeouts aligns the timeouts of the roles in the
+// database to the requested ones
+func synchronizeRoleTimeouts(
+	ctx context.Context,
+	db *sql.DB,
+	roles []apiv1.RoleTimeoutsConfiguration,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	settingsByRole, err := getRoleSettings(ctx, db)
+	if err != nil {
+		return fmt.Errorf("while getting the settings of the roles: %w", err)
+	}
+
+	for _, role := range roles {
+		currentSettings, exists := settingsByRole[role.Name]
+		if !exists {
+			contextLogger.Info("Skipping the timeouts of a role not existing yet", "role", role.Name)
+			continue
+		}
+
+		for _, statement := range getRoleTimeoutsStatements(role, currentSettings) {
+			contextLogger.Info("Updating the timeouts of a role", "role", role.Name, "statement", statement)
+			if _, err := db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("while updating the timeouts of role %s: %w", role.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getRoleSettings gets the parameters set for every role, indexed
+// by role name
+func getRoleSettings(ctx context.Context, db *sql.DB) (map[string]map[string]string, error) {
+	rows, err := db.QueryContext(ctx, roleSettingsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]map[string]string)
+	for rows.Next() {
+		var name string
+		var config pq.StringArray
+		if err := rows.Scan(&name, &config); err != nil {
+			return nil, err
+		}
+
+		settings := make(map[string]string, len(config))
+		for _, item := range config {
+			key, value, _ := strings.Cut(item, "=")
+			settings[key] = value
+		}
+		result[name] = settings
+	}
+
+	return result, rows.Err()
+}
+
+// getRoleTimeoutsStatements returns the statements aligning the current
+// settings of a role to the requested timeouts
+func getRoleTimeoutsStatements(role apiv1.RoleTimeoutsConfiguration, currentSettings map[string]string) []string {
+	desiredSettings := role.GetSettings()
+	parameters := make([]string, 0, len(desiredSettings))
+	for parameter := range desiredSettings {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+
+	roleName := pgx.Identifier{role.Name}.Sanitize()
+	var statements []string
+	for _, parameter := range parameters {
+		desired := desiredSettings[parameter]
+		current, isSet := currentSettings[parameter]
+		switch {
+		case desired == "" && isSet:
+			statements = append(statements, fmt.Sprintf("ALTER ROLE %s RESET %s", roleName, parameter))
+		case desired != "" && (!isSet || current != desired):
+			statements = append(statements, fmt.Sprintf("ALTER ROLE %s SET %s TO %s",
+				roleName, parameter, pq.QuoteLiteral(desired)))
+		}
+	}
+
+	return statements
+}
@@ -0,0 +1,140 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeouts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("role timeouts statements", func() {
+	It("sets the timeouts not set yet", func() {
+		role := apiv1.RoleTimeoutsConfiguration{Name: "migrator", StatementTimeout: "0", LockTimeout: "1min"}
+		Expect(getRoleTimeoutsStatements(role, map[string]string{"search_path": "app"})).To(Equal([]string{
+			`ALTER ROLE "migrator" SET lock_timeout TO '1min'`,
+			`ALTER ROLE "migrator" SET statement_timeout TO '0'`,
+		}))
+	})
+
+	It("changes only the timeouts having a different value", func() {
+		role := apiv1.RoleTimeoutsConfiguration{Name: "migrator", StatementTimeout: "0", LockTimeout: "1min"}
+		Expect(getRoleTimeoutsStatements(role, map[string]string{
+			"statement_timeout": "0",
+			"lock_timeout":      "5s",
+		})).To(Equal([]string{`ALTER ROLE "migrator" SET lock_timeout TO '1min'`}))
+	})
+
+	It("resets the timeouts not requested anymore", func() {
+		role := apiv1.RoleTimeoutsConfiguration{Name: "migrator", StatementTimeout: "0"}
+		Expect(getRoleTimeoutsStatements(role, map[string]string{
+			"statement_timeout": "0",
+			"lock_timeout":      "5s",
+		})).To(Equal([]string{`ALTER ROLE "migrator" RESET lock_timeout`}))
+	})
+
+	It("does nothing when the timeouts are aligned", func() {
+		role := apiv1.RoleTimeoutsConfiguration{Name: "migrator", StatementTimeout: "0"}
+		Expect(getRoleTimeoutsStatements(role, map[string]string{"statement_timeout": "0"})).To(BeEmpty())
+		Expect(getRoleTimeoutsStatements(apiv1.RoleTimeoutsConfiguration{Name: "app"}, nil)).To(BeEmpty())
+	})
+
+	It("quotes the role names", func() {
+		role := apiv1.RoleTimeoutsConfiguration{Name: "Schema Migrator", LockTimeout: "1s"}
+		Expect(getRoleTimeoutsStatements(role, nil)).To(Equal([]string{
+			`ALTER ROLE "Schema Migrator" SET lock_timeout TO '1s'`,
+		}))
+	})
+})
+
+var _ = Describe("roles removed from the timeouts", func() {
+	It("resets the roles not requested anymore", func() {
+		roles := []apiv1.RoleTimeoutsConfiguration{{Name: "migrator", StatementTimeout: "0"}}
+		Expect(withRemovedRoles(roles, []string{"batch", "migrator"})).To(Equal([]apiv1.RoleTimeoutsConfiguration{
+			{Name: "migrator", StatementTimeout: "0"},
+			{Name: "batch"},
+		}))
+		Expect(roles).To(HaveLen(1))
+	})
+
+	It("tracks the sorted names of the requested roles", func() {
+		Expect(getRoleNames([]apiv1.RoleTimeoutsConfiguration{{Name: "migrator"}, {Name: "batch"}})).
+			To(Equal([]string{"batch", "migrator"}))
+		Expect(getRoleNames(nil)).To(BeNil())
+	})
+})
+
+var _ = Describe("role timeouts synchronization", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("alters the existing roles, skipping the missing ones", func(ctx context.Context) {
+		mock.ExpectQuery(roleSettingsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "setconfig"}).
+				AddRow("app", "{}").
+				AddRow("migrator", "{statement_timeout=30s,search_path=app}"))
+		mock.ExpectExec(`ALTER ROLE "migrator" SET statement_timeout TO '0'`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(synchronizeRoleTimeouts(ctx, db, []apiv1.RoleTimeoutsConfiguration{
+			{Name: "migrator", StatementTimeout: "0"},
+			{Name: "not_there_yet", StatementTimeout: "0"},
+		})).To(Succeed())
+	})
+
+	It("resets the timeouts of the removed roles", func(ctx context.Context) {
+		mock.ExpectQuery(roleSettingsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "setconfig"}).
+				AddRow("batch", "{lock_timeout=1s,search_path=app}"))
+		mock.ExpectExec(`ALTER ROLE "batch" RESET lock_timeout`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(synchronizeRoleTimeouts(ctx, db, withRemovedRoles(nil, []string{"batch", "dropped"}))).
+			To(Succeed())
+	})
+
+	It("reports the errors altering the roles", func(ctx context.Context) {
+		mock.ExpectQuery(roleSettingsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "setconfig"}).AddRow("migrator", "{}"))
+		mock.ExpectExec(`ALTER ROLE "migrator" SET lock_timeout TO '1s'`).
+			WillReturnError(errors.New("permission denied"))
+
+		err := synchronizeRoleTimeouts(ctx, db, []apiv1.RoleTimeoutsConfiguration{
+			{Name: "migrator", LockTimeout: "1s"},
+		})
+		Expect(err).To(MatchError(ContainSubstring("permission denied")))
+	})
+})
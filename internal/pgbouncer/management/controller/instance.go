@@ -17,28 +17,69 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"k8s.io/client-go/util/retry"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
+const (
+	// maxDatabaseNameLength is the maximum length of a database name
+	// accepted by PgBouncer
+	maxDatabaseNameLength = 63
+
+	// defaultCommandTimeout is the maximum time the commands issued to the
+	// admin console can last, so that a hung PgBouncer can't block the
+	// reconciliation loop
+	defaultCommandTimeout = 30 * time.Second
+
+	// alreadyPausedMessage is the error raised by PgBouncer when pausing
+	// an instance which is already paused, or is still pausing
+	alreadyPausedMessage = "already suspended/paused"
+
+	// unknownDatabaseMessagePrefix is the prefix of the error raised by
+	// PgBouncer when pausing or resuming a database it doesn't know
+	unknownDatabaseMessagePrefix = "no such database"
+
+	// notPausedMessageSuffix is the suffix of the error raised by PgBouncer
+	// when resuming a database which is not paused
+	notPausedMessageSuffix = "is not paused"
+)
+
+// ErrUnknownDatabase is raised when pausing a database PgBouncer doesn't know
+var ErrUnknownDatabase = errors.New("the database is not known to PgBouncer")
+
 // PgBouncerInstanceInterface the public interface for a PgBouncer instance,
 // implementations should be thread safe
 type PgBouncerInstanceInterface interface {
 	Paused() bool
-	Pause() error
-	Resume() error
-	Reload() error
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+	DatabasePaused(name string) bool
+	PausedDatabases() []string
+	PauseDB(ctx context.Context, name string) error
+	ResumeDB(ctx context.Context, name string) error
+	Reload(ctx context.Context) error
+	Reconnect(ctx context.Context) error
+	Drain(ctx context.Context, timeout time.Duration) error
 }
 
 // NewPgBouncerInstance initializes a new pgBouncerInstance
 func NewPgBouncerInstance() PgBouncerInstanceInterface {
+	// The admin console is reached through the unix socket, where
+	// PgBouncer doesn't support TLS: the TLS configuration of the
+	// pooler only applies to the TCP connections
 	dsn := fmt.Sprintf(
 		"host=%s port=%v user=%s sslmode=disable",
 		config.PgBouncerSocketDir,
@@ -47,21 +88,46 @@ func NewPgBouncerInstance() PgBouncerInstanceInterface {
 	)
 
 	return &pgBouncerInstance{
-		mu:     &sync.RWMutex{},
-		paused: false,
-		pool:   pool.NewPgbouncerConnectionPool(dsn),
+		mu:              &sync.RWMutex{},
+		paused:          false,
+		pausedDatabases: stringset.New(),
+		pool:            pool.NewPgbouncerConnectionPool(dsn),
+		commandTimeout:  defaultCommandTimeout,
 	}
 }
 
 type pgBouncerInstance struct {
-	// The following two fields are used to keep track of
-	// pgbouncer being paused or not
-	mu     *sync.RWMutex
-	paused bool
+	// The following fields are used to keep track of
+	// pgbouncer, or some of its databases, being paused or not
+	mu              *sync.RWMutex
+	paused          bool
+	pausedDatabases *stringset.Data
 
 	// This is the connection pool used to connect to pgbouncer
 	// using the administrative user and the administrative database
 	pool pool.Pooler
+
+	// The maximum time a command issued to the admin console can last,
+	// zero meaning that only the passed context bounds it
+	commandTimeout time.Duration
+}
+
+// exec issues a command to the admin console of PgBouncer, giving up
+// when the passed context is done or when the command timeout expires
+func (p *pgBouncerInstance) exec(ctx context.Context, command string) error {
+	db, err := p.pool.Connection(ctx, "pgbouncer")
+	if err != nil {
+		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
+	}
+
+	if p.commandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.commandTimeout)
+		defer cancel()
+	}
+
+	_, err = db.ExecContext(ctx, command)
+	return err
 }
 
 // Paused returns whether the pgbouncerInstance is paused or not, thread safe
@@ -72,32 +138,26 @@ func (p *pgBouncerInstance) Paused() bool {
 }
 
 // Pause the instance, thread safe
-func (p *pgBouncerInstance) Pause() error {
-	// First step: connect to the pgbouncer administrative database
-	db, err := p.pool.Connection("pgbouncer")
-	if err != nil {
-		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
-	}
-
-	// Second step: pause pgbouncer
+func (p *pgBouncerInstance) Pause(ctx context.Context) error {
+	// First step: pause pgbouncer
 	//
 	// We are retrying the PAUSE query since we need to wait for
 	// pgbouncer to be really up and the user could have created
 	// a pooler which is paused from the start.
-	err = retry.OnError(retry.DefaultBackoff, func(err error) bool {
-		if errors.Is(err, os.ErrNotExist) {
-			return true
-		}
-		return true
+	//
+	// A previous PAUSE may have timed out while PgBouncer was waiting
+	// for the server connections to be released: PgBouncer keeps
+	// pausing, and is already holding the new queries
+	err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return ctx.Err() == nil && !isAlreadyPaused(err)
 	}, func() error {
-		_, err = db.Exec("PAUSE")
-		return err
+		return p.exec(ctx, "PAUSE")
 	})
-	if err != nil {
+	if err != nil && !isAlreadyPaused(err) {
 		return err
 	}
 
-	// Third step: keep track of pgbouncer being paused
+	// Second step: keep track of pgbouncer being paused
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.paused = true
@@ -106,39 +166,172 @@ func (p *pgBouncerInstance) Pause() error {
 }
 
 // Resume the instance, thread safe
-func (p *pgBouncerInstance) Resume() error {
-	// First step: connect to the pgbouncer administrative database
-	db, err := p.pool.Connection("pgbouncer")
-	if err != nil {
-		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
+func (p *pgBouncerInstance) Resume(ctx context.Context) error {
+	// First step: resume pgbouncer
+	if err := p.exec(ctx, "RESUME"); err != nil {
+		return fmt.Errorf("while resuming instance: %w", err)
 	}
 
-	// Second step: resume pgbouncer
-	_, err = db.Exec("RESUME")
+	// Second step: keep track of pgbouncer being resumed
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+
+	return nil
+}
+
+// DatabasePaused returns whether the passed database is paused, either
+// because the whole instance is paused or because the database itself
+// is, thread safe
+func (p *pgBouncerInstance) DatabasePaused(name string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.paused || p.pausedDatabases.Has(name)
+}
+
+// PausedDatabases returns the sorted list of the databases paused one
+// by one, independently of the whole instance being paused, thread safe
+func (p *pgBouncerInstance) PausedDatabases() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pausedDatabases.ToSortedList()
+}
+
+// PauseDB pauses a single database of the instance, thread safe
+func (p *pgBouncerInstance) PauseDB(ctx context.Context, name string) error {
+	if err := validateDatabaseName(name); err != nil {
+		return err
+	}
+
+	// First step: pause the database
+	err := p.exec(ctx, fmt.Sprintf("PAUSE %s", pgx.Identifier{name}.Sanitize()))
+	if isUnknownDatabase(err) {
+		return fmt.Errorf("while pausing database %s: %w: %w", name, ErrUnknownDatabase, err)
+	}
 	if err != nil {
-		return fmt.Errorf("while resuming instance: %w", err)
+		return fmt.Errorf("while pausing database %s: %w", name, err)
 	}
 
-	// Third step: keep track of pgbouncer being resumed
+	// Second step: keep track of the database being paused
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.paused = false
+	p.pausedDatabases.Put(name)
+
+	return nil
+}
+
+// ResumeDB resumes a single database of the instance, thread safe
+func (p *pgBouncerInstance) ResumeDB(ctx context.Context, name string) error {
+	if err := validateDatabaseName(name); err != nil {
+		return err
+	}
+
+	// First step: resume the database. A database PgBouncer doesn't know,
+	// or doesn't consider paused, is already resumed, for example
+	// because PgBouncer has been restarted
+	err := p.exec(ctx, fmt.Sprintf("RESUME %s", pgx.Identifier{name}.Sanitize()))
+	if err != nil && !isUnknownDatabase(err) && !isNotPaused(err) {
+		return fmt.Errorf("while resuming database %s: %w", name, err)
+	}
+
+	// Second step: keep track of the database being resumed
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pausedDatabases.Delete(name)
+
+	return nil
+}
+
+// isAlreadyPaused checks whether the passed error has been raised by
+// PgBouncer because the instance is already paused
+func isAlreadyPaused(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Message == alreadyPausedMessage
+}
+
+// isUnknownDatabase checks whether the passed error has been raised by
+// PgBouncer because the database is not defined in its configuration
+func isUnknownDatabase(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Message, unknownDatabaseMessagePrefix)
+}
+
+// isNotPaused checks whether the passed error has been raised by
+// PgBouncer because the database to be resumed is not paused
+func isNotPaused(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && strings.HasSuffix(pgErr.Message, notPausedMessageSuffix)
+}
+
+// validateDatabaseName checks if the passed name can be used to pause
+// or resume a database
+func validateDatabaseName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("the database name cannot be empty")
+	case len(name) > maxDatabaseNameLength:
+		return fmt.Errorf("the database name %q is longer than %d characters", name, maxDatabaseNameLength)
+	case strings.ContainsRune(name, 0):
+		return fmt.Errorf("the database name %q contains invalid characters", name)
+	case name == "pgbouncer":
+		return errors.New("the pgbouncer administrative database cannot be paused or resumed")
+	}
 
 	return nil
 }
 
 // Reload issues a RELOAD command to the PgBouncer instance, returning any error
-func (p *pgBouncerInstance) Reload() error {
+func (p *pgBouncerInstance) Reload(ctx context.Context) error {
+	if err := p.exec(ctx, "RELOAD"); err != nil {
+		return fmt.Errorf("while reloading configuration: %w", err)
+	}
+
+	return nil
+}
+
+// Reconnect issues a RECONNECT command to the PgBouncer instance, closing
+// each server connection as soon as it is released by its client, so that
+// no in-flight query or transaction is interrupted
+func (p *pgBouncerInstance) Reconnect(ctx context.Context) error {
+	// Let pgbouncer open new server connections
+	if err := p.exec(ctx, "RECONNECT"); err != nil {
+		return fmt.Errorf("while reconnecting server connections: %w", err)
+	}
+
+	return nil
+}
+
+// Drain pauses the PgBouncer instance, waiting up to the passed timeout
+// for the in-flight transactions to complete, and then shuts it down,
+// closing the client connections. The passed context bounds the whole
+// operation
+func (p *pgBouncerInstance) Drain(ctx context.Context, timeout time.Duration) error {
 	// First step: connect to the pgbouncer administrative database
-	db, err := p.pool.Connection("pgbouncer")
+	db, err := p.pool.Connection(ctx, "pgbouncer")
 	if err != nil {
 		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
 	}
 
-	// Second step: resume pgbouncer
-	_, err = db.Exec("RELOAD")
-	if err != nil {
-		return fmt.Errorf("while reloading configuration: %w", err)
+	// Second step: wait for the server connections to be released.
+	// PAUSE only returns when every transaction is complete, and
+	// holds the new queries in the meantime. The drain timeout
+	// replaces the command timeout, which could be shorter
+	pauseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if _, err = db.ExecContext(pauseCtx, "PAUSE"); err != nil {
+		log.Warning("Connections not drained, interrupting the in-flight transactions",
+			"timeout", timeout, "err", err)
+	}
+
+	// Third step: shut down pgbouncer. The connection issuing the
+	// command is closed too, so only the errors raised by PgBouncer
+	// itself, or the context being done, are meaningful
+	var pgErr *pgconn.PgError
+	if err := p.exec(ctx, "SHUTDOWN"); errors.As(err, &pgErr) {
+		return fmt.Errorf("while shutting down: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("while shutting down: %w", err)
 	}
 
 	return nil
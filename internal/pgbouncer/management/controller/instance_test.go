@@ -17,10 +17,19 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -43,7 +52,7 @@ var _ = Describe("PgBouncerInstance", func() {
 	})
 
 	Context("when the instance is paused", func() {
-		It("should not return an error", func() {
+		It("should not return an error", func(ctx context.Context) {
 			mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
 
 			pgBouncerInstance := &pgBouncerInstance{
@@ -52,14 +61,56 @@ var _ = Describe("PgBouncerInstance", func() {
 				pool:   &fakePooler{DB: db},
 			}
 
-			err := pgBouncerInstance.Pause()
+			err := pgBouncerInstance.Pause(ctx)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(pgBouncerInstance.Paused()).To(BeTrue())
 		})
 	})
 
+	Context("when the instance is still pausing", func() {
+		It("should consider it paused", func(ctx context.Context) {
+			mock.ExpectExec("PAUSE").WillReturnError(&pgconn.PgError{Code: "08P01", Message: alreadyPausedMessage})
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:   &sync.RWMutex{},
+				pool: &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.Pause(ctx)).To(Succeed())
+			Expect(pgBouncerInstance.Paused()).To(BeTrue())
+		})
+	})
+
+	Context("when PgBouncer doesn't answer", func() {
+		It("should give up after the command timeout", func(ctx context.Context) {
+			mock.ExpectExec("RELOAD").WillDelayFor(time.Minute).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:             &sync.RWMutex{},
+				pool:           &fakePooler{DB: db},
+				commandTimeout: 10 * time.Millisecond,
+			}
+
+			Expect(pgBouncerInstance.Reload(ctx)).To(MatchError(ContainSubstring("canceling query")))
+		})
+
+		It("should stop retrying the pause when the context is done", func(ctx context.Context) {
+			mock.ExpectExec("PAUSE").WillDelayFor(time.Minute).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:   &sync.RWMutex{},
+				pool: &fakePooler{DB: db},
+			}
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+			Expect(pgBouncerInstance.Pause(timeoutCtx)).ToNot(Succeed())
+			Expect(pgBouncerInstance.Paused()).To(BeFalse())
+		})
+	})
+
 	Context("when the instance is resumed", func() {
-		It("should not return an error", func() {
+		It("should not return an error", func(ctx context.Context) {
 			mock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
 
 			pgBouncerInstance := &pgBouncerInstance{
@@ -68,14 +119,131 @@ var _ = Describe("PgBouncerInstance", func() {
 				pool:   &fakePooler{DB: db},
 			}
 
-			err := pgBouncerInstance.Resume()
+			err := pgBouncerInstance.Resume(ctx)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(pgBouncerInstance.Paused()).To(BeFalse())
 		})
 	})
 
+	Context("when a database is paused", func() {
+		It("should track the paused database", func(ctx context.Context) {
+			mock.ExpectExec(regexp.QuoteMeta(`PAUSE "app"`)).WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				pausedDatabases: stringset.New(),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.PauseDB(ctx, "app")).To(Succeed())
+			Expect(pgBouncerInstance.DatabasePaused("app")).To(BeTrue())
+			Expect(pgBouncerInstance.DatabasePaused("other")).To(BeFalse())
+			Expect(pgBouncerInstance.Paused()).To(BeFalse())
+		})
+
+		It("should not track the database if the pause fails", func(ctx context.Context) {
+			mock.ExpectExec(regexp.QuoteMeta(`PAUSE "app"`)).WillReturnError(sqlmock.ErrCancelled)
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				pausedDatabases: stringset.New(),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.PauseDB(ctx, "app")).ToNot(Succeed())
+			Expect(pgBouncerInstance.DatabasePaused("app")).To(BeFalse())
+		})
+
+		It("should recognize the databases PgBouncer doesn't know", func(ctx context.Context) {
+			mock.ExpectExec(regexp.QuoteMeta(`PAUSE "app"`)).
+				WillReturnError(&pgconn.PgError{Code: "08P01", Message: "no such database: app"})
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				pausedDatabases: stringset.New(),
+				pool:            &fakePooler{DB: db},
+			}
+
+			err := pgBouncerInstance.PauseDB(ctx, "app")
+			Expect(errors.Is(err, ErrUnknownDatabase)).To(BeTrue())
+			Expect(pgBouncerInstance.DatabasePaused("app")).To(BeFalse())
+		})
+
+		It("should reject invalid database names", func(ctx context.Context) {
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				pausedDatabases: stringset.New(),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.PauseDB(ctx, "")).ToNot(Succeed())
+			Expect(pgBouncerInstance.PauseDB(ctx, "pgbouncer")).ToNot(Succeed())
+			Expect(pgBouncerInstance.PauseDB(ctx, strings.Repeat("a", 64))).ToNot(Succeed())
+			Expect(pgBouncerInstance.ResumeDB(ctx, "app\x00")).ToNot(Succeed())
+			Expect(pgBouncerInstance.pausedDatabases.Len()).To(BeZero())
+		})
+
+		It("should report every database as paused when the instance is paused", func() {
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				paused:          true,
+				pausedDatabases: stringset.New(),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.DatabasePaused("app")).To(BeTrue())
+		})
+	})
+
+	Context("when a database is resumed", func() {
+		It("should stop tracking the database", func(ctx context.Context) {
+			mock.ExpectExec(regexp.QuoteMeta(`RESUME "app"`)).WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				pausedDatabases: stringset.From([]string{"app", "other"}),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.ResumeDB(ctx, "app")).To(Succeed())
+			Expect(pgBouncerInstance.DatabasePaused("app")).To(BeFalse())
+			Expect(pgBouncerInstance.DatabasePaused("other")).To(BeTrue())
+		})
+
+		It("should stop tracking the databases PgBouncer doesn't consider paused", func(ctx context.Context) {
+			mock.ExpectExec(regexp.QuoteMeta(`RESUME "app"`)).
+				WillReturnError(&pgconn.PgError{Code: "08P01", Message: "database app is not paused"})
+			mock.ExpectExec(regexp.QuoteMeta(`RESUME "other"`)).
+				WillReturnError(&pgconn.PgError{Code: "08P01", Message: "no such database: other"})
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				pausedDatabases: stringset.From([]string{"app", "other"}),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.ResumeDB(ctx, "app")).To(Succeed())
+			Expect(pgBouncerInstance.ResumeDB(ctx, "other")).To(Succeed())
+			Expect(pgBouncerInstance.PausedDatabases()).To(BeEmpty())
+		})
+
+		It("should list the databases still paused", func(ctx context.Context) {
+			mock.ExpectExec(regexp.QuoteMeta(`RESUME "app"`)).WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:              &sync.RWMutex{},
+				paused:          true,
+				pausedDatabases: stringset.From([]string{"reporting", "app", "other"}),
+				pool:            &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.ResumeDB(ctx, "app")).To(Succeed())
+			Expect(pgBouncerInstance.PausedDatabases()).To(Equal([]string{"other", "reporting"}))
+		})
+	})
+
 	Context("when the instance configuration is reloaded", func() {
-		It("should not return an error", func() {
+		It("should not return an error", func(ctx context.Context) {
 			mock.ExpectExec("RELOAD").WillReturnResult(sqlmock.NewResult(1, 1))
 
 			pgBouncerInstance := &pgBouncerInstance{
@@ -84,17 +252,76 @@ var _ = Describe("PgBouncerInstance", func() {
 				pool:   &fakePooler{DB: db},
 			}
 
-			err := pgBouncerInstance.Reload()
+			err := pgBouncerInstance.Reload(ctx)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("when the server connections are reconnected", func() {
+		It("should issue the RECONNECT command", func(ctx context.Context) {
+			mock.ExpectExec("RECONNECT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:   &sync.RWMutex{},
+				pool: &fakePooler{DB: db},
+			}
+
+			Expect(pgBouncerInstance.Reconnect(ctx)).To(Succeed())
+		})
+	})
+
+	Context("when the connections are drained", func() {
+		var instance *pgBouncerInstance
+
+		BeforeEach(func() {
+			instance = &pgBouncerInstance{
+				mu:   &sync.RWMutex{},
+				pool: &fakePooler{DB: db},
+			}
+		})
+
+		It("should pause and shut down the instance", func(ctx context.Context) {
+			mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SHUTDOWN").WillReturnError(driver.ErrBadConn)
+
+			Expect(instance.Drain(ctx, time.Minute)).To(Succeed())
+		})
+
+		It("should shut down the instance when the timeout expires", func(ctx context.Context) {
+			mock.ExpectExec("PAUSE").WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SHUTDOWN").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			start := time.Now()
+			Expect(instance.Drain(ctx, 10*time.Millisecond)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+
+		It("should return the errors raised by PgBouncer", func(ctx context.Context) {
+			mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SHUTDOWN").WillReturnError(&pgconn.PgError{Code: "08P01", Message: "denied"})
+
+			Expect(instance.Drain(ctx, time.Minute)).To(MatchError(ContainSubstring("denied")))
+		})
+
+		It("should give up when the context is done", func(ctx context.Context) {
+			mock.ExpectExec("PAUSE").WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 0))
+			instance.commandTimeout = 10 * time.Millisecond
+
+			drainCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			Expect(instance.Drain(drainCtx, time.Minute)).To(MatchError(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
 })
 
 type fakePooler struct {
 	DB *sql.DB
 }
 
-func (f *fakePooler) Connection(_ string) (*sql.DB, error) {
+func (f *fakePooler) Connection(_ context.Context, _ string) (*sql.DB, error) {
 	return f.DB, nil
 }
 
@@ -20,6 +20,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
 // PgBouncerReconciler reconciles the status of the Pooler resource with
@@ -45,6 +47,15 @@ type PgBouncerReconciler struct {
 	poolerWatch          watch.Interface
 	instance             PgBouncerInstanceInterface
 	poolerNamespacedName types.NamespacedName
+
+	// The version of the server TLS secret that was in use when the
+	// server connections have been opened
+	serverTLSVersion string
+
+	// The databases PgBouncer refused to pause because they are not in
+	// its configuration. They are not paused again until the
+	// configuration is reloaded
+	unknownDatabases *stringset.Data
 }
 
 // NewPgBouncerReconciler creates a new pgbouncer reconciler
@@ -111,6 +122,12 @@ func (r *PgBouncerReconciler) Stop() {
 	}
 }
 
+// Drain waits for the in-flight transactions of PgBouncer to complete,
+// up to the passed timeout, and shuts it down
+func (r *PgBouncerReconciler) Drain(ctx context.Context, timeout time.Duration) error {
+	return r.instance.Drain(ctx, timeout)
+}
+
 // GetClient returns the dynamic client that is being used for a certain reconciler
 func (r *PgBouncerReconciler) GetClient() ctrl.Client {
 	return r.client
@@ -134,25 +151,74 @@ func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event)
 		return fmt.Errorf("while reconciling configuration: %w", err)
 	}
 
-	return r.synchronizePause(pooler)
+	return r.synchronizePause(ctx, pooler)
 }
 
 // synchronizePause ensure that the pause flag inside the Pooler
-// specification matches the PgBouncer status
-func (r *PgBouncerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
+// specification matches the PgBouncer status. The pooler is also paused
+// when the operator holds it paused during a switchover
+func (r *PgBouncerReconciler) synchronizePause(ctx context.Context, pooler *apiv1.Pooler) error {
 	isPaused := r.instance.Paused()
-	shouldBePaused := pooler.Spec.PgBouncer.IsPaused()
+	shouldBePaused := pooler.Spec.PgBouncer.IsPaused() || pooler.Status.PausedDuringSwitchover
 	if shouldBePaused && !isPaused {
-		if err := r.instance.Pause(); err != nil {
+		if err := r.instance.Pause(ctx); err != nil {
 			return fmt.Errorf("while pausing instance: %w", err)
 		}
 	}
 	if !shouldBePaused && isPaused {
-		if err := r.instance.Resume(); err != nil {
+		if err := r.instance.Resume(ctx); err != nil {
 			return fmt.Errorf("while resuming instance: %w", err)
 		}
 	}
-	return nil
+	return r.synchronizePausedDatabases(ctx, pooler)
+}
+
+// synchronizePausedDatabases ensures that the databases paused in PgBouncer
+// are the ones listed in the Pooler specification. The databases are paused
+// independently of the whole instance, which can be paused and resumed
+// without affecting them
+func (r *PgBouncerReconciler) synchronizePausedDatabases(ctx context.Context, pooler *apiv1.Pooler) error {
+	// PgBouncer refuses to pause a database while the whole instance is
+	// paused: the databases are synchronized once it's resumed
+	if r.instance.Paused() {
+		return nil
+	}
+
+	if r.unknownDatabases == nil {
+		r.unknownDatabases = stringset.New()
+	}
+
+	shouldBePaused := stringset.From(pooler.Spec.PgBouncer.PausedDatabases)
+	isPaused := stringset.From(r.instance.PausedDatabases())
+
+	var errs []error
+	for _, name := range isPaused.ToSortedList() {
+		if !shouldBePaused.Has(name) {
+			log.Info("Resuming database", "database", name)
+			if err := r.instance.ResumeDB(ctx, name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for _, name := range shouldBePaused.ToSortedList() {
+		if isPaused.Has(name) || r.unknownDatabases.Has(name) {
+			continue
+		}
+
+		log.Info("Pausing database", "database", name)
+		err := r.instance.PauseDB(ctx, name)
+		if errors.Is(err, ErrUnknownDatabase) {
+			log.Warning("Cannot pause a database not defined in the PgBouncer configuration",
+				"database", name)
+			r.unknownDatabases.Put(name)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // synchronizeConfig ensure that the configuration derived from
@@ -167,13 +233,54 @@ func (r *PgBouncerReconciler) synchronizeConfig(ctx context.Context, pooler *api
 		return fmt.Errorf("while writing PgBouncer configuration: %w", err)
 	}
 
-	if !configurationChanged {
+	return r.applyConfiguration(ctx, pooler, configurationChanged)
+}
+
+// applyConfiguration reloads PgBouncer when its configuration files changed,
+// and then replaces the server connections if the server certificate of the
+// cluster has been rotated
+func (r *PgBouncerReconciler) applyConfiguration(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	configurationChanged bool,
+) error {
+	if configurationChanged {
+		if err := r.instance.Reload(ctx); err != nil {
+			return fmt.Errorf("while reloading configuration due to change: %w", err)
+		}
+
+		// the new configuration may define the databases that were unknown
+		r.unknownDatabases = nil
+	}
+
+	return r.reconnectOnServerCertificateRotation(ctx, pooler)
+}
+
+// reconnectOnServerCertificateRotation makes PgBouncer replace its server
+// connections when the server certificate of the cluster has been rotated,
+// so that they are established again using the new certificate.
+// The server connections are closed only after their clients release them
+func (r *PgBouncerReconciler) reconnectOnServerCertificateRotation(ctx context.Context, pooler *apiv1.Pooler) error {
+	if pooler.Status.Secrets == nil {
+		return nil
+	}
+
+	version := pooler.Status.Secrets.ServerTLS.Version
+	switch {
+	case version == "" || version == r.serverTLSVersion:
+		return nil
+	case r.serverTLSVersion == "":
+		// the server connections have been opened with this certificate
+		r.serverTLSVersion = version
 		return nil
 	}
 
-	if err = r.instance.Reload(); err != nil {
-		return fmt.Errorf("while reloading configuration due to change: %w", err)
+	log.Info("Server certificate rotated, reconnecting the server connections",
+		"previousVersion", r.serverTLSVersion, "version", version)
+	if err := r.instance.Reconnect(ctx); err != nil {
+		return fmt.Errorf("while reconnecting after the server certificate rotation: %w", err)
 	}
+	r.serverTLSVersion = version
 
 	return nil
 }
@@ -225,6 +332,9 @@ func (r *PgBouncerReconciler) Init(ctx context.Context) error {
 	if _, err := r.writePgBouncerConfig(ctx, &pooler); err != nil {
 		return err
 	}
+	if pooler.Status.Secrets != nil {
+		r.serverTLSVersion = pooler.Status.Secrets.ServerTLS.Version
+	}
 
 	// Ensure we have the directory to store the controlling socket
 	if err := fileutils.EnsureDirectoryExists(config.PgBouncerSocketDir); err != nil {
@@ -0,0 +1,275 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeInstance records the commands issued to PgBouncer
+type fakeInstance struct {
+	PgBouncerInstanceInterface
+
+	commands        []string
+	paused          bool
+	reconnects      int
+	reconnectErr    error
+	pausedDatabases []string
+	pauseDBErr      error
+	pauseDBAttempts int
+}
+
+func (f *fakeInstance) Paused() bool {
+	return f.paused
+}
+
+func (f *fakeInstance) Pause(_ context.Context) error {
+	f.commands = append(f.commands, "PAUSE")
+	f.paused = true
+	return nil
+}
+
+func (f *fakeInstance) Resume(_ context.Context) error {
+	f.commands = append(f.commands, "RESUME")
+	f.paused = false
+	return nil
+}
+
+func (f *fakeInstance) PausedDatabases() []string {
+	return f.pausedDatabases
+}
+
+func (f *fakeInstance) PauseDB(_ context.Context, name string) error {
+	f.pauseDBAttempts++
+	if f.pauseDBErr != nil {
+		return f.pauseDBErr
+	}
+	f.commands = append(f.commands, "PAUSE "+name)
+	f.pausedDatabases = append(f.pausedDatabases, name)
+	return nil
+}
+
+func (f *fakeInstance) ResumeDB(_ context.Context, name string) error {
+	f.commands = append(f.commands, "RESUME "+name)
+	pausedDatabases := make([]string, 0, len(f.pausedDatabases))
+	for _, paused := range f.pausedDatabases {
+		if paused != name {
+			pausedDatabases = append(pausedDatabases, paused)
+		}
+	}
+	f.pausedDatabases = pausedDatabases
+	return nil
+}
+
+func (f *fakeInstance) Reload(_ context.Context) error {
+	f.commands = append(f.commands, "RELOAD")
+	return nil
+}
+
+func (f *fakeInstance) Reconnect(_ context.Context) error {
+	if f.reconnectErr != nil {
+		return f.reconnectErr
+	}
+	f.commands = append(f.commands, "RECONNECT")
+	f.reconnects++
+	return nil
+}
+
+var _ = Describe("server certificate rotation", func() {
+	var (
+		instance   *fakeInstance
+		reconciler *PgBouncerReconciler
+	)
+
+	poolerWithServerTLSVersion := func(version string) *apiv1.Pooler {
+		return &apiv1.Pooler{
+			Status: apiv1.PoolerStatus{
+				Secrets: &apiv1.PoolerSecrets{
+					ServerTLS: apiv1.SecretVersion{Name: serverTLSName, Version: version},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		instance = &fakeInstance{}
+		reconciler = &PgBouncerReconciler{instance: instance}
+	})
+
+	It("doesn't reconnect when the first version of the certificate is seen", func(ctx context.Context) {
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion("1"))).To(Succeed())
+		Expect(instance.reconnects).To(BeZero())
+		Expect(reconciler.serverTLSVersion).To(Equal("1"))
+	})
+
+	It("doesn't reconnect when the certificate didn't change", func(ctx context.Context) {
+		reconciler.serverTLSVersion = "1"
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion("1"))).To(Succeed())
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion(""))).To(Succeed())
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, &apiv1.Pooler{})).To(Succeed())
+		Expect(instance.reconnects).To(BeZero())
+		Expect(reconciler.serverTLSVersion).To(Equal("1"))
+	})
+
+	It("reconnects once when the certificate is rotated", func(ctx context.Context) {
+		reconciler.serverTLSVersion = "1"
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion("2"))).To(Succeed())
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion("2"))).To(Succeed())
+		Expect(instance.reconnects).To(Equal(1))
+		Expect(reconciler.serverTLSVersion).To(Equal("2"))
+	})
+
+	It("retries the reconnection after a failure", func(ctx context.Context) {
+		reconciler.serverTLSVersion = "1"
+		instance.reconnectErr = errors.New("pgbouncer is not ready")
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion("2"))).ToNot(Succeed())
+		Expect(reconciler.serverTLSVersion).To(Equal("1"))
+
+		instance.reconnectErr = nil
+		Expect(reconciler.reconnectOnServerCertificateRotation(ctx, poolerWithServerTLSVersion("2"))).To(Succeed())
+		Expect(instance.reconnects).To(Equal(1))
+		Expect(reconciler.serverTLSVersion).To(Equal("2"))
+	})
+
+	It("reloads the new certificate files before reconnecting", func(ctx context.Context) {
+		reconciler.serverTLSVersion = "1"
+		Expect(reconciler.applyConfiguration(ctx, poolerWithServerTLSVersion("2"), true)).To(Succeed())
+		Expect(instance.commands).To(Equal([]string{"RELOAD", "RECONNECT"}))
+	})
+
+	It("only reloads when the configuration changed without a rotation", func(ctx context.Context) {
+		reconciler.serverTLSVersion = "1"
+		Expect(reconciler.applyConfiguration(ctx, poolerWithServerTLSVersion("1"), true)).To(Succeed())
+		Expect(reconciler.applyConfiguration(ctx, poolerWithServerTLSVersion("1"), false)).To(Succeed())
+		Expect(instance.commands).To(Equal([]string{"RELOAD"}))
+	})
+})
+
+var _ = Describe("paused databases", func() {
+	var (
+		instance   *fakeInstance
+		reconciler *PgBouncerReconciler
+	)
+
+	poolerWithPausedDatabases := func(names ...string) *apiv1.Pooler {
+		return &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{PausedDatabases: names},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		instance = &fakeInstance{}
+		reconciler = &PgBouncerReconciler{instance: instance}
+	})
+
+	It("pauses the databases listed in the specification", func(ctx context.Context) {
+		Expect(reconciler.synchronizePause(ctx, poolerWithPausedDatabases("reporting", "app"))).To(Succeed())
+		Expect(instance.commands).To(Equal([]string{"PAUSE app", "PAUSE reporting"}))
+	})
+
+	It("doesn't pause the databases already paused", func(ctx context.Context) {
+		instance.pausedDatabases = []string{"app"}
+		Expect(reconciler.synchronizePause(ctx, poolerWithPausedDatabases("app"))).To(Succeed())
+		Expect(instance.commands).To(BeEmpty())
+	})
+
+	It("resumes the databases removed from the specification", func(ctx context.Context) {
+		instance.pausedDatabases = []string{"app", "reporting"}
+		Expect(reconciler.synchronizePause(ctx, poolerWithPausedDatabases("reporting", "other"))).To(Succeed())
+		Expect(instance.commands).To(Equal([]string{"RESUME app", "PAUSE other"}))
+		Expect(instance.pausedDatabases).To(ConsistOf("reporting", "other"))
+	})
+
+	It("resumes the other databases even if a pause fails", func(ctx context.Context) {
+		instance.pausedDatabases = []string{"app"}
+		instance.pauseDBErr = errors.New("pause failed")
+		err := reconciler.synchronizePause(ctx, poolerWithPausedDatabases("reporting"))
+		Expect(err).To(MatchError(ContainSubstring("pause failed")))
+		Expect(instance.commands).To(Equal([]string{"RESUME app"}))
+	})
+
+	It("doesn't synchronize the databases while the instance is paused", func(ctx context.Context) {
+		instance.paused = true
+		instance.pausedDatabases = []string{"app"}
+		pooler := poolerWithPausedDatabases("reporting")
+		pooler.Spec.PgBouncer.Paused = ptr.To(true)
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(instance.commands).To(BeEmpty())
+	})
+
+	It("stops pausing the unknown databases until the configuration is reloaded", func(ctx context.Context) {
+		instance.pauseDBErr = fmt.Errorf("while pausing database reporting: %w", ErrUnknownDatabase)
+		pooler := poolerWithPausedDatabases("reporting")
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(instance.pauseDBAttempts).To(Equal(1))
+
+		Expect(reconciler.applyConfiguration(ctx, pooler, true)).To(Succeed())
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(instance.pauseDBAttempts).To(Equal(2))
+	})
+})
+
+var _ = Describe("pausing during a switchover", func() {
+	var (
+		instance   *fakeInstance
+		reconciler *PgBouncerReconciler
+	)
+
+	BeforeEach(func() {
+		instance = &fakeInstance{}
+		reconciler = &PgBouncerReconciler{instance: instance}
+	})
+
+	It("pauses the instance when the operator requests it", func(ctx context.Context) {
+		pooler := &apiv1.Pooler{
+			Spec:   apiv1.PoolerSpec{PgBouncer: &apiv1.PgBouncerSpec{}},
+			Status: apiv1.PoolerStatus{PausedDuringSwitchover: true},
+		}
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(instance.commands).To(Equal([]string{"PAUSE"}))
+	})
+
+	It("resumes the instance after the switchover", func(ctx context.Context) {
+		instance.paused = true
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{PgBouncer: &apiv1.PgBouncerSpec{}},
+		}
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(instance.commands).To(Equal([]string{"RESUME"}))
+	})
+
+	It("keeps the instance paused by the user after the switchover", func(ctx context.Context) {
+		instance.paused = true
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{PgBouncer: &apiv1.PgBouncerSpec{Paused: ptr.To(true)}},
+		}
+		Expect(reconciler.synchronizePause(ctx, pooler)).To(Succeed())
+		Expect(instance.commands).To(BeEmpty())
+	})
+})
@@ -58,14 +58,27 @@ func getSecrets(ctx context.Context, client ctrl.Client, pooler *apiv1.Pooler) (
 		return nil, fmt.Errorf("while getting server CA secret: %w", err)
 	}
 
+	// The certificate presented to the client applications and the CA
+	// validating their certificates default to the ones of the cluster
+	clientTLSSecretName := pooler.Status.Secrets.ServerTLS.Name
+	clientCASecretName := pooler.Status.Secrets.ClientCA.Name
+	if pgbouncerSecrets := pooler.Status.Secrets.PgBouncerSecrets; pgbouncerSecrets != nil {
+		if pgbouncerSecrets.ClientTLS.Name != "" {
+			clientTLSSecretName = pgbouncerSecrets.ClientTLS.Name
+		}
+		if pgbouncerSecrets.ClientCA.Name != "" {
+			clientCASecretName = pgbouncerSecrets.ClientCA.Name
+		}
+	}
+
 	if err := client.Get(ctx,
-		types.NamespacedName{Name: pooler.Status.Secrets.ServerTLS.Name, Namespace: pooler.Namespace},
+		types.NamespacedName{Name: clientTLSSecretName, Namespace: pooler.Namespace},
 		&serverCertSecret); err != nil {
 		return nil, fmt.Errorf("while getting server cert secret: %w", err)
 	}
 
 	if err := client.Get(ctx,
-		types.NamespacedName{Name: pooler.Status.Secrets.ClientCA.Name, Namespace: pooler.Namespace},
+		types.NamespacedName{Name: clientCASecretName, Namespace: pooler.Namespace},
 		&clientCASecret); err != nil {
 		return nil, fmt.Errorf("while getting client CA secret: %w", err)
 	}
@@ -19,6 +19,8 @@ package controller
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -60,6 +62,28 @@ var _ = Describe("getSecrets tests", func() {
 		})
 	})
 
+	Context("when the TLS secrets are provided by the user", func() {
+		BeforeEach(func(ctx context.Context) {
+			for _, name := range []string{"pooler-tls", "pooler-client-ca"} {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pooler.Namespace}}
+				Expect(client.Create(ctx, secret)).To(Succeed())
+			}
+			pooler.Status.Secrets.PgBouncerSecrets = &apiv1.PgBouncerSecrets{
+				ClientTLS: apiv1.SecretVersion{Name: "pooler-tls"},
+				ClientCA:  apiv1.SecretVersion{Name: "pooler-client-ca"},
+			}
+		})
+
+		It("should use them instead of the ones of the cluster", func(ctx context.Context) {
+			res, err := getSecrets(ctx, client, pooler)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Client.Name).To(Equal("pooler-tls"))
+			Expect(res.ClientCA.Name).To(Equal("pooler-client-ca"))
+			Expect(res.ServerCA.Name).To(Equal(serverCAName))
+		})
+	})
+
 	Context("when a secret is not found", func() {
 		BeforeEach(func() {
 			pooler.Status.Secrets.ServerCA = apiv1.SecretVersion{Name: "nonexistent"}
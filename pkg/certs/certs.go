@@ -371,6 +371,22 @@ func ParseCASecret(secret *v1.Secret) (*KeyPair, error) {
 	}, nil
 }
 
+// GetCACertificate extracts the PEM encoded certificate of a CA secret,
+// checking it can be parsed. The private key is not required, to support
+// user-provided CAs
+func GetCACertificate(secret *v1.Secret) ([]byte, error) {
+	publicKey, ok := secret.Data[CACertKey]
+	if !ok {
+		return nil, fmt.Errorf("missing %s secret data", CACertKey)
+	}
+
+	if err := ValidateCABundle(publicKey); err != nil {
+		return nil, err
+	}
+
+	return publicKey, nil
+}
+
 // ParseServerSecret parse a secret for a server to a key pair
 func ParseServerSecret(secret *v1.Secret) (*KeyPair, error) {
 	privateKey, ok := secret.Data[TLSPrivateKeyKey]
@@ -395,6 +411,28 @@ func ParseServerSecret(secret *v1.Secret) (*KeyPair, error) {
 	}, nil
 }
 
+// ValidateCABundle checks that the passed data is a PEM bundle containing
+// at least a certificate, and that every certificate in it can be parsed
+func ValidateCABundle(data []byte) error {
+	certificates := 0
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != certificatePEMBlockType {
+			continue
+		}
+
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("invalid certificate in CA bundle: %w", err)
+		}
+		certificates++
+	}
+
+	if certificates == 0 {
+		return fmt.Errorf("no PEM encoded certificate found in CA bundle")
+	}
+
+	return nil
+}
+
 // createCAWithValidity create a CA with a certain validity, with a parent certificate and signed by a certain
 // private key. If the latest two parameters are nil, the CA will be a root one (self-signed)
 func createCAWithValidity(
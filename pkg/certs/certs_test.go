@@ -22,6 +22,8 @@ import (
 	"encoding/pem"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -278,3 +280,64 @@ var _ = Describe("Keypair generation", func() {
 		})
 	})
 })
+
+var _ = Describe("CA bundle validation", func() {
+	It("accepts a bundle made of many certificates", func() {
+		rootCA, err := CreateRootCA("root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		intermediateCA, err := rootCA.CreateDerivedCA("intermediate", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+
+		var bundle bytes.Buffer
+		bundle.Write(rootCA.Certificate)
+		bundle.Write(intermediateCA.Certificate)
+
+		Expect(ValidateCABundle(bundle.Bytes())).To(Succeed())
+	})
+
+	It("rejects data not containing any certificate", func() {
+		Expect(ValidateCABundle([]byte("this is not a PEM bundle"))).ToNot(Succeed())
+
+		rootCA, err := CreateRootCA("root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ValidateCABundle(rootCA.Private)).ToNot(Succeed())
+	})
+
+	It("rejects a bundle containing a corrupted certificate", func() {
+		corrupted := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("corrupted")})
+		Expect(ValidateCABundle(corrupted)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("CA certificate extraction", func() {
+	It("returns the certificate of a CA secret", func() {
+		rootCA, err := CreateRootCA("root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		secret := rootCA.GenerateCASecret("namespace", "cluster-example-ca")
+
+		certificate, err := GetCACertificate(secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate).To(Equal(rootCA.Certificate))
+	})
+
+	It("doesn't require the private key", func() {
+		rootCA, err := CreateRootCA("root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		secret := rootCA.GenerateCASecret("namespace", "cluster-example-ca")
+		delete(secret.Data, CAPrivateKeyKey)
+
+		certificate, err := GetCACertificate(secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate).To(Equal(rootCA.Certificate))
+	})
+
+	It("fails when the certificate is missing or invalid", func() {
+		secret := &v1.Secret{Data: map[string][]byte{}}
+		_, err := GetCACertificate(secret)
+		Expect(err).To(MatchError(ContainSubstring(CACertKey)))
+
+		secret.Data[CACertKey] = []byte("this is not a certificate")
+		_, err = GetCACertificate(secret)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -21,6 +21,7 @@ limitations under the License.
 package compatibility
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"syscall"
@@ -28,11 +29,19 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-// CreateFifo invokes the Unix system call Mkfifo, if the given filename exists
+// CreateFifo invokes the Unix system call Mkfifo, if the given filename exists.
+// An existing file that is not a FIFO is never replaced, as it may contain
+// data, and an error is returned instead
 func CreateFifo(fileName string) error {
-	if _, err := os.Stat(fileName); err != nil {
+	info, err := os.Stat(fileName)
+	if err != nil {
 		return unix.Mkfifo(fileName, 0o600)
 	}
+
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return fmt.Errorf("%s already exists and is not a FIFO", fileName)
+	}
+
 	return nil
 }
 
@@ -47,3 +56,15 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 func Umask(mask int) int {
 	return unix.Umask(mask)
 }
+
+// GetDiskUsage returns the size and the space available to unprivileged
+// users of the file system containing the passed path, in bytes
+func GetDiskUsage(path string) (total uint64, available uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return stat.Blocks * blockSize, stat.Bavail * blockSize, nil
+}
@@ -39,3 +39,8 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 func Umask(mask int) int {
 	return mask
 }
+
+// GetDiskUsage fakes function for cross-compiling compatibility
+func GetDiskUsage(_ string) (uint64, uint64, error) {
+	return 0, 0, fmt.Errorf("function GetDiskUsage() is not supported in Windows")
+}
@@ -56,6 +56,13 @@ type WALArchiver struct {
 	env []string
 
 	pgDataDirectory string
+
+	// The directory where the WAL files failed on some destination, while
+	// reaching the quorum, are queued to be archived again later
+	pendingDirectory string
+
+	// The function archiving a WAL file on a destination
+	archiveTo func(walName string, destination Destination) error
 }
 
 // WALArchiverResult contains the result of the archival of one WAL
@@ -79,6 +86,7 @@ func New(
 	cluster *apiv1.Cluster,
 	env []string,
 	spoolDirectory string,
+	pendingDirectory string,
 	pgDataDirectory string,
 ) (archiver *WALArchiver, err error) {
 	contextLog := log.FromContext(ctx)
@@ -90,11 +98,13 @@ func New(
 	}
 
 	archiver = &WALArchiver{
-		cluster:         cluster,
-		spool:           walArchiveSpool,
-		env:             env,
-		pgDataDirectory: pgDataDirectory,
+		cluster:          cluster,
+		spool:            walArchiveSpool,
+		env:              env,
+		pgDataDirectory:  pgDataDirectory,
+		pendingDirectory: pendingDirectory,
 	}
+	archiver.archiveTo = archiver.ArchiveTo
 	return archiver, nil
 }
 
@@ -112,11 +122,14 @@ func (archiver *WALArchiver) DeleteFromSpool(walName string) (hasBeenDeleted boo
 	return true, archiver.spool.Remove(walName)
 }
 
-// ArchiveList archives a list of WAL files in parallel
+// ArchiveList archives a list of WAL files in parallel on the passed
+// destinations. A WAL file is archived when at least quorum destinations
+// succeed, and it is queued for the other ones
 func (archiver *WALArchiver) ArchiveList(
 	ctx context.Context,
 	walNames []string,
-	options []string,
+	destinations []Destination,
+	quorum int,
 ) (result []WALArchiverResult) {
 	contextLog := log.FromContext(ctx)
 	result = make([]WALArchiverResult, len(walNames))
@@ -128,7 +141,7 @@ func (archiver *WALArchiver) ArchiveList(
 			walStatus := &result[walIndex]
 			walStatus.WalName = walNames[walIndex]
 			walStatus.StartTime = time.Now()
-			walStatus.Err = archiver.Archive(walNames[walIndex], options)
+			walStatus.Err = archiver.archiveToDestinations(ctx, walNames[walIndex], destinations, quorum)
 			walStatus.EndTime = time.Now()
 			if walStatus.Err == nil && walIndex != 0 {
 				walStatus.Err = archiver.spool.Touch(walNames[walIndex])
@@ -163,6 +176,13 @@ func (archiver *WALArchiver) ArchiveList(
 // Archive archives a certain WAL file using barman-cloud-wal-archive.
 // See archiveWALFileList for the meaning of the parameters
 func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error {
+	return archiver.ArchiveTo(walName, Destination{Env: archiver.env, Options: baseOptions})
+}
+
+// ArchiveTo archives a certain WAL file on a destination
+// using barman-cloud-wal-archive
+func (archiver *WALArchiver) ArchiveTo(walName string, destination Destination) error {
+	baseOptions := destination.Options
 	optionsLength := len(baseOptions)
 	if optionsLength >= math.MaxInt-1 {
 		return fmt.Errorf("can't archive wal file %v, options too long", walName)
@@ -179,7 +199,7 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	)
 
 	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, options...) // #nosec G204
-	barmanCloudWalArchiveCmd.Env = archiver.env
+	barmanCloudWalArchiveCmd.Env = destination.Env
 
 	err := execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudWalArchive)
 	if err != nil {
@@ -289,13 +309,9 @@ func (archiver *WALArchiver) BarmanCloudCheckWalArchiveOptions(
 		return nil, err
 	}
 
-	serverName := clusterName
-	if len(configuration.ServerName) != 0 {
-		serverName = configuration.ServerName
-	}
 	options = append(
 		options,
 		configuration.DestinationPath,
-		serverName)
+		configuration.GetServerName(clusterName))
 	return options, nil
 }
@@ -0,0 +1,237 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// Destination is an object store where the WAL files are archived
+type Destination struct {
+	// The name of the destination, i.e. its destination path and server
+	// name, identifying its queue of WAL files to be archived again
+	Name string
+
+	// The environment that should be used to invoke barman-cloud-wal-archive
+	Env []string
+
+	// The options of barman-cloud-wal-archive, not including the WAL name
+	Options []string
+}
+
+// DestinationName returns the name of the destination archiving the WAL
+// files of a cluster on an object store
+func DestinationName(configuration *apiv1.BarmanObjectStoreConfiguration, clusterName string) string {
+	return configuration.DestinationPath + "/" + configuration.GetServerName(clusterName)
+}
+
+// queueDirectoryName returns the name of the directory containing the
+// WAL files queued for this destination
+func (destination Destination) queueDirectoryName() string {
+	hash := sha256.Sum256([]byte(destination.Name))
+	return hex.EncodeToString(hash[:8])
+}
+
+// archiveToDestinations archives a WAL file on every destination in
+// parallel, succeeding when at least quorum destinations succeeded. The WAL
+// file is queued for the failed destinations, so that it can be archived
+// again later
+func (archiver *WALArchiver) archiveToDestinations(
+	ctx context.Context,
+	walName string,
+	destinations []Destination,
+	quorum int,
+) error {
+	if len(destinations) == 1 {
+		return archiver.archiveTo(walName, destinations[0])
+	}
+
+	errs := make([]error, len(destinations))
+	var waitGroup sync.WaitGroup
+	for idx := range destinations {
+		waitGroup.Add(1)
+		go func(destinationIndex int) {
+			defer waitGroup.Done()
+			errs[destinationIndex] = archiver.archiveTo(walName, destinations[destinationIndex])
+		}(idx)
+	}
+	waitGroup.Wait()
+
+	if err := checkQuorum(errs, quorum); err != nil {
+		return err
+	}
+
+	contextLog := log.FromContext(ctx)
+	for idx, destination := range destinations {
+		if errs[idx] == nil {
+			continue
+		}
+
+		// If we cannot queue the WAL file, we let PostgreSQL retry it
+		if err := archiver.enqueue(walName, destination); err != nil {
+			return fmt.Errorf("while queueing WAL file %s for %s: %w", walName, destination.Name, err)
+		}
+		contextLog.Warning("Queued WAL file failed on a destination, the quorum has been reached",
+			"walName", walName,
+			"destination", destination.Name,
+			"error", errs[idx])
+	}
+
+	return nil
+}
+
+// checkQuorum checks that at least quorum of the archival attempts
+// succeeded, returning the errors otherwise
+func checkQuorum(errs []error, quorum int) error {
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+
+	if succeeded >= quorum {
+		return nil
+	}
+
+	return fmt.Errorf("archived on %d of %d destinations, %d required: %w",
+		succeeded, len(errs), quorum, errors.Join(errs...))
+}
+
+// enqueue copies a WAL file in the queue of the destination, as
+// PostgreSQL may recycle it after a successful archival
+func (archiver *WALArchiver) enqueue(walName string, destination Destination) error {
+	queueDirectory := filepath.Join(archiver.pendingDirectory, destination.queueDirectoryName())
+	if err := fileutils.EnsureDirectoryExists(queueDirectory); err != nil {
+		return err
+	}
+
+	source := walName
+	if !filepath.IsAbs(source) {
+		source = filepath.Join(archiver.pgDataDirectory, walName)
+	}
+
+	// Temporary files are hidden, and renamed when complete
+	target := filepath.Join(queueDirectory, path.Base(walName))
+	temporaryTarget := filepath.Join(queueDirectory, "."+path.Base(walName))
+	if err := fileutils.CopyFile(source, temporaryTarget); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryTarget, target)
+}
+
+// ArchivePending archives again, on every destination, the oldest WAL
+// files queued after failing on it. A destination is skipped as soon as
+// one of its WAL files fails again, and at most maxFiles WAL files are
+// archived for each destination
+func (archiver *WALArchiver) ArchivePending(ctx context.Context, destinations []Destination, maxFiles int) {
+	contextLog := log.FromContext(ctx)
+
+	var waitGroup sync.WaitGroup
+	for _, destination := range destinations {
+		walFiles, err := archiver.pendingWALFiles(destination)
+		if err != nil {
+			contextLog.Error(err, "while listing the queued WAL files", "destination", destination.Name)
+			continue
+		}
+		if len(walFiles) == 0 {
+			continue
+		}
+
+		waitGroup.Add(1)
+		go func(destination Destination, walFiles []string) {
+			defer waitGroup.Done()
+			for idx, walFile := range walFiles {
+				if idx >= maxFiles {
+					break
+				}
+
+				if err := archiver.archiveTo(walFile, destination); err != nil {
+					contextLog.Warning("Failed archiving a queued WAL file, will retry",
+						"walName", path.Base(walFile),
+						"destination", destination.Name,
+						"queuedWALFiles", len(walFiles)-idx,
+						"error", err)
+					return
+				}
+
+				if err := fileutils.RemoveFile(walFile); err != nil {
+					contextLog.Error(err, "while removing an archived queued WAL file",
+						"walName", path.Base(walFile),
+						"destination", destination.Name)
+					return
+				}
+				contextLog.Info("Archived queued WAL file",
+					"walName", path.Base(walFile),
+					"destination", destination.Name)
+			}
+		}(destination, walFiles)
+	}
+
+	waitGroup.Wait()
+}
+
+// pendingWALFiles returns the paths of the WAL files queued for a
+// destination, sorted by name so that the oldest ones come first
+func (archiver *WALArchiver) pendingWALFiles(destination Destination) ([]string, error) {
+	return listQueue(filepath.Join(archiver.pendingDirectory, destination.queueDirectoryName()))
+}
+
+// CountPendingWALFiles returns the number of WAL files queued in the
+// pending directory for the destination with the passed name
+func CountPendingWALFiles(pendingDirectory string, destinationName string) (int, error) {
+	walFiles, err := listQueue(filepath.Join(pendingDirectory, Destination{Name: destinationName}.queueDirectoryName()))
+	return len(walFiles), err
+}
+
+// listQueue returns the paths of the WAL files in a queue directory,
+// sorted by name. A missing directory is an empty queue
+func listQueue(queueDirectory string) ([]string, error) {
+	names, err := fileutils.GetDirectoryContent(queueDirectory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	walFiles := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		walFiles = append(walFiles, filepath.Join(queueDirectory, name))
+	}
+	sort.Strings(walFiles)
+
+	return walFiles, nil
+}
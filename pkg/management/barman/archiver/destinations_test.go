@@ -0,0 +1,207 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/spool"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeObjectStores records the WAL files archived on each destination,
+// failing on the unavailable ones
+type fakeObjectStores struct {
+	mu          sync.Mutex
+	archived    map[string][]string
+	unavailable map[string]bool
+}
+
+func (f *fakeObjectStores) archiveTo(walName string, destination Destination) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.unavailable[destination.Name] {
+		return errors.New("object store unavailable")
+	}
+	f.archived[destination.Name] = append(f.archived[destination.Name], path.Base(walName))
+	return nil
+}
+
+var _ = Describe("WAL archiving on multiple destinations", func() {
+	const (
+		firstWAL  = "000000010000000000000001"
+		secondWAL = "000000010000000000000002"
+	)
+
+	var (
+		pgData       string
+		stores       *fakeObjectStores
+		walArchiver  *WALArchiver
+		destinations []Destination
+	)
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		pgData = filepath.Join(tempDir, "pgdata")
+		Expect(os.MkdirAll(filepath.Join(pgData, "pg_wal"), 0o750)).To(Succeed())
+		for _, walName := range []string{firstWAL, secondWAL} {
+			Expect(os.WriteFile(filepath.Join(pgData, "pg_wal", walName), []byte(walName), 0o600)).To(Succeed())
+		}
+
+		walSpool, err := spool.New(filepath.Join(tempDir, "spool"))
+		Expect(err).ToNot(HaveOccurred())
+
+		stores = &fakeObjectStores{
+			archived:    make(map[string][]string),
+			unavailable: make(map[string]bool),
+		}
+		walArchiver = &WALArchiver{
+			cluster:          &apiv1.Cluster{},
+			spool:            walSpool,
+			pgDataDirectory:  pgData,
+			pendingDirectory: filepath.Join(tempDir, "pending"),
+			archiveTo:        stores.archiveTo,
+		}
+		destinations = []Destination{
+			{Name: "s3://bucket/cluster-example"},
+			{Name: "gs://bucket/cluster-example"},
+		}
+	})
+
+	It("archives the WAL files on every destination", func(ctx context.Context) {
+		result := walArchiver.ArchiveList(ctx,
+			[]string{"pg_wal/" + firstWAL, "pg_wal/" + secondWAL}, destinations, 2)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Err).ToNot(HaveOccurred())
+		Expect(result[1].Err).ToNot(HaveOccurred())
+		Expect(stores.archived["s3://bucket/cluster-example"]).To(ConsistOf(firstWAL, secondWAL))
+		Expect(stores.archived["gs://bucket/cluster-example"]).To(ConsistOf(firstWAL, secondWAL))
+
+		// Only the WAL files archived in parallel are in the spool
+		Expect(walArchiver.spool.Contains(firstWAL)).To(BeFalse())
+		Expect(walArchiver.spool.Contains(secondWAL)).To(BeTrue())
+	})
+
+	It("fails when a destination fails and all of them are required", func(ctx context.Context) {
+		stores.unavailable["gs://bucket/cluster-example"] = true
+
+		result := walArchiver.ArchiveList(ctx, []string{"pg_wal/" + firstWAL}, destinations, 2)
+		Expect(result[0].Err).To(MatchError(ContainSubstring("archived on 1 of 2 destinations, 2 required")))
+		Expect(result[0].Err).To(MatchError(ContainSubstring("object store unavailable")))
+
+		pending, err := walArchiver.pendingWALFiles(destinations[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("succeeds with the quorum, queueing the WAL file for the failed destination", func(ctx context.Context) {
+		stores.unavailable["gs://bucket/cluster-example"] = true
+
+		result := walArchiver.ArchiveList(ctx,
+			[]string{"pg_wal/" + firstWAL, "pg_wal/" + secondWAL}, destinations, 1)
+		Expect(result[0].Err).ToNot(HaveOccurred())
+		Expect(result[1].Err).ToNot(HaveOccurred())
+
+		pending, err := walArchiver.pendingWALFiles(destinations[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(HaveLen(2))
+		Expect(path.Base(pending[0])).To(Equal(firstWAL))
+		content, err := os.ReadFile(pending[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal(firstWAL))
+
+		pending, err = walArchiver.pendingWALFiles(destinations[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+
+		count, err := CountPendingWALFiles(walArchiver.pendingDirectory, "gs://bucket/cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(count).To(Equal(2))
+	})
+
+	It("fails when no destination reaches the quorum", func(ctx context.Context) {
+		stores.unavailable["s3://bucket/cluster-example"] = true
+		stores.unavailable["gs://bucket/cluster-example"] = true
+
+		result := walArchiver.ArchiveList(ctx, []string{"pg_wal/" + firstWAL}, destinations, 1)
+		Expect(result[0].Err).To(MatchError(ContainSubstring("archived on 0 of 2 destinations, 1 required")))
+	})
+
+	It("archives the queued WAL files when the destination is back", func(ctx context.Context) {
+		stores.unavailable["gs://bucket/cluster-example"] = true
+		walArchiver.ArchiveList(ctx, []string{"pg_wal/" + firstWAL, "pg_wal/" + secondWAL}, destinations, 1)
+
+		// Still unavailable: the queue is kept
+		walArchiver.ArchivePending(ctx, destinations, 10)
+		pending, err := walArchiver.pendingWALFiles(destinations[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(HaveLen(2))
+
+		// Back online: at most the requested number of files is archived
+		stores.unavailable["gs://bucket/cluster-example"] = false
+		walArchiver.ArchivePending(ctx, destinations, 1)
+		Expect(stores.archived["gs://bucket/cluster-example"]).To(Equal([]string{firstWAL}))
+
+		walArchiver.ArchivePending(ctx, destinations, 1)
+		Expect(stores.archived["gs://bucket/cluster-example"]).To(Equal([]string{firstWAL, secondWAL}))
+		pending, err = walArchiver.pendingWALFiles(destinations[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("archives on a single destination without queueing", func(ctx context.Context) {
+		stores.unavailable["s3://bucket/cluster-example"] = true
+
+		result := walArchiver.ArchiveList(ctx, []string{"pg_wal/" + firstWAL}, destinations[:1], 1)
+		Expect(result[0].Err).To(MatchError("object store unavailable"))
+	})
+})
+
+var _ = Describe("quorum check", func() {
+	failure := errors.New("failure")
+
+	It("succeeds when enough destinations succeeded", func() {
+		Expect(checkQuorum([]error{nil, nil}, 2)).To(Succeed())
+		Expect(checkQuorum([]error{nil, failure}, 1)).To(Succeed())
+		Expect(checkQuorum([]error{failure, nil, nil}, 2)).To(Succeed())
+	})
+
+	It("fails when not enough destinations succeeded", func() {
+		Expect(checkQuorum([]error{nil, failure}, 2)).To(MatchError(failure))
+		Expect(checkQuorum([]error{failure, failure, nil}, 2)).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("destination names", func() {
+	It("uses the server name, defaulting to the cluster name", func() {
+		Expect(DestinationName(&apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket"},
+			"cluster-example")).To(Equal("s3://bucket/cluster-example"))
+		Expect(DestinationName(&apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://bucket",
+			ServerName:      "cluster-dr",
+		}, "cluster-example")).To(Equal("s3://bucket/cluster-dr"))
+	})
+})
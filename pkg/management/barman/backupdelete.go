@@ -176,8 +176,6 @@ func useSameBackupLocation(backup *v1.BackupStatus, cluster *v1.Cluster) bool {
 	configuration := cluster.Spec.Backup.BarmanObjectStore
 	return backup.EndpointURL == configuration.EndpointURL &&
 		backup.DestinationPath == configuration.DestinationPath &&
-		(backup.ServerName == configuration.ServerName ||
-			// if not specified we use the cluster name as server name
-			(configuration.ServerName == "" && backup.ServerName == cluster.Name)) &&
+		backup.ServerName == configuration.GetServerName(cluster.Name) &&
 		reflect.DeepEqual(backup.BarmanCredentials, configuration.BarmanCredentials)
 }
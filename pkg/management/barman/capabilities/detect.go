@@ -51,6 +51,14 @@ func Detect() (*Capabilities, error) {
 		// The --name flag was added to Barman in version 3.3 but we also require the
 		// barman-cloud-backup-show command which was not added until Barman version 3.4
 		newCapabilities.hasName = true
+		// The bandwidth limit of the uploads, added in Barman >= 3.4
+		newCapabilities.HasMaxBandwidth = true
+		fallthrough
+	case version.GE(semver.Version{Major: 2, Minor: 19}):
+		// Google Cloud Storage support, added in Barman >= 2.19
+		newCapabilities.HasGoogle = true
+		// Custom KMS key for the server-side encryption, added in Barman >= 2.19
+		newCapabilities.HasSSEKMSKeyID = true
 		fallthrough
 	case version.GE(semver.Version{Major: 2, Minor: 18}):
 		// Tags, added in Barman >= 2.18
@@ -72,10 +80,6 @@ func Detect() (*Capabilities, error) {
 		// Cloud providers support, added in Barman >= 2.13
 		newCapabilities.HasAzure = true
 		newCapabilities.HasS3 = true
-		fallthrough
-	case version.GE(semver.Version{Major: 2, Minor: 19}):
-		// Google Cloud Storage support, added in Barman >= 2.19
-		newCapabilities.HasGoogle = true
 	}
 
 	log.Debug("Detected Barman installation", "newCapabilities", newCapabilities)
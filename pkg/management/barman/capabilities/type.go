@@ -37,6 +37,8 @@ type Capabilities struct {
 	HasSnappy                  bool
 	HasErrorCodesForWALRestore bool
 	HasAzureManagedIdentity    bool
+	HasSSEKMSKeyID             bool
+	HasMaxBandwidth            bool
 }
 
 // ShouldExecuteBackupWithName returns true if the new backup logic should be executed
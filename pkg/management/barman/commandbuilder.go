@@ -17,10 +17,13 @@ limitations under the License.
 package barman
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/kms"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
 
@@ -42,13 +45,48 @@ func CloudWalRestoreOptions(
 		return nil, err
 	}
 
-	serverName := clusterName
-	if len(configuration.ServerName) != 0 {
-		serverName = configuration.ServerName
+	options = append(options, configuration.DestinationPath, configuration.GetServerName(clusterName))
+	return options, nil
+}
+
+// CloudWalRestoreCommand returns the barman-cloud-wal-restore command used
+// as restore_command while recovering from a backup, limiting the bandwidth
+// of the downloads when requested
+func CloudWalRestoreCommand(backup *v1.Backup, maxBandwidth *int64) ([]string, error) {
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return nil, err
 	}
 
-	options = append(options, configuration.DestinationPath, serverName)
-	return options, nil
+	return cloudWalRestoreCommand(backup, maxBandwidth, capabilities)
+}
+
+func cloudWalRestoreCommand(
+	backup *v1.Backup,
+	maxBandwidth *int64,
+	capabilities *barmanCapabilities.Capabilities,
+) ([]string, error) {
+	cmd := []string{barmanCapabilities.BarmanCloudWalRestore}
+	if backup.Status.EndpointURL != "" {
+		cmd = append(cmd, "--endpoint-url", backup.Status.EndpointURL)
+	}
+
+	// PostgreSQL runs the restore_command for one WAL file at a time, so
+	// limiting the bandwidth of each download throttles the whole recovery
+	cmd, err := appendMaxBandwidthOptions(cmd, maxBandwidth, capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd = append(cmd, backup.Status.DestinationPath)
+	cmd = append(cmd, backup.Status.ServerName)
+
+	cmd, err = AppendCloudProviderOptionsFromBackup(cmd, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(cmd, "%f", "%p"), nil
 }
 
 // AppendCloudProviderOptionsFromConfiguration takes an options array and adds the cloud provider specified
@@ -130,3 +168,92 @@ func appendCloudProviderOptions(options []string, credentials v1.BarmanCredentia
 
 	return options, nil
 }
+
+// AppendEncryptionOptions takes an options array and adds the options needed
+// for the envelope encryption specified in the Barman configuration object.
+// The encryption option passed is used when no envelope encryption is configured
+func AppendEncryptionOptions(
+	ctx context.Context,
+	options []string,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	encryption v1.EncryptionType,
+) ([]string, error) {
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	return appendEncryptionOptions(ctx, options, barmanConfiguration.Encryption, encryption, capabilities)
+}
+
+func appendEncryptionOptions(
+	ctx context.Context,
+	options []string,
+	configuration *v1.BarmanEncryptionConfiguration,
+	encryption v1.EncryptionType,
+	capabilities *barmanCapabilities.Capabilities,
+) ([]string, error) {
+	if configuration == nil {
+		if len(encryption) != 0 {
+			options = append(options, "--encryption", string(encryption))
+		}
+		return options, nil
+	}
+
+	if configuration.Method != v1.BarmanEncryptionMethodSSEKMS {
+		return nil, fmt.Errorf("unsupported encryption method: %s", configuration.Method)
+	}
+
+	if !capabilities.HasSSEKMSKeyID {
+		return nil, fmt.Errorf(
+			"barman >= 2.19 is required to use the sse-kms encryption, current: %v",
+			capabilities.Version)
+	}
+
+	dataKey, err := kms.GetDataKey(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(
+		options,
+		"--encryption",
+		string(v1.EncryptionTypeNoneAWSKMS),
+		"--sse-kms-key-id",
+		dataKey.ID), nil
+}
+
+// AppendMaxBandwidthOptions takes an options array and adds the bandwidth
+// limit of the uploads specified in the Barman configuration object
+func AppendMaxBandwidthOptions(
+	options []string,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+) ([]string, error) {
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	return appendMaxBandwidthOptions(options, barmanConfiguration.MaxBandwidth, capabilities)
+}
+
+func appendMaxBandwidthOptions(
+	options []string,
+	maxBandwidth *int64,
+	capabilities *barmanCapabilities.Capabilities,
+) ([]string, error) {
+	if maxBandwidth == nil {
+		return options, nil
+	}
+
+	if !capabilities.HasMaxBandwidth {
+		return nil, fmt.Errorf(
+			"barman >= 3.4 is required to limit the bandwidth, current: %v",
+			capabilities.Version)
+	}
+
+	return append(
+		options,
+		"--max-bandwidth",
+		strconv.FormatInt(*maxBandwidth, 10)), nil
+}
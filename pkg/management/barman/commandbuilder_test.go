@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"context"
+
+	"github.com/blang/semver"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman-cloud encryption options", func() {
+	const keyARN = "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	capabilities := &barmanCapabilities.Capabilities{
+		Version:        &semver.Version{Major: 3, Minor: 10},
+		HasSSEKMSKeyID: true,
+	}
+	kmsEncryption := &apiv1.BarmanEncryptionConfiguration{
+		Method: apiv1.BarmanEncryptionMethodSSEKMS,
+		KeyID:  keyARN,
+	}
+
+	It("adds nothing without encryption", func(ctx context.Context) {
+		options, err := appendEncryptionOptions(ctx, []string{"--gzip"}, nil, apiv1.EncryptionTypeNone, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--gzip"}))
+	})
+
+	It("uses the encryption type when the envelope encryption is not configured", func(ctx context.Context) {
+		options, err := appendEncryptionOptions(ctx, nil, nil, apiv1.EncryptionTypeAES256, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--encryption", "AES256"}))
+	})
+
+	It("passes the KMS key to barman-cloud", func(ctx context.Context) {
+		options, err := appendEncryptionOptions(
+			ctx, []string{"--gzip"}, kmsEncryption, apiv1.EncryptionTypeAES256, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{
+			"--gzip",
+			"--encryption", "aws:kms",
+			"--sse-kms-key-id", keyARN,
+		}))
+	})
+
+	It("requires a barman-cloud version supporting the KMS keys", func(ctx context.Context) {
+		_, err := appendEncryptionOptions(ctx, nil, kmsEncryption, apiv1.EncryptionTypeNone,
+			&barmanCapabilities.Capabilities{Version: &semver.Version{Major: 2, Minor: 18}})
+		Expect(err).To(MatchError(ContainSubstring("barman >= 2.19")))
+	})
+
+	It("refuses the unknown encryption methods", func(ctx context.Context) {
+		_, err := appendEncryptionOptions(ctx, nil,
+			&apiv1.BarmanEncryptionConfiguration{Method: "client-side", KeyID: keyARN},
+			apiv1.EncryptionTypeNone, capabilities)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("barman-cloud bandwidth limit options", func() {
+	capabilities := &barmanCapabilities.Capabilities{
+		Version:         &semver.Version{Major: 3, Minor: 10},
+		HasMaxBandwidth: true,
+	}
+
+	It("adds nothing without a bandwidth limit", func() {
+		options, err := appendMaxBandwidthOptions([]string{"--gzip"}, nil, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--gzip"}))
+	})
+
+	It("passes the bandwidth limit to barman-cloud", func() {
+		options, err := appendMaxBandwidthOptions([]string{"--gzip"}, ptr.To(int64(10485760)), capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--gzip", "--max-bandwidth", "10485760"}))
+	})
+
+	It("requires a barman-cloud version supporting the bandwidth limit", func() {
+		_, err := appendMaxBandwidthOptions(nil, ptr.To(int64(10485760)),
+			&barmanCapabilities.Capabilities{Version: &semver.Version{Major: 3, Minor: 3}})
+		Expect(err).To(MatchError(ContainSubstring("barman >= 3.4")))
+	})
+})
+
+var _ = Describe("barman-cloud-wal-restore options", func() {
+	configuration := &apiv1.BarmanObjectStoreConfiguration{
+		DestinationPath: "s3://bucket/path",
+		BarmanCredentials: apiv1.BarmanCredentials{
+			AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+		},
+	}
+
+	It("restores from the folder of the cluster by default", func() {
+		options, err := CloudWalRestoreOptions(configuration, "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options[len(options)-2:]).To(Equal([]string{"s3://bucket/path", "cluster-example"}))
+	})
+
+	It("restores from the folder of the configured server name", func() {
+		withServerName := configuration.DeepCopy()
+		withServerName.ServerName = "cluster-example-v2"
+		options, err := CloudWalRestoreOptions(withServerName, "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options[len(options)-2:]).To(Equal([]string{"s3://bucket/path", "cluster-example-v2"}))
+	})
+})
+
+var _ = Describe("barman-cloud-wal-restore recovery command", func() {
+	backup := &apiv1.Backup{
+		Status: apiv1.BackupStatus{
+			BarmanCredentials: apiv1.BarmanCredentials{
+				AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+			},
+			EndpointURL:     "https://s3.example.com",
+			DestinationPath: "s3://bucket/path",
+			ServerName:      "cluster-example",
+		},
+	}
+	capabilities := &barmanCapabilities.Capabilities{
+		Version:         &semver.Version{Major: 3, Minor: 10},
+		HasMaxBandwidth: true,
+	}
+
+	It("doesn't throttle the downloads by default", func() {
+		cmd, err := cloudWalRestoreCommand(backup, nil, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmd).ToNot(ContainElement("--max-bandwidth"))
+		Expect(cmd[0]).To(Equal(barmanCapabilities.BarmanCloudWalRestore))
+		Expect(cmd[len(cmd)-2:]).To(Equal([]string{"%f", "%p"}))
+	})
+
+	It("limits the bandwidth of the downloads", func() {
+		cmd, err := cloudWalRestoreCommand(backup, ptr.To(int64(1048576)), capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmd[:7]).To(Equal([]string{
+			barmanCapabilities.BarmanCloudWalRestore,
+			"--endpoint-url", "https://s3.example.com",
+			"--max-bandwidth", "1048576",
+			"s3://bucket/path", "cluster-example",
+		}))
+		Expect(cmd[len(cmd)-2:]).To(Equal([]string{"%f", "%p"}))
+	})
+
+	It("requires a barman-cloud version supporting the bandwidth limit", func() {
+		_, err := cloudWalRestoreCommand(backup, ptr.To(int64(1048576)),
+			&barmanCapabilities.Capabilities{Version: &semver.Version{Major: 3, Minor: 3}})
+		Expect(err).To(MatchError(ContainSubstring("barman >= 3.4")))
+	})
+})
@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman endpoint CA environment", func() {
+	endpointCA := &apiv1.SecretKeySelector{
+		LocalObjectReference: apiv1.LocalObjectReference{Name: "endpoint-ca"},
+		Key:                  "ca.crt",
+	}
+	awsCredentials := apiv1.BarmanCredentials{
+		AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+	}
+	azureCredentials := apiv1.BarmanCredentials{
+		Azure: &apiv1.AzureCredentials{InheritFromAzureAD: true},
+	}
+
+	It("points the AWS client to the backup CA bundle", func(ctx SpecContext) {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: awsCredentials,
+			EndpointCA:        endpointCA,
+		}
+
+		env, err := EnvSetBackupCloudCredentials(ctx, nil, "default", configuration, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("AWS_CA_BUNDLE=" + postgres.BarmanBackupEndpointCACertificateLocation))
+	})
+
+	It("points the Azure client to the restore CA bundle", func(ctx SpecContext) {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: azureCredentials,
+			EndpointCA:        endpointCA,
+		}
+
+		env, err := EnvSetRestoreCloudCredentials(ctx, nil, "default", configuration, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("REQUESTS_CA_BUNDLE=" + postgres.BarmanRestoreEndpointCACertificateLocation))
+	})
+
+	It("doesn't set any CA bundle when no endpoint CA is configured", func(ctx SpecContext) {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: awsCredentials,
+		}
+
+		env, err := EnvSetBackupCloudCredentials(ctx, nil, "default", configuration, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(BeEmpty())
+	})
+})
@@ -0,0 +1,37 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// awsProvider uses the AWS Key Management Service. S3 requests the data
+// keys to the KMS by itself, so the key is passed to barman-cloud as is
+type awsProvider struct{}
+
+// GetDataKey implements the Provider interface
+func (awsProvider) GetDataKey(_ context.Context, keyID string) (*DataKey, error) {
+	keyID = strings.TrimSpace(keyID)
+	if keyID == "" {
+		return nil, errors.New("missing KMS key ID")
+	}
+
+	return &DataKey{ID: keyID}, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kms fetches the keys used for the envelope encryption of the
+// backups from the Key Management Services
+package kms
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// DataKey is the reference to the key encrypting the data keys of the
+// backups, in the format expected by barman-cloud
+type DataKey struct {
+	// ID is the identifier of the key passed to barman-cloud
+	ID string
+}
+
+// Provider fetches the keys from a KMS
+type Provider interface {
+	// GetDataKey gets the key encrypting the data keys given the
+	// reference in the encryption configuration
+	GetDataKey(ctx context.Context, keyID string) (*DataKey, error)
+}
+
+var (
+	providersMutex sync.RWMutex
+	providers      = map[apiv1.KMSProvider]Provider{
+		apiv1.KMSProviderAWS: awsProvider{},
+	}
+)
+
+// RegisterProvider registers a KMS provider, replacing the one
+// previously registered with the same name
+func RegisterProvider(name apiv1.KMSProvider, provider Provider) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	providers[name] = provider
+}
+
+// GetProvider gets the KMS provider registered with the passed name
+func GetProvider(name apiv1.KMSProvider) (Provider, error) {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+
+	provider, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown KMS provider: %s", name)
+	}
+	return provider, nil
+}
+
+// GetDataKey gets the key encrypting the data keys of the backups from
+// the KMS provider selected in the encryption configuration
+func GetDataKey(ctx context.Context, encryption *apiv1.BarmanEncryptionConfiguration) (*DataKey, error) {
+	provider, err := GetProvider(encryption.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := provider.GetDataKey(ctx, encryption.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the data key from the %s KMS: %w", encryption.GetProvider(), err)
+	}
+	return dataKey, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeProvider struct{}
+
+func (fakeProvider) GetDataKey(_ context.Context, keyID string) (*DataKey, error) {
+	return &DataKey{ID: "resolved/" + keyID}, nil
+}
+
+var _ = Describe("KMS providers", func() {
+	const keyARN = "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	It("uses the AWS provider by default", func(ctx context.Context) {
+		dataKey, err := GetDataKey(ctx, &apiv1.BarmanEncryptionConfiguration{
+			Method: apiv1.BarmanEncryptionMethodSSEKMS,
+			KeyID:  keyARN,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dataKey.ID).To(Equal(keyARN))
+	})
+
+	It("refuses an empty key ID", func(ctx context.Context) {
+		_, err := GetDataKey(ctx, &apiv1.BarmanEncryptionConfiguration{
+			Method: apiv1.BarmanEncryptionMethodSSEKMS,
+			KeyID:  " ",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses an unknown provider", func(ctx context.Context) {
+		_, err := GetDataKey(ctx, &apiv1.BarmanEncryptionConfiguration{
+			Method:   apiv1.BarmanEncryptionMethodSSEKMS,
+			KeyID:    keyARN,
+			Provider: "unknown",
+		})
+		Expect(err).To(MatchError(ContainSubstring("unknown KMS provider")))
+	})
+
+	It("uses the registered providers", func(ctx context.Context) {
+		const name = apiv1.KMSProvider("fake")
+		RegisterProvider(name, fakeProvider{})
+		DeferCleanup(func() {
+			providersMutex.Lock()
+			defer providersMutex.Unlock()
+			delete(providers, name)
+		})
+
+		dataKey, err := GetDataKey(ctx, &apiv1.BarmanEncryptionConfiguration{
+			Method:   apiv1.BarmanEncryptionMethodSSEKMS,
+			KeyID:    "backups",
+			Provider: name,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dataKey.ID).To(Equal("resolved/backups"))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+const prometheusNamespace = "cnpg"
+
+// operationDescs contains the descriptions of the metrics of an operation
+type operationDescs struct {
+	tracker          *Tracker
+	inProgress       *prometheus.Desc
+	startTime        *prometheus.Desc
+	bytesTransferred *prometheus.Desc
+	progressRatio    *prometheus.Desc
+}
+
+func newOperationDescs(tracker *Tracker, operation string) operationDescs {
+	return operationDescs{
+		tracker: tracker,
+		inProgress: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, operation, "in_progress"),
+			fmt.Sprintf("1 if a %s is running, 0 otherwise", operation),
+			nil, nil),
+		startTime: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, operation, "start_time_seconds"),
+			fmt.Sprintf("The time the running %s started, in seconds since the Unix epoch", operation),
+			nil, nil),
+		bytesTransferred: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, operation, "bytes_transferred"),
+			fmt.Sprintf("The bytes transferred by the running %s", operation),
+			nil, nil),
+		progressRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, operation, "progress_ratio"),
+			fmt.Sprintf("The completed fraction of the running %s, between 0 and 1", operation),
+			nil, nil),
+	}
+}
+
+// Collector exports the progress of the backups and of the restores
+type Collector struct {
+	enabled    func() bool
+	operations []operationDescs
+}
+
+// NewCollector creates a collector of the progress metrics, that are
+// exported only when the enabled function returns true
+func NewCollector(enabled func() bool) *Collector {
+	return &Collector{
+		enabled: enabled,
+		operations: []operationDescs{
+			newOperationDescs(Backup, "backup"),
+			newOperationDescs(Restore, "restore"),
+		},
+	}
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, operation := range c.operations {
+		ch <- operation.inProgress
+		ch <- operation.startTime
+		ch <- operation.bytesTransferred
+		ch <- operation.progressRatio
+	}
+}
+
+// Collect implements the prometheus.Collector interface. The start time
+// is reported only while the operation is running, and the progress only
+// when it can be measured
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	if !c.enabled() {
+		return
+	}
+
+	for _, operation := range c.operations {
+		snapshot := operation.tracker.Snapshot()
+
+		var inProgress float64
+		if snapshot.Running {
+			inProgress = 1
+		}
+		ch <- prometheus.MustNewConstMetric(operation.inProgress, prometheus.GaugeValue, inProgress)
+
+		if snapshot.Running {
+			ch <- prometheus.MustNewConstMetric(operation.startTime, prometheus.GaugeValue,
+				float64(snapshot.StartedAt.UnixNano())/float64(time.Second))
+		}
+		if snapshot.HasBytes {
+			ch <- prometheus.MustNewConstMetric(
+				operation.bytesTransferred, prometheus.GaugeValue, snapshot.BytesTransferred)
+		}
+		if snapshot.HasRatio {
+			ch <- prometheus.MustNewConstMetric(
+				operation.progressRatio, prometheus.GaugeValue, snapshot.Ratio)
+		}
+	}
+}
+
+// ServeMetrics exposes the progress metrics on the port of the metrics
+// exporter until the context is done. It is meant for the processes not
+// running the metrics exporter, like the recovery job
+func ServeMetrics(ctx context.Context) error {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(NewCollector(func() bool { return true })); err != nil {
+		return fmt.Errorf("while registering the progress metrics: %w", err)
+	}
+
+	serveMux := http.NewServeMux()
+	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	// the timeouts are the same of the webservers of the instance manager
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", url.PostgresMetricsPort),
+		Handler:           serveMux,
+		ReadTimeout:       20 * time.Second,
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Error(err, "Error while shutting down the progress metrics server")
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package progress tracks the barman-cloud commands taking a backup
+// or restoring it, and exports their state as metrics
+package progress
+
+import (
+	"errors"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+)
+
+// BytesFunc gets the bytes transferred so far by a running command
+type BytesFunc func() (int64, error)
+
+// Tracker keeps track of a barman-cloud command while it runs. The
+// barman-cloud commands don't report the amount of data transferred, which
+// is known only when it can be measured from outside the command, like the
+// size of the data directory being restored
+type Tracker struct {
+	mu sync.Mutex
+
+	running   bool
+	startedAt time.Time
+
+	// measure is nil when the bytes transferred can't be measured
+	measure BytesFunc
+
+	// totalBytes is zero when the size of the whole operation is unknown
+	totalBytes int64
+}
+
+var (
+	// Backup tracks the progress of barman-cloud-backup
+	Backup = &Tracker{}
+
+	// Restore tracks the progress of barman-cloud-restore
+	Restore = &Tracker{}
+)
+
+// Run executes the command redirecting its stdout and stderr to the logger,
+// like execlog.RunStreaming does, tracking it until it terminates
+func (t *Tracker) Run(cmd *exec.Cmd, cmdName string) error {
+	return t.RunMeasuring(cmd, cmdName, nil, 0)
+}
+
+// RunMeasuring is like Run, also measuring the bytes transferred by the
+// command with the passed function. The completed fraction of the operation
+// is known only when its total size in bytes is greater than zero
+func (t *Tracker) RunMeasuring(cmd *exec.Cmd, cmdName string, measure BytesFunc, totalBytes int64) error {
+	t.start(measure, totalBytes)
+	defer t.reset()
+
+	return execlog.RunStreaming(cmd, cmdName)
+}
+
+// Snapshot is the state of a Tracker at a certain moment
+type Snapshot struct {
+	Running bool
+
+	// StartedAt is the zero time when the command is not running
+	StartedAt time.Time
+
+	// HasBytes and HasRatio are false if the corresponding progress
+	// can't be measured
+	HasBytes         bool
+	BytesTransferred float64
+	HasRatio         bool
+	Ratio            float64
+}
+
+// Snapshot gets the current state of the tracker, measuring the
+// bytes transferred so far if possible
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	result := Snapshot{
+		Running:   t.running,
+		StartedAt: t.startedAt,
+	}
+	measure, totalBytes := t.measure, t.totalBytes
+	t.mu.Unlock()
+
+	if !result.Running || measure == nil {
+		return result
+	}
+
+	// the measure may take a while, and is done without holding the lock
+	transferred, err := measure()
+	if err != nil {
+		return result
+	}
+
+	result.HasBytes = true
+	result.BytesTransferred = float64(transferred)
+	if totalBytes > 0 {
+		result.HasRatio = true
+		result.Ratio = min(float64(transferred)/float64(totalBytes), 1)
+	}
+
+	return result
+}
+
+func (t *Tracker) start(measure BytesFunc, totalBytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.running = true
+	t.startedAt = time.Now()
+	t.measure = measure
+	t.totalBytes = totalBytes
+}
+
+// reset clears the state when the command has terminated
+func (t *Tracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.running = false
+	t.startedAt = time.Time{}
+	t.measure = nil
+	t.totalBytes = 0
+}
+
+// DirectorySize measures the bytes written to a directory, summing the
+// size of the regular files it contains. The files removed while the
+// directory is being walked are skipped
+func DirectorySize(path string) BytesFunc {
+	return func() (int64, error) {
+		var size int64
+		err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+			return nil
+		})
+		return size, err
+	}
+}
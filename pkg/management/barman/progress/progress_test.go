@@ -0,0 +1,177 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("progress tracking", func() {
+	It("tracks the command while it runs", func() {
+		tracker := &Tracker{}
+		Expect(tracker.Snapshot().Running).To(BeFalse())
+
+		before := time.Now()
+		tracker.start(nil, 0)
+		snapshot := tracker.Snapshot()
+		Expect(snapshot.Running).To(BeTrue())
+		Expect(snapshot.StartedAt).To(BeTemporally(">=", before))
+
+		tracker.reset()
+		Expect(tracker.Snapshot()).To(Equal(Snapshot{}))
+	})
+
+	It("measures the bytes transferred and the completed fraction", func() {
+		var transferred int64
+		tracker := &Tracker{}
+		tracker.start(func() (int64, error) { return transferred, nil }, 4096)
+
+		transferred = 1024
+		snapshot := tracker.Snapshot()
+		Expect(snapshot.HasBytes).To(BeTrue())
+		Expect(snapshot.BytesTransferred).To(BeEquivalentTo(1024))
+		Expect(snapshot.HasRatio).To(BeTrue())
+		Expect(snapshot.Ratio).To(BeNumerically("==", 0.25))
+
+		// the size of the backup may be lower than the restored data
+		transferred = 8192
+		Expect(tracker.Snapshot().Ratio).To(BeNumerically("==", 1))
+
+		tracker.reset()
+		Expect(tracker.Snapshot()).To(Equal(Snapshot{}))
+	})
+
+	It("doesn't report the completed fraction without the total size", func() {
+		tracker := &Tracker{}
+		tracker.start(func() (int64, error) { return 1024, nil }, 0)
+
+		snapshot := tracker.Snapshot()
+		Expect(snapshot.HasBytes).To(BeTrue())
+		Expect(snapshot.HasRatio).To(BeFalse())
+	})
+
+	It("doesn't report the progress when it can't be measured", func() {
+		tracker := &Tracker{}
+		tracker.start(func() (int64, error) { return 0, errors.New("cannot measure") }, 4096)
+
+		snapshot := tracker.Snapshot()
+		Expect(snapshot.Running).To(BeTrue())
+		Expect(snapshot.HasBytes).To(BeFalse())
+		Expect(snapshot.HasRatio).To(BeFalse())
+	})
+
+	It("returns the error of the command", func() {
+		tracker := &Tracker{}
+		Expect(tracker.Run(exec.Command("sh", "-c", "exit 1"), "test")).ToNot(Succeed())
+		Expect(tracker.Snapshot().Running).To(BeFalse())
+	})
+})
+
+var _ = Describe("directory size", func() {
+	It("sums the size of the regular files", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "base", "1"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "base", "1", "1259"), make([]byte, 8192), 0o600)).To(Succeed())
+		Expect(os.Symlink("/nonexistent", filepath.Join(dir, "pg_wal"))).To(Succeed())
+
+		size, err := DirectorySize(dir)()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeEquivalentTo(8195))
+	})
+
+	It("measures nothing while the directory doesn't exist", func() {
+		size, err := DirectorySize(filepath.Join(GinkgoT().TempDir(), "pgdata"))()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeZero())
+	})
+})
+
+var _ = Describe("progress metrics", func() {
+	var tracker *Tracker
+
+	BeforeEach(func() {
+		tracker = Backup
+		DeferCleanup(tracker.reset)
+	})
+
+	It("exports the start time of the running backup", func() {
+		tracker.start(nil, 0)
+		tracker.startedAt = time.Unix(1715335200, 0)
+
+		collector := NewCollector(func() bool { return true })
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP cnpg_backup_in_progress 1 if a backup is running, 0 otherwise
+# TYPE cnpg_backup_in_progress gauge
+cnpg_backup_in_progress 1
+# HELP cnpg_backup_start_time_seconds The time the running backup started, in seconds since the Unix epoch
+# TYPE cnpg_backup_start_time_seconds gauge
+cnpg_backup_start_time_seconds 1.7153352e+09
+`), "cnpg_backup_in_progress", "cnpg_backup_start_time_seconds")).To(Succeed())
+	})
+
+	It("exports the progress of the running restore", func() {
+		DeferCleanup(Restore.reset)
+		Restore.start(func() (int64, error) { return 1024, nil }, 4096)
+
+		collector := NewCollector(func() bool { return true })
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP cnpg_restore_bytes_transferred The bytes transferred by the running restore
+# TYPE cnpg_restore_bytes_transferred gauge
+cnpg_restore_bytes_transferred 1024
+# HELP cnpg_restore_progress_ratio The completed fraction of the running restore, between 0 and 1
+# TYPE cnpg_restore_progress_ratio gauge
+cnpg_restore_progress_ratio 0.25
+`), "cnpg_restore_bytes_transferred", "cnpg_restore_progress_ratio",
+			"cnpg_backup_bytes_transferred", "cnpg_backup_progress_ratio")).To(Succeed())
+	})
+
+	It("resets the state when the backup completes", func() {
+		Expect(tracker.Run(exec.Command("sh", "-c", "echo 'Starting backup'"), "test")).To(Succeed())
+
+		collector := NewCollector(func() bool { return true })
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP cnpg_backup_in_progress 1 if a backup is running, 0 otherwise
+# TYPE cnpg_backup_in_progress gauge
+cnpg_backup_in_progress 0
+`), "cnpg_backup_in_progress", "cnpg_backup_start_time_seconds")).To(Succeed())
+	})
+
+	It("doesn't export anything when disabled", func() {
+		tracker.start(nil, 0)
+		collector := NewCollector(func() bool { return false })
+		Expect(testutil.CollectAndCount(collector)).To(BeZero())
+	})
+
+	It("describes the metrics of both the operations", func() {
+		ch := make(chan *prometheus.Desc, 10)
+		NewCollector(func() bool { return true }).Describe(ch)
+		close(ch)
+		Expect(ch).To(HaveLen(8))
+	})
+})
@@ -56,7 +56,7 @@ func NewCatalogFromBarmanCloudBackupList(rawJSON string) (*Catalog, error) {
 	return result, nil
 }
 
-var currentTLIRegex = regexp.MustCompile("^(|latest)$")
+var currentTLIRegex = regexp.MustCompile("^(|latest|current)$")
 
 // LatestBackupInfo gets the information about the latest successful backup
 func (catalog *Catalog) LatestBackupInfo() *BarmanBackup {
@@ -102,7 +102,7 @@ func (catalog *Catalog) FindBackupInfo(recoveryTarget *v1.RecoveryTarget) (*Barm
 	// Check that BackupID is not empty. In such case, always use the
 	// backup ID provided by the user.
 	if recoveryTarget.BackupID != "" {
-		return catalog.findBackupFromID(recoveryTarget.BackupID)
+		return catalog.FindBackupFromID(recoveryTarget.BackupID)
 	}
 
 	// The user has not specified any backup ID. As a result we need
@@ -191,21 +191,47 @@ func (catalog *Catalog) findlatestBackupFromTimeline(targetTLI string) *BarmanBa
 	return nil
 }
 
-func (catalog *Catalog) findBackupFromID(backupID string) (*BarmanBackup, error) {
+// FindBackupFromID finds the completed backup having the passed ID
+// or name, returning an error when it doesn't exist or it is not completed
+func (catalog *Catalog) FindBackupFromID(backupID string) (*BarmanBackup, error) {
 	if backupID == "" {
 		return nil, fmt.Errorf("no backupID provided")
 	}
+	found := false
 	for _, barmanBackup := range catalog.List {
-		if !barmanBackup.isBackupDone() {
+		if barmanBackup.ID != backupID && barmanBackup.BackupName != backupID {
 			continue
 		}
-		if barmanBackup.ID == backupID {
+		if barmanBackup.isBackupDone() {
 			return &barmanBackup, nil
 		}
+		found = true
+	}
+	if found {
+		return nil, fmt.Errorf("backup %s is not completed", backupID)
 	}
 	return nil, fmt.Errorf("no backup found with ID %s", backupID)
 }
 
+// WithoutLatest gets a copy of the catalog without the passed number
+// of most recent completed backups
+func (catalog *Catalog) WithoutLatest(skip int) *Catalog {
+	// the code below assumes the catalog to be sorted, therefore we enforce it first
+	sort.Sort(catalog)
+
+	end := len(catalog.List)
+	for end > 0 && skip > 0 {
+		end--
+		if catalog.List[end].isBackupDone() {
+			skip--
+		}
+	}
+
+	result := &Catalog{List: make([]BarmanBackup, end)}
+	copy(result.List, catalog.List[:end])
+	return result
+}
+
 // BarmanBackup represent a backup as created
 // by Barman
 type BarmanBackup struct {
@@ -251,6 +277,9 @@ type BarmanBackup struct {
 
 	// The TimeLine
 	TimeLine int `json:"timeline"`
+
+	// The size of the backup in bytes, zero if not recorded
+	Size int64 `json:"size,omitempty"`
 }
 
 type barmanBackupShow struct {
@@ -17,6 +17,7 @@ limitations under the License.
 package catalog
 
 import (
+	"strings"
 	"time"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -98,6 +99,77 @@ var _ = Describe("Backup catalog", func() {
 	})
 })
 
+var _ = Describe("Backup selection", func() {
+	var catalog *Catalog
+
+	BeforeEach(func() {
+		catalog = NewCatalog([]BarmanBackup{
+			{
+				ID:         "202101011200",
+				BackupName: "first",
+				BeginTime:  time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+				EndTime:    time.Date(2021, 1, 1, 12, 30, 0, 0, time.UTC),
+				TimeLine:   1,
+			},
+			{
+				ID:        "202101021200",
+				BeginTime: time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2021, 1, 2, 12, 30, 0, 0, time.UTC),
+				TimeLine:  1,
+			},
+			{
+				ID:        "202101031200",
+				BeginTime: time.Date(2021, 1, 3, 12, 0, 0, 0, time.UTC),
+				TimeLine:  1,
+			},
+			{
+				ID:        "202101041200",
+				BeginTime: time.Date(2021, 1, 4, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2021, 1, 4, 12, 30, 0, 0, time.UTC),
+				TimeLine:  1,
+			},
+		})
+	})
+
+	It("finds the specified backup by ID", func() {
+		backup, err := catalog.FindBackupFromID("202101021200")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101021200"))
+	})
+
+	It("finds the specified backup by name", func() {
+		backup, err := catalog.FindBackupFromID("first")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101011200"))
+	})
+
+	It("rejects a nonexistent backup ID", func() {
+		_, err := catalog.FindBackupFromID("202001011200")
+		Expect(err).To(MatchError(ContainSubstring("no backup found with ID 202001011200")))
+	})
+
+	It("rejects a backup that is not completed", func() {
+		_, err := catalog.FindBackupFromID("202101031200")
+		Expect(err).To(MatchError(ContainSubstring("is not completed")))
+	})
+
+	It("skips the latest completed backups", func() {
+		Expect(catalog.WithoutLatest(0).LatestBackupInfo().ID).To(Equal("202101041200"))
+		Expect(catalog.WithoutLatest(1).LatestBackupInfo().ID).To(Equal("202101021200"))
+		Expect(catalog.WithoutLatest(2).LatestBackupInfo().ID).To(Equal("202101011200"))
+		Expect(catalog.WithoutLatest(3).LatestBackupInfo()).To(BeNil())
+		Expect(catalog.List).To(HaveLen(4))
+	})
+
+	It("skips the latest completed backups when looking for a recovery target", func() {
+		recoveryTarget := &v1.RecoveryTarget{TargetTime: time.Date(2021, 1, 5, 0, 0, 0,
+			0, time.UTC).Format("2006-01-02 15:04:04")}
+		backup, err := catalog.WithoutLatest(1).FindBackupInfo(recoveryTarget)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101021200"))
+	})
+})
+
 var _ = Describe("barman-cloud-backup-list parsing", func() {
 	const barmanCloudListOutput = `{
   "backups_list": [
@@ -245,5 +317,13 @@ var _ = Describe("barman-cloud-backup-show parsing", func() {
 		Expect(result.SystemID).To(Equal("6885668674852188181"))
 		Expect(result.BeginTimeString).To(Equal("Tue Jan 19 03:14:08 2038"))
 		Expect(result.EndTimeString).To(Equal("Tue Jan 19 04:14:08 2038"))
+		Expect(result.Size).To(BeZero())
+	})
+
+	It("must parse the size of the backup", func() {
+		result, err := NewBackupFromBarmanCloudBackupShow(
+			strings.Replace(barmanCloudShowOutput, `"size": null`, `"size": 35237376`, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Size).To(BeEquivalentTo(35237376))
 	})
 })
@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrAuthenticationFailed is returned when the external server refused
+// the configured credentials
+var ErrAuthenticationFailed = errors.New("authentication failed")
+
+// SystemIdentity is the identity of a server, as reported by the
+// IDENTIFY_SYSTEM replication command
+type SystemIdentity struct {
+	SystemID string
+	Timeline string
+	XLogPos  string
+}
+
+// Connector opens a replication connection to a server and identifies it
+type Connector interface {
+	IdentifySystem(ctx context.Context, connectionString string) (*SystemIdentity, error)
+}
+
+// NewConnector creates a Connector using the replication protocol of
+// PostgreSQL, the same used by pg_basebackup
+func NewConnector() Connector {
+	return replicationConnector{}
+}
+
+type replicationConnector struct{}
+
+// IdentifySystem implements the Connector interface
+func (replicationConnector) IdentifySystem(
+	ctx context.Context,
+	connectionString string,
+) (*SystemIdentity, error) {
+	config, err := pgconn.ParseConfig(connectionString)
+	if err != nil {
+		return nil, err
+	}
+	config.RuntimeParams["replication"] = "true"
+
+	conn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	results, err := conn.Exec(ctx, "IDENTIFY_SYSTEM").ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 || len(results[0].Rows) != 1 || len(results[0].Rows[0]) < 3 {
+		return nil, fmt.Errorf("unexpected result of IDENTIFY_SYSTEM")
+	}
+
+	row := results[0].Rows[0]
+	return &SystemIdentity{
+		SystemID: string(row[0]),
+		Timeline: string(row[1]),
+		XLogPos:  string(row[2]),
+	}, nil
+}
+
+// CheckServer opens a test connection to an external server, making sure
+// that it accepts the credentials and that it is a primary or a standby
+// able to stream its data. The connection respects the TLS settings and
+// the password file of the connection string
+func CheckServer(ctx context.Context, connector Connector, connectionString string) (*SystemIdentity, error) {
+	identity, err := connector.IdentifySystem(ctx, connectionString)
+	if err != nil {
+		// invalid_password and invalid_authorization_specification
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "28P01" || pgErr.Code == "28000") {
+			return nil, fmt.Errorf("%w: %s", ErrAuthenticationFailed, pgErr.Message)
+		}
+		return nil, fmt.Errorf("while connecting to the server: %w", err)
+	}
+
+	if identity == nil || identity.SystemID == "" || identity.Timeline == "" {
+		return nil, fmt.Errorf("the server didn't report its system identifier and timeline")
+	}
+
+	return identity, nil
+}
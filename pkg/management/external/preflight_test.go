@@ -0,0 +1,81 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeConnector is a Connector returning a fixed outcome
+type fakeConnector struct {
+	identity *SystemIdentity
+	err      error
+}
+
+func (f fakeConnector) IdentifySystem(_ context.Context, _ string) (*SystemIdentity, error) {
+	return f.identity, f.err
+}
+
+var _ = Describe("test connection to an external server", func() {
+	const connectionString = "host=cluster-origin-rw user=streaming_replica sslmode=verify-full"
+
+	It("returns the identity of a reachable server", func(ctx context.Context) {
+		connector := fakeConnector{identity: &SystemIdentity{
+			SystemID: "7300000000000000000",
+			Timeline: "3",
+			XLogPos:  "0/3000060",
+		}}
+		identity, err := CheckServer(ctx, connector, connectionString)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(identity.SystemID).To(Equal("7300000000000000000"))
+		Expect(identity.Timeline).To(Equal("3"))
+	})
+
+	It("detects the authentication failures", func(ctx context.Context) {
+		for _, code := range []string{"28P01", "28000"} {
+			// the errors raised while connecting wrap the one of the server
+			connector := fakeConnector{err: fmt.Errorf("failed to connect: %w", &pgconn.PgError{
+				Severity: "FATAL",
+				Code:     code,
+				Message:  `password authentication failed for user "streaming_replica"`,
+			})}
+			_, err := CheckServer(ctx, connector, connectionString)
+			Expect(err).To(MatchError(ErrAuthenticationFailed))
+			Expect(err.Error()).To(ContainSubstring("streaming_replica"))
+		}
+	})
+
+	It("reports the other connection failures", func(ctx context.Context) {
+		connector := fakeConnector{err: errors.New("dial tcp: lookup cluster-origin-rw: no such host")}
+		_, err := CheckServer(ctx, connector, connectionString)
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(ErrAuthenticationFailed))
+		Expect(err.Error()).To(ContainSubstring("no such host"))
+	})
+
+	It("rejects the servers not reporting their identity", func(ctx context.Context) {
+		_, err := CheckServer(ctx, fakeConnector{identity: &SystemIdentity{}}, connectionString)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -39,11 +41,11 @@ const (
 
 	// ClientTLSCertPath is the path where the client TLS certificate
 	// is stored
-	clientTLSCertPath = ConfigsDir + "/server-tls/tls.crt"
+	ClientTLSCertPath = ConfigsDir + "/server-tls/tls.crt"
 
 	// ClientTLSKeyPath is the path where the client TLS private key
 	// is stored
-	clientTLSKeyPath = ConfigsDir + "/server-tls/tls.key"
+	ClientTLSKeyPath = ConfigsDir + "/server-tls/tls.key"
 
 	// ClientTLSCAPath is the path where the public key of the CA
 	// used to authenticate clients is stored
@@ -80,7 +82,10 @@ auth_query = {{ .AuthQuery }}
 `
 	pgbouncerHBAFileTemplateString = `
 local pgbouncer pgbouncer peer
-
+{{ if .AdminLocalOnly }}
+host pgbouncer all 0.0.0.0/0 reject
+host pgbouncer all ::/0 reject
+{{ end }}
 {{ range $rule := .PgHba }}
 {{ $rule -}}
 {{ end }}
@@ -119,11 +124,9 @@ var (
 		"admin_users":          PgBouncerAdminUser,
 		"auth_type":            "hba",
 		"auth_hba_file":        ConfigsDir + "/pg_hba.conf",
-		"server_tls_sslmode":   "verify-ca",
 		"server_tls_ca_file":   serverTLSCAPath,
-		"client_tls_sslmode":   "prefer",
-		"client_tls_cert_file": clientTLSCertPath,
-		"client_tls_key_file":  clientTLSKeyPath,
+		"client_tls_cert_file": ClientTLSCertPath,
+		"client_tls_key_file":  ClientTLSKeyPath,
 		"client_tls_ca_file":   clientTLSCAPath,
 	}
 )
@@ -172,6 +175,31 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	}
 
 	parameters := buildPgBouncerParameters(pooler.Spec.PgBouncer.Parameters)
+	if size := pooler.Spec.PgBouncer.ReservePoolSize; size != nil {
+		parameters["reserve_pool_size"] = strconv.Itoa(int(*size))
+	}
+	if timeout := pooler.Spec.PgBouncer.ReservePoolTimeout; timeout != nil {
+		parameters["reserve_pool_timeout"] = strconv.Itoa(int(*timeout))
+	}
+	if maxPreparedStatements := pooler.Spec.PgBouncer.MaxPreparedStatements; maxPreparedStatements != nil {
+		parameters["max_prepared_statements"] = strconv.Itoa(int(*maxPreparedStatements))
+	}
+	if keepalives := pooler.Spec.PgBouncer.TCPKeepalives; keepalives != nil {
+		parameters["tcp_keepalive"] = "1"
+		if idle := keepalives.Idle; idle != nil {
+			parameters["tcp_keepidle"] = strconv.Itoa(int(*idle))
+		}
+		if interval := keepalives.Interval; interval != nil {
+			parameters["tcp_keepintvl"] = strconv.Itoa(int(*interval))
+		}
+		if count := keepalives.Count; count != nil {
+			parameters["tcp_keepcnt"] = strconv.Itoa(int(*count))
+		}
+	}
+
+	parameters["admin_users"] = buildAdminUsers(pooler.Spec.PgBouncer.Admin)
+	parameters["client_tls_sslmode"] = string(pooler.Spec.PgBouncer.GetClientSSLMode())
+	parameters["server_tls_sslmode"] = string(pooler.Spec.PgBouncer.GetServerSSLMode())
 
 	if isCertAuth {
 		parameters["server_tls_cert_file"] = authUserCrtPath
@@ -187,6 +215,7 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		AuthQueryPassword string
 		Parameters        string
 		PgHba             []string
+		AdminLocalOnly    bool
 	}{
 		Pooler:            pooler,
 		AuthQuery:         pooler.GetAuthQuery(),
@@ -199,8 +228,9 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		//
 		// Also, we want the list of parameters inside the PgBouncer configuration
 		// to be stable.
-		Parameters: stringifyPgBouncerParameters(parameters),
-		PgHba:      pooler.Spec.PgBouncer.PgHBA,
+		Parameters:     stringifyPgBouncerParameters(parameters),
+		PgHba:          pooler.Spec.PgBouncer.PgHBA,
+		AdminLocalOnly: pooler.Spec.PgBouncer.Admin != nil && pooler.Spec.PgBouncer.Admin.LocalOnly,
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
@@ -226,8 +256,23 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	// The required crypto-material
 	files[serverTLSCAPath] = secrets.ServerCA.Data[certs.CACertKey]
 	files[clientTLSCAPath] = secrets.ClientCA.Data[certs.CACertKey]
-	files[clientTLSCertPath] = secrets.Client.Data[certs.TLSCertKey]
-	files[clientTLSKeyPath] = secrets.Client.Data[certs.TLSPrivateKeyKey]
+	files[ClientTLSCertPath] = secrets.Client.Data[certs.TLSCertKey]
+	files[ClientTLSKeyPath] = secrets.Client.Data[certs.TLSPrivateKeyKey]
 
 	return files, nil
 }
+
+// buildAdminUsers gets the value of the admin_users parameter, which
+// always starts with the user of the operator
+func buildAdminUsers(admin *apiv1.PgBouncerAdminConfiguration) string {
+	users := []string{PgBouncerAdminUser}
+	if admin != nil {
+		for _, user := range admin.Users {
+			if !slices.Contains(users, user) {
+				users = append(users, user)
+			}
+		}
+	}
+
+	return strings.Join(users, ",")
+}
@@ -0,0 +1,215 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgBouncer configuration files", func() {
+	var (
+		pooler  *apiv1.Pooler
+		secrets *Secrets
+	)
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgBouncer: &apiv1.PgBouncerSpec{
+					PoolMode: apiv1.PgBouncerPoolModeSession,
+				},
+			},
+		}
+		secrets = &Secrets{
+			AuthQuery: &corev1.Secret{
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					corev1.BasicAuthUsernameKey: []byte("cnpg_pooler_pgbouncer"),
+					corev1.BasicAuthPasswordKey: []byte("secret"),
+				},
+			},
+			Client:   &corev1.Secret{Data: map[string][]byte{certs.TLSCertKey: nil, certs.TLSPrivateKeyKey: nil}},
+			ClientCA: &corev1.Secret{Data: map[string][]byte{certs.CACertKey: nil}},
+			ServerCA: &corev1.Secret{Data: map[string][]byte{certs.CACertKey: nil}},
+		}
+	})
+
+	getIni := func() string {
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+		return string(files[filepath.Join(ConfigsDir, PgBouncerIniFileName)])
+	}
+
+	It("doesn't configure the reserve pool by default", func() {
+		ini := getIni()
+		Expect(ini).To(ContainSubstring("pool_mode = session"))
+		Expect(ini).ToNot(ContainSubstring("reserve_pool_size"))
+		Expect(ini).ToNot(ContainSubstring("reserve_pool_timeout"))
+	})
+
+	It("renders the reserve pool options", func() {
+		pooler.Spec.PgBouncer.ReservePoolSize = ptr.To(int32(5))
+		pooler.Spec.PgBouncer.ReservePoolTimeout = ptr.To(int32(3))
+
+		ini := getIni()
+		Expect(ini).To(MatchRegexp(`(?m)^reserve_pool_size = 5$`))
+		Expect(ini).To(MatchRegexp(`(?m)^reserve_pool_timeout = 3$`))
+	})
+
+	It("renders the maximum number of prepared statements", func() {
+		Expect(getIni()).ToNot(ContainSubstring("max_prepared_statements"))
+
+		pooler.Spec.PgBouncer.MaxPreparedStatements = ptr.To(int32(100))
+		Expect(getIni()).To(MatchRegexp(`(?m)^max_prepared_statements = 100$`))
+	})
+
+	It("doesn't configure the TCP keepalives by default", func() {
+		ini := getIni()
+		Expect(ini).ToNot(ContainSubstring("tcp_keep"))
+	})
+
+	It("renders the TCP keepalive options", func() {
+		pooler.Spec.PgBouncer.TCPKeepalives = &apiv1.TCPKeepalivesConfiguration{
+			Idle:     ptr.To(int32(60)),
+			Interval: ptr.To(int32(10)),
+			Count:    ptr.To(int32(5)),
+		}
+
+		ini := getIni()
+		Expect(ini).To(MatchRegexp(`(?m)^tcp_keepalive = 1$`))
+		Expect(ini).To(MatchRegexp(`(?m)^tcp_keepidle = 60$`))
+		Expect(ini).To(MatchRegexp(`(?m)^tcp_keepintvl = 10$`))
+		Expect(ini).To(MatchRegexp(`(?m)^tcp_keepcnt = 5$`))
+	})
+
+	It("renders only the TCP keepalive options which are set", func() {
+		pooler.Spec.PgBouncer.TCPKeepalives = &apiv1.TCPKeepalivesConfiguration{
+			Idle: ptr.To(int32(30)),
+		}
+
+		ini := getIni()
+		Expect(ini).To(MatchRegexp(`(?m)^tcp_keepalive = 1$`))
+		Expect(ini).To(MatchRegexp(`(?m)^tcp_keepidle = 30$`))
+		Expect(ini).ToNot(ContainSubstring("tcp_keepintvl"))
+		Expect(ini).ToNot(ContainSubstring("tcp_keepcnt"))
+	})
+
+	It("keeps the reserve pool parameters when the options are not set", func() {
+		pooler.Spec.PgBouncer.Parameters = map[string]string{
+			"reserve_pool_size":    "2",
+			"reserve_pool_timeout": "1.5",
+		}
+
+		ini := getIni()
+		Expect(ini).To(MatchRegexp(`(?m)^reserve_pool_size = 2$`))
+		Expect(ini).To(MatchRegexp(`(?m)^reserve_pool_timeout = 1.5$`))
+	})
+
+	getHBA := func() string {
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+		return string(files[filepath.Join(ConfigsDir, PgBouncerHBAConfFileName)])
+	}
+
+	It("allows only the operator to use the admin console by default", func() {
+		Expect(getIni()).To(MatchRegexp(`(?m)^admin_users = pgbouncer$`))
+		Expect(getHBA()).ToNot(ContainSubstring("reject"))
+	})
+
+	It("renders the additional admin users", func() {
+		pooler.Spec.PgBouncer.Admin = &apiv1.PgBouncerAdminConfiguration{
+			Users: []string{"dba", "pgbouncer", "monitor"},
+		}
+
+		Expect(getIni()).To(MatchRegexp(`(?m)^admin_users = pgbouncer,dba,monitor$`))
+	})
+
+	It("rejects the network connections to the admin console when requested", func() {
+		pooler.Spec.PgBouncer.Admin = &apiv1.PgBouncerAdminConfiguration{LocalOnly: true}
+		pooler.Spec.PgBouncer.PgHBA = []string{"host pgbouncer dba 10.0.0.0/8 md5"}
+
+		hba := getHBA()
+		Expect(hba).To(MatchRegexp(`(?m)^host pgbouncer all 0.0.0.0/0 reject$`))
+		Expect(hba).To(MatchRegexp(`(?m)^host pgbouncer all ::/0 reject$`))
+		Expect(strings.Index(hba, "local pgbouncer pgbouncer peer")).
+			To(BeNumerically("<", strings.Index(hba, "host pgbouncer all 0.0.0.0/0 reject")))
+		Expect(strings.Index(hba, "reject")).
+			To(BeNumerically("<", strings.Index(hba, "host pgbouncer dba 10.0.0.0/8 md5")))
+	})
+
+	It("keeps the operator access to the admin console whatever the user rules", func() {
+		pooler.Spec.PgBouncer.Admin = &apiv1.PgBouncerAdminConfiguration{LocalOnly: true}
+		pooler.Spec.PgBouncer.PgHBA = []string{
+			"local all all reject",
+			"local pgbouncer pgbouncer md5",
+		}
+
+		// the operator relies on the admin console to pause and resume the
+		// pooler: its rule comes before the user ones, and is always matched
+		var rules []string
+		for _, line := range strings.Split(getHBA(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				rules = append(rules, line)
+			}
+		}
+		Expect(rules[0]).To(Equal("local pgbouncer pgbouncer peer"))
+		Expect(getIni()).To(MatchRegexp(`(?m)^admin_users = pgbouncer(,|$)`))
+	})
+
+	It("uses the default TLS modes", func() {
+		ini := getIni()
+		Expect(ini).To(MatchRegexp(`(?m)^client_tls_sslmode = prefer$`))
+		Expect(ini).To(MatchRegexp(`(?m)^server_tls_sslmode = verify-ca$`))
+	})
+
+	It("renders the TLS modes", func() {
+		pooler.Spec.PgBouncer.TLS = &apiv1.PgBouncerTLSConfiguration{
+			ClientSSLMode: apiv1.PgBouncerTLSModeVerifyFull,
+			ServerSSLMode: apiv1.PgBouncerTLSModeVerifyFull,
+		}
+
+		ini := getIni()
+		Expect(ini).To(MatchRegexp(`(?m)^client_tls_sslmode = verify-full$`))
+		Expect(ini).To(MatchRegexp(`(?m)^server_tls_sslmode = verify-full$`))
+	})
+
+	It("writes the certificates of the passed secrets", func() {
+		secrets.Client.Data = map[string][]byte{
+			certs.TLSCertKey:       []byte("certificate"),
+			certs.TLSPrivateKeyKey: []byte("key"),
+		}
+		secrets.ClientCA.Data = map[string][]byte{certs.CACertKey: []byte("ca")}
+
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files[ClientTLSCertPath]).To(Equal([]byte("certificate")))
+		Expect(files[ClientTLSKeyPath]).To(Equal([]byte("key")))
+		Expect(files[clientTLSCAPath]).To(Equal([]byte("ca")))
+	})
+})
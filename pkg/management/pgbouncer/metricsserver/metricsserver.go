@@ -19,6 +19,7 @@ package metricsserver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 )
@@ -57,8 +59,10 @@ func Setup() error {
 	return nil
 }
 
-// ListenAndServe starts the web server handling metrics
-func ListenAndServe() error {
+// ListenAndServe starts the web server handling metrics. When enableTLS is
+// true, the metrics are served over HTTPS using the server certificate
+// of the cluster
+func ListenAndServe(enableTLS bool) error {
 	serveMux := http.NewServeMux()
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
@@ -68,7 +72,14 @@ func ListenAndServe() error {
 		ReadTimeout:       webserver.DefaultReadTimeout,
 		ReadHeaderTimeout: webserver.DefaultReadHeaderTimeout,
 	}
-	err := server.ListenAndServe()
+
+	var err error
+	if enableTLS {
+		server.TLSConfig = newTLSConfig()
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
 
 	// The metricsServer has been shut down
 	if err == http.ErrServerClosed {
@@ -78,6 +89,23 @@ func ListenAndServe() error {
 	return err
 }
 
+// newTLSConfig creates the TLS configuration of the metrics server.
+// The certificate is loaded at every handshake, given it is written
+// by the reconciler after the server is started and is refreshed
+// whenever the corresponding secret changes
+func newTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(config.ClientTLSCertPath, config.ClientTLSKeyPath)
+			if err != nil {
+				return nil, fmt.Errorf("while loading the server certificate: %w", err)
+			}
+			return &cert, nil
+		},
+	}
+}
+
 // Shutdown stops the web metrics server
 func Shutdown() error {
 	return server.Shutdown(context.Background())
@@ -18,6 +18,7 @@ limitations under the License.
 package metricsserver
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -141,7 +142,7 @@ func (e *Exporter) collectPgBouncerMetrics(ch chan<- prometheus.Metric) {
 
 // GetPgBouncerDB gets a connection to the admin user db "pgbouncer" on this instance
 func (e *Exporter) GetPgBouncerDB() (*sql.DB, error) {
-	return e.ConnectionPool().Connection("pgbouncer")
+	return e.ConnectionPool().Connection(context.Background(), "pgbouncer")
 }
 
 // ConnectionPool gets or initializes the connection pool for this instance
@@ -154,7 +155,13 @@ func (e *Exporter) ConnectionPool() pool.Pooler {
 			config.PgBouncerAdminUser,
 		)
 
-		e.pool = pool.NewPgbouncerConnectionPool(dsn)
+		// Every scrape runs several SHOW commands: keeping one idle
+		// connection avoids reconnecting for each of them
+		e.pool = pool.NewPgbouncerConnectionPool(
+			dsn,
+			pool.WithMaxIdleConns(1),
+			pool.WithConnMaxIdleTime(time.Minute),
+		)
 	}
 
 	return e.pool
@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// activityQuery gets the client backends that are not idle, starting from
+// the longest running query. We get one more backend than the maximum
+// allowed to detect if the list has been truncated
+var activityQuery = fmt.Sprintf(`SELECT
+  pid,
+  COALESCE(usename, ''),
+  COALESCE(datname, ''),
+  COALESCE(application_name, ''),
+  COALESCE(host(client_addr), ''),
+  COALESCE(state, ''),
+  COALESCE(wait_event_type, ''),
+  COALESCE(wait_event, ''),
+  COALESCE(left(query, %d), ''),
+  COALESCE(EXTRACT(EPOCH FROM (pg_catalog.now() - query_start))::float8, 0)
+FROM pg_catalog.pg_stat_activity
+WHERE backend_type = 'client backend'
+  AND state IS DISTINCT FROM 'idle'
+  AND pid <> pg_catalog.pg_backend_pid()
+ORDER BY query_start NULLS LAST
+LIMIT %d`, postgres.MaxActivityQueryLength, postgres.MaxActivityBackends+1)
+
+// GetActivity gets the active backends of the instance. The text of the
+// queries, which may contain sensitive data, is redacted unless requested
+// and exposed by the cluster configuration
+func (instance *Instance) GetActivity(includeQueries bool) (*postgres.ActivityReport, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return getActivity(superUserDB, instance.canReportQueries(includeQueries))
+}
+
+// canReportQueries checks whether the text of the queries can be reported.
+// The request of the client can only restrict what the cluster exposes
+func (instance *Instance) canReportQueries(requested bool) bool {
+	return requested && instance.activityQueriesExposed.Load()
+}
+
+func getActivity(db *sql.DB, includeQueries bool) (*postgres.ActivityReport, error) {
+	rows, err := db.Query(activityQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := postgres.ActivityReport{
+		Backends: make([]postgres.BackendActivity, 0),
+	}
+	for rows.Next() {
+		var backend postgres.BackendActivity
+		if err := rows.Scan(
+			&backend.PID,
+			&backend.User,
+			&backend.Database,
+			&backend.ApplicationName,
+			&backend.ClientAddress,
+			&backend.State,
+			&backend.WaitEventType,
+			&backend.WaitEvent,
+			&backend.Query,
+			&backend.DurationSeconds,
+		); err != nil {
+			return nil, err
+		}
+
+		if !includeQueries && backend.Query != "" {
+			backend.Query = ""
+			backend.QueryRedacted = true
+		}
+
+		if len(result.Backends) == postgres.MaxActivityBackends {
+			result.Truncated = true
+			break
+		}
+		result.Backends = append(result.Backends, backend)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
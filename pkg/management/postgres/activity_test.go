@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance activity", func() {
+	activityColumns := []string{
+		"pid", "usename", "datname", "application_name", "client_addr",
+		"state", "wait_event_type", "wait_event", "query", "duration",
+	}
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+	})
+
+	It("reports the active backends as JSON", func() {
+		mock.ExpectQuery(activityQuery).WillReturnRows(sqlmock.NewRows(activityColumns).
+			AddRow(42, "app", "app", "psql", "10.244.0.21", "active", "Lock", "relation",
+				"UPDATE accounts SET balance = 0", 12.5))
+
+		report, err := getActivity(db, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		data, err := json.Marshal(report)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{
+			"backends": [{
+				"pid": 42,
+				"user": "app",
+				"database": "app",
+				"applicationName": "psql",
+				"clientAddress": "10.244.0.21",
+				"state": "active",
+				"waitEventType": "Lock",
+				"waitEvent": "relation",
+				"query": "UPDATE accounts SET balance = 0",
+				"durationSeconds": 12.5
+			}]
+		}`))
+	})
+
+	It("redacts the text of the queries unless they are requested", func() {
+		mock.ExpectQuery(activityQuery).WillReturnRows(sqlmock.NewRows(activityColumns).
+			AddRow(42, "app", "app", "", "", "active", "", "",
+				"SELECT * FROM customers WHERE email = 'someone@example.com'", 1.0))
+
+		report, err := getActivity(db, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Backends).To(HaveLen(1))
+		Expect(report.Backends[0].Query).To(BeEmpty())
+		Expect(report.Backends[0].QueryRedacted).To(BeTrue())
+
+		data, err := json.Marshal(report)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).ToNot(ContainSubstring("someone@example.com"))
+	})
+
+	It("reports the text of the queries only when the cluster exposes it", func() {
+		instance := &Instance{}
+		Expect(instance.canReportQueries(true)).To(BeFalse())
+		Expect(instance.canReportQueries(false)).To(BeFalse())
+
+		instance.SetActivityQueriesExposed(true)
+		Expect(instance.canReportQueries(true)).To(BeTrue())
+		Expect(instance.canReportQueries(false)).To(BeFalse())
+	})
+
+	It("reports an empty list when no backend is active", func() {
+		mock.ExpectQuery(activityQuery).WillReturnRows(sqlmock.NewRows(activityColumns))
+
+		report, err := getActivity(db, false)
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := json.Marshal(report)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"backends": []}`))
+	})
+
+	It("bounds the number of backends reported", func() {
+		rows := sqlmock.NewRows(activityColumns)
+		for i := 0; i <= postgres.MaxActivityBackends; i++ {
+			rows.AddRow(i, "app", "app", "", "", "active", "", "", "SELECT 1", 1.0)
+		}
+		mock.ExpectQuery(activityQuery).WillReturnRows(rows)
+
+		report, err := getActivity(db, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Backends).To(HaveLen(postgres.MaxActivityBackends))
+		Expect(report.Truncated).To(BeTrue())
+	})
+})
@@ -38,6 +38,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/progress"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -95,10 +96,20 @@ func NewBackupCommand(
 
 // getDataConfiguration gets the configuration in the `Data` object of the Barman configuration
 func getDataConfiguration(
+	ctx context.Context,
 	options []string,
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	capabilities *barmanCapabilities.Capabilities,
 ) ([]string, error) {
+	var encryption apiv1.EncryptionType
+	if configuration.Data != nil {
+		encryption = configuration.Data.Encryption
+	}
+	options, err := barman.AppendEncryptionOptions(ctx, options, configuration, encryption)
+	if err != nil {
+		return nil, err
+	}
+
 	if configuration.Data == nil {
 		return options, nil
 	}
@@ -113,13 +124,6 @@ func getDataConfiguration(
 			fmt.Sprintf("--%v", configuration.Data.Compression))
 	}
 
-	if len(configuration.Data.Encryption) != 0 {
-		options = append(
-			options,
-			"--encryption",
-			string(configuration.Data.Encryption))
-	}
-
 	if configuration.Data.ImmediateCheckpoint {
 		options = append(
 			options,
@@ -139,6 +143,7 @@ func getDataConfiguration(
 // getBarmanCloudBackupOptions extract the list of command line options to be used with
 // barman-cloud-backup
 func (b *BackupCommand) getBarmanCloudBackupOptions(
+	ctx context.Context,
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	serverName string,
 ) ([]string, error) {
@@ -150,7 +155,7 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 		options = append(options, "--name", b.Backup.Status.BackupName)
 	}
 
-	options, err := getDataConfiguration(options, configuration, b.Capabilities)
+	options, err := getDataConfiguration(ctx, options, configuration, b.Capabilities)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +175,11 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 			configuration.EndpointURL)
 	}
 
+	options, err = barman.AppendMaxBandwidthOptions(options, configuration)
+	if err != nil {
+		return nil, err
+	}
+
 	options, err = barman.AppendCloudProviderOptionsFromConfiguration(options, configuration)
 	if err != nil {
 		return nil, err
@@ -247,12 +257,23 @@ func (b *BackupCommand) ensureBarmanCompatibility() error {
 func (b *BackupCommand) retryWithRefreshedCluster(
 	ctx context.Context,
 	cb func() error,
+) error {
+	return retryWithRefreshedCluster(ctx, b.Client, b.Cluster, cb)
+}
+
+// retryWithRefreshedCluster executes the passed callback after having
+// refreshed the cluster, retrying it when it fails
+func retryWithRefreshedCluster(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	cb func() error,
 ) error {
 	return retry.OnError(retry.DefaultBackoff, resources.RetryAlways, func() error {
-		if err := b.Client.Get(ctx, types.NamespacedName{
-			Namespace: b.Cluster.Namespace,
-			Name:      b.Cluster.Name,
-		}, b.Cluster); err != nil {
+		if err := cli.Get(ctx, types.NamespacedName{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+		}, cluster); err != nil {
 			return err
 		}
 
@@ -301,7 +322,7 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
 	backupStatus := b.Backup.GetStatus()
 
-	options, backupErr := b.getBarmanCloudBackupOptions(barmanConfiguration, backupStatus.ServerName)
+	options, backupErr := b.getBarmanCloudBackupOptions(ctx, barmanConfiguration, backupStatus.ServerName)
 	if backupErr != nil {
 		b.Log.Error(backupErr, "while getting barman-cloud-backup options")
 		return backupErr
@@ -329,7 +350,7 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
 	cmd.Env = b.Env
 	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
-	if err := execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup); err != nil {
+	if err := b.runBarmanCloudBackup(cmd); err != nil {
 		return err
 	}
 
@@ -361,6 +382,16 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	return nil
 }
 
+// runBarmanCloudBackup executes barman-cloud-backup, tracking its progress
+// when the progress metrics are enabled
+func (b *BackupCommand) runBarmanCloudBackup(cmd *exec.Cmd) error {
+	if !b.Cluster.Spec.Monitoring.IsBackupProgressMetricsEnabled() {
+		return execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup)
+	}
+
+	return progress.Backup.Run(cmd, barmanCapabilities.BarmanCloudBackup)
+}
+
 func (b *BackupCommand) getExecutedBackupInfo(
 	ctx context.Context,
 ) (*catalog.BarmanBackup, error) {
@@ -466,15 +497,16 @@ func (b *BackupCommand) setupBackupStatus() {
 	backupStatus.EndpointCA = barmanConfiguration.EndpointCA
 	backupStatus.EndpointURL = barmanConfiguration.EndpointURL
 	backupStatus.DestinationPath = barmanConfiguration.DestinationPath
-	if barmanConfiguration.Data != nil {
+	backupStatus.MaxBandwidth = barmanConfiguration.MaxBandwidth
+	switch {
+	case barmanConfiguration.Encryption != nil:
+		backupStatus.Encryption = string(apiv1.EncryptionTypeNoneAWSKMS)
+	case barmanConfiguration.Data != nil:
 		backupStatus.Encryption = string(barmanConfiguration.Data.Encryption)
 	}
 	// Set the barman server name as specified by the user.
 	// If not explicitly configured use the cluster name
-	backupStatus.ServerName = barmanConfiguration.ServerName
-	if backupStatus.ServerName == "" {
-		backupStatus.ServerName = b.Cluster.Name
-	}
+	backupStatus.ServerName = barmanConfiguration.GetServerName(b.Cluster.Name)
 	backupStatus.Phase = apiv1.BackupPhaseRunning
 }
 
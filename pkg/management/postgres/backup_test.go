@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -142,4 +143,27 @@ var _ = Describe("testing backup command", func() {
 
 		Expect(backup.Status.Error).To(Equal(clusterCond.Message))
 	})
+
+	It("should record the bandwidth limit in the backup status", func() {
+		backupCommand.setupBackupStatus()
+		Expect(backup.Status.MaxBandwidth).To(BeNil())
+
+		cluster.Spec.Backup.BarmanObjectStore.MaxBandwidth = ptr.To(int64(10485760))
+		backupCommand.setupBackupStatus()
+		Expect(backup.Status.MaxBandwidth).To(Equal(ptr.To(int64(10485760))))
+	})
+
+	It("should take the backup in the folder of the configured server name", func(ctx context.Context) {
+		backupCommand.setupBackupStatus()
+		Expect(backup.Status.ServerName).To(Equal("test-cluster"))
+
+		cluster.Spec.Backup.BarmanObjectStore.ServerName = "test-cluster-v2"
+		backupCommand.setupBackupStatus()
+		Expect(backup.Status.ServerName).To(Equal("test-cluster-v2"))
+
+		options, err := backupCommand.getBarmanCloudBackupOptions(
+			ctx, cluster.Spec.Backup.BarmanObjectStore, backup.Status.ServerName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options[len(options)-1]).To(Equal("test-cluster-v2"))
+	})
 })
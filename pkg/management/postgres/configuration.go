@@ -25,11 +25,14 @@ import (
 	"sort"
 	"strings"
 
+	"k8s.io/utils/ptr"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
@@ -60,7 +63,15 @@ func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	cluster *apiv1.Cluster,
 	preserveUserSettings bool,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, preserveUserSettings)
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return false, err
+	}
+
+	instance.replicaConnection.Store(cluster.Spec.PostgresConfiguration.ReplicaConnection.DeepCopy())
+
+	postgresConfiguration, sha256, err := createPostgresqlConfiguration(
+		cluster, instance.PodName, isPrimary, preserveUserSettings)
 	if err != nil {
 		return false, err
 	}
@@ -104,7 +115,8 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 	return postgres.CreateHBARules(
 		cluster.Spec.PostgresConfiguration.PgHBA,
 		defaultAuthenticationMethod,
-		buildLDAPConfigString(cluster, ldapBindPassword))
+		buildLDAPConfigString(cluster, ldapBindPassword),
+		!cluster.GetEnableSuperuserAccess())
 }
 
 // RefreshPGHBA generates and writes down the pg_hba.conf file
@@ -370,8 +382,13 @@ func migratePostgresAutoConfFile(ctx context.Context, instance *Instance, forceM
 }
 
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
-// used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings bool) (string, string, error) {
+// used for the passed instance of this cluster and return it and its sha256 checksum
+func createPostgresqlConfiguration(
+	cluster *apiv1.Cluster,
+	instanceName string,
+	isPrimary bool,
+	preserveUserSettings bool,
+) (string, string, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
@@ -381,10 +398,20 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	info := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     fromVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     cluster.GetExtensionsParameters(),
+		InstanceSettings:                 cluster.GetInstanceParameters(instanceName),
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
+		RelaxedDurability:                cluster.IsDurabilityRelaxed(),
+		MaxSlotWALKeepSize:               cluster.Spec.ReplicationSlots.GetMaxSlotWALKeepSize(),
+		DefaultTransactionIsolation:      string(cluster.Spec.PostgresConfiguration.DefaultTransactionIsolation),
+		DefaultTransactionReadOnly:       cluster.Spec.PostgresConfiguration.ReadOnlyReplicas && !isPrimary,
+		EffectiveCacheSize:               cluster.GetAutoEffectiveCacheSize(),
+		Timezone:                         cluster.Spec.PostgresConfiguration.Timezone,
+		LogTimezone:                      cluster.Spec.PostgresConfiguration.LogTimezone,
+		DateStyle:                        cluster.Spec.PostgresConfiguration.DateStyle,
+		AutovacuumSettings:               cluster.Spec.PostgresConfiguration.Autovacuum.GetParameters(),
 	}
 
 	if preserveUserSettings {
@@ -396,7 +423,13 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	// Compute the actual number of sync replicas
 	syncReplicas, electable := cluster.GetSyncReplicasData()
 	info.SyncReplicas = syncReplicas
-	info.SyncReplicasElectable = electable
+
+	// The standbys are referred to with the application name they
+	// register with on the primary
+	info.SyncReplicasElectable = make([]string, len(electable))
+	for idx, instanceName := range electable {
+		info.SyncReplicasElectable[idx] = cluster.GetReplicaApplicationName(instanceName)
+	}
 
 	// Ensure a consistent ordering to avoid spurious configuration changes
 	sort.Strings(info.SyncReplicasElectable)
@@ -404,6 +437,31 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	// Set cluster name
 	info.ClusterName = cluster.Name
 
+	if cluster.ShouldCreateStatsTempVolume() {
+		info.StatsTempDirectory = postgres.StatsTempDirectory
+	}
+
+	// The log directory follows the volumes mounted in this Pod, as an instance
+	// not yet restarted with the log volume keeps using the default one
+	if logDirectory := logpipe.GetLogDirectory(); logDirectory != postgres.LogPath {
+		info.LogDirectory = logDirectory
+	}
+
+	if keepalives := cluster.Spec.PostgresConfiguration.TCPKeepalives; keepalives != nil {
+		info.TCPKeepalivesIdle = int(ptr.Deref(keepalives.Idle, 0))
+		info.TCPKeepalivesInterval = int(ptr.Deref(keepalives.Interval, 0))
+		info.TCPKeepalivesCount = int(ptr.Deref(keepalives.Count, 0))
+	}
+
+	if replicaConnection := cluster.Spec.PostgresConfiguration.ReplicaConnection; replicaConnection != nil {
+		info.WalRetrieveRetryInterval = int(ptr.Deref(replicaConnection.RetryInterval, 0))
+	}
+
+	if timeouts := cluster.Spec.PostgresConfiguration.Timeouts; timeouts != nil {
+		info.StatementTimeout = timeouts.StatementTimeout
+		info.LockTimeout = timeouts.LockTimeout
+	}
+
 	conf, sha256 := postgres.CreatePostgresqlConfFile(postgres.CreatePostgresqlConfiguration(info))
 	return conf, sha256, nil
 }
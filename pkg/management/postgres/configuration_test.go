@@ -18,12 +18,18 @@ package postgres
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -113,3 +119,173 @@ var _ = Describe("testing the building of the ldap config string", func() {
 			ldapSearchFilter, ldapSearchAttribute)))
 	})
 })
+
+var _ = Describe("read-only replicas", func() {
+	var instance *Instance
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: versions.DefaultImageName,
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				ReadOnlyReplicas: true,
+			},
+		},
+	}
+
+	readCustomConf := func() string {
+		content, err := os.ReadFile(filepath.Join(instance.PgData, constants.PostgresqlCustomConfigurationFile))
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		instance = NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		instance.PodName = "cluster-example-1"
+	})
+
+	It("sets default_transaction_read_only on a replica and clears it on promotion", func() {
+		signalFile := filepath.Join(instance.PgData, "standby.signal")
+		Expect(os.WriteFile(signalFile, nil, 0o600)).To(Succeed())
+
+		changed, err := instance.RefreshConfigurationFilesFromCluster(cluster, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readCustomConf()).To(ContainSubstring("default_transaction_read_only = 'on'\n"))
+
+		// Promoting the instance removes the signal file
+		Expect(os.Remove(signalFile)).To(Succeed())
+
+		changed, err = instance.RefreshConfigurationFilesFromCluster(cluster, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readCustomConf()).ToNot(ContainSubstring("default_transaction_read_only"))
+	})
+
+	It("doesn't set default_transaction_read_only on a replica when not requested", func() {
+		Expect(os.WriteFile(filepath.Join(instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+
+		plainCluster := cluster.DeepCopy()
+		plainCluster.Spec.PostgresConfiguration.ReadOnlyReplicas = false
+		_, err := instance.RefreshConfigurationFilesFromCluster(plainCluster, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readCustomConf()).ToNot(ContainSubstring("default_transaction_read_only"))
+	})
+})
+
+var _ = Describe("automatic effective_cache_size", func() {
+	var instance *Instance
+
+	newCluster := func(memory string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					AutoEffectiveCacheSize: true,
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			},
+		}
+	}
+
+	readCustomConf := func() string {
+		content, err := os.ReadFile(filepath.Join(instance.PgData, constants.PostgresqlCustomConfigurationFile))
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		instance = NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		instance.PodName = "cluster-example-1"
+	})
+
+	It("recomputes effective_cache_size when the pod memory changes", func() {
+		changed, err := instance.RefreshConfigurationFilesFromCluster(newCluster("4Gi"), false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readCustomConf()).To(ContainSubstring("effective_cache_size = '3145728kB'\n"))
+
+		// The configuration is unchanged as long as the memory is the same
+		changed, err = instance.RefreshConfigurationFilesFromCluster(newCluster("4Gi"), false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		// A changed configuration is applied reloading PostgreSQL, and
+		// effective_cache_size doesn't require a restart
+		changed, err = instance.RefreshConfigurationFilesFromCluster(newCluster("8Gi"), false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readCustomConf()).To(ContainSubstring("effective_cache_size = '6291456kB'\n"))
+	})
+
+	It("keeps the effective_cache_size set by the user", func() {
+		cluster := newCluster("4Gi")
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"effective_cache_size": "1GB",
+		}
+		_, err := instance.RefreshConfigurationFilesFromCluster(cluster, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readCustomConf()).To(ContainSubstring("effective_cache_size = '1GB'\n"))
+		Expect(readCustomConf()).ToNot(ContainSubstring("3145728kB"))
+	})
+})
+
+var _ = Describe("synchronous standby names", func() {
+	newCluster := func(replicaConnection *apiv1.ReplicaConnectionConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName:       versions.DefaultImageName,
+				Instances:       3,
+				MinSyncReplicas: 1,
+				MaxSyncReplicas: 2,
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					ReplicaConnection: replicaConnection,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				InstancesStatus: map[utils.PodStatus][]string{
+					utils.PodHealthy: {"cluster-example-1", "cluster-example-3", "cluster-example-2"},
+				},
+			},
+		}
+	}
+
+	It("refers to the replicas with the name of their pods by default", func() {
+		conf, _, err := createPostgresqlConfiguration(newCluster(nil), "cluster-example-1", true, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf).To(ContainSubstring(
+			`synchronous_standby_names = 'ANY 2 ("cluster-example-2","cluster-example-3")'`))
+	})
+
+	It("refers to the replicas with their application name", func() {
+		cluster := newCluster(&apiv1.ReplicaConnectionConfiguration{ApplicationName: "dc1-{serial}"})
+		conf, _, err := createPostgresqlConfiguration(cluster, "cluster-example-1", true, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf).To(ContainSubstring(`synchronous_standby_names = 'ANY 2 ("dc1-2","dc1-3")'`))
+
+		// the names are the ones the replicas register with
+		for _, instanceName := range []string{"cluster-example-2", "cluster-example-3"} {
+			connInfo := buildReplicaConnInfo("cluster-example-rw", instanceName, cluster)
+			Expect(strings.Fields(connInfo)).To(ContainElement(
+				"application_name=" + cluster.GetReplicaApplicationName(instanceName)))
+		}
+	})
+})
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"sync"
+	"time"
+)
+
+// replicationConflictsWindow is the period of time over which the queries
+// canceled because of conflicts with recovery are counted
+const replicationConflictsWindow = 5 * time.Minute
+
+// replicationConflictsQuery counts the queries canceled because of
+// conflicts with recovery since the statistics were last reset
+const replicationConflictsQuery = `SELECT coalesce(sum(
+  confl_tablespace + confl_lock + confl_snapshot + confl_bufferpin + confl_deadlock), 0)
+FROM pg_catalog.pg_stat_database_conflicts`
+
+// conflictsSample is the total number of conflicts observed at a certain time
+type conflictsSample struct {
+	time  time.Time
+	total int64
+}
+
+// conflictsTracker keeps the recent samples of the total number of
+// conflicts with recovery, allowing to detect a spike
+type conflictsTracker struct {
+	mu      sync.Mutex
+	samples []conflictsSample
+}
+
+// observe records the total number of conflicts at the passed time, returning
+// the number of conflicts which happened in the last window
+func (tracker *conflictsTracker) observe(now time.Time, total int64) int64 {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	// the statistics have been reset, or PostgreSQL has been restarted
+	if len(tracker.samples) > 0 && total < tracker.samples[len(tracker.samples)-1].total {
+		tracker.samples = nil
+	}
+
+	tracker.samples = append(tracker.samples, conflictsSample{time: now, total: total})
+
+	// we keep the newest sample older than the window as the baseline
+	windowStart := now.Add(-replicationConflictsWindow)
+	for len(tracker.samples) > 1 && !tracker.samples[1].time.After(windowStart) {
+		tracker.samples = tracker.samples[1:]
+	}
+
+	return total - tracker.samples[0].total
+}
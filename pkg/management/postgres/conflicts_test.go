@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("conflicts with recovery tracking", func() {
+	var (
+		tracker *conflictsTracker
+		start   time.Time
+	)
+
+	BeforeEach(func() {
+		tracker = &conflictsTracker{}
+		start = time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	It("doesn't report conflicts on the first observation", func() {
+		Expect(tracker.observe(start, 42)).To(BeZero())
+	})
+
+	It("reports the conflicts which happened in the window", func() {
+		Expect(tracker.observe(start, 10)).To(BeZero())
+		Expect(tracker.observe(start.Add(time.Minute), 15)).To(BeEquivalentTo(5))
+		Expect(tracker.observe(start.Add(2*time.Minute), 30)).To(BeEquivalentTo(20))
+	})
+
+	It("forgets the conflicts older than the window", func() {
+		Expect(tracker.observe(start, 10)).To(BeZero())
+		Expect(tracker.observe(start.Add(time.Minute), 30)).To(BeEquivalentTo(20))
+		Expect(tracker.observe(start.Add(replicationConflictsWindow+time.Minute), 31)).
+			To(BeEquivalentTo(1))
+		Expect(tracker.observe(start.Add(3*replicationConflictsWindow), 31)).To(BeZero())
+	})
+
+	It("restarts counting when the statistics are reset", func() {
+		Expect(tracker.observe(start, 100)).To(BeZero())
+		Expect(tracker.observe(start.Add(time.Minute), 3)).To(BeZero())
+		Expect(tracker.observe(start.Add(2*time.Minute), 5)).To(BeEquivalentTo(2))
+	})
+})
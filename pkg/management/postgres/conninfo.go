@@ -38,3 +38,38 @@ func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
 		"sslmode=verify-ca"
 	return primaryConnInfo
 }
+
+// buildReplicaConnInfo builds the connection string used by the given
+// instance to stream from primaryHostname, registering with the application
+// name and the connection options requested in the cluster
+func buildReplicaConnInfo(primaryHostname, instanceName string, cluster *apiv1.Cluster) string {
+	return withReplicaConnectionOptions(
+		buildPrimaryConnInfo(primaryHostname, cluster.GetReplicaApplicationName(instanceName)),
+		cluster)
+}
+
+// withSSLNegotiation adds the SSL negotiation mode of the cluster to a
+// connection string to the primary, when it's not the default one.
+// Only libpq supports this option, so this must be used only for the
+// connection strings used by PostgreSQL and its client applications,
+// not by the instance manager
+func withSSLNegotiation(connInfo string, cluster *apiv1.Cluster) string {
+	if cluster.GetSSLNegotiation() != apiv1.SSLNegotiationModeDirect {
+		return connInfo
+	}
+
+	return connInfo + fmt.Sprintf(" sslnegotiation=%v", apiv1.SSLNegotiationModeDirect)
+}
+
+// withReplicaConnectionOptions adds the options requested in the cluster
+// to the connection string used by the replicas to stream from the primary
+func withReplicaConnectionOptions(connInfo string, cluster *apiv1.Cluster) string {
+	connInfo = withSSLNegotiation(connInfo, cluster)
+
+	replicaConnection := cluster.Spec.PostgresConfiguration.ReplicaConnection
+	if replicaConnection == nil || replicaConnection.ConnectTimeout == nil {
+		return connInfo
+	}
+
+	return connInfo + fmt.Sprintf(" connect_timeout=%v", *replicaConnection.ConnectTimeout)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary_conninfo generation", func() {
+	It("authenticates the streaming_replica user with its client certificate", func() {
+		GinkgoT().Setenv("PGPORT", "")
+
+		connInfo := buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2")
+		Expect(strings.Fields(connInfo)).To(ConsistOf(
+			"host=cluster-example-rw",
+			"user=streaming_replica",
+			"port=5432",
+			"sslkey="+postgres.StreamingReplicaKeyLocation,
+			"sslcert="+postgres.StreamingReplicaCertificateLocation,
+			"sslrootcert="+postgres.ServerCACertificateLocation,
+			"application_name=cluster-example-2",
+			"sslmode=verify-ca",
+		))
+		Expect(connInfo).ToNot(ContainSubstring("password"))
+	})
+
+	It("registers the replicas with the requested application name", func() {
+		GinkgoT().Setenv("PGPORT", "")
+
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		}
+		Expect(strings.Fields(buildReplicaConnInfo("cluster-example-rw", "cluster-example-2", cluster))).
+			To(ContainElement("application_name=cluster-example-2"))
+
+		cluster.Spec.PostgresConfiguration.ReplicaConnection = &apiv1.ReplicaConnectionConfiguration{
+			ApplicationName: "dc1-{podName}",
+			ConnectTimeout:  ptr.To(int32(10)),
+		}
+		connInfo := buildReplicaConnInfo("cluster-example-rw", "cluster-example-2", cluster)
+		Expect(strings.Fields(connInfo)).To(ContainElements(
+			"host=cluster-example-rw",
+			"application_name=dc1-cluster-example-2",
+			"connect_timeout=10",
+		))
+	})
+})
+
+var _ = Describe("SSL negotiation of the primary_conninfo", func() {
+	newCluster := func(imageName string, mode apiv1.SSLNegotiationMode) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: imageName,
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					SSLNegotiation: mode,
+				},
+			},
+		}
+	}
+
+	It("adds the direct negotiation on PostgreSQL 17", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:17.0", apiv1.SSLNegotiationModeDirect)
+		Expect(withSSLNegotiation("host=cluster-example-rw", cluster)).
+			To(Equal("host=cluster-example-rw sslnegotiation=direct"))
+	})
+
+	It("falls back to the postgres negotiation on older versions", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:16.2", apiv1.SSLNegotiationModeDirect)
+		Expect(withSSLNegotiation("host=cluster-example-rw", cluster)).To(Equal("host=cluster-example-rw"))
+	})
+
+	It("doesn't change the connection string by default", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:17.0", "")
+		Expect(withSSLNegotiation("host=cluster-example-rw", cluster)).To(Equal("host=cluster-example-rw"))
+	})
+})
+
+var _ = Describe("replica connection options of the primary_conninfo", func() {
+	newCluster := func(replicaConnection *apiv1.ReplicaConnectionConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					SSLNegotiation:    apiv1.SSLNegotiationModeDirect,
+					ReplicaConnection: replicaConnection,
+				},
+			},
+		}
+	}
+
+	DescribeTable("renders the connection timeout",
+		func(replicaConnection *apiv1.ReplicaConnectionConfiguration, expected string) {
+			Expect(withReplicaConnectionOptions("host=cluster-example-rw", newCluster(replicaConnection))).
+				To(Equal(expected))
+		},
+		Entry("without replica connection settings",
+			nil,
+			"host=cluster-example-rw sslnegotiation=direct"),
+		Entry("without a connection timeout",
+			&apiv1.ReplicaConnectionConfiguration{RetryInterval: ptr.To(int32(1000))},
+			"host=cluster-example-rw sslnegotiation=direct"),
+		Entry("with the minimum connection timeout",
+			&apiv1.ReplicaConnectionConfiguration{ConnectTimeout: ptr.To(int32(2))},
+			"host=cluster-example-rw sslnegotiation=direct connect_timeout=2"),
+		Entry("with a longer connection timeout",
+			&apiv1.ReplicaConnectionConfiguration{ConnectTimeout: ptr.To(int32(30))},
+			"host=cluster-example-rw sslnegotiation=direct connect_timeout=30"),
+	)
+})
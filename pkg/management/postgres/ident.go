@@ -20,30 +20,75 @@ import (
 	"fmt"
 	"os/user"
 	"path/filepath"
+	"strings"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 )
 
-// WritePostgresUserMaps creates a pg_ident.conf file containing only one map called "local" that
-// maps the current user to "postgres" user.
-func WritePostgresUserMaps(pgData string) error {
-	var username string
+// WritePostgresUserMaps creates a pg_ident.conf file containing the map called "local" that
+// maps the current user to the "postgres" user and to the dedicated monitoring role,
+// followed by the user name maps requested by the user.
+func WritePostgresUserMaps(pgData string, identMaps []apiv1.IdentMapEntry) error {
+	_, err := fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
+		buildPostgresUserMaps(getCurrentUsername(), identMaps))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RefreshPGIdent generates the pg_ident.conf file from the user name maps
+// defined in the cluster, returning true if the file has been changed
+func (instance *Instance) RefreshPGIdent(cluster *apiv1.Cluster) (postgresIdentChanged bool, err error) {
+	identMaps := cluster.Spec.PostgresConfiguration.PgIdent
+	instance.identMaps.Store(&identMaps)
+
+	postgresIdentChanged, err = InstallPgDataFileContent(
+		instance.PgData,
+		buildPostgresUserMaps(getCurrentUsername(), identMaps),
+		constants.PostgresqlIdentFile)
+	if err != nil {
+		return postgresIdentChanged, fmt.Errorf(
+			"installing postgresql user name maps: %w",
+			err)
+	}
 
+	return postgresIdentChanged, nil
+}
+
+// getIdentMaps gets the user name maps latest applied from the cluster
+func (instance *Instance) getIdentMaps() []apiv1.IdentMapEntry {
+	identMaps := instance.identMaps.Load()
+	if identMaps == nil {
+		return nil
+	}
+	return *identMaps
+}
+
+// getCurrentUsername gets the name of the operating system user running
+// the instance manager, falling back to an insecure mapping when it cannot
+// be identified
+func getCurrentUsername() string {
 	currentUser, err := user.Current()
 	if err != nil {
 		log.Info("Unable to identify the current user. Falling back to insecure mapping.")
-		username = "/"
-	} else {
-		username = currentUser.Username
+		return "/"
 	}
 
-	_, err = fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
-		fmt.Sprintf("local %s postgres\n", username))
-	if err != nil {
-		return err
+	return currentUser.Username
+}
+
+// buildPostgresUserMaps generates the content of the pg_ident.conf file
+func buildPostgresUserMaps(username string, identMaps []apiv1.IdentMapEntry) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("local %s postgres\nlocal %s %s\n", username, username, apiv1.MonitoringRoleName))
+	for _, entry := range identMaps {
+		result.WriteString(fmt.Sprintf("%s %s %s\n", entry.MapName, entry.SystemUsername, entry.PGUsername))
 	}
 
-	return nil
+	return result.String()
 }
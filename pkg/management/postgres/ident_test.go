@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_ident.conf generation", func() {
+	It("only contains the local map when no user name maps are defined", func() {
+		Expect(buildPostgresUserMaps("postgres", nil)).To(Equal(
+			"local postgres postgres\nlocal postgres cnpg_monitoring\n"))
+	})
+
+	It("appends the user name maps after the local map", func() {
+		content := buildPostgresUserMaps("postgres", []apiv1.IdentMapEntry{
+			{MapName: "omicron", SystemUsername: "bryanh", PGUsername: "bryanh"},
+			{MapName: "omicron", SystemUsername: `/^(.*)@example\.com$`, PGUsername: `\1`},
+		})
+		Expect(content).To(Equal(
+			"local postgres postgres\n" +
+				"local postgres cnpg_monitoring\n" +
+				"omicron bryanh bryanh\n" +
+				"omicron /^(.*)@example\\.com$ \\1\n"))
+	})
+	It("keeps the user name maps when the file is rewritten", func() {
+		pgData := GinkgoT().TempDir()
+		instance := NewInstance()
+		instance.PgData = pgData
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					PgIdent: []apiv1.IdentMapEntry{
+						{MapName: "omicron", SystemUsername: "bryanh", PGUsername: "bryanh"},
+					},
+				},
+			},
+		}
+
+		changed, err := instance.RefreshPGIdent(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		changed, err = instance.RefreshPGIdent(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		Expect(WritePostgresUserMaps(pgData, instance.getIdentMaps())).To(Succeed())
+		content, err := os.ReadFile(filepath.Join(pgData, constants.PostgresqlIdentFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(HaveSuffix("omicron bryanh bryanh\n"))
+	})
+})
@@ -136,6 +136,9 @@ type Instance struct {
 	// Pool of DB connections pointing to primary instance
 	primaryPool *pool.ConnectionPool
 
+	// Pool of DB connections authenticated as the dedicated monitoring role
+	monitoringPool *pool.ConnectionPool
+
 	// The namespace of the k8s object representing this cluster
 	Namespace string
 
@@ -167,6 +170,10 @@ type Instance struct {
 	// PgRewindIsRunning tells if there is a `pg_rewind` process running
 	PgRewindIsRunning bool
 
+	// replicationConflicts keeps track of the queries canceled because
+	// of conflicts with recovery
+	replicationConflicts conflictsTracker
+
 	// MaxStopDelay is the current MaxStopDelay of the cluster
 	MaxStopDelay int32
 
@@ -185,6 +192,30 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// livenessQuery specifies whether the liveness probe should run a
+	// query on PostgreSQL
+	livenessQuery atomic.Bool
+
+	// livenessProbeTimeout is the time, in nanoseconds, the liveness
+	// probe waits for an answer
+	livenessProbeTimeout atomic.Int64
+
+	// monitoringRoleAvailable specifies whether the metrics exporter
+	// can use the dedicated monitoring role
+	monitoringRoleAvailable atomic.Bool
+
+	// activityQueriesExposed specifies whether the text of the queries
+	// of the active backends may be reported
+	activityQueriesExposed atomic.Bool
+
+	// identMaps are the user name maps latest applied from the cluster,
+	// to be kept when the pg_ident.conf file is rewritten at startup
+	identMaps atomic.Pointer[[]apiv1.IdentMapEntry]
+
+	// replicaConnection are the replica connection settings latest applied
+	// from the cluster, telling the application names of the replicas
+	replicaConnection atomic.Pointer[apiv1.ReplicaConnectionConfiguration]
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
 
@@ -225,6 +256,26 @@ func (instance *Instance) SetMightBeUnavailable(enabled bool) {
 	instance.mightBeUnavailable.Store(enabled)
 }
 
+// SetLivenessQuery marks whether the liveness probe should run a query
+// on PostgreSQL, and sets the time the probe waits for an answer
+func (instance *Instance) SetLivenessQuery(enabled bool, probeTimeout time.Duration) {
+	// The timeout is stored first, as it's read once the query is enabled
+	instance.livenessProbeTimeout.Store(int64(probeTimeout))
+	instance.livenessQuery.Store(enabled)
+}
+
+// SetMonitoringRoleAvailable marks whether the metrics exporter should
+// connect using the dedicated monitoring role
+func (instance *Instance) SetMonitoringRoleAvailable(available bool) {
+	instance.monitoringRoleAvailable.Store(available)
+}
+
+// SetActivityQueriesExposed marks whether the text of the queries of the
+// active backends may be reported when requested
+func (instance *Instance) SetActivityQueriesExposed(exposed bool) {
+	instance.activityQueriesExposed.Store(exposed)
+}
+
 // ConfigureSlotReplicator sends the configuration to the slot replicator
 func (instance *Instance) ConfigureSlotReplicator(config *apiv1.ReplicationSlotsConfiguration) {
 	go func() {
@@ -260,7 +311,7 @@ func (instance *Instance) VerifyPgDataCoherence(ctx context.Context) error {
 		return err
 	}
 
-	return WritePostgresUserMaps(instance.PgData)
+	return WritePostgresUserMaps(instance.PgData, instance.getIdentMaps())
 }
 
 // InstanceCommand are commands for the goroutine managing postgres
@@ -380,6 +431,9 @@ func (instance *Instance) ShutdownConnections() {
 	if instance.primaryPool != nil {
 		instance.primaryPool.ShutdownConnections()
 	}
+	if instance.monitoringPool != nil {
+		instance.monitoringPool.ShutdownConnections()
+	}
 }
 
 // Shutdown shuts down a PostgreSQL instance which was previously started
@@ -614,12 +668,12 @@ func (instance *Instance) WithActiveInstance(inner func() error) error {
 
 // GetSuperUserDB gets a connection to the "postgres" database on this instance
 func (instance *Instance) GetSuperUserDB() (*sql.DB, error) {
-	return instance.ConnectionPool().Connection("postgres")
+	return instance.ConnectionPool().Connection(context.Background(), "postgres")
 }
 
 // GetTemplateDB gets a connection to the "template1" database on this instance
 func (instance *Instance) GetTemplateDB() (*sql.DB, error) {
-	return instance.ConnectionPool().Connection("template1")
+	return instance.ConnectionPool().Connection(context.Background(), "template1")
 }
 
 // GetPgVersion queries the postgres instance to know the current version, parses it and memoize it for future uses
@@ -655,12 +709,80 @@ func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
 			applicationName,
 		)
 
-		instance.pool = pool.NewPostgresqlConnectionPool(dsn)
+		// This is the list of long-running processes of the instance manager
+		// that need a PostgreSQL connection:
+		//
+		// * Declarative Role Management
+		// * Probes
+		// * Replication slots reconciler
+		// * Online VolumeSnapshot backup connection
+		//
+		// The latter will use an exclusive connection, that is required
+		// for the PostgreSQL Physical backup APIs.
+		//
+		// The connections are renewed every hour, releasing the memory
+		// the backends accumulate over a long lifetime
+		instance.pool = pool.NewPostgresqlConnectionPool(
+			dsn,
+			pool.WithMaxOpenConns(3),
+			pool.WithConnMaxLifetime(time.Hour),
+		)
 	}
 
 	return instance.pool
 }
 
+// IsMonitoringRoleAvailable checks whether the metrics exporter should
+// connect using the dedicated monitoring role
+func (instance *Instance) IsMonitoringRoleAvailable() bool {
+	return instance.monitoringRoleAvailable.Load()
+}
+
+// MonitoringRoleConnectionPool gets or initializes the pool of connections
+// authenticated as the dedicated monitoring role
+func (instance *Instance) MonitoringRoleConnectionPool() *pool.ConnectionPool {
+	const applicationName = "cnpg_metrics_exporter"
+	if instance.monitoringPool == nil {
+		socketDir := GetSocketDir()
+		dsn := fmt.Sprintf(
+			"host=%s port=%v user=%v sslmode=disable application_name=%v",
+			socketDir,
+			GetServerPort(),
+			apiv1.MonitoringRoleName,
+			applicationName,
+		)
+
+		// The metrics exporter runs its queries at every scrape: keeping one
+		// idle connection avoids authenticating every time, and renewing it
+		// every hour releases the memory the backend accumulates
+		instance.monitoringPool = pool.NewPostgresqlConnectionPool(
+			dsn,
+			pool.WithMaxIdleConns(1),
+			pool.WithConnMaxIdleTime(time.Minute),
+			pool.WithConnMaxLifetime(time.Hour),
+		)
+	}
+
+	return instance.monitoringPool
+}
+
+// MonitoringConnectionPool gets the connection pool to be used by the
+// metrics exporter. This is the superuser pool unless the dedicated
+// monitoring role has been marked as available
+func (instance *Instance) MonitoringConnectionPool() *pool.ConnectionPool {
+	if instance.IsMonitoringRoleAvailable() {
+		return instance.MonitoringRoleConnectionPool()
+	}
+
+	return instance.ConnectionPool()
+}
+
+// GetMonitoringDB gets a connection to the "postgres" database to be used
+// by the metrics exporter
+func (instance *Instance) GetMonitoringDB() (*sql.DB, error) {
+	return instance.MonitoringConnectionPool().Connection(context.Background(), "postgres")
+}
+
 // PrimaryConnectionPool gets or initializes the primary connection pool for this instance
 func (instance *Instance) PrimaryConnectionPool() *pool.ConnectionPool {
 	if instance.primaryPool == nil {
@@ -700,7 +822,10 @@ func (instance *Instance) Demote(ctx context.Context, cluster *apiv1.Cluster) er
 
 	contextLogger.Info("Demoting instance", "pgpdata", instance.PgData)
 	slotName := cluster.GetSlotNameFromInstanceName(instance.PodName)
-	_, err := UpdateReplicaConfiguration(instance.PgData, instance.GetPrimaryConnInfo(), slotName)
+	_, err := UpdateReplicaConfiguration(
+		instance.PgData,
+		instance.getReplicaConnInfo(cluster),
+		slotName)
 	return err
 }
 
@@ -1132,6 +1257,12 @@ func (instance *Instance) GetPrimaryConnInfo() string {
 	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
 }
 
+// getReplicaConnInfo returns the DSN used by this instance to stream
+// from the primary
+func (instance *Instance) getReplicaConnInfo(cluster *apiv1.Cluster) string {
+	return buildReplicaConnInfo(instance.ClusterName+"-rw", instance.PodName, cluster)
+}
+
 // HandleInstanceCommandRequests execute a command requested by the reconciliation
 // loop.
 func (instance *Instance) HandleInstanceCommandRequests(
@@ -61,7 +61,10 @@ func (instance *Instance) RefreshReplicaConfiguration(
 
 func (instance *Instance) writeReplicaConfigurationForReplica(cluster *apiv1.Cluster) (changed bool, err error) {
 	slotName := cluster.GetSlotNameFromInstanceName(instance.PodName)
-	return UpdateReplicaConfiguration(instance.PgData, instance.GetPrimaryConnInfo(), slotName)
+	return UpdateReplicaConfiguration(
+		instance.PgData,
+		instance.getReplicaConnInfo(cluster),
+		slotName)
 }
 
 func (instance *Instance) writeReplicaConfigurationForDesignatedPrimary(
@@ -225,3 +225,22 @@ var _ = Describe("check atomic bool", func() {
 		Expect(unAvailable).To(BeTrue())
 	})
 })
+
+var _ = Describe("monitoring connection pool", func() {
+	It("uses the superuser until the dedicated monitoring role is available", func() {
+		instance := NewInstance()
+		Expect(instance.MonitoringConnectionPool()).To(BeIdenticalTo(instance.ConnectionPool()))
+		Expect(instance.MonitoringConnectionPool().GetDsn("postgres")).To(ContainSubstring("user=postgres"))
+	})
+
+	It("uses the dedicated monitoring role when it is available", func() {
+		instance := NewInstance()
+		instance.SetMonitoringRoleAvailable(true)
+		Expect(instance.MonitoringConnectionPool()).To(BeIdenticalTo(instance.MonitoringRoleConnectionPool()))
+		Expect(instance.MonitoringConnectionPool().GetDsn("postgres")).
+			To(ContainSubstring("user=" + apiv1.MonitoringRoleName))
+
+		instance.SetMonitoringRoleAvailable(false)
+		Expect(instance.MonitoringConnectionPool()).To(BeIdenticalTo(instance.ConnectionPool()))
+	})
+})
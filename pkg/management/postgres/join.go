@@ -70,7 +70,9 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 
 // Join creates a new instance joined to an existing PostgreSQL cluster
 func (info InitInfo) Join(cluster *apiv1.Cluster) error {
-	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
+	primaryConnInfo := withSSLNegotiation(
+		buildPrimaryConnInfo(info.ParentNode, info.PodName)+" dbname=postgres connect_timeout=5",
+		cluster)
 
 	coredumpFilter := cluster.GetCoredumpFilter()
 	if err := system.SetCoredumpFilter(coredumpFilter); err != nil {
@@ -83,6 +85,9 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	}
 
 	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
-	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
+	_, err = UpdateReplicaConfiguration(
+		info.PgData,
+		buildReplicaConnInfo(info.ClusterName+"-rw", info.PodName, cluster),
+		slotName)
 	return err
 }
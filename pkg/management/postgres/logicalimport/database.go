@@ -47,7 +47,7 @@ func (ds *databaseSnapshotter) getDatabaseList(ctx context.Context, target pool.
 		return passedDatabases, nil
 	}
 
-	dbPostgres, err := target.Connection(postgresDatabase)
+	dbPostgres, err := target.Connection(ctx, postgresDatabase)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +135,7 @@ func (ds *databaseSnapshotter) importDatabases(
 				"section", section,
 			)
 
-			exists, err := ds.databaseExists(target, database)
+			exists, err := ds.databaseExists(ctx, target, database)
 			if err != nil {
 				return err
 			}
@@ -188,7 +188,7 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 	contextLogger.Info("temporarily granting superuser permission to owner user",
 		"owner", owner)
 
-	db, err := target.Connection(targetDatabase)
+	db, err := target.Connection(ctx, targetDatabase)
 	if err != nil {
 		return err
 	}
@@ -235,16 +235,18 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 }
 
 func (ds *databaseSnapshotter) databaseExists(
+	ctx context.Context,
 	target pool.Pooler,
 	dbName string,
 ) (bool, error) {
-	db, err := target.Connection(postgresDatabase)
+	db, err := target.Connection(ctx, postgresDatabase)
 	if err != nil {
 		return false, err
 	}
 
 	var exists bool
-	row := db.QueryRow(
+	row := db.QueryRowContext(
+		ctx,
 		"SELECT EXISTS(SELECT datname FROM pg_catalog.pg_database WHERE datname = $1)",
 		dbName,
 	)
@@ -267,7 +269,7 @@ func (ds *databaseSnapshotter) executePostImportQueries(
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("executing post import user defined queries")
 
-	db, err := target.Connection(database)
+	db, err := target.Connection(ctx, database)
 	if err != nil {
 		return err
 	}
@@ -289,9 +291,15 @@ func (ds *databaseSnapshotter) analyze(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
+	if ds.cluster.ShouldAnalyzeAfterImport() {
+		contextLogger.Info("skipping analyze, the statistics will be regenerated in background " +
+			"once the primary is up")
+		return nil
+	}
+
 	for _, database := range databases {
 		contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
-		db, err := target.Connection(database)
+		db, err := target.Connection(ctx, database)
 		if err != nil {
 			return err
 		}
@@ -314,7 +322,7 @@ func (ds *databaseSnapshotter) dropExtensionsFromDatabase(
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("dropping user-defined extensions from the target (empty) database")
 
-	db, err := target.Connection(database)
+	db, err := target.Connection(ctx, database)
 	if err != nil {
 		return err
 	}
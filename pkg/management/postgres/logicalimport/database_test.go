@@ -74,7 +74,7 @@ var _ = Describe("databaseSnapshotter methods test", func() {
 			rows := sqlmock.NewRows([]string{"*"}).AddRow(true)
 			expectedQuery.WillReturnRows(rows)
 
-			res, err := ds.databaseExists(fp, "test")
+			res, err := ds.databaseExists(ctx, fp, "test")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(res).To(BeTrue())
 		})
@@ -83,7 +83,7 @@ var _ = Describe("databaseSnapshotter methods test", func() {
 			rows := sqlmock.NewRows([]string{"*"}).AddRow(false)
 			expectedQuery.WillReturnRows(rows)
 
-			res, err := ds.databaseExists(fp, "test")
+			res, err := ds.databaseExists(ctx, fp, "test")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(res).To(BeFalse())
 		})
@@ -91,7 +91,7 @@ var _ = Describe("databaseSnapshotter methods test", func() {
 		It("should correctly report errors", func() {
 			err := fmt.Errorf("test error")
 			expectedQuery.WillReturnError(err)
-			_, err = ds.databaseExists(fp, "test")
+			_, err = ds.databaseExists(ctx, fp, "test")
 			Expect(err).To(Equal(err))
 		})
 	})
@@ -128,6 +128,17 @@ var _ = Describe("databaseSnapshotter methods test", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should leave the analyze to the instance manager when requested", func() {
+		ds.cluster.Spec.PostgresConfiguration.AnalyzeAfterImport = true
+		ds.cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+			InitDB: &apiv1.BootstrapInitDB{
+				Import: &apiv1.Import{},
+			},
+		}
+		err := ds.analyze(ctx, fp, []string{"test"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	Context("dropExtensionsFromDatabase testing", func() {
 		var expectedQuery *sqlmock.ExpectedQuery
 
@@ -74,7 +74,7 @@ func cloneRoles(
 func (rs *roleManager) importRoles(ctx context.Context, roles []Role) error {
 	contextLogger := log.FromContext(ctx)
 
-	db, err := rs.destination.Connection(postgresDatabase)
+	db, err := rs.destination.Connection(ctx, postgresDatabase)
 	if err != nil {
 		return err
 	}
@@ -134,7 +134,7 @@ func (rs *roleManager) createSQLStatement(role Role) string {
 
 func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 	contextLogger := log.FromContext(ctx)
-	originDatabase, err := rs.origin.Connection(postgresDatabase)
+	originDatabase, err := rs.origin.Connection(ctx, postgresDatabase)
 	if err != nil {
 		return nil, err
 	}
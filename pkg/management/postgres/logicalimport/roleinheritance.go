@@ -57,7 +57,7 @@ func (rs *roleInheritanceManager) importRoleInheritance(ctx context.Context, ris
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("importing role inheritances")
 
-	db, err := rs.destination.Connection(postgresDatabase)
+	db, err := rs.destination.Connection(ctx, postgresDatabase)
 	if err != nil {
 		return err
 	}
@@ -87,7 +87,7 @@ func (rs *roleInheritanceManager) importRoleInheritance(ctx context.Context, ris
 
 func (rs *roleInheritanceManager) getRoleInheritance(ctx context.Context) ([]RoleInheritance, error) {
 	contextLogger := log.FromContext(ctx)
-	originDB, err := rs.origin.Connection(postgresDatabase)
+	originDB, err := rs.origin.Connection(ctx, postgresDatabase)
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package logicalimport
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -33,7 +34,7 @@ type fakePooler struct {
 	db *sql.DB
 }
 
-func (f fakePooler) Connection(_ string) (*sql.DB, error) {
+func (f fakePooler) Connection(_ context.Context, _ string) (*sql.DB, error) {
 	return f.db, nil
 }
 
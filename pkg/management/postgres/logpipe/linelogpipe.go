@@ -70,15 +70,20 @@ func NewJSONLineLogPipe(fileName string) *LineLogPipe {
 	}
 }
 
-// NewRawLineLogPipe returns a logPipe for raw output
+// NewRawLineLogPipe returns a logPipe for raw output. The lines are also
+// written to the log files in the volume dedicated to the logs, when mounted
 func NewRawLineLogPipe(fileName, name string) *LineLogPipe {
 	logger := log.WithName(name).WithValues("source", fileName)
+	volumeWriter := getLogVolumeWriter()
 
 	return &LineLogPipe{
 		fileName: fileName,
 		handler: func(line []byte) {
 			if len(line) != 0 {
 				logger.Info(string(line))
+				if volumeWriter != nil {
+					volumeWriter.WriteLine(name, line)
+				}
 			}
 		},
 		initialized: concurrency.NewExecuted(),
@@ -41,6 +41,7 @@ type LogPipe struct {
 	fileName        string
 	record          CSVRecordParser
 	fieldsValidator FieldsValidator
+	writer          RecordWriter
 
 	initialized *concurrency.Executed
 	exited      *concurrency.Executed
@@ -52,12 +53,31 @@ var tagRegex = regexp.MustCompile(`(?s)(?P<Tag>^[a-zA-Z]+): (?P<Record>.*)$`)
 // for a specific log line to be parsed
 type FieldsValidator func(int) *ErrFieldCountExtended
 
-// NewLogPipe returns a new LogPipe
+// GetLogDirectory returns the directory where PostgreSQL writes its logs:
+// the volume dedicated to the logs when it is mounted, the default log
+// directory otherwise
+func GetLogDirectory() string {
+	if exists, err := fileutils.FileExists(postgres.LogVolumeDirectory); err != nil || !exists {
+		return postgres.LogPath
+	}
+
+	return postgres.LogVolumeDirectory
+}
+
+// NewLogPipe returns a new LogPipe. The records are written to the
+// instance manager logger and, when the volume dedicated to the logs
+// is mounted, to the log files in it
 func NewLogPipe() *LogPipe {
+	var writer RecordWriter = &LogRecordWriter{}
+	if volumeWriter := getLogVolumeWriter(); volumeWriter != nil {
+		writer = multiRecordWriter{writer, volumeWriter}
+	}
+
 	return &LogPipe{
-		fileName:        filepath.Join(postgres.LogPath, postgres.LogFileName+".csv"),
+		fileName:        filepath.Join(GetLogDirectory(), postgres.LogFileName+".csv"),
 		record:          NewPgAuditLoggingDecorator(),
 		fieldsValidator: LogFieldValidator,
+		writer:          writer,
 
 		initialized: concurrency.NewExecuted(),
 		exited:      concurrency.NewExecuted(),
@@ -147,7 +167,7 @@ func (p *LogPipe) collectLogsFromFile(ctx context.Context) error {
 	// the cancellation signal happened
 	go func() {
 		defer close(errChan)
-		errChan <- p.streamLogFromCSVFile(ctx, f, &LogRecordWriter{})
+		errChan <- p.streamLogFromCSVFile(ctx, f, p.writer)
 	}()
 	select {
 	case <-ctx.Done():
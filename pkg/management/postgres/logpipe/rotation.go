@@ -0,0 +1,244 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// LogVolumeFileName is the name of the file, in the volume dedicated
+	// to the logs, where the PostgreSQL log records are written
+	LogVolumeFileName = "postgresql.json"
+
+	// logVolumeFiles is the number of files, including the one being
+	// written, that are kept in the volume dedicated to the logs
+	logVolumeFiles = 10
+
+	// logVolumeUsage is the percentage of the volume dedicated to the
+	// logs that the log files are allowed to use
+	logVolumeUsage = 80
+
+	// minLogFileSize is the size a log file is always allowed to reach
+	// before being rotated
+	minLogFileSize = 1024 * 1024
+)
+
+// RotatedFile writes to a file, rotating it when it grows over the
+// maximum size. The rotated files are renamed adding a numeric suffix,
+// the oldest one having the highest number, and only maxFiles files
+// are kept
+type RotatedFile struct {
+	fileName string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatedFile creates a new RotatedFile. The file is opened
+// at the first write
+func NewRotatedFile(fileName string, maxSize int64, maxFiles int) *RotatedFile {
+	return &RotatedFile{
+		fileName: fileName,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+}
+
+// Write implements the io.Writer interface. The file is rotated before
+// writing the passed content if it would grow over the maximum size
+func (f *RotatedFile) Write(content []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.size > 0 && f.size+int64(len(content)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(content)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file being written
+func (f *RotatedFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the file in append mode, as it may have been written
+// before a restart of the instance manager
+func (f *RotatedFile) open() error {
+	file, err := os.OpenFile(f.fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate closes the file being written, shifting it
+// and the rotated ones, and removing the oldest one
+func (f *RotatedFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if err := fileutils.RemoveFile(f.rotatedFileName(f.maxFiles - 1)); err != nil {
+		return err
+	}
+	for idx := f.maxFiles - 2; idx >= 0; idx-- {
+		err := os.Rename(f.rotatedFileName(idx), f.rotatedFileName(idx+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rotatedFileName returns the name of the file rotated the
+// passed number of times, zero being the file being written
+func (f *RotatedFile) rotatedFileName(rotations int) string {
+	if rotations == 0 {
+		return f.fileName
+	}
+
+	return fmt.Sprintf("%s.%d", f.fileName, rotations)
+}
+
+// fileRecord is the content of every line of the log files
+type fileRecord struct {
+	Timestamp string      `json:"ts"`
+	Logger    string      `json:"logger"`
+	Message   string      `json:"msg,omitempty"`
+	Record    NamedRecord `json:"record,omitempty"`
+}
+
+// fileRecordWriter writes the log records as JSON lines
+// in a RotatedFile
+type fileRecordWriter struct {
+	file *RotatedFile
+}
+
+// Write implements the RecordWriter interface
+func (writer *fileRecordWriter) Write(record NamedRecord) {
+	writer.write(fileRecord{Logger: record.GetName(), Record: record})
+}
+
+// WriteLine writes a raw log line with the passed logger name
+func (writer *fileRecordWriter) WriteLine(name string, line []byte) {
+	writer.write(fileRecord{Logger: name, Message: string(line)})
+}
+
+func (writer *fileRecordWriter) write(record fileRecord) {
+	record.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	content, err := json.Marshal(record)
+	if err != nil {
+		log.Error(err, "while encoding a log record to be written on the log volume")
+		return
+	}
+
+	if _, err := writer.file.Write(append(content, '\n')); err != nil {
+		log.Error(err, "while writing a log record on the log volume", "fileName", writer.file.fileName)
+	}
+}
+
+// multiRecordWriter writes the log records to every RecordWriter
+type multiRecordWriter []RecordWriter
+
+// Write implements the RecordWriter interface
+func (writers multiRecordWriter) Write(record NamedRecord) {
+	for _, writer := range writers {
+		writer.Write(record)
+	}
+}
+
+var (
+	logVolumeWriter     *fileRecordWriter
+	logVolumeWriterOnce sync.Once
+)
+
+// getLogVolumeWriter returns the writer of the log records on the volume
+// dedicated to the logs, or nil if the volume is not mounted. The files are
+// rotated to use at most logVolumeUsage percent of the volume
+func getLogVolumeWriter() *fileRecordWriter {
+	logVolumeWriterOnce.Do(func() {
+		if GetLogDirectory() != postgres.LogVolumeDirectory {
+			return
+		}
+
+		maxSize := int64(minLogFileSize)
+		if total, _, err := compatibility.GetDiskUsage(postgres.LogVolumeDirectory); err != nil {
+			log.Warning("Cannot get the size of the log volume, using the minimum log file size",
+				"error", err)
+		} else {
+			maxSize = max(maxSize, int64(total/100*logVolumeUsage/logVolumeFiles))
+		}
+
+		logVolumeWriter = &fileRecordWriter{
+			file: NewRotatedFile(
+				filepath.Join(postgres.LogVolumeDirectory, LogVolumeFileName),
+				maxSize,
+				logVolumeFiles,
+			),
+		}
+	})
+
+	return logVolumeWriter
+}
@@ -0,0 +1,95 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rotated log files", func() {
+	var fileName string
+
+	BeforeEach(func() {
+		fileName = filepath.Join(GinkgoT().TempDir(), LogVolumeFileName)
+	})
+
+	readFile := func(name string) string {
+		content, err := os.ReadFile(name) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("rotates the file when it would grow over the maximum size", func() {
+		file := NewRotatedFile(fileName, 10, 3)
+		DeferCleanup(file.Close)
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := file.Write([]byte(line))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(readFile(fileName)).To(Equal("fourth\n"))
+		Expect(readFile(fileName + ".1")).To(Equal("third\n"))
+		Expect(readFile(fileName + ".2")).To(Equal("second\n"))
+		Expect(fileName + ".3").ToNot(BeAnExistingFile())
+	})
+
+	It("appends to the file written before a restart", func() {
+		Expect(os.WriteFile(fileName, []byte("before\n"), 0o600)).To(Succeed())
+
+		file := NewRotatedFile(fileName, 10, 3)
+		DeferCleanup(file.Close)
+		_, err := file.Write([]byte("after\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(readFile(fileName)).To(Equal("after\n"))
+		Expect(readFile(fileName + ".1")).To(Equal("before\n"))
+	})
+
+	It("writes the records and the raw lines as JSON lines", func() {
+		file := NewRotatedFile(fileName, 1024*1024, 3)
+		DeferCleanup(file.Close)
+		writer := &fileRecordWriter{file: file}
+
+		record := &LoggingRecord{}
+		record.ErrorSeverity = "LOG"
+		record.Message = "database system is ready to accept connections"
+		writer.Write(record)
+		writer.WriteLine(LoggingCollectorRecordName, []byte("raw message"))
+
+		lines := strings.Split(strings.TrimSpace(readFile(fileName)), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		var content map[string]interface{}
+		Expect(json.Unmarshal([]byte(lines[0]), &content)).To(Succeed())
+		Expect(content).To(HaveKeyWithValue("logger", record.GetName()))
+		Expect(content).To(HaveKey("ts"))
+		Expect(content["record"]).To(HaveKeyWithValue("message", record.Message))
+
+		content = nil
+		Expect(json.Unmarshal([]byte(lines[1]), &content)).To(Succeed())
+		Expect(content).To(HaveKeyWithValue("logger", LoggingCollectorRecordName))
+		Expect(content).To(HaveKeyWithValue("msg", "raw message"))
+		Expect(content).ToNot(HaveKey("record"))
+	})
+})
@@ -120,7 +120,7 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 
 		allTargetDatabases := q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache)
 		for targetDatabase := range allTargetDatabases {
-			conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
+			conn, err := q.instance.MonitoringConnectionPool().Connection(context.Background(), targetDatabase)
 			if err != nil {
 				q.reportUserQueryErrorMetric(name + ": " + err.Error())
 				continue
@@ -207,7 +207,7 @@ func (q QueriesCollector) expandTargetDatabases(
 }
 
 func (q QueriesCollector) getAllAccessibleDatabases() ([]string, error) {
-	conn, err := q.instance.ConnectionPool().Connection(q.defaultDBName)
+	conn, err := q.instance.MonitoringConnectionPool().Connection(context.Background(), q.defaultDBName)
 	if err != nil {
 		return nil, fmt.Errorf("while connecting to expand target_database *: %w", err)
 	}
@@ -244,6 +244,27 @@ func (q QueriesCollector) Describe(ch chan<- *prometheus.Desc) {
 	q.errorUserQueriesGauge.Describe(ch)
 }
 
+// DefinesMetric checks whether one of the queries produces the metric
+// with the passed fully qualified name
+func (q *QueriesCollector) DefinesMetric(name string) bool {
+	if q == nil {
+		return false
+	}
+
+	for queryName, userQuery := range q.userQueries {
+		namespace := fmt.Sprintf("%v_%v", q.collectorName, queryName)
+		for _, columnMapping := range userQuery.Metrics {
+			for columnName, columnDescriptor := range columnMapping {
+				if columnDescriptor.metricName(columnName, namespace) == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 // NewQueriesCollector creates a new PgCollector working over a set of custom queries
 // supplied by the user
 func NewQueriesCollector(
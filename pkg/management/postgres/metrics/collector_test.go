@@ -62,6 +62,36 @@ var _ = Describe("Set default queries", func() {
 	})
 })
 
+var _ = Describe("Metrics defined by the queries", func() {
+	It("finds the metrics produced by the queries", func() {
+		q := NewQueriesCollector("cnpg", nil, "db")
+		Expect(q.ParseQueries([]byte(`
+pg_database:
+  query: "SELECT datname, pg_database_size(datname) AS size_bytes, '1s' AS age FROM pg_database"
+  metrics:
+    - datname:
+        usage: "LABEL"
+        description: "Name of the database"
+    - size_bytes:
+        usage: "GAUGE"
+        description: "Disk space used by the database"
+    - age:
+        usage: "DURATION"
+        description: "Some duration"
+`))).To(Succeed())
+
+		Expect(q.DefinesMetric("cnpg_pg_database_size_bytes")).To(BeTrue())
+		Expect(q.DefinesMetric("cnpg_pg_database_age_milliseconds")).To(BeTrue())
+		Expect(q.DefinesMetric("cnpg_pg_database_datname")).To(BeFalse())
+		Expect(q.DefinesMetric("cnpg_pg_stat_database_temp_files")).To(BeFalse())
+	})
+
+	It("works without queries", func() {
+		var q *QueriesCollector
+		Expect(q.DefinesMetric("cnpg_pg_database_size_bytes")).To(BeFalse())
+	})
+})
+
 var _ = Describe("QueryCollector tests", func() {
 	Context("collect metric tests", func() {
 		It("should ensure that a metric without conversion is discarded", func() {
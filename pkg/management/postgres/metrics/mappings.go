@@ -87,6 +87,19 @@ func (userQuery UserQuery) ToMetricMap(namespace string) (result MetricMapSet, v
 	return result, variableLabels
 }
 
+// metricName returns the fully qualified name of the metric generated for
+// the column, or an empty string when the column doesn't generate one
+func (columnMapping ColumnMapping) metricName(columnName, namespace string) string {
+	switch columnMapping.Usage {
+	case COUNTER, GAUGE, HISTOGRAM, MAPPEDMETRIC:
+		return fmt.Sprintf("%s_%s", namespace, columnName)
+	case DURATION:
+		return fmt.Sprintf("%s_%s_milliseconds", namespace, columnName)
+	default:
+		return ""
+	}
+}
+
 // ToMetricMap transform this query mapping in the metadata for a Prometheus metric. Since a query
 // from the user can result in multiple metrics being generated (histograms are an example
 // of this behavior), we are returning a mapping, which therefore should be collected together
@@ -19,8 +19,11 @@ limitations under the License.
 package pool
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"sync"
+	"time"
 
 	// this is needed to correctly open the sql connection with the pgx driver
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -31,15 +34,77 @@ import (
 // and shutting down all active connections.
 type Pooler interface {
 	// Connection gets the connection for the given database
-	Connection(dbname string) (*sql.DB, error)
+	Connection(ctx context.Context, dbname string) (*sql.DB, error)
 	// GetDsn returns the connection string for a given database
 	GetDsn(dbname string) string
 	// ShutdownConnections closes every database connection
 	ShutdownConnections()
 }
 
+// Options contains the settings applied to the connections of every
+// database of a pool
+type Options struct {
+	// The maximum number of open connections to a database
+	MaxOpenConns int
+
+	// The maximum number of idle connections kept for a database
+	MaxIdleConns int
+
+	// The maximum amount of time a connection may be reused, zero
+	// meaning that the connections are reused forever
+	ConnMaxLifetime time.Duration
+
+	// The maximum amount of time a connection may be idle, zero
+	// meaning that the idle connections are never closed
+	ConnMaxIdleTime time.Duration
+}
+
+// Option changes the options of a connection pool
+type Option func(options *Options)
+
+// WithMaxOpenConns sets the maximum number of open connections to a database
+func WithMaxOpenConns(value int) Option {
+	return func(options *Options) {
+		options.MaxOpenConns = value
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections kept for a database
+func WithMaxIdleConns(value int) Option {
+	return func(options *Options) {
+		options.MaxIdleConns = value
+	}
+}
+
+// WithConnMaxLifetime sets the maximum amount of time a connection may be reused
+func WithConnMaxLifetime(value time.Duration) Option {
+	return func(options *Options) {
+		options.ConnMaxLifetime = value
+	}
+}
+
+// WithConnMaxIdleTime sets the maximum amount of time a connection may be idle
+func WithConnMaxIdleTime(value time.Duration) Option {
+	return func(options *Options) {
+		options.ConnMaxIdleTime = value
+	}
+}
+
+// defaultOptions returns the options used when creating a new pool,
+// not keeping any idle connection
+func defaultOptions() Options {
+	return Options{
+		MaxOpenConns: 3,
+		MaxIdleConns: 0,
+	}
+}
+
 // ConnectionPool is a repository of DB connections, pointing to the same instance
-// given a base DSN without the "dbname" parameter
+// given a base DSN without the "dbname" parameter. It is safe for concurrent use.
+//
+// The connections are opened lazily by the returned *sql.DB objects: callers
+// are expected to use their context-aware methods, like ExecContext and
+// QueryContext, to bound the time spent waiting for the server
 type ConnectionPool struct {
 	// This is the base connection string (without the "dbname" parameter)
 	baseConnectionString string
@@ -47,34 +112,58 @@ type ConnectionPool struct {
 	// The configuration to be used
 	connectionProfile ConnectionProfile
 
+	// The settings of the connections
+	options Options
+
+	// This protects the map of connections
+	mu sync.Mutex
+
 	// A map of connection for every used database
 	connectionMap map[string]*sql.DB
 }
 
 // NewPostgresqlConnectionPool creates a new connectionMap of connections given
 // the base connection string, targeting a PostgreSQL server
-func NewPostgresqlConnectionPool(baseConnectionString string) *ConnectionPool {
-	return newConnectionPool(baseConnectionString, ConnectionProfilePostgresql)
+func NewPostgresqlConnectionPool(baseConnectionString string, options ...Option) *ConnectionPool {
+	return newConnectionPool(baseConnectionString, ConnectionProfilePostgresql, options...)
 }
 
 // NewPgbouncerConnectionPool creates a new connectionMap of connections given
 // the base connection string
-func NewPgbouncerConnectionPool(baseConnectionString string) *ConnectionPool {
-	return newConnectionPool(baseConnectionString, ConnectionProfilePgbouncer)
+func NewPgbouncerConnectionPool(baseConnectionString string, options ...Option) *ConnectionPool {
+	return newConnectionPool(baseConnectionString, ConnectionProfilePgbouncer, options...)
 }
 
 // newConnectionPool creates a new connectionMap of connections given
 // the base connection string
-func newConnectionPool(baseConnectionString string, connectionProfile ConnectionProfile) *ConnectionPool {
+func newConnectionPool(
+	baseConnectionString string,
+	connectionProfile ConnectionProfile,
+	options ...Option,
+) *ConnectionPool {
+	poolOptions := defaultOptions()
+	for _, option := range options {
+		option(&poolOptions)
+	}
+
 	return &ConnectionPool{
 		baseConnectionString: baseConnectionString,
 		connectionMap:        make(map[string]*sql.DB),
 		connectionProfile:    connectionProfile,
+		options:              poolOptions,
 	}
 }
 
-// Connection gets the connection for the given database
-func (pool *ConnectionPool) Connection(dbname string) (*sql.DB, error) {
+// Connection gets the connection for the given database, failing if the
+// passed context is already done
+func (pool *ConnectionPool) Connection(ctx context.Context, dbname string) (*sql.DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
 	if result, ok := pool.connectionMap[dbname]; ok {
 		return result, nil
 	}
@@ -90,6 +179,9 @@ func (pool *ConnectionPool) Connection(dbname string) (*sql.DB, error) {
 
 // ShutdownConnections closes every database connection
 func (pool *ConnectionPool) ShutdownConnections() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
 	for _, db := range pool.connectionMap {
 		_ = db.Close()
 	}
@@ -106,19 +198,10 @@ func (pool *ConnectionPool) newConnection(dbname string) (*sql.DB, error) {
 		return nil, fmt.Errorf("cannot create connection connectionMap: %w", err)
 	}
 
-	// This is the list of long-running processes of the instance manager
-	// that need a PostgreSQL connection:
-	//
-	// * Declarative Role Management
-	// * Probes
-	// * Replication slots reconciler
-	// * Online VolumeSnapshot backup connection
-	//
-	// The latter will use an exclusive connection, that is required
-	// for the PostgreSQL Physical backup APIs
-
-	db.SetMaxOpenConns(3)
-	db.SetMaxIdleConns(0)
+	db.SetMaxOpenConns(pool.options.MaxOpenConns)
+	db.SetMaxIdleConns(pool.options.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.options.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.options.ConnMaxIdleTime)
 
 	return db, nil
 }
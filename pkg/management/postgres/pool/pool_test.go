@@ -17,6 +17,10 @@ limitations under the License.
 package pool
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	_ "github.com/lib/pq"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -40,13 +44,68 @@ var _ = Describe("Connection pool test", func() {
 
 	It("stores created connections", func() {
 		pool := NewPostgresqlConnectionPool("host=127.0.0.1")
-		Expect(pool.Connection("test")).ToNot(BeNil())
+		Expect(pool.Connection(context.Background(), "test")).ToNot(BeNil())
 		Expect(pool.connectionMap).To(HaveLen(1))
 	})
 
+	It("limits the open connections by default", func() {
+		pool := NewPostgresqlConnectionPool("host=127.0.0.1")
+		conn, err := pool.Connection(context.Background(), "test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Stats().MaxOpenConnections).To(Equal(3))
+	})
+
+	It("applies the passed options to the connections", func() {
+		pool := NewPgbouncerConnectionPool("host=127.0.0.1",
+			WithMaxOpenConns(1),
+			WithMaxIdleConns(1),
+			WithConnMaxLifetime(time.Minute),
+			WithConnMaxIdleTime(time.Second),
+		)
+		Expect(pool.options).To(Equal(Options{
+			MaxOpenConns:    1,
+			MaxIdleConns:    1,
+			ConnMaxLifetime: time.Minute,
+			ConnMaxIdleTime: time.Second,
+		}))
+
+		conn, err := pool.Connection(context.Background(), "test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Stats().MaxOpenConnections).To(Equal(1))
+	})
+
+	It("returns the same connection to concurrent callers", func() {
+		pool := NewPostgresqlConnectionPool("host=127.0.0.1")
+		results := make(chan *sql.DB, 10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				defer GinkgoRecover()
+				conn, err := pool.Connection(context.Background(), "test")
+				Expect(err).ToNot(HaveOccurred())
+				results <- conn
+			}()
+		}
+
+		first := <-results
+		for i := 0; i < 9; i++ {
+			Expect(<-results).To(BeIdenticalTo(first))
+		}
+		Expect(pool.connectionMap).To(HaveLen(1))
+	})
+
+	It("refuses to hand out connections when the context is done", func() {
+		pool := NewPostgresqlConnectionPool("host=127.0.0.1")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := pool.Connection(ctx, "test")
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(pool.connectionMap).To(BeEmpty())
+	})
+
 	It("shut down connections on request", func() {
 		pool := NewPostgresqlConnectionPool("host=127.0.0.1")
-		Expect(pool.Connection("test")).ToNot(BeNil())
+		Expect(pool.Connection(context.Background(), "test")).ToNot(BeNil())
 		pool.ShutdownConnections()
 		Expect(pool.connectionMap).To(BeEmpty())
 	})
@@ -0,0 +1,48 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pooltest contains the testing utils for the code using a connection pooler
+package pooltest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// FakePooler is a Pooler returning the connection registered for a database
+type FakePooler struct {
+	// Databases maps the name of a database to its connection
+	Databases map[string]*sql.DB
+}
+
+// Connection returns the connection registered for the given database
+func (f FakePooler) Connection(_ context.Context, dbname string) (*sql.DB, error) {
+	db, ok := f.Databases[dbname]
+	if !ok {
+		return nil, fmt.Errorf("unknown database %s", dbname)
+	}
+	return db, nil
+}
+
+// GetDsn returns the name of the database, as there is no real connection string
+func (f FakePooler) GetDsn(dbname string) string {
+	return dbname
+}
+
+// ShutdownConnections does nothing, the connections are owned by the caller
+func (f FakePooler) ShutdownConnections() {
+}
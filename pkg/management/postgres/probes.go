@@ -17,12 +17,14 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,7 +32,9 @@ import (
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -46,8 +50,48 @@ func (instance *Instance) IsServerHealthy() error {
 	if errors.Is(err, ErrPgRejectingConnection) {
 		return nil
 	}
+	if err != nil || !instance.livenessQuery.Load() {
+		return err
+	}
 
-	return err
+	// The query uses a dedicated connection, as the ones of the pool can
+	// all be taken by the other users of the instance manager, failing
+	// the probe of a healthy server
+	db, err := pool.NewDBConnection(
+		instance.ConnectionPool().GetDsn("postgres"),
+		pool.ConnectionProfilePostgresql,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	db.SetMaxOpenConns(1)
+
+	return checkLivenessQuery(db, getLivenessQueryTimeout(time.Duration(instance.livenessProbeTimeout.Load())))
+}
+
+// getLivenessQueryTimeout gets the time the liveness query has to connect
+// to PostgreSQL and complete, which is half of the timeout of the probe,
+// leaving the rest to `pg_isready` and to the HTTP request of the probe
+func getLivenessQueryTimeout(probeTimeout time.Duration) time.Duration {
+	return probeTimeout / 2
+}
+
+// checkLivenessQuery runs a lightweight query on the server. Unlike
+// `pg_isready`, it detects a server that accepts the connections without
+// serving them
+func checkLivenessQuery(db *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var result int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&result); err != nil {
+		return fmt.Errorf("liveness query failed: %w", err)
+	}
+
+	return nil
 }
 
 // IsServerReady check if the instance is healthy and can really accept connections
@@ -60,7 +104,45 @@ func (instance *Instance) IsServerReady() error {
 		return err
 	}
 
-	return superUserDB.Ping()
+	if err := superUserDB.Ping(); err != nil {
+		return err
+	}
+
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !isPrimary {
+		return nil
+	}
+
+	return checkPrimaryAcceptsWrites(superUserDB)
+}
+
+// ErrPrimaryNotAcceptingWrites is raised by the readiness probe when
+// the primary instance is up and running but not able to accept writes
+var ErrPrimaryNotAcceptingWrites = errors.New("primary instance is not accepting writes")
+
+// checkPrimaryAcceptsWrites checks if the server is out of recovery and
+// new transactions are not read-only by default. The check doesn't
+// write anything, so it cannot be blocked by synchronous replication
+func checkPrimaryAcceptsWrites(db *sql.DB) error {
+	var inRecovery, readOnly bool
+	row := db.QueryRow(
+		"SELECT pg_catalog.pg_is_in_recovery(), " +
+			"pg_catalog.current_setting('default_transaction_read_only')::boolean")
+	if err := row.Scan(&inRecovery, &readOnly); err != nil {
+		return err
+	}
+
+	switch {
+	case inRecovery:
+		return fmt.Errorf("%w: the server is still in recovery", ErrPrimaryNotAcceptingWrites)
+	case readOnly:
+		return fmt.Errorf("%w: default_transaction_read_only is enabled", ErrPrimaryNotAcceptingWrites)
+	}
+
+	return nil
 }
 
 // GetStatus Extract the status of this PostgreSQL database
@@ -107,8 +189,11 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 			-- True if at least one column requires a restart
 			EXISTS(SELECT 1 FROM pg_settings WHERE pending_restart),
 			-- The size of database in human readable format
-			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database)`)
-	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.TotalInstanceSize)
+			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database),
+			-- The default isolation level of the transactions
+			current_setting('default_transaction_isolation')`)
+	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.TotalInstanceSize,
+		&result.DefaultTransactionIsolation)
 	if err != nil {
 		return result, err
 	}
@@ -134,6 +219,15 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 
 	result.IsInstanceManagerUpgrading = instance.InstanceManagerIsUpgrading.Load()
 
+	// The disk usage is used to trigger a switchover when the primary is
+	// running out of space, but not getting it is not a reason to fail
+	if total, available, diskErr := compatibility.GetDiskUsage(instance.PgData); diskErr == nil {
+		result.DataDiskTotalBytes = total
+		result.DataDiskAvailableBytes = available
+	} else {
+		log.Debug("Error while getting the disk usage of PGDATA", "err", diskErr)
+	}
+
 	return result, nil
 }
 
@@ -275,6 +369,22 @@ func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
 		return err
 	}
 
+	if err := instance.fillDataChecksumsStatus(superUserDB, result); err != nil {
+		return err
+	}
+
+	if err := fillReplicationSlotsUsage(superUserDB, result); err != nil {
+		return err
+	}
+
+	if err := fillWalSegmentSize(superUserDB, result); err != nil {
+		return err
+	}
+
+	if err := instance.fillLocaleProvider(superUserDB, result); err != nil {
+		return err
+	}
+
 	return instance.fillWalStatus(result)
 }
 
@@ -362,6 +472,76 @@ func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatu
 	return err
 }
 
+// dataChecksumsQuery reads whether the data checksums are enabled, and the
+// number of checksum failures detected in every database, including the
+// row of the shared objects. The failures are NULL without data checksums
+const dataChecksumsQuery = `SELECT pg_catalog.current_setting('data_checksums')::boolean,
+  COALESCE((SELECT sum(checksum_failures) FROM pg_catalog.pg_stat_database), 0)::bigint`
+
+// fillDataChecksumsStatus get information about the data checksum failures,
+// that are counted from PostgreSQL 12
+func (instance *Instance) fillDataChecksumsStatus(
+	superUserDB *sql.DB,
+	result *postgres.PostgresqlStatus,
+) error {
+	if ver, _ := instance.GetPgVersion(); ver.Major < 12 {
+		return nil
+	}
+
+	var enabled bool
+	if err := superUserDB.QueryRow(dataChecksumsQuery).Scan(&enabled, &result.ChecksumFailures); err != nil {
+		return err
+	}
+	result.DataChecksumsEnabled = &enabled
+
+	return nil
+}
+
+// replicationSlotsUsageQuery reads the number of existing replication slots,
+// including the temporary ones, and the maximum number of them allowed
+const replicationSlotsUsageQuery = `SELECT
+  (SELECT count(*) FROM pg_catalog.pg_replication_slots)::int,
+  pg_catalog.current_setting('max_replication_slots')::int`
+
+// fillReplicationSlotsUsage get how many replication slots are used,
+// compared to the limit set by max_replication_slots
+func fillReplicationSlotsUsage(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	return superUserDB.QueryRow(replicationSlotsUsageQuery).Scan(
+		&result.ReplicationSlotsUsed,
+		&result.MaxReplicationSlots,
+	)
+}
+
+// walSegmentSizeQuery reads the size of the WAL segments chosen by initdb
+const walSegmentSizeQuery = `SELECT bytes_per_wal_segment FROM pg_catalog.pg_control_init()`
+
+// fillWalSegmentSize get the size of the WAL segments from the control file
+func fillWalSegmentSize(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	return superUserDB.QueryRow(walSegmentSizeQuery).Scan(&result.WalSegmentSize)
+}
+
+// localeProviderQuery reads the default collation provider of the new
+// databases, which is the one of the template1 database
+const localeProviderQuery = `SELECT CASE datlocprovider
+  WHEN 'c' THEN 'libc'
+  WHEN 'i' THEN 'icu'
+  WHEN 'b' THEN 'builtin'
+  ELSE datlocprovider::text
+END
+FROM pg_catalog.pg_database
+WHERE datname = 'template1'`
+
+// fillLocaleProvider get the default collation provider of the databases.
+// Before PostgreSQL 15 it can only be libc
+func (instance *Instance) fillLocaleProvider(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	if ver, _ := instance.GetPgVersion(); ver.Major < 15 {
+		result.LocaleProvider = "libc"
+		return nil
+	}
+
+	return superUserDB.QueryRow(localeProviderQuery).Scan(&result.LocaleProvider)
+}
+
 // fillArchiverStatus get information about the PostgreSQL archiving process
 func fillArchiverStatus(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
 	row := superUserDB.QueryRow(
@@ -482,7 +662,7 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			coalesce(sync_priority, 0)
 		FROM pg_catalog.pg_stat_replication
 		WHERE application_name ~ $1 AND usename = $2`,
-		fmt.Sprintf("%s-[0-9]+$", instance.ClusterName),
+		instance.replicaConnection.Load().GetApplicationNameRegex(instance.ClusterName),
 		v1.StreamingReplicationUser,
 	)
 	if err != nil {
@@ -520,7 +700,7 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 		return err
 	}
 
-	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
+	result.ReadyWALFiles, _, err = GetWALArchiveCounters(specs.PgWalArchiveStatusPath)
 	if err != nil {
 		return err
 	}
@@ -560,6 +740,13 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 	if err != nil {
 		return err
 	}
+
+	var conflicts int64
+	if err := superUserDB.QueryRow(replicationConflictsQuery).Scan(&conflicts); err != nil {
+		return err
+	}
+	result.RecentReplicationConflicts = instance.replicationConflicts.observe(time.Now(), conflicts)
+
 	return nil
 }
 
@@ -638,9 +825,10 @@ func (instance *Instance) TryGetPgStatWAL() (*PgStatWal, error) {
 }
 
 // GetWALArchiveCounters returns the number of WAL files with status ready,
-// and the number of those in status done.
-func GetWALArchiveCounters() (ready, done int, err error) {
-	files, err := fileutils.GetDirectoryContent(specs.PgWalArchiveStatusPath)
+// and the number of those in status done, in the passed archive status
+// directory.
+func GetWALArchiveCounters(archiveStatusPath string) (ready, done int, err error) {
+	files, err := fileutils.GetDirectoryContent(archiveStatusPath)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -17,8 +17,11 @@ limitations under the License.
 package postgres
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/blang/semver"
@@ -88,6 +91,100 @@ var _ = Describe("probes", func() {
 		Expect(status.IsArchivingWAL).To(BeFalse())
 	})
 
+	Context("Fill data checksums status", func() {
+		It("does nothing in case of that major version is less than 12", func() {
+			instance := &Instance{
+				pgVersion: &semver.Version{Major: 11},
+			}
+			status := &postgres.PostgresqlStatus{}
+			Expect(instance.fillDataChecksumsStatus(nil, status)).To(Succeed())
+			Expect(status.DataChecksumsEnabled).To(BeNil())
+		})
+
+		It("reports the checksum failures", func() {
+			instance := &Instance{
+				pgVersion: &semver.Version{Major: 16},
+			}
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			Expect(err).ToNot(HaveOccurred())
+			mock.ExpectQuery(dataChecksumsQuery).
+				WillReturnRows(sqlmock.NewRows([]string{"data_checksums", "checksum_failures"}).AddRow(true, 3))
+
+			status := &postgres.PostgresqlStatus{}
+			Expect(instance.fillDataChecksumsStatus(db, status)).To(Succeed())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+			Expect(status.DataChecksumsEnabled).To(HaveValue(BeTrue()))
+			Expect(status.ChecksumFailures).To(BeEquivalentTo(3))
+		})
+
+		It("reports when the data checksums are disabled", func() {
+			instance := &Instance{
+				pgVersion: &semver.Version{Major: 16},
+			}
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			Expect(err).ToNot(HaveOccurred())
+			mock.ExpectQuery(dataChecksumsQuery).
+				WillReturnRows(sqlmock.NewRows([]string{"data_checksums", "checksum_failures"}).AddRow(false, 0))
+
+			status := &postgres.PostgresqlStatus{}
+			Expect(instance.fillDataChecksumsStatus(db, status)).To(Succeed())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+			Expect(status.DataChecksumsEnabled).To(HaveValue(BeFalse()))
+			Expect(status.ChecksumFailures).To(BeZero())
+		})
+	})
+
+	Context("Fill the locale provider", func() {
+		It("reports libc before PostgreSQL 15 without querying the server", func() {
+			instance := &Instance{
+				pgVersion: &semver.Version{Major: 14},
+			}
+			status := &postgres.PostgresqlStatus{}
+			Expect(instance.fillLocaleProvider(nil, status)).To(Succeed())
+			Expect(status.LocaleProvider).To(Equal("libc"))
+		})
+
+		It("reports the locale provider of the template1 database", func() {
+			instance := &Instance{
+				pgVersion: &semver.Version{Major: 16},
+			}
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			Expect(err).ToNot(HaveOccurred())
+			mock.ExpectQuery(localeProviderQuery).
+				WillReturnRows(sqlmock.NewRows([]string{"datlocprovider"}).AddRow("icu"))
+
+			status := &postgres.PostgresqlStatus{}
+			Expect(instance.fillLocaleProvider(db, status)).To(Succeed())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+			Expect(status.LocaleProvider).To(Equal("icu"))
+		})
+	})
+
+	It("reports the replication slots usage", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery(replicationSlotsUsageQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "current_setting"}).AddRow(3, 10))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillReplicationSlotsUsage(db, status)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(status.ReplicationSlotsUsed).To(Equal(3))
+		Expect(status.MaxReplicationSlots).To(Equal(10))
+	})
+
+	It("reports the WAL segment size", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery(walSegmentSizeQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"bytes_per_wal_segment"}).AddRow(16 * 1024 * 1024))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillWalSegmentSize(db, status)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(status.WalSegmentSize).To(Equal(int64(16 * 1024 * 1024)))
+	})
+
 	Context("Fill basebackup stats", func() {
 		It("does nothing in case of that major version is less than 13 ", func() {
 			instance := &Instance{
@@ -147,4 +244,96 @@ var _ = Describe("probes", func() {
 			Expect(status.PgStatBasebackupsInfo[0].TablespacesStreamed).To(Equal(int64(1)))
 		})
 	})
+
+	Context("primary accepting writes", func() {
+		const query = "SELECT pg_catalog.pg_is_in_recovery(), " +
+			"pg_catalog.current_setting('default_transaction_read_only')::boolean"
+
+		var (
+			db   *sql.DB
+			mock sqlmock.Sqlmock
+		)
+
+		BeforeEach(func() {
+			var err error
+			db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("succeeds when the primary is accepting writes", func() {
+			mock.ExpectQuery(query).WillReturnRows(
+				sqlmock.NewRows([]string{"pg_is_in_recovery", "current_setting"}).AddRow(false, false))
+			Expect(checkPrimaryAcceptsWrites(db)).To(Succeed())
+		})
+
+		It("fails when new transactions are read-only by default", func() {
+			mock.ExpectQuery(query).WillReturnRows(
+				sqlmock.NewRows([]string{"pg_is_in_recovery", "current_setting"}).AddRow(false, true))
+			err := checkPrimaryAcceptsWrites(db)
+			Expect(err).To(MatchError(ErrPrimaryNotAcceptingWrites))
+			Expect(err.Error()).To(ContainSubstring("default_transaction_read_only"))
+		})
+
+		It("fails when the server is still in recovery", func() {
+			mock.ExpectQuery(query).WillReturnRows(
+				sqlmock.NewRows([]string{"pg_is_in_recovery", "current_setting"}).AddRow(true, false))
+			err := checkPrimaryAcceptsWrites(db)
+			Expect(err).To(MatchError(ErrPrimaryNotAcceptingWrites))
+			Expect(err.Error()).To(ContainSubstring("recovery"))
+		})
+
+		It("reports the query errors", func() {
+			errFailedQuery := fmt.Errorf("failed query")
+			mock.ExpectQuery(query).WillReturnError(errFailedQuery)
+			err := checkPrimaryAcceptsWrites(db)
+			Expect(err).To(MatchError(errFailedQuery))
+			Expect(errors.Is(err, ErrPrimaryNotAcceptingWrites)).To(BeFalse())
+		})
+	})
+
+	Context("liveness query", func() {
+		var (
+			db   *sql.DB
+			mock sqlmock.Sqlmock
+		)
+
+		BeforeEach(func() {
+			var err error
+			db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(func() {
+				_ = db.Close()
+			})
+		})
+
+		It("succeeds when the server answers the query", func() {
+			mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			Expect(checkLivenessQuery(db, time.Second)).To(Succeed())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("fails when the server doesn't answer before the timeout", func() {
+			mock.ExpectQuery("SELECT 1").
+				WillDelayFor(time.Second).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			start := time.Now()
+			err := checkLivenessQuery(db, 50*time.Millisecond)
+			Expect(err).To(MatchError(ContainSubstring("liveness query failed")))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+
+		It("reports the query errors", func() {
+			errFailedQuery := fmt.Errorf("the database system is in recovery mode")
+			mock.ExpectQuery("SELECT 1").WillReturnError(errFailedQuery)
+			Expect(checkLivenessQuery(db, time.Second)).To(MatchError(errFailedQuery))
+		})
+
+		It("leaves half of the timeout of the probe to the query", func() {
+			Expect(getLivenessQueryTimeout(5 * time.Second)).To(Equal(2500 * time.Millisecond))
+		})
+	})
 })
@@ -43,6 +43,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/progress"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
@@ -50,6 +51,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/volumebackup"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -154,7 +156,12 @@ func (info InitInfo) RestoreSnapshot(ctx context.Context, cli client.Client, imm
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+	restoreCommand, err := getObjectStoreRestoreCommand(backup, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := info.writeRestoreWalConfig(restoreCommand, cluster); err != nil {
 		return err
 	}
 
@@ -238,17 +245,14 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	// If we need to download data from a backup, we do it
-	backup, env, err := info.loadBackup(ctx, typedClient, cluster)
-	if err != nil {
-		return err
-	}
-
-	if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
-		return err
+	var restoreCommand string
+	var env []string
+	if cluster.Spec.Bootstrap.Recovery.VolumeBackup != nil {
+		restoreCommand, err = info.restoreVolumeBackup(ctx, cluster)
+	} else {
+		restoreCommand, env, err = info.restoreObjectStoreBackup(ctx, typedClient, cluster)
 	}
-
-	if err := info.restoreDataDir(backup, env); err != nil {
+	if err != nil {
 		return err
 	}
 
@@ -287,13 +291,100 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+	if err := info.writeRestoreWalConfig(restoreCommand, cluster); err != nil {
 		return err
 	}
 
 	return info.ConfigureInstanceAfterRestore(ctx, cluster, env)
 }
 
+// restoreObjectStoreBackup restores PGDATA from a backup stored in an
+// object store, returning the restore_command downloading the WAL files
+// from it and the environment needed to run it
+func (info InitInfo) restoreObjectStoreBackup(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+) (string, []string, error) {
+	// If we need to download data from a backup, we do it
+	backup, env, err := info.loadBackup(ctx, typedClient, cluster)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
+		return "", nil, err
+	}
+
+	if err := info.restoreDataDir(ctx, cluster, backup, env); err != nil {
+		return "", nil, err
+	}
+
+	restoreCommand, err := getObjectStoreRestoreCommand(backup, cluster)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return restoreCommand, env, nil
+}
+
+// restoreVolumeBackup restores PGDATA from a backup stored in the
+// backup volume of another cluster, returning the restore_command
+// copying the WAL files archived in it
+func (info InitInfo) restoreVolumeBackup(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
+	contextLogger := log.FromContext(ctx)
+	root := postgresSpec.RecoveryBackupVolumeDirectory
+
+	if err := volumebackup.CheckVolumeMounted(root); err != nil {
+		return "", err
+	}
+
+	backups, err := volumebackup.ListBackups(root)
+	if err != nil {
+		return "", fmt.Errorf("while listing the backups in the backup volume: %w", err)
+	}
+
+	targetBackup, err := findTargetBackup(cluster.Spec.Bootstrap.Recovery, newVolumeBackupCatalog(backups))
+	if err != nil {
+		return "", err
+	}
+
+	contextLogger.Info("Restoring the backup from the backup volume",
+		"claimName", cluster.Spec.Bootstrap.Recovery.VolumeBackup.ClaimName,
+		"backup", targetBackup.ID)
+	if err := volumebackup.RestoreBackup(root, targetBackup.ID, info.PgData); err != nil {
+		return "", err
+	}
+	if err := fileutils.EnsurePgDataPerms(info.PgData); err != nil {
+		return "", err
+	}
+	contextLogger.Info("Restore completed")
+
+	return volumebackup.GetRestoreCommand(root), nil
+}
+
+// newVolumeBackupCatalog creates a catalog with the base backups stored
+// in a backup volume, allowing the backup to restore to be chosen like
+// the ones stored in an object store
+func newVolumeBackupCatalog(backups []volumebackup.BackupInfo) *catalog.Catalog {
+	list := make([]catalog.BarmanBackup, len(backups))
+	for idx, backup := range backups {
+		list[idx] = catalog.BarmanBackup{
+			ID:         backup.Name,
+			BackupName: backup.Name,
+			BeginTime:  backup.BeginTime,
+			EndTime:    backup.EndTime,
+			BeginWal:   backup.BeginWal,
+			EndWal:     backup.EndWal,
+			BeginLSN:   backup.BeginLSN,
+			EndLSN:     backup.EndLSN,
+			TimeLine:   backup.TimeLine,
+		}
+	}
+
+	return catalog.NewCatalog(list)
+}
+
 func (info InitInfo) ensureArchiveContainsLastCheckpointRedoWAL(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -374,7 +465,12 @@ func (info InitInfo) restoreCustomWalDir(ctx context.Context) (bool, error) {
 }
 
 // restoreDataDir restores PGDATA from an existing backup
-func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
+func (info InitInfo) restoreDataDir(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	env []string,
+) error {
 	var options []string
 
 	if backup.Status.EndpointURL != "" {
@@ -396,7 +492,11 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 
 	cmd := exec.Command(barmanCapabilities.BarmanCloudRestore, options...) // #nosec G204
 	cmd.Env = env
-	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudRestore)
+	if cluster.Spec.Monitoring.IsBackupProgressMetricsEnabled() {
+		err = info.runTrackingRestoreProgress(ctx, cmd, backup, env)
+	} else {
+		err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudRestore)
+	}
 	if err != nil {
 		log.Error(err, "Can't restore backup")
 		return err
@@ -405,6 +505,58 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 	return nil
 }
 
+// runTrackingRestoreProgress executes barman-cloud-restore exposing its
+// progress as metrics, as the recovery job doesn't run the metrics exporter.
+// The progress is the size of PGDATA over the size of the backup recorded
+// in the barman catalog
+func (info InitInfo) runTrackingRestoreProgress(
+	ctx context.Context,
+	cmd *exec.Cmd,
+	backup *apiv1.Backup,
+	env []string,
+) error {
+	metricsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		if err := progress.ServeMetrics(metricsCtx); err != nil {
+			log.Warning("Cannot expose the progress of the restore", "err", err)
+		}
+	}()
+
+	return progress.Restore.RunMeasuring(
+		cmd,
+		barmanCapabilities.BarmanCloudRestore,
+		progress.DirectorySize(info.PgData),
+		getBackupSize(ctx, backup, env),
+	)
+}
+
+// getBackupSize gets the size of the backup from the barman catalog,
+// or zero if it is not known
+func getBackupSize(ctx context.Context, backup *apiv1.Backup, env []string) int64 {
+	backupInfo, err := barman.GetBackupByName(
+		ctx,
+		backup.Status.BackupID,
+		backup.Status.ServerName,
+		&apiv1.BarmanObjectStoreConfiguration{
+			BarmanCredentials: backup.Status.BarmanCredentials,
+			EndpointCA:        backup.Status.EndpointCA,
+			EndpointURL:       backup.Status.EndpointURL,
+			DestinationPath:   backup.Status.DestinationPath,
+			ServerName:        backup.Status.ServerName,
+		},
+		env,
+	)
+	if err != nil {
+		log.Warning("Cannot get the size of the backup, the progress of the restore will not be reported",
+			"backupID", backup.Status.BackupID, "err", err)
+		return 0
+	}
+
+	return backupInfo.Size
+}
+
 // loadCluster loads the cluster definition from the API server
 func (info InitInfo) loadCluster(ctx context.Context, typedClient client.Client) (*apiv1.Cluster, error) {
 	var cluster apiv1.Cluster
@@ -467,23 +619,11 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 		return nil, nil, err
 	}
 
-	// We are now choosing the right backup to restore
-	var targetBackup *catalog.BarmanBackup
-	if cluster.Spec.Bootstrap.Recovery != nil &&
-		cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
-		targetBackup, err = backupCatalog.FindBackupInfo(cluster.Spec.Bootstrap.Recovery.RecoveryTarget)
-		if err != nil {
-			return nil, nil, err
-		}
-	} else {
-		targetBackup = backupCatalog.LatestBackupInfo()
-	}
-	if targetBackup == nil {
-		return nil, nil, fmt.Errorf("no target backup found")
+	targetBackup, err := findTargetBackup(cluster.Spec.Bootstrap.Recovery, backupCatalog)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	log.Info("Target backup found", "backup", targetBackup)
-
 	return &apiv1.Backup{
 		Spec: apiv1.BackupSpec{
 			Cluster: apiv1.LocalObjectReference{
@@ -511,6 +651,41 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 	}, env, nil
 }
 
+// findTargetBackup chooses the backup to restore between the ones in
+// the passed catalog, as requested in the recovery section
+func findTargetBackup(
+	recovery *apiv1.BootstrapRecovery,
+	backupCatalog *catalog.Catalog,
+) (*catalog.BarmanBackup, error) {
+	if recovery.SkipLatest > 0 {
+		log.Info("Skipping the latest backups", "skipLatest", recovery.SkipLatest)
+		backupCatalog = backupCatalog.WithoutLatest(recovery.SkipLatest)
+	}
+
+	var targetBackup *catalog.BarmanBackup
+	var err error
+	switch {
+	case recovery.GetBackupID() != "":
+		targetBackup, err = backupCatalog.FindBackupFromID(recovery.GetBackupID())
+		if err != nil {
+			return nil, err
+		}
+	case recovery.RecoveryTarget != nil:
+		targetBackup, err = backupCatalog.FindBackupInfo(recovery.RecoveryTarget)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		targetBackup = backupCatalog.LatestBackupInfo()
+	}
+	if targetBackup == nil {
+		return nil, fmt.Errorf("no target backup found")
+	}
+
+	log.Info("Target backup found", "backup", targetBackup)
+	return targetBackup, nil
+}
+
 // loadBackupFromReference loads a backup object and the required credentials given the backup object resource
 func (info InitInfo) loadBackupFromReference(
 	ctx context.Context,
@@ -546,31 +721,26 @@ func (info InitInfo) loadBackupFromReference(
 	return &backup, env, nil
 }
 
-// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
-// to complete the WAL recovery from the object storage and then start
-// as a new primary
-func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
-	var err error
-
-	cmd := []string{barmanCapabilities.BarmanCloudWalRestore}
-	if backup.Status.EndpointURL != "" {
-		cmd = append(cmd, "--endpoint-url", backup.Status.EndpointURL)
-	}
-	cmd = append(cmd, backup.Status.DestinationPath)
-	cmd = append(cmd, backup.Status.ServerName)
-
-	cmd, err = barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
+// getObjectStoreRestoreCommand gets the restore_command downloading
+// the WAL files from the object store containing the passed backup
+func getObjectStoreRestoreCommand(backup *apiv1.Backup, cluster *apiv1.Cluster) (string, error) {
+	cmd, err := barman.CloudWalRestoreCommand(backup, cluster.Spec.Bootstrap.Recovery.WalRestoreMaxBandwidth)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	cmd = append(cmd, "%f", "%p")
+	return strings.Join(cmd, " "), nil
+}
 
+// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
+// to complete the WAL recovery with the passed restore_command and
+// then start as a new primary
+func (info InitInfo) writeRestoreWalConfig(restoreCommand string, cluster *apiv1.Cluster) error {
 	recoveryFileContents := fmt.Sprintf(
 		"recovery_target_action = promote\n"+
 			"restore_command = '%s'\n"+
 			"%s",
-		strings.Join(cmd, " "),
+		restoreCommand,
 		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
 
 	return info.writeRecoveryConfiguration(recoveryFileContents)
@@ -761,7 +931,7 @@ func (info InitInfo) WriteRestoreHbaConf() error {
 	}
 
 	// Create the local map referred in the HBA configuration
-	return WritePostgresUserMaps(info.PgData)
+	return WritePostgresUserMaps(info.PgData, nil)
 }
 
 // ConfigureInstanceAfterRestore changes the superuser password
@@ -856,7 +1026,7 @@ func (info *InitInfo) checkBackupDestination(
 
 	// Instantiate the WALArchiver to get the proper configuration
 	var walArchiver *archiver.WALArchiver
-	walArchiver, err = archiver.New(ctx, cluster, env, walarchive.SpoolDirectory, info.PgData)
+	walArchiver, err = archiver.New(ctx, cluster, env, walarchive.SpoolDirectory, walarchive.PendingDirectory, info.PgData)
 	if err != nil {
 		return fmt.Errorf("while creating the archiver: %w", err)
 	}
@@ -20,11 +20,14 @@ import (
 	"context"
 	"os"
 	"path"
+	"time"
 
 	"github.com/thoas/go-funk"
 	"k8s.io/utils/strings/slices"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/volumebackup"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -148,3 +151,60 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(chg).To(BeFalse())
 	})
 })
+
+var _ = Describe("choosing the backup to restore from a backup volume", func() {
+	now := time.Now()
+	backups := []volumebackup.BackupInfo{
+		{
+			Name:      "backup-2",
+			BeginTime: now.Add(-time.Hour),
+			EndTime:   now.Add(-50 * time.Minute),
+			TimeLine:  1,
+		},
+		{
+			Name:      "backup-1",
+			BeginTime: now.Add(-2 * time.Hour),
+			EndTime:   now.Add(-110 * time.Minute),
+			TimeLine:  1,
+		},
+	}
+
+	It("chooses the latest backup by default", func() {
+		backup, err := findTargetBackup(&apiv1.BootstrapRecovery{}, newVolumeBackupCatalog(backups))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("backup-2"))
+	})
+
+	It("chooses the backup with the passed ID", func() {
+		backup, err := findTargetBackup(
+			&apiv1.BootstrapRecovery{BackupID: "backup-1"},
+			newVolumeBackupCatalog(backups),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("backup-1"))
+	})
+
+	It("skips the latest backups", func() {
+		backup, err := findTargetBackup(&apiv1.BootstrapRecovery{SkipLatest: 1}, newVolumeBackupCatalog(backups))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("backup-1"))
+	})
+
+	It("chooses the backup preceding the target time", func() {
+		backup, err := findTargetBackup(
+			&apiv1.BootstrapRecovery{
+				RecoveryTarget: &apiv1.RecoveryTarget{
+					TargetTime: now.Add(-90 * time.Minute).Format(time.RFC3339),
+				},
+			},
+			newVolumeBackupCatalog(backups),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("backup-1"))
+	})
+
+	It("complains when the volume contains no backup", func() {
+		_, err := findTargetBackup(&apiv1.BootstrapRecovery{}, newVolumeBackupCatalog(nil))
+		Expect(err).To(HaveOccurred())
+	})
+})
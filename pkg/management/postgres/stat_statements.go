@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrStatStatementsNotInstalled is returned when resetting the statistics
+	// of the statements without the pg_stat_statements extension
+	ErrStatStatementsNotInstalled = errors.New(
+		"the pg_stat_statements extension is not installed in the postgres database")
+
+	// ErrStatStatementsResetOnReplica is returned when resetting the
+	// statistics of the statements on an instance that is not the primary
+	ErrStatStatementsResetOnReplica = errors.New(
+		"the statistics of the statements can only be reset on the primary instance")
+)
+
+// statStatementsSchemaQuery finds the schema where the pg_stat_statements
+// extension has been created in the database
+const statStatementsSchemaQuery = `SELECT n.nspname
+FROM pg_catalog.pg_extension e
+JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+WHERE e.extname = 'pg_stat_statements'`
+
+// ResetStatStatements discards the statistics of the statements gathered
+// by pg_stat_statements on the primary instance
+func (instance *Instance) ResetStatStatements() error {
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !isPrimary {
+		return ErrStatStatementsResetOnReplica
+	}
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	return resetStatStatements(superUserDB)
+}
+
+func resetStatStatements(db *sql.DB) error {
+	var schema string
+	err := db.QueryRow(statStatementsSchemaQuery).Scan(&schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrStatStatementsNotInstalled
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(buildStatStatementsResetQuery(schema)); err != nil {
+		return fmt.Errorf("while resetting the statistics of the statements: %w", err)
+	}
+
+	return nil
+}
+
+// buildStatStatementsResetQuery builds the call to the reset function,
+// qualified with the schema of the extension
+func buildStatStatementsResetQuery(schema string) string {
+	return fmt.Sprintf("SELECT %s.pg_stat_statements_reset()", pgx.Identifier{schema}.Sanitize())
+}
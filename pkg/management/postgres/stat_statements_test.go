@@ -0,0 +1,81 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_stat_statements reset", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+	})
+
+	It("resets the statistics when the extension is installed", func() {
+		mock.ExpectQuery(statStatementsSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+		mock.ExpectExec(`SELECT "public".pg_stat_statements_reset()`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(resetStatStatements(db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("fails with a clear error when the extension is not installed", func() {
+		mock.ExpectQuery(statStatementsSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}))
+
+		Expect(resetStatStatements(db)).To(MatchError(ErrStatStatementsNotInstalled))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the errors of the reset function", func() {
+		mock.ExpectQuery(statStatementsSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("monitoring"))
+		mock.ExpectExec(`SELECT "monitoring".pg_stat_statements_reset()`).
+			WillReturnError(errors.New("permission denied"))
+
+		err := resetStatStatements(db)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("permission denied"))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("refuses to reset the statistics on a replica", func() {
+		instance := NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		Expect(createStandbySignal(instance.PgData)).To(Succeed())
+
+		Expect(instance.ResetStatStatements()).To(MatchError(ErrStatStatementsResetOnReplica))
+	})
+})
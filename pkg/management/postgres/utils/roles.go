@@ -25,14 +25,23 @@ import (
 	"github.com/lib/pq"
 )
 
-// DisableSuperuserPassword disables the password for the `postgres` user
-func DisableSuperuserPassword(db *sql.DB) error {
+// IsSuperuserPasswordSet checks if the `postgres` user has a password
+func IsSuperuserPasswordSet(db *sql.DB) (bool, error) {
 	var hasPassword bool
 	passwordCheck := `SELECT rolpassword IS NOT NULL
 		FROM pg_catalog.pg_authid
 		WHERE rolname='postgres'`
 	err := db.QueryRow(passwordCheck).Scan(&hasPassword)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	return hasPassword, nil
+}
+
+// DisableSuperuserPassword disables the password for the `postgres` user
+func DisableSuperuserPassword(db *sql.DB) error {
+	hasPassword, err := IsSuperuserPasswordSet(db)
+	if err != nil {
 		return err
 	}
 	if !hasPassword {
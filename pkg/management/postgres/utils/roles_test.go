@@ -74,6 +74,44 @@ var _ = Describe("Credentials management functions", func() {
 		Expect(DisableSuperuserPassword(db)).To(Succeed())
 	})
 
+	It("disables again the password set for the PostgreSQL user in the meantime", func() {
+		passwordCheck := `SELECT rolpassword IS NOT NULL
+		FROM pg_catalog.pg_authid
+		WHERE rolname='postgres'`
+		expectPasswordRemoval := func() {
+			mock.ExpectQuery(passwordCheck).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(true))
+			mock.ExpectBegin()
+			mock.ExpectExec("ALTER ROLE postgres WITH PASSWORD NULL").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+
+		expectPasswordRemoval()
+		Expect(DisableSuperuserPassword(db)).To(Succeed())
+
+		// the password is still disabled at the next reconciliation
+		mock.ExpectQuery(passwordCheck).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+		Expect(DisableSuperuserPassword(db)).To(Succeed())
+
+		// somebody sets the password again
+		expectPasswordRemoval()
+		Expect(DisableSuperuserPassword(db)).To(Succeed())
+	})
+
+	It("checks if the PostgreSQL user has a password", func() {
+		passwordCheck := `SELECT rolpassword IS NOT NULL
+		FROM pg_catalog.pg_authid
+		WHERE rolname='postgres'`
+		mock.ExpectQuery(passwordCheck).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(true))
+		Expect(IsSuperuserPasswordSet(db)).To(BeTrue())
+
+		mock.ExpectQuery(passwordCheck).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+		Expect(IsSuperuserPasswordSet(db)).To(BeFalse())
+
+		mock.ExpectQuery(passwordCheck).WillReturnRows(sqlmock.NewRows([]string{""}))
+		Expect(IsSuperuserPasswordSet(db)).To(BeFalse())
+	})
+
 	It("can set the password for a PostgreSQL role", func() {
 		mock.ExpectExec("ALTER ROLE \"testuser\" WITH PASSWORD 'testpassword'").
 			WillReturnResult(sqlmock.NewResult(0, 0))
@@ -0,0 +1,363 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/volumebackup"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// volumeBackupApplicationName is the application name used by
+// pg_basebackup while streaming a backup to the backup volume
+const volumeBackupApplicationName = "cnpg-volume-backup"
+
+// VolumeBackupCommand represents a backup, taken with pg_basebackup,
+// that is being streamed to the volume dedicated to the backups
+type VolumeBackupCommand struct {
+	Cluster  *apiv1.Cluster
+	Backup   *apiv1.Backup
+	Client   client.Client
+	Recorder record.EventRecorder
+	Log      log.Logger
+	Instance *Instance
+
+	// The directory where the backup volume is mounted
+	root string
+
+	// runBaseBackup executes pg_basebackup with the passed options
+	runBaseBackup func(options []string) error
+
+	// getWalSegmentSize gets the size of the WAL segments of the instance
+	getWalSegmentSize func() (int64, error)
+}
+
+// NewVolumeBackupCommand initializes a VolumeBackupCommand object
+func NewVolumeBackupCommand(
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	client client.Client,
+	recorder record.EventRecorder,
+	instance *Instance,
+	log log.Logger,
+) *VolumeBackupCommand {
+	return &VolumeBackupCommand{
+		Cluster:  cluster,
+		Backup:   backup,
+		Client:   client,
+		Recorder: recorder,
+		Log:      log,
+		Instance: instance,
+		root:     postgres.BackupVolumeDirectory,
+		runBaseBackup: func(options []string) error {
+			pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
+			return execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
+		},
+		getWalSegmentSize: func() (int64, error) {
+			db, err := instance.GetSuperUserDB()
+			if err != nil {
+				return 0, err
+			}
+
+			var walSegmentSize int64
+			err = db.QueryRow(walSegmentSizeQuery).Scan(&walSegmentSize)
+			return walSegmentSize, err
+		},
+	}
+}
+
+// Start initiates a backup for this instance using pg_basebackup
+func (b *VolumeBackupCommand) Start(ctx context.Context) error {
+	if err := volumebackup.CheckVolumeMounted(b.root); err != nil {
+		return err
+	}
+
+	b.setupBackupStatus()
+
+	err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup)
+	if err != nil {
+		return fmt.Errorf("can't set backup as running: %v", err)
+	}
+
+	if err := ensureWalArchiveIsWorking(b.Instance); err != nil {
+		log.Warning("WAL archiving is not working", "err", err)
+		b.Backup.GetStatus().Phase = apiv1.BackupPhaseWalArchivingFailing
+		return PatchBackupStatusAndRetry(ctx, b.Client, b.Backup)
+	}
+
+	if b.Backup.GetStatus().Phase != apiv1.BackupPhaseRunning {
+		b.Backup.GetStatus().Phase = apiv1.BackupPhaseRunning
+		err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup)
+		if err != nil {
+			log.Error(err, "can't set backup as WAL archiving failing")
+		}
+	}
+
+	// Run the actual backup process
+	go b.run(ctx)
+
+	return nil
+}
+
+// setupBackupStatus configures the backup's status, choosing the directory
+// where the backup will be stored
+func (b *VolumeBackupCommand) setupBackupStatus() {
+	backupStatus := b.Backup.GetStatus()
+
+	backupStatus.BackupName = fmt.Sprintf("backup-%v", utils.ToCompactISO8601(time.Now()))
+	backupStatus.BackupID = backupStatus.BackupName
+	backupStatus.DestinationPath = volumebackup.GetBackupPath(b.root, backupStatus.BackupName)
+	backupStatus.Phase = apiv1.BackupPhaseRunning
+}
+
+// run executes pg_basebackup and updates the status. This method will
+// take long time and is supposed to run inside a dedicated goroutine.
+func (b *VolumeBackupCommand) run(ctx context.Context) {
+	if err := b.takeBackup(ctx); err != nil {
+		backupStatus := b.Backup.GetStatus()
+
+		// record the failure
+		b.Log.Error(err, "Backup failed")
+		b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+		if errors.Is(err, volumebackup.ErrVolumeFull) {
+			b.Recorder.Event(b.Cluster, "Warning", "BackupVolumeFull",
+				"The backup volume is full, please expand it or reduce the retention policy")
+		}
+
+		// update backup status as failed
+		backupStatus.SetAsFailed(err)
+		if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+			b.Log.Error(err, "Can't mark backup as failed")
+			// We do not terminate here because we still want to do the maintenance
+			// activity on the backups and to set the condition on the cluster.
+		}
+
+		// add backup failed condition to the cluster
+		if failErr := retryWithRefreshedCluster(ctx, b.Client, b.Cluster, func() error {
+			origCluster := b.Cluster.DeepCopy()
+
+			meta.SetStatusCondition(&b.Cluster.Status.Conditions, *apiv1.BuildClusterBackupFailedCondition(err))
+
+			b.Cluster.Status.LastFailedBackup = utils.GetCurrentTimestampWithFormat(time.RFC3339)
+			return b.Client.Status().Patch(ctx, b.Cluster, client.MergeFrom(origCluster))
+		}); failErr != nil {
+			b.Log.Error(failErr, "while setting cluster condition for failed backup")
+		}
+	}
+
+	b.backupMaintenance(ctx)
+}
+
+func (b *VolumeBackupCommand) takeBackup(ctx context.Context) error {
+	backupStatus := b.Backup.GetStatus()
+	backupName := backupStatus.BackupName
+	backupPath := volumebackup.GetBackupPath(b.root, backupName)
+
+	// record the backup beginning
+	b.Log.Info("Backup started", "path", backupPath)
+	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+
+	// Update backup status in cluster conditions on startup
+	if err := retryWithRefreshedCluster(ctx, b.Client, b.Cluster, func() error {
+		return conditions.Patch(ctx, b.Client, b.Cluster, apiv1.BackupStartingCondition)
+	}); err != nil {
+		b.Log.Error(err, "Error changing backup condition (backup started)")
+		// We do not terminate here because we could still have a good backup
+		// even if we are unable to communicate with the Kubernetes API server
+	}
+
+	if err := fileutils.EnsureDirectoryExists(backupPath); err != nil {
+		return volumebackup.CheckVolumeFull(b.root, fmt.Errorf("while creating the backup directory: %w", err))
+	}
+
+	beginTime := time.Now()
+	if err := b.runBaseBackup(b.getBaseBackupOptions(backupPath)); err != nil {
+		err = volumebackup.CheckVolumeFull(b.root, fmt.Errorf("error in pg_basebackup: %w", err))
+		// A partial backup is useless and takes space
+		if removeErr := volumebackup.RemoveBackup(b.root, backupName); removeErr != nil {
+			b.Log.Error(removeErr, "while removing the partial backup", "path", backupPath)
+		}
+		return err
+	}
+
+	info := volumebackup.BackupInfo{
+		Name:      backupName,
+		BeginTime: beginTime,
+		EndTime:   time.Now(),
+	}
+	if err := b.loadWALRange(&info); err != nil {
+		// The backup can still be restored, but the WAL files it
+		// needs won't be protected from the retention policy
+		b.Log.Warning("Cannot read the WAL range of the backup", "err", err)
+	}
+
+	if err := volumebackup.WriteBackupInfo(b.root, info); err != nil {
+		if removeErr := volumebackup.RemoveBackup(b.root, backupName); removeErr != nil {
+			b.Log.Error(removeErr, "while removing the partial backup", "path", backupPath)
+		}
+		return fmt.Errorf("while writing the backup metadata: %w", err)
+	}
+
+	b.Log.Info("Backup completed")
+	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+
+	// Set the status to completed
+	backupStatus.SetAsCompleted()
+	backupStatus.StartedAt = &metav1.Time{Time: info.BeginTime}
+	backupStatus.StoppedAt = &metav1.Time{Time: info.EndTime}
+	backupStatus.BeginWal = info.BeginWal
+	backupStatus.EndWal = info.EndWal
+	backupStatus.BeginLSN = info.BeginLSN
+	backupStatus.EndLSN = info.EndLSN
+
+	if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set backup status as completed")
+	}
+
+	// Update backup status in cluster conditions on backup completion
+	if err := retryWithRefreshedCluster(ctx, b.Client, b.Cluster, func() error {
+		return conditions.Patch(ctx, b.Client, b.Cluster, apiv1.BackupSucceededCondition)
+	}); err != nil {
+		b.Log.Error(err, "Can't update the cluster with the completed backup data")
+	}
+
+	return nil
+}
+
+// getBaseBackupOptions gets the options of pg_basebackup to stream a
+// compressed backup, including the WAL files needed to restore it,
+// into the passed directory. The streaming replication user is used, as
+// the backup can be taken from a standby too
+func (b *VolumeBackupCommand) getBaseBackupOptions(backupPath string) []string {
+	connectionString := withSSLNegotiation(
+		buildPrimaryConnInfo("localhost", volumeBackupApplicationName)+" dbname=postgres",
+		b.Cluster)
+
+	return []string{
+		"-D", backupPath,
+		"--format=tar",
+		"--gzip",
+		"--wal-method=stream",
+		"--label", b.Backup.Status.BackupName,
+		"-v",
+		"-w",
+		"-d", connectionString,
+	}
+}
+
+// loadWALRange fills the WAL files needed to restore the backup
+func (b *VolumeBackupCommand) loadWALRange(info *volumebackup.BackupInfo) error {
+	walSegmentSize, err := b.getWalSegmentSize()
+	if err != nil {
+		return fmt.Errorf("while getting the size of the WAL segments: %w", err)
+	}
+
+	return info.LoadWALRange(b.root, walSegmentSize)
+}
+
+func (b *VolumeBackupCommand) backupMaintenance(ctx context.Context) {
+	// Delete backups per policy
+	if b.Cluster.Spec.Backup.RetentionPolicy != "" {
+		b.Log.Info("Applying backup retention policy",
+			"retentionPolicy", b.Cluster.Spec.Backup.RetentionPolicy)
+		removed, err := volumebackup.ApplyRetentionPolicy(b.root, b.Cluster.Spec.Backup.RetentionPolicy, time.Now())
+		if err != nil {
+			b.Log.Error(err, "while applying the retention policy")
+			b.Recorder.Event(b.Cluster, "Warning", "RetentionPolicyFailed", "Retention policy failed")
+			// We do not want to return here, we must go on to set the first recoverability point
+		}
+		if len(removed) > 0 {
+			b.Log.Info("Removed obsolete backups", "backups", removed)
+		}
+	}
+
+	backups, err := volumebackup.ListBackups(b.root)
+	if err != nil {
+		b.Log.Error(err, "while listing the backups in the backup volume")
+		return
+	}
+
+	if err := b.deleteBackupsNotInVolume(ctx, backups); err != nil {
+		b.Log.Error(err, "while deleting Backups not present in the backup volume")
+	}
+
+	if len(backups) == 0 {
+		return
+	}
+
+	if err := retryWithRefreshedCluster(ctx, b.Client, b.Cluster, func() error {
+		origCluster := b.Cluster.DeepCopy()
+
+		b.Cluster.Status.FirstRecoverabilityPoint = backups[0].EndTime.Format(time.RFC3339)
+		b.Cluster.Status.LastSuccessfulBackup = backups[len(backups)-1].EndTime.Format(time.RFC3339)
+
+		return b.Client.Status().Patch(ctx, b.Cluster, client.MergeFrom(origCluster))
+	}); err != nil {
+		b.Log.Error(err, "while setting the firstRecoverabilityPoint and latestSuccessfulBackup")
+	}
+}
+
+// deleteBackupsNotInVolume deletes the completed Backup objects of the
+// cluster whose content has been removed from the backup volume
+func (b *VolumeBackupCommand) deleteBackupsNotInVolume(
+	ctx context.Context,
+	backups []volumebackup.BackupInfo,
+) error {
+	var backupList apiv1.BackupList
+	if err := b.Client.List(ctx, &backupList, client.InNamespace(b.Cluster.Namespace)); err != nil {
+		return fmt.Errorf("while getting backups: %w", err)
+	}
+
+	var errs []error
+	for idx := range backupList.Items {
+		backup := &backupList.Items[idx]
+		if backup.Spec.Cluster.Name != b.Cluster.Name ||
+			backup.Status.Method != apiv1.BackupMethodVolumeBackup ||
+			backup.Status.Phase != apiv1.BackupPhaseCompleted {
+			continue
+		}
+
+		if slices.ContainsFunc(backups, func(info volumebackup.BackupInfo) bool {
+			return info.Name == backup.Status.BackupID
+		}) {
+			continue
+		}
+
+		if err := b.Client.Delete(ctx, backup); err != nil {
+			errs = append(errs, fmt.Errorf("while deleting backup %s/%s: %w", backup.Namespace, backup.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
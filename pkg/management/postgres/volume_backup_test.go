@@ -0,0 +1,197 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/volumebackup"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeBaseBackup emulates pg_basebackup, writing a backup and its
+// manifest in the target directory
+func fakeBaseBackup(options []string) error {
+	targetDirectory := options[1]
+	if err := os.WriteFile(filepath.Join(targetDirectory, "base.tar.gz"), []byte("data"), 0o600); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(targetDirectory, "backup_manifest"), []byte(`{
+  "WAL-Ranges": [
+    { "Timeline": 1, "Start-LSN": "0/9000028", "End-LSN": "0/9000138" }
+  ]
+}`), 0o600)
+}
+
+var _ = Describe("volume backup command", func() {
+	const namespace = "test"
+
+	var (
+		cluster       *apiv1.Cluster
+		backup        *apiv1.Backup
+		backupCommand *VolumeBackupCommand
+		cli           client.Client
+		root          string
+	)
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					VolumeBackup: &apiv1.VolumeBackupConfiguration{
+						Storage: apiv1.StorageConfiguration{Size: "10Gi"},
+					},
+					RetentionPolicy: "7d",
+				},
+			},
+		}
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: namespace},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "test-cluster"},
+				Method:  apiv1.BackupMethodVolumeBackup,
+			},
+			Status: apiv1.BackupStatus{Method: apiv1.BackupMethodVolumeBackup},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, backup).
+			WithStatusSubresource(cluster, backup).
+			Build()
+		backupCommand = &VolumeBackupCommand{
+			Cluster:           cluster,
+			Backup:            backup,
+			Client:            cli,
+			Recorder:          record.NewFakeRecorder(10),
+			Log:               log.FromContext(context.Background()),
+			Instance:          &Instance{},
+			root:              root,
+			runBaseBackup:     fakeBaseBackup,
+			getWalSegmentSize: func() (int64, error) { return 1 << 24, nil },
+		}
+		backupCommand.setupBackupStatus()
+	})
+
+	It("streams the backup to the backup volume", func(ctx SpecContext) {
+		options := backupCommand.getBaseBackupOptions(backup.Status.DestinationPath)
+		Expect(options).To(ContainElements("--format=tar", "--wal-method=stream"))
+		Expect(options[1]).To(Equal(volumebackup.GetBackupPath(root, backup.Status.BackupName)))
+
+		backupCommand.run(ctx)
+
+		var storedBackup apiv1.Backup
+		Expect(cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "test-backup"}, &storedBackup)).
+			To(Succeed())
+		Expect(storedBackup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseCompleted))
+		Expect(storedBackup.Status.BackupID).To(Equal(backup.Status.BackupName))
+		Expect(storedBackup.Status.BeginWal).To(Equal("000000010000000000000009"))
+		Expect(storedBackup.Status.BeginLSN).To(Equal("0/9000028"))
+		Expect(storedBackup.Status.EndLSN).To(Equal("0/9000138"))
+
+		backups, err := volumebackup.ListBackups(root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(1))
+		Expect(backups[0].Name).To(Equal(backup.Status.BackupName))
+
+		Expect(cluster.Status.FirstRecoverabilityPoint).ToNot(BeEmpty())
+		Expect(cluster.Status.LastSuccessfulBackup).ToNot(BeEmpty())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBackup))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("removes the partial backup when pg_basebackup fails", func(ctx SpecContext) {
+		backupCommand.runBaseBackup = func(options []string) error {
+			Expect(os.WriteFile(filepath.Join(options[1], "base.tar.gz"), []byte("dat"), 0o600)).To(Succeed())
+			return errors.New("connection lost")
+		}
+
+		backupCommand.run(ctx)
+
+		Expect(backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseFailed))
+		Expect(backup.Status.Error).To(ContainSubstring("connection lost"))
+		Expect(volumebackup.GetBackupPath(root, backup.Status.BackupName)).ToNot(BeADirectory())
+		Expect(cluster.Status.LastFailedBackup).ToNot(BeEmpty())
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBackup))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonLastBackupFailed)))
+	})
+
+	It("prunes the obsolete backups and their Backup objects", func(ctx SpecContext) {
+		obsoleteBackup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "obsolete-backup", Namespace: namespace},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "test-cluster"},
+				Method:  apiv1.BackupMethodVolumeBackup,
+			},
+		}
+		Expect(cli.Create(ctx, obsoleteBackup)).To(Succeed())
+		obsoleteBackup.Status = apiv1.BackupStatus{
+			Method:   apiv1.BackupMethodVolumeBackup,
+			Phase:    apiv1.BackupPhaseCompleted,
+			BackupID: "backup-old",
+		}
+		Expect(cli.Status().Update(ctx, obsoleteBackup)).To(Succeed())
+
+		// The most recent backup before the recovery window is still
+		// needed, while the older ones are obsolete
+		for name, age := range map[string]int{"backup-old": 30, "backup-needed": 20} {
+			Expect(os.MkdirAll(volumebackup.GetBackupPath(root, name), 0o700)).To(Succeed())
+			Expect(volumebackup.WriteBackupInfo(root, volumebackup.BackupInfo{
+				Name:      name,
+				BeginTime: time.Now().AddDate(0, 0, -age),
+				EndTime:   time.Now().AddDate(0, 0, -age),
+			})).To(Succeed())
+		}
+
+		backupCommand.run(ctx)
+
+		backups, err := volumebackup.ListBackups(root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(2))
+		Expect(backups[0].Name).To(Equal("backup-needed"))
+		Expect(backups[1].Name).To(Equal(backup.Status.BackupName))
+		Expect(cluster.Status.FirstRecoverabilityPoint).To(
+			Equal(backups[0].EndTime.Format(time.RFC3339)))
+
+		var backupList apiv1.BackupList
+		Expect(cli.List(ctx, &backupList)).To(Succeed())
+		Expect(backupList.Items).To(HaveLen(1))
+		Expect(backupList.Items[0].Name).To(Equal("test-backup"))
+	})
+})
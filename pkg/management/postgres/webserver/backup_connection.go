@@ -23,6 +23,8 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sync"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -36,6 +38,8 @@ type BackupResultData struct {
 	EndLSN     postgresUtils.LSN     `json:"endLSN,omitempty"`
 	LabelFile  []byte                `json:"labelFile,omitempty"`
 	SpcmapFile []byte                `json:"spcmapFile,omitempty"`
+	BeginWal   string                `json:"beginWal,omitempty"`
+	EndWal     string                `json:"endWal,omitempty"`
 	BackupName string                `json:"backupName,omitempty"`
 	Phase      BackupConnectionPhase `json:"phase,omitempty"`
 }
@@ -80,6 +84,14 @@ func newBackupError(phase BackupConnectionPhase, err error) *backupError {
 	return &backupError{phase: phase, err: err}
 }
 
+// errHoldTimeoutExpired is raised when the backup is aborted because the
+// caller didn't ask to stop it within the hold timeout
+var errHoldTimeoutExpired = errors.New("the backup was not stopped within the hold timeout and has been aborted")
+
+// beginWalRegex extracts the first WAL file of the backup from the content
+// of the backup_label file
+var beginWalRegex = regexp.MustCompile(`(?m)^START WAL LOCATION: \S+ \(file ([0-9A-F]{24})\)$`)
+
 // replicationSlotInvalidCharacters matches every character that is
 // not valid in a replication slot name
 var replicationSlotInvalidCharacters = regexp.MustCompile(`[^a-z0-9_]`)
@@ -87,10 +99,17 @@ var replicationSlotInvalidCharacters = regexp.MustCompile(`[^a-z0-9_]`)
 type backupConnection struct {
 	immediateCheckpoint  bool
 	waitForArchive       bool
+	holdTimeout          time.Duration
 	conn                 *sql.Conn
 	postgresMajorVersion uint64
-	data                 BackupResultData
-	err                  *backupError
+
+	// mu protects the fields below, which are changed by the goroutines
+	// starting and stopping the backup, by the requests of the operator
+	// and by the hold timeout
+	mu        sync.Mutex
+	startedAt time.Time
+	data      BackupResultData
+	err       *backupError
 }
 
 func newBackupConnection(
@@ -99,6 +118,7 @@ func newBackupConnection(
 	backupName string,
 	immediateCheckpoint bool,
 	waitForArchive bool,
+	holdTimeout time.Duration,
 ) (*backupConnection, error) {
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
@@ -119,6 +139,7 @@ func newBackupConnection(
 	return &backupConnection{
 		immediateCheckpoint:  immediateCheckpoint,
 		waitForArchive:       waitForArchive,
+		holdTimeout:          holdTimeout,
 		conn:                 conn,
 		postgresMajorVersion: vers.Major,
 		data: BackupResultData{
@@ -135,21 +156,32 @@ func (bc *backupConnection) startBackup(ctx context.Context) {
 		return
 	}
 
-	defer func() {
-		if bc.err == nil {
-			return
-		}
-		bc.data.Phase = Failed
+	beginLSN, err := bc.executeBackupStart(ctx)
 
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	// the backup may have been replaced by a new one in the meantime
+	if bc.data.Phase == Failed {
+		return
+	}
+
+	if err != nil {
+		bc.err = newBackupError(bc.data.Phase, err)
+		bc.data.Phase = Failed
 		contextLogger.Error(bc.err, "encountered error while starting backup")
+		bc.closeConnection(ctx)
+		return
+	}
 
-		if err := bc.conn.Close(); err != nil {
-			if !errors.Is(err, sql.ErrConnDone) {
-				contextLogger.Error(err, "while closing backup connection")
-			}
-		}
-	}()
+	bc.data.BeginLSN = beginLSN
+	bc.startedAt = time.Now()
+	bc.data.Phase = Started
+}
 
+// executeBackupStart creates the temporary replication slot keeping the
+// WAL files of the backup and starts it, returning its begin LSN
+func (bc *backupConnection) executeBackupStart(ctx context.Context) (postgresUtils.LSN, error) {
 	// TODO: refactor with the same logic of GetSlotNameFromInstanceName in the api package
 	slotName := replicationSlotInvalidCharacters.ReplaceAllString(bc.data.BackupName, "_")
 	if _, err := bc.conn.ExecContext(
@@ -157,8 +189,7 @@ func (bc *backupConnection) startBackup(ctx context.Context) {
 		"SELECT pg_create_physical_replication_slot(slot_name => $1, immediately_reserve => true, temporary => true)",
 		slotName,
 	); err != nil {
-		bc.err = newBackupError(bc.data.Phase, bc.err)
-		return
+		return "", err
 	}
 
 	var row *sql.Row
@@ -170,10 +201,9 @@ func (bc *backupConnection) startBackup(ctx context.Context) {
 			bc.immediateCheckpoint)
 	}
 
-	if err := row.Scan(&bc.data.BeginLSN); err != nil {
-		bc.err = newBackupError(bc.data.Phase, err)
-	}
-	bc.data.Phase = Started
+	var beginLSN postgresUtils.LSN
+	err := row.Scan(&beginLSN)
+	return beginLSN, err
 }
 
 func (bc *backupConnection) stopBackup(ctx context.Context) {
@@ -183,36 +213,157 @@ func (bc *backupConnection) stopBackup(ctx context.Context) {
 		return
 	}
 
-	defer func() {
-		if err := bc.conn.Close(); err != nil {
-			if !errors.Is(err, sql.ErrConnDone) {
-				contextLogger.Error(err, "while closing backup connection")
-			}
-		}
+	var result BackupResultData
+	err := bc.executeBackupStop(ctx, &result)
 
-		if bc.err != nil {
-			bc.data.Phase = Failed
-		}
-	}()
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.closeConnection(ctx)
 
-	if bc.err != nil {
+	// the backup may have been replaced by a new one in the meantime
+	if bc.data.Phase == Failed {
 		return
 	}
 
+	if err != nil {
+		bc.err = newBackupError(bc.data.Phase, err)
+		bc.data.Phase = Failed
+		contextLogger.Error(err, "while stopping PostgreSQL physical backup")
+		return
+	}
+
+	bc.data.EndLSN = result.EndLSN
+	bc.data.LabelFile = result.LabelFile
+	bc.data.SpcmapFile = result.SpcmapFile
+	bc.data.EndWal = result.EndWal
+	if matches := beginWalRegex.FindSubmatch(bc.data.LabelFile); matches != nil {
+		bc.data.BeginWal = string(matches[1])
+	}
+	bc.data.Phase = Completed
+}
+
+// executeBackupStop stops the backup, filling the result with its end LSN,
+// the content of the backup_label and tablespace_map files and the last
+// WAL file
+func (bc *backupConnection) executeBackupStop(ctx context.Context, result *BackupResultData) error {
+	// the name of the WAL files can't be computed on a standby
+	const endWalColumn = "CASE WHEN pg_is_in_recovery() THEN NULL ELSE pg_walfile_name(lsn) END"
+
 	var row *sql.Row
 	if bc.postgresMajorVersion < 15 {
 		row = bc.conn.QueryRowContext(ctx,
-			"SELECT lsn, labelfile, spcmapfile FROM pg_stop_backup(false, $1);", bc.waitForArchive)
+			"SELECT lsn, labelfile, spcmapfile, "+endWalColumn+" FROM pg_stop_backup(false, $1);",
+			bc.waitForArchive)
 	} else {
 		row = bc.conn.QueryRowContext(ctx,
-			"SELECT lsn, labelfile, spcmapfile FROM pg_backup_stop(wait_for_archive => $1);", bc.waitForArchive)
+			"SELECT lsn, labelfile, spcmapfile, "+endWalColumn+" FROM pg_backup_stop(wait_for_archive => $1);",
+			bc.waitForArchive)
 	}
 
-	if err := row.Scan(&bc.data.EndLSN, &bc.data.LabelFile, &bc.data.SpcmapFile); err != nil {
-		bc.err = newBackupError(bc.data.Phase, err)
-		contextLogger.Error(err, "while stopping PostgreSQL physical backup")
+	var endWal sql.NullString
+	if err := row.Scan(&result.EndLSN, &result.LabelFile, &result.SpcmapFile, &endWal); err != nil {
+		return err
+	}
+	result.EndWal = endWal.String
+	return nil
+}
+
+// getStatus gets the result of the backup and its error, if any
+func (bc *backupConnection) getStatus() (BackupResultData, *backupError) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	return bc.data, bc.err
+}
+
+// requestStop moves a started backup to the closing phase, telling if
+// it should be stopped. Backups already closing are left untouched, the
+// ones that failed are released, and the ones not started are refused
+func (bc *backupConnection) requestStop(ctx context.Context) (bool, error) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	switch {
+	case bc.data.Phase == Closing:
+		return false, nil
+
+	case bc.data.Phase != Started:
+		return false, fmt.Errorf("phase is: %s", bc.data.Phase)
+
+	case bc.err != nil:
+		bc.closeConnection(ctx)
+		bc.data.Phase = Failed
+		return false, nil
+	}
+
+	bc.data.Phase = Closing
+	return true, nil
+}
+
+// replace marks the backup as failed, as it is being replaced by a new one,
+// and closes its connection
+func (bc *backupConnection) replace(ctx context.Context) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.data.Phase = Failed
+	bc.closeConnection(ctx)
+}
+
+// closeConnection closes the backup connection. It must be called with
+// the mutex held
+func (bc *backupConnection) closeConnection(ctx context.Context) {
+	if err := bc.conn.Close(); err != nil {
+		if !errors.Is(err, sql.ErrConnDone) {
+			log.FromContext(ctx).Error(err, "while closing backup connection")
+		}
+	}
+}
+
+// isHoldTimeoutExpired tells whether the backup has been started for
+// longer than the hold timeout, without being asked to stop
+func (bc *backupConnection) isHoldTimeoutExpired() bool {
+	if bc == nil {
+		return false
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	return bc.isHoldTimeoutExpiredLocked()
+}
+
+// isHoldTimeoutExpiredLocked is isHoldTimeoutExpired, to be called with the
+// mutex held
+func (bc *backupConnection) isHoldTimeoutExpiredLocked() bool {
+	if bc.holdTimeout == 0 || bc.err != nil || bc.data.Phase != Started {
+		return false
+	}
+
+	return time.Since(bc.startedAt) > bc.holdTimeout
+}
+
+// abortBackup closes the backup connection, making PostgreSQL abort the
+// backup and drop the temporary replication slot, and marks it as failed.
+// The backup is aborted only if the hold timeout is still expired, as it
+// may have been asked to stop in the meantime
+func (bc *backupConnection) abortBackup(ctx context.Context, err error) {
+	contextLogger := log.FromContext(ctx).WithValues("step", "abort")
+
+	if bc == nil {
 		return
 	}
 
-	bc.data.Phase = Completed
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if !bc.isHoldTimeoutExpiredLocked() {
+		return
+	}
+
+	contextLogger.Warning("Aborting the backup", "backupName", bc.data.BackupName, "reason", err.Error())
+	bc.err = newBackupError(bc.data.Phase, err)
+	bc.data.Phase = Failed
+	bc.closeConnection(ctx)
 }
@@ -0,0 +1,181 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup connection", func() {
+	const labelFile = "START WAL LOCATION: 0/2000028 (file 000000010000000000000002)\n" +
+		"CHECKPOINT LOCATION: 0/2000060\n" +
+		"BACKUP METHOD: streamed\n" +
+		"BACKUP FROM: primary\n"
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		bc   *backupConnection
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		conn, err := db.Conn(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		bc = &backupConnection{
+			immediateCheckpoint:  true,
+			waitForArchive:       true,
+			holdTimeout:          time.Hour,
+			conn:                 conn,
+			postgresMajorVersion: 16,
+			data: BackupResultData{
+				BackupName: "snapshot-backup",
+				Phase:      Starting,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		mock.ExpectClose()
+		Expect(db.Close()).To(Succeed())
+	})
+
+	expectStart := func() {
+		mock.ExpectExec("SELECT pg_create_physical_replication_slot(slot_name => $1, " +
+			"immediately_reserve => true, temporary => true)").
+			WithArgs("snapshot_backup").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT pg_backup_start(label => $1, fast => $2);").
+			WithArgs("snapshot-backup", true).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_backup_start"}).AddRow("0/2000028"))
+	}
+
+	It("starts the backup, holds it and stops it returning the label and the WAL files", func(ctx context.Context) {
+		expectStart()
+		bc.startBackup(ctx)
+		Expect(bc.err).To(BeNil())
+		Expect(bc.data.Phase).To(Equal(Started))
+		Expect(string(bc.data.BeginLSN)).To(Equal("0/2000028"))
+
+		// the backup is held until the caller asks to stop it
+		Expect(bc.isHoldTimeoutExpired()).To(BeFalse())
+
+		mock.ExpectQuery("SELECT lsn, labelfile, spcmapfile, " +
+			"CASE WHEN pg_is_in_recovery() THEN NULL ELSE pg_walfile_name(lsn) END " +
+			"FROM pg_backup_stop(wait_for_archive => $1);").
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows([]string{"lsn", "labelfile", "spcmapfile", "pg_walfile_name"}).
+				AddRow("0/2000100", []byte(labelFile), []byte(""), "000000010000000000000002"))
+		bc.data.Phase = Closing
+		bc.stopBackup(ctx)
+		Expect(bc.err).To(BeNil())
+		Expect(bc.data.Phase).To(Equal(Completed))
+		Expect(string(bc.data.EndLSN)).To(Equal("0/2000100"))
+		Expect(string(bc.data.LabelFile)).To(Equal(labelFile))
+		Expect(bc.data.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(bc.data.EndWal).To(Equal("000000010000000000000002"))
+		Expect(bc.isHoldTimeoutExpired()).To(BeFalse())
+	})
+
+	It("doesn't report the end WAL file when the backup is taken from a standby", func(ctx context.Context) {
+		expectStart()
+		bc.startBackup(ctx)
+
+		mock.ExpectQuery("SELECT lsn, labelfile, spcmapfile, " +
+			"CASE WHEN pg_is_in_recovery() THEN NULL ELSE pg_walfile_name(lsn) END " +
+			"FROM pg_backup_stop(wait_for_archive => $1);").
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows([]string{"lsn", "labelfile", "spcmapfile", "pg_walfile_name"}).
+				AddRow("0/2000100", []byte(labelFile), []byte(""), nil))
+		bc.stopBackup(ctx)
+		Expect(bc.data.Phase).To(Equal(Completed))
+		Expect(bc.data.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(bc.data.EndWal).To(BeEmpty())
+	})
+
+	It("aborts the backup when it is not stopped within the hold timeout", func(ctx context.Context) {
+		expectStart()
+		bc.startBackup(ctx)
+		Expect(bc.data.Phase).To(Equal(Started))
+
+		bc.startedAt = time.Now().Add(-2 * time.Hour)
+		Expect(bc.isHoldTimeoutExpired()).To(BeTrue())
+
+		bc.abortBackup(ctx, errHoldTimeoutExpired)
+		Expect(bc.data.Phase).To(Equal(Failed))
+		Expect(bc.err).To(HaveOccurred())
+		Expect(bc.err.Error()).To(ContainSubstring("hold timeout"))
+		Expect(bc.isHoldTimeoutExpired()).To(BeFalse())
+
+		// the connection is closed, aborting the backup in PostgreSQL
+		_, err := bc.conn.ExecContext(ctx, "SELECT 1")
+		Expect(err).To(MatchError(sql.ErrConnDone))
+	})
+
+	It("doesn't abort the backups asked to stop after the hold timeout expired", func(ctx context.Context) {
+		expectStart()
+		bc.startBackup(ctx)
+		bc.startedAt = time.Now().Add(-2 * time.Hour)
+		Expect(bc.isHoldTimeoutExpired()).To(BeTrue())
+
+		shouldStop, err := bc.requestStop(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shouldStop).To(BeTrue())
+
+		bc.abortBackup(ctx, errHoldTimeoutExpired)
+		data, backupErr := bc.getStatus()
+		Expect(backupErr).To(BeNil())
+		Expect(data.Phase).To(Equal(Closing))
+	})
+
+	It("refuses to stop the backups that have not been started", func(ctx context.Context) {
+		shouldStop, err := bc.requestStop(ctx)
+		Expect(err).To(MatchError("phase is: starting"))
+		Expect(shouldStop).To(BeFalse())
+	})
+
+	It("doesn't report the errors of the backups replaced in the meantime", func(ctx context.Context) {
+		// the connection of the replaced backup is closed, failing the start
+		bc.replace(ctx)
+		bc.startBackup(ctx)
+
+		data, backupErr := bc.getStatus()
+		Expect(backupErr).To(BeNil())
+		Expect(data.Phase).To(Equal(Failed))
+		Expect(data.BeginLSN).To(BeEmpty())
+	})
+
+	It("never aborts the backups without a hold timeout", func(ctx context.Context) {
+		expectStart()
+		bc.holdTimeout = 0
+		bc.startBackup(ctx)
+		bc.startedAt = time.Now().Add(-24 * time.Hour)
+		Expect(bc.isHoldTimeoutExpired()).To(BeFalse())
+	})
+})
@@ -29,6 +29,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/drain"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -61,6 +62,7 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPgPreStop, endpoints.preStop)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -81,8 +83,8 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	log.Debug("Cached object request received")
 
 	var js []byte
-	switch requestedObject {
-	case cache.ClusterKey:
+	switch {
+	case requestedObject == cache.ClusterKey:
 		response, err := cache.LoadClusterUnsafe()
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
@@ -99,7 +101,7 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-	case cache.WALRestoreKey, cache.WALArchiveKey:
+	case cache.IsEnvKey(requestedObject):
 		response, err := cache.LoadEnv(requestedObject)
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
@@ -161,15 +163,20 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		return
 	}
 
+	backupLog := log.WithValues(
+		"backupName", backup.Name,
+		"backupNamespace", backup.Name)
+
+	if backup.Spec.Method == apiv1.BackupMethodVolumeBackup {
+		ws.startVolumeBackup(ctx, w, &cluster, &backup, backupLog)
+		return
+	}
+
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 		http.Error(w, "Backup not configured in the cluster", http.StatusConflict)
 		return
 	}
 
-	backupLog := log.WithValues(
-		"backupName", backup.Name,
-		"backupNamespace", backup.Name)
-
 	backupCommand, err := postgres.NewBackupCommand(
 		&cluster,
 		&backup,
@@ -196,3 +203,55 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 
 	_, _ = fmt.Fprint(w, "OK")
 }
+
+// startVolumeBackup starts a backup streamed to the backup volume
+func (ws *localWebserverEndpoints) startVolumeBackup(
+	ctx context.Context,
+	w http.ResponseWriter,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	backupLog log.Logger,
+) {
+	if !cluster.ShouldCreateBackupVolume() {
+		http.Error(w, "Backup volume not configured in the cluster", http.StatusConflict)
+		return
+	}
+
+	backupCommand := postgres.NewVolumeBackupCommand(
+		cluster,
+		backup,
+		ws.typedClient,
+		ws.eventRecorder,
+		ws.instance,
+		backupLog,
+	)
+	if err := backupCommand.Start(ctx); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while starting backup: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// This function coordinates the shutdown of the instance, and is invoked
+// by the preStop hook of the Pod. When the Pod of the primary is being
+// drained, it blocks until the switchover is complete
+func (ws *localWebserverEndpoints) preStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := drain.NewCoordinator(ws.instance, ws.typedClient).Run(r.Context()); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while coordinating the shutdown: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
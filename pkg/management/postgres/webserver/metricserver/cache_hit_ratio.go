@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// databaseBlocksQuery reads, for each database, the number of blocks
+// found in the buffer cache and the number of blocks read from disk.
+// The row with a NULL name, related to the shared objects, is skipped
+const databaseBlocksQuery = `SELECT datname, blks_hit, blks_read
+FROM pg_catalog.pg_stat_database
+WHERE datname IS NOT NULL`
+
+// databaseBlocks is the block access statistics of a database
+type databaseBlocks struct {
+	database string
+	hit      float64
+	read     float64
+}
+
+// cacheHitRatio returns the fraction of the blocks accesses that were
+// satisfied by the buffer cache. The ratio is not defined when the
+// database has had no block access
+func (b databaseBlocks) cacheHitRatio() (float64, bool) {
+	total := b.hit + b.read
+	if total <= 0 {
+		return 0, false
+	}
+
+	return b.hit / total, true
+}
+
+// getDatabaseBlocks reads the block access statistics of the databases
+func getDatabaseBlocks(db *sql.DB) ([]databaseBlocks, error) {
+	rows, err := db.Query(databaseBlocksQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getDatabaseBlocks")
+		}
+	}()
+
+	var result []databaseBlocks
+	for rows.Next() {
+		var item databaseBlocks
+		if err := rows.Scan(&item.database, &item.hit, &item.read); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func collectPGCacheHitRatio(e *Exporter, db *sql.DB) error {
+	blocks, err := getDatabaseBlocks(db)
+	if err != nil {
+		return err
+	}
+
+	return reportExistingDatabases(func() error {
+		for _, item := range blocks {
+			if ratio, ok := item.cacheHitRatio(); ok {
+				e.Metrics.CacheHitRatio.WithLabelValues(item.database).Set(ratio)
+			}
+		}
+		return nil
+	}, e.Metrics.CacheHitRatio)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cache hit ratio metrics", func() {
+	blocksColumns := []string{"datname", "blks_hit", "blks_read"}
+
+	DescribeTable("computes the cache hit ratio",
+		func(hit, read float64, expectedRatio float64, expectedOK bool) {
+			ratio, ok := databaseBlocks{database: "app", hit: hit, read: read}.cacheHitRatio()
+			Expect(ok).To(Equal(expectedOK))
+			Expect(ratio).To(BeNumerically("~", expectedRatio, 1e-9))
+		},
+		Entry("when every block was found in the cache", 100.0, 0.0, 1.0, true),
+		Entry("when every block was read from disk", 0.0, 100.0, 0.0, true),
+		Entry("when the blocks were partially found in the cache", 90.0, 10.0, 0.9, true),
+		Entry("when the database had no block access", 0.0, 0.0, 0.0, false),
+	)
+
+	It("reports the ratio by database, skipping the ones without block access", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseBlocksQuery).
+			WillReturnRows(sqlmock.NewRows(blocksColumns).
+				AddRow("app", 750, 250).
+				AddRow("template0", 0, 0))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGCacheHitRatio(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.CacheHitRatio)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveLen(1))
+		Expect(families[0].GetName()).To(Equal("cnpg_pg_cache_hit_ratio"))
+		Expect(families[0].GetMetric()).To(HaveLen(1))
+
+		metric := families[0].GetMetric()[0]
+		Expect(metric.GetLabel()).To(HaveLen(1))
+		Expect(metric.GetLabel()[0].GetValue()).To(Equal("app"))
+		Expect(metric.GetGauge().GetValue()).To(Equal(0.75))
+	})
+
+	It("returns an error when the statistics can't be read", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseBlocksQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGCacheHitRatio(exporter, db)).ToNot(Succeed())
+	})
+})
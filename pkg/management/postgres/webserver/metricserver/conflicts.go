@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// databaseConflictsQuery reads, for each database accepting connections,
+// the number of queries canceled because of conflicts with recovery
+const databaseConflictsQuery = `SELECT c.datname,
+  c.confl_tablespace,
+  c.confl_lock,
+  c.confl_snapshot,
+  c.confl_bufferpin,
+  c.confl_deadlock
+FROM pg_catalog.pg_stat_database_conflicts c
+JOIN pg_catalog.pg_database d ON d.oid = c.datid
+WHERE d.datallowconn`
+
+// databaseConflicts is the content of a row of pg_stat_database_conflicts
+type databaseConflicts struct {
+	database   string
+	tablespace float64
+	lock       float64
+	snapshot   float64
+	bufferpin  float64
+	deadlock   float64
+}
+
+// conflictSample is the number of canceled queries for a type of conflict
+type conflictSample struct {
+	conflictType string
+	value        float64
+}
+
+// samples returns the conflicts broken out by type
+func (c databaseConflicts) samples() []conflictSample {
+	return []conflictSample{
+		{conflictType: "tablespace", value: c.tablespace},
+		{conflictType: "lock", value: c.lock},
+		{conflictType: "snapshot", value: c.snapshot},
+		{conflictType: "bufferpin", value: c.bufferpin},
+		{conflictType: "deadlock", value: c.deadlock},
+	}
+}
+
+// getDatabaseConflicts reads the pg_stat_database_conflicts view
+func getDatabaseConflicts(db *sql.DB) ([]databaseConflicts, error) {
+	rows, err := db.Query(databaseConflictsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getDatabaseConflicts")
+		}
+	}()
+
+	var result []databaseConflicts
+	for rows.Next() {
+		var item databaseConflicts
+		if err := rows.Scan(
+			&item.database,
+			&item.tablespace,
+			&item.lock,
+			&item.snapshot,
+			&item.bufferpin,
+			&item.deadlock,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func collectPGStatDatabaseConflicts(e *Exporter, db *sql.DB) error {
+	conflicts, err := getDatabaseConflicts(db)
+	if err != nil {
+		return err
+	}
+
+	return reportExistingDatabases(func() error {
+		for _, item := range conflicts {
+			for _, sample := range item.samples() {
+				e.Metrics.DatabaseConflicts.WithLabelValues(item.database, sample.conflictType).Set(sample.value)
+			}
+		}
+		return nil
+	}, e.Metrics.DatabaseConflicts)
+}
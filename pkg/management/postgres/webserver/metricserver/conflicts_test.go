@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("database conflicts metrics", func() {
+	conflictsColumns := []string{
+		"datname", "confl_tablespace", "confl_lock", "confl_snapshot", "confl_bufferpin", "confl_deadlock",
+	}
+
+	It("parses the conflicts view into typed samples", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseConflictsQuery).
+			WillReturnRows(sqlmock.NewRows(conflictsColumns).
+				AddRow("app", 1, 2, 3, 4, 5).
+				AddRow("postgres", 0, 0, 0, 0, 0))
+
+		conflicts, err := getDatabaseConflicts(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(conflicts).To(HaveLen(2))
+
+		Expect(conflicts[0].database).To(Equal("app"))
+		Expect(conflicts[0].samples()).To(ConsistOf(
+			conflictSample{conflictType: "tablespace", value: 1},
+			conflictSample{conflictType: "lock", value: 2},
+			conflictSample{conflictType: "snapshot", value: 3},
+			conflictSample{conflictType: "bufferpin", value: 4},
+			conflictSample{conflictType: "deadlock", value: 5},
+		))
+		Expect(conflicts[1].database).To(Equal("postgres"))
+		for _, sample := range conflicts[1].samples() {
+			Expect(sample.value).To(BeZero())
+		}
+	})
+
+	It("reports the conflicts by database and type", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseConflictsQuery).
+			WillReturnRows(sqlmock.NewRows(conflictsColumns).
+				AddRow("app", 0, 7, 12, 0, 1))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGStatDatabaseConflicts(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.DatabaseConflicts)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveLen(1))
+		Expect(families[0].GetName()).To(Equal("cnpg_pg_stat_database_conflicts_by_type"))
+
+		values := make(map[string]float64)
+		for _, metric := range families[0].GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			Expect(labels["database"]).To(Equal("app"))
+			values[labels["type"]] = metric.GetGauge().GetValue()
+		}
+		Expect(values).To(Equal(map[string]float64{
+			"tablespace": 0,
+			"lock":       7,
+			"snapshot":   12,
+			"bufferpin":  0,
+			"deadlock":   1,
+		}))
+	})
+
+	It("returns an error when the view can't be read", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseConflictsQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGStatDatabaseConflicts(exporter, db)).ToNot(Succeed())
+	})
+})
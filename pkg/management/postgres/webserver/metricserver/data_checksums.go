@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// dataChecksumsEnabledQuery reads whether the data checksums are enabled
+const dataChecksumsEnabledQuery = `SELECT pg_catalog.current_setting('data_checksums')::boolean`
+
+// databaseChecksumFailuresQuery reads, for each database, the number of
+// data checksum failures detected since the statistics were last reset.
+// The counter is NULL when the data checksums are disabled, and the row
+// with a NULL name, related to the shared objects, is skipped
+const databaseChecksumFailuresQuery = `SELECT datname, checksum_failures
+FROM pg_catalog.pg_stat_database
+WHERE datname IS NOT NULL AND checksum_failures IS NOT NULL`
+
+// databaseChecksumFailures is the number of checksum failures of a database
+type databaseChecksumFailures struct {
+	database string
+	failures float64
+}
+
+// getDatabaseChecksumFailures reads the checksum failures of the databases
+func getDatabaseChecksumFailures(db *sql.DB) ([]databaseChecksumFailures, error) {
+	rows, err := db.Query(databaseChecksumFailuresQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getDatabaseChecksumFailures")
+		}
+	}()
+
+	var result []databaseChecksumFailures
+	for rows.Next() {
+		var item databaseChecksumFailures
+		if err := rows.Scan(&item.database, &item.failures); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// collectPGDataChecksums reports whether the data checksums are enabled,
+// and the checksum failures of the databases, counted from PostgreSQL 12
+func collectPGDataChecksums(e *Exporter, db *sql.DB, majorVersion uint64) error {
+	var enabled bool
+	if err := db.QueryRow(dataChecksumsEnabledQuery).Scan(&enabled); err != nil {
+		return err
+	}
+
+	var failures []databaseChecksumFailures
+	if majorVersion >= 12 {
+		var err error
+		if failures, err = getDatabaseChecksumFailures(db); err != nil {
+			return err
+		}
+	}
+
+	e.Metrics.DataChecksumsEnabled.Reset()
+	if enabled {
+		e.Metrics.DataChecksumsEnabled.WithLabelValues().Set(1)
+	} else {
+		e.Metrics.DataChecksumsEnabled.WithLabelValues().Set(0)
+	}
+
+	return reportExistingDatabases(func() error {
+		for _, item := range failures {
+			e.Metrics.DatabaseChecksumFailures.WithLabelValues(item.database).Set(item.failures)
+		}
+		return nil
+	}, e.Metrics.DatabaseChecksumFailures)
+}
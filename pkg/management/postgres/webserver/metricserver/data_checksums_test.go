@@ -0,0 +1,128 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("data checksums metrics", func() {
+	checksumFailuresColumns := []string{"datname", "checksum_failures"}
+
+	gatherValues := func(exporter *Exporter) map[string]map[string]float64 {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.DataChecksumsEnabled, exporter.Metrics.DatabaseChecksumFailures)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		values := make(map[string]map[string]float64)
+		for _, family := range families {
+			values[family.GetName()] = make(map[string]float64)
+			for _, metric := range family.GetMetric() {
+				var datname string
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "datname" {
+						datname = label.GetValue()
+					}
+				}
+				values[family.GetName()][datname] = metric.GetGauge().GetValue()
+			}
+		}
+		return values
+	}
+
+	It("reports the checksum failures by database", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(dataChecksumsEnabledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"data_checksums"}).AddRow(true))
+		mock.ExpectQuery(databaseChecksumFailuresQuery).
+			WillReturnRows(sqlmock.NewRows(checksumFailuresColumns).
+				AddRow("app", 2).
+				AddRow("postgres", 0))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGDataChecksums(exporter, db, 16)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(gatherValues(exporter)).To(Equal(map[string]map[string]float64{
+			"cnpg_pg_data_checksums_enabled":          {"": 1},
+			"cnpg_pg_stat_database_checksum_failures": {"app": 2, "postgres": 0},
+		}))
+	})
+
+	It("reports the data checksums as disabled", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(dataChecksumsEnabledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"data_checksums"}).AddRow(false))
+		mock.ExpectQuery(databaseChecksumFailuresQuery).
+			WillReturnRows(sqlmock.NewRows(checksumFailuresColumns))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGDataChecksums(exporter, db, 16)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(gatherValues(exporter)).To(Equal(map[string]map[string]float64{
+			"cnpg_pg_data_checksums_enabled": {"": 0},
+		}))
+	})
+
+	It("doesn't count the checksum failures before PostgreSQL 12", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(dataChecksumsEnabledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"data_checksums"}).AddRow(true))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGDataChecksums(exporter, db, 11)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns an error when the view can't be read", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(dataChecksumsEnabledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"data_checksums"}).AddRow(true))
+		mock.ExpectQuery(databaseChecksumFailuresQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGDataChecksums(exporter, db, 16)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// databaseSizeGrowthWindow is the time window used to compute the
+// growth rate of the databases
+const databaseSizeGrowthWindow = 5 * time.Minute
+
+// databaseSizeQuery reads the disk space used by each database
+const databaseSizeQuery = `SELECT datname, pg_catalog.pg_database_size(oid)
+FROM pg_catalog.pg_database`
+
+// databaseSizeNoTemplatesQuery reads the disk space used by each
+// database, skipping the template ones
+const databaseSizeNoTemplatesQuery = databaseSizeQuery + `
+WHERE NOT datistemplate`
+
+// databaseSize is the disk space used by a database
+type databaseSize struct {
+	database string
+	bytes    float64
+}
+
+// databaseSizeSample is the size of a database at a certain time
+type databaseSizeSample struct {
+	bytes     float64
+	timestamp time.Time
+}
+
+// databaseSizeTracker keeps the recent size samples of every database,
+// and uses them to compute the growth rate
+type databaseSizeTracker struct {
+	mu      sync.Mutex
+	window  time.Duration
+	samples map[string][]databaseSizeSample
+}
+
+// newDatabaseSizeTracker creates a tracker computing the growth rate
+// over the passed time window
+func newDatabaseSizeTracker(window time.Duration) *databaseSizeTracker {
+	return &databaseSizeTracker{
+		window:  window,
+		samples: make(map[string][]databaseSizeSample),
+	}
+}
+
+// observe records the size of the databases at the passed time, and
+// returns the growth rate in bytes per second of the databases having
+// at least two samples in the time window. The databases that are not
+// reported anymore are forgotten
+func (t *databaseSizeTracker) observe(sizes []databaseSize, now time.Time) map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := now.Add(-t.window)
+	samples := make(map[string][]databaseSizeSample, len(sizes))
+	rates := make(map[string]float64, len(sizes))
+	for _, size := range sizes {
+		var recent []databaseSizeSample
+		for _, sample := range t.samples[size.database] {
+			if !sample.timestamp.Before(cutoff) && sample.timestamp.Before(now) {
+				recent = append(recent, sample)
+			}
+		}
+		recent = append(recent, databaseSizeSample{bytes: size.bytes, timestamp: now})
+		samples[size.database] = recent
+
+		if len(recent) < 2 {
+			continue
+		}
+		oldest := recent[0]
+		rates[size.database] = (size.bytes - oldest.bytes) / now.Sub(oldest.timestamp).Seconds()
+	}
+	t.samples = samples
+
+	return rates
+}
+
+// getDatabaseSizes reads the disk space used by the databases
+func getDatabaseSizes(db *sql.DB, includeTemplates bool) ([]databaseSize, error) {
+	query := databaseSizeNoTemplatesQuery
+	if includeTemplates {
+		query = databaseSizeQuery
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getDatabaseSizes")
+		}
+	}()
+
+	var result []databaseSize
+	for rows.Next() {
+		var item databaseSize
+		if err := rows.Scan(&item.database, &item.bytes); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// areTemplateDatabasesIncluded checks whether the cached cluster requires
+// the size of the template databases to be reported
+func areTemplateDatabasesIncluded() bool {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return false
+	}
+
+	return cluster.Spec.Monitoring.AreTemplateDatabasesIncluded()
+}
+
+func collectPGDatabaseSize(e *Exporter, db *sql.DB, includeTemplates bool, now time.Time) error {
+	sizes, err := getDatabaseSizes(db, includeTemplates)
+	if err != nil {
+		return err
+	}
+
+	rates := e.databaseSizes.observe(sizes, now)
+
+	return reportExistingDatabases(func() error {
+		for _, item := range sizes {
+			e.Metrics.DatabaseSize.WithLabelValues(item.database).Set(item.bytes)
+			if rate, ok := rates[item.database]; ok {
+				e.Metrics.DatabaseSizeGrowthRate.WithLabelValues(item.database).Set(rate)
+			}
+		}
+		return nil
+	}, e.Metrics.DatabaseSize, e.Metrics.DatabaseSizeGrowthRate)
+}
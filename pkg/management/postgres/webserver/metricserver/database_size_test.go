@@ -0,0 +1,177 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("database size metrics", func() {
+	sizeColumns := []string{"datname", "pg_database_size"}
+
+	gatherByDatabase := func(collector prometheus.Collector) map[string]float64 {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		values := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "datname" {
+						values[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return values
+	}
+
+	It("parses the size of the databases", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseSizeNoTemplatesQuery).
+			WillReturnRows(sqlmock.NewRows(sizeColumns).
+				AddRow("app", 8589934592).
+				AddRow("postgres", 7631663))
+
+		sizes, err := getDatabaseSizes(db, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(sizes).To(Equal([]databaseSize{
+			{database: "app", bytes: 8589934592},
+			{database: "postgres", bytes: 7631663},
+		}))
+	})
+
+	It("excludes the template databases by default", func() {
+		Expect(databaseSizeNoTemplatesQuery).To(HaveSuffix("WHERE NOT datistemplate"))
+		Expect(databaseSizeQuery).ToNot(ContainSubstring("datistemplate"))
+	})
+
+	It("reports the template databases when requested", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseSizeQuery).
+			WillReturnRows(sqlmock.NewRows(sizeColumns).
+				AddRow("app", 1024).
+				AddRow("template0", 7385903).
+				AddRow("template1", 7558147))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGDatabaseSize(exporter, db, true, time.Now())).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(gatherByDatabase(exporter.Metrics.DatabaseSize)).To(Equal(map[string]float64{
+			"app":       1024,
+			"template0": 7385903,
+			"template1": 7558147,
+		}))
+	})
+
+	It("reports the growth rate starting from the second collection", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseSizeNoTemplatesQuery).
+			WillReturnRows(sqlmock.NewRows(sizeColumns).
+				AddRow("app", 1000).
+				AddRow("postgres", 5000))
+		mock.ExpectQuery(databaseSizeNoTemplatesQuery).
+			WillReturnRows(sqlmock.NewRows(sizeColumns).
+				AddRow("app", 4000).
+				AddRow("postgres", 4400))
+
+		exporter := NewExporter(postgres.NewInstance())
+		now := time.Now()
+		Expect(collectPGDatabaseSize(exporter, db, false, now)).To(Succeed())
+		Expect(gatherByDatabase(exporter.Metrics.DatabaseSizeGrowthRate)).To(BeEmpty())
+
+		Expect(collectPGDatabaseSize(exporter, db, false, now.Add(30*time.Second))).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(gatherByDatabase(exporter.Metrics.DatabaseSize)).To(Equal(map[string]float64{
+			"app":      4000,
+			"postgres": 4400,
+		}))
+		Expect(gatherByDatabase(exporter.Metrics.DatabaseSizeGrowthRate)).To(Equal(map[string]float64{
+			"app":      100,
+			"postgres": -20,
+		}))
+	})
+
+	It("returns an error when the sizes can't be read", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseSizeNoTemplatesQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGDatabaseSize(exporter, db, false, time.Now())).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("database size tracker", func() {
+	It("computes the growth rate over the time window", func() {
+		tracker := newDatabaseSizeTracker(time.Minute)
+		start := time.Now()
+
+		Expect(tracker.observe([]databaseSize{{database: "app", bytes: 100}}, start)).To(BeEmpty())
+		Expect(tracker.observe([]databaseSize{{database: "app", bytes: 400}}, start.Add(30*time.Second))).
+			To(Equal(map[string]float64{"app": 10}))
+		Expect(tracker.observe([]databaseSize{{database: "app", bytes: 700}}, start.Add(60*time.Second))).
+			To(Equal(map[string]float64{"app": 10}))
+
+		// the first sample is now outside the window
+		Expect(tracker.observe([]databaseSize{{database: "app", bytes: 1600}}, start.Add(90*time.Second))).
+			To(Equal(map[string]float64{"app": 20}))
+	})
+
+	It("forgets the databases that are not reported anymore", func() {
+		tracker := newDatabaseSizeTracker(time.Minute)
+		start := time.Now()
+
+		tracker.observe([]databaseSize{{database: "app", bytes: 100}, {database: "old", bytes: 100}}, start)
+		tracker.observe([]databaseSize{{database: "app", bytes: 200}}, start.Add(10*time.Second))
+		Expect(tracker.samples).To(HaveKey("app"))
+		Expect(tracker.samples).ToNot(HaveKey("old"))
+
+		Expect(tracker.observe([]databaseSize{{database: "old", bytes: 300}}, start.Add(20*time.Second))).
+			To(BeEmpty())
+	})
+})
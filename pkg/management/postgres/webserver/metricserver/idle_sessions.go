@@ -0,0 +1,63 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// idleInTransactionSessionsQuery counts, for each database accepting
+// connections, the client sessions that are idle in transaction and
+// the age in seconds of the oldest one
+const idleInTransactionSessionsQuery = `SELECT d.datname,
+  count(a.pid) AS sessions,
+  coalesce(max(extract(epoch FROM pg_catalog.now() - a.state_change)), 0) AS oldest_age_seconds
+FROM pg_catalog.pg_database d
+LEFT JOIN pg_catalog.pg_stat_activity a
+  ON a.datid = d.oid
+  AND a.backend_type = 'client backend'
+  AND a.state IN ('idle in transaction', 'idle in transaction (aborted)')
+WHERE d.datallowconn
+GROUP BY d.datname`
+
+func collectPGIdleInTransactionSessions(e *Exporter, db *sql.DB) error {
+	rows, err := db.Query(idleInTransactionSessionsQuery)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for collectPGIdleInTransactionSessions")
+		}
+	}()
+
+	return reportExistingDatabases(func() error {
+		for rows.Next() {
+			var database string
+			var sessions, oldestAge float64
+			if err := rows.Scan(&database, &sessions, &oldestAge); err != nil {
+				return err
+			}
+
+			e.Metrics.IdleInTransactionSessions.WithLabelValues(database).Set(sessions)
+			e.Metrics.IdleInTransactionOldestAge.WithLabelValues(database).Set(oldestAge)
+		}
+		return rows.Err()
+	}, e.Metrics.IdleInTransactionSessions, e.Metrics.IdleInTransactionOldestAge)
+}
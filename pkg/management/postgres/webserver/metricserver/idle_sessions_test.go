@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("idle in transaction sessions metrics", func() {
+	var exporter *Exporter
+
+	BeforeEach(func() {
+		exporter = NewExporter(postgres.NewInstance())
+	})
+
+	gatherByDatabase := func(collector prometheus.Collector) map[string]float64 {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		result := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "database" {
+						result[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return result
+	}
+
+	It("reports the number of sessions and the age of the oldest one for each database", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(idleInTransactionSessionsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "sessions", "oldest_age_seconds"}).
+				AddRow("app", 3, 125.5).
+				AddRow("postgres", 0, 0))
+
+		Expect(collectPGIdleInTransactionSessions(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		sessions := gatherByDatabase(exporter.Metrics.IdleInTransactionSessions)
+		Expect(sessions).To(HaveLen(2))
+		Expect(sessions["app"]).To(BeEquivalentTo(3))
+		Expect(sessions["postgres"]).To(BeZero())
+
+		oldestAge := gatherByDatabase(exporter.Metrics.IdleInTransactionOldestAge)
+		Expect(oldestAge).To(HaveLen(2))
+		Expect(oldestAge["app"]).To(BeEquivalentTo(125.5))
+		Expect(oldestAge["postgres"]).To(BeZero())
+	})
+
+	It("doesn't report the databases that have been dropped", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		exporter.Metrics.IdleInTransactionSessions.WithLabelValues("dropped").Set(1)
+		exporter.Metrics.IdleInTransactionOldestAge.WithLabelValues("dropped").Set(10)
+		mock.ExpectQuery(idleInTransactionSessionsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "sessions", "oldest_age_seconds"}))
+
+		Expect(collectPGIdleInTransactionSessions(exporter, db)).To(Succeed())
+		Expect(gatherByDatabase(exporter.Metrics.IdleInTransactionSessions)).To(BeEmpty())
+		Expect(gatherByDatabase(exporter.Metrics.IdleInTransactionOldestAge)).To(BeEmpty())
+	})
+})
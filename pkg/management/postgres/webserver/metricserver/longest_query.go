@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// activityQuery lists, for each database accepting connections, the
+// backends connected to it, with the time elapsed since the start of
+// their current query. The backends are filtered by selectLongestRunningQueries
+const activityQuery = `SELECT d.datname,
+  coalesce(a.backend_type, ''),
+  coalesce(a.state, ''),
+  coalesce(a.wait_event, ''),
+  coalesce(extract(epoch FROM pg_catalog.now() - a.query_start), 0)
+FROM pg_catalog.pg_database d
+LEFT JOIN pg_catalog.pg_stat_activity a
+  ON a.datid = d.oid
+  AND a.pid <> pg_catalog.pg_backend_pid()
+WHERE d.datallowconn`
+
+// excludedBackendTypes are the types of the backends whose queries are not
+// considered when looking for the longest running one, as they are
+// expected to stay active for a long time
+var excludedBackendTypes = map[string]bool{
+	"autovacuum launcher":          true,
+	"autovacuum worker":            true,
+	"walsender":                    true,
+	"walreceiver":                  true,
+	"logical replication launcher": true,
+	"logical replication worker":   true,
+}
+
+// activityRow is a backend connected to a database, as
+// reported by activityQuery
+type activityRow struct {
+	database    string
+	backendType string
+	state       string
+	waitEvent   string
+	duration    float64
+}
+
+// runningQuery is the longest running query of a database
+type runningQuery struct {
+	waitEvent string
+	duration  float64
+}
+
+// selectLongestRunningQueries finds the longest running query of each
+// database, considering only the active backends, excluding the autovacuum
+// and the replication ones. Databases without running queries are reported
+// with a zero duration
+func selectLongestRunningQueries(rows []activityRow) map[string]runningQuery {
+	result := make(map[string]runningQuery)
+	for _, row := range rows {
+		longest, found := result[row.database]
+		if !found {
+			result[row.database] = runningQuery{}
+		}
+
+		if row.state != "active" || excludedBackendTypes[row.backendType] {
+			continue
+		}
+
+		if row.duration > longest.duration {
+			result[row.database] = runningQuery{waitEvent: row.waitEvent, duration: row.duration}
+		}
+	}
+
+	return result
+}
+
+func collectPGLongestRunningQuery(e *Exporter, db *sql.DB) error {
+	rows, err := db.Query(activityQuery)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for collectPGLongestRunningQuery")
+		}
+	}()
+
+	var activity []activityRow
+	for rows.Next() {
+		var row activityRow
+		if err := rows.Scan(&row.database, &row.backendType, &row.state, &row.waitEvent, &row.duration); err != nil {
+			return err
+		}
+		activity = append(activity, row)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// the wait event of the longest running query changes over time, and
+	// databases can be dropped: let's report only the current values
+	e.Metrics.LongestRunningQuery.Reset()
+	for database, query := range selectLongestRunningQueries(activity) {
+		e.Metrics.LongestRunningQuery.WithLabelValues(database, query.waitEvent).Set(query.duration)
+	}
+
+	return nil
+}
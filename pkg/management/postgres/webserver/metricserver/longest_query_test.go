@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("longest running query metric", func() {
+	It("computes the longest running query of each database", func() {
+		longest := selectLongestRunningQueries([]activityRow{
+			{database: "app", backendType: "client backend", state: "active", waitEvent: "Lock", duration: 12},
+			{database: "app", backendType: "client backend", state: "active", duration: 30.5},
+			{database: "app", backendType: "client backend", state: "active", waitEvent: "ClientRead", duration: 3},
+			{database: "postgres"},
+		})
+
+		Expect(longest).To(HaveLen(2))
+		Expect(longest["app"]).To(Equal(runningQuery{duration: 30.5}))
+		Expect(longest["postgres"]).To(Equal(runningQuery{}))
+	})
+
+	It("ignores the idle backends", func() {
+		longest := selectLongestRunningQueries([]activityRow{
+			{database: "app", backendType: "client backend", state: "idle", duration: 600},
+			{database: "app", backendType: "client backend", state: "idle in transaction", duration: 300},
+			{database: "app", backendType: "client backend", state: "active", waitEvent: "Lock", duration: 5},
+		})
+
+		Expect(longest["app"]).To(Equal(runningQuery{waitEvent: "Lock", duration: 5}))
+	})
+
+	It("ignores the autovacuum and the replication backends", func() {
+		longest := selectLongestRunningQueries([]activityRow{
+			{database: "app", backendType: "autovacuum worker", state: "active", duration: 3600},
+			{database: "app", backendType: "walsender", state: "active", duration: 7200},
+			{database: "app", backendType: "logical replication worker", state: "active", duration: 7200},
+			{database: "app", backendType: "client backend", state: "active", duration: 1.5},
+			{database: "other", backendType: "walsender", state: "active", duration: 7200},
+		})
+
+		Expect(longest["app"]).To(Equal(runningQuery{duration: 1.5}))
+		Expect(longest["other"]).To(Equal(runningQuery{}))
+	})
+
+	It("exports the metric with the wait event of the longest running query", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		exporter.Metrics.LongestRunningQuery.WithLabelValues("dropped", "").Set(100)
+		mock.ExpectQuery(activityQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "backend_type", "state", "wait_event", "duration"}).
+				AddRow("app", "client backend", "active", "Lock", 42.0).
+				AddRow("app", "autovacuum worker", "active", "", 4200.0).
+				AddRow("postgres", "", "", "", 0.0))
+
+		Expect(collectPGLongestRunningQuery(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(testutil.CollectAndCount(exporter.Metrics.LongestRunningQuery)).To(Equal(2))
+		Expect(testutil.ToFloat64(exporter.Metrics.LongestRunningQuery.WithLabelValues("app", "Lock"))).
+			To(BeEquivalentTo(42))
+		Expect(testutil.ToFloat64(exporter.Metrics.LongestRunningQuery.WithLabelValues("postgres", ""))).
+			To(BeZero())
+	})
+})
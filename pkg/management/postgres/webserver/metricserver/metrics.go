@@ -24,6 +24,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/progress"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
@@ -49,6 +51,9 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 	if err := registry.Register(collectors.NewGoCollector()); err != nil {
 		return nil, fmt.Errorf("while registering Go exporters: %w", err)
 	}
+	if err := registry.Register(progress.NewCollector(isBackupProgressMetricsEnabled)); err != nil {
+		return nil, fmt.Errorf("while registering backup progress exporters: %w", err)
+	}
 	serveMux := http.NewServeMux()
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
@@ -67,6 +72,17 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 	return metricServer, nil
 }
 
+// isBackupProgressMetricsEnabled checks whether the progress of the
+// backups should be exported, according to the cached cluster
+func isBackupProgressMetricsEnabled() bool {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return false
+	}
+
+	return cluster.Spec.Monitoring.IsBackupProgressMetricsEnabled()
+}
+
 // GetExporter get the exporter used for metrics. If the web statusServer still
 // has not started, the exporter is nil
 func (ms *MetricsServer) GetExporter() *Exporter {
@@ -0,0 +1,33 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+// resettableMetric is a metric whose samples can be removed all at once
+type resettableMetric interface {
+	Reset()
+}
+
+// reportExistingDatabases sets the samples of the per-database metrics
+// through the passed function, after removing the previous ones. Databases
+// can be dropped at any time, and only the existing ones are reported
+func reportExistingDatabases(set func() error, metrics ...resettableMetric) error {
+	for _, metric := range metrics {
+		metric.Reset()
+	}
+
+	return set()
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package metricserver
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -46,6 +47,14 @@ type Exporter struct {
 	instance *postgres.Instance
 	Metrics  *metrics
 	queries  *m.QueriesCollector
+
+	// databaseSizes keeps the recent sizes of the databases, to compute
+	// their growth rate
+	databaseSizes *databaseSizeTracker
+
+	// walRate keeps the last WAL write location of the primary, to
+	// compute the WAL generation rate
+	walRate *walRateTracker
 }
 
 // metrics here are related to the exporter itself, which is instrumented to
@@ -68,6 +77,28 @@ type metrics struct {
 	FencingOn                    prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
 	NodesUsed                    prometheus.Gauge
+	ReplicationSlotsRetainedWAL  *prometheus.GaugeVec
+	ReplicationSlotsUsed         prometheus.Gauge
+	ReplicationSlotsMax          prometheus.Gauge
+	IdleInTransactionSessions    *prometheus.GaugeVec
+	IdleInTransactionOldestAge   *prometheus.GaugeVec
+	DatabaseConflicts            *prometheus.GaugeVec
+	DatabaseSize                 *prometheus.GaugeVec
+	DatabaseSizeGrowthRate       *prometheus.GaugeVec
+	CacheHitRatio                *prometheus.GaugeVec
+	DatabaseTempFiles            *databaseCounter
+	DatabaseTempBytes            *databaseCounter
+	DataChecksumsEnabled         *prometheus.GaugeVec
+	DatabaseChecksumFailures     *prometheus.GaugeVec
+	WALGenerationRate            prometheus.Gauge
+	WALReadyCount                prometheus.Gauge
+	WALArchivePendingCount       *prometheus.GaugeVec
+	LongestRunningQuery          *prometheus.GaugeVec
+	TableXidAge                  *prometheus.GaugeVec
+	TopStatementsCalls           *prometheus.GaugeVec
+	TopStatementsRows            *prometheus.GaugeVec
+	TopStatementsTotalExecTime   *prometheus.GaugeVec
+	TopStatementsMeanExecTime    *prometheus.GaugeVec
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -85,8 +116,10 @@ type PgStatWalMetrics struct {
 // NewExporter creates an exporter
 func NewExporter(instance *postgres.Instance) *Exporter {
 	return &Exporter{
-		instance: instance,
-		Metrics:  newMetrics(),
+		instance:      instance,
+		Metrics:       newMetrics(),
+		databaseSizes: newDatabaseSizeTracker(databaseSizeGrowthWindow),
+		walRate:       &walRateTracker{},
 	}
 }
 
@@ -197,6 +230,150 @@ func newMetrics() *metrics {
 				"implying the absence of High Availability (HA). Ideally this value " +
 				"should match the number of instances in the cluster.",
 		}),
+		ReplicationSlotsRetainedWAL: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_replication_slots",
+			Name:      "retained_wal_bytes",
+			Help: "Amount of WAL in bytes retained by the replication slot, " +
+				"computed as the distance between the current WAL position and its restart_lsn. " +
+				"NaN if the slot doesn't reserve WAL yet",
+		}, []string{"slot_name", "slot_type", "database"}),
+		ReplicationSlotsUsed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_replication_slots",
+			Name:      "used",
+			Help:      "Number of replication slots existing in the instance, including the temporary ones",
+		}),
+		ReplicationSlotsMax: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_replication_slots",
+			Name:      "max",
+			Help: "Maximum number of replication slots that the instance can support, " +
+				"as set by max_replication_slots",
+		}),
+		IdleInTransactionSessions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "idle_in_transaction_sessions",
+			Help:      "Number of client sessions that are idle in transaction",
+		}, []string{"database"}),
+		IdleInTransactionOldestAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "idle_in_transaction_oldest_age_seconds",
+			Help: "Number of seconds since the oldest client session that is idle in transaction " +
+				"changed its state. 0 if there are no such sessions",
+		}, []string{"database"}),
+		DatabaseConflicts: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_stat_database",
+			Name:      "conflicts_by_type",
+			Help: "Number of queries canceled on a replica due to conflicts with recovery, " +
+				"by type of conflict",
+		}, []string{"database", "type"}),
+		DatabaseSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_database",
+			Name:      "size_bytes",
+			Help:      "Disk space used by the database",
+		}, []string{"datname"}),
+		DatabaseSizeGrowthRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_database",
+			Name:      "size_growth_bytes_per_second",
+			Help: "Growth rate of the disk space used by the database, in bytes per second, " +
+				"computed over the last five minutes. Negative if the database shrunk",
+		}, []string{"datname"}),
+		CacheHitRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "cache_hit_ratio",
+			Help: "Fraction of the disk blocks accesses of the database that were satisfied by the buffer cache. " +
+				"Not reported for databases without any block access",
+		}, []string{"datname"}),
+		DatabaseTempFiles: newDatabaseCounter("pg_stat_database", "temp_files",
+			"Number of temporary files created by queries in this database. "+
+				"All temporary files are counted, regardless of the log_temp_files setting"),
+		DatabaseTempBytes: newDatabaseCounter("pg_stat_database", "temp_bytes",
+			"Total amount of data written to temporary files by queries in this database. "+
+				"All temporary files are counted, regardless of the log_temp_files setting"),
+		DataChecksumsEnabled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "data_checksums_enabled",
+			Help: "1 if the data checksums are enabled, 0 otherwise. " +
+				"Without data checksums the corruption of the data pages can't be detected",
+		}, []string{}),
+		DatabaseChecksumFailures: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_stat_database",
+			Name:      "checksum_failures",
+			Help: "Number of data page checksum failures detected in this database, indicating " +
+				"a possible corruption. Only reported with the data checksums enabled on PostgreSQL 12 or later",
+		}, []string{"datname"}),
+		WALGenerationRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "wal_bytes_per_second",
+			Help: "Rate at which the primary generated WAL since the previous collection, in bytes per second. " +
+				"Zero on replicas and after a timeline change",
+		}),
+		WALReadyCount: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "wal_ready_count",
+			Help: fmt.Sprintf("Number of WAL files waiting to be archived, as flagged in the '%s' directory. "+
+				"Zero on replicas", specs.PgWalArchiveStatusPath),
+		}),
+		WALArchivePendingCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "wal_archive_pending_count",
+			Help: "Number of WAL files queued to be archived again on an object store, after failing on it " +
+				"while the archive quorum was reached",
+		}, []string{"destination"}),
+		LongestRunningQuery: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg",
+			Name:      "longest_running_query_seconds",
+			Help: "Number of seconds since the start of the longest running query of the database, " +
+				"with the wait event of its backend. Autovacuum and replication backends are excluded",
+		}, []string{"database", "wait_event"}),
+		TableXidAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_table",
+			Name:      "xid_age",
+			Help: "Age of the oldest unfrozen transaction ID of the table, as age(relfrozenxid). " +
+				"Only the tables with the highest age are reported, and only on the primary",
+		}, []string{"datname", "schemaname", "relname"}),
+		TopStatementsCalls: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_stat_statements",
+			Name:      "calls",
+			Help: "Number of times the statement was executed, " +
+				"for the statements with the highest total execution time",
+		}, topStatementsLabels),
+		TopStatementsRows: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_stat_statements",
+			Name:      "rows",
+			Help: "Number of rows retrieved or affected by the statement, " +
+				"for the statements with the highest total execution time",
+		}, topStatementsLabels),
+		TopStatementsTotalExecTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_stat_statements",
+			Name:      "total_exec_time",
+			Help: "Total time spent executing the statement, in milliseconds, " +
+				"for the statements with the highest total execution time",
+		}, topStatementsLabels),
+		TopStatementsMeanExecTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: "pg_stat_statements",
+			Name:      "mean_exec_time",
+			Help: "Mean time spent executing the statement, in milliseconds, " +
+				"for the statements with the highest total execution time",
+		}, topStatementsLabels),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -258,6 +435,7 @@ func newMetrics() *metrics {
 }
 
 // Describe implements prometheus.Collector, defining the Metrics we return.
+// Like in Collect, the metrics of the disabled collectors are skipped
 func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.Metrics.CollectionsTotal.Desc()
 	ch <- e.Metrics.Error.Desc()
@@ -265,22 +443,35 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.PostgreSQLUp.Describe(ch)
 	ch <- e.Metrics.SwitchoverRequired.Desc()
 	e.Metrics.CollectionDuration.Describe(ch)
-	e.Metrics.SyncReplicas.Describe(ch)
 	ch <- e.Metrics.ReplicaCluster.Desc()
-	e.Metrics.PgWALArchiveStatus.Describe(ch)
-	e.Metrics.PgWALDirectory.Describe(ch)
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.TopStatementsCalls.Describe(ch)
+	e.Metrics.TopStatementsRows.Describe(ch)
+	e.Metrics.TopStatementsTotalExecTime.Describe(ch)
+	e.Metrics.TopStatementsMeanExecTime.Describe(ch)
+
+	for name, collectors := range e.defaultCollectorsMetrics() {
+		if isCollectorDisabled(name) {
+			continue
+		}
+		for _, collector := range collectors {
+			if e.isShadowedByCustomQueries(collector) {
+				continue
+			}
+			collector.Describe(ch)
+		}
+	}
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
 	}
 
-	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
+	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 && !isCollectorDisabled(apiv1.CollectorPgStatWAL) {
 		e.Metrics.PgStatWalMetrics.WalSync.Describe(ch)
 		e.Metrics.PgStatWalMetrics.WalWriteTime.Describe(ch)
 		e.Metrics.PgStatWalMetrics.WalFpi.Describe(ch)
@@ -303,18 +494,31 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.PostgreSQLUp.Collect(ch)
 	ch <- e.Metrics.SwitchoverRequired
 	e.Metrics.CollectionDuration.Collect(ch)
-	e.Metrics.SyncReplicas.Collect(ch)
 	ch <- e.Metrics.ReplicaCluster
-	e.Metrics.PgWALArchiveStatus.Collect(ch)
-	e.Metrics.PgWALDirectory.Collect(ch)
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
 	e.Metrics.FencingOn.Collect(ch)
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.TopStatementsCalls.Collect(ch)
+	e.Metrics.TopStatementsRows.Collect(ch)
+	e.Metrics.TopStatementsTotalExecTime.Collect(ch)
+	e.Metrics.TopStatementsMeanExecTime.Collect(ch)
+
+	for name, collectors := range e.defaultCollectorsMetrics() {
+		if isCollectorDisabled(name) {
+			continue
+		}
+		for _, collector := range collectors {
+			if e.isShadowedByCustomQueries(collector) {
+				continue
+			}
+			collector.Collect(ch)
+		}
+	}
 
-	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
+	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 && !isCollectorDisabled(apiv1.CollectorPgStatWAL) {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
 		e.Metrics.PgStatWalMetrics.WalWriteTime.Collect(ch)
 		e.Metrics.PgStatWalMetrics.WalFpi.Collect(ch)
@@ -326,6 +530,67 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
+// defaultCollectorsMetrics gets the metrics produced by each of the built-in
+// collectors that can be disabled, except the version dependent ones
+func (e *Exporter) defaultCollectorsMetrics() map[string][]prometheus.Collector {
+	return map[string][]prometheus.Collector{
+		apiv1.CollectorSynchronousStandbys: {e.Metrics.SyncReplicas},
+		apiv1.CollectorDatabaseConflicts:   {e.Metrics.DatabaseConflicts},
+		apiv1.CollectorDatabaseSize:        {e.Metrics.DatabaseSize, e.Metrics.DatabaseSizeGrowthRate},
+		apiv1.CollectorCacheHitRatio:       {e.Metrics.CacheHitRatio},
+		apiv1.CollectorTempFiles:           {e.Metrics.DatabaseTempFiles, e.Metrics.DatabaseTempBytes},
+		apiv1.CollectorDataChecksums:       {e.Metrics.DataChecksumsEnabled, e.Metrics.DatabaseChecksumFailures},
+		apiv1.CollectorWALGenerationRate:   {e.Metrics.WALGenerationRate},
+		apiv1.CollectorWALArchiveStatus: {
+			e.Metrics.PgWALArchiveStatus,
+			e.Metrics.WALReadyCount,
+			e.Metrics.WALArchivePendingCount,
+		},
+		apiv1.CollectorWALDirectory: {e.Metrics.PgWALDirectory},
+		apiv1.CollectorReplicationSlots: {
+			e.Metrics.ReplicationSlotsRetainedWAL,
+			e.Metrics.ReplicationSlotsUsed,
+			e.Metrics.ReplicationSlotsMax,
+		},
+		apiv1.CollectorIdleInTransaction: {
+			e.Metrics.IdleInTransactionSessions,
+			e.Metrics.IdleInTransactionOldestAge,
+		},
+		apiv1.CollectorLongestRunningQuery: {e.Metrics.LongestRunningQuery},
+		apiv1.CollectorTableXidAge:         {e.Metrics.TableXidAge},
+	}
+}
+
+// legacyMetricNames gets the names of the built-in metrics which used to be
+// produced by the default queries
+func (e *Exporter) legacyMetricNames() map[prometheus.Collector]string {
+	return map[prometheus.Collector]string{
+		e.Metrics.DatabaseSize:      PrometheusNamespace + "_pg_database_size_bytes",
+		e.Metrics.DatabaseTempFiles: PrometheusNamespace + "_pg_stat_database_temp_files",
+		e.Metrics.DatabaseTempBytes: PrometheusNamespace + "_pg_stat_database_temp_bytes",
+	}
+}
+
+// isShadowedByCustomQueries checks whether a built-in metric is still
+// produced by the custom queries, as users may have a copy of the default
+// query which used to define it. The built-in metric is not reported in
+// that case, as duplicated series would fail the whole scrape
+func (e *Exporter) isShadowedByCustomQueries(collector prometheus.Collector) bool {
+	name, ok := e.legacyMetricNames()[collector]
+	return ok && e.queries.DefinesMetric(name)
+}
+
+// isCollectorDisabled checks whether a built-in collector has been disabled
+// in the cluster, keeping all of them enabled until the cluster is known
+func isCollectorDisabled(name string) bool {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return false
+	}
+
+	return cluster.Spec.Monitoring.IsCollectorDisabled(name)
+}
+
 func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	e.Metrics.CollectionsTotal.Inc()
 	collectionStart := time.Now()
@@ -342,7 +607,7 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	db, err := e.instance.GetSuperUserDB()
+	db, err := e.instance.GetMonitoringDB()
 	if err != nil {
 		log.Error(err, "Error opening connection to PostgreSQL")
 		e.Metrics.Error.Set(1)
@@ -384,7 +649,9 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	// metrics collected only on primary server
 	if isPrimary {
 		// getting required synchronous standby number from postgres itself
-		e.collectFromPrimarySynchronousStandbysNumber(db)
+		if !isCollectorDisabled(apiv1.CollectorSynchronousStandbys) {
+			e.collectFromPrimarySynchronousStandbysNumber(db)
+		}
 
 		// getting the first point of recoverability
 		e.collectFromPrimaryFirstPointOnTimeRecovery()
@@ -395,18 +662,163 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.collectFromPrimaryLastFailedBackupTimestamp()
 	}
 
-	if err := collectPGWalArchiveMetric(e); err != nil {
-		log.Error(err, "while collecting WAL archive metrics", "path", specs.PgWalArchiveStatusPath)
-		e.Metrics.Error.Set(1)
-		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PgWALArchiveStats").Inc()
-		e.Metrics.PgWALArchiveStatus.Reset()
+	// conflicts with recovery can only happen on replicas
+	if isPrimary {
+		e.Metrics.DatabaseConflicts.Reset()
+	} else if !isCollectorDisabled(apiv1.CollectorDatabaseConflicts) {
+		if err := collectPGStatDatabaseConflicts(e, db); err != nil {
+			log.Error(err, "while collecting database conflicts")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGStatDatabaseConflicts").Inc()
+			e.Metrics.DatabaseConflicts.Reset()
+		}
 	}
 
-	if err := collectPGWalSettings(e, db); err != nil {
-		log.Error(err, "while collecting WAL settings", "path", specs.PgWalPath)
-		e.Metrics.Error.Set(1)
-		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWalSettings").Inc()
-		e.Metrics.PgWALDirectory.Reset()
+	if !isCollectorDisabled(apiv1.CollectorDatabaseSize) {
+		if err := collectPGDatabaseSize(e, db, areTemplateDatabasesIncluded(), time.Now()); err != nil {
+			log.Error(err, "while collecting database sizes")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGDatabaseSize").Inc()
+			e.Metrics.DatabaseSize.Reset()
+			e.Metrics.DatabaseSizeGrowthRate.Reset()
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorCacheHitRatio) {
+		if err := collectPGCacheHitRatio(e, db); err != nil {
+			log.Error(err, "while collecting cache hit ratio")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGCacheHitRatio").Inc()
+			e.Metrics.CacheHitRatio.Reset()
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorTempFiles) {
+		if err := collectPGStatDatabaseTempFiles(e, db); err != nil {
+			log.Error(err, "while collecting temporary files usage")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGStatDatabaseTempFiles").Inc()
+			e.Metrics.DatabaseTempFiles.Reset()
+			e.Metrics.DatabaseTempBytes.Reset()
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorDataChecksums) {
+		version, _ := e.instance.GetPgVersion()
+		if err := collectPGDataChecksums(e, db, version.Major); err != nil {
+			log.Error(err, "while collecting data checksum failures")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGDataChecksums").Inc()
+			e.Metrics.DataChecksumsEnabled.Reset()
+			e.Metrics.DatabaseChecksumFailures.Reset()
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorWALGenerationRate) {
+		if err := collectPGWALGenerationRate(e, db, isPrimary, time.Now()); err != nil {
+			log.Error(err, "while collecting WAL generation rate")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALGenerationRate").Inc()
+			e.walRate.reset()
+			e.Metrics.WALGenerationRate.Set(0)
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorWALArchiveStatus) {
+		if err := collectPGWalArchiveMetric(e); err != nil {
+			log.Error(err, "while collecting WAL archive metrics", "path", specs.PgWalArchiveStatusPath)
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PgWALArchiveStats").Inc()
+			e.Metrics.PgWALArchiveStatus.Reset()
+		}
+
+		if err := collectPGWALReadyCount(e, specs.PgWalArchiveStatusPath, isPrimary); err != nil {
+			log.Error(err, "while collecting the WAL files ready to be archived", "path", specs.PgWalArchiveStatusPath)
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALReadyCount").Inc()
+			e.Metrics.WALReadyCount.Set(0)
+		}
+
+		if cluster, err := cache.LoadClusterUnsafe(); err == nil {
+			if err := collectPGWALArchivePendingCount(e, specs.PgWalArchivePendingPath, cluster); err != nil {
+				log.Error(err, "while collecting the queued WAL files", "path", specs.PgWalArchivePendingPath)
+				e.Metrics.Error.Set(1)
+				e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALArchivePendingCount").Inc()
+				e.Metrics.WALArchivePendingCount.Reset()
+			}
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorWALDirectory) {
+		if err := collectPGWalSettings(e, db); err != nil {
+			log.Error(err, "while collecting WAL settings", "path", specs.PgWalPath)
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWalSettings").Inc()
+			e.Metrics.PgWALDirectory.Reset()
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorReplicationSlots) {
+		if err := collectPGReplicationSlotsRetainedWAL(e, db); err != nil {
+			log.Error(err, "while collecting replication slots retained WAL")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGReplicationSlotsRetainedWAL").Inc()
+			e.Metrics.ReplicationSlotsRetainedWAL.Reset()
+		}
+		if err := collectPGReplicationSlotsUsage(e, db); err != nil {
+			log.Error(err, "while collecting replication slots usage")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGReplicationSlotsUsage").Inc()
+			e.Metrics.ReplicationSlotsUsed.Set(0)
+			e.Metrics.ReplicationSlotsMax.Set(0)
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorIdleInTransaction) {
+		if err := collectPGIdleInTransactionSessions(e, db); err != nil {
+			log.Error(err, "while collecting idle in transaction sessions")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGIdleInTransactionSessions").Inc()
+			e.Metrics.IdleInTransactionSessions.Reset()
+			e.Metrics.IdleInTransactionOldestAge.Reset()
+		}
+	}
+
+	if !isCollectorDisabled(apiv1.CollectorLongestRunningQuery) {
+		if err := collectPGLongestRunningQuery(e, db); err != nil {
+			log.Error(err, "while collecting the longest running queries")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGLongestRunningQuery").Inc()
+			e.Metrics.LongestRunningQuery.Reset()
+		}
+	}
+
+	// the transaction IDs are frozen by vacuum on the primary, and
+	// replicas just mirror it
+	if !isPrimary {
+		e.Metrics.TableXidAge.Reset()
+	} else if !isCollectorDisabled(apiv1.CollectorTableXidAge) {
+		connect := func(database string) (*sql.DB, error) {
+			return e.instance.MonitoringConnectionPool().Connection(context.Background(), database)
+		}
+		if err := collectPGTableXidAge(e, db, connect, tableXidAgeLimit); err != nil {
+			log.Error(err, "while collecting the transaction ID age of the tables")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGTableXidAge").Inc()
+			e.Metrics.TableXidAge.Reset()
+		}
+	}
+
+	if limit := getTopStatementsLimit(); limit > 0 {
+		version, _ := e.instance.GetPgVersion()
+		if err := collectPGStatStatements(e, db, version.Major, limit); err != nil {
+			log.Error(err, "while collecting the top statements")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGStatStatements").Inc()
+			e.resetTopStatements()
+		}
+	} else {
+		e.resetTopStatements()
 	}
 
 	if err := collectPGVersion(e); err != nil {
@@ -416,7 +828,7 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.PgVersion.Reset()
 	}
 
-	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
+	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 && !isCollectorDisabled(apiv1.CollectorPgStatWAL) {
 		if err := collectPGWALStat(e); err != nil {
 			log.Error(err, "while collecting pg_wal_stat")
 			e.Metrics.Error.Set(1)
@@ -27,6 +27,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	m "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
 	postgresconf "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -237,6 +238,141 @@ var _ = Describe("ensure timestamp metric it's set properly", func() {
 	})
 })
 
+var _ = Describe("disabled default collectors", func() {
+	const (
+		cacheHitRatioName = "cnpg_pg_cache_hit_ratio"
+		databaseSizeName  = "cnpg_pg_database_size_bytes"
+	)
+
+	var (
+		exporter *Exporter
+		registry *prometheus.Registry
+	)
+
+	BeforeEach(func() {
+		cache.Delete(cache.ClusterKey)
+		DeferCleanup(cache.Delete, cache.ClusterKey)
+
+		// avoid connecting to PostgreSQL while collecting the metrics
+		instance := postgres.NewInstance()
+		instance.SetMightBeUnavailable(true)
+		exporter = NewExporter(instance)
+		exporter.Metrics.CacheHitRatio.WithLabelValues("app").Set(0.99)
+		exporter.Metrics.DatabaseSize.WithLabelValues("app").Set(8192)
+
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exporter)
+	})
+
+	It("exposes the metrics of all the collectors by default", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, cacheHitRatioName)).ToNot(BeNil())
+		Expect(getMetric(metrics, databaseSizeName)).ToNot(BeNil())
+	})
+
+	It("doesn't expose the metrics of the disabled collectors", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					DisableDefaultCollectors: []string{apiv1.CollectorCacheHitRatio, "bloat"},
+				},
+			},
+		})
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, cacheHitRatioName)).To(BeNil())
+		Expect(getMetric(metrics, databaseSizeName)).ToNot(BeNil())
+	})
+
+	It("doesn't describe the metrics of the disabled collectors", func() {
+		describedNames := func() []string {
+			ch := make(chan *prometheus.Desc, 1000)
+			exporter.Describe(ch)
+			close(ch)
+
+			var names []string
+			for desc := range ch {
+				names = append(names, desc.String())
+			}
+			return names
+		}
+
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+		Expect(describedNames()).To(ContainElement(ContainSubstring(`"` + cacheHitRatioName + `"`)))
+
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					DisableDefaultCollectors: []string{apiv1.CollectorCacheHitRatio},
+				},
+			},
+		})
+		names := describedNames()
+		Expect(names).ToNot(ContainElement(ContainSubstring(`"` + cacheHitRatioName + `"`)))
+		Expect(names).To(ContainElement(ContainSubstring(`"` + databaseSizeName + `"`)))
+	})
+
+	It("doesn't expose the built-in metrics still defined by the custom queries", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+
+		queries := m.NewQueriesCollector(PrometheusNamespace, exporter.instance, "postgres")
+		Expect(queries.ParseQueries([]byte(`
+pg_database:
+  query: "SELECT datname, pg_database_size(datname) AS size_bytes FROM pg_database"
+  metrics:
+    - datname:
+        usage: "LABEL"
+        description: "Name of the database"
+    - size_bytes:
+        usage: "GAUGE"
+        description: "Disk space used by the database"
+`))).To(Succeed())
+		exporter.SetCustomQueries(queries)
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, databaseSizeName)).To(BeNil())
+		Expect(getMetric(metrics, cacheHitRatioName)).ToNot(BeNil())
+	})
+
+	It("doesn't expose the temporary files counters still defined by the custom queries", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+		exporter.Metrics.DatabaseTempFiles.Set("app", 12)
+		exporter.Metrics.DatabaseTempBytes.Set("app", 2048)
+
+		queries := m.NewQueriesCollector(PrometheusNamespace, exporter.instance, "postgres")
+		Expect(queries.ParseQueries([]byte(`
+pg_stat_database:
+  query: "SELECT datname, temp_files FROM pg_catalog.pg_stat_database"
+  metrics:
+    - datname:
+        usage: "LABEL"
+        description: "Name of the database"
+    - temp_files:
+        usage: "COUNTER"
+        description: "Number of temporary files created by queries in this database"
+`))).To(Succeed())
+		exporter.SetCustomQueries(queries)
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, "cnpg_pg_stat_database_temp_files")).To(BeNil())
+		Expect(getMetric(metrics, "cnpg_pg_stat_database_temp_bytes")).ToNot(BeNil())
+	})
+
+	It("names all the collectors that can be disabled", func() {
+		names := []string{apiv1.CollectorPgStatWAL}
+		for name := range exporter.defaultCollectorsMetrics() {
+			names = append(names, name)
+		}
+		Expect(names).To(ConsistOf(apiv1.DefaultCollectors))
+	})
+})
+
 type nameGetter interface {
 	GetName() string
 }
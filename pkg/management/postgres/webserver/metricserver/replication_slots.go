@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"math"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// replicationSlotsRetainedWALQuery computes, for each replication slot, the
+// amount of WAL that is kept by the instance because of the slot. The value
+// is NULL for the slots that have never reserved WAL
+const replicationSlotsRetainedWALQuery = `SELECT slot_name,
+  slot_type,
+  coalesce(database, '') AS database,
+  (CASE pg_catalog.pg_is_in_recovery()
+    WHEN TRUE THEN pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_last_wal_receive_lsn(), restart_lsn)
+    ELSE pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), restart_lsn)
+  END) AS retained_wal_bytes
+FROM pg_catalog.pg_replication_slots`
+
+func collectPGReplicationSlotsRetainedWAL(e *Exporter, db *sql.DB) error {
+	rows, err := db.Query(replicationSlotsRetainedWALQuery)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for collectPGReplicationSlotsRetainedWAL")
+		}
+	}()
+
+	// slots can be dropped at any time, let's report only the existing ones
+	e.Metrics.ReplicationSlotsRetainedWAL.Reset()
+	for rows.Next() {
+		var slotName, slotType, database string
+		var retainedWALBytes sql.NullFloat64
+		if err := rows.Scan(&slotName, &slotType, &database, &retainedWALBytes); err != nil {
+			return err
+		}
+
+		// slots with a NULL restart_lsn don't retain any WAL yet
+		value := math.NaN()
+		if retainedWALBytes.Valid {
+			value = retainedWALBytes.Float64
+		}
+		e.Metrics.ReplicationSlotsRetainedWAL.WithLabelValues(slotName, slotType, database).Set(value)
+	}
+
+	return rows.Err()
+}
+
+// replicationSlotsUsageQuery reads the number of existing replication slots
+// and the maximum number of slots allowed by the configuration
+const replicationSlotsUsageQuery = `SELECT
+  (SELECT count(*) FROM pg_catalog.pg_replication_slots)::int,
+  pg_catalog.current_setting('max_replication_slots')::int`
+
+// collectPGReplicationSlotsUsage reports how many replication slots are used,
+// compared to the limit set by max_replication_slots
+func collectPGReplicationSlotsUsage(e *Exporter, db *sql.DB) error {
+	var used, maxSlots float64
+	if err := db.QueryRow(replicationSlotsUsageQuery).Scan(&used, &maxSlots); err != nil {
+		return err
+	}
+
+	e.Metrics.ReplicationSlotsUsed.Set(used)
+	e.Metrics.ReplicationSlotsMax.Set(maxSlots)
+
+	return nil
+}
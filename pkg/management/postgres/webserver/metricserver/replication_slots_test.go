@@ -0,0 +1,133 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"math"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication slots retained WAL metric", func() {
+	var exporter *Exporter
+
+	BeforeEach(func() {
+		exporter = NewExporter(postgres.NewInstance())
+	})
+
+	gatherRetainedWAL := func() map[string]float64 {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.ReplicationSlotsRetainedWAL)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		result := make(map[string]float64)
+		for _, family := range families {
+			Expect(family.GetName()).To(Equal("cnpg_pg_replication_slots_retained_wal_bytes"))
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "slot_name" {
+						result[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return result
+	}
+
+	It("reports the retained WAL of each slot, and NaN for slots without restart_lsn", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(replicationSlotsRetainedWALQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "retained_wal_bytes"}).
+				AddRow("_cnpg_cluster_example_2", "physical", "", 16777216).
+				AddRow("logical_slot", "logical", "app", 1024).
+				AddRow("unused_slot", "physical", "", nil))
+
+		Expect(collectPGReplicationSlotsRetainedWAL(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		values := gatherRetainedWAL()
+		Expect(values).To(HaveLen(3))
+		Expect(values["_cnpg_cluster_example_2"]).To(BeEquivalentTo(16777216))
+		Expect(values["logical_slot"]).To(BeEquivalentTo(1024))
+		Expect(math.IsNaN(values["unused_slot"])).To(BeTrue())
+	})
+
+	It("doesn't report the slots that have been dropped", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		exporter.Metrics.ReplicationSlotsRetainedWAL.WithLabelValues("dropped_slot", "physical", "").Set(1)
+		mock.ExpectQuery(replicationSlotsRetainedWALQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"slot_name", "slot_type", "database", "retained_wal_bytes"}))
+
+		Expect(collectPGReplicationSlotsRetainedWAL(exporter, db)).To(Succeed())
+		Expect(gatherRetainedWAL()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replication slots usage metrics", func() {
+	var exporter *Exporter
+
+	BeforeEach(func() {
+		exporter = NewExporter(postgres.NewInstance())
+	})
+
+	It("reports the used replication slots and the configured maximum", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(replicationSlotsUsageQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "current_setting"}).AddRow(9, 10))
+
+		Expect(collectPGReplicationSlotsUsage(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.ReplicationSlotsUsed)).To(BeEquivalentTo(9))
+		Expect(testutil.ToFloat64(exporter.Metrics.ReplicationSlotsMax)).To(BeEquivalentTo(10))
+	})
+
+	It("returns the error when the query fails", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(replicationSlotsUsageQuery).WillReturnError(sql.ErrConnDone)
+
+		Expect(collectPGReplicationSlotsUsage(exporter, db)).To(MatchError(sql.ErrConnDone))
+	})
+})
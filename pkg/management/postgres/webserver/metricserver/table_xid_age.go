@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// tableXidAgeLimit is the maximum number of tables whose transaction ID
+// age is reported, to bound the cardinality of the metric
+const tableXidAgeLimit = 10
+
+// connectableDatabasesQuery lists the databases whose tables can be
+// inspected. template0 doesn't accept connections, and is frozen anyway
+const connectableDatabasesQuery = `SELECT datname
+FROM pg_catalog.pg_database
+WHERE datallowconn`
+
+// tableXidAgeQuery reads the tables of the current database having the
+// oldest unfrozen transaction ID. Only the relation kinds having a
+// relfrozenxid are considered: tables, materialized views and TOAST tables
+const tableXidAgeQuery = `SELECT pg_catalog.current_database(), n.nspname, c.relname,
+  pg_catalog.age(c.relfrozenxid)
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'm', 't')
+ORDER BY 4 DESC
+LIMIT $1`
+
+// tableXidAge is the age of the oldest unfrozen transaction ID of a table
+type tableXidAge struct {
+	database string
+	schema   string
+	table    string
+	age      float64
+}
+
+// selectOldestTables gets the tables having the highest transaction ID
+// age among the passed ones, never returning more than limit tables
+func selectOldestTables(tables []tableXidAge, limit int) []tableXidAge {
+	result := make([]tableXidAge, len(tables))
+	copy(result, tables)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].age > result[j].age
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
+
+// getConnectableDatabases lists the databases accepting connections
+func getConnectableDatabases(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(connectableDatabasesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getConnectableDatabases")
+		}
+	}()
+
+	var result []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		result = append(result, database)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getTablesXidAge reads the tables of a database having the highest
+// transaction ID age, up to limit tables
+func getTablesXidAge(db *sql.DB, limit int) ([]tableXidAge, error) {
+	rows, err := db.Query(tableXidAgeQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getTablesXidAge")
+		}
+	}()
+
+	var result []tableXidAge
+	for rows.Next() {
+		var item tableXidAge
+		if err := rows.Scan(&item.database, &item.schema, &item.table, &item.age); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// collectPGTableXidAge reports the tables of the whole instance having
+// the highest transaction ID age. Every database is inspected through
+// the connection returned by connect, as pg_class is local to each of them
+func collectPGTableXidAge(
+	e *Exporter,
+	db *sql.DB,
+	connect func(database string) (*sql.DB, error),
+	limit int,
+) error {
+	databases, err := getConnectableDatabases(db)
+	if err != nil {
+		return err
+	}
+
+	var tables []tableXidAge
+	for _, database := range databases {
+		conn, err := connect(database)
+		if err != nil {
+			return err
+		}
+
+		databaseTables, err := getTablesXidAge(conn, limit)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, databaseTables...)
+	}
+
+	// the oldest tables change as they get frozen, and tables can be
+	// dropped: let's report only the current ones
+	e.Metrics.TableXidAge.Reset()
+	for _, item := range selectOldestTables(tables, limit) {
+		e.Metrics.TableXidAge.WithLabelValues(item.database, item.schema, item.table).Set(item.age)
+	}
+
+	return nil
+}
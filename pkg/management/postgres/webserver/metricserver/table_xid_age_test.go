@@ -0,0 +1,112 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("table transaction ID age metric", func() {
+	columns := []string{"current_database", "nspname", "relname", "age"}
+
+	newMock := func() (*sql.DB, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+		return db, mock
+	}
+
+	It("selects the tables having the highest age", func() {
+		tables := selectOldestTables([]tableXidAge{
+			{database: "app", schema: "public", table: "a", age: 100},
+			{database: "app", schema: "public", table: "b", age: 3000},
+			{database: "postgres", schema: "public", table: "c", age: 200},
+			{database: "app", schema: "pg_toast", table: "pg_toast_1", age: 1500},
+		}, 3)
+
+		Expect(tables).To(Equal([]tableXidAge{
+			{database: "app", schema: "public", table: "b", age: 3000},
+			{database: "app", schema: "pg_toast", table: "pg_toast_1", age: 1500},
+			{database: "postgres", schema: "public", table: "c", age: 200},
+		}))
+		Expect(selectOldestTables(nil, 3)).To(BeEmpty())
+	})
+
+	It("exports the oldest tables of all the databases", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		exporter.Metrics.TableXidAge.WithLabelValues("app", "public", "dropped").Set(100)
+
+		db, mock := newMock()
+		appDB, appMock := newMock()
+		mock.ExpectQuery(connectableDatabasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("postgres").AddRow("app"))
+		mock.ExpectQuery(tableXidAgeQuery).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("postgres", "pg_catalog", "pg_class", 50.0).
+				AddRow("postgres", "pg_catalog", "pg_proc", 40.0))
+		appMock.ExpectQuery(tableXidAgeQuery).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("app", "public", "orders", 90000.0).
+				AddRow("app", "public", "customers", 45.0))
+
+		connect := func(database string) (*sql.DB, error) {
+			if database == "app" {
+				return appDB, nil
+			}
+			return db, nil
+		}
+
+		Expect(collectPGTableXidAge(exporter, db, connect, 2)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(appMock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(testutil.CollectAndCount(exporter.Metrics.TableXidAge)).To(Equal(2))
+		Expect(testutil.ToFloat64(exporter.Metrics.TableXidAge.WithLabelValues("app", "public", "orders"))).
+			To(BeEquivalentTo(90000))
+		Expect(testutil.ToFloat64(exporter.Metrics.TableXidAge.WithLabelValues("postgres", "pg_catalog", "pg_class"))).
+			To(BeEquivalentTo(50))
+	})
+
+	It("fails when a database can't be inspected", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		exporter.Metrics.TableXidAge.WithLabelValues("app", "public", "orders").Set(100)
+
+		db, mock := newMock()
+		mock.ExpectQuery(connectableDatabasesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app"))
+
+		connect := func(string) (*sql.DB, error) {
+			return nil, errors.New("connection refused")
+		}
+
+		Expect(collectPGTableXidAge(exporter, db, connect, tableXidAgeLimit)).ToNot(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
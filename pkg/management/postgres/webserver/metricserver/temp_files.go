@@ -0,0 +1,134 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// databaseTempFilesQuery reads, for each database, the number and the size
+// of the temporary files created by the queries exceeding work_mem.
+// The row with a NULL name, related to the shared objects, is skipped
+const databaseTempFilesQuery = `SELECT datname, temp_files, temp_bytes
+FROM pg_catalog.pg_stat_database
+WHERE datname IS NOT NULL`
+
+// databaseCounter exports a cumulative statistic of each database as a
+// counter. PostgreSQL accumulates these statistics until they are reset,
+// and a gauge would prevent using rate() over them
+type databaseCounter struct {
+	desc *prometheus.Desc
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func newDatabaseCounter(subsystem, name, help string) *databaseCounter {
+	return &databaseCounter{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(PrometheusNamespace, subsystem, name),
+			help,
+			[]string{"datname"}, nil),
+		values: make(map[string]float64),
+	}
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *databaseCounter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *databaseCounter) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for database, value := range c.values {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, value, database)
+	}
+}
+
+// Set sets the value of the statistic for a database
+func (c *databaseCounter) Set(database string, value float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[database] = value
+}
+
+// Reset removes the values of all the databases
+func (c *databaseCounter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values = make(map[string]float64)
+}
+
+// databaseTempFiles is the temporary files usage of a database
+type databaseTempFiles struct {
+	database string
+	files    float64
+	bytes    float64
+}
+
+// getDatabaseTempFiles reads the temporary files usage of the databases
+func getDatabaseTempFiles(db *sql.DB) ([]databaseTempFiles, error) {
+	rows, err := db.Query(databaseTempFilesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getDatabaseTempFiles")
+		}
+	}()
+
+	var result []databaseTempFiles
+	for rows.Next() {
+		var item databaseTempFiles
+		if err := rows.Scan(&item.database, &item.files, &item.bytes); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func collectPGStatDatabaseTempFiles(e *Exporter, db *sql.DB) error {
+	tempFiles, err := getDatabaseTempFiles(db)
+	if err != nil {
+		return err
+	}
+
+	return reportExistingDatabases(func() error {
+		for _, item := range tempFiles {
+			e.Metrics.DatabaseTempFiles.Set(item.database, item.files)
+			e.Metrics.DatabaseTempBytes.Set(item.database, item.bytes)
+		}
+		return nil
+	}, e.Metrics.DatabaseTempFiles, e.Metrics.DatabaseTempBytes)
+}
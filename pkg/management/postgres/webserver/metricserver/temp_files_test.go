@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("temporary files metrics", func() {
+	tempFilesColumns := []string{"datname", "temp_files", "temp_bytes"}
+
+	It("parses the temporary files usage of each database", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseTempFilesQuery).
+			WillReturnRows(sqlmock.NewRows(tempFilesColumns).
+				AddRow("app", 12, 104857600).
+				AddRow("postgres", 0, 0))
+
+		tempFiles, err := getDatabaseTempFiles(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(tempFiles).To(ConsistOf(
+			databaseTempFiles{database: "app", files: 12, bytes: 104857600},
+			databaseTempFiles{database: "postgres", files: 0, bytes: 0},
+		))
+	})
+
+	It("reports the temporary files usage by database", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseTempFilesQuery).
+			WillReturnRows(sqlmock.NewRows(tempFilesColumns).
+				AddRow("app", 12, 104857600).
+				AddRow("reports", 3, 2048))
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGStatDatabaseTempFiles(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.DatabaseTempFiles, exporter.Metrics.DatabaseTempBytes)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveLen(2))
+		for _, family := range families {
+			Expect(family.GetType().String()).To(Equal("COUNTER"))
+		}
+
+		values := make(map[string]map[string]float64)
+		for _, family := range families {
+			values[family.GetName()] = make(map[string]float64)
+			for _, metric := range family.GetMetric() {
+				Expect(metric.GetLabel()).To(HaveLen(1))
+				Expect(metric.GetLabel()[0].GetName()).To(Equal("datname"))
+				values[family.GetName()][metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+			}
+		}
+		Expect(values).To(Equal(map[string]map[string]float64{
+			"cnpg_pg_stat_database_temp_files": {"app": 12, "reports": 3},
+			"cnpg_pg_stat_database_temp_bytes": {"app": 104857600, "reports": 2048},
+		}))
+	})
+
+	It("returns an error when the view can't be read", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(databaseTempFilesQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGStatDatabaseTempFiles(exporter, db)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,184 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// pgStatStatementsSchemaQuery finds the schema where the pg_stat_statements
+// extension has been created in the database
+const pgStatStatementsSchemaQuery = `SELECT n.nspname
+FROM pg_catalog.pg_extension e
+JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+WHERE e.extname = 'pg_stat_statements'`
+
+// topStatementsLabels are the labels identifying a statement in the
+// pg_stat_statements metrics
+var topStatementsLabels = []string{"queryid", "datname", "usename", "query"}
+
+// maxQueryLabelLength is the maximum number of characters of the
+// text of the statements reported in the query label
+const maxQueryLabelLength = 64
+
+var (
+	// stringLiteralRegex matches the string constants, including the
+	// dollar-quoted ones, that pg_stat_statements doesn't normalize in
+	// the utility statements
+	stringLiteralRegex = regexp.MustCompile(`(?s)'(?:[^']|'')*'|\$\$.*?\$\$`)
+
+	whitespacesRegex = regexp.MustCompile(`\s+`)
+)
+
+// topStatement contains the statistics of a statement
+type topStatement struct {
+	queryID       string
+	database      string
+	user          string
+	query         string
+	calls         float64
+	rows          float64
+	totalExecTime float64
+	meanExecTime  float64
+}
+
+// getTopStatementsLimit gets the number of top statements to be reported,
+// zero if their metrics are not enabled
+func getTopStatementsLimit() int {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return 0
+	}
+
+	return cluster.Spec.Monitoring.GetTopStatementsLimit()
+}
+
+// buildTopStatementsQuery builds the query reading the statements having
+// the highest total execution time. The timing columns have been renamed
+// in PostgreSQL 13
+func buildTopStatementsQuery(schema string, majorVersion uint64) string {
+	totalTimeColumn, meanTimeColumn := "total_exec_time", "mean_exec_time"
+	if majorVersion < 13 {
+		totalTimeColumn, meanTimeColumn = "total_time", "mean_time"
+	}
+
+	return fmt.Sprintf(`SELECT s.queryid::text, d.datname, r.rolname, s.query,
+  s.calls, s.rows, s.%[2]s, s.%[3]s
+FROM %[1]s s
+JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+JOIN pg_catalog.pg_roles r ON r.oid = s.userid
+WHERE s.queryid IS NOT NULL
+ORDER BY s.%[2]s DESC
+LIMIT $1`, pgx.Identifier{schema, "pg_stat_statements"}.Sanitize(), totalTimeColumn, meanTimeColumn)
+}
+
+// getTopStatements reads the statistics of the statements having the
+// highest total execution time, returning no statements when the
+// pg_stat_statements extension is not available yet
+func getTopStatements(db *sql.DB, majorVersion uint64, limit int) ([]topStatement, error) {
+	var schema string
+	err := db.QueryRow(pgStatStatementsSchemaQuery).Scan(&schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(buildTopStatementsQuery(schema, majorVersion), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err, "while closing rows for getTopStatements")
+		}
+	}()
+
+	var result []topStatement
+	for rows.Next() {
+		var item topStatement
+		if err := rows.Scan(
+			&item.queryID, &item.database, &item.user, &item.query,
+			&item.calls, &item.rows, &item.totalExecTime, &item.meanExecTime,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// never report more statements than requested
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// boundQueryLabel makes the text of a statement suitable for a label,
+// collapsing the whitespaces, hiding the string constants and truncating
+// it, so that it doesn't leak data and its size is bounded
+func boundQueryLabel(query string) string {
+	query = stringLiteralRegex.ReplaceAllString(query, "'?'")
+	query = whitespacesRegex.ReplaceAllString(strings.TrimSpace(query), " ")
+
+	if runes := []rune(query); len(runes) > maxQueryLabelLength {
+		return string(runes[:maxQueryLabelLength]) + "..."
+	}
+
+	return query
+}
+
+// resetTopStatements stops reporting the metrics of the top statements
+func (e *Exporter) resetTopStatements() {
+	e.Metrics.TopStatementsCalls.Reset()
+	e.Metrics.TopStatementsRows.Reset()
+	e.Metrics.TopStatementsTotalExecTime.Reset()
+	e.Metrics.TopStatementsMeanExecTime.Reset()
+}
+
+func collectPGStatStatements(e *Exporter, db *sql.DB, majorVersion uint64, limit int) error {
+	statements, err := getTopStatements(db, majorVersion, limit)
+	if err != nil {
+		return err
+	}
+
+	// the top statements change over time, let's report only the current ones
+	e.resetTopStatements()
+	for _, item := range statements {
+		labels := []string{item.queryID, item.database, item.user, boundQueryLabel(item.query)}
+		e.Metrics.TopStatementsCalls.WithLabelValues(labels...).Set(item.calls)
+		e.Metrics.TopStatementsRows.WithLabelValues(labels...).Set(item.rows)
+		e.Metrics.TopStatementsTotalExecTime.WithLabelValues(labels...).Set(item.totalExecTime)
+		e.Metrics.TopStatementsMeanExecTime.WithLabelValues(labels...).Set(item.meanExecTime)
+	}
+
+	return nil
+}
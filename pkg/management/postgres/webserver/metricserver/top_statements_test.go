@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"strings"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("top statements metrics", func() {
+	var (
+		exporter *Exporter
+		mock     sqlmock.Sqlmock
+	)
+
+	columns := []string{"queryid", "datname", "rolname", "query", "calls", "rows", "total_exec_time", "mean_exec_time"}
+
+	newCollector := func() func(majorVersion uint64, limit int) error {
+		exporter = NewExporter(postgres.NewInstance())
+		db, dbMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+		mock = dbMock
+
+		return func(majorVersion uint64, limit int) error {
+			return collectPGStatStatements(exporter, db, majorVersion, limit)
+		}
+	}
+
+	It("exports the statements with the highest total execution time", func() {
+		collect := newCollector()
+		exporter.Metrics.TopStatementsCalls.WithLabelValues("1", "app", "app", "dropped").Set(100)
+
+		mock.ExpectQuery(pgStatStatementsSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+		mock.ExpectQuery(buildTopStatementsQuery("public", 16)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("11", "app", "app", "SELECT * FROM t WHERE id = $1", 90.0, 90.0, 900.0, 10.0).
+				AddRow("12", "app", "app", "UPDATE t SET v = $1", 10.0, 20.0, 100.0, 10.0).
+				AddRow("13", "app", "app", "SELECT 1", 1.0, 1.0, 1.0, 1.0))
+
+		Expect(collect(16, 2)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(testutil.CollectAndCount(exporter.Metrics.TopStatementsCalls)).To(Equal(2))
+		Expect(testutil.CollectAndCount(exporter.Metrics.TopStatementsMeanExecTime)).To(Equal(2))
+		labels := []string{"11", "app", "app", "SELECT * FROM t WHERE id = $1"}
+		Expect(testutil.ToFloat64(exporter.Metrics.TopStatementsCalls.WithLabelValues(labels...))).
+			To(BeEquivalentTo(90))
+		Expect(testutil.ToFloat64(exporter.Metrics.TopStatementsTotalExecTime.WithLabelValues(labels...))).
+			To(BeEquivalentTo(900))
+	})
+
+	It("uses the timing columns of the PostgreSQL version", func() {
+		Expect(buildTopStatementsQuery("public", 12)).To(ContainSubstring("ORDER BY s.total_time DESC"))
+		Expect(buildTopStatementsQuery("public", 13)).To(ContainSubstring("ORDER BY s.total_exec_time DESC"))
+		Expect(buildTopStatementsQuery("my schema", 16)).To(ContainSubstring(`FROM "my schema"."pg_stat_statements"`))
+	})
+
+	It("exports nothing when the extension is not installed", func() {
+		collect := newCollector()
+		exporter.Metrics.TopStatementsRows.WithLabelValues("1", "app", "app", "dropped").Set(100)
+
+		mock.ExpectQuery(pgStatStatementsSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}))
+
+		Expect(collect(16, 10)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(testutil.CollectAndCount(exporter.Metrics.TopStatementsRows)).To(BeZero())
+	})
+
+	It("bounds the text of the statements used as label", func() {
+		Expect(boundQueryLabel("SELECT  *\n\tFROM t")).To(Equal("SELECT * FROM t"))
+		Expect(boundQueryLabel("ALTER ROLE app PASSWORD 'it''s secret'")).To(Equal("ALTER ROLE app PASSWORD '?'"))
+		Expect(boundQueryLabel("DO $$BEGIN PERFORM 'x'; END$$")).To(Equal("DO '?'"))
+
+		label := boundQueryLabel("SELECT " + strings.Repeat("a, ", 100) + "b FROM t")
+		Expect([]rune(label)).To(HaveLen(maxQueryLabelLength + len("...")))
+		Expect(label).To(HavePrefix("SELECT a, a,"))
+		Expect(label).To(HaveSuffix("..."))
+	})
+})
@@ -22,7 +22,9 @@ import (
 	"os"
 	"regexp"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -30,7 +32,7 @@ import (
 )
 
 func collectPGWalArchiveMetric(exporter *Exporter) error {
-	ready, done, err := postgres.GetWALArchiveCounters()
+	ready, done, err := postgres.GetWALArchiveCounters(specs.PgWalArchiveStatusPath)
 	if err != nil {
 		return err
 	}
@@ -40,6 +42,49 @@ func collectPGWalArchiveMetric(exporter *Exporter) error {
 	return nil
 }
 
+// collectPGWALReadyCount reports the number of WAL files the primary is
+// waiting to archive. A rising value means that the `archive_command`
+// can't keep up with the WAL generation
+func collectPGWALReadyCount(e *Exporter, archiveStatusPath string, isPrimary bool) error {
+	// replicas don't archive the WAL files they receive
+	if !isPrimary {
+		e.Metrics.WALReadyCount.Set(0)
+		return nil
+	}
+
+	ready, _, err := postgres.GetWALArchiveCounters(archiveStatusPath)
+	if err != nil {
+		return err
+	}
+
+	e.Metrics.WALReadyCount.Set(float64(ready))
+	return nil
+}
+
+// collectPGWALArchivePendingCount reports the number of WAL files queued
+// for each additional object store, after failing on it while the
+// archive quorum was reached. A growing queue means that the object
+// store is not receiving the WAL files anymore
+func collectPGWALArchivePendingCount(e *Exporter, pendingPath string, cluster *apiv1.Cluster) error {
+	e.Metrics.WALArchivePendingCount.Reset()
+
+	objectStores := cluster.Spec.Backup.GetWalObjectStores()
+	if len(objectStores) < 2 {
+		return nil
+	}
+
+	for _, objectStore := range objectStores {
+		destinationName := archiver.DestinationName(objectStore, cluster.Name)
+		count, err := archiver.CountPendingWALFiles(pendingPath, destinationName)
+		if err != nil {
+			return err
+		}
+		e.Metrics.WALArchivePendingCount.WithLabelValues(destinationName).Set(float64(count))
+	}
+
+	return nil
+}
+
 func collectPGWALStat(e *Exporter) error {
 	walStat, err := e.instance.TryGetPgStatWAL()
 	if walStat == nil || err != nil {
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	postgresconf "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// walPositionQuery reads the current WAL write location of the primary
+// together with its timeline, taken from the name of the current WAL file
+const walPositionQuery = `SELECT pg_catalog.pg_current_wal_lsn(),
+	substr(pg_catalog.pg_walfile_name(pg_catalog.pg_current_wal_lsn()), 1, 8)`
+
+// walPosition is the WAL write location of the primary at a certain time
+type walPosition struct {
+	lsn       int64
+	timeline  string
+	timestamp time.Time
+}
+
+// walRateTracker keeps the last WAL write location of the primary, and
+// uses it to compute the WAL generation rate between two collections
+type walRateTracker struct {
+	mu   sync.Mutex
+	last *walPosition
+}
+
+// observe records the passed WAL write location and returns the rate, in
+// bytes per second, at which the WAL was generated since the previous one.
+// The rate is zero when there is no previous location to compare with,
+// i.e. at the first collection or after a timeline change, as the two
+// locations are not comparable
+func (t *walRateTracker) observe(position walPosition) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.last
+	t.last = &position
+
+	if previous == nil || previous.timeline != position.timeline || position.lsn < previous.lsn {
+		return 0
+	}
+
+	elapsed := position.timestamp.Sub(previous.timestamp).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(position.lsn-previous.lsn) / elapsed
+}
+
+// reset forgets the last WAL write location, i.e. when the
+// instance is not a primary anymore
+func (t *walRateTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.last = nil
+}
+
+// getWALPosition reads the current WAL write location of the primary
+func getWALPosition(db *sql.DB, now time.Time) (walPosition, error) {
+	var lsn postgresconf.LSN
+	position := walPosition{timestamp: now}
+	if err := db.QueryRow(walPositionQuery).Scan(&lsn, &position.timeline); err != nil {
+		return walPosition{}, err
+	}
+
+	var err error
+	if position.lsn, err = lsn.Parse(); err != nil {
+		return walPosition{}, err
+	}
+
+	return position, nil
+}
+
+func collectPGWALGenerationRate(e *Exporter, db *sql.DB, isPrimary bool, now time.Time) error {
+	// replicas don't generate WAL
+	if !isPrimary {
+		e.walRate.reset()
+		e.Metrics.WALGenerationRate.Set(0)
+		return nil
+	}
+
+	position, err := getWALPosition(db, now)
+	if err != nil {
+		return err
+	}
+
+	e.Metrics.WALGenerationRate.Set(e.walRate.observe(position))
+	return nil
+}
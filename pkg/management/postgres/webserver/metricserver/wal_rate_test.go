@@ -0,0 +1,124 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL generation rate metric", func() {
+	positionColumns := []string{"pg_current_wal_lsn", "substr"}
+
+	It("reports the rate starting from the second collection on the primary", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(walPositionQuery).
+			WillReturnRows(sqlmock.NewRows(positionColumns).AddRow("0/3000000", "00000001"))
+		mock.ExpectQuery(walPositionQuery).
+			WillReturnRows(sqlmock.NewRows(positionColumns).AddRow("0/3F00000", "00000001"))
+
+		exporter := NewExporter(postgres.NewInstance())
+		now := time.Now()
+		Expect(collectPGWALGenerationRate(exporter, db, true, now)).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.WALGenerationRate)).To(BeZero())
+
+		Expect(collectPGWALGenerationRate(exporter, db, true, now.Add(15*time.Second))).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.WALGenerationRate)).To(BeEquivalentTo(0xF00000 / 15))
+	})
+
+	It("reports zero on replicas without querying them", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		exporter := NewExporter(postgres.NewInstance())
+		exporter.walRate.observe(walPosition{lsn: 100, timeline: "00000001", timestamp: time.Now()})
+		exporter.Metrics.WALGenerationRate.Set(42)
+
+		Expect(collectPGWALGenerationRate(exporter, db, false, time.Now())).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.WALGenerationRate)).To(BeZero())
+		Expect(exporter.walRate.last).To(BeNil())
+	})
+
+	It("returns an error when the WAL position can't be read", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+
+		mock.ExpectQuery(walPositionQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGWALGenerationRate(exporter, db, true, time.Now())).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("WAL rate tracker", func() {
+	start := time.Now()
+
+	It("computes the rate from the delta between two collections", func() {
+		tracker := &walRateTracker{}
+		Expect(tracker.observe(walPosition{lsn: 1000, timeline: "00000001", timestamp: start})).To(BeZero())
+		Expect(tracker.observe(walPosition{lsn: 4000, timeline: "00000001", timestamp: start.Add(30 * time.Second)})).
+			To(BeEquivalentTo(100))
+		Expect(tracker.observe(walPosition{lsn: 4000, timeline: "00000001", timestamp: start.Add(60 * time.Second)})).
+			To(BeZero())
+	})
+
+	It("doesn't report a spike after a timeline change", func() {
+		tracker := &walRateTracker{}
+		tracker.observe(walPosition{lsn: 1000, timeline: "00000001", timestamp: start})
+		Expect(tracker.observe(walPosition{lsn: 1 << 40, timeline: "00000002", timestamp: start.Add(time.Second)})).
+			To(BeZero())
+
+		// the new timeline is used as the baseline for the next collections
+		Expect(tracker.observe(walPosition{lsn: 1<<40 + 500, timeline: "00000002",
+			timestamp: start.Add(6 * time.Second)})).To(BeEquivalentTo(100))
+	})
+
+	It("doesn't report a negative rate when the LSN goes backwards", func() {
+		tracker := &walRateTracker{}
+		tracker.observe(walPosition{lsn: 5000, timeline: "00000001", timestamp: start})
+		Expect(tracker.observe(walPosition{lsn: 1000, timeline: "00000001", timestamp: start.Add(time.Second)})).
+			To(BeZero())
+		Expect(tracker.observe(walPosition{lsn: 2000, timeline: "00000001", timestamp: start.Add(2 * time.Second)})).
+			To(BeEquivalentTo(1000))
+	})
+
+	It("ignores the collections happening at the same time", func() {
+		tracker := &walRateTracker{}
+		tracker.observe(walPosition{lsn: 1000, timeline: "00000001", timestamp: start})
+		Expect(tracker.observe(walPosition{lsn: 2000, timeline: "00000001", timestamp: start})).To(BeZero())
+	})
+})
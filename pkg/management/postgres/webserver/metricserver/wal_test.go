@@ -18,10 +18,16 @@ package metricserver
 
 import (
 	"database/sql"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -118,3 +124,84 @@ IN ('wal_segment_size', 'min_wal_size', 'max_wal_size', 'wal_keep_size', 'wal_ke
 		Expect(settings.maxSlotWalKeepSize).To(Equal(maxSlotWalKeepSize))
 	})
 })
+
+var _ = Describe("WAL files ready to be archived metric", func() {
+	var archiveStatusPath string
+
+	BeforeEach(func() {
+		archiveStatusPath = GinkgoT().TempDir()
+		for _, fileName := range []string{
+			"000000010000000000000001.done",
+			"000000010000000000000002.done",
+			"000000010000000000000003.ready",
+			"000000010000000000000004.ready",
+			"00000002.history.ready",
+			"000000010000000000000005.ready.tmp",
+		} {
+			Expect(os.WriteFile(filepath.Join(archiveStatusPath, fileName), nil, 0o600)).To(Succeed())
+		}
+	})
+
+	It("reports the number of WAL files ready to be archived on the primary", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		Expect(collectPGWALReadyCount(exporter, archiveStatusPath, true)).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.WALReadyCount)).To(BeEquivalentTo(3))
+	})
+
+	It("reports zero when no WAL file is ready to be archived", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		exporter.Metrics.WALReadyCount.Set(3)
+		Expect(collectPGWALReadyCount(exporter, GinkgoT().TempDir(), true)).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.WALReadyCount)).To(BeZero())
+	})
+
+	It("reports zero on replicas", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		exporter.Metrics.WALReadyCount.Set(3)
+		Expect(collectPGWALReadyCount(exporter, archiveStatusPath, false)).To(Succeed())
+		Expect(testutil.ToFloat64(exporter.Metrics.WALReadyCount)).To(BeZero())
+	})
+
+	It("fails when the archive status directory can't be read", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		err := collectPGWALReadyCount(exporter, filepath.Join(archiveStatusPath, "missing"), true)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("WAL files queued for the additional object stores", func() {
+	var (
+		cluster  *apiv1.Cluster
+		exporter *Exporter
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket"},
+				},
+			},
+		}
+		exporter = NewExporter(postgres.NewInstance())
+	})
+
+	It("reports nothing with a single object store", func() {
+		Expect(collectPGWALArchivePendingCount(exporter, GinkgoT().TempDir(), cluster)).To(Succeed())
+		Expect(testutil.CollectAndCount(exporter.Metrics.WALArchivePendingCount)).To(BeZero())
+	})
+
+	It("reports the queue of every object store", func() {
+		cluster.Spec.Backup.AdditionalWalObjectStores = []apiv1.BarmanObjectStoreConfiguration{
+			{DestinationPath: "gs://bucket", ServerName: "cluster-dr"},
+		}
+
+		Expect(collectPGWALArchivePendingCount(exporter, GinkgoT().TempDir(), cluster)).To(Succeed())
+		Expect(testutil.CollectAndCount(exporter.Metrics.WALArchivePendingCount)).To(Equal(2))
+		Expect(testutil.ToFloat64(
+			exporter.Metrics.WALArchivePendingCount.WithLabelValues("gs://bucket/cluster-dr"))).To(BeZero())
+		Expect(testutil.ToFloat64(
+			exporter.Metrics.WALArchivePendingCount.WithLabelValues("s3://bucket/cluster-example"))).To(BeZero())
+	})
+})
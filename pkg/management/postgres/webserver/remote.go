@@ -18,11 +18,12 @@ package webserver
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,9 +37,13 @@ import (
 )
 
 type remoteWebserverEndpoints struct {
-	typedClient   client.Client
-	instance      *postgres.Instance
-	currentBackup *backupConnection
+	typedClient client.Client
+	instance    *postgres.Instance
+
+	// currentBackupMu protects the current backup, which is replaced by
+	// the requests of the operator and checked by keepBackupAliveConn
+	currentBackupMu sync.Mutex
+	currentBackup   *backupConnection
 }
 
 // StartBackupRequest the required data to execute the pg_start_backup
@@ -47,6 +52,9 @@ type StartBackupRequest struct {
 	WaitForArchive      bool   `json:"waitForArchive"`
 	BackupName          string `json:"backupName"`
 	Force               bool   `json:"force,omitempty"`
+	// The number of seconds the backup is kept started waiting for the
+	// request to stop it, before being aborted. Zero means no limit
+	HoldTimeoutSeconds int `json:"holdTimeoutSeconds,omitempty"`
 }
 
 // NewRemoteWebServer returns a webserver that allows connection from external clients
@@ -60,7 +68,7 @@ func NewRemoteWebServer(
 		return nil, fmt.Errorf("creating controller-runtine client: %v", err)
 	}
 
-	endpoints := remoteWebserverEndpoints{
+	endpoints := &remoteWebserverEndpoints{
 		typedClient: typedClient,
 		instance:    instance,
 	}
@@ -72,6 +80,8 @@ func NewRemoteWebServer(
 	serveMux.HandleFunc(url.PathReady, endpoints.isServerReady)
 	serveMux.HandleFunc(url.PathPgStatus, endpoints.pgStatus)
 	serveMux.HandleFunc(url.PathPGControlData, endpoints.pgControlData)
+	serveMux.HandleFunc(url.PathPgActivity, endpoints.pgActivity)
+	serveMux.HandleFunc(url.PathPgStatStatementsReset, endpoints.pgStatStatementsReset)
 	serveMux.HandleFunc(url.PathUpdate, endpoints.updateInstanceManager(cancelFunc, exitedConditions))
 
 	server := &http.Server{
@@ -171,6 +181,58 @@ func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http
 	_, _ = w.Write(res)
 }
 
+// pgActivity reports the active backends of the instance. The text of the
+// queries is redacted unless the cluster exposes it and the "includeQueries"
+// parameter is true
+func (ws *remoteWebserverEndpoints) pgActivity(w http.ResponseWriter, req *http.Request) {
+	includeQueries, _ := strconv.ParseBool(req.URL.Query().Get("includeQueries"))
+
+	report, err := ws.instance.GetActivity(includeQueries)
+	if err != nil {
+		log.Debug(
+			"Instance activity endpoint failing",
+			"err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res, err := json.Marshal(report)
+	if err != nil {
+		log.Warning(
+			"Internal error marshalling activity response",
+			"err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(res)
+}
+
+// pgStatStatementsReset discards the statistics gathered by
+// pg_stat_statements on the primary instance
+func (ws *remoteWebserverEndpoints) pgStatStatementsReset(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := ws.instance.ResetStatStatements()
+	switch {
+	case errors.Is(err, postgres.ErrStatStatementsResetOnReplica):
+		sendBadRequestJSONResponse(w, "NOT_PRIMARY", err.Error())
+	case errors.Is(err, postgres.ErrStatStatementsNotInstalled):
+		sendBadRequestJSONResponse(w, "EXTENSION_NOT_INSTALLED", err.Error())
+	case err != nil:
+		log.Warning(
+			"Instance pg_stat_statements reset endpoint failing",
+			"err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		sendDataJSONResponse(w, http.StatusOK, struct{}{})
+	}
+}
+
 // updateInstanceManager replace the instance with one in the
 // new binary
 func (ws *remoteWebserverEndpoints) updateInstanceManager(
@@ -210,17 +272,21 @@ func (ws *remoteWebserverEndpoints) updateInstanceManager(
 func (ws *remoteWebserverEndpoints) backup(w http.ResponseWriter, req *http.Request) {
 	log.Trace("request method", "method", req.Method)
 
+	ws.currentBackupMu.Lock()
+	defer ws.currentBackupMu.Unlock()
+
 	switch req.Method {
 	case http.MethodGet:
 		if ws.currentBackup == nil {
 			sendDataJSONResponse(w, 200, struct{}{})
 			return
 		}
-		if ws.currentBackup.err != nil {
-			sendBadRequestJSONResponse(w, "ERROR_WHILE_PROCESSING", ws.currentBackup.err.Error())
+		data, backupErr := ws.currentBackup.getStatus()
+		if backupErr != nil {
+			sendBadRequestJSONResponse(w, "ERROR_WHILE_PROCESSING", backupErr.Error())
 			return
 		}
-		sendDataJSONResponse(w, 200, ws.currentBackup.data)
+		sendDataJSONResponse(w, 200, data)
 
 	case http.MethodPost:
 		var p StartBackupRequest
@@ -239,12 +305,7 @@ func (ws *remoteWebserverEndpoints) backup(w http.ResponseWriter, req *http.Requ
 				sendBadRequestJSONResponse(w, "PROCESS_ALREADY_RUNNING", "")
 				return
 			}
-			ws.currentBackup.data.Phase = Failed
-			if err := ws.currentBackup.conn.Close(); err != nil {
-				if !errors.Is(err, sql.ErrConnDone) {
-					log.Error(err, "Error while closing backup connection (start)")
-				}
-			}
+			ws.currentBackup.replace(req.Context())
 		}
 		ws.currentBackup, err = newBackupConnection(
 			req.Context(),
@@ -252,6 +313,7 @@ func (ws *remoteWebserverEndpoints) backup(w http.ResponseWriter, req *http.Requ
 			p.BackupName,
 			p.ImmediateCheckpoint,
 			p.WaitForArchive,
+			time.Duration(p.HoldTimeoutSeconds)*time.Second,
 		)
 		if err != nil {
 			sendBadRequestJSONResponse(w, "INITIALIZING_CONNECTION", err.Error())
@@ -266,31 +328,15 @@ func (ws *remoteWebserverEndpoints) backup(w http.ResponseWriter, req *http.Requ
 			return
 		}
 
-		if ws.currentBackup.data.Phase == Closing {
-			sendDataJSONResponse(w, 200, struct{}{})
-			return
-		}
-
-		if ws.currentBackup.data.Phase != Started {
-			sendBadRequestJSONResponse(w, "CANNOT_CLOSE_NOT_STARTED",
-				fmt.Sprintf("Phase is: %s", ws.currentBackup.data.Phase))
+		shouldStop, err := ws.currentBackup.requestStop(req.Context())
+		if err != nil {
+			sendBadRequestJSONResponse(w, "CANNOT_CLOSE_NOT_STARTED", err.Error())
 			return
 		}
 
-		if ws.currentBackup.err != nil {
-			if err := ws.currentBackup.conn.Close(); err != nil {
-				if !errors.Is(err, sql.ErrConnDone) {
-					log.Error(err, "Error while closing backup connection (stop)")
-				}
-			}
-
-			ws.currentBackup.data.Phase = Failed
-			sendDataJSONResponse(w, 200, struct{}{})
-			return
+		if shouldStop {
+			go ws.currentBackup.stopBackup(context.Background())
 		}
-
-		ws.currentBackup.data.Phase = Closing
-		go ws.currentBackup.stopBackup(context.Background())
 		sendDataJSONResponse(w, 200, struct{}{})
 	}
 }
@@ -298,10 +344,19 @@ func (ws *remoteWebserverEndpoints) backup(w http.ResponseWriter, req *http.Requ
 // TODO: no need to active ping, we are connected locally
 func (ws *remoteWebserverEndpoints) keepBackupAliveConn() {
 	for {
-		if ws.currentBackup != nil && ws.currentBackup.conn != nil &&
-			ws.currentBackup.err == nil && ws.currentBackup.data.Phase != Completed {
-			log.Trace("keeping current backup connection alive")
-			_ = ws.currentBackup.conn.PingContext(context.Background())
+		ws.currentBackupMu.Lock()
+		currentBackup := ws.currentBackup
+		ws.currentBackupMu.Unlock()
+
+		if currentBackup != nil && currentBackup.conn != nil {
+			data, backupErr := currentBackup.getStatus()
+			if backupErr == nil && data.Phase != Completed {
+				log.Trace("keeping current backup connection alive")
+				_ = currentBackup.conn.PingContext(context.Background())
+			}
+		}
+		if currentBackup.isHoldTimeoutExpired() {
+			currentBackup.abortBackup(context.Background(), errHoldTimeoutExpired)
 		}
 		time.Sleep(3 * time.Second)
 	}
@@ -43,12 +43,23 @@ const (
 	// PathPgStatus is the URL path for PostgreSQL Status
 	PathPgStatus string = "/pg/status"
 
+	// PathPgActivity is the URL path for the active backends of PostgreSQL
+	PathPgActivity string = "/pg/activity"
+
+	// PathPgStatStatementsReset is the URL path to reset the statistics
+	// gathered by pg_stat_statements
+	PathPgStatStatementsReset string = "/pg/stat_statements/reset"
+
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
 	// PathPgModeBackup is the URL path to interact with pg_start_backup and pg_stop_backup
 	PathPgModeBackup string = "/pg/mode/backup"
 
+	// PathPgPreStop is the URL path to coordinate the shutdown of the instance
+	// from the preStop hook of its Pod
+	PathPgPreStop string = "/pg/prestop"
+
 	// PathMetrics is the URL path for Metrics
 	PathMetrics string = "/metrics"
 
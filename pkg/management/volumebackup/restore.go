@@ -0,0 +1,222 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumebackup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// baseTarballName is the tarball, written by pg_basebackup,
+	// containing the data directory
+	baseTarballName = "base.tar.gz"
+
+	// walTarballName is the tarball, written by pg_basebackup,
+	// containing the WAL files streamed during the backup
+	walTarballName = "pg_wal.tar.gz"
+
+	// tarballSuffix is the suffix of the tarballs written by pg_basebackup,
+	// which writes one of them for every tablespace, named after its OID
+	tarballSuffix = ".tar.gz"
+
+	// tablespaceMapFileName is the file, included by pg_basebackup in the
+	// data directory tarball, containing the location of every tablespace
+	tablespaceMapFileName = "tablespace_map"
+)
+
+// RestoreBackup extracts the base backup with the passed name in the
+// passed data directory, together with the tablespaces and the WAL files
+// streamed during the backup, which are needed to reach a consistent state
+func RestoreBackup(root, name, pgData string) error {
+	backupPath := GetBackupPath(root, name)
+
+	if err := extractTarball(filepath.Join(backupPath, baseTarballName), pgData); err != nil {
+		return fmt.Errorf("while restoring the data directory: %w", err)
+	}
+
+	if err := restoreTablespaces(backupPath, pgData); err != nil {
+		return fmt.Errorf("while restoring the tablespaces: %w", err)
+	}
+
+	if err := extractTarball(filepath.Join(backupPath, walTarballName), filepath.Join(pgData, "pg_wal")); err != nil {
+		return fmt.Errorf("while restoring the WAL files: %w", err)
+	}
+
+	return nil
+}
+
+// restoreTablespaces extracts the tarball of every tablespace in the
+// location recorded in the tablespace map of the restored data directory.
+// PostgreSQL recreates the links to the tablespaces from the map at startup
+func restoreTablespaces(backupPath, pgData string) error {
+	locations, err := readTablespaceMap(filepath.Join(pgData, tablespaceMapFileName))
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(backupPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, tarballSuffix) ||
+			fileName == baseTarballName || fileName == walTarballName {
+			continue
+		}
+
+		oid := strings.TrimSuffix(fileName, tarballSuffix)
+		location, ok := locations[oid]
+		if !ok {
+			return fmt.Errorf("the location of the tablespace with OID %s is missing from the tablespace map", oid)
+		}
+
+		if err := extractTarball(filepath.Join(backupPath, fileName), location); err != nil {
+			return fmt.Errorf("while restoring the tablespace with OID %s: %w", oid, err)
+		}
+		delete(locations, oid)
+	}
+
+	if len(locations) > 0 {
+		missing := make([]string, 0, len(locations))
+		for oid := range locations {
+			missing = append(missing, oid)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("the backup of the tablespaces with OID %s is missing", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// readTablespaceMap reads the location of every tablespace, indexed by
+// OID, from the passed tablespace map. A missing map means that the
+// backup contains no tablespace
+func readTablespaceMap(fileName string) (map[string]string, error) {
+	content, err := os.ReadFile(fileName) // #nosec G304
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		if line == "" {
+			continue
+		}
+
+		oid, location, found := strings.Cut(line, " ")
+		if !found || !filepath.IsAbs(location) {
+			return nil, fmt.Errorf("invalid line in the tablespace map: %q", line)
+		}
+		locations[oid] = filepath.Clean(location)
+	}
+
+	return locations, nil
+}
+
+// GetRestoreCommand gets the restore_command copying the WAL files
+// archived in the backup volume
+func GetRestoreCommand(root string) string {
+	return fmt.Sprintf("cp %s %%p", GetWALPath(root, "%f"))
+}
+
+// extractTarball extracts a tarball compressed with gzip in the passed
+// directory, refusing the entries that would be written outside of it
+func extractTarball(tarballPath, destination string) error {
+	file, err := os.Open(tarballPath) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = gzipReader.Close()
+	}()
+
+	if err := os.MkdirAll(destination, 0o700); err != nil {
+		return err
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := extractEntry(tarReader, header, destination); err != nil {
+			return fmt.Errorf("while extracting %s: %w", header.Name, err)
+		}
+	}
+}
+
+// extractEntry writes an entry of a tarball in the passed directory
+func extractEntry(reader io.Reader, header *tar.Header, destination string) error {
+	destination = filepath.Clean(destination)
+	target := filepath.Join(destination, header.Name) // #nosec G305
+	if target != destination && !strings.HasPrefix(target, destination+string(os.PathSeparator)) {
+		return fmt.Errorf("the entry is outside of the destination directory")
+	}
+
+	mode := os.FileMode(header.Mode).Perm() // #nosec G115
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode)
+
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode) // #nosec
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(file, reader) // #nosec G110
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+
+	case tar.TypeSymlink:
+		return os.Symlink(header.Linkname, target)
+
+	default:
+		return fmt.Errorf("unsupported entry type %v", header.Typeflag)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumebackup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// tarballEntry is an entry of a tarball written by the tests
+type tarballEntry struct {
+	name    string
+	content string
+	isDir   bool
+}
+
+// writeTarball writes a tarball compressed with gzip with the passed entries
+func writeTarball(fileName string, entries ...tarballEntry) {
+	Expect(os.MkdirAll(filepath.Dir(fileName), 0o700)).To(Succeed())
+	file, err := os.Create(fileName) // #nosec G304
+	Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		Expect(file.Close()).To(Succeed())
+	}()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o600, Size: int64(len(entry.content))}
+		if entry.isDir {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0o700
+		}
+		Expect(tarWriter.WriteHeader(header)).To(Succeed())
+		_, err := tarWriter.Write([]byte(entry.content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tarWriter.Close()).To(Succeed())
+	Expect(gzipWriter.Close()).To(Succeed())
+}
+
+var _ = Describe("backup restore", func() {
+	var root string
+	var pgData string
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		root = filepath.Join(tempDir, "backup")
+		pgData = filepath.Join(tempDir, "pgdata")
+	})
+
+	It("restores the data directory and the WAL files streamed during the backup", func() {
+		backupPath := GetBackupPath(root, "backup-1")
+		writeTarball(filepath.Join(backupPath, baseTarballName),
+			tarballEntry{name: "PG_VERSION", content: "16\n"},
+			tarballEntry{name: "global", isDir: true},
+			tarballEntry{name: "global/pg_control", content: "control"},
+			tarballEntry{name: "pg_wal", isDir: true},
+		)
+		writeTarball(filepath.Join(backupPath, walTarballName),
+			tarballEntry{name: "000000010000000000000002", content: "wal"},
+		)
+
+		Expect(RestoreBackup(root, "backup-1", pgData)).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(pgData, "global", "pg_control")) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("control"))
+		Expect(filepath.Join(pgData, "PG_VERSION")).To(BeAnExistingFile())
+		Expect(filepath.Join(pgData, "pg_wal", "000000010000000000000002")).To(BeAnExistingFile())
+	})
+
+	It("refuses the entries outside of the data directory", func() {
+		backupPath := GetBackupPath(root, "backup-1")
+		writeTarball(filepath.Join(backupPath, baseTarballName),
+			tarballEntry{name: "../outside", content: "data"},
+		)
+
+		Expect(RestoreBackup(root, "backup-1", pgData)).ToNot(Succeed())
+		Expect(filepath.Join(filepath.Dir(pgData), "outside")).ToNot(BeAnExistingFile())
+	})
+
+	It("restores the tablespaces in the locations of the tablespace map", func() {
+		tablespacePath := filepath.Join(filepath.Dir(pgData), "tablespaces", "foo")
+		backupPath := GetBackupPath(root, "backup-1")
+		writeTarball(filepath.Join(backupPath, baseTarballName),
+			tarballEntry{name: "PG_VERSION", content: "16\n"},
+			tarballEntry{name: tablespaceMapFileName, content: "16385 " + tablespacePath + "\n"},
+		)
+		writeTarball(filepath.Join(backupPath, "16385.tar.gz"),
+			tarballEntry{name: "PG_16_202307071", isDir: true},
+			tarballEntry{name: "PG_16_202307071/16384", isDir: true},
+			tarballEntry{name: "PG_16_202307071/16384/16386", content: "table"},
+		)
+		writeTarball(filepath.Join(backupPath, walTarballName))
+
+		Expect(RestoreBackup(root, "backup-1", pgData)).To(Succeed())
+		Expect(filepath.Join(tablespacePath, "PG_16_202307071", "16384", "16386")).To(BeAnExistingFile())
+		Expect(filepath.Join(pgData, tablespaceMapFileName)).To(BeAnExistingFile())
+	})
+
+	It("refuses a tablespace missing from the tablespace map", func() {
+		backupPath := GetBackupPath(root, "backup-1")
+		writeTarball(filepath.Join(backupPath, baseTarballName),
+			tarballEntry{name: "PG_VERSION", content: "16\n"},
+		)
+		writeTarball(filepath.Join(backupPath, "16385.tar.gz"),
+			tarballEntry{name: "PG_16_202307071", isDir: true},
+		)
+		writeTarball(filepath.Join(backupPath, walTarballName))
+
+		Expect(RestoreBackup(root, "backup-1", pgData)).To(MatchError(ContainSubstring("16385")))
+	})
+
+	It("refuses a tablespace map referring to a missing tablespace", func() {
+		backupPath := GetBackupPath(root, "backup-1")
+		writeTarball(filepath.Join(backupPath, baseTarballName),
+			tarballEntry{name: tablespaceMapFileName, content: "16385 /var/lib/postgresql/tablespaces/foo\n"},
+		)
+		writeTarball(filepath.Join(backupPath, walTarballName))
+
+		Expect(RestoreBackup(root, "backup-1", pgData)).To(MatchError(ContainSubstring("16385")))
+	})
+
+	It("complains when the backup doesn't exist", func() {
+		Expect(RestoreBackup(root, "backup-1", pgData)).ToNot(Succeed())
+	})
+
+	It("copies the WAL files from the backup volume", func() {
+		Expect(GetRestoreCommand("/var/lib/postgresql/recovery-backup")).
+			To(Equal("cp /var/lib/postgresql/recovery-backup/wals/%f %p"))
+	})
+})
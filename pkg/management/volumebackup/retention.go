@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumebackup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ApplyRetentionPolicy removes the base backups that are not needed to
+// recover to any point of the recovery window described by the passed
+// policy, and the WAL files older than the first base backup being kept.
+// The most recent base backup is never removed. The name of the removed
+// base backups is returned
+func ApplyRetentionPolicy(root, policy string, now time.Time) ([]string, error) {
+	windowStart, err := utils.GetRecoveryWindowStart(policy, now)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := ListBackups(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) == 0 {
+		return nil, nil
+	}
+
+	// The most recent backup ended before the beginning of the
+	// recovery window is needed to recover to the beginning of the
+	// recovery window, while the older ones are obsolete
+	firstKept := 0
+	for idx := range backups {
+		if backups[idx].EndTime.Before(windowStart) {
+			firstKept = idx
+		}
+	}
+
+	removed := make([]string, 0, firstKept)
+	for _, backup := range backups[:firstKept] {
+		if err := RemoveBackup(root, backup.Name); err != nil {
+			return removed, err
+		}
+		removed = append(removed, backup.Name)
+	}
+
+	return removed, removeWALsBefore(root, backups[firstKept].BeginWal)
+}
+
+// removeWALsBefore removes the archived WAL files preceding the passed
+// one. The history files are always kept, as they are needed to follow
+// the timeline changes
+func removeWALsBefore(root, walName string) error {
+	if walName == "" {
+		// We don't know where the backup starts, so we keep every
+		// WAL file to be on the safe side
+		return nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, walsDirectory))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, ".history") || !postgres.WALRe.MatchString(name) {
+			continue
+		}
+
+		// The name of the segment is the same for WAL files, partial
+		// WAL files and backup history files, and WAL names sort in
+		// the same order of the WAL stream
+		if name[:24] >= walName {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(root, walsDirectory, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumebackup
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// createBackup stores a complete base backup in the backup volume
+func createBackup(root string, info BackupInfo) {
+	Expect(os.MkdirAll(GetBackupPath(root, info.Name), 0o700)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(GetBackupPath(root, info.Name), baseTarballName), []byte("data"), 0o600)).
+		To(Succeed())
+	Expect(WriteBackupInfo(root, info)).To(Succeed())
+}
+
+// createWALs stores the passed WAL files in the backup volume
+func createWALs(root string, names ...string) {
+	Expect(os.MkdirAll(filepath.Join(root, walsDirectory), 0o700)).To(Succeed())
+	for _, name := range names {
+		Expect(os.WriteFile(GetWALPath(root, name), []byte(name), 0o600)).To(Succeed())
+	}
+}
+
+// listWALs gets the name of the archived WAL files
+func listWALs(root string) []string {
+	entries, err := os.ReadDir(filepath.Join(root, walsDirectory))
+	Expect(err).ToNot(HaveOccurred())
+
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Name())
+	}
+	return result
+}
+
+var _ = Describe("backup catalog", func() {
+	var root string
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+	})
+
+	It("is empty when there are no backups", func() {
+		Expect(ListBackups(root)).To(BeEmpty())
+	})
+
+	It("lists the complete backups from the oldest one", func() {
+		now := time.Now().UTC().Truncate(time.Second)
+		createBackup(root, BackupInfo{Name: "backup-b", EndTime: now})
+		createBackup(root, BackupInfo{Name: "backup-a", EndTime: now.Add(-time.Hour)})
+
+		// A running or failed backup doesn't have its metadata
+		Expect(os.MkdirAll(GetBackupPath(root, "backup-c"), 0o700)).To(Succeed())
+
+		backups, err := ListBackups(root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(2))
+		Expect(backups[0].Name).To(Equal("backup-a"))
+		Expect(backups[1].Name).To(Equal("backup-b"))
+		Expect(backups[1].EndTime).To(BeTemporally("==", now))
+	})
+
+	It("reads the WAL range from the backup manifest", func() {
+		info := BackupInfo{Name: "backup"}
+		Expect(os.MkdirAll(GetBackupPath(root, info.Name), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(GetBackupPath(root, info.Name), manifestFileName), []byte(`{
+  "PostgreSQL-Backup-Manifest-Version": 1,
+  "Files": [],
+  "WAL-Ranges": [
+    { "Timeline": 2, "Start-LSN": "0/5000028", "End-LSN": "0/6000100" }
+  ]
+}`), 0o600)).To(Succeed())
+
+		Expect(info.LoadWALRange(root, 1<<24)).To(Succeed())
+		Expect(info.TimeLine).To(Equal(2))
+		Expect(info.BeginLSN).To(Equal("0/5000028"))
+		Expect(info.EndLSN).To(Equal("0/6000100"))
+		Expect(info.BeginWal).To(Equal("000000020000000000000005"))
+		Expect(info.EndWal).To(Equal("000000020000000000000006"))
+	})
+
+	It("complains when the backup has no manifest", func() {
+		info := BackupInfo{Name: "backup"}
+		Expect(info.LoadWALRange(root, 1<<24)).To(MatchError(os.ErrNotExist))
+	})
+})
+
+var _ = Describe("retention policy", func() {
+	var root string
+	now := time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+	})
+
+	It("keeps the backups needed to recover to any point of the recovery window", func() {
+		createBackup(root, BackupInfo{
+			Name:     "backup-1",
+			EndTime:  now.AddDate(0, 0, -20),
+			BeginWal: "000000010000000000000002",
+		})
+		createBackup(root, BackupInfo{
+			Name:     "backup-2",
+			EndTime:  now.AddDate(0, 0, -10),
+			BeginWal: "000000010000000000000010",
+		})
+		createBackup(root, BackupInfo{
+			Name:     "backup-3",
+			EndTime:  now.AddDate(0, 0, -3),
+			BeginWal: "000000020000000000000020",
+		})
+		createWALs(root,
+			"000000010000000000000002",
+			"00000001000000000000000F",
+			"000000010000000000000010.00000028.backup",
+			"000000010000000000000010",
+			"00000002.history",
+			"000000020000000000000020",
+		)
+
+		removed, err := ApplyRetentionPolicy(root, "7d", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(ConsistOf("backup-1"))
+
+		backups, err := ListBackups(root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(2))
+		Expect(backups[0].Name).To(Equal("backup-2"))
+		Expect(GetBackupPath(root, "backup-1")).ToNot(BeADirectory())
+
+		Expect(listWALs(root)).To(ConsistOf(
+			"000000010000000000000010.00000028.backup",
+			"000000010000000000000010",
+			"00000002.history",
+			"000000020000000000000020",
+		))
+	})
+
+	It("never removes the most recent backup", func() {
+		createBackup(root, BackupInfo{
+			Name:     "backup-1",
+			EndTime:  now.AddDate(0, -3, 0),
+			BeginWal: "000000010000000000000002",
+		})
+		createBackup(root, BackupInfo{
+			Name:     "backup-2",
+			EndTime:  now.AddDate(0, -2, 0),
+			BeginWal: "000000010000000000000004",
+		})
+
+		removed, err := ApplyRetentionPolicy(root, "1m", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(ConsistOf("backup-1"))
+
+		backups, err := ListBackups(root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(1))
+		Expect(backups[0].Name).To(Equal("backup-2"))
+	})
+
+	It("keeps every WAL file when the beginning of the first backup is unknown", func() {
+		createBackup(root, BackupInfo{Name: "backup-1", EndTime: now.AddDate(0, 0, -20)})
+		createBackup(root, BackupInfo{Name: "backup-2", EndTime: now.AddDate(0, 0, -1)})
+		createWALs(root, "000000010000000000000002", "000000010000000000000003")
+
+		removed, err := ApplyRetentionPolicy(root, "7d", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+		Expect(listWALs(root)).To(HaveLen(2))
+	})
+
+	It("doesn't remove the backups that are not complete", func() {
+		Expect(os.MkdirAll(GetBackupPath(root, "running"), 0o700)).To(Succeed())
+		createBackup(root, BackupInfo{Name: "backup-1", EndTime: now.AddDate(0, 0, -20)})
+
+		removed, err := ApplyRetentionPolicy(root, "7d", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+		Expect(GetBackupPath(root, "running")).To(BeADirectory())
+	})
+
+	It("complains with a wrong policy", func() {
+		_, err := ApplyRetentionPolicy(root, "7x", now)
+		Expect(err).To(HaveOccurred())
+	})
+})
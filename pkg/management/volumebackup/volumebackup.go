@@ -0,0 +1,247 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package volumebackup manages the base backups and the WAL files stored
+// in the volume dedicated to the backups of a cluster
+package volumebackup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// baseDirectory is the directory, inside the backup volume,
+	// containing a directory for each base backup
+	baseDirectory = "base"
+
+	// walsDirectory is the directory, inside the backup volume,
+	// containing the archived WAL files
+	walsDirectory = "wals"
+
+	// infoFileName is the name of the file, inside the directory of a
+	// base backup, containing its metadata. It's written only when the
+	// backup is complete
+	infoFileName = "backup.info"
+
+	// manifestFileName is the name of the manifest written by pg_basebackup
+	manifestFileName = "backup_manifest"
+
+	// minimumFreeSpace is the space we need to have available in the
+	// backup volume to not consider it full, which is the default size
+	// of a WAL file
+	minimumFreeSpace = uint64(postgres.DefaultWALSegmentSize)
+)
+
+var (
+	// ErrVolumeFull is raised when the backup volume has no space left
+	ErrVolumeFull = errors.New("the backup volume is full")
+
+	// ErrVolumeNotMounted is raised when the backup volume is not mounted
+	// in the Pod of the instance, which happens to a new primary until its
+	// Pod is recreated
+	ErrVolumeNotMounted = errors.New("the backup volume is not mounted")
+)
+
+// BackupInfo is the metadata of a base backup stored in the backup volume
+type BackupInfo struct {
+	// The name of the backup, which is also the name of its directory
+	Name string `json:"name"`
+
+	// The moment where the backup started
+	BeginTime time.Time `json:"beginTime"`
+
+	// The moment where the backup ended
+	EndTime time.Time `json:"endTime"`
+
+	// The LSN where the backup started
+	BeginLSN string `json:"beginLSN,omitempty"`
+
+	// The LSN where the backup ended
+	EndLSN string `json:"endLSN,omitempty"`
+
+	// The WAL where the backup started
+	BeginWal string `json:"beginWal,omitempty"`
+
+	// The WAL where the backup ended
+	EndWal string `json:"endWal,omitempty"`
+
+	// The timeline of the backup
+	TimeLine int `json:"timeline,omitempty"`
+}
+
+// backupManifest is the part of the manifest written by pg_basebackup
+// we are interested in
+type backupManifest struct {
+	WALRanges []struct {
+		Timeline int32  `json:"Timeline"`
+		StartLSN string `json:"Start-LSN"`
+		EndLSN   string `json:"End-LSN"`
+	} `json:"WAL-Ranges"`
+}
+
+// GetBackupPath gets the directory containing the base backup with the
+// passed name
+func GetBackupPath(root, name string) string {
+	return filepath.Join(root, baseDirectory, name)
+}
+
+// GetWALPath gets the path of an archived WAL file
+func GetWALPath(root, walName string) string {
+	return filepath.Join(root, walsDirectory, filepath.Base(walName))
+}
+
+// LoadWALRange reads the range of WAL files needed to restore the base
+// backup from the manifest written by pg_basebackup
+func (info *BackupInfo) LoadWALRange(root string, walSegmentSize int64) error {
+	content, err := os.ReadFile(filepath.Join(GetBackupPath(root, info.Name), manifestFileName)) // #nosec
+	if err != nil {
+		return err
+	}
+
+	var manifest backupManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return fmt.Errorf("while parsing the backup manifest: %w", err)
+	}
+	if len(manifest.WALRanges) == 0 {
+		return fmt.Errorf("the backup manifest contains no WAL range")
+	}
+
+	// The first range contains the beginning of the backup, and the
+	// last one its end, in case of a timeline change during the backup
+	first := manifest.WALRanges[0]
+	last := manifest.WALRanges[len(manifest.WALRanges)-1]
+
+	beginSegment, err := postgres.SegmentFromLSN(postgres.LSN(first.StartLSN), first.Timeline, walSegmentSize)
+	if err != nil {
+		return err
+	}
+	endSegment, err := postgres.SegmentFromLSN(postgres.LSN(last.EndLSN), last.Timeline, walSegmentSize)
+	if err != nil {
+		return err
+	}
+
+	info.TimeLine = int(last.Timeline)
+	info.BeginLSN = first.StartLSN
+	info.EndLSN = last.EndLSN
+	info.BeginWal = beginSegment.Name()
+	info.EndWal = endSegment.Name()
+	return nil
+}
+
+// WriteBackupInfo marks a base backup as complete, storing its metadata
+func WriteBackupInfo(root string, info BackupInfo) error {
+	content, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteFileAtomic(
+		filepath.Join(GetBackupPath(root, info.Name), infoFileName),
+		content,
+		0o600)
+	return CheckVolumeFull(root, err)
+}
+
+// ListBackups gets the complete base backups stored in the backup volume,
+// sorted from the oldest to the most recent one
+func ListBackups(root string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(root, baseDirectory))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BackupInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, baseDirectory, entry.Name(), infoFileName)) // #nosec
+		if errors.Is(err, os.ErrNotExist) {
+			// This backup is still running or has failed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var info BackupInfo
+		if err := json.Unmarshal(content, &info); err != nil {
+			return nil, fmt.Errorf("while parsing the metadata of backup %s: %w", entry.Name(), err)
+		}
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].EndTime.Before(result[j].EndTime)
+	})
+
+	return result, nil
+}
+
+// RemoveBackup removes a base backup from the backup volume
+func RemoveBackup(root, name string) error {
+	return os.RemoveAll(GetBackupPath(root, name))
+}
+
+// CheckVolumeMounted checks whether the backup volume is mounted in the
+// passed directory, to avoid writing the backups in the Pod filesystem
+func CheckVolumeMounted(root string) error {
+	_, err := os.Stat(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrVolumeNotMounted
+	}
+
+	return err
+}
+
+// IsVolumeFull checks whether the backup volume has not enough space
+// left to store another WAL file
+func IsVolumeFull(root string) (bool, error) {
+	_, available, err := compatibility.GetDiskUsage(root)
+	if err != nil {
+		return false, err
+	}
+
+	return available < minimumFreeSpace, nil
+}
+
+// CheckVolumeFull wraps the passed error with ErrVolumeFull when it has
+// been caused by the backup volume having not enough space left
+func CheckVolumeFull(root string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if isFull, fullErr := IsVolumeFull(root); fullErr == nil && isFull {
+		return fmt.Errorf("%w: %w", ErrVolumeFull, err)
+	}
+
+	return err
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumebackup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+// ArchiveWAL copies a WAL file into the backup volume. The file is written
+// under a temporary name and renamed when it has been synced to disk, so
+// that a WAL file is never partially archived. Archiving again a WAL file
+// with the same content succeeds, while a different content is refused
+func ArchiveWAL(root, walPath string) error {
+	destination := GetWALPath(root, walPath)
+
+	exists, err := fileutils.FileExists(destination)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return checkArchivedWAL(walPath, destination)
+	}
+
+	temporaryDestination := destination + ".tmp"
+	if err := fileutils.CopyFile(walPath, temporaryDestination); err != nil {
+		_ = os.Remove(temporaryDestination)
+		return CheckVolumeFull(root, err)
+	}
+
+	if err := os.Rename(temporaryDestination, destination); err != nil {
+		_ = os.Remove(temporaryDestination)
+		return err
+	}
+
+	return syncDirectory(filepath.Dir(destination))
+}
+
+// checkArchivedWAL checks whether a WAL file that has already been archived
+// has the same content of the one PostgreSQL wants to archive
+func checkArchivedWAL(walPath, destination string) error {
+	content, err := os.ReadFile(walPath) // #nosec
+	if err != nil {
+		return err
+	}
+
+	archivedContent, err := os.ReadFile(destination) // #nosec
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(content, archivedContent) {
+		return fmt.Errorf("WAL file %s has already been archived with a different content",
+			filepath.Base(walPath))
+	}
+
+	return nil
+}
+
+// syncDirectory makes the changes to the content of a directory durable
+func syncDirectory(directory string) error {
+	dir, err := os.Open(directory) // #nosec
+	if err != nil {
+		return err
+	}
+
+	syncErr := dir.Sync()
+	if closeErr := dir.Close(); syncErr == nil {
+		syncErr = closeErr
+	}
+
+	return syncErr
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumebackup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archiving", func() {
+	var root string
+	var walPath string
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		root = filepath.Join(tempDir, "backup")
+		walPath = filepath.Join(tempDir, "pg_wal", "000000010000000000000001")
+		Expect(os.MkdirAll(filepath.Dir(walPath), 0o700)).To(Succeed())
+		Expect(os.WriteFile(walPath, []byte("wal content"), 0o600)).To(Succeed())
+	})
+
+	It("copies the WAL file in the backup volume", func() {
+		Expect(ArchiveWAL(root, walPath)).To(Succeed())
+
+		content, err := os.ReadFile(GetWALPath(root, "000000010000000000000001"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("wal content"))
+		Expect(GetWALPath(root, "000000010000000000000001") + ".tmp").ToNot(BeAnExistingFile())
+	})
+
+	It("succeeds when the same WAL file is archived again", func() {
+		Expect(ArchiveWAL(root, walPath)).To(Succeed())
+		Expect(ArchiveWAL(root, walPath)).To(Succeed())
+	})
+
+	It("refuses to overwrite an archived WAL file with a different content", func() {
+		Expect(ArchiveWAL(root, walPath)).To(Succeed())
+		Expect(os.WriteFile(walPath, []byte("another content"), 0o600)).To(Succeed())
+		Expect(ArchiveWAL(root, walPath)).ToNot(Succeed())
+	})
+
+	It("complains when the WAL file doesn't exist", func() {
+		Expect(ArchiveWAL(root, walPath+"-missing")).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("full volume detection", func() {
+	It("doesn't wrap the errors when there is space left", func() {
+		root := GinkgoT().TempDir()
+		isFull, err := IsVolumeFull(root)
+		Expect(err).ToNot(HaveOccurred())
+		if isFull {
+			Skip("the temporary directory is on a full file system")
+		}
+
+		sourceErr := fmt.Errorf("test error")
+		Expect(CheckVolumeFull(root, sourceErr)).To(Equal(sourceErr))
+		Expect(errors.Is(CheckVolumeFull(root, sourceErr), ErrVolumeFull)).To(BeFalse())
+		Expect(CheckVolumeFull(root, nil)).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("mounted volume detection", func() {
+	It("detects when the backup volume is not mounted", func() {
+		root := GinkgoT().TempDir()
+		Expect(CheckVolumeMounted(root)).To(Succeed())
+		Expect(CheckVolumeMounted(filepath.Join(root, "backup"))).To(MatchError(ErrVolumeNotMounted))
+	})
+})
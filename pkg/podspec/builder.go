@@ -187,6 +187,34 @@ func (builder *Builder) WithServiceAccountName(name string, overwrite bool) *Bui
 	return builder
 }
 
+// WithTerminationGracePeriodSeconds sets the time the pod is given
+// to terminate gracefully
+func (builder *Builder) WithTerminationGracePeriodSeconds(seconds int64, overwrite bool) *Builder {
+	if builder.status.Spec.TerminationGracePeriodSeconds != nil && !overwrite {
+		return builder
+	}
+
+	builder.status.Spec.TerminationGracePeriodSeconds = &seconds
+
+	return builder
+}
+
+// WithImagePullSecret ensures that the pod references the passed
+// image pull secret
+func (builder *Builder) WithImagePullSecret(name string) *Builder {
+	for _, value := range builder.status.Spec.ImagePullSecrets {
+		if value.Name == name {
+			return builder
+		}
+	}
+
+	builder.status.Spec.ImagePullSecrets = append(builder.status.Spec.ImagePullSecrets,
+		corev1.LocalObjectReference{
+			Name: name,
+		})
+	return builder
+}
+
 // WithLivenessProbe add the provided liveness probe to a container
 func (builder *Builder) WithLivenessProbe(name string, livenessProbe *corev1.Probe, overwrite bool) *Builder {
 	builder.WithContainer(name)
@@ -39,6 +39,15 @@ var _ = Describe("Pod template builder", func() {
 			To(Equal("annotation"))
 	})
 
+	It("adds image pull secrets only once", func() {
+		template := New().
+			WithImagePullSecret("mirror").
+			WithImagePullSecret("mirror").
+			Build()
+
+		Expect(template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "mirror"}))
+	})
+
 	It("adds volumes", func() {
 		template := New().
 			WithVolume(&corev1.Volume{
@@ -266,4 +275,20 @@ var _ = Describe("Pod template builder", func() {
 		Expect(template.Spec.Containers[0].Env[0].Name).To(Equal("one"))
 		Expect(template.Spec.Containers[0].Env[0].Value).To(Equal("two"))
 	})
+
+	It("correctly set the termination grace period when not set", func() {
+		template := New().
+			WithTerminationGracePeriodSeconds(60, false).
+			WithTerminationGracePeriodSeconds(30, false).
+			Build()
+		Expect(template.Spec.TerminationGracePeriodSeconds).To(HaveValue(Equal(int64(60))))
+	})
+
+	It("correctly override the termination grace period when set", func() {
+		template := New().
+			WithTerminationGracePeriodSeconds(60, false).
+			WithTerminationGracePeriodSeconds(30, true).
+			Build()
+		Expect(template.Spec.TerminationGracePeriodSeconds).To(HaveValue(Equal(int64(30))))
+	})
 })
@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+// MaxActivityBackends is the maximum number of backends reported
+// by the activity endpoint of the instance manager
+const MaxActivityBackends = 200
+
+// MaxActivityQueryLength is the maximum length of the query text
+// reported for each backend
+const MaxActivityQueryLength = 4096
+
+// BackendActivity is the activity of a PostgreSQL backend, as
+// reported by pg_stat_activity
+type BackendActivity struct {
+	// The process ID of the backend
+	PID int `json:"pid"`
+
+	// The user logged into the backend
+	User string `json:"user,omitempty"`
+
+	// The database the backend is connected to
+	Database string `json:"database,omitempty"`
+
+	// The application name of the client
+	ApplicationName string `json:"applicationName,omitempty"`
+
+	// The address of the client, empty for Unix sockets
+	ClientAddress string `json:"clientAddress,omitempty"`
+
+	// The current state of the backend
+	State string `json:"state,omitempty"`
+
+	// The type of the event the backend is waiting for
+	WaitEventType string `json:"waitEventType,omitempty"`
+
+	// The event the backend is waiting for
+	WaitEvent string `json:"waitEvent,omitempty"`
+
+	// The text of the current or last query, truncated to
+	// MaxActivityQueryLength characters
+	Query string `json:"query,omitempty"`
+
+	// True when the query text has been redacted
+	QueryRedacted bool `json:"queryRedacted,omitempty"`
+
+	// The time elapsed since the start of the current or last query,
+	// in seconds
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// ActivityReport is the list of the active backends of an instance
+type ActivityReport struct {
+	// The active backends, starting from the longest running query
+	Backends []BackendActivity `json:"backends"`
+
+	// True when there are more than MaxActivityBackends active backends,
+	// and the list has been truncated
+	Truncated bool `json:"truncated,omitempty"`
+}
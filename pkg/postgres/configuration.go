@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -40,6 +41,10 @@ hostssl all cnpg_pooler_pgbouncer all cert
 {{ range $rule := .UserRules }}
 {{ $rule -}}
 {{ end }}
+{{ if .RejectSuperuser }}
+# The superuser access is disabled
+host all postgres all reject
+{{ end }}
 {{ if .LDAPConfiguration }}
 
 # LDAP Configuration
@@ -66,6 +71,10 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// CertificatesDir location to store the certificates
 	CertificatesDir = ScratchDataDirectory + "/certificates/"
 
+	// StatsTempDirectory is the directory where the memory backed volume
+	// hosting the stats_temp_directory is mounted
+	StatsTempDirectory = "/var/lib/postgresql/stats_temp"
+
 	// ProjectedVolumeDirectory is the base directory to store ProjectedVolumeSource
 	ProjectedVolumeDirectory = "/projected"
 
@@ -133,6 +142,19 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// LogPath is the path of the folder used by the logging_collector
 	LogPath = ScratchDataDirectory + "/log"
 
+	// LogVolumeDirectory is the directory where the volume dedicated
+	// to the PostgreSQL logs is mounted, when requested
+	LogVolumeDirectory = "/var/lib/postgresql/log"
+
+	// BackupVolumeDirectory is the directory where the volume dedicated
+	// to the backups is mounted, when requested
+	BackupVolumeDirectory = "/var/lib/postgresql/backup"
+
+	// RecoveryBackupVolumeDirectory is the directory where the volume
+	// dedicated to the backups of another cluster is mounted, when
+	// recovering from it
+	RecoveryBackupVolumeDirectory = "/var/lib/postgresql/recovery-backup"
+
 	// LogFileName is the name of the file produced by the logging_collector,
 	// excluding the extension. The logging collector process will append
 	// `.csv` and `.log` as needed.
@@ -202,6 +224,55 @@ type ConfigurationInfo struct {
 	// The list of user-level settings
 	UserSettings map[string]string
 
+	// The TCP keepalive settings of the connections. Zero values
+	// are not rendered, leaving the ones of the user in place
+	TCPKeepalivesIdle     int
+	TCPKeepalivesInterval int
+	TCPKeepalivesCount    int
+
+	// The number of milliseconds the replicas wait before retrying to
+	// retrieve the WAL. A zero value is not rendered
+	WalRetrieveRetryInterval int
+
+	// The maximum size of WAL files that replication slots are allowed
+	// to retain. An empty value is not rendered
+	MaxSlotWALKeepSize string
+
+	// The default isolation level of the transactions. An empty
+	// value is not rendered
+	DefaultTransactionIsolation string
+
+	// When true, the transactions are read-only unless they explicitly
+	// request otherwise. Set on the replicas when the cluster enforces it
+	DefaultTransactionReadOnly bool
+
+	// The statement and lock timeouts of the sessions. Empty
+	// values are not rendered
+	StatementTimeout string
+	LockTimeout      string
+
+	// The cluster-wide settings of the autovacuum, keyed by the name
+	// of the parameter
+	AutovacuumSettings map[string]string
+
+	// The time zones and the date style of the sessions and of the
+	// server log. Empty values are not rendered
+	Timezone    string
+	LogTimezone string
+	DateStyle   string
+
+	// The effective_cache_size computed from the memory of the pod,
+	// rendered unless the user has set it. An empty value is not rendered
+	EffectiveCacheSize string
+
+	// When true, the crash safety guarantees are disabled, setting
+	// fsync, full_page_writes and synchronous_commit to off
+	RelaxedDurability bool
+
+	// The list of user-level settings overridden for this instance.
+	// Fixed and instance protected parameters are ignored
+	InstanceSettings map[string]string
+
 	// The list of replicas
 	SyncReplicasElectable []string
 
@@ -226,6 +297,14 @@ type ConfigurationInfo struct {
 
 	// Is this a replica cluster?
 	IsReplicaCluster bool
+
+	// The directory to be used as stats_temp_directory, empty to
+	// use the default one. Ignored from PostgreSQL 15
+	StatsTempDirectory string
+
+	// The directory to be used as log_directory, empty to use
+	// the default one
+	LogDirectory string
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -359,6 +438,41 @@ var (
 		"syslog_split_messages":                  blockedConfigurationParameter,
 	}
 
+	// InstanceProtectedParameters contains the parameters that must have
+	// the same value on every instance of the cluster, and can't be
+	// overridden for a single instance. A replica can't start streaming if
+	// its value of the hot standby related parameters is lower than the one
+	// of the primary, which may change after a switchover
+	InstanceProtectedParameters = map[string]bool{
+		"full_page_writes":          true,
+		"max_connections":           true,
+		"max_locks_per_transaction": true,
+		"max_prepared_transactions": true,
+		"max_wal_senders":           true,
+		"max_worker_processes":      true,
+		"synchronous_commit":        true,
+		"track_commit_timestamp":    true,
+		"wal_level":                 true,
+		"wal_log_hints":             true,
+	}
+
+	// RestartRequiredParameters contains the parameters, among the ones
+	// rendered from the dedicated settings of the cluster, whose change
+	// requires a restart of PostgreSQL. The other ones are applied with
+	// a reload of the configuration
+	RestartRequiredParameters = map[string]bool{
+		"autovacuum_max_workers": true,
+	}
+
+	// RelaxedDurabilitySettings contains the settings disabling the crash
+	// safety guarantees of PostgreSQL, applied on top of the mandatory ones
+	// when the relaxed durability is requested
+	RelaxedDurabilitySettings = SettingsCollection{
+		"fsync":              "off",
+		"full_page_writes":   "off",
+		"synchronous_commit": "off",
+	}
+
 	// CnpgConfigurationSettings contains the settings that represent the
 	// default and the mandatory behavior of CNP
 	CnpgConfigurationSettings = ConfigurationSettings{
@@ -419,15 +533,18 @@ var (
 // the rules set by the cluster spec
 func CreateHBARules(hba []string,
 	defaultAuthenticationMethod, ldapConfigString string,
+	rejectSuperuser bool,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
 		UserRules                   []string
+		RejectSuperuser             bool
 		LDAPConfiguration           string
 		DefaultAuthenticationMethod string
 	}{
 		UserRules:                   hba,
+		RejectSuperuser:             rejectSuperuser,
 		LDAPConfiguration:           ldapConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
 	}
@@ -503,6 +620,12 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 	// Set all the default settings
 	setDefaultConfigurations(info, configuration)
 
+	// Size the cache assumed by the planner after the memory of the pod,
+	// before the values from the user that take precedence
+	if info.EffectiveCacheSize != "" {
+		configuration.OverwriteConfig("effective_cache_size", info.EffectiveCacheSize)
+	}
+
 	// Apply all the values from the user, overriding defaults,
 	// ignoring those which are fixed if ignoreFixedSettingsFromUser is true
 	for key, value := range info.UserSettings {
@@ -513,6 +636,63 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig(key, value)
 	}
 
+	// Apply the TCP keepalive settings
+	setTCPKeepalivesConfigurations(info, configuration)
+
+	// Set the interval between the attempts to retrieve the WAL
+	if info.WalRetrieveRetryInterval > 0 {
+		configuration.OverwriteConfig("wal_retrieve_retry_interval", strconv.Itoa(info.WalRetrieveRetryInterval))
+	}
+
+	// Cap the WAL retained by the replication slots
+	if info.MaxSlotWALKeepSize != "" {
+		configuration.OverwriteConfig("max_slot_wal_keep_size", info.MaxSlotWALKeepSize)
+	}
+
+	// Set the default isolation level of the transactions
+	if info.DefaultTransactionIsolation != "" {
+		configuration.OverwriteConfig("default_transaction_isolation", info.DefaultTransactionIsolation)
+	}
+
+	// Set the time zones and the date style
+	if info.Timezone != "" {
+		configuration.OverwriteConfig("timezone", info.Timezone)
+	}
+	if info.LogTimezone != "" {
+		configuration.OverwriteConfig("log_timezone", info.LogTimezone)
+	}
+	if info.DateStyle != "" {
+		configuration.OverwriteConfig("datestyle", info.DateStyle)
+	}
+
+	// Make the writes fail fast on the replicas
+	if info.DefaultTransactionReadOnly {
+		configuration.OverwriteConfig("default_transaction_read_only", "on")
+	}
+
+	// Set the timeouts of the sessions
+	if info.StatementTimeout != "" {
+		configuration.OverwriteConfig("statement_timeout", info.StatementTimeout)
+	}
+	if info.LockTimeout != "" {
+		configuration.OverwriteConfig("lock_timeout", info.LockTimeout)
+	}
+
+	// Set the cluster-wide settings of the autovacuum
+	for key, value := range info.AutovacuumSettings {
+		configuration.OverwriteConfig(key, value)
+	}
+
+	// Apply the settings of this instance, on top of the ones of the cluster,
+	// never overriding the ones that must be the same on every instance
+	for key, value := range info.InstanceSettings {
+		_, isFixed := FixedConfigurationParameters[key]
+		if isFixed || InstanceProtectedParameters[key] {
+			continue
+		}
+		configuration.OverwriteConfig(key, value)
+	}
+
 	// Apply all mandatory settings, on top of defaults and user settings
 	if info.IncludingMandatory {
 		for key, value := range info.Settings.MandatorySettings {
@@ -520,6 +700,14 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		}
 	}
 
+	// Disable the crash safety guarantees when the relaxed durability is requested,
+	// overriding the mandatory full_page_writes
+	if info.RelaxedDurability {
+		for key, value := range RelaxedDurabilitySettings {
+			configuration.OverwriteConfig(key, value)
+		}
+	}
+
 	// Apply the correct archive_mode
 	if info.IsReplicaCluster {
 		configuration.OverwriteConfig("archive_mode", "always")
@@ -530,6 +718,16 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 	// Apply the list of replicas
 	setReplicasListConfigurations(info, configuration)
 
+	// Place the statistics temporary files where requested
+	if info.StatsTempDirectory != "" && info.MajorVersion < 150000 {
+		configuration.OverwriteConfig("stats_temp_directory", info.StatsTempDirectory)
+	}
+
+	// Place the log files on the dedicated volume, when requested
+	if info.LogDirectory != "" {
+		configuration.OverwriteConfig("log_directory", info.LogDirectory)
+	}
+
 	if info.IncludingSharedPreloadLibraries {
 		// Set all managed shared preload libraries
 		setManagedSharedPreloadLibraries(info, configuration)
@@ -541,6 +739,20 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 	return configuration
 }
 
+// setTCPKeepalivesConfigurations sets the TCP keepalive parameters
+// requested in the configuration info
+func setTCPKeepalivesConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
+	for key, value := range map[string]int{
+		"tcp_keepalives_idle":     info.TCPKeepalivesIdle,
+		"tcp_keepalives_interval": info.TCPKeepalivesInterval,
+		"tcp_keepalives_count":    info.TCPKeepalivesCount,
+	} {
+		if value > 0 {
+			configuration.OverwriteConfig(key, strconv.Itoa(value))
+		}
+	}
+}
+
 // setDefaultConfigurations sets all default configurations into the configuration map
 // from the provided info
 func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
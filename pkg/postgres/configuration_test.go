@@ -57,6 +57,373 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 		Expect(config.GetConfig("hot_standby")).To(Equal("true"))
 	})
 
+	It("applies the instance settings on top of the ones of the cluster", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 160000,
+			UserSettings: map[string]string{
+				"work_mem":        "4MB",
+				"max_connections": "200",
+			},
+			InstanceSettings: map[string]string{
+				"work_mem":             "256MB",
+				"effective_cache_size": "8GB",
+			},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("work_mem")).To(Equal("256MB"))
+		Expect(config.GetConfig("effective_cache_size")).To(Equal("8GB"))
+		Expect(config.GetConfig("max_connections")).To(Equal("200"))
+	})
+
+	It("sets the computed effective_cache_size", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			EffectiveCacheSize: "6291456kB",
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("effective_cache_size")).To(Equal("6291456kB"))
+	})
+
+	It("gives precedence to the effective_cache_size set by the user", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 160000,
+			UserSettings: map[string]string{
+				"effective_cache_size": "4GB",
+			},
+			EffectiveCacheSize: "6291456kB",
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("effective_cache_size")).To(Equal("4GB"))
+
+		info.UserSettings = nil
+		info.InstanceSettings = map[string]string{
+			"effective_cache_size": "2GB",
+		}
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("effective_cache_size")).To(Equal("2GB"))
+	})
+
+	It("never overrides the protected and the mandatory settings for an instance", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 160000,
+			UserSettings: map[string]string{
+				"max_connections": "200",
+			},
+			InstanceSettings: map[string]string{
+				"max_connections": "50",
+				"wal_level":       "minimal",
+				"hot_standby":     "off",
+				"archive_mode":    "off",
+			},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("max_connections")).To(Equal("200"))
+		Expect(config.GetConfig("wal_level")).To(Equal("logical"))
+		Expect(config.GetConfig("hot_standby")).To(Equal("true"))
+		Expect(config.GetConfig("archive_mode")).To(Equal("on"))
+	})
+
+	It("renders the TCP keepalive settings", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 160000,
+			UserSettings: map[string]string{
+				"tcp_keepalives_count": "3",
+			},
+			TCPKeepalivesIdle:     60,
+			TCPKeepalivesInterval: 10,
+			IncludingMandatory:    true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("tcp_keepalives_idle")).To(Equal("60"))
+		Expect(config.GetConfig("tcp_keepalives_interval")).To(Equal("10"))
+		Expect(config.GetConfig("tcp_keepalives_count")).To(Equal("3"))
+	})
+
+	DescribeTable("renders the interval between the attempts to retrieve the WAL",
+		func(retryInterval int, expected string) {
+			info := ConfigurationInfo{
+				Settings:                 CnpgConfigurationSettings,
+				MajorVersion:             160000,
+				WalRetrieveRetryInterval: retryInterval,
+				IncludingMandatory:       true,
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("wal_retrieve_retry_interval")).To(Equal(expected))
+		},
+		Entry("not set", 0, ""),
+		Entry("100 milliseconds", 100, "100"),
+		Entry("one minute", 60000, "60000"),
+	)
+
+	It("renders the WAL retention cap of the replication slots", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			MaxSlotWALKeepSize: "10240MB",
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("max_slot_wal_keep_size")).To(Equal("10240MB"))
+	})
+
+	It("doesn't cap the WAL retention of the replication slots by default", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("max_slot_wal_keep_size")).To(BeEmpty())
+	})
+
+	It("renders the default isolation level of the transactions", func() {
+		info := ConfigurationInfo{
+			Settings:                    CnpgConfigurationSettings,
+			MajorVersion:                160000,
+			DefaultTransactionIsolation: "repeatable read",
+			IncludingMandatory:          true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("default_transaction_isolation")).To(Equal("repeatable read"))
+
+		conf, _ := CreatePostgresqlConfFile(config)
+		Expect(conf).To(ContainSubstring("default_transaction_isolation = 'repeatable read'\n"))
+	})
+
+	It("changes only the default isolation level of the transactions, without requiring a restart", func() {
+		info := ConfigurationInfo{
+			Settings:                    CnpgConfigurationSettings,
+			MajorVersion:                160000,
+			DefaultTransactionIsolation: "read committed",
+			IncludingMandatory:          true,
+		}
+		before := CreatePostgresqlConfiguration(info).GetConfigurationParameters()
+
+		info.DefaultTransactionIsolation = "serializable"
+		after := CreatePostgresqlConfiguration(info).GetConfigurationParameters()
+
+		var changed []string
+		for key, value := range after {
+			if before[key] != value {
+				changed = append(changed, key)
+			}
+		}
+		Expect(changed).To(ConsistOf("default_transaction_isolation"))
+
+		// The fixed parameters are the ones that can't be reloaded
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("default_transaction_isolation"))
+	})
+
+	It("doesn't render the default isolation level of the transactions by default", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("default_transaction_isolation")).To(BeEmpty())
+	})
+
+	It("renders the read-only transactions by default when requested", func() {
+		info := ConfigurationInfo{
+			Settings:                   CnpgConfigurationSettings,
+			MajorVersion:               160000,
+			DefaultTransactionReadOnly: true,
+			IncludingMandatory:         true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("default_transaction_read_only")).To(Equal("on"))
+
+		info.DefaultTransactionReadOnly = false
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("default_transaction_read_only")).To(BeEmpty())
+
+		// The parameter can be changed with a reload
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("default_transaction_read_only"))
+	})
+
+	It("renders the statement and lock timeouts", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			StatementTimeout:   "30s",
+			LockTimeout:        "5s",
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("statement_timeout")).To(Equal("30s"))
+		Expect(config.GetConfig("lock_timeout")).To(Equal("5s"))
+
+		conf, _ := CreatePostgresqlConfFile(config)
+		Expect(conf).To(ContainSubstring("statement_timeout = '30s'\n"))
+		Expect(conf).To(ContainSubstring("lock_timeout = '5s'\n"))
+	})
+
+	It("doesn't render the statement and lock timeouts by default", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("statement_timeout")).To(BeEmpty())
+		Expect(config.GetConfig("lock_timeout")).To(BeEmpty())
+	})
+
+	It("renders the autovacuum settings", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 160000,
+			AutovacuumSettings: map[string]string{
+				"autovacuum_max_workers":       "6",
+				"autovacuum_vacuum_cost_limit": "2000",
+				"autovacuum_naptime":           "30s",
+			},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("autovacuum_max_workers")).To(Equal("6"))
+		Expect(config.GetConfig("autovacuum_vacuum_cost_limit")).To(Equal("2000"))
+		Expect(config.GetConfig("autovacuum_naptime")).To(Equal("30s"))
+	})
+
+	It("classifies the autovacuum settings requiring a restart", func() {
+		Expect(RestartRequiredParameters).To(HaveKeyWithValue("autovacuum_max_workers", true))
+		Expect(RestartRequiredParameters).ToNot(HaveKey("autovacuum_vacuum_cost_limit"))
+		Expect(RestartRequiredParameters).ToNot(HaveKey("autovacuum_naptime"))
+
+		// none of them is fixed, as they can all be changed by the user
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("autovacuum_max_workers"))
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("autovacuum_vacuum_cost_limit"))
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("autovacuum_naptime"))
+	})
+
+	It("renders the time zones and the date style", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			Timezone:           "Europe/Rome",
+			LogTimezone:        "UTC",
+			DateStyle:          "ISO, DMY",
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("timezone")).To(Equal("Europe/Rome"))
+		Expect(config.GetConfig("log_timezone")).To(Equal("UTC"))
+		Expect(config.GetConfig("datestyle")).To(Equal("ISO, DMY"))
+
+		conf, _ := CreatePostgresqlConfFile(config)
+		Expect(conf).To(ContainSubstring("timezone = 'Europe/Rome'\n"))
+		Expect(conf).To(ContainSubstring("log_timezone = 'UTC'\n"))
+		Expect(conf).To(ContainSubstring("datestyle = 'ISO, DMY'\n"))
+
+		// The fixed parameters are the ones that can't be reloaded
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("timezone"))
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("log_timezone"))
+		Expect(FixedConfigurationParameters).ToNot(HaveKey("datestyle"))
+	})
+
+	It("doesn't render the time zones and the date style by default", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("timezone")).To(BeEmpty())
+		Expect(config.GetConfig("log_timezone")).To(BeEmpty())
+		Expect(config.GetConfig("datestyle")).To(BeEmpty())
+	})
+
+	It("doesn't render the TCP keepalive settings by default", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("tcp_keepalives_idle")).To(BeEmpty())
+		Expect(config.GetConfig("tcp_keepalives_interval")).To(BeEmpty())
+		Expect(config.GetConfig("tcp_keepalives_count")).To(BeEmpty())
+	})
+
+	It("disables the crash safety guarantees with the relaxed durability", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+			RelaxedDurability:  true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("fsync")).To(Equal("off"))
+		Expect(config.GetConfig("full_page_writes")).To(Equal("off"))
+		Expect(config.GetConfig("synchronous_commit")).To(Equal("off"))
+	})
+
+	It("keeps the crash safety guarantees by default", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("fsync")).To(BeEmpty())
+		Expect(config.GetConfig("full_page_writes")).To(Equal("on"))
+		Expect(config.GetConfig("synchronous_commit")).To(BeEmpty())
+	})
+
+	It("places the stats_temp_directory where requested", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       140000,
+			IncludingMandatory: true,
+			StatsTempDirectory: StatsTempDirectory,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("stats_temp_directory")).To(Equal(StatsTempDirectory))
+	})
+
+	It("ignores the stats_temp_directory from PostgreSQL 15", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       150000,
+			IncludingMandatory: true,
+			StatsTempDirectory: StatsTempDirectory,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("stats_temp_directory")).To(BeEmpty())
+	})
+
+	It("keeps the default log_directory when no log volume is requested", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("log_directory")).To(Equal(LogPath))
+	})
+
+	It("places the log_directory on the log volume when requested", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       160000,
+			IncludingMandatory: true,
+			LogDirectory:       LogVolumeDirectory,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("log_directory")).To(Equal(LogVolumeDirectory))
+	})
+
 	It("generate a config file", func() {
 		info := ConfigurationInfo{
 			Settings:              CnpgConfigurationSettings,
@@ -233,19 +600,47 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, "md5", "", false)).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, "this-one", "", false)).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString", false)).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("requires certificate authentication for the streaming_replica user before the user rules", func() {
+		rules, err := CreateHBARules([]string{"host all all all md5"}, "scram-sha-256", "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhostssl postgres streaming_replica all cert\n"))
+		Expect(rules).To(ContainSubstring("\nhostssl replication streaming_replica all cert\n"))
+		Expect(strings.Index(rules, "hostssl replication streaming_replica all cert")).To(
+			BeNumerically("<", strings.Index(rules, "host all all all md5")))
+	})
+
+	It("rejects the superuser after the user rules when the superuser access is disabled", func() {
+		rules, err := CreateHBARules(
+			[]string{"hostssl all postgres all cert"}, "scram-sha-256", "ldapConfigString", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhost all postgres all reject\n"))
+		Expect(strings.Index(rules, "hostssl all postgres all cert")).To(
+			BeNumerically("<", strings.Index(rules, "host all postgres all reject")))
+		Expect(strings.Index(rules, "host all postgres all reject")).To(
+			BeNumerically("<", strings.Index(rules, "ldapConfigString")))
+		Expect(strings.Index(rules, "host all postgres all reject")).To(
+			BeNumerically("<", strings.Index(rules, "host all all all scram-sha-256")))
+	})
+
+	It("doesn't reject the superuser when the superuser access is enabled", func() {
+		rules, err := CreateHBARules(nil, "scram-sha-256", "", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).ToNot(ContainSubstring("reject"))
+	})
 })
 
 var _ = Describe("pgaudit", func() {
@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -46,6 +47,35 @@ type PostgresqlStatus struct {
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
+	// The default isolation level of the transactions
+	DefaultTransactionIsolation string `json:"defaultTransactionIsolation,omitempty"`
+
+	// The size and the free space of the file system hosting PGDATA, in bytes
+	DataDiskTotalBytes     uint64 `json:"dataDiskTotalBytes,omitempty"`
+	DataDiskAvailableBytes uint64 `json:"dataDiskAvailableBytes,omitempty"`
+
+	// The number of queries canceled on a replica because of conflicts
+	// with recovery in the last five minutes
+	RecentReplicationConflicts int64 `json:"recentReplicationConflicts,omitempty"`
+
+	// Whether the data checksums are enabled, and the number of checksum
+	// failures detected in every database since the statistics were last
+	// reset. Reported on PostgreSQL 12 and later
+	DataChecksumsEnabled *bool `json:"dataChecksumsEnabled,omitempty"`
+	ChecksumFailures     int64 `json:"checksumFailures,omitempty"`
+
+	// The number of existing replication slots, and the maximum number
+	// of them allowed by max_replication_slots
+	ReplicationSlotsUsed int `json:"replicationSlotsUsed,omitempty"`
+	MaxReplicationSlots  int `json:"maxReplicationSlots,omitempty"`
+
+	// The size of the WAL segments, in bytes, as reported by the control file
+	WalSegmentSize int64 `json:"walSegmentSize,omitempty"`
+
+	// The default collation provider of the databases, as reported by
+	// the template1 database
+	LocaleProvider string `json:"localeProvider,omitempty"`
+
 	// Archiver status
 
 	LastArchivedWAL     string `json:"lastArchivedWAL,omitempty"`
@@ -147,6 +177,37 @@ func (status PostgresqlStatus) HasHTTPStatus() bool {
 	return status.Error == nil
 }
 
+// GetDataDiskUsage returns the usage percentage of the file system hosting
+// PGDATA, and false if the instance didn't report it
+func (status PostgresqlStatus) GetDataDiskUsage() (float64, bool) {
+	if status.DataDiskTotalBytes == 0 || status.DataDiskAvailableBytes > status.DataDiskTotalBytes {
+		return 0, false
+	}
+
+	used := status.DataDiskTotalBytes - status.DataDiskAvailableBytes
+	return float64(used) * 100 / float64(status.DataDiskTotalBytes), true
+}
+
+// GetReplicationSlotsUsage returns the percentage of the replication slots
+// allowed by max_replication_slots that are in use, and false if the
+// instance didn't report it
+func (status PostgresqlStatus) GetReplicationSlotsUsage() (float64, bool) {
+	if status.MaxReplicationSlots == 0 {
+		return 0, false
+	}
+
+	return float64(status.ReplicationSlotsUsed) * 100 / float64(status.MaxReplicationSlots), true
+}
+
+// ReplicationSlotsUsageThreshold is the percentage of the replication slots
+// allowed by max_replication_slots above which their usage is considered high
+const ReplicationSlotsUsageThreshold = 80
+
+// ReplicationConflictsSpikeThreshold is the number of queries canceled
+// because of conflicts with recovery in the last five minutes above which
+// a replica is considered to have a spike of conflicts
+const ReplicationConflictsSpikeThreshold = 10
+
 // PgStatReplicationList is a list of PgStatReplication reported by the primary instance
 type PgStatReplicationList []PgStatReplication
 
@@ -165,6 +226,17 @@ type PgReplicationSlot struct {
 	Active      bool   `json:"active,omitempty"`
 }
 
+// ReplicationSlotWALStatusLost is the wal_status of the replication slots
+// which have been invalidated, because they were retaining more WAL than
+// allowed by max_slot_wal_keep_size
+const ReplicationSlotWALStatusLost = "lost"
+
+// IsInvalidated checks whether the replication slot has been invalidated,
+// and can't be used anymore
+func (slot PgReplicationSlot) IsInvalidated() bool {
+	return slot.WalStatus == ReplicationSlotWALStatusLost
+}
+
 // PgReplicationSlotList is a list of PgReplicationSlot reported by the primary instance
 type PgReplicationSlotList []PgReplicationSlot
 
@@ -265,6 +337,46 @@ func (list *PostgresqlStatusList) Swap(i, j int) {
 // name (split brain?), and secondaries always go by their replication status with
 // the more updated one coming as first
 func (list *PostgresqlStatusList) Less(i, j int) bool {
+	return list.less(i, j, nil)
+}
+
+// SortWithPreferredZone sorts the list in election order, like sort.Sort
+// does, but when two replicas have received and replayed the same WAL the
+// one running on a node in the preferred zone comes first. The zone never
+// takes precedence over the replication status
+func (list *PostgresqlStatusList) SortWithPreferredZone(nodes map[string]corev1.Node, preferredZone string) {
+	isPreferred := func(item *PostgresqlStatus) bool {
+		node, ok := nodes[item.Node]
+		return ok && node.Labels[corev1.LabelTopologyZone] == preferredZone
+	}
+	sort.Sort(&preferredZoneSorter{list: list, isPreferred: isPreferred})
+}
+
+// preferredZoneSorter sorts a PostgresqlStatusList using the zone
+// preference as a tiebreaker
+type preferredZoneSorter struct {
+	list        *PostgresqlStatusList
+	isPreferred func(item *PostgresqlStatus) bool
+}
+
+// Len implements sort.Interface
+func (sorter *preferredZoneSorter) Len() int {
+	return sorter.list.Len()
+}
+
+// Swap implements sort.Interface
+func (sorter *preferredZoneSorter) Swap(i, j int) {
+	sorter.list.Swap(i, j)
+}
+
+// Less implements sort.Interface
+func (sorter *preferredZoneSorter) Less(i, j int) bool {
+	return sorter.list.less(i, j, sorter.isPreferred)
+}
+
+// less implements Less, using isPreferred, when not nil, to break
+// the ties between equally advanced replicas
+func (list *PostgresqlStatusList) less(i, j int, isPreferred func(item *PostgresqlStatus) bool) bool {
 	// Incomplete status records go to the bottom of
 	// the list, since this is used to elect a new primary
 	// when needed.
@@ -297,6 +409,14 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return !list.Items[i].ReplayLsn.Less(list.Items[j].ReplayLsn)
 	}
 
+	// Prefer the replicas in the preferred zone
+	if isPreferred != nil {
+		iPreferred, jPreferred := isPreferred(&list.Items[i]), isPreferred(&list.Items[j])
+		if iPreferred != jPreferred {
+			return iPreferred
+		}
+	}
+
 	return list.Items[i].Pod.Name < list.Items[j].Pod.Name
 }
 
@@ -405,3 +525,75 @@ func (list PostgresqlStatusList) InstancesReportingStatus() int {
 
 	return n
 }
+
+// InvalidatedReplicationSlots returns the names of the replication slots
+// of the primary instances which have been invalidated. The second value
+// is false when no primary instance reported its replication slots
+func (list PostgresqlStatusList) InvalidatedReplicationSlots() ([]string, bool) {
+	var result []string
+	reported := false
+	for _, item := range list.Items {
+		if !item.IsPrimary || item.Error != nil {
+			continue
+		}
+		reported = true
+		for _, slot := range item.ReplicationSlotsInfo {
+			if slot.IsInvalidated() {
+				result = append(result, slot.SlotName)
+			}
+		}
+	}
+	return result, reported
+}
+
+// InstancesWithHighReplicationSlotsUsage returns the names of the instances
+// using at least ReplicationSlotsUsageThreshold percent of the replication
+// slots they allow. The second value is false when no instance reported
+// its replication slots usage
+func (list PostgresqlStatusList) InstancesWithHighReplicationSlotsUsage() ([]string, bool) {
+	var result []string
+	reported := false
+	for _, item := range list.Items {
+		if item.Error != nil || item.Pod == nil {
+			continue
+		}
+		usage, ok := item.GetReplicationSlotsUsage()
+		if !ok {
+			continue
+		}
+		reported = true
+		if usage >= ReplicationSlotsUsageThreshold {
+			result = append(result, item.Pod.Name)
+		}
+	}
+	return result, reported
+}
+
+// InstancesWithReplicationConflicts returns the names of the replicas which
+// reported a spike of queries canceled because of conflicts with recovery
+func (list PostgresqlStatusList) InstancesWithReplicationConflicts() []string {
+	var result []string
+	for _, item := range list.Items {
+		if item.IsPrimary || item.Pod == nil {
+			continue
+		}
+		if item.RecentReplicationConflicts >= ReplicationConflictsSpikeThreshold {
+			result = append(result, item.Pod.Name)
+		}
+	}
+	return result
+}
+
+// WritablePrimaries returns the names of the instances which reported
+// being a primary accepting writes, i.e. not in recovery. More than one
+// of them means that the cluster is in a split-brain condition
+func (list PostgresqlStatusList) WritablePrimaries() []string {
+	var result []string
+	for _, item := range list.Items {
+		if !item.IsPrimary || item.Error != nil || item.Pod == nil {
+			continue
+		}
+		result = append(result, item.Pod.Name)
+	}
+	return result
+}
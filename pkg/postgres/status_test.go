@@ -133,6 +133,118 @@ var _ = Describe("PostgreSQL status", func() {
 		Expect(podList.InstancesReportingStatus()).To(BeEquivalentTo(2))
 	})
 
+	It("detects the replicas with a spike of conflicts with recovery", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:                        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-10"}},
+					IsPrimary:                  true,
+					RecentReplicationConflicts: ReplicationConflictsSpikeThreshold,
+				},
+				{
+					Pod:                        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-20"}},
+					RecentReplicationConflicts: ReplicationConflictsSpikeThreshold - 1,
+				},
+				{
+					Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-30"}},
+				},
+			},
+		}
+		Expect(podList.InstancesWithReplicationConflicts()).To(BeEmpty())
+
+		podList.Items[2].RecentReplicationConflicts = ReplicationConflictsSpikeThreshold
+		Expect(podList.InstancesWithReplicationConflicts()).To(ConsistOf("server-30"))
+	})
+
+	It("detects the invalidated replication slots of the primary", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-10"}},
+					ReplicationSlotsInfo: PgReplicationSlotList{
+						{SlotName: "_cnpg_server_20", WalStatus: ReplicationSlotWALStatusLost},
+					},
+				},
+			},
+		}
+		slots, reported := podList.InvalidatedReplicationSlots()
+		Expect(reported).To(BeFalse())
+		Expect(slots).To(BeEmpty())
+
+		podList.Items[0].IsPrimary = true
+		podList.Items[0].ReplicationSlotsInfo = append(podList.Items[0].ReplicationSlotsInfo,
+			PgReplicationSlot{SlotName: "_cnpg_server_30", WalStatus: "reserved"},
+			PgReplicationSlot{SlotName: "_cnpg_server_40", WalStatus: "extended"},
+			PgReplicationSlot{SlotName: "_cnpg_server_50", WalStatus: "unreserved"},
+		)
+		slots, reported = podList.InvalidatedReplicationSlots()
+		Expect(reported).To(BeTrue())
+		Expect(slots).To(ConsistOf("_cnpg_server_20"))
+
+		podList.Items[0].ReplicationSlotsInfo = podList.Items[0].ReplicationSlotsInfo[1:]
+		slots, reported = podList.InvalidatedReplicationSlots()
+		Expect(reported).To(BeTrue())
+		Expect(slots).To(BeEmpty())
+	})
+
+	It("detects more than one writable primary", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-10"}},
+					IsPrimary: true,
+				},
+				{
+					Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-20"}},
+				},
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-30"}},
+					IsPrimary: true,
+					Error:     fmt.Errorf("cannot find postgres container"),
+				},
+			},
+		}
+		Expect(podList.WritablePrimaries()).To(ConsistOf("server-10"))
+
+		podList.Items[2].Error = nil
+		Expect(podList.WritablePrimaries()).To(ConsistOf("server-10", "server-30"))
+	})
+
+	It("detects the instances with a high usage of the replication slots", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-10"}},
+					IsPrimary:            true,
+					ReplicationSlotsUsed: 8,
+				},
+				{
+					Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-20"}},
+					ReplicationSlotsUsed: 7,
+					MaxReplicationSlots:  10,
+				},
+			},
+		}
+		usage, ok := podList.Items[1].GetReplicationSlotsUsage()
+		Expect(ok).To(BeTrue())
+		Expect(usage).To(BeEquivalentTo(70))
+
+		instances, reported := podList.InstancesWithHighReplicationSlotsUsage()
+		Expect(reported).To(BeTrue())
+		Expect(instances).To(BeEmpty())
+
+		podList.Items[0].MaxReplicationSlots = 10
+		instances, reported = podList.InstancesWithHighReplicationSlotsUsage()
+		Expect(reported).To(BeTrue())
+		Expect(instances).To(ConsistOf("server-10"))
+
+		podList.Items[0].Error = errCannotConnectToPostgres
+		podList.Items[1].MaxReplicationSlots = 0
+		instances, reported = podList.InstancesWithHighReplicationSlotsUsage()
+		Expect(reported).To(BeFalse())
+		Expect(instances).To(BeEmpty())
+	})
+
 	Describe("when sorted", func() {
 		sort.Sort(&list)
 
@@ -191,3 +303,90 @@ var _ = Describe("PostgreSQL status real", func() {
 		})
 	})
 })
+
+var _ = Describe("PostgreSQL status sorted with a preferred zone", func() {
+	nodes := map[string]corev1.Node{
+		"node-a": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-a",
+				Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"},
+			},
+		},
+		"node-b": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-b",
+				Labels: map[string]string{corev1.LabelTopologyZone: "zone-b"},
+			},
+		},
+	}
+
+	newStatus := func(name, node string, lsn LSN) PostgresqlStatus {
+		return PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			},
+			Node:        node,
+			ReceivedLsn: lsn,
+			ReplayLsn:   lsn,
+		}
+	}
+
+	podNames := func(list PostgresqlStatusList) []string {
+		result := make([]string, len(list.Items))
+		for idx := range list.Items {
+			result[idx] = list.Items[idx].Pod.Name
+		}
+		return result
+	}
+
+	It("prefers the replicas in the preferred zone when equally advanced", func() {
+		list := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				newStatus("server-1", "node-a", "0/6000000"),
+				newStatus("server-2", "node-b", "0/6000000"),
+				newStatus("server-3", "node-a", "0/5000000"),
+			},
+		}
+		list.SortWithPreferredZone(nodes, "zone-b")
+		Expect(podNames(list)).To(Equal([]string{"server-2", "server-1", "server-3"}))
+	})
+
+	It("never prefers a replica in the preferred zone which is behind", func() {
+		list := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				newStatus("server-1", "node-b", "0/5000000"),
+				newStatus("server-2", "node-a", "0/6000000"),
+			},
+		}
+		list.SortWithPreferredZone(nodes, "zone-b")
+		Expect(podNames(list)).To(Equal([]string{"server-2", "server-1"}))
+	})
+
+	It("keeps the primary and the incomplete statuses in their place", func() {
+		primary := newStatus("server-1", "node-a", "")
+		primary.IsPrimary = true
+		incomplete := newStatus("server-2", "node-b", "")
+		incomplete.Error = fmt.Errorf("cannot connect to PostgreSQL")
+		list := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				incomplete,
+				newStatus("server-3", "node-a", "0/6000000"),
+				primary,
+				newStatus("server-4", "node-b", "0/6000000"),
+			},
+		}
+		list.SortWithPreferredZone(nodes, "zone-b")
+		Expect(podNames(list)).To(Equal([]string{"server-1", "server-4", "server-3", "server-2"}))
+	})
+
+	It("falls back to the Pod name when no replica is in the preferred zone", func() {
+		list := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				newStatus("server-2", "node-b", "0/6000000"),
+				newStatus("server-1", "node-unknown", "0/6000000"),
+			},
+		}
+		list.SortWithPreferredZone(nodes, "zone-c")
+		Expect(podNames(list)).To(Equal([]string{"server-1", "server-2"}))
+	})
+})
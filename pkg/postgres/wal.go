@@ -144,6 +144,25 @@ func MustSegmentFromName(name string) Segment {
 	return result
 }
 
+// SegmentFromLSN gets the WAL segment, in the passed timeline, containing
+// the passed LSN, given the size of the WAL segments
+func SegmentFromLSN(lsn LSN, timeline int32, walSegmentSize int64) (Segment, error) {
+	if walSegmentSize <= 0 {
+		return Segment{}, fmt.Errorf("invalid WAL segment size: %v", walSegmentSize)
+	}
+
+	position, err := lsn.Parse()
+	if err != nil {
+		return Segment{}, err
+	}
+
+	return Segment{
+		Tli: timeline,
+		Log: int32(position >> 32),
+		Seg: int32((position & 0xFFFFFFFF) / walSegmentSize),
+	}, nil
+}
+
 // Name gets the name of the segment
 func (segment Segment) Name() string {
 	return fmt.Sprintf("%08X%08X%08X", segment.Tli, segment.Log, segment.Seg)
@@ -244,3 +244,22 @@ var _ = Describe("WAL files checking", func() {
 		}
 	})
 })
+
+var _ = Describe("Segment from LSN", func() {
+	It("finds the WAL file containing an LSN", func() {
+		Expect(SegmentFromLSN("0/2000028", 1, DefaultWALSegmentSize)).To(
+			Equal(MustSegmentFromName("000000010000000000000002")))
+		Expect(SegmentFromLSN("1A/FF000000", 3, DefaultWALSegmentSize)).To(
+			Equal(MustSegmentFromName("000000030000001A000000FF")))
+		Expect(SegmentFromLSN("0/8000000", 2, 1<<26)).To(
+			Equal(MustSegmentFromName("000000020000000000000002")))
+	})
+
+	It("complains when the LSN or the segment size are not valid", func() {
+		_, err := SegmentFromLSN("invalid", 1, DefaultWALSegmentSize)
+		Expect(err).To(HaveOccurred())
+
+		_, err = SegmentFromLSN("0/2000028", 1, 0)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "cnpg"
+
+// Collector is a Prometheus collector exposing the time of the last
+// successful backup of every cluster, and the number of seconds elapsed
+// since then, computed when the metrics are scraped.
+// Clusters that never had a successful backup are not reported
+type Collector struct {
+	mu                   sync.Mutex
+	lastSuccessfulBackup map[types.NamespacedName]time.Time
+	now                  func() time.Time
+
+	lastSuccessfulBackupTimestamp *prometheus.Desc
+	timeSinceLastBackup           *prometheus.Desc
+}
+
+// BackupCollector is the collector registered in the metrics registry
+// of the operator
+var BackupCollector = newCollector(time.Now)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(BackupCollector)
+}
+
+func newCollector(now func() time.Time) *Collector {
+	labels := []string{"namespace", "cluster"}
+	return &Collector{
+		lastSuccessfulBackup: make(map[types.NamespacedName]time.Time),
+		now:                  now,
+		lastSuccessfulBackupTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "last_successful_backup_timestamp"),
+			"The end time of the last successful backup of the cluster as a unix timestamp",
+			labels, nil,
+		),
+		timeSinceLastBackup: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "time_since_last_backup_seconds"),
+			"Number of seconds elapsed since the end of the last successful backup of the cluster",
+			labels, nil,
+		),
+	}
+}
+
+// SetLastSuccessfulBackup records the end time of the last successful
+// backup of a cluster. Times older than the recorded one are ignored,
+// so the metrics never go back in time
+func (c *Collector) SetLastSuccessfulBackup(cluster types.NamespacedName, stoppedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, ok := c.lastSuccessfulBackup[cluster]; ok && !stoppedAt.After(current) {
+		return
+	}
+	c.lastSuccessfulBackup[cluster] = stoppedAt
+}
+
+// Forget stops reporting the metrics of a cluster
+func (c *Collector) Forget(cluster types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.lastSuccessfulBackup, cluster)
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lastSuccessfulBackupTimestamp
+	ch <- c.timeSinceLastBackup
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for cluster, stoppedAt := range c.lastSuccessfulBackup {
+		ch <- prometheus.MustNewConstMetric(
+			c.lastSuccessfulBackupTimestamp, prometheus.GaugeValue,
+			float64(stoppedAt.Unix()), cluster.Namespace, cluster.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.timeSinceLastBackup, prometheus.GaugeValue,
+			now.Sub(stoppedAt).Seconds(), cluster.Namespace, cluster.Name,
+		)
+	}
+}
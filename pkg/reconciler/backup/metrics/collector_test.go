@@ -0,0 +1,76 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup metrics collector", func() {
+	now := time.Unix(1700000000, 0)
+	cluster := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+
+	var collector *Collector
+	BeforeEach(func() {
+		collector = newCollector(func() time.Time { return now })
+	})
+
+	It("doesn't report clusters without a successful backup", func() {
+		Expect(testutil.CollectAndCount(collector)).To(BeZero())
+	})
+
+	It("reports the last successful backup and the time since it ended", func() {
+		collector.SetLastSuccessfulBackup(cluster, now.Add(-90*time.Minute))
+
+		expected := `
+# HELP cnpg_last_successful_backup_timestamp The end time of the last successful backup of the cluster as a unix timestamp
+# TYPE cnpg_last_successful_backup_timestamp gauge
+cnpg_last_successful_backup_timestamp{cluster="cluster-example",namespace="default"} 1.6999946e+09
+# HELP cnpg_time_since_last_backup_seconds Number of seconds elapsed since the end of the last successful backup of the cluster
+# TYPE cnpg_time_since_last_backup_seconds gauge
+cnpg_time_since_last_backup_seconds{cluster="cluster-example",namespace="default"} 5400
+`
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected))).To(Succeed())
+	})
+
+	It("doesn't go back in time", func() {
+		collector.SetLastSuccessfulBackup(cluster, now.Add(-time.Hour))
+		collector.SetLastSuccessfulBackup(cluster, now.Add(-2*time.Hour))
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP cnpg_time_since_last_backup_seconds Number of seconds elapsed since the end of the last successful backup of the cluster
+# TYPE cnpg_time_since_last_backup_seconds gauge
+cnpg_time_since_last_backup_seconds{cluster="cluster-example",namespace="default"} 3600
+`), "cnpg_time_since_last_backup_seconds")).To(Succeed())
+	})
+
+	It("stops reporting forgotten clusters", func() {
+		collector.SetLastSuccessfulBackup(cluster, now.Add(-time.Hour))
+		collector.SetLastSuccessfulBackup(types.NamespacedName{Namespace: "default", Name: "other"}, now)
+		Expect(testutil.CollectAndCount(collector)).To(Equal(4))
+
+		collector.Forget(cluster)
+		Expect(testutil.CollectAndCount(collector)).To(Equal(2))
+	})
+})
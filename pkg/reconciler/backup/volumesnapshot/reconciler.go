@@ -250,6 +250,7 @@ func (se *Reconciler) prepareSnapshotBackupStep(
 			WaitForArchive:      volumeSnapshotConfig.OnlineConfiguration.GetWaitForArchive(),
 			BackupName:          backup.Name,
 			Force:               true,
+			HoldTimeoutSeconds:  int(volumeSnapshotConfig.OnlineConfiguration.GetHoldTimeoutSeconds()),
 		}
 		if _, err := se.backupClient.Start(ctx, targetPod.Status.PodIP, req); err != nil {
 			return nil, fmt.Errorf("while trying to start the backup: %w", err)
@@ -299,6 +300,8 @@ func (se *Reconciler) finalizeSnapshotBackupStep(
 
 		backup.Status.BeginLSN = string(status.BeginLSN)
 		backup.Status.EndLSN = string(status.EndLSN)
+		backup.Status.BeginWal = status.BeginWal
+		backup.Status.EndWal = status.EndWal
 		backup.Status.TablespaceMapFile = status.SpcmapFile
 		backup.Status.BackupLabelFile = status.LabelFile
 	}
@@ -358,6 +361,15 @@ func annotateSnapshotsWithBackupData(
 		snapshot.Annotations[utils.BackupStartTimeAnnotationName] = backupStatus.StartedAt.Format(time.RFC3339)
 		snapshot.Annotations[utils.BackupEndTimeAnnotationName] = backupStatus.StoppedAt.Format(time.RFC3339)
 
+		// the WAL files reported by PostgreSQL at the end of an online
+		// backup are more accurate than the ones from pg_controldata
+		if backupStatus.BeginWal != "" {
+			snapshot.Annotations[utils.BackupStartWALAnnotationName] = backupStatus.BeginWal
+		}
+		if backupStatus.EndWal != "" {
+			snapshot.Annotations[utils.BackupEndWALAnnotationName] = backupStatus.EndWal
+		}
+
 		if len(backupStatus.BackupLabelFile) > 0 {
 			snapshot.Annotations[utils.BackupLabelFileAnnotationName] = base64.StdEncoding.EncodeToString(
 				backupStatus.BackupLabelFile)
@@ -388,11 +400,15 @@ func backupStatusFromSnapshots(
 	}
 	pairs := utils.ParsePgControldataOutput(controldata)
 
-	// TODO: calculate wal in case of online backup
 	// the begin/end WAL and LSN are the same, since the instance was fenced
-	// for the snapshot
-	backupStatus.BeginWal = pairs["Latest checkpoint's REDO WAL file"]
-	backupStatus.EndWal = pairs["Latest checkpoint's REDO WAL file"]
+	// for the snapshot. The online backups report them when PostgreSQL
+	// can compute them, i.e. not on a standby
+	if backupStatus.BeginWal == "" {
+		backupStatus.BeginWal = pairs["Latest checkpoint's REDO WAL file"]
+	}
+	if backupStatus.EndWal == "" {
+		backupStatus.EndWal = pairs["Latest checkpoint's REDO WAL file"]
+	}
 
 	if !backupStatus.GetOnline() {
 		backupStatus.BeginLSN = pairs["Latest checkpoint's REDO location"]
@@ -511,7 +527,8 @@ func (se *Reconciler) waitForPodToBeFenced(
 }
 
 // snapshotPVCGroup creates a volumeSnapshot resource for every PVC
-// used by the Pod
+// used by the Pod, except the one hosting the PostgreSQL logs that
+// is not needed to restore the instance
 func (se *Reconciler) createSnapshotPVCGroupStep(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -520,6 +537,10 @@ func (se *Reconciler) createSnapshotPVCGroupStep(
 	targetPod *corev1.Pod,
 ) error {
 	for i := range pvcs {
+		if utils.PVCRole(pvcs[i].Labels[utils.PvcRoleLabelName]) == utils.PVCRolePgLog {
+			continue
+		}
+
 		se.recorder.Eventf(backup, "Normal", "CreateSnapshot",
 			"Creating VolumeSnapshot for PVC %v", pvcs[i].Name)
 
@@ -463,4 +463,16 @@ var _ = Describe("annotateSnapshotsWithBackupData", func() {
 			Expect(snapshot.Annotations[utils.BackupEndTimeAnnotationName]).To(BeEquivalentTo(stoppedAt.Format(time.RFC3339)))
 		}
 	})
+
+	It("should annotate the snapshots with the WAL files reported by the online backup", func(ctx context.Context) {
+		backupStatus.BeginWal = "000000010000000000000002"
+		backupStatus.EndWal = "000000010000000000000003"
+		err := annotateSnapshotsWithBackupData(ctx, fakeClient, snapshots.Items, backupStatus)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, snapshot := range snapshots.Items {
+			Expect(snapshot.Annotations[utils.BackupStartWALAnnotationName]).To(Equal("000000010000000000000002"))
+			Expect(snapshot.Annotations[utils.BackupEndWALAnnotationName]).To(Equal("000000010000000000000003"))
+		}
+	})
 })
@@ -0,0 +1,150 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const namespace = "cnpg"
+
+// ErrorType is the kind of error that interrupted a reconciliation loop
+type ErrorType string
+
+const (
+	// ErrorTypeTransient is used when the reconciliation loop has been
+	// interrupted to be retried later, i.e. waiting for the instances or
+	// because of a conflict while updating an object
+	ErrorTypeTransient ErrorType = "transient"
+
+	// ErrorTypeHard is used for any other error
+	ErrorTypeHard ErrorType = "hard"
+)
+
+// Collector is a Prometheus collector exposing the number of reconciliation
+// loops of every cluster that ended with an error, by type of error, and the
+// time when the last reconciliation loop ended
+type Collector struct {
+	mu            sync.Mutex
+	errors        map[types.NamespacedName]map[ErrorType]int
+	lastReconcile map[types.NamespacedName]time.Time
+	now           func() time.Time
+
+	reconcileErrors        *prometheus.Desc
+	lastReconcileTimestamp *prometheus.Desc
+}
+
+// ReconcileCollector is the collector registered in the metrics registry
+// of the operator
+var ReconcileCollector = newCollector(time.Now)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(ReconcileCollector)
+}
+
+func newCollector(now func() time.Time) *Collector {
+	labels := []string{"namespace", "cluster"}
+	return &Collector{
+		errors:        make(map[types.NamespacedName]map[ErrorType]int),
+		lastReconcile: make(map[types.NamespacedName]time.Time),
+		now:           now,
+		reconcileErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "reconcile_errors_total"),
+			"Number of reconciliation loops of the cluster that ended with an error, "+
+				"either transient (requeued) or hard",
+			append(labels, "type"), nil,
+		),
+		lastReconcileTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "last_reconcile_timestamp"),
+			"The time when the last reconciliation loop of the cluster ended as a unix timestamp",
+			labels, nil,
+		),
+	}
+}
+
+// classifyError gets the type of error that interrupted a reconciliation loop
+func classifyError(err error) ErrorType {
+	if errors.Is(err, utils.ErrNextLoop) || apierrors.IsConflict(err) {
+		return ErrorTypeTransient
+	}
+
+	return ErrorTypeHard
+}
+
+// RecordReconcile records the end of a reconciliation loop of a cluster,
+// counting the error that interrupted it, if any
+func (c *Collector) RecordReconcile(cluster types.NamespacedName, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastReconcile[cluster] = c.now()
+
+	// The counters of a cluster start from zero for all the error types,
+	// so that they are reported even before the first error
+	if _, ok := c.errors[cluster]; !ok {
+		c.errors[cluster] = map[ErrorType]int{ErrorTypeTransient: 0, ErrorTypeHard: 0}
+	}
+	if err != nil {
+		c.errors[cluster][classifyError(err)]++
+	}
+}
+
+// Forget stops reporting the metrics of a cluster
+func (c *Collector) Forget(cluster types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.errors, cluster)
+	delete(c.lastReconcile, cluster)
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.reconcileErrors
+	ch <- c.lastReconcileTimestamp
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for cluster, counters := range c.errors {
+		for errorType, count := range counters {
+			ch <- prometheus.MustNewConstMetric(
+				c.reconcileErrors, prometheus.CounterValue,
+				float64(count), cluster.Namespace, cluster.Name, string(errorType),
+			)
+		}
+	}
+
+	for cluster, lastReconcile := range c.lastReconcile {
+		ch <- prometheus.MustNewConstMetric(
+			c.lastReconcileTimestamp, prometheus.GaugeValue,
+			float64(lastReconcile.Unix()), cluster.Namespace, cluster.Name,
+		)
+	}
+}
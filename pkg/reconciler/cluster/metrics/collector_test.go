@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster reconciliation metrics collector", func() {
+	now := time.Unix(1700000000, 0)
+	cluster := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+
+	var collector *Collector
+	BeforeEach(func() {
+		collector = newCollector(func() time.Time { return now })
+	})
+
+	errorsMetric := func(transient, hard int) string {
+		return fmt.Sprintf(`
+# HELP cnpg_cluster_reconcile_errors_total Number of reconciliation loops of the cluster that ended with an error, either transient (requeued) or hard
+# TYPE cnpg_cluster_reconcile_errors_total counter
+cnpg_cluster_reconcile_errors_total{cluster="cluster-example",namespace="default",type="hard"} %d
+cnpg_cluster_reconcile_errors_total{cluster="cluster-example",namespace="default",type="transient"} %d
+`, hard, transient)
+	}
+
+	It("doesn't report clusters never reconciled", func() {
+		Expect(testutil.CollectAndCount(collector)).To(BeZero())
+	})
+
+	It("doesn't count the clean reconciliation loops", func() {
+		collector.RecordReconcile(cluster, nil)
+
+		expected := errorsMetric(0, 0) + `
+# HELP cnpg_cluster_last_reconcile_timestamp The time when the last reconciliation loop of the cluster ended as a unix timestamp
+# TYPE cnpg_cluster_last_reconcile_timestamp gauge
+cnpg_cluster_last_reconcile_timestamp{cluster="cluster-example",namespace="default"} 1.7e+09
+`
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected))).To(Succeed())
+	})
+
+	It("counts the hard errors", func() {
+		collector.RecordReconcile(cluster, nil)
+		collector.RecordReconcile(cluster, errors.New("cannot create the pod"))
+		collector.RecordReconcile(cluster, errors.New("cannot create the pod"))
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(errorsMetric(0, 2)),
+			"cnpg_cluster_reconcile_errors_total")).To(Succeed())
+	})
+
+	It("counts the requeues and the conflicts as transient errors", func() {
+		conflict := apierrors.NewConflict(
+			schema.GroupResource{Group: "postgresql.cnpg.io", Resource: "clusters"},
+			cluster.Name, errors.New("the object has been modified"))
+		collector.RecordReconcile(cluster, utils.ErrNextLoop)
+		collector.RecordReconcile(cluster, fmt.Errorf("while updating the status: %w", conflict))
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(errorsMetric(2, 0)),
+			"cnpg_cluster_reconcile_errors_total")).To(Succeed())
+	})
+
+	It("stops reporting forgotten clusters", func() {
+		collector.RecordReconcile(cluster, nil)
+		collector.RecordReconcile(types.NamespacedName{Namespace: "default", Name: "other"}, nil)
+		Expect(testutil.CollectAndCount(collector)).To(Equal(6))
+
+		collector.Forget(cluster)
+		Expect(testutil.CollectAndCount(collector)).To(Equal(3))
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskpressure
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Switchover describes a switchover needed because the data volume
+// of the primary instance is nearly full
+type Switchover struct {
+	// The current primary instance
+	Primary *postgres.PostgresqlStatus
+
+	// The usage percentage of the data volume of the current primary
+	PrimaryUsage float64
+
+	// The replica to be promoted
+	Target *postgres.PostgresqlStatus
+
+	// The usage percentage of the data volume of the replica to be promoted
+	TargetUsage float64
+}
+
+// GetSwitchover checks if the primary instance is running out of disk space
+// and a replica with enough free space exists, returning the switchover to
+// be executed or nil if not needed.
+//
+// To avoid switching over back and forth, the replica must be below the
+// low watermark and no new primary must have been requested during the
+// cooldown period
+func GetSwitchover(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	now time.Time,
+) *Switchover {
+	configuration := cluster.Spec.DiskPressureSwitchover
+	if !configuration.IsEnabled() {
+		return nil
+	}
+
+	// Don't interfere with switchovers or failovers already in progress
+	if cluster.Status.CurrentPrimary == "" ||
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		return nil
+	}
+
+	if isInCooldownPeriod(cluster, configuration.GetCooldownPeriod(), now) {
+		return nil
+	}
+
+	var result Switchover
+	for i := range status.Items {
+		if status.Items[i].Pod != nil && status.Items[i].Pod.Name == cluster.Status.CurrentPrimary {
+			result.Primary = &status.Items[i]
+			break
+		}
+	}
+	if result.Primary == nil || !result.Primary.HasHTTPStatus() || !result.Primary.IsPrimary {
+		return nil
+	}
+
+	primaryUsage, ok := result.Primary.GetDataDiskUsage()
+	if !ok || primaryUsage < float64(configuration.GetHighWatermark()) {
+		return nil
+	}
+	result.PrimaryUsage = primaryUsage
+
+	// Choose the replica with the most free space, preferring the
+	// most advanced one when more replicas have the same free space
+	for i := range status.Items {
+		candidate := &status.Items[i]
+		if candidate == result.Primary || !isValidCandidate(candidate) {
+			continue
+		}
+
+		usage, ok := candidate.GetDataDiskUsage()
+		if !ok || usage >= float64(configuration.GetLowWatermark()) {
+			continue
+		}
+
+		if candidate.DataDiskAvailableBytes <= result.Primary.DataDiskAvailableBytes {
+			continue
+		}
+
+		if result.Target == nil || candidate.DataDiskAvailableBytes > result.Target.DataDiskAvailableBytes {
+			result.Target = candidate
+			result.TargetUsage = usage
+		}
+	}
+
+	if result.Target == nil {
+		return nil
+	}
+
+	return &result
+}
+
+// isValidCandidate checks if a replica is healthy enough to be promoted
+func isValidCandidate(candidate *postgres.PostgresqlStatus) bool {
+	return candidate.Pod != nil &&
+		candidate.HasHTTPStatus() &&
+		!candidate.IsPrimary &&
+		!candidate.MightBeUnavailable &&
+		candidate.IsPodReady &&
+		utils.IsPodActive(*candidate.Pod)
+}
+
+// isInCooldownPeriod checks if a new primary has been requested
+// too recently to switch over again
+func isInCooldownPeriod(cluster *apiv1.Cluster, cooldownPeriod time.Duration, now time.Time) bool {
+	if cluster.Status.TargetPrimaryTimestamp == "" {
+		return false
+	}
+
+	lastRequest, err := time.Parse(metav1.RFC3339Micro, cluster.Status.TargetPrimaryTimestamp)
+	if err != nil {
+		// If we can't parse the timestamp, we stay on the safe side
+		return true
+	}
+
+	return now.Sub(lastRequest) < cooldownPeriod
+}
@@ -0,0 +1,203 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskpressure
+
+import (
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const gigabyte = 1024 * 1024 * 1024
+
+var _ = Describe("disk pressure switchover decision", func() {
+	var (
+		now     time.Time
+		cluster *apiv1.Cluster
+	)
+
+	// newInstance creates the status of an instance using usedGB of a 100GB volume
+	newInstance := func(name string, isPrimary bool, usedGB uint64) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			},
+			IsPrimary:              isPrimary,
+			IsPodReady:             true,
+			DataDiskTotalBytes:     100 * gigabyte,
+			DataDiskAvailableBytes: (100 - usedGB) * gigabyte,
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				DiskPressureSwitchover: &apiv1.DiskPressureSwitchoverConfiguration{
+					Enabled:       true,
+					HighWatermark: 90,
+					LowWatermark:  80,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:         "cluster-example-1",
+				TargetPrimary:          "cluster-example-1",
+				TargetPrimaryTimestamp: now.Add(-time.Hour).Format(metav1.RFC3339Micro),
+			},
+		}
+	})
+
+	It("switches over to the replica with the most free space", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				newInstance("cluster-example-2", false, 70),
+				newInstance("cluster-example-3", false, 50),
+			},
+		}
+
+		switchover := GetSwitchover(cluster, status, now)
+		Expect(switchover).ToNot(BeNil())
+		Expect(switchover.Primary.Pod.Name).To(Equal("cluster-example-1"))
+		Expect(switchover.PrimaryUsage).To(BeNumerically("~", 95, 0.01))
+		Expect(switchover.Target.Pod.Name).To(Equal("cluster-example-3"))
+		Expect(switchover.TargetUsage).To(BeNumerically("~", 50, 0.01))
+	})
+
+	It("prefers the most advanced replica when the free space is the same", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				newInstance("cluster-example-3", false, 50),
+				newInstance("cluster-example-2", false, 50),
+			},
+		}
+
+		switchover := GetSwitchover(cluster, status, now)
+		Expect(switchover).ToNot(BeNil())
+		Expect(switchover.Target.Pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("doesn't switch over when the primary is below the high watermark", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 89),
+				newInstance("cluster-example-2", false, 10),
+			},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+	})
+
+	It("doesn't switch over to replicas above the low watermark", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				newInstance("cluster-example-2", false, 80),
+				newInstance("cluster-example-3", false, 85),
+			},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+	})
+
+	It("doesn't switch over to replicas that are not healthy", func() {
+		notReady := newInstance("cluster-example-2", false, 10)
+		notReady.IsPodReady = false
+
+		unreachable := newInstance("cluster-example-3", false, 10)
+		unreachable.Error = errors.New("connection refused")
+
+		mightBeUnavailable := newInstance("cluster-example-4", false, 10)
+		mightBeUnavailable.MightBeUnavailable = true
+
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				notReady,
+				unreachable,
+				mightBeUnavailable,
+			},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+	})
+
+	It("doesn't switch over when the disk usage is not reported", func() {
+		primary := newInstance("cluster-example-1", true, 95)
+		primary.DataDiskTotalBytes = 0
+
+		replica := newInstance("cluster-example-2", false, 10)
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{primary, replica},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+
+		primary = newInstance("cluster-example-1", true, 95)
+		replica.DataDiskTotalBytes = 0
+		status = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{primary, replica},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+	})
+
+	It("doesn't switch over during the cooldown period", func() {
+		cluster.Spec.DiskPressureSwitchover.CooldownPeriod = ptr.To(int32(3600))
+		cluster.Status.TargetPrimaryTimestamp = now.Add(-30 * time.Minute).Format(metav1.RFC3339Micro)
+
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				newInstance("cluster-example-2", false, 10),
+			},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+
+		Expect(GetSwitchover(cluster, status, now.Add(31*time.Minute))).ToNot(BeNil())
+	})
+
+	It("doesn't switch over when a switchover is already in progress", func() {
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				newInstance("cluster-example-2", false, 10),
+			},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+	})
+
+	It("doesn't switch over when the feature is not enabled", func() {
+		cluster.Spec.DiskPressureSwitchover.Enabled = false
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("cluster-example-1", true, 95),
+				newInstance("cluster-example-2", false, 10),
+			},
+		}
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+
+		cluster.Spec.DiskPressureSwitchover = nil
+		Expect(GetSwitchover(cluster, status, now)).To(BeNil())
+	})
+})
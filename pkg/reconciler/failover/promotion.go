@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"errors"
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrDataLossThresholdExceeded is raised when no replica can be promoted
+// within the maximum data loss allowed and the policy is fail-closed
+var ErrDataLossThresholdExceeded = errors.New(
+	"no replica is within the maximum data loss allowed, waiting before triggering a failover")
+
+// UnknownDataLoss is the data loss of a promotion when the last LSN of
+// the primary is not known
+const UnknownDataLoss = -1
+
+// Promotion describes the replica chosen to replace the failed primary
+type Promotion struct {
+	// The replica to be promoted
+	Target *postgres.PostgresqlStatus
+
+	// The amount of WAL, in bytes, between the last LSN reported by the
+	// primary and the flush LSN of the target, or UnknownDataLoss
+	DataLossBytes int64
+
+	// True when the target is promoted even if it exceeds the maximum data
+	// loss allowed, because the policy is fail-open
+	ThresholdExceeded bool
+}
+
+// GetPromotion chooses the most advanced replica, with the highest flush
+// LSN, and checks it against the maximum data loss allowed. When the target
+// exceeds it and the policy is fail-closed, ErrDataLossThresholdExceeded is
+// returned together with the promotion that has been refused.
+//
+// If the last LSN of the primary has not been recorded, the data loss can't
+// be estimated and the most advanced replica is promoted
+func GetPromotion(cluster *apiv1.Cluster, status postgres.PostgresqlStatusList) (*Promotion, error) {
+	var (
+		result     Promotion
+		targetLSN  int64
+		configured = cluster.Spec.Failover
+	)
+
+	for i := range status.Items {
+		candidate := &status.Items[i]
+		if !isValidCandidate(cluster, candidate) {
+			continue
+		}
+
+		lsn, err := getFlushLSN(candidate).Parse()
+		if err != nil {
+			continue
+		}
+
+		// The list is already sorted, so we keep the first of the
+		// replicas with the same flush LSN
+		if result.Target == nil || lsn > targetLSN {
+			result.Target = candidate
+			targetLSN = lsn
+		}
+	}
+
+	if result.Target == nil {
+		return nil, fmt.Errorf("no replica is reporting a valid flush LSN")
+	}
+
+	result.DataLossBytes = UnknownDataLoss
+	primaryLSN, err := postgres.LSN(cluster.Status.LastPrimaryLSN).Parse()
+	if err != nil {
+		return &result, nil
+	}
+
+	result.DataLossBytes = primaryLSN - targetLSN
+	if result.DataLossBytes < 0 {
+		result.DataLossBytes = 0
+	}
+
+	if !configured.IsDataLossBounded() || result.DataLossBytes <= *configured.MaxDataLossBytes {
+		return &result, nil
+	}
+
+	if configured.GetDataLossPolicy() == apiv1.FailoverDataLossPolicyFailOpen {
+		result.ThresholdExceeded = true
+		return &result, nil
+	}
+
+	return &result, ErrDataLossThresholdExceeded
+}
+
+// isValidCandidate checks if the instance can be promoted
+func isValidCandidate(cluster *apiv1.Cluster, candidate *postgres.PostgresqlStatus) bool {
+	return candidate.Pod != nil &&
+		candidate.Pod.Name != cluster.Status.CurrentPrimary &&
+		candidate.HasHTTPStatus() &&
+		!candidate.IsPrimary
+}
+
+// getFlushLSN gets the last WAL location received and flushed to disk
+// by the replica, falling back to the replayed one when the replica
+// isn't streaming
+func getFlushLSN(candidate *postgres.PostgresqlStatus) postgres.LSN {
+	if candidate.ReceivedLsn != "" {
+		return candidate.ReceivedLsn
+	}
+	return candidate.ReplayLsn
+}
@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover candidate selection", func() {
+	var cluster *apiv1.Cluster
+
+	newReplica := func(name string, receivedLSN postgres.LSN) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			},
+			ReceivedLsn: receivedLSN,
+			ReplayLsn:   receivedLSN,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Failover: &apiv1.FailoverConfiguration{
+					MaxDataLossBytes: ptr.To(int64(1024)),
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  apiv1.PendingFailoverMarker,
+				LastPrimaryLSN: "0/3001000",
+			},
+		}
+	})
+
+	It("promotes the most advanced replica within the threshold", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newReplica("cluster-example-2", "0/3000000"),
+				newReplica("cluster-example-3", "0/3000C00"),
+			},
+		}
+
+		promotion, err := GetPromotion(cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promotion.Target.Pod.Name).To(Equal("cluster-example-3"))
+		Expect(promotion.DataLossBytes).To(BeEquivalentTo(0x400))
+		Expect(promotion.ThresholdExceeded).To(BeFalse())
+	})
+
+	It("prefers the first replica of the list when the LSNs are the same", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newReplica("cluster-example-3", "0/3001000"),
+				newReplica("cluster-example-2", "0/3001000"),
+			},
+		}
+
+		promotion, err := GetPromotion(cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promotion.Target.Pod.Name).To(Equal("cluster-example-3"))
+		Expect(promotion.DataLossBytes).To(BeZero())
+	})
+
+	It("skips the failed primary and the instances not reporting their status", func() {
+		failedPrimary := newReplica("cluster-example-1", "0/3001000")
+		failedPrimary.IsPrimary = true
+		unreachable := newReplica("cluster-example-2", "0/3001000")
+		unreachable.Error = errors.New("unreachable")
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				failedPrimary,
+				unreachable,
+				newReplica("cluster-example-3", "0/3000F00"),
+			},
+		}
+
+		promotion, err := GetPromotion(cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promotion.Target.Pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("uses the replayed LSN when the replica isn't streaming", func() {
+		replica := newReplica("cluster-example-2", "")
+		replica.ReplayLsn = "0/3000F00"
+
+		promotion, err := GetPromotion(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{replica},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promotion.DataLossBytes).To(BeEquivalentTo(0x100))
+	})
+
+	It("blocks the failover when the policy is fail-closed", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newReplica("cluster-example-2", "0/2000000"),
+				newReplica("cluster-example-3", "0/1000000"),
+			},
+		}
+
+		promotion, err := GetPromotion(cluster, status)
+		Expect(err).To(MatchError(ErrDataLossThresholdExceeded))
+		Expect(promotion.Target.Pod.Name).To(Equal("cluster-example-2"))
+		Expect(promotion.DataLossBytes).To(BeEquivalentTo(0x1001000))
+	})
+
+	It("promotes the most advanced replica anyway when the policy is fail-open", func() {
+		cluster.Spec.Failover.DataLossPolicy = apiv1.FailoverDataLossPolicyFailOpen
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newReplica("cluster-example-2", "0/2000000"),
+			},
+		}
+
+		promotion, err := GetPromotion(cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promotion.Target.Pod.Name).To(Equal("cluster-example-2"))
+		Expect(promotion.ThresholdExceeded).To(BeTrue())
+	})
+
+	It("promotes the most advanced replica when the LSN of the primary is not known", func() {
+		cluster.Status.LastPrimaryLSN = ""
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newReplica("cluster-example-2", "0/2000000"),
+			},
+		}
+
+		promotion, err := GetPromotion(cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promotion.DataLossBytes).To(BeEquivalentTo(UnknownDataLoss))
+	})
+
+	It("fails when no replica reports a valid LSN", func() {
+		_, err := GetPromotion(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{newReplica("cluster-example-2", "")},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(ErrDataLossThresholdExceeded))
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// BuildBackupVolume builds the PVC dedicated to the backups of a cluster.
+// The PVC is not owned by the cluster, so that the backups are not lost
+// when the cluster is deleted
+func BuildBackupVolume(cluster *apiv1.Cluster) (*corev1.PersistentVolumeClaim, error) {
+	storage := cluster.Spec.Backup.VolumeBackup.Storage
+
+	builder := resources.NewPersistentVolumeClaimBuilder().
+		BeginMetadata().
+		WithNamespacedName(cluster.GetBackupVolumeName(), cluster.Namespace).
+		WithLabels(map[string]string{
+			utils.PvcRoleLabelName: string(utils.PVCRolePgBackup),
+		}).
+		EndMetadata().
+		WithSpec(storage.PersistentVolumeClaimTemplate)
+
+	// A single instance doesn't need a shared volume, and the webhook
+	// requires the access modes to be set when there are more of them
+	if template := storage.PersistentVolumeClaimTemplate; template == nil || len(template.AccessModes) == 0 {
+		builder = builder.WithAccessModes(corev1.ReadWriteOnce)
+	}
+
+	// If the customer specified a storage class, let's use it
+	if storage.StorageClass != nil {
+		builder = builder.WithStorageClass(storage.StorageClass)
+	}
+
+	if storage.Size != "" {
+		parsedSize, err := resource.ParseQuantity(storage.Size)
+		if err != nil {
+			return nil, ErrorInvalidSize
+		}
+		builder = builder.WithRequests(corev1.ResourceList{
+			corev1.ResourceStorage: parsedSize,
+		})
+	}
+
+	pvc := builder.Build()
+	if pvc.Spec.Resources.Requests.Storage().IsZero() {
+		return nil, ErrorInvalidSize
+	}
+	cluster.SetInheritedData(&pvc.ObjectMeta)
+
+	return pvc, nil
+}
+
+// ReconcileBackupVolume creates the PVC dedicated to the backups when
+// it's requested and missing, and expands it when its size is increased
+func ReconcileBackupVolume(ctx context.Context, c client.Client, cluster *apiv1.Cluster) error {
+	if !cluster.ShouldCreateBackupVolume() {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	expectedPVC, err := BuildBackupVolume(cluster)
+	if err == ErrorInvalidSize {
+		// This error should have been caught by the validating webhook
+		contextLogger.Info("The size specified for the backup volume is not valid",
+			"size", cluster.Spec.Backup.VolumeBackup.Storage.Size)
+		return utils.ErrNextLoop
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create the spec of the backup volume: %w", err)
+	}
+
+	var pvc corev1.PersistentVolumeClaim
+	err = c.Get(ctx, client.ObjectKeyFromObject(expectedPVC), &pvc)
+	if apierrs.IsNotFound(err) {
+		contextLogger.Info("Creating the backup volume", "pvcName", expectedPVC.Name)
+		if err := c.Create(ctx, expectedPVC); err != nil && !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("unable to create the backup volume %s: %w", expectedPVC.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	expectedSize := expectedPVC.Spec.Resources.Requests.Storage()
+	if pvc.Spec.Resources.Requests.Storage().Cmp(*expectedSize) >= 0 {
+		return nil
+	}
+
+	contextLogger.Info("Expanding the backup volume",
+		"pvcName", pvc.Name,
+		"size", expectedSize.String())
+	origPVC := pvc.DeepCopy()
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *expectedSize
+	return c.Patch(ctx, &pvc, client.MergeFrom(origPVC))
+}
@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup volume", func() {
+	var cluster *apiv1.Cluster
+	var cli k8client.WithWatch
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Backup: &apiv1.BackupConfiguration{
+					VolumeBackup: &apiv1.VolumeBackupConfiguration{
+						Storage: apiv1.StorageConfiguration{Size: "10Gi"},
+					},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).Build()
+	})
+
+	getBackupVolume := func(ctx SpecContext) *corev1.PersistentVolumeClaim {
+		var pvc corev1.PersistentVolumeClaim
+		Expect(cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-name-backup"}, &pvc)).
+			To(Succeed())
+		return &pvc
+	}
+
+	It("creates a PVC not owned by the cluster", func(ctx SpecContext) {
+		Expect(ReconcileBackupVolume(ctx, cli, cluster)).To(Succeed())
+
+		pvc := getBackupVolume(ctx)
+		Expect(pvc.OwnerReferences).To(BeEmpty())
+		Expect(pvc.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "test-name"))
+		Expect(pvc.Labels).To(HaveKeyWithValue(utils.PvcRoleLabelName, string(utils.PVCRolePgBackup)))
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+	})
+
+	It("uses the access modes of the PVC template", func() {
+		cluster.Spec.Backup.VolumeBackup.Storage.PersistentVolumeClaimTemplate = &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+		}
+
+		pvc, err := BuildBackupVolume(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+	})
+
+	It("expands the PVC when the size is increased", func(ctx SpecContext) {
+		Expect(ReconcileBackupVolume(ctx, cli, cluster)).To(Succeed())
+
+		cluster.Spec.Backup.VolumeBackup.Storage.Size = "20Gi"
+		Expect(ReconcileBackupVolume(ctx, cli, cluster)).To(Succeed())
+		Expect(getBackupVolume(ctx).Spec.Resources.Requests.Storage().Cmp(resource.MustParse("20Gi"))).
+			To(BeZero())
+
+		// The PVC is never shrunk
+		cluster.Spec.Backup.VolumeBackup.Storage.Size = "5Gi"
+		Expect(ReconcileBackupVolume(ctx, cli, cluster)).To(Succeed())
+		Expect(getBackupVolume(ctx).Spec.Resources.Requests.Storage().Cmp(resource.MustParse("20Gi"))).
+			To(BeZero())
+	})
+
+	It("does nothing when the backup volume is not requested", func(ctx SpecContext) {
+		cluster.Spec.Backup.VolumeBackup = nil
+		Expect(ReconcileBackupVolume(ctx, cli, cluster)).To(Succeed())
+
+		var pvcList corev1.PersistentVolumeClaimList
+		Expect(cli.List(ctx, &pvcList)).To(Succeed())
+		Expect(pvcList.Items).To(BeEmpty())
+	})
+
+	It("complains when the size is not valid", func() {
+		cluster.Spec.Backup.VolumeBackup.Storage.Size = ""
+		_, err := BuildBackupVolume(cluster)
+		Expect(err).To(Equal(ErrorInvalidSize))
+	})
+})
@@ -0,0 +1,259 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// storageClassQuotaSuffix is the suffix of the quota resource names
+// limiting the PVCs of a single storage class
+const storageClassQuotaSuffix = ".storageclass.storage.k8s.io/"
+
+// storageRequest is the storage that is going to be requested
+// in the namespace by the PVCs of a new instance
+type storageRequest struct {
+	// total is the sum of the storage of the PVCs
+	total resource.Quantity
+
+	// count is the number of the PVCs
+	count int64
+
+	// byStorageClass is the storage requested for every explicitly
+	// set storage class
+	byStorageClass map[string]*storageRequest
+}
+
+func (r *storageRequest) add(pvc *corev1.PersistentVolumeClaim) {
+	r.total.Add(*pvc.Spec.Resources.Requests.Storage())
+	r.count++
+}
+
+// CheckStorageCapacity checks whether the PVCs of the instance with the
+// passed serial fit in the ResourceQuotas and LimitRanges of the namespace
+// of the cluster, before creating them. A message describing the violated
+// constraint is returned when they don't, an empty string otherwise.
+// The constraints that cannot be discovered, i.e. because the operator
+// is not allowed to read them, are ignored.
+// The passed reader should not be backed by the cache, as the operator
+// does not watch the quotas and the limits of the namespaces
+func CheckStorageCapacity(
+	ctx context.Context,
+	c client.Reader,
+	cluster *apiv1.Cluster,
+	serial int,
+) (string, error) {
+	pvcs, err := buildInstancePVCs(cluster, serial)
+	if err != nil {
+		return "", err
+	}
+	if len(pvcs) == 0 {
+		return "", nil
+	}
+
+	if message, err := checkLimitRanges(ctx, c, cluster.Namespace, pvcs); err != nil || message != "" {
+		return message, err
+	}
+
+	return checkResourceQuotas(ctx, c, cluster.Namespace, pvcs)
+}
+
+// buildInstancePVCs builds the PVCs that are going to be created for
+// a new instance. The ones with an invalid size are skipped, as
+// they will be reported while being created
+func buildInstancePVCs(cluster *apiv1.Cluster, serial int) ([]*corev1.PersistentVolumeClaim, error) {
+	instanceName := specs.GetInstanceName(cluster.Name, serial)
+
+	var pvcs []*corev1.PersistentVolumeClaim
+	for _, expected := range getExpectedPVCsFromCluster(cluster, instanceName) {
+		storage, err := getStorageConfiguration(cluster, expected.role)
+		if err != nil {
+			return nil, err
+		}
+
+		pvc, err := Build(cluster, expected.toCreateConfiguration(serial, storage, nil))
+		if err == ErrorInvalidSize {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pvcs = append(pvcs, pvc)
+	}
+
+	return pvcs, nil
+}
+
+// checkLimitRanges checks the size of the PVCs against the minimum and
+// the maximum storage allowed by the LimitRanges of the namespace
+func checkLimitRanges(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	pvcs []*corev1.PersistentVolumeClaim,
+) (string, error) {
+	var limitRanges corev1.LimitRangeList
+	if err := c.List(ctx, &limitRanges, client.InNamespace(namespace)); err != nil {
+		return "", ignoreUndiscoverable(ctx, "LimitRange", err)
+	}
+
+	for _, limitRange := range limitRanges.Items {
+		for _, limit := range limitRange.Spec.Limits {
+			if limit.Type != corev1.LimitTypePersistentVolumeClaim {
+				continue
+			}
+
+			for _, pvc := range pvcs {
+				size := pvc.Spec.Resources.Requests.Storage()
+				if maxSize, ok := limit.Max[corev1.ResourceStorage]; ok && size.Cmp(maxSize) > 0 {
+					return fmt.Sprintf(
+						"the size of PVC %s (%s) is above the maximum allowed by the %s LimitRange (%s)",
+						pvc.Name, size.String(), limitRange.Name, maxSize.String()), nil
+				}
+				if minSize, ok := limit.Min[corev1.ResourceStorage]; ok && size.Cmp(minSize) < 0 {
+					return fmt.Sprintf(
+						"the size of PVC %s (%s) is below the minimum allowed by the %s LimitRange (%s)",
+						pvc.Name, size.String(), limitRange.Name, minSize.String()), nil
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// checkResourceQuotas checks whether the PVCs fit in what is left
+// of the storage quotas of the namespace
+func checkResourceQuotas(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	pvcs []*corev1.PersistentVolumeClaim,
+) (string, error) {
+	var quotas corev1.ResourceQuotaList
+	if err := c.List(ctx, &quotas, client.InNamespace(namespace)); err != nil {
+		return "", ignoreUndiscoverable(ctx, "ResourceQuota", err)
+	}
+
+	request := &storageRequest{byStorageClass: make(map[string]*storageRequest)}
+	for _, pvc := range pvcs {
+		request.add(pvc)
+		if pvc.Spec.StorageClassName == nil {
+			// The default storage class will be used, and
+			// we cannot know which one it will be
+			continue
+		}
+
+		className := *pvc.Spec.StorageClassName
+		if _, ok := request.byStorageClass[className]; !ok {
+			request.byStorageClass[className] = &storageRequest{}
+		}
+		request.byStorageClass[className].add(pvc)
+	}
+
+	classNames := make([]string, 0, len(request.byStorageClass))
+	for className := range request.byStorageClass {
+		classNames = append(classNames, className)
+	}
+	sort.Strings(classNames)
+
+	for idx := range quotas.Items {
+		quota := &quotas.Items[idx]
+
+		// Scoped quotas only apply to some PVCs, and
+		// we cannot tell which ones
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+
+		if message := checkResourceQuota(quota, corev1.ResourceRequestsStorage,
+			request.total); message != "" {
+			return message, nil
+		}
+		if message := checkResourceQuota(quota, corev1.ResourcePersistentVolumeClaims,
+			*resource.NewQuantity(request.count, resource.DecimalSI)); message != "" {
+			return message, nil
+		}
+
+		for _, className := range classNames {
+			classRequest := request.byStorageClass[className]
+			prefix := className + storageClassQuotaSuffix
+			if message := checkResourceQuota(quota, corev1.ResourceName(prefix+string(corev1.ResourceRequestsStorage)),
+				classRequest.total); message != "" {
+				return message, nil
+			}
+			if message := checkResourceQuota(quota,
+				corev1.ResourceName(prefix+string(corev1.ResourcePersistentVolumeClaims)),
+				*resource.NewQuantity(classRequest.count, resource.DecimalSI)); message != "" {
+				return message, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// checkResourceQuota checks whether the requested quantity of a resource
+// fits in a ResourceQuota. Quotas whose usage has not been computed
+// yet are ignored
+func checkResourceQuota(
+	quota *corev1.ResourceQuota,
+	name corev1.ResourceName,
+	requested resource.Quantity,
+) string {
+	hard, ok := quota.Status.Hard[name]
+	if !ok {
+		return ""
+	}
+	used, ok := quota.Status.Used[name]
+	if !ok {
+		return ""
+	}
+
+	total := used.DeepCopy()
+	total.Add(requested)
+	if total.Cmp(hard) <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"requesting %s of %s would exceed the %s ResourceQuota (used: %s, hard: %s)",
+		requested.String(), name, quota.Name, used.String(), hard.String())
+}
+
+// ignoreUndiscoverable ignores the errors raised when the
+// operator is not allowed to read an object kind
+func ignoreUndiscoverable(ctx context.Context, kind string, err error) error {
+	if apierrs.IsForbidden(err) || apierrs.IsNotFound(err) {
+		log.FromContext(ctx).Debug("Cannot discover the storage constraints, skipping the check",
+			"kind", kind, "err", err)
+		return nil
+	}
+
+	return fmt.Errorf("while listing the %s objects: %w", kind, err)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage capacity check", func() {
+	ctx := context.Background()
+	var cluster *apiv1.Cluster
+
+	newQuota := func(name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	newClient := func(objects ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			Build()
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+				},
+			},
+		}
+	})
+
+	It("passes when the namespace has no constraint", func() {
+		message, err := CheckStorageCapacity(ctx, newClient(), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("passes when the PVCs fit in the quota", func() {
+		quota := newQuota("storage",
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("50Gi")},
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("20Gi")})
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("reports the requested storage exceeding the quota", func() {
+		quota := newQuota("storage",
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("25Gi")},
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("20Gi")})
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(ContainSubstring("storage ResourceQuota"))
+		Expect(message).To(ContainSubstring("requests.storage"))
+	})
+
+	It("takes into account the WAL storage", func() {
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "10Gi"}
+		quota := newQuota("storage",
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("35Gi")},
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("20Gi")})
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(ContainSubstring("requesting 20Gi of requests.storage"))
+	})
+
+	It("reports the number of PVCs exceeding the quota", func() {
+		quota := newQuota("count",
+			corev1.ResourceList{corev1.ResourcePersistentVolumeClaims: resource.MustParse("2")},
+			corev1.ResourceList{corev1.ResourcePersistentVolumeClaims: resource.MustParse("2")})
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(ContainSubstring("persistentvolumeclaims"))
+	})
+
+	It("checks the quota of the requested storage class", func() {
+		cluster.Spec.StorageConfiguration.StorageClass = ptr.To("fast")
+		name := corev1.ResourceName("fast.storageclass.storage.k8s.io/requests.storage")
+		quota := newQuota("fast",
+			corev1.ResourceList{name: resource.MustParse("15Gi")},
+			corev1.ResourceList{name: resource.MustParse("10Gi")})
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(ContainSubstring(string(name)))
+
+		cluster.Spec.StorageConfiguration.StorageClass = ptr.To("slow")
+		message, err = CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("ignores the quotas whose usage is unknown", func() {
+		quota := newQuota("storage",
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("1Gi")},
+			nil)
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("ignores the scoped quotas", func() {
+		quota := newQuota("storage",
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("1Gi")},
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("1Gi")})
+		quota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("reports the PVCs above the maximum size of a LimitRange", func() {
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "default"},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type: corev1.LimitTypePersistentVolumeClaim,
+						Max:  corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+					},
+				},
+			},
+		}
+
+		message, err := CheckStorageCapacity(ctx, newClient(limitRange), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(ContainSubstring("above the maximum allowed by the limits LimitRange"))
+	})
+
+	It("reports the PVCs below the minimum size of a LimitRange", func() {
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "default"},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type: corev1.LimitTypePersistentVolumeClaim,
+						Min:  corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+					},
+				},
+			},
+		}
+
+		message, err := CheckStorageCapacity(ctx, newClient(limitRange), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(ContainSubstring("below the minimum"))
+	})
+
+	It("ignores the constraints of the other namespaces", func() {
+		quota := newQuota("storage",
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("1Gi")},
+			corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("1Gi")})
+		quota.Namespace = "other"
+
+		message, err := CheckStorageCapacity(ctx, newClient(quota), cluster, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(BeEmpty())
+	})
+})
@@ -186,6 +186,13 @@ func newLabelReconciler(cluster *apiv1.Cluster) metadataReconciler { //nolint: g
 					}
 				}
 
+				if pvc.Name == GetName(instanceName, utils.PVCRolePgLog) {
+					found = true
+					if pvcRole != utils.PVCRolePgLog {
+						return false
+					}
+				}
+
 				if found && pvc.Labels[utils.InstanceNameLabelName] != instanceName {
 					return false
 				}
@@ -213,6 +220,13 @@ func newLabelReconciler(cluster *apiv1.Cluster) metadataReconciler { //nolint: g
 					}
 				}
 
+				if pvc.Name == GetName(instanceName, utils.PVCRolePgLog) {
+					found = true
+					if pvcRole != utils.PVCRolePgLog {
+						pvc.Labels[utils.PvcRoleLabelName] = string(utils.PVCRolePgLog)
+					}
+				}
+
 				if found {
 					pvc.Labels[utils.InstanceNameLabelName] = instanceName
 					break
@@ -33,8 +33,11 @@ import (
 // GetName builds the name for a given PVC of the instance
 func GetName(instanceName string, role utils.PVCRole) string {
 	pvcName := instanceName
-	if role == utils.PVCRolePgWal {
+	switch role {
+	case utils.PVCRolePgWal:
 		pvcName += apiv1.WalArchiveVolumeSuffix
+	case utils.PVCRolePgLog:
+		pvcName += apiv1.LogVolumeSuffix
 	}
 	return pvcName
 }
@@ -74,6 +77,31 @@ external:
 	return true
 }
 
+// IsInstanceDataLost returns true if any of the PVCs mounted by the instance
+// Pod is missing, is being deleted or has lost its underlying volume
+func IsInstanceDataLost(instance *corev1.Pod, pvcs []corev1.PersistentVolumeClaim) bool {
+	for _, volume := range instance.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+
+		pvc := findPVCByName(volume.PersistentVolumeClaim.ClaimName, pvcs)
+		if pvc == nil || pvc.DeletionTimestamp != nil || pvc.Status.Phase == corev1.ClaimLost {
+			return true
+		}
+	}
+	return false
+}
+
+func findPVCByName(name string, pvcs []corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	for idx := range pvcs {
+		if pvcs[idx].Name == name {
+			return &pvcs[idx]
+		}
+	}
+	return nil
+}
+
 // isResizing returns true if PersistentVolumeClaimResizing condition is present
 func isResizing(pvc corev1.PersistentVolumeClaim) bool {
 	for _, condition := range pvc.Status.Conditions {
@@ -156,6 +184,10 @@ func getExpectedPVCsFromCluster(cluster *apiv1.Cluster, instanceName string) []e
 		roles = append(roles, utils.PVCRolePgWal)
 	}
 
+	if cluster.ShouldCreateLogVolume() {
+		roles = append(roles, utils.PVCRolePgLog)
+	}
+
 	return buildExpectedPVCs(instanceName, roles)
 }
 
@@ -208,6 +240,17 @@ func buildExpectedPVCs(instanceName string, roles []utils.PVCRole) []expectedPVC
 		)
 	}
 
+	if containsRole(roles, utils.PVCRolePgLog) {
+		logPVCName := GetName(instanceName, utils.PVCRolePgLog)
+		expectedMounts = append(expectedMounts,
+			expectedPVC{
+				name:          logPVCName,
+				role:          utils.PVCRolePgLog,
+				initialStatus: StatusReady,
+			},
+		)
+	}
+
 	return expectedMounts
 }
 
@@ -221,6 +264,8 @@ func getStorageConfiguration(
 		storageConfiguration = &cluster.Spec.StorageConfiguration
 	case utils.PVCRolePgWal:
 		storageConfiguration = cluster.Spec.WalStorage
+	case utils.PVCRolePgLog:
+		storageConfiguration = cluster.Spec.LogStorage
 	default:
 		return apiv1.StorageConfiguration{}, fmt.Errorf("unknown pvcRole: %s", string(role))
 	}
@@ -272,5 +317,14 @@ func GetInstancePVCs(
 		pvcs = append(pvcs, *pgWal)
 	}
 
+	pgLogName := GetName(instanceName, utils.PVCRolePgLog)
+	pgLog, err := getPVC(pgLogName)
+	if err != nil {
+		return nil, err
+	}
+	if pgLog != nil {
+		pvcs = append(pvcs, *pgLog)
+	}
+
 	return pvcs, nil
 }
@@ -22,6 +22,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -108,3 +109,132 @@ var _ = Describe("PVCs used by instance", func() {
 		Expect(res).To(BeFalse())
 	})
 })
+
+var _ = Describe("instance data loss detection", func() {
+	instance := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster-example-1",
+		},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name: "pgdata",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: "cluster-example-1",
+						},
+					},
+				},
+				{
+					Name: "scratch-data",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+		},
+	}
+
+	makeBoundPVC := func() corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example-1",
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase: corev1.ClaimBound,
+			},
+		}
+	}
+
+	It("is false when the PVCs of the instance are bound", func() {
+		Expect(IsInstanceDataLost(instance, []corev1.PersistentVolumeClaim{makeBoundPVC()})).To(BeFalse())
+	})
+
+	It("is true when a PVC of the instance is missing", func() {
+		Expect(IsInstanceDataLost(instance, nil)).To(BeTrue())
+	})
+
+	It("is true when a PVC of the instance is being deleted", func() {
+		pvc := makeBoundPVC()
+		pvc.DeletionTimestamp = ptr.To(metav1.Now())
+		Expect(IsInstanceDataLost(instance, []corev1.PersistentVolumeClaim{pvc})).To(BeTrue())
+	})
+
+	It("is true when a PVC of the instance has lost its volume", func() {
+		pvc := makeBoundPVC()
+		pvc.Status.Phase = corev1.ClaimLost
+		Expect(IsInstanceDataLost(instance, []corev1.PersistentVolumeClaim{pvc})).To(BeTrue())
+	})
+})
+
+var _ = Describe("PVCs of the log volume", func() {
+	clusterName := "cluster-pvc-log"
+	instanceName := clusterName + "-1"
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName,
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{Size: "10Gi"},
+			LogStorage:           &apiv1.StorageConfiguration{Size: "1Gi"},
+		},
+	}
+
+	It("expects a log PVC for every instance", func() {
+		Expect(GetName(instanceName, utils.PVCRolePgLog)).To(Equal(instanceName + "-log"))
+		Expect(getExpectedInstancePVCNamesFromCluster(cluster, instanceName)).To(
+			ConsistOf(instanceName, instanceName+"-log"))
+		Expect(BelongToInstance(cluster, instanceName, instanceName+"-log")).To(BeTrue())
+	})
+
+	It("builds the log PVC from the log storage configuration", func() {
+		storage, err := getStorageConfiguration(cluster, utils.PVCRolePgLog)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(storage.Size).To(Equal("1Gi"))
+
+		pvc, err := Build(cluster, &CreateConfiguration{
+			Status:     StatusReady,
+			NodeSerial: 1,
+			Role:       utils.PVCRolePgLog,
+			Storage:    storage,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Name).To(Equal(instanceName + "-log"))
+		Expect(pvc.Labels[utils.PvcRoleLabelName]).To(Equal(string(utils.PVCRolePgLog)))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+	})
+
+	It("detects the instances that need to be restarted to mount the log PVC", func() {
+		instance := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: instanceName},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{
+						Name: "pgdata",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: instanceName},
+						},
+					},
+				},
+			},
+		}
+		Expect(InstanceHasMissingMounts(cluster, instance)).To(BeTrue())
+
+		instance.Spec.Volumes = append(instance.Spec.Volumes, corev1.Volume{
+			Name: "pg-log",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: instanceName + "-log"},
+			},
+		})
+		Expect(InstanceHasMissingMounts(cluster, instance)).To(BeFalse())
+	})
+
+	It("never restores the log PVC from a snapshot", func() {
+		source := &StorageSource{}
+		reference, err := source.ForRole(utils.PVCRolePgLog)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reference).To(BeNil())
+	})
+})
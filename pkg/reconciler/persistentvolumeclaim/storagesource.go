@@ -50,6 +50,9 @@ func (source *StorageSource) ForRole(role utils.PVCRole) (*corev1.TypedLocalObje
 		return &source.DataSource, nil
 	case utils.PVCRolePgWal:
 		return source.WALSource, nil
+	case utils.PVCRolePgLog:
+		// the logs are never restored from a snapshot
+		return nil, nil
 	default:
 		return nil, errors.New("unknown PVC role for StorageSource")
 	}
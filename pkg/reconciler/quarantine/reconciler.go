@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Reconcile quarantines the replicas that failed to start too many
+// times within the window, adding them to the quarantined instances
+// annotation of the cluster
+func Reconcile(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+) (*ctrl.Result, error) {
+	instances := getInstancesToQuarantine(cluster, time.Now())
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Warning("Quarantining the replicas that repeatedly failed to start",
+		"instances", instances,
+		"startupFailures", cluster.Status.StartupFailures)
+
+	origCluster := cluster.DeepCopy()
+	if err := utils.AddQuarantinedInstances(&cluster.ObjectMeta, instances...); err != nil {
+		return nil, err
+	}
+	if err := c.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return nil, err
+	}
+
+	recorder.Eventf(cluster, "Warning", "InstancesQuarantined",
+		"Quarantined %s after %d consecutive failed startups, remove them from the %s annotation to lift the quarantine",
+		strings.Join(instances, ", "),
+		cluster.Spec.Quarantine.FailedStartups,
+		utils.QuarantinedInstancesAnnotation)
+
+	// The instance managers will fence the quarantined replicas
+	return &ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// ExcludeQuarantinedReplicas removes the quarantined replicas from the
+// list of the instances, so that they are never promoted
+func ExcludeQuarantinedReplicas(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) postgres.PostgresqlStatusList {
+	result := postgres.PostgresqlStatusList{
+		Items: make([]postgres.PostgresqlStatus, 0, len(status.Items)),
+	}
+	for _, item := range status.Items {
+		if item.Pod != nil && item.Pod.Name != cluster.Status.CurrentPrimary &&
+			cluster.IsInstanceQuarantined(item.Pod.Name) {
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result
+}
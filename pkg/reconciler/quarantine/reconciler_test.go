@@ -0,0 +1,157 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quarantine threshold", func() {
+	now := time.Now().Truncate(time.Second)
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Quarantine: &apiv1.QuarantineConfiguration{FailedStartups: 3, Window: 600},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+	})
+
+	It("quarantines the replicas reaching the threshold within the window", func() {
+		since := now.Add(-time.Minute).Format(time.RFC3339)
+		cluster.Status.StartupFailures = map[string]apiv1.StartupFailures{
+			"cluster-example-2": {RestartCount: 8, Count: 2, Since: since},
+			"cluster-example-3": {RestartCount: 3, Count: 3, Since: since},
+			"cluster-example-4": {RestartCount: 9, Count: 4, Since: since},
+		}
+		Expect(getInstancesToQuarantine(cluster, now)).To(Equal([]string{"cluster-example-3", "cluster-example-4"}))
+	})
+
+	It("doesn't quarantine the replicas when the window is expired", func() {
+		cluster.Status.StartupFailures = map[string]apiv1.StartupFailures{
+			"cluster-example-2": {RestartCount: 3, Count: 3, Since: now.Add(-time.Hour).Format(time.RFC3339)},
+		}
+		Expect(getInstancesToQuarantine(cluster, now)).To(BeEmpty())
+	})
+
+	It("never quarantines the primary nor the already quarantined replicas", func() {
+		since := now.Format(time.RFC3339)
+		cluster.Annotations = map[string]string{
+			utils.QuarantinedInstancesAnnotation: `["cluster-example-2"]`,
+		}
+		cluster.Status.StartupFailures = map[string]apiv1.StartupFailures{
+			"cluster-example-1": {RestartCount: 3, Count: 3, Since: since},
+			"cluster-example-2": {RestartCount: 3, Count: 3, Since: since},
+		}
+		Expect(getInstancesToQuarantine(cluster, now)).To(BeEmpty())
+	})
+
+	It("doesn't quarantine anything when disabled", func() {
+		cluster.Spec.Quarantine = nil
+		cluster.Status.StartupFailures = map[string]apiv1.StartupFailures{
+			"cluster-example-2": {RestartCount: 3, Count: 3, Since: now.Format(time.RFC3339)},
+		}
+		Expect(getInstancesToQuarantine(cluster, now)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Reconcile", func() {
+	It("adds the replicas to the quarantine annotation and emits an event", func(ctx context.Context) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Quarantine: &apiv1.QuarantineConfiguration{FailedStartups: 2},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				StartupFailures: map[string]apiv1.StartupFailures{
+					"cluster-example-3": {RestartCount: 2, Count: 2, Since: time.Now().Format(time.RFC3339)},
+				},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster.DeepCopy()).
+			Build()
+		recorder := record.NewFakeRecorder(10)
+
+		result, err := Reconcile(ctx, fakeClient, recorder, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("cluster-example-3")))
+
+		var updatedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.IsInstanceQuarantined("cluster-example-3")).To(BeTrue())
+		Expect(updatedCluster.IsInstanceFenced("cluster-example-3")).To(BeTrue())
+	})
+
+	It("does nothing when no replica must be quarantined", func(ctx context.Context) {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Quarantine: &apiv1.QuarantineConfiguration{FailedStartups: 2},
+			},
+		}
+		result, err := Reconcile(ctx, nil, nil, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+	})
+})
+
+var _ = Describe("Promotion candidates", func() {
+	It("excludes the quarantined replicas but not the primary", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.QuarantinedInstancesAnnotation: `["cluster-example-1","cluster-example-2"]`,
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}},
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}},
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}},
+			},
+		}
+
+		candidates := ExcludeQuarantinedReplicas(cluster, status)
+		Expect(candidates.GetNames()).To(Equal([]string{"cluster-example-3", "cluster-example-1"}))
+	})
+})
@@ -0,0 +1,212 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// ConditionReasonInstancesQuarantined is the reason of the quarantine
+	// condition used when some replicas are quarantined
+	ConditionReasonInstancesQuarantined = "InstancesQuarantined"
+
+	// ConditionReasonNoInstancesQuarantined is the reason of the quarantine
+	// condition used when no replica is quarantined
+	ConditionReasonNoInstancesQuarantined = "NoInstancesQuarantined"
+
+	// ConditionReasonInvalidAnnotation is the reason of the quarantine
+	// condition used when the annotation listing the quarantined
+	// instances cannot be parsed
+	ConditionReasonInvalidAnnotation = "InvalidAnnotation"
+)
+
+// EnrichStatus tracks the consecutive failed startups of the replicas
+// and reports the quarantined ones in the conditions of the cluster
+func EnrichStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podList []corev1.Pod,
+	now time.Time,
+) {
+	if !cluster.Spec.Quarantine.IsEnabled() {
+		cluster.Status.StartupFailures = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionInstancesQuarantined))
+		return
+	}
+
+	quarantinedInstances, err := utils.GetQuarantinedInstances(cluster.Annotations)
+	if err != nil {
+		log.FromContext(ctx).Warning("while getting the quarantined instances", "err", err)
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionInstancesQuarantined),
+			Status:  metav1.ConditionUnknown,
+			Reason:  ConditionReasonInvalidAnnotation,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	startupFailures := make(map[string]apiv1.StartupFailures)
+	for idx := range podList {
+		pod := &podList[idx]
+
+		// The primary is never quarantined, and the failures of the
+		// quarantined replicas are tracked again from scratch when
+		// the quarantine is lifted
+		if pod.Name == cluster.Status.CurrentPrimary ||
+			pod.Name == cluster.Status.TargetPrimary ||
+			quarantinedInstances.Has(pod.Name) {
+			continue
+		}
+
+		var previous *apiv1.StartupFailures
+		if failures, ok := cluster.Status.StartupFailures[pod.Name]; ok {
+			previous = &failures
+		}
+		if failures := trackStartupFailures(previous, pod, now, cluster.Spec.Quarantine.GetWindow()); failures != nil {
+			startupFailures[pod.Name] = *failures
+		}
+	}
+	cluster.Status.StartupFailures = nil
+	if len(startupFailures) > 0 {
+		cluster.Status.StartupFailures = startupFailures
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, getQuarantineCondition(quarantinedInstances.ToSortedList()))
+}
+
+// getQuarantineCondition builds the condition listing the quarantined instances
+func getQuarantineCondition(quarantinedInstances []string) metav1.Condition {
+	if len(quarantinedInstances) == 0 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionInstancesQuarantined),
+			Status:  metav1.ConditionFalse,
+			Reason:  ConditionReasonNoInstancesQuarantined,
+			Message: "No instance is quarantined",
+		}
+	}
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionInstancesQuarantined),
+		Status: metav1.ConditionTrue,
+		Reason: ConditionReasonInstancesQuarantined,
+		Message: fmt.Sprintf(
+			"Quarantined instances: %s. Remove them from the %s annotation to lift the quarantine",
+			strings.Join(quarantinedInstances, ", "),
+			utils.QuarantinedInstancesAnnotation),
+	}
+}
+
+// trackStartupFailures updates the consecutive failed startups of an
+// instance looking at the restart count of its PostgreSQL container.
+// A nil value is returned when the instance is ready, as the failures
+// are not consecutive anymore
+func trackStartupFailures(
+	previous *apiv1.StartupFailures,
+	pod *corev1.Pod,
+	now time.Time,
+	window time.Duration,
+) *apiv1.StartupFailures {
+	if utils.IsPodReady(*pod) {
+		return nil
+	}
+
+	containerStatus := getPostgresContainerStatus(pod)
+	if containerStatus == nil {
+		return previous
+	}
+
+	// We start from the current restart count when we first see the
+	// instance failing, or when the Pod has been recreated
+	if previous == nil || containerStatus.RestartCount < previous.RestartCount {
+		return &apiv1.StartupFailures{RestartCount: containerStatus.RestartCount}
+	}
+
+	newFailures := containerStatus.RestartCount - previous.RestartCount
+	if newFailures == 0 {
+		return previous
+	}
+
+	result := *previous
+	result.RestartCount = containerStatus.RestartCount
+
+	since, err := time.Parse(time.RFC3339, previous.Since)
+	if previous.Count == 0 || err != nil || now.Sub(since) > window {
+		// The window has expired: this is the first failure of a new one
+		result.Count = newFailures
+		result.Since = now.Format(time.RFC3339)
+		return &result
+	}
+
+	result.Count += newFailures
+	return &result
+}
+
+// getPostgresContainerStatus gets the status of the container running
+// PostgreSQL, or nil if the Kubelet has not reported it yet
+func getPostgresContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	for idx := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[idx].Name == specs.PostgresContainerName {
+			return &pod.Status.ContainerStatuses[idx]
+		}
+	}
+	return nil
+}
+
+// getInstancesToQuarantine gets the names of the replicas that failed to
+// start too many times within the window
+func getInstancesToQuarantine(cluster *apiv1.Cluster, now time.Time) []string {
+	if !cluster.Spec.Quarantine.IsEnabled() {
+		return nil
+	}
+
+	var result []string
+	for name, failures := range cluster.Status.StartupFailures {
+		if failures.Count < cluster.Spec.Quarantine.FailedStartups {
+			continue
+		}
+
+		since, err := time.Parse(time.RFC3339, failures.Since)
+		if err != nil || now.Sub(since) > cluster.Spec.Quarantine.GetWindow() {
+			continue
+		}
+
+		if name == cluster.Status.CurrentPrimary || name == cluster.Status.TargetPrimary ||
+			cluster.IsInstanceQuarantined(name) {
+			continue
+		}
+
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
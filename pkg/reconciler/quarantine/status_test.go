@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newInstancePod(name string, restartCount int32, ready bool) corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.ContainersReady, Status: readyStatus},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: specs.PostgresContainerName, RestartCount: restartCount},
+			},
+		},
+	}
+}
+
+var _ = Describe("Startup failures tracking", func() {
+	now := time.Now().Truncate(time.Second)
+	window := 10 * time.Minute
+
+	It("starts tracking from the current restart count", func() {
+		pod := newInstancePod("cluster-example-2", 7, false)
+		Expect(trackStartupFailures(nil, &pod, now, window)).To(Equal(&apiv1.StartupFailures{RestartCount: 7}))
+	})
+
+	It("forgets the failures of a ready instance", func() {
+		pod := newInstancePod("cluster-example-2", 7, true)
+		previous := &apiv1.StartupFailures{RestartCount: 5, Count: 2, Since: now.Format(time.RFC3339)}
+		Expect(trackStartupFailures(previous, &pod, now, window)).To(BeNil())
+	})
+
+	It("counts the consecutive failures within the window", func() {
+		pod := newInstancePod("cluster-example-2", 1, false)
+		failures := trackStartupFailures(nil, &pod, now, window)
+
+		for restartCount := int32(2); restartCount <= 4; restartCount++ {
+			pod = newInstancePod("cluster-example-2", restartCount, false)
+			failures = trackStartupFailures(failures, &pod, now.Add(time.Minute*time.Duration(restartCount)), window)
+		}
+
+		Expect(failures.RestartCount).To(BeEquivalentTo(4))
+		Expect(failures.Count).To(BeEquivalentTo(3))
+		Expect(failures.Since).To(Equal(now.Add(2 * time.Minute).Format(time.RFC3339)))
+	})
+
+	It("keeps the failures when the instance didn't restart", func() {
+		pod := newInstancePod("cluster-example-2", 4, false)
+		previous := &apiv1.StartupFailures{RestartCount: 4, Count: 2, Since: now.Format(time.RFC3339)}
+		Expect(trackStartupFailures(previous, &pod, now.Add(time.Hour), window)).To(Equal(previous))
+	})
+
+	It("starts a new window when the previous one is expired", func() {
+		pod := newInstancePod("cluster-example-2", 6, false)
+		previous := &apiv1.StartupFailures{RestartCount: 4, Count: 2, Since: now.Format(time.RFC3339)}
+		later := now.Add(window + time.Minute)
+		Expect(trackStartupFailures(previous, &pod, later, window)).To(Equal(&apiv1.StartupFailures{
+			RestartCount: 6,
+			Count:        2,
+			Since:        later.Format(time.RFC3339),
+		}))
+	})
+
+	It("starts from scratch when the Pod has been recreated", func() {
+		pod := newInstancePod("cluster-example-2", 0, false)
+		previous := &apiv1.StartupFailures{RestartCount: 4, Count: 2, Since: now.Format(time.RFC3339)}
+		Expect(trackStartupFailures(previous, &pod, now, window)).To(Equal(&apiv1.StartupFailures{}))
+	})
+})
+
+var _ = Describe("EnrichStatus", func() {
+	var cluster *apiv1.Cluster
+	now := time.Now().Truncate(time.Second)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Quarantine: &apiv1.QuarantineConfiguration{FailedStartups: 3},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+	})
+
+	It("tracks the failures of the replicas only", func(ctx context.Context) {
+		pods := []corev1.Pod{
+			newInstancePod("cluster-example-1", 3, false),
+			newInstancePod("cluster-example-2", 2, false),
+			newInstancePod("cluster-example-3", 0, true),
+		}
+		EnrichStatus(ctx, cluster, pods, now)
+
+		Expect(cluster.Status.StartupFailures).To(HaveLen(1))
+		Expect(cluster.Status.StartupFailures).To(HaveKey("cluster-example-2"))
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionInstancesQuarantined))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("reports the quarantined replicas without tracking them", func(ctx context.Context) {
+		cluster.Annotations = map[string]string{
+			utils.QuarantinedInstancesAnnotation: `["cluster-example-2"]`,
+		}
+		cluster.Status.StartupFailures = map[string]apiv1.StartupFailures{
+			"cluster-example-2": {RestartCount: 5, Count: 3, Since: now.Format(time.RFC3339)},
+		}
+		EnrichStatus(ctx, cluster, []corev1.Pod{newInstancePod("cluster-example-2", 5, false)}, now)
+
+		Expect(cluster.Status.StartupFailures).To(BeEmpty())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionInstancesQuarantined))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2"))
+	})
+
+	It("does nothing when the quarantine is disabled", func(ctx context.Context) {
+		cluster.Spec.Quarantine = nil
+		cluster.Status.StartupFailures = map[string]apiv1.StartupFailures{
+			"cluster-example-2": {RestartCount: 5},
+		}
+		EnrichStatus(ctx, cluster, []corev1.Pod{newInstancePod("cluster-example-2", 5, false)}, now)
+
+		Expect(cluster.Status.StartupFailures).To(BeNil())
+		Expect(meta.FindStatusCondition(
+			cluster.Status.Conditions, string(apiv1.ConditionInstancesQuarantined))).To(BeNil())
+	})
+})
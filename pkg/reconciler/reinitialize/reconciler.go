@@ -0,0 +1,153 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reinitialize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+var (
+	// errPrimary is raised when the user asked to reinitialize the primary
+	errPrimary = errors.New("the primary can't be reinitialized, switch over to another instance first")
+
+	// errUnhealthyPrimary is raised when there's no healthy primary to
+	// clone the replica from
+	errUnhealthyPrimary = errors.New("the primary is not healthy")
+)
+
+// Reconcile rebuilds the replica whose Pod has the reinitialize annotation,
+// deleting its Pod and its PVCs so that the operator clones a new replica
+// from the primary. The replicas are rebuilt one at a time, and only when
+// enough healthy replicas would be left to keep the quorum of the cluster
+func Reconcile(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (*ctrl.Result, error) {
+	instanceName := getInstanceToReinitialize(instancesStatus)
+	if instanceName == "" {
+		return nil, nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("instance", instanceName)
+	if err := checkQuorum(cluster, instancesStatus, instanceName); err != nil {
+		contextLogger.Info("Waiting to reinitialize the instance", "reason", err.Error())
+		recorder.Eventf(cluster, "Warning", "ReinitializeBlocked",
+			"Can't reinitialize %s: %s", instanceName, err.Error())
+		return nil, nil
+	}
+
+	contextLogger.Info("Reinitializing the instance, deleting its Pod and PVCs")
+	recorder.Eventf(cluster, "Normal", "Reinitialize",
+		"Reinitializing %s, a new replica will be cloned from the primary", instanceName)
+
+	pod := &corev1.Pod{}
+	pod.Name = instanceName
+	pod.Namespace = cluster.Namespace
+	if err := c.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("while deleting the Pod of %s: %w", instanceName, err)
+	}
+
+	if err := persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+		ctx,
+		c,
+		cluster,
+		instanceName,
+		cluster.Namespace,
+	); err != nil {
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// getInstanceToReinitialize gets the first instance, by name, whose Pod
+// has the reinitialize annotation and is not already being deleted
+func getInstanceToReinitialize(instancesStatus postgres.PostgresqlStatusList) string {
+	result := ""
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil || item.Pod.DeletionTimestamp != nil ||
+			item.Pod.Annotations[utils.ReinitializeAnnotationName] != "true" {
+			continue
+		}
+		if result == "" || item.Pod.Name < result {
+			result = item.Pod.Name
+		}
+	}
+
+	return result
+}
+
+// checkQuorum checks whether the instance can be rebuilt, which requires a
+// healthy primary to clone it from and enough healthy replicas to keep
+// serving the synchronous replicas while it is down
+func checkQuorum(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	instanceName string,
+) error {
+	if instanceName == cluster.Status.CurrentPrimary || instanceName == cluster.Status.TargetPrimary {
+		return errPrimary
+	}
+
+	primaryIsHealthy := false
+	healthyReplicas := 0
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil || item.Pod.Name == instanceName || item.Error != nil || !utils.IsPodReady(*item.Pod) {
+			continue
+		}
+
+		switch {
+		case item.Pod.Name == cluster.Status.CurrentPrimary:
+			primaryIsHealthy = true
+		case !cluster.IsInstanceQuarantined(item.Pod.Name):
+			healthyReplicas++
+		}
+	}
+
+	if !primaryIsHealthy {
+		return errUnhealthyPrimary
+	}
+
+	requiredReplicas := 1
+	if cluster.Spec.MinSyncReplicas > requiredReplicas {
+		requiredReplicas = cluster.Spec.MinSyncReplicas
+	}
+	if healthyReplicas < requiredReplicas {
+		return fmt.Errorf("it would leave %d healthy replicas, while at least %d are required",
+			healthyReplicas, requiredReplicas)
+	}
+
+	return nil
+}
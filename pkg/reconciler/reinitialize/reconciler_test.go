@@ -0,0 +1,253 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reinitialize
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newPod(name string, ready bool, annotations map[string]string) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: status}},
+		},
+	}
+}
+
+var reinitializeAnnotation = map[string]string{utils.ReinitializeAnnotationName: "true"}
+
+var _ = Describe("Instance to reinitialize", func() {
+	It("gets the first annotated instance", func() {
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: newPod("cluster-example-1", true, nil)},
+				{Pod: newPod("cluster-example-3", true, reinitializeAnnotation)},
+				{Pod: newPod("cluster-example-2", true, reinitializeAnnotation)},
+			},
+		}
+		Expect(getInstanceToReinitialize(status)).To(Equal("cluster-example-2"))
+	})
+
+	It("ignores the annotations not set to true and the Pods being deleted", func() {
+		deleted := newPod("cluster-example-2", true, reinitializeAnnotation)
+		deleted.DeletionTimestamp = ptrToNow()
+		status := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: newPod("cluster-example-1", true, map[string]string{utils.ReinitializeAnnotationName: "false"})},
+				{Pod: deleted},
+			},
+		}
+		Expect(getInstanceToReinitialize(status)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Quorum check", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+	})
+
+	statusOf := func(pods ...*corev1.Pod) postgres.PostgresqlStatusList {
+		result := postgres.PostgresqlStatusList{}
+		for _, pod := range pods {
+			result.Items = append(result.Items, postgres.PostgresqlStatus{Pod: pod})
+		}
+		return result
+	}
+
+	It("allows the reinitialization when another healthy replica is left", func() {
+		status := statusOf(
+			newPod("cluster-example-1", true, nil),
+			newPod("cluster-example-2", false, nil),
+			newPod("cluster-example-3", true, nil),
+		)
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).To(Succeed())
+	})
+
+	It("blocks the reinitialization of the primary", func() {
+		status := statusOf(
+			newPod("cluster-example-1", true, nil),
+			newPod("cluster-example-2", true, nil),
+			newPod("cluster-example-3", true, nil),
+		)
+		Expect(checkQuorum(cluster, status, "cluster-example-1")).To(MatchError(errPrimary))
+
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).To(MatchError(errPrimary))
+	})
+
+	It("blocks the reinitialization when the primary is not healthy", func() {
+		status := statusOf(
+			newPod("cluster-example-1", false, nil),
+			newPod("cluster-example-2", true, nil),
+			newPod("cluster-example-3", true, nil),
+		)
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).To(MatchError(errUnhealthyPrimary))
+	})
+
+	It("blocks the reinitialization when no healthy replica would be left", func() {
+		status := statusOf(
+			newPod("cluster-example-1", true, nil),
+			newPod("cluster-example-2", true, nil),
+			newPod("cluster-example-3", false, nil),
+		)
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).
+			To(MatchError(ContainSubstring("leave 0 healthy replicas")))
+	})
+
+	It("doesn't count the replicas reporting errors or quarantined", func() {
+		cluster.Annotations = map[string]string{utils.QuarantinedInstancesAnnotation: `["cluster-example-4"]`}
+		status := statusOf(
+			newPod("cluster-example-1", true, nil),
+			newPod("cluster-example-2", true, nil),
+			newPod("cluster-example-3", true, nil),
+			newPod("cluster-example-4", true, nil),
+		)
+		status.Items[2].Error = apierrs.NewBadRequest("unreachable")
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).To(HaveOccurred())
+	})
+
+	It("requires enough healthy replicas for the synchronous replication", func() {
+		cluster.Spec.MinSyncReplicas = 2
+		status := statusOf(
+			newPod("cluster-example-1", true, nil),
+			newPod("cluster-example-2", true, nil),
+			newPod("cluster-example-3", true, nil),
+		)
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).
+			To(MatchError(ContainSubstring("at least 2 are required")))
+
+		status.Items = append(status.Items, postgres.PostgresqlStatus{Pod: newPod("cluster-example-4", true, nil)})
+		Expect(checkQuorum(cluster, status, "cluster-example-2")).To(Succeed())
+	})
+})
+
+var _ = Describe("Reconcile", func() {
+	var (
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+		recorder   *record.FakeRecorder
+		pods       []*corev1.Pod
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{Instances: 3},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		pods = []*corev1.Pod{
+			newPod("cluster-example-1", true, nil),
+			newPod("cluster-example-2", true, reinitializeAnnotation),
+			newPod("cluster-example-3", true, nil),
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2", Namespace: "default"},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster.DeepCopy(), pods[0], pods[1], pods[2], pvc).
+			Build()
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	statusOfPods := func() postgres.PostgresqlStatusList {
+		result := postgres.PostgresqlStatusList{}
+		for _, pod := range pods {
+			result.Items = append(result.Items, postgres.PostgresqlStatus{Pod: pod})
+		}
+		return result
+	}
+
+	It("deletes the Pod and the PVC of the annotated replica", func(ctx context.Context) {
+		result, err := Reconcile(ctx, fakeClient, recorder, cluster, statusOfPods())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Reinitializing cluster-example-2")))
+
+		var pod corev1.Pod
+		err = fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &pod)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		var pvc corev1.PersistentVolumeClaim
+		err = fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &pvc)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-3"}, &pod)).
+			To(Succeed())
+	})
+
+	It("keeps the replica when the quorum would be lost", func(ctx context.Context) {
+		pods[2] = newPod("cluster-example-3", false, nil)
+
+		result, err := Reconcile(ctx, fakeClient, recorder, cluster, statusOfPods())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("ReinitializeBlocked")))
+
+		var pod corev1.Pod
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &pod)).
+			To(Succeed())
+	})
+
+	It("does nothing when no replica must be reinitialized", func(ctx context.Context) {
+		pods[1] = newPod("cluster-example-2", true, nil)
+
+		result, err := Reconcile(ctx, nil, nil, cluster, statusOfPods())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+	})
+})
+
+func ptrToNow() *metav1.Time {
+	now := metav1.Now()
+	return &now
+}
@@ -28,6 +28,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -125,7 +126,16 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if localeCType := config.LocaleCType; localeCType != "" {
 		options = append(options, fmt.Sprintf("--lc-ctype=%s", localeCType))
 	}
-	if walSegmentSize := config.WalSegmentSize; walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
+	if localeProvider := config.LocaleProvider; localeProvider != "" {
+		options = append(options, fmt.Sprintf("--locale-provider=%s", localeProvider))
+	}
+	if icuLocale := config.ICULocale; icuLocale != "" {
+		options = append(options, fmt.Sprintf("--icu-locale=%s", icuLocale))
+	}
+	if icuRules := config.ICURules; icuRules != "" {
+		options = append(options, fmt.Sprintf("--icu-rules=%s", icuRules))
+	}
+	if walSegmentSize := cluster.GetWalSegmentSize(); walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
 		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize))
 	}
 	initCommand = append(
@@ -182,6 +192,11 @@ func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *
 
 	addBarmanEndpointCAToJobFromCluster(cluster, backup, job)
 
+	if volumeBackup := cluster.Spec.Bootstrap.Recovery.VolumeBackup; volumeBackup != nil {
+		addBackupVolume(&job.Spec.Template.Spec, "recovery-backup", volumeBackup.ClaimName,
+			postgres.RecoveryBackupVolumeDirectory, true)
+	}
+
 	return job
 }
 
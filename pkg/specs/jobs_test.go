@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	"github.com/kballard/go-shellquote"
 	v1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -165,3 +166,83 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
 })
+
+var _ = Describe("initdb flags", func() {
+	It("pass the WAL segment size from the bootstrap section", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						WalSegmentSize: 32,
+					},
+				},
+			},
+		}
+		Expect(buildInitDBFlags(cluster)).To(ContainElement(ContainSubstring("--wal-segsize=32")))
+	})
+
+	It("prefer the WAL segment size of the PostgreSQL configuration", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					WalSegmentSize: 64,
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						WalSegmentSize: 32,
+					},
+				},
+			},
+		}
+		flags := buildInitDBFlags(cluster)
+		Expect(flags).To(ContainElement(ContainSubstring("--wal-segsize=64")))
+		Expect(flags).ToNot(ContainElement(ContainSubstring("--wal-segsize=32")))
+	})
+
+	It("don't pass the WAL segment size when it is not set", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{},
+				},
+			},
+		}
+		Expect(buildInitDBFlags(cluster)).ToNot(ContainElement(ContainSubstring("--wal-segsize")))
+	})
+
+	It("pass the ICU locale provider options", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						LocaleProvider: apiv1.LocaleProviderICU,
+						ICULocale:      "en-US",
+						ICURules:       "&a < g",
+					},
+				},
+			},
+		}
+		flags := buildInitDBFlags(cluster)
+		Expect(flags).To(ContainElement("--initdb-flags"))
+		initDBFlags, err := shellquote.Split(flags[len(flags)-1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(initDBFlags).To(ContainElements(
+			"--locale-provider=icu",
+			"--icu-locale=en-US",
+			"--icu-rules=&a < g",
+		))
+	})
+
+	It("don't pass the locale provider options when they are not set", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{},
+				},
+			},
+		}
+		flags := buildInitDBFlags(cluster)
+		Expect(flags).ToNot(ContainElement(ContainSubstring("--locale-provider")))
+		Expect(flags).ToNot(ContainElement(ContainSubstring("--icu-")))
+	})
+})
@@ -19,6 +19,8 @@ limitations under the License.
 package pgbouncer
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,8 +40,40 @@ import (
 const (
 	// DefaultPgbouncerImage is the name of the pgbouncer image used by default
 	DefaultPgbouncerImage = "ghcr.io/cloudnative-pg/pgbouncer:1.21.0"
+
+	// drainGracePeriodMargin is the number of seconds added to the drain
+	// timeout to let the instance manager shut down PgBouncer and exit
+	drainGracePeriodMargin = 10
 )
 
+// getImageName returns the PgBouncer image to be used by the pooler
+func getImageName(pooler *apiv1.Pooler) string {
+	if pooler.Spec.ImageName != "" {
+		return pooler.Spec.ImageName
+	}
+
+	return DefaultPgbouncerImage
+}
+
+// getCommand returns the command running the PgBouncer instance manager
+func getCommand(pooler *apiv1.Pooler) []string {
+	command := []string{
+		"/controller/manager",
+		"pgbouncer",
+		"run",
+	}
+
+	if pooler.IsMetricsTLSEnabled() {
+		command = append(command, "--metrics-tls")
+	}
+
+	if pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.DrainTimeout != nil {
+		command = append(command, fmt.Sprintf("--drain-timeout=%s", pooler.Spec.PgBouncer.GetDrainTimeout()))
+	}
+
+	return command
+}
+
 // Deployment create the deployment of pgbouncer, given
 // the configurations we have in the pooler specifications
 func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deployment, error) {
@@ -48,7 +82,17 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		return nil, err
 	}
 
-	podTemplate := podspec.NewFrom(pooler.Spec.Template).
+	// The template is copied, as the builder changes its containers
+	builder := podspec.NewFrom(pooler.Spec.Template.DeepCopy())
+
+	// The environment variables of the spec take precedence over the
+	// ones of the template, and are overwritten by the ones managed
+	// by the operator
+	for _, env := range pooler.Spec.Env {
+		builder.WithContainerEnv("pgbouncer", env, true)
+	}
+
+	builder.
 		WithLabel(utils.PgbouncerNameLabel, pooler.Name).
 		WithLabel(utils.ClusterLabelName, cluster.Name).
 		WithVolume(&corev1.Volume{
@@ -68,12 +112,8 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			},
 		}).
 		WithSecurityContext(specs.CreatePodSecurityContext(cluster.GetSeccompProfile(), 998, 996), true).
-		WithContainerImage("pgbouncer", DefaultPgbouncerImage, false).
-		WithContainerCommand("pgbouncer", []string{
-			"/controller/manager",
-			"pgbouncer",
-			"run",
-		}, false).
+		WithContainerImage("pgbouncer", getImageName(pooler), false).
+		WithContainerCommand("pgbouncer", getCommand(pooler), false).
 		WithContainerPort("pgbouncer", &corev1.ContainerPort{
 			Name:          "pgbouncer",
 			ContainerPort: pgBouncerConfig.PgBouncerPort,
@@ -114,8 +154,18 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 					Port: intstr.FromInt(pgBouncerConfig.PgBouncerPort),
 				},
 			},
-		}, false).
-		Build()
+		}, false)
+
+	// The pod must not be killed while the connections are being drained,
+	// unless the template explicitly asks for it
+	if pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.DrainTimeout != nil {
+		builder.WithTerminationGracePeriodSeconds(int64(*pooler.Spec.PgBouncer.DrainTimeout)+drainGracePeriodMargin, false)
+	}
+
+	for _, secret := range pooler.Spec.ImagePullSecrets {
+		builder.WithImagePullSecret(secret.Name)
+	}
+	podTemplate := builder.Build()
 
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
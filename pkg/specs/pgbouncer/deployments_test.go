@@ -18,8 +18,10 @@ package pgbouncer
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
@@ -100,6 +102,77 @@ var _ = Describe("Deployment", func() {
 		Expect(podTemplate.Spec.Containers[0].Image).To(Equal(DefaultPgbouncerImage))
 	})
 
+	It("uses the image and the pull secrets specified in the pooler", func() {
+		pooler.Spec.ImageName = "mirror.example.com/pgbouncer/pgbouncer:1.21.0"
+		pooler.Spec.ImagePullSecrets = []apiv1.LocalObjectReference{{Name: "pgbouncer-mirror"}}
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment).ToNot(BeNil())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.Containers[0].Image).To(Equal("mirror.example.com/pgbouncer/pgbouncer:1.21.0"))
+		Expect(podSpec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "pgbouncer-mirror"}))
+	})
+
+	It("gives precedence to the image specified in the pod template", func() {
+		pooler.Spec.ImageName = "mirror.example.com/pgbouncer/pgbouncer:1.21.0"
+		pooler.Spec.Template = &apiv1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "pgbouncer",
+						Image: "custom.example.com/pgbouncer:1.21.0",
+					},
+				},
+			},
+		}
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("custom.example.com/pgbouncer:1.21.0"))
+	})
+
+	It("merges the environment variables onto the PgBouncer container", func() {
+		pooler.Spec.Template = &apiv1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "pgbouncer",
+						Env: []corev1.EnvVar{
+							{Name: "AGENT_ENDPOINT", Value: "template"},
+							{Name: "AGENT_LEVEL", Value: "debug"},
+							{Name: "POOLER_NAME", Value: "template"},
+						},
+					},
+				},
+			},
+		}
+		pooler.Spec.Env = []corev1.EnvVar{
+			{Name: "AGENT_ENDPOINT", Value: "spec"},
+			{Name: "AGENT_TOKEN", Value: "secret"},
+			{Name: "NAMESPACE", Value: "spec"},
+		}
+
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: "AGENT_ENDPOINT", Value: "spec"},
+			corev1.EnvVar{Name: "AGENT_LEVEL", Value: "debug"},
+			corev1.EnvVar{Name: "AGENT_TOKEN", Value: "secret"},
+			corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace},
+			corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name},
+		))
+
+		// the pod template of the pooler is left untouched
+		Expect(pooler.Spec.Template.Spec.Containers[0].Env).To(ContainElement(
+			corev1.EnvVar{Name: "POOLER_NAME", Value: "template"}))
+	})
+
+	It("does not set pull secrets when none is specified", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(BeEmpty())
+	})
+
 	It("sets the correct number of replicas", func() {
 		pooler.Spec.Instances = 3
 		deployment, err := Deployment(pooler, cluster)
@@ -143,6 +216,59 @@ var _ = Describe("Deployment", func() {
 		Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(pooler.Name))
 	})
 
+	It("doesn't change the pod template when only the PgBouncer configuration changes", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// These changes are applied by the instance manager reloading
+		// PgBouncer, and must not trigger a rollout of the pods
+		pooler.Spec.PgBouncer.PoolMode = apiv1.PgBouncerPoolModeTransaction
+		pooler.Spec.PgBouncer.Parameters = map[string]string{"max_client_conn": "1000"}
+		updatedDeployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(updatedDeployment.Spec.Template).To(Equal(deployment.Spec.Template))
+		Expect(updatedDeployment.Annotations[utils.PoolerSpecHashAnnotationName]).
+			ToNot(Equal(deployment.Annotations[utils.PoolerSpecHashAnnotationName]))
+	})
+
+	It("serves the metrics over TLS only when requested", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Command).ToNot(ContainElement("--metrics-tls"))
+
+		pooler.Spec.Monitoring.TLSConfig = &apiv1.PoolerMonitoringTLSConfiguration{Enabled: true}
+		deployment, err = Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{
+			"/controller/manager",
+			"pgbouncer",
+			"run",
+			"--metrics-tls",
+		}))
+	})
+
+	It("drains the connections only when requested", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Command).ToNot(ContainElement(HavePrefix("--drain-timeout")))
+		Expect(deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeNil())
+
+		pooler.Spec.PgBouncer.DrainTimeout = ptr.To(int32(60))
+		deployment, err = Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Command).To(ContainElement("--drain-timeout=1m0s"))
+		Expect(deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(HaveValue(Equal(int64(70))))
+	})
+
+	It("keeps the termination grace period of the template", func() {
+		pooler.Spec.PgBouncer.DrainTimeout = ptr.To(int32(60))
+		pooler.Spec.Template.Spec.TerminationGracePeriodSeconds = ptr.To(int64(120))
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(HaveValue(Equal(int64(120))))
+	})
+
 	It("sets the correct readiness probe", func() {
 		deployment, err := Deployment(pooler, cluster)
 		Expect(err).ShouldNot(HaveOccurred())
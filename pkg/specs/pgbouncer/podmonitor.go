@@ -18,20 +18,23 @@ package pgbouncer
 
 import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // PoolerPodMonitorManager builds the PodMonitor for the pooler resource
 type PoolerPodMonitorManager struct {
-	pooler *apiv1.Pooler
+	pooler  *apiv1.Pooler
+	cluster *apiv1.Cluster
 }
 
 // NewPoolerPodMonitorManager returns a new instance of PoolerPodMonitorManager
-func NewPoolerPodMonitorManager(pooler *apiv1.Pooler) *PoolerPodMonitorManager {
-	return &PoolerPodMonitorManager{pooler: pooler}
+func NewPoolerPodMonitorManager(pooler *apiv1.Pooler, cluster *apiv1.Cluster) *PoolerPodMonitorManager {
+	return &PoolerPodMonitorManager{pooler: pooler, cluster: cluster}
 }
 
 // IsPodMonitorEnabled returns a boolean indicating if the PodMonitor should exists or not
@@ -51,15 +54,36 @@ func (c PoolerPodMonitorManager) BuildPodMonitor() *monitoringv1.PodMonitor {
 
 	utils.SetAsOwnedBy(&meta, c.pooler.ObjectMeta, c.pooler.TypeMeta)
 
+	endpoint := monitoringv1.PodMetricsEndpoint{
+		Port: "metrics",
+	}
+
+	if c.pooler.Spec.Monitoring != nil {
+		endpoint.Interval = monitoringv1.Duration(c.pooler.Spec.Monitoring.PodMonitorInterval)
+	}
+
+	if c.pooler.IsMetricsTLSEnabled() {
+		endpoint.Scheme = "https"
+		endpoint.TLSConfig = &monitoringv1.PodMetricsEndpointTLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				CA: monitoringv1.SecretOrConfigMap{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: c.cluster.GetServerCASecretName(),
+						},
+						Key: certs.CACertKey,
+					},
+				},
+				ServerName: c.cluster.GetServiceReadWriteName(),
+			},
+		}
+	}
+
 	spec := monitoringv1.PodMonitorSpec{
 		Selector: metav1.LabelSelector{
 			MatchLabels: meta.Labels,
 		},
-		PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
-			{
-				Port: "metrics",
-			},
-		},
+		PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{endpoint},
 	}
 
 	return &monitoringv1.PodMonitor{
@@ -17,9 +17,11 @@ limitations under the License.
 package pgbouncer
 
 import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -27,9 +29,18 @@ import (
 )
 
 var _ = Describe("PoolerPodMonitorManager", func() {
-	var pooler *apiv1.Pooler
+	var (
+		pooler  *apiv1.Pooler
+		cluster *apiv1.Cluster
+	)
 
 	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+		}
 		pooler = &apiv1.Pooler{
 			TypeMeta: metav1.TypeMeta{
 				Kind:       "Pooler",
@@ -49,7 +60,7 @@ var _ = Describe("PoolerPodMonitorManager", func() {
 
 	Context("when calling IsPodMonitorEnabled", func() {
 		It("returns the correct value", func() {
-			manager := NewPoolerPodMonitorManager(pooler)
+			manager := NewPoolerPodMonitorManager(pooler, cluster)
 
 			Expect(manager.IsPodMonitorEnabled()).To(BeFalse())
 
@@ -64,7 +75,7 @@ var _ = Describe("PoolerPodMonitorManager", func() {
 		})
 
 		It("returns the correct PodMonitor object", func() {
-			manager := NewPoolerPodMonitorManager(pooler)
+			manager := NewPoolerPodMonitorManager(pooler, cluster)
 
 			podMonitor := manager.BuildPodMonitor()
 
@@ -80,6 +91,31 @@ var _ = Describe("PoolerPodMonitorManager", func() {
 
 			Expect(podMonitor.Spec.PodMetricsEndpoints).To(HaveLen(1))
 			Expect(podMonitor.Spec.PodMetricsEndpoints[0].Port).To(Equal("metrics"))
+			Expect(podMonitor.Spec.PodMetricsEndpoints[0].Interval).To(BeEmpty())
+			Expect(podMonitor.Spec.PodMetricsEndpoints[0].Scheme).To(BeEmpty())
+			Expect(podMonitor.Spec.PodMetricsEndpoints[0].TLSConfig).To(BeNil())
+		})
+
+		It("uses the configured scrape interval", func() {
+			pooler.Spec.Monitoring.PodMonitorInterval = "15s"
+			podMonitor := NewPoolerPodMonitorManager(pooler, cluster).BuildPodMonitor()
+
+			Expect(podMonitor.Spec.PodMetricsEndpoints).To(HaveLen(1))
+			Expect(podMonitor.Spec.PodMetricsEndpoints[0].Interval).To(Equal(monitoringv1.Duration("15s")))
+		})
+
+		It("scrapes the metrics over TLS when enabled", func() {
+			pooler.Spec.Monitoring.TLSConfig = &apiv1.PoolerMonitoringTLSConfiguration{Enabled: true}
+			podMonitor := NewPoolerPodMonitorManager(pooler, cluster).BuildPodMonitor()
+
+			Expect(podMonitor.Spec.PodMetricsEndpoints).To(HaveLen(1))
+			endpoint := podMonitor.Spec.PodMetricsEndpoints[0]
+			Expect(endpoint.Scheme).To(Equal("https"))
+			Expect(endpoint.TLSConfig).ToNot(BeNil())
+			Expect(endpoint.TLSConfig.ServerName).To(Equal("test-cluster-rw"))
+			Expect(endpoint.TLSConfig.CA.Secret).ToNot(BeNil())
+			Expect(endpoint.TLSConfig.CA.Secret.Name).To(Equal(cluster.GetServerCASecretName()))
+			Expect(endpoint.TLSConfig.CA.Secret.Key).To(Equal(certs.CACertKey))
 		})
 	})
 })
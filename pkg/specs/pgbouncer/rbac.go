@@ -47,6 +47,16 @@ func Role(pooler *apiv1.Pooler) *v1.Role {
 		if pooler.Status.Secrets.ClientCA.Name != "" {
 			secretNames = append(secretNames, pooler.Status.Secrets.ClientCA.Name)
 		}
+
+		if pgbouncerSecrets := pooler.Status.Secrets.PgBouncerSecrets; pgbouncerSecrets != nil {
+			if pgbouncerSecrets.ClientTLS.Name != "" {
+				secretNames = append(secretNames, pgbouncerSecrets.ClientTLS.Name)
+			}
+
+			if pgbouncerSecrets.ClientCA.Name != "" {
+				secretNames = append(secretNames, pgbouncerSecrets.ClientCA.Name)
+			}
+		}
 	}
 
 	return &v1.Role{ObjectMeta: metav1.ObjectMeta{
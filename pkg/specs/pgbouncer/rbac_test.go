@@ -62,6 +62,15 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 			Expect(role.Rules[2].Resources).To(ContainElement("secrets"))
 			Expect(role.Rules[2].Verbs).To(ConsistOf("get", "watch"))
 		})
+
+		It("allows reading the user-provided TLS secrets", func() {
+			pooler.Status.Secrets.PgBouncerSecrets = &apiv1.PgBouncerSecrets{
+				ClientTLS: apiv1.SecretVersion{Name: "pooler-tls"},
+				ClientCA:  apiv1.SecretVersion{Name: "pooler-client-ca"},
+			}
+			role := Role(pooler)
+			Expect(role.Rules[2].ResourceNames).To(ContainElements("pooler-tls", "pooler-client-ca"))
+		})
 	})
 
 	Context("when creating a RoleBinding", func() {
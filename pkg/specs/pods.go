@@ -24,6 +24,7 @@ import (
 	"math"
 	"reflect"
 	"strconv"
+	"time"
 
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
@@ -81,6 +82,12 @@ const (
 	// PgWalArchiveStatusPath is the path to the archive status directory
 	PgWalArchiveStatusPath = PgWalPath + "/archive_status"
 
+	// PgWalArchivePendingPath is the directory, in the PGDATA volume but
+	// outside PGDATA, where the WAL files failed on some object store are
+	// queued to be archived again. It is kept across restarts, and not
+	// copied by pg_basebackup or pg_rewind
+	PgWalArchivePendingPath = "/var/lib/postgresql/data/wal-archive-pending"
+
 	// ReadinessProbePeriod is the period set for the postgres instance readiness probe
 	ReadinessProbePeriod = 10
 
@@ -160,14 +167,18 @@ func CreateClusterPodSpec(
 	envConfig EnvConfig,
 	gracePeriod int64,
 ) corev1.PodSpec {
-	return corev1.PodSpec{
+	podSpec := corev1.PodSpec{
 		Hostname: podName,
-		InitContainers: []corev1.Container{
-			createBootstrapContainer(cluster),
-		},
+		InitContainers: append(
+			[]corev1.Container{createBootstrapContainer(cluster)},
+			createUserContainers(cluster.Spec.InitContainers)...,
+		),
 		SchedulerName: cluster.Spec.SchedulerName,
-		Containers:    createPostgresContainers(cluster, envConfig),
-		Volumes:       createPostgresVolumes(cluster, podName),
+		Containers: append(
+			createPostgresContainers(cluster, envConfig),
+			createUserContainers(cluster.Spec.Containers)...,
+		),
+		Volumes: createPostgresVolumes(cluster, podName),
 		SecurityContext: CreatePodSecurityContext(
 			cluster.GetSeccompProfile(),
 			cluster.GetPostgresUID(),
@@ -179,6 +190,12 @@ func CreateClusterPodSpec(
 		TerminationGracePeriodSeconds: &gracePeriod,
 		TopologySpreadConstraints:     cluster.Spec.TopologySpreadConstraints,
 	}
+
+	if cluster.ShouldCreateBackupVolume() {
+		addBackupVolume(&podSpec, "backup", cluster.GetBackupVolumeName(), postgres.BackupVolumeDirectory, false)
+	}
+
+	return podSpec
 }
 
 // createPostgresContainers create the PostgreSQL containers that are
@@ -192,42 +209,25 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 			Env:             envConfig.EnvVars,
 			EnvFrom:         envConfig.EnvFrom,
 			VolumeMounts:    createPostgresVolumeMounts(cluster),
-			StartupProbe: &corev1.Probe{
-				FailureThreshold: getStartupProbeFailureThreshold(cluster.GetMaxStartDelay()),
-				PeriodSeconds:    StartupProbePeriod,
-				TimeoutSeconds:   5,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathHealth,
-						Port: intstr.FromInt32(int32(url.StatusPort)),
-					},
-				},
-			},
-			ReadinessProbe: &corev1.Probe{
-				TimeoutSeconds: 5,
-				PeriodSeconds:  ReadinessProbePeriod,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathReady,
-						Port: intstr.FromInt(url.StatusPort),
-					},
-				},
-			},
-			LivenessProbe: &corev1.Probe{
-				PeriodSeconds:  LivenessProbePeriod,
-				TimeoutSeconds: 5,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathHealth,
-						Port: intstr.FromInt(url.StatusPort),
-					},
-				},
-			},
+			StartupProbe:    createStartupProbe(cluster),
+			ReadinessProbe:  createReadinessProbe(cluster),
+			LivenessProbe:   createLivenessProbe(cluster),
 			Command: []string{
 				"/controller/manager",
 				"instance",
 				"run",
 			},
+			Lifecycle: &corev1.Lifecycle{
+				PreStop: &corev1.LifecycleHandler{
+					Exec: &corev1.ExecAction{
+						Command: []string{
+							"/controller/manager",
+							"instance",
+							"prestop",
+						},
+					},
+				},
+			},
 			Resources: cluster.Spec.Resources,
 			Ports: []corev1.ContainerPort{
 				{
@@ -255,13 +255,94 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 	return containers
 }
 
+// createUserContainers creates the containers requested by the user,
+// which are added after the ones managed by the operator
+func createUserContainers(containers []corev1.Container) []corev1.Container {
+	result := make([]corev1.Container, 0, len(containers))
+	for i := range containers {
+		result = append(result, *containers[i].DeepCopy())
+	}
+
+	return result
+}
+
+// createStartupProbe creates the startup probe of the PostgreSQL container,
+// applying the timings requested by the user over the default ones.
+// Unless it has been explicitly set, the failure threshold is derived
+// from the startup delay of the cluster
+func createStartupProbe(cluster apiv1.Cluster) *corev1.Probe {
+	probe := &corev1.Probe{
+		PeriodSeconds:  StartupProbePeriod,
+		TimeoutSeconds: 5,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: url.PathHealth,
+				Port: intstr.FromInt32(int32(url.StatusPort)),
+			},
+		},
+	}
+
+	timings := cluster.Spec.Probes.GetStartup()
+	timings.ApplyTo(probe)
+	if timings == nil || timings.FailureThreshold == nil {
+		probe.FailureThreshold = getStartupProbeFailureThreshold(cluster.GetMaxStartDelay(), probe.PeriodSeconds)
+	}
+
+	return probe
+}
+
+// createReadinessProbe creates the readiness probe of the PostgreSQL container,
+// applying the timings requested by the user over the default ones
+func createReadinessProbe(cluster apiv1.Cluster) *corev1.Probe {
+	probe := &corev1.Probe{
+		TimeoutSeconds: 5,
+		PeriodSeconds:  ReadinessProbePeriod,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: url.PathReady,
+				Port: intstr.FromInt(url.StatusPort),
+			},
+		},
+	}
+	cluster.Spec.Probes.GetReadiness().ApplyTo(probe)
+
+	return probe
+}
+
+// createLivenessProbe creates the liveness probe of the PostgreSQL container,
+// applying the timings requested by the user over the default ones
+func createLivenessProbe(cluster apiv1.Cluster) *corev1.Probe {
+	probe := &corev1.Probe{
+		PeriodSeconds:  LivenessProbePeriod,
+		TimeoutSeconds: 5,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: url.PathHealth,
+				Port: intstr.FromInt(url.StatusPort),
+			},
+		},
+	}
+	cluster.Spec.Probes.GetLiveness().ApplyTo(probe)
+
+	return probe
+}
+
+// GetLivenessProbeTimeout gets the time the liveness probe of the
+// PostgreSQL container waits for the instance manager to answer
+func GetLivenessProbeTimeout(cluster apiv1.Cluster) time.Duration {
+	return time.Duration(createLivenessProbe(cluster).TimeoutSeconds) * time.Second
+}
+
 // getStartupProbeFailureThreshold get the startup probe failure threshold
 // FAILURE_THRESHOLD = ceil(startDelay / periodSeconds) and minimum value is 1
-func getStartupProbeFailureThreshold(startupDelay int32) int32 {
-	if startupDelay <= StartupProbePeriod {
+func getStartupProbeFailureThreshold(startupDelay, periodSeconds int32) int32 {
+	if periodSeconds <= 0 {
+		periodSeconds = StartupProbePeriod
+	}
+	if startupDelay <= periodSeconds {
 		return 1
 	}
-	return int32(math.Ceil(float64(startupDelay) / float64(StartupProbePeriod)))
+	return int32(math.Ceil(float64(startupDelay) / float64(periodSeconds)))
 }
 
 // CreateAffinitySection creates the affinity sections for Pods, given the configuration
@@ -18,10 +18,12 @@ package specs
 
 import (
 	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -828,14 +830,270 @@ var _ = Describe("PodSpec drift detection", func() {
 			"containers: container postgres differs in resources"))
 		Expect(specsMatch).To(BeFalse())
 	})
+
+	It("detects startup probe mismatch on the postgres container", func() {
+		cluster := v1.Cluster{Spec: v1.ClusterSpec{MaxStartDelay: 300}}
+		podSpec1 := corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:         PostgresContainerName,
+					StartupProbe: createStartupProbe(cluster),
+				},
+			},
+		}
+		cluster.Spec.MaxStartDelay = 600
+		podSpec2 := corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:         PostgresContainerName,
+					StartupProbe: createStartupProbe(cluster),
+				},
+			},
+		}
+
+		specsMatch, diff := ComparePodSpecs(podSpec1, podSpec2)
+		Expect(diff).To(ContainSubstring(
+			"containers: container postgres differs in startup-probe"))
+		Expect(specsMatch).To(BeFalse())
+	})
 })
 
 var _ = Describe("Compute startup probe failure threshold", func() {
 	It("should take the minimum value 1", func() {
-		Expect(getStartupProbeFailureThreshold(5)).To(BeNumerically("==", 1))
+		Expect(getStartupProbeFailureThreshold(5, StartupProbePeriod)).To(BeNumerically("==", 1))
 	})
 
 	It("should take the value from 'startDelay / periodSeconds'", func() {
-		Expect(getStartupProbeFailureThreshold(109)).To(BeNumerically("==", 11))
+		Expect(getStartupProbeFailureThreshold(109, StartupProbePeriod)).To(BeNumerically("==", 11))
+	})
+})
+
+var _ = Describe("Probes timings", func() {
+	It("uses the operator defaults when nothing is configured", func() {
+		cluster := v1.Cluster{Spec: v1.ClusterSpec{MaxStartDelay: 300}}
+
+		startup := createStartupProbe(cluster)
+		Expect(startup.PeriodSeconds).To(BeEquivalentTo(StartupProbePeriod))
+		Expect(startup.TimeoutSeconds).To(BeEquivalentTo(5))
+		Expect(startup.FailureThreshold).To(BeEquivalentTo(30))
+
+		readiness := createReadinessProbe(cluster)
+		Expect(readiness.PeriodSeconds).To(BeEquivalentTo(ReadinessProbePeriod))
+		Expect(readiness.TimeoutSeconds).To(BeEquivalentTo(5))
+		Expect(readiness.FailureThreshold).To(BeZero())
+
+		liveness := createLivenessProbe(cluster)
+		Expect(liveness.PeriodSeconds).To(BeEquivalentTo(LivenessProbePeriod))
+		Expect(liveness.TimeoutSeconds).To(BeEquivalentTo(5))
+		Expect(GetLivenessProbeTimeout(cluster)).To(Equal(5 * time.Second))
+	})
+
+	It("gets the configured timeout of the liveness probe", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Probes: &v1.ProbesConfiguration{
+					Liveness: &v1.ProbeTimings{
+						TimeoutSeconds: ptr.To(int32(10)),
+					},
+				},
+			},
+		}
+
+		Expect(GetLivenessProbeTimeout(cluster)).To(Equal(10 * time.Second))
+	})
+
+	It("merges the configured timings over the defaults", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 300,
+				Probes: &v1.ProbesConfiguration{
+					Liveness: &v1.ProbeTimings{
+						InitialDelaySeconds: ptr.To(int32(15)),
+						FailureThreshold:    ptr.To(int32(12)),
+					},
+					Readiness: &v1.ProbeTimings{
+						TimeoutSeconds: ptr.To(int32(8)),
+					},
+				},
+			},
+		}
+
+		liveness := createLivenessProbe(cluster)
+		Expect(liveness.InitialDelaySeconds).To(BeEquivalentTo(15))
+		Expect(liveness.FailureThreshold).To(BeEquivalentTo(12))
+		Expect(liveness.PeriodSeconds).To(BeEquivalentTo(LivenessProbePeriod))
+		Expect(liveness.TimeoutSeconds).To(BeEquivalentTo(5))
+		Expect(liveness.HTTPGet).ToNot(BeNil())
+
+		readiness := createReadinessProbe(cluster)
+		Expect(readiness.TimeoutSeconds).To(BeEquivalentTo(8))
+		Expect(readiness.PeriodSeconds).To(BeEquivalentTo(ReadinessProbePeriod))
+	})
+
+	It("derives the startup failure threshold from the configured period", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 300,
+				Probes: &v1.ProbesConfiguration{
+					Startup: &v1.ProbeTimings{
+						PeriodSeconds: ptr.To(int32(30)),
+					},
+				},
+			},
+		}
+
+		startup := createStartupProbe(cluster)
+		Expect(startup.PeriodSeconds).To(BeEquivalentTo(30))
+		Expect(startup.FailureThreshold).To(BeEquivalentTo(10))
+	})
+
+	It("uses the startup failure threshold when explicitly set", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 300,
+				Probes: &v1.ProbesConfiguration{
+					Startup: &v1.ProbeTimings{
+						FailureThreshold: ptr.To(int32(100)),
+					},
+				},
+			},
+		}
+
+		Expect(createStartupProbe(cluster).FailureThreshold).To(BeEquivalentTo(100))
+	})
+})
+
+var _ = Describe("Barman endpoint CA", func() {
+	endpointCA := &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "endpoint-ca"},
+		Key:                  "ca.crt",
+	}
+
+	It("mounts the CA bundle and points the AWS client to it", func() {
+		podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: PostgresContainerName}}}
+		AddBarmanEndpointCAToPodSpec(&podSpec, endpointCA, v1.BarmanCredentials{})
+
+		Expect(podSpec.Volumes).To(HaveLen(1))
+		Expect(podSpec.Volumes[0].Secret.SecretName).To(Equal("endpoint-ca"))
+		Expect(podSpec.Volumes[0].Secret.Items).To(ConsistOf(corev1.KeyToPath{
+			Key:  "ca.crt",
+			Path: postgres.BarmanRestoreEndpointCACertificateFileName,
+		}))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      podSpec.Volumes[0].Name,
+			MountPath: postgres.CertificatesDir,
+		}))
+		Expect(podSpec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{
+			Name:  "AWS_CA_BUNDLE",
+			Value: postgres.BarmanRestoreEndpointCACertificateLocation,
+		}))
+	})
+
+	It("points the Azure client to the CA bundle", func() {
+		podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: PostgresContainerName}}}
+		AddBarmanEndpointCAToPodSpec(&podSpec, endpointCA, v1.BarmanCredentials{
+			Azure: &v1.AzureCredentials{InheritFromAzureAD: true},
+		})
+
+		Expect(podSpec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{
+			Name:  "REQUESTS_CA_BUNDLE",
+			Value: postgres.BarmanRestoreEndpointCACertificateLocation,
+		}))
+	})
+
+	It("doesn't change the pod when the CA is not set", func() {
+		podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: PostgresContainerName}}}
+		AddBarmanEndpointCAToPodSpec(&podSpec, nil, v1.BarmanCredentials{})
+
+		Expect(podSpec.Volumes).To(BeEmpty())
+		Expect(podSpec.Containers[0].Env).To(BeEmpty())
+	})
+})
+
+var _ = Describe("User defined containers", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: v1.ClusterSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "metrics-proxy",
+					Image: "proxy:latest",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "pgdata",
+							MountPath: "/var/lib/postgresql/data",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			InitContainers: []corev1.Container{
+				{
+					Name:  "setup",
+					Image: "setup:latest",
+				},
+			},
+		},
+	}
+
+	It("adds the user containers after the ones managed by the operator", func() {
+		podSpec := CreateClusterPodSpec("cluster-example-1", cluster, EnvConfig{}, 30)
+
+		Expect(podSpec.Containers).To(HaveLen(2))
+		Expect(podSpec.Containers[0].Name).To(Equal(PostgresContainerName))
+		Expect(podSpec.Containers[1]).To(Equal(cluster.Spec.Containers[0]))
+
+		Expect(podSpec.InitContainers).To(HaveLen(2))
+		Expect(podSpec.InitContainers[0].Name).To(Equal(BootstrapControllerContainerName))
+		Expect(podSpec.InitContainers[1]).To(Equal(cluster.Spec.InitContainers[0]))
+	})
+
+	It("doesn't share the containers with the cluster spec", func() {
+		podSpec := CreateClusterPodSpec("cluster-example-1", cluster, EnvConfig{}, 30)
+		podSpec.Containers[1].VolumeMounts[0].ReadOnly = false
+
+		Expect(cluster.Spec.Containers[0].VolumeMounts[0].ReadOnly).To(BeTrue())
+	})
+
+	It("detects the drift of the user containers", func() {
+		podSpec := CreateClusterPodSpec("cluster-example-1", cluster, EnvConfig{}, 30)
+
+		changedCluster := cluster.DeepCopy()
+		changedCluster.Spec.Containers[0].Args = []string{"--verbose"}
+		changedPodSpec := CreateClusterPodSpec("cluster-example-1", *changedCluster, EnvConfig{}, 30)
+
+		specsMatch, diff := ComparePodSpecs(podSpec, changedPodSpec)
+		Expect(specsMatch).To(BeFalse())
+		Expect(diff).To(ContainSubstring("container metrics-proxy differs in args"))
+	})
+})
+
+var _ = Describe("PostgreSQL container lifecycle", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+	}
+
+	It("coordinates the shutdown through the preStop hook", func() {
+		podSpec := CreateClusterPodSpec("cluster-example-1", cluster, EnvConfig{}, 30)
+
+		Expect(podSpec.Containers[0].Lifecycle).ToNot(BeNil())
+		Expect(podSpec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal(
+			[]string{"/controller/manager", "instance", "prestop"}))
+	})
+
+	It("detects the drift of the lifecycle hooks", func() {
+		podSpec := CreateClusterPodSpec("cluster-example-1", cluster, EnvConfig{}, 30)
+		oldPodSpec := podSpec.DeepCopy()
+		oldPodSpec.Containers[0].Lifecycle = nil
+
+		specsMatch, diff := ComparePodSpecs(*oldPodSpec, podSpec)
+		Expect(specsMatch).To(BeFalse())
+		Expect(diff).To(ContainSubstring("differs in lifecycle"))
 	})
 })
@@ -154,15 +154,24 @@ func doContainersMatch(currentContainer, targetContainer corev1.Container) (bool
 				EnvVars: currentContainer.Env,
 			}.IsEnvEqual(targetContainer)
 		},
+		"startup-probe": func() bool {
+			return reflect.DeepEqual(currentContainer.StartupProbe, targetContainer.StartupProbe)
+		},
 		"readiness-probe": func() bool {
 			return reflect.DeepEqual(currentContainer.ReadinessProbe, targetContainer.ReadinessProbe)
 		},
 		"liveness-probe": func() bool {
 			return reflect.DeepEqual(currentContainer.LivenessProbe, targetContainer.LivenessProbe)
 		},
+		"lifecycle": func() bool {
+			return reflect.DeepEqual(currentContainer.Lifecycle, targetContainer.Lifecycle)
+		},
 		"command": func() bool {
 			return reflect.DeepEqual(currentContainer.Command, targetContainer.Command)
 		},
+		"args": func() bool {
+			return reflect.DeepEqual(currentContainer.Args, targetContainer.Args)
+		},
 		"resources": func() bool {
 			return reflect.DeepEqual(currentContainer.Resources, targetContainer.Resources)
 		},
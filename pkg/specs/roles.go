@@ -125,6 +125,17 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"patch",
 			},
 		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"pods",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
 	}
 
 	return rbacv1.Role{
@@ -156,6 +167,8 @@ func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) [
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(cluster)...)
+	involvedSecretNames = append(involvedSecretNames, managedSubscriptionsSecrets(cluster)...)
+	involvedSecretNames = append(involvedSecretNames, managedSQLJobsSecrets(cluster)...)
 
 	return cleanupResourceList(involvedSecretNames)
 }
@@ -349,3 +362,34 @@ func managedRolesSecrets(cluster apiv1.Cluster) []string {
 
 	return secretNames
 }
+
+func managedSubscriptionsSecrets(cluster apiv1.Cluster) []string {
+	if !cluster.ContainsManagedSubscriptionsConfiguration() {
+		return nil
+	}
+	managedSubscriptions := cluster.Spec.Managed.Subscriptions
+	secretNames := make([]string, 0, len(managedSubscriptions))
+	for _, subscription := range managedSubscriptions {
+		secretName := subscription.ConnectionSecret.Name
+		if secretName != "" {
+			secretNames = append(secretNames, secretName)
+		}
+	}
+
+	return secretNames
+}
+
+func managedSQLJobsSecrets(cluster apiv1.Cluster) []string {
+	if !cluster.ContainsManagedSQLJobsConfiguration() {
+		return nil
+	}
+	managedSQLJobs := cluster.Spec.Managed.SQLJobs
+	secretNames := make([]string, 0, len(managedSQLJobs))
+	for _, job := range managedSQLJobs {
+		if job.SecretRef != nil && job.SecretRef.Name != "" {
+			secretNames = append(secretNames, job.SecretRef.Name)
+		}
+	}
+
+	return secretNames
+}
@@ -165,7 +165,7 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules).To(HaveLen(7))
+		Expect(serviceAccount.Rules).To(HaveLen(8))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
@@ -355,3 +355,76 @@ var _ = Describe("Managed Roles", func() {
 		Expect(secretsPolicy.ResourceNames).To(ContainElements("my_secret1", "my_secret3"))
 	})
 })
+
+var _ = Describe("Managed Subscriptions", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thisTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Managed: &apiv1.ManagedConfiguration{
+				Subscriptions: []apiv1.SubscriptionConfiguration{
+					{
+						Name: "sub1",
+						ConnectionSecret: apiv1.SecretKeySelector{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "publisher"},
+							Key:                  "conninfo",
+						},
+						PublicationName: "pub1",
+					},
+				},
+			},
+		},
+	}
+
+	It("gets the list of secrets needed by the managed subscriptions", func() {
+		Expect(managedSubscriptionsSecrets(cluster)).To(ConsistOf("publisher"))
+		serviceAccount := CreateRole(cluster, nil)
+		var secretsPolicy v1.PolicyRule
+		for _, policy := range serviceAccount.Rules {
+			if len(policy.Resources) > 0 && policy.Resources[0] == "secrets" {
+				secretsPolicy = policy
+			}
+		}
+		Expect(secretsPolicy.ResourceNames).To(ContainElement("publisher"))
+	})
+})
+
+var _ = Describe("Managed SQL jobs", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thisTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Managed: &apiv1.ManagedConfiguration{
+				SQLJobs: []apiv1.SQLJobConfiguration{
+					{
+						Name: "inline",
+						SQL:  "ANALYZE",
+					},
+					{
+						Name: "migration",
+						SecretRef: &apiv1.SecretKeySelector{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "migration-script"},
+							Key:                  "script.sql",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	It("gets the list of secrets needed by the managed SQL jobs", func() {
+		Expect(managedSQLJobsSecrets(cluster)).To(ConsistOf("migration-script"))
+		serviceAccount := CreateRole(cluster, nil)
+		var secretsPolicy v1.PolicyRule
+		for _, policy := range serviceAccount.Rules {
+			if len(policy.Resources) > 0 && policy.Resources[0] == "secrets" {
+				secretsPolicy = policy
+			}
+		}
+		Expect(secretsPolicy.ResourceNames).To(ContainElement("migration-script"))
+	})
+})
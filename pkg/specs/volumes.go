@@ -98,9 +98,34 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 			})
 	}
 
+	if cluster.ShouldCreateLogVolume() {
+		result = append(result,
+			corev1.Volume{
+				Name: "pg-log",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: podName + apiv1.LogVolumeSuffix,
+					},
+				},
+			})
+	}
+
 	if cluster.ShouldCreateProjectedVolume() {
 		result = append(result, createProjectedVolume(cluster))
 	}
+
+	if cluster.ShouldCreateStatsTempVolume() {
+		result = append(result,
+			corev1.Volume{
+				Name: "stats-temp",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium:    "Memory",
+						SizeLimit: cluster.GetStatsTempVolumeSizeLimit(),
+					},
+				},
+			})
+	}
 	return result
 }
 
@@ -212,6 +237,15 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if cluster.ShouldCreateLogVolume() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "pg-log",
+				MountPath: postgres.LogVolumeDirectory,
+			},
+		)
+	}
+
 	if cluster.ShouldCreateProjectedVolume() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
@@ -221,6 +255,15 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if cluster.ShouldCreateStatsTempVolume() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "stats-temp",
+				MountPath: postgres.StatsTempDirectory,
+			},
+		)
+	}
+
 	return volumeMounts
 }
 
@@ -232,3 +275,26 @@ func createProjectedVolume(cluster apiv1.Cluster) corev1.Volume {
 		},
 	}
 }
+
+// addBackupVolume mounts a PVC containing the backups of a cluster in
+// the first container of the passed PodSpec, which runs PostgreSQL.
+// Unlike the other volumes, it's never mounted by the jobs
+func addBackupVolume(podSpec *corev1.PodSpec, name, claimName, mountPath string, readOnly bool) {
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+					ReadOnly:  readOnly,
+				},
+			},
+		})
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{
+			Name:      name,
+			MountPath: mountPath,
+			ReadOnly:  readOnly,
+		})
+}
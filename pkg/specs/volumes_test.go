@@ -18,8 +18,11 @@ package specs
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -290,3 +293,203 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 		}))
 	})
 })
+
+var _ = Describe("stats_temp_directory volume", func() {
+	newCluster := func(imageName string, enabled bool) apiv1.Cluster {
+		return apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: imageName,
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					StatsTempDirectoryInMemory: enabled,
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+	}
+
+	findVolume := func(volumes []corev1.Volume) *corev1.Volume {
+		for i := range volumes {
+			if volumes[i].Name == "stats-temp" {
+				return &volumes[i]
+			}
+		}
+		return nil
+	}
+
+	findVolumeMount := func(volumeMounts []corev1.VolumeMount) *corev1.VolumeMount {
+		for i := range volumeMounts {
+			if volumeMounts[i].Name == "stats-temp" {
+				return &volumeMounts[i]
+			}
+		}
+		return nil
+	}
+
+	It("is created in memory and sized after the pod memory when supported", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:14.10", true)
+
+		volume := findVolume(createPostgresVolumes(cluster, "cluster-example-1"))
+		Expect(volume).ToNot(BeNil())
+		Expect(volume.EmptyDir).ToNot(BeNil())
+		Expect(volume.EmptyDir.Medium).To(Equal(corev1.StorageMediumMemory))
+		Expect(volume.EmptyDir.SizeLimit.String()).To(Equal("64Mi"))
+
+		volumeMount := findVolumeMount(createPostgresVolumeMounts(cluster))
+		Expect(volumeMount).ToNot(BeNil())
+		Expect(volumeMount.MountPath).To(Equal(postgres.StatsTempDirectory))
+	})
+
+	It("is not created when the option is not enabled", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:14.10", false)
+		Expect(findVolume(createPostgresVolumes(cluster, "cluster-example-1"))).To(BeNil())
+		Expect(findVolumeMount(createPostgresVolumeMounts(cluster))).To(BeNil())
+	})
+
+	It("is not created on PostgreSQL versions keeping the statistics in shared memory", func() {
+		cluster := newCluster("ghcr.io/cloudnative-pg/postgresql:16.1", true)
+		Expect(findVolume(createPostgresVolumes(cluster, "cluster-example-1"))).To(BeNil())
+		Expect(findVolumeMount(createPostgresVolumeMounts(cluster))).To(BeNil())
+	})
+})
+
+var _ = Describe("log volume", func() {
+	findVolume := func(volumes []corev1.Volume) *corev1.Volume {
+		for i := range volumes {
+			if volumes[i].Name == "pg-log" {
+				return &volumes[i]
+			}
+		}
+		return nil
+	}
+
+	findVolumeMount := func(volumeMounts []corev1.VolumeMount) *corev1.VolumeMount {
+		for i := range volumeMounts {
+			if volumeMounts[i].Name == "pg-log" {
+				return &volumeMounts[i]
+			}
+		}
+		return nil
+	}
+
+	It("mounts the dedicated PVC in the log directory when requested", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				LogStorage: &apiv1.StorageConfiguration{Size: "1Gi"},
+			},
+		}
+
+		volume := findVolume(createPostgresVolumes(cluster, "cluster-example-1"))
+		Expect(volume).ToNot(BeNil())
+		Expect(volume.PersistentVolumeClaim).ToNot(BeNil())
+		Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("cluster-example-1-log"))
+
+		volumeMount := findVolumeMount(createPostgresVolumeMounts(cluster))
+		Expect(volumeMount).ToNot(BeNil())
+		Expect(volumeMount.MountPath).To(Equal(postgres.LogVolumeDirectory))
+	})
+
+	It("is not created when the log storage is not configured", func() {
+		cluster := apiv1.Cluster{}
+		Expect(findVolume(createPostgresVolumes(cluster, "cluster-example-1"))).To(BeNil())
+		Expect(findVolumeMount(createPostgresVolumeMounts(cluster))).To(BeNil())
+	})
+})
+
+var _ = Describe("backup volume", func() {
+	findVolume := func(volumes []corev1.Volume, name string) *corev1.Volume {
+		for i := range volumes {
+			if volumes[i].Name == name {
+				return &volumes[i]
+			}
+		}
+		return nil
+	}
+
+	findVolumeMount := func(volumeMounts []corev1.VolumeMount, name string) *corev1.VolumeMount {
+		for i := range volumeMounts {
+			if volumeMounts[i].Name == name {
+				return &volumeMounts[i]
+			}
+		}
+		return nil
+	}
+
+	newCluster := func(instances int, target apiv1.BackupTarget) apiv1.Cluster {
+		return apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Instances: instances,
+				Backup: &apiv1.BackupConfiguration{
+					Target: target,
+					VolumeBackup: &apiv1.VolumeBackupConfiguration{
+						Storage: apiv1.StorageConfiguration{Size: "10Gi"},
+					},
+				},
+			},
+		}
+	}
+
+	isMounted := func(cluster apiv1.Cluster, podName string) bool {
+		podSpec := CreateClusterPodSpec(podName, cluster, EnvConfig{}, 30)
+		volume := findVolume(podSpec.Volumes, "backup")
+		volumeMount := findVolumeMount(podSpec.Containers[0].VolumeMounts, "backup")
+		Expect(volume == nil).To(Equal(volumeMount == nil))
+		if volume == nil {
+			return false
+		}
+
+		Expect(volume.PersistentVolumeClaim).ToNot(BeNil())
+		Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("cluster-example-backup"))
+		Expect(volumeMount.MountPath).To(Equal(postgres.BackupVolumeDirectory))
+		Expect(findVolumeMount(podSpec.InitContainers[0].VolumeMounts, "backup")).To(BeNil())
+		return true
+	}
+
+	It("is mounted by every instance, whatever the backup target is", func() {
+		for _, target := range []apiv1.BackupTarget{apiv1.BackupTargetPrimary, apiv1.BackupTargetStandby} {
+			cluster := newCluster(3, target)
+			Expect(isMounted(cluster, "cluster-example-1")).To(BeTrue())
+			Expect(isMounted(cluster, "cluster-example-2")).To(BeTrue())
+		}
+	})
+
+	It("is not mounted by the jobs", func() {
+		cluster := newCluster(3, apiv1.BackupTargetStandby)
+		Expect(findVolume(createPostgresVolumes(cluster, "cluster-example-1"), "backup")).To(BeNil())
+		Expect(findVolumeMount(createPostgresVolumeMounts(cluster), "backup")).To(BeNil())
+	})
+
+	It("is not created when the backup volume is not configured", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{},
+			},
+		}
+		Expect(isMounted(cluster, "cluster-example-1")).To(BeFalse())
+	})
+
+	It("mounts the source backup volume, read-only, in the recovery job", func() {
+		cluster := newCluster(1, apiv1.BackupTargetPrimary)
+		cluster.Spec.Backup = nil
+		cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+			Recovery: &apiv1.BootstrapRecovery{
+				VolumeBackup: &apiv1.VolumeBackupSource{ClaimName: "origin-backup"},
+			},
+		}
+
+		podSpec := CreatePrimaryJobViaRecovery(cluster, 1, nil).Spec.Template.Spec
+		volume := findVolume(podSpec.Volumes, "recovery-backup")
+		Expect(volume).ToNot(BeNil())
+		Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("origin-backup"))
+		Expect(volume.PersistentVolumeClaim.ReadOnly).To(BeTrue())
+
+		volumeMount := findVolumeMount(podSpec.Containers[0].VolumeMounts, "recovery-backup")
+		Expect(volumeMount).ToNot(BeNil())
+		Expect(volumeMount.MountPath).To(Equal(postgres.RecoveryBackupVolumeDirectory))
+		Expect(volumeMount.ReadOnly).To(BeTrue())
+	})
+})
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/cnpgerrors"
 )
@@ -42,6 +44,29 @@ func ParsePolicy(policy string) (string, error) {
 	return fmt.Sprintf("RECOVERY WINDOW OF %v %v", matches[1], unitName[matches[2]]), nil
 }
 
+// GetRecoveryWindowStart returns the beginning of the recovery window
+// described by the passed retention policy, when it ends at `now`
+func GetRecoveryWindowStart(policy string, now time.Time) (time.Time, error) {
+	matches := regexPolicy.FindStringSubmatch(policy)
+	if len(matches) < 3 {
+		return time.Time{}, fmt.Errorf("not a valid policy")
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not a valid policy: %w", err)
+	}
+
+	switch matches[2] {
+	case "w":
+		return now.AddDate(0, 0, -7*value), nil
+	case "m":
+		return now.AddDate(0, -value, 0), nil
+	default:
+		return now.AddDate(0, 0, -value), nil
+	}
+}
+
 // MapToBarmanTagsFormat will transform a map[string]string into the
 // Barman tags format needed
 func MapToBarmanTagsFormat(option string, mapTags map[string]string) ([]string, error) {
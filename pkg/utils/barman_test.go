@@ -17,6 +17,8 @@ limitations under the License.
 package utils
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -41,6 +43,21 @@ var _ = Describe("parsing policy", func() {
 	})
 })
 
+var _ = Describe("recovery window start", func() {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	It("goes back the number of days, weeks or months of the policy", func() {
+		Expect(GetRecoveryWindowStart("7d", now)).To(Equal(time.Date(2024, 3, 24, 12, 0, 0, 0, time.UTC)))
+		Expect(GetRecoveryWindowStart("2w", now)).To(Equal(time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC)))
+		Expect(GetRecoveryWindowStart("1m", now)).To(Equal(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)))
+	})
+
+	It("must complain with a wrong policy", func() {
+		_, err := GetRecoveryWindowStart("30", now)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("converting map to barman tags format", func() {
 	It("returns an empty slice, if map is missing", func() {
 		Expect(MapToBarmanTagsFormat("test", nil)).To(BeEmpty())
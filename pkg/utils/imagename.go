@@ -26,8 +26,23 @@ var (
 	digestRegex = regexp.MustCompile(`@sha256:(?P<sha256>[a-fA-F0-9]+)$`)
 	tagRegex    = regexp.MustCompile(`:(?P<tag>[^/]+)$`)
 	hostRegex   = regexp.MustCompile(`^[^./:]+((\.[^./:]+)+(:[0-9]+)?|:[0-9]+)/`)
+
+	// referenceRegex follows the grammar of the image references
+	// accepted by the container runtimes:
+	// [domain[:port]/]path[:tag][@digest]
+	referenceRegex = regexp.MustCompile(
+		`^(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])` +
+			`(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+			`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*` +
+			`(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+			`(?::[\w][\w.-]{0,127})?` +
+			`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
 )
 
+// maxImageNameLength is the maximum length of the name part of an image
+// reference
+const maxImageNameLength = 255
+
 // Reference .
 type Reference struct {
 	Name   string
@@ -86,3 +101,24 @@ func GetImageTag(imageName string) string {
 	ref := NewReference(imageName)
 	return ref.Tag
 }
+
+// ValidateImageName checks if the passed string is a well-formed image
+// reference, returning an error describing the problem otherwise
+func ValidateImageName(imageName string) error {
+	if !referenceRegex.MatchString(imageName) {
+		return fmt.Errorf("invalid image reference format: %q", imageName)
+	}
+
+	name := imageName
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		name = name[:idx]
+	}
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		name = name[:idx]
+	}
+	if len(name) > maxImageNameLength {
+		return fmt.Errorf("image name longer than %d characters: %q", maxImageNameLength, imageName)
+	}
+
+	return nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package utils
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -46,4 +48,23 @@ var _ = Describe("image name management", func() {
 		Expect(GetImageTag("postgres@sha256:cff94dd382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866")).
 			To(BeEmpty())
 	})
+
+	It("should accept well-formed image references", func() {
+		Expect(ValidateImageName("postgres")).To(Succeed())
+		Expect(ValidateImageName("postgres:16.1")).To(Succeed())
+		Expect(ValidateImageName("localhost:5000/postgres:14.4")).To(Succeed())
+		Expect(ValidateImageName("mirror.example.com/cnpg/pgbouncer:1.21.0")).To(Succeed())
+		Expect(ValidateImageName("ghcr.io/cloudnative-pg/postgresql:16.1" +
+			"@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866")).To(Succeed())
+	})
+
+	It("should reject malformed image references", func() {
+		Expect(ValidateImageName("")).ToNot(Succeed())
+		Expect(ValidateImageName("Postgres:16")).ToNot(Succeed())
+		Expect(ValidateImageName("postgres:16:1")).ToNot(Succeed())
+		Expect(ValidateImageName("ghcr.io/cloudnative-pg/postgresql:")).ToNot(Succeed())
+		Expect(ValidateImageName("ghcr.io//postgresql:16")).ToNot(Succeed())
+		Expect(ValidateImageName("postgres@sha256:xyz")).ToNot(Succeed())
+		Expect(ValidateImageName("ghcr.io/" + strings.Repeat("a", 256))).ToNot(Succeed())
+	})
 })
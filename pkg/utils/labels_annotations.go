@@ -164,6 +164,27 @@ const (
 	// If the list contain the "*" element, every node is fenced.
 	FencedInstanceAnnotation = MetadataNamespace + "/fencedInstances"
 
+	// QuarantinedInstancesAnnotation is the annotation listing the instances
+	// that have been quarantined after repeatedly failing to start, the value
+	// should be a JSON list of all the instances. Quarantined instances stay
+	// fenced until they are removed from the list
+	QuarantinedInstancesAnnotation = MetadataNamespace + "/quarantinedInstances"
+
+	// ReinitializeAnnotationName is the annotation to be set to "true" on the
+	// Pod of a replica to make the operator delete its PVCs and clone it
+	// again from the primary
+	ReinitializeAnnotationName = MetadataNamespace + "/reinitialize"
+
+	// BypassMaintenanceWindowAnnotationName is the annotation to be set to
+	// "enabled" on a Cluster to apply the changes requiring a restart of
+	// the instances without waiting for the maintenance window
+	BypassMaintenanceWindowAnnotationName = MetadataNamespace + "/bypassMaintenanceWindow"
+
+	// SkipOperatorUpgradeAnnotationName is the annotation to be set to
+	// "enabled" on a Cluster to keep the image of its instances unchanged
+	// when the operator is upgraded, until the annotation is removed
+	SkipOperatorUpgradeAnnotationName = MetadataNamespace + "/skipOperatorUpgrade"
+
 	// CNPGHashAnnotationName is the name of the annotation containing the hash of the resource used by operator
 	// expect the pooler that uses PoolerSpecHashAnnotationName
 	CNPGHashAnnotationName = MetadataNamespace + "/hash"
@@ -220,6 +241,11 @@ const (
 	PVCRolePgData PVCRole = "PG_DATA"
 	// PVCRolePgWal is a PVC used for storing PG_WAL
 	PVCRolePgWal PVCRole = "PG_WAL"
+	// PVCRolePgLog is a PVC used for storing the PostgreSQL logs
+	PVCRolePgLog PVCRole = "PG_LOG"
+	// PVCRolePgBackup is the PVC, shared by the instances, used for
+	// storing the backups and the archived WAL files
+	PVCRolePgBackup PVCRole = "PG_BACKUP"
 )
 
 // LabelClusterName labels the object with the cluster name
@@ -368,6 +394,18 @@ func IsEmptyWalArchiveCheckEnabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[skipEmptyWalArchiveCheck] != string(annotationStatusEnabled)
 }
 
+// IsMaintenanceWindowBypassed checks if the restarts of the instances
+// can be executed outside the maintenance window
+func IsMaintenanceWindowBypassed(object *metav1.ObjectMeta) bool {
+	return object.Annotations[BypassMaintenanceWindowAnnotationName] == string(annotationStatusEnabled)
+}
+
+// IsOperatorUpgradeSkipped checks if the object has been cordoned from the
+// changes of the instance image caused by an operator upgrade
+func IsOperatorUpgradeSkipped(object *metav1.ObjectMeta) bool {
+	return object.Annotations[SkipOperatorUpgradeAnnotationName] == string(annotationStatusEnabled)
+}
+
 // MergeMap transfers the content of a giver map to a receiver
 func MergeMap(receiver, giver map[string]string) {
 	for key, value := range giver {
@@ -71,6 +71,18 @@ func IsPodEvicted(p *corev1.Pod) bool {
 		PodReasonEvicted == p.Status.Reason
 }
 
+// IsPodDisrupted checks if a Pod is about to be terminated by a disruption,
+// e.g. by the eviction issued while draining its node
+func IsPodDisrupted(p *corev1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.DisruptionTarget {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 // IsPodUnscheduled check if a Pod is unscheduled
 func IsPodUnscheduled(p *corev1.Pod) bool {
 	if corev1.PodPending != p.Status.Phase && corev1.PodFailed != p.Status.Phase {
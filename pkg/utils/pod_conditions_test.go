@@ -134,6 +134,25 @@ var _ = Describe("Pod conditions test suite", func() {
 		})
 	})
 
+	It("detects the pods being disrupted", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.DisruptionTarget,
+						Status: corev1.ConditionTrue,
+						Reason: "EvictionByEvictionAPI",
+					},
+				},
+			},
+		}
+		Expect(IsPodDisrupted(pod)).To(BeTrue())
+
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		Expect(IsPodDisrupted(pod)).To(BeFalse())
+		Expect(IsPodDisrupted(&corev1.Pod{})).To(BeFalse())
+	})
+
 	Describe("Must detect if a pod has been evicted or not", func() {
 		pod := &corev1.Pod{
 			Status: corev1.PodStatus{
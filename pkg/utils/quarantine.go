@@ -0,0 +1,76 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// ErrorQuarantinedInstancesSyntax is emitted when the quarantinedInstances
+// annotation have an invalid syntax
+var ErrorQuarantinedInstancesSyntax = errors.New("quarantinedInstances annotation has invalid syntax")
+
+// GetQuarantinedInstances gets the set of quarantined servers from the annotations
+func GetQuarantinedInstances(annotations map[string]string) (*stringset.Data, error) {
+	quarantinedInstances, ok := annotations[QuarantinedInstancesAnnotation]
+	if !ok {
+		return stringset.New(), nil
+	}
+
+	var quarantinedInstancesList []string
+	if err := json.Unmarshal([]byte(quarantinedInstances), &quarantinedInstancesList); err != nil {
+		return nil, ErrorQuarantinedInstancesSyntax
+	}
+
+	return stringset.From(quarantinedInstancesList), nil
+}
+
+// AddQuarantinedInstances adds the given server names to the
+// QuarantinedInstancesAnnotation annotation
+func AddQuarantinedInstances(object *metav1.ObjectMeta, serverNames ...string) error {
+	quarantinedInstances, err := GetQuarantinedInstances(object.Annotations)
+	if err != nil {
+		return err
+	}
+
+	for _, serverName := range serverNames {
+		quarantinedInstances.Put(serverName)
+	}
+	if quarantinedInstances.Len() == 0 {
+		return nil
+	}
+
+	serverList := quarantinedInstances.ToList()
+	sort.Strings(serverList)
+
+	annotationValue, err := json.Marshal(serverList)
+	if err != nil {
+		return err
+	}
+	if object.Annotations == nil {
+		object.Annotations = make(map[string]string)
+	}
+	object.Annotations[QuarantinedInstancesAnnotation] = string(annotationValue)
+
+	return nil
+}
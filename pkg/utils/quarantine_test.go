@@ -0,0 +1,51 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quarantined instances", func() {
+	It("are empty when the annotation is not set", func() {
+		instances, err := GetQuarantinedInstances(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances.Len()).To(BeZero())
+	})
+
+	It("complains about an invalid annotation", func() {
+		_, err := GetQuarantinedInstances(map[string]string{
+			QuarantinedInstancesAnnotation: "cluster-example-2",
+		})
+		Expect(err).To(MatchError(ErrorQuarantinedInstancesSyntax))
+	})
+
+	It("can be added to the annotation", func() {
+		object := metav1.ObjectMeta{}
+		Expect(AddQuarantinedInstances(&object, "cluster-example-3")).To(Succeed())
+		Expect(AddQuarantinedInstances(&object, "cluster-example-2", "cluster-example-3")).To(Succeed())
+		Expect(object.Annotations).To(HaveKeyWithValue(
+			QuarantinedInstancesAnnotation, `["cluster-example-2","cluster-example-3"]`))
+
+		instances, err := GetQuarantinedInstances(object.Annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances.ToSortedList()).To(Equal([]string{"cluster-example-2", "cluster-example-3"}))
+	})
+})